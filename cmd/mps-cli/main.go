@@ -0,0 +1,345 @@
+// Command mps-cli runs the cmp protocols of this library between processes, with the key shares stored on disk.
+//
+// Every party runs the same command, with its own ID and the addresses of the others:
+//
+//	mps-cli keygen  -id a -parties a,b,c -threshold 1 -config a.key -listen :9001 -peers b=host-b:9001,c=host-c:9001
+//	mps-cli refresh -config a.key -listen :9001 -peers b=host-b:9001,c=host-c:9001
+//	mps-cli presign -config a.key -signers a,b -presignature a.presig -listen :9001 -peers b=host-b:9001
+//	mps-cli sign    -config a.key -signers a,b -message <hex hash> -listen :9001 -peers b=host-b:9001
+//
+// sign prints the signature as the hex encoding of the compressed point R followed by S.
+// With -presignature, it consumes a presignature generated by presign instead, and deletes its file.
+//
+// Instead of TCP, messages can be exchanged through a directory shared by all parties, with -transport file -dir <path>,
+// where each party reads the messages addressed to it from the subdirectory named after its ID.
+//
+// A session is identified by -session, which must be the same for all parties, and distinct from the previous ones.
+// Messages aren't authenticated, so the TCP transport should only be used in a trusted network.
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+)
+
+const usage = `usage: mps-cli <command> [flags]
+
+commands:
+  keygen   generate a new key, and store this party's share in -config
+  refresh  refresh the share stored in -config
+  presign  generate a presignature with -signers, and store it in -presignature
+  sign     sign -message with -signers, or with the presignature in -presignature
+
+Run mps-cli <command> -h for the flags of a command.
+`
+
+// group is the curve of the keys, the only one cmp signatures are verified over by this command.
+var group = curve.Secp256k1{}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mps-cli: ")
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	commands := map[string]func(args []string) error{
+		"keygen":  keygen,
+		"refresh": refresh,
+		"presign": presign,
+		"sign":    sign,
+	}
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err := command(os.Args[2:]); err != nil {
+		log.Fatalf("%s: %v", os.Args[1], err)
+	}
+}
+
+// sessionFlags are the flags shared by all commands, describing how to reach the other parties.
+type sessionFlags struct {
+	session   string
+	transport string
+	listen    string
+	peers     string
+	dir       string
+	timeout   time.Duration
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *sessionFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	f := &sessionFlags{}
+	fs.StringVar(&f.session, "session", name, "name of the session, which all parties must agree on")
+	fs.StringVar(&f.transport, "transport", "tcp", "how messages are exchanged: tcp or file")
+	fs.StringVar(&f.listen, "listen", ":9001", "address to accept the connections of the other parties on, with -transport tcp")
+	fs.StringVar(&f.peers, "peers", "", "comma separated list of id=host:port of the other parties, with -transport tcp")
+	fs.StringVar(&f.dir, "dir", "", "directory shared by all parties, with -transport file")
+	fs.DurationVar(&f.timeout, "timeout", 5*time.Minute, "time after which the session is aborted")
+	return fs, f
+}
+
+func keygen(args []string) error {
+	fs, f := newFlagSet("keygen")
+	id := fs.String("id", "", "party ID of this party")
+	parties := fs.String("parties", "", "comma separated list of the IDs of all parties, including this one")
+	threshold := fs.Int("threshold", 1, "maximum number of parties which can be corrupted; threshold+1 parties are needed to sign")
+	configPath := fs.String("config", "", "file to store this party's key share in")
+	_ = fs.Parse(args)
+
+	if *id == "" || *parties == "" || *configPath == "" {
+		return errors.New("-id, -parties and -config are required")
+	}
+	if _, err := os.Stat(*configPath); err == nil {
+		return fmt.Errorf("%s already exists", *configPath)
+	}
+	ids := parseIDs(*parties)
+
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	result, err := f.run(party.ID(*id), ids, cmp.Keygen(group, party.ID(*id), ids, *threshold, pl))
+	if err != nil {
+		return err
+	}
+	config := result.(*cmp.Config)
+	if err = writeConfig(*configPath, config); err != nil {
+		return err
+	}
+	return printPublicKey(config)
+}
+
+func refresh(args []string) error {
+	fs, f := newFlagSet("refresh")
+	configPath := fs.String("config", "", "file storing this party's key share, which is replaced by the refreshed one")
+	_ = fs.Parse(args)
+
+	config, err := readConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	result, err := f.run(config.ID, config.PartyIDs(), cmp.Refresh(config, pl))
+	if err != nil {
+		return err
+	}
+	refreshed := result.(*cmp.Config)
+	if err = writeConfig(*configPath, refreshed); err != nil {
+		return err
+	}
+	return printPublicKey(refreshed)
+}
+
+func presign(args []string) error {
+	fs, f := newFlagSet("presign")
+	configPath := fs.String("config", "", "file storing this party's key share")
+	signers := fs.String("signers", "", "comma separated list of the IDs of the signers, including this party")
+	presignaturePath := fs.String("presignature", "", "file to store the presignature in")
+	_ = fs.Parse(args)
+
+	if *signers == "" || *presignaturePath == "" {
+		return errors.New("-signers and -presignature are required")
+	}
+	config, err := readConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	ids := parseIDs(*signers)
+
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	result, err := f.run(config.ID, ids, cmp.Presign(config, ids, pl))
+	if err != nil {
+		return err
+	}
+	data, err := cbor.Marshal(result.(*ecdsa.PreSignature))
+	if err != nil {
+		return err
+	}
+	return writeFile(*presignaturePath, data)
+}
+
+func sign(args []string) error {
+	fs, f := newFlagSet("sign")
+	configPath := fs.String("config", "", "file storing this party's key share")
+	signers := fs.String("signers", "", "comma separated list of the IDs of the signers, including this party")
+	presignaturePath := fs.String("presignature", "", "file storing a presignature of the signers, to sign with instead of running the full protocol")
+	messageHex := fs.String("message", "", "hex encoded hash of the message to sign")
+	_ = fs.Parse(args)
+
+	message, err := hex.DecodeString(*messageHex)
+	if err != nil || len(message) == 0 {
+		return errors.New("-message must be a hex encoded hash")
+	}
+	config, err := readConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	var result interface{}
+	if *presignaturePath != "" {
+		preSignature, err := readPreSignature(*presignaturePath)
+		if err != nil {
+			return err
+		}
+		// a presignature must never be used twice, since two signatures with the same nonce reveal the key.
+		if err = os.Remove(*presignaturePath); err != nil {
+			return err
+		}
+		result, err = f.run(config.ID, preSignature.SignerIDs(), cmp.PresignOnline(config, preSignature, message, pl))
+		if err != nil {
+			return err
+		}
+	} else {
+		ids := parseIDs(*signers)
+		if len(ids) == 0 {
+			return errors.New("-signers or -presignature is required")
+		}
+		result, err = f.run(config.ID, ids, cmp.Sign(config, ids, message, pl))
+		if err != nil {
+			return err
+		}
+	}
+
+	signature := result.(*ecdsa.Signature)
+	if !signature.Verify(config.PublicPoint(), message) {
+		return errors.New("the signature is invalid")
+	}
+	r, err := signature.R.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	s, err := signature.S.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	fmt.Println(hex.EncodeToString(append(r, s...)))
+	return nil
+}
+
+// run executes create between the parties over the transport described by f, and returns its result.
+func (f *sessionFlags) run(self party.ID, parties []party.ID, create protocol.StartFunc) (interface{}, error) {
+	t, err := f.open(self, parties)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+	h, err := protocol.NewMultiHandlerContext(ctx, create, []byte(f.session))
+	if err != nil {
+		return nil, err
+	}
+	return transport.Run(ctx, h, t)
+}
+
+// open returns the transport to the other parties.
+func (f *sessionFlags) open(self party.ID, parties []party.ID) (transport.Transport, error) {
+	var others []party.ID
+	for _, id := range parties {
+		if id != self {
+			others = append(others, id)
+		}
+	}
+	switch f.transport {
+	case "tcp":
+		return openTCP(self, others, f.listen, f.peers, f.timeout)
+	case "file":
+		if f.dir == "" {
+			return nil, errors.New("-dir is required with -transport file")
+		}
+		return newFileTransport(self, others, f.dir)
+	default:
+		return nil, fmt.Errorf("unknown transport %q", f.transport)
+	}
+}
+
+// parseIDs parses a comma separated list of party IDs.
+func parseIDs(list string) []party.ID {
+	var ids []party.ID
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, party.ID(id))
+		}
+	}
+	return ids
+}
+
+func readConfig(path string) (*cmp.Config, error) {
+	if path == "" {
+		return nil, errors.New("-config is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := cmp.EmptyConfig(group)
+	if err = config.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+func writeConfig(path string, config *cmp.Config) error {
+	data, err := config.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+func readPreSignature(path string) (*ecdsa.PreSignature, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	preSignature := ecdsa.EmptyPreSignature(group)
+	if err = cbor.Unmarshal(data, preSignature); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err = preSignature.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return preSignature, nil
+}
+
+// writeFile replaces the file at path with data, readable only by the current user,
+// without leaving it truncated if the command is interrupted.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func printPublicKey(config *cmp.Config) error {
+	data, err := config.PublicPoint().MarshalBinary()
+	if err != nil {
+		return err
+	}
+	fmt.Println(hex.EncodeToString(data))
+	return nil
+}
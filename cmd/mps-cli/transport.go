@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+)
+
+const (
+	// dialInterval is the delay between two attempts to connect to a party which isn't listening yet.
+	dialInterval = 200 * time.Millisecond
+	// pollInterval is the delay between two reads of the inbox of the file transport.
+	pollInterval = 50 * time.Millisecond
+)
+
+// openTCP listens on listen, and connects to the parties in others, whose addresses are in peers.
+//
+// The parties are usually not started at the same time, so connections are retried until timeout.
+func openTCP(self party.ID, others []party.ID, listen, peers string, timeout time.Duration) (transport.Transport, error) {
+	addresses, err := parsePeers(peers)
+	if err != nil {
+		return nil, err
+	}
+	session := make(map[party.ID]string, len(others))
+	for _, id := range others {
+		addr, ok := addresses[id]
+		if !ok {
+			return nil, fmt.Errorf("no address for party %s in -peers", id)
+		}
+		session[id] = addr
+	}
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, err
+	}
+	return transport.NewTCP(transport.TCPConfig{
+		Self:     self,
+		Listener: listener,
+		Peers:    session,
+		Dial: func(addr string) (net.Conn, error) {
+			deadline := time.Now().Add(timeout)
+			for {
+				conn, err := net.DialTimeout("tcp", addr, dialInterval)
+				if err == nil || time.Now().After(deadline) {
+					return conn, err
+				}
+				time.Sleep(dialInterval)
+			}
+		},
+	})
+}
+
+// parsePeers parses a list of the form "b=host:port,c=host:port".
+func parsePeers(list string) (map[party.ID]string, error) {
+	peers := map[party.ID]string{}
+	if list == "" {
+		return peers, nil
+	}
+	for _, entry := range strings.Split(list, ",") {
+		id, addr, ok := strings.Cut(entry, "=")
+		if !ok || id == "" || addr == "" {
+			return nil, fmt.Errorf("invalid peer %q", entry)
+		}
+		peers[party.ID(id)] = addr
+	}
+	return peers, nil
+}
+
+// fileTransport is a transport.Transport over a directory shared by all parties.
+//
+// A message to party j is written to the file <dir>/<j>/<sender>-<sequence number>.msg,
+// and removed by j once read.
+type fileTransport struct {
+	self   party.ID
+	others []party.ID
+	dir    string
+	inbox  chan *protocol.Message
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mtx sync.Mutex
+	seq uint64
+	// prefix distinguishes the files of this process from those of a previous run with the same ID.
+	prefix string
+}
+
+func newFileTransport(self party.ID, others []party.ID, dir string) (*fileTransport, error) {
+	for _, id := range append([]party.ID{self}, others...) {
+		if err := os.MkdirAll(filepath.Join(dir, string(id)), 0o700); err != nil {
+			return nil, err
+		}
+	}
+	t := &fileTransport{
+		self:   self,
+		others: others,
+		dir:    dir,
+		inbox:  make(chan *protocol.Message),
+		done:   make(chan struct{}),
+		prefix: fmt.Sprintf("%s-%d", self, time.Now().UnixNano()),
+	}
+	t.wg.Add(1)
+	go t.poll()
+	return t, nil
+}
+
+// Send implements transport.Transport.
+func (t *fileTransport) Send(msg *protocol.Message) error {
+	return t.write(msg.To, msg)
+}
+
+// Broadcast implements transport.Transport.
+func (t *fileTransport) Broadcast(msg *protocol.Message) error {
+	for _, id := range t.others {
+		if err := t.write(id, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Receive implements transport.Transport.
+func (t *fileTransport) Receive() <-chan *protocol.Message {
+	return t.inbox
+}
+
+// Close implements transport.Transport.
+func (t *fileTransport) Close() error {
+	select {
+	case <-t.done:
+	default:
+		close(t.done)
+	}
+	t.wg.Wait()
+	return nil
+}
+
+// write stores msg in the inbox of to, under a temporary name until it is complete.
+func (t *fileTransport) write(to party.ID, msg *protocol.Message) error {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	t.mtx.Lock()
+	t.seq++
+	name := fmt.Sprintf("%s-%08d.msg", t.prefix, t.seq)
+	t.mtx.Unlock()
+
+	path := filepath.Join(t.dir, string(to), name)
+	if err = os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// poll delivers the messages written to our inbox, until the transport is closed.
+func (t *fileTransport) poll() {
+	defer t.wg.Done()
+	defer close(t.inbox)
+	inbox := filepath.Join(t.dir, string(t.self))
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		entries, _ := os.ReadDir(inbox)
+		names := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			if strings.HasSuffix(entry.Name(), ".msg") {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			path := filepath.Join(inbox, name)
+			data, err := os.ReadFile(path)
+			_ = os.Remove(path)
+			if err != nil {
+				continue
+			}
+			msg := &protocol.Message{}
+			if err = msg.UnmarshalBinary(data); err != nil {
+				continue
+			}
+			select {
+			case t.inbox <- msg:
+			case <-t.done:
+				return
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-t.done:
+			return
+		}
+	}
+}
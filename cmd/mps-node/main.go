@@ -0,0 +1,119 @@
+// Command mps-node runs the protocols of this library as a daemon, so that signing nodes can be
+// deployed without writing Go.
+//
+// Every node is started with its own party ID, the HTTPS addresses of the other nodes, and its TLS credentials:
+//
+//	mps-node -id a -listen :8001 -peers b=https://10.0.0.2:8001,c=https://10.0.0.3:8001 \
+//		-cert a.pem -key a-key.pem -ca ca.pem -operators ops
+//
+// All connections use mutual TLS, with certificates issued by the given CA. A client is identified by the common
+// name of its certificate: the other nodes by their party ID, which must match the sender of the messages they deliver,
+// and the operators, who may start sessions and read their results, by one of the names given to -operators.
+//
+// The API is made of JSON requests. A session runs once all of its parties started it with the same name.
+//
+//	POST /v1/keygen   {"session": "k1", "protocol": "cmp", "parties": ["a", "b", "c"], "threshold": 1}
+//	POST /v1/sign     {"session": "s1", "key": "k1", "signers": ["a", "b"], "message": "<hex hash>"}
+//	GET  /v1/session?name=k1
+//	POST /v1/deliver  (a binary protocol.Message, sent by the other nodes)
+//
+// The supported protocols are "cmp", producing ECDSA signatures over secp256k1, and "frost-taproot",
+// producing BIP-340 signatures. Key shares are only kept in memory, and the results of
+// other sessions are forgotten once they have been finished for -timeout.
+//
+// The API is HTTP/JSON rather than gRPC. It only has four calls, which operators can make with any HTTP client,
+// and serving gRPC would add the gRPC and protobuf runtimes, together with generated code, to the dependencies
+// of this module, which every user of the library shares.
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+func main() {
+	id := flag.String("id", "", "party ID of this node")
+	listen := flag.String("listen", ":8001", "address to serve the API on")
+	peers := flag.String("peers", "", "comma separated list of id=url for the other nodes")
+	timeout := flag.Duration("timeout", 5*time.Minute, "time after which an idle session is aborted")
+	certFile := flag.String("cert", "", "certificate of this node, whose common name is its party ID")
+	keyFile := flag.String("key", "", "private key of the certificate")
+	caFile := flag.String("ca", "", "CA certificate which issued the certificates of the nodes and operators")
+	operators := flag.String("operators", "", "comma separated list of the common names of the operators' certificates")
+	flag.Parse()
+
+	if *id == "" {
+		log.Fatal("mps-node: -id is required")
+	}
+	addresses, err := parsePeers(*peers)
+	if err != nil {
+		log.Fatalf("mps-node: %v", err)
+	}
+	tlsConfig, err := loadTLS(*certFile, *keyFile, *caFile)
+	if err != nil {
+		log.Fatalf("mps-node: %v", err)
+	}
+	var names []string
+	if *operators != "" {
+		names = strings.Split(*operators, ",")
+	}
+	n := newNode(party.ID(*id), addresses, *timeout, tlsConfig, names)
+	defer n.close()
+
+	server := &http.Server{Addr: *listen, Handler: n, TLSConfig: tlsConfig}
+	log.Printf("mps-node: %s listening on %s", *id, *listen)
+	log.Fatal(server.ListenAndServeTLS("", ""))
+}
+
+// loadTLS returns a configuration for mutual TLS, presenting the given certificate and
+// requiring the certificates of the other side to be issued by the CA.
+func loadTLS(certFile, keyFile, caFile string) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" || caFile == "" {
+		return nil, errors.New("-cert, -key and -ca are required")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	caData, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	ca := x509.NewCertPool()
+	if !ca.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("no certificate found in %s", caFile)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      ca,
+		ClientCAs:    ca,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// parsePeers parses a list of the form "b=https://host:port,c=https://host:port".
+func parsePeers(list string) (map[party.ID]string, error) {
+	peers := map[party.ID]string{}
+	if list == "" {
+		return peers, nil
+	}
+	for _, entry := range strings.Split(list, ",") {
+		id, url, ok := strings.Cut(entry, "=")
+		if !ok || id == "" || url == "" {
+			return nil, fmt.Errorf("invalid peer %q", entry)
+		}
+		peers[party.ID(id)] = strings.TrimSuffix(url, "/")
+	}
+	return peers, nil
+}
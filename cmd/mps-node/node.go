@@ -0,0 +1,412 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+const (
+	protocolCMP          = "cmp"
+	protocolFROSTTaproot = "frost-taproot"
+
+	// retryInterval is the delay before delivering a message again to a node which hasn't started the session yet.
+	retryInterval = 200 * time.Millisecond
+)
+
+// node serves the API of a single party.
+type node struct {
+	id      party.ID
+	peers   map[party.ID]string
+	timeout time.Duration
+	client  *http.Client
+	mux     *protocol.Multiplexer
+	pl      *pool.Pool
+	api     *http.ServeMux
+	// operators are the names of the client certificates allowed to start sessions and read their results.
+	operators map[string]bool
+
+	mtx      sync.Mutex
+	sessions map[string]*session
+	// finished contains the time at which the sessions which are no longer running stopped, by SSID.
+	finished map[string]time.Time
+}
+
+// session is the state of a keygen or signature started through the API.
+type session struct {
+	protocol string
+	done     bool
+	// doneAt is the time at which the session finished.
+	doneAt time.Time
+	result interface{}
+	err    error
+}
+
+// newNode returns a node using tlsConfig both to serve its API and to connect to its peers.
+//
+// Clients are identified by the common name of their certificate, which tlsConfig must verify:
+// peers by their party ID, and operators by one of the given names.
+func newNode(id party.ID, peers map[party.ID]string, timeout time.Duration, tlsConfig *tls.Config, operators []string) *node {
+	n := &node{
+		id:      id,
+		peers:   peers,
+		timeout: timeout,
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		mux:       protocol.NewMultiplexer(timeout),
+		pl:        pool.NewPool(0),
+		api:       http.NewServeMux(),
+		operators: map[string]bool{},
+		sessions:  map[string]*session{},
+		finished:  map[string]time.Time{},
+	}
+	for _, name := range operators {
+		n.operators[name] = true
+	}
+	n.api.HandleFunc("/v1/keygen", n.operator(n.handleKeygen))
+	n.api.HandleFunc("/v1/sign", n.operator(n.handleSign))
+	n.api.HandleFunc("/v1/session", n.operator(n.handleSession))
+	n.api.HandleFunc("/v1/deliver", n.handleDeliver)
+	return n
+}
+
+// caller returns the common name of the verified client certificate of r.
+func caller(r *http.Request) (string, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return r.TLS.VerifiedChains[0][0].Subject.CommonName, true
+}
+
+// operator only lets the operators of this node call handler.
+func (n *node) operator(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if name, ok := caller(r); !ok || !n.operators[name] {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (n *node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.api.ServeHTTP(w, r)
+}
+
+func (n *node) close() {
+	n.mux.Stop()
+	n.pl.TearDown()
+}
+
+type keygenRequest struct {
+	Session   string     `json:"session"`
+	Protocol  string     `json:"protocol"`
+	Parties   []party.ID `json:"parties"`
+	Threshold int        `json:"threshold"`
+}
+
+type signRequest struct {
+	Session string     `json:"session"`
+	Key     string     `json:"key"`
+	Signers []party.ID `json:"signers"`
+	Message string     `json:"message"`
+}
+
+type sessionResponse struct {
+	Session   string `json:"session"`
+	Protocol  string `json:"protocol"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+func (n *node) handleKeygen(w http.ResponseWriter, r *http.Request) {
+	var req keygenRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	var create protocol.StartFunc
+	switch req.Protocol {
+	case protocolCMP:
+		create = cmp.Keygen(curve.Secp256k1{}, n.id, req.Parties, req.Threshold, n.pl)
+	case protocolFROSTTaproot:
+		create = frost.KeygenTaproot(n.id, req.Parties, req.Threshold)
+	default:
+		http.Error(w, fmt.Sprintf("unknown protocol %q", req.Protocol), http.StatusBadRequest)
+		return
+	}
+	n.start(w, req.Session, req.Protocol, create)
+}
+
+func (n *node) handleSign(w http.ResponseWriter, r *http.Request) {
+	var req signRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	message, err := hex.DecodeString(req.Message)
+	if err != nil {
+		http.Error(w, "message must be hex encoded", http.StatusBadRequest)
+		return
+	}
+	n.mtx.Lock()
+	key, ok := n.sessions[req.Key]
+	n.mtx.Unlock()
+	if !ok || !key.done || key.err != nil {
+		http.Error(w, fmt.Sprintf("no key was generated by session %q", req.Key), http.StatusNotFound)
+		return
+	}
+	var create protocol.StartFunc
+	switch config := key.result.(type) {
+	case *cmp.Config:
+		create = cmp.Sign(config, req.Signers, message, n.pl)
+	case *frost.TaprootConfig:
+		create = frost.SignTaproot(config, req.Signers, message)
+	default:
+		http.Error(w, fmt.Sprintf("session %q did not generate a key", req.Key), http.StatusBadRequest)
+		return
+	}
+	n.start(w, req.Session, key.protocol, create)
+}
+
+func (n *node) handleSession(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	n.mtx.Lock()
+	s, ok := n.sessions[name]
+	var resp sessionResponse
+	if ok {
+		resp = s.response(name)
+	}
+	n.mtx.Unlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown session %q", name), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// handleDeliver accepts a message from another node.
+//
+// The message must come from the party named by the client certificate of the sender.
+// It responds with 404 while the session hasn't been started on this node, so that the sender tries again,
+// and with 410 once the session is over.
+func (n *node) handleDeliver(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "expected POST", http.StatusMethodNotAllowed)
+		return
+	}
+	name, ok := caller(r)
+	if _, peer := n.peers[party.ID(name)]; !ok || !peer {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, 1<<26))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	msg := &protocol.Message{}
+	if err = msg.UnmarshalBinary(data); err != nil || !msg.IsFor(n.id) {
+		http.Error(w, "invalid message", http.StatusBadRequest)
+		return
+	}
+	if msg.From != party.ID(name) {
+		http.Error(w, "message is not from the sender", http.StatusForbidden)
+		return
+	}
+	n.mtx.Lock()
+	n.evict(time.Now())
+	_, finished := n.finished[string(msg.SSID)]
+	n.mtx.Unlock()
+	if finished {
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+	for _, ssid := range n.mux.Namespace("").Sessions() {
+		if bytes.Equal(ssid, msg.SSID) {
+			n.mux.Accept(msg)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// start runs a new session called name, and responds with its state.
+func (n *node) start(w http.ResponseWriter, name, protocolName string, create protocol.StartFunc) {
+	if name == "" {
+		http.Error(w, "missing session name", http.StatusBadRequest)
+		return
+	}
+	n.mtx.Lock()
+	n.evict(time.Now())
+	if _, ok := n.sessions[name]; ok {
+		n.mtx.Unlock()
+		http.Error(w, fmt.Sprintf("session %q already exists", name), http.StatusConflict)
+		return
+	}
+	s := &session{protocol: protocolName}
+	n.sessions[name] = s
+	n.mtx.Unlock()
+
+	h, err := n.mux.Start(create, []byte(name))
+	if err != nil {
+		n.mtx.Lock()
+		delete(n.sessions, name)
+		n.mtx.Unlock()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	go n.run(name, s, h)
+
+	n.mtx.Lock()
+	resp := s.response(name)
+	n.mtx.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// run sends the messages of h to the other nodes, and records its result.
+func (n *node) run(name string, s *session, h protocol.Handler) {
+	var ssid []byte
+	var wg sync.WaitGroup
+	for msg := range h.Listen() {
+		ssid = msg.SSID
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			log.Printf("mps-node: session %s: %v", name, err)
+			continue
+		}
+		for id, url := range n.peers {
+			if msg.IsFor(id) {
+				wg.Add(1)
+				go func(id party.ID, url string) {
+					defer wg.Done()
+					if err := n.deliver(url, data); err != nil {
+						log.Printf("mps-node: session %s: delivering to %s: %v", name, id, err)
+					}
+				}(id, url)
+			}
+		}
+	}
+	result, err := h.Result()
+
+	n.mtx.Lock()
+	s.done, s.doneAt, s.result, s.err = true, time.Now(), result, err
+	if ssid != nil {
+		n.finished[string(ssid)] = s.doneAt
+	}
+	n.mtx.Unlock()
+	wg.Wait()
+}
+
+// evict forgets the sessions which finished more than a timeout before now, except for the keys
+// generated successfully, which later signatures use.
+//
+// Retransmissions from the other nodes stop after a timeout, so the SSIDs of finished sessions are dropped as well.
+// It must be called with the node's lock held.
+func (n *node) evict(now time.Time) {
+	cutoff := now.Add(-n.timeout)
+	for name, s := range n.sessions {
+		if !s.done || s.doneAt.After(cutoff) {
+			continue
+		}
+		switch s.result.(type) {
+		case *cmp.Config, *frost.TaprootConfig:
+			if s.err == nil {
+				continue
+			}
+		}
+		delete(n.sessions, name)
+	}
+	for ssid, doneAt := range n.finished {
+		if !doneAt.After(cutoff) {
+			delete(n.finished, ssid)
+		}
+	}
+}
+
+// deliver posts data to the node at url, until it accepts it or the session times out.
+func (n *node) deliver(url string, data []byte) error {
+	deadline := time.Now().Add(n.timeout)
+	for {
+		resp, err := n.client.Post(url+"/v1/deliver", "application/octet-stream", bytes.NewReader(data))
+		if err == nil {
+			_ = resp.Body.Close()
+			switch resp.StatusCode {
+			case http.StatusAccepted, http.StatusGone:
+				return nil
+			case http.StatusNotFound:
+			default:
+				return fmt.Errorf("rejected with %s", resp.Status)
+			}
+		}
+		if time.Now().After(deadline) {
+			return errors.New("timed out")
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+// response must be called with the node's lock held.
+func (s *session) response(name string) sessionResponse {
+	resp := sessionResponse{Session: name, Protocol: s.protocol, Status: "running"}
+	if !s.done {
+		return resp
+	}
+	if s.err != nil {
+		resp.Status, resp.Error = "failed", s.err.Error()
+		return resp
+	}
+	resp.Status = "done"
+	switch result := s.result.(type) {
+	case *cmp.Config:
+		if data, err := result.PublicPoint().MarshalBinary(); err == nil {
+			resp.PublicKey = hex.EncodeToString(data)
+		}
+	case *frost.TaprootConfig:
+		resp.PublicKey = hex.EncodeToString(result.PublicKey)
+	case *ecdsa.Signature:
+		r, errR := result.R.MarshalBinary()
+		sig, errS := result.S.MarshalBinary()
+		if errR == nil && errS == nil {
+			resp.Signature = hex.EncodeToString(append(r, sig...))
+		}
+	case taproot.Signature:
+		resp.Signature = hex.EncodeToString(result)
+	}
+	return resp
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "expected POST", http.StatusMethodNotAllowed)
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
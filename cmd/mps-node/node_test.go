@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// testCA issues certificates for the nodes and operators of a test.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+// config returns a mutual TLS configuration presenting a certificate for name.
+func (ca *testCA) config(t *testing.T, name string) *tls.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		RootCAs:      ca.pool,
+		ClientCAs:    ca.pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+}
+
+func (ca *testCA) client(t *testing.T, name string) *http.Client {
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: ca.config(t, name)}}
+}
+
+// startNodes serves a node for each of ids, and returns their URLs.
+func startNodes(t *testing.T, ca *testCA, ids party.IDSlice) map[party.ID]string {
+	servers := map[party.ID]*httptest.Server{}
+	urls := map[party.ID]string{}
+	for _, id := range ids {
+		server := httptest.NewUnstartedServer(nil)
+		servers[id] = server
+		urls[id] = "https://" + server.Listener.Addr().String()
+	}
+	for _, id := range ids {
+		peers := map[party.ID]string{}
+		for _, other := range ids {
+			if other != id {
+				peers[other] = urls[other]
+			}
+		}
+		tlsConfig := ca.config(t, string(id))
+		n := newNode(id, peers, 5*time.Minute, tlsConfig, []string{"operator"})
+		server := servers[id]
+		server.Config.Handler = n
+		server.TLS = tlsConfig
+		server.StartTLS()
+		t.Cleanup(func() {
+			server.Close()
+			n.close()
+		})
+	}
+	return urls
+}
+
+func post(t *testing.T, client *http.Client, url string, request interface{}) *http.Response {
+	data, err := json.Marshal(request)
+	require.NoError(t, err)
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+// wait polls the node at url until the session called name is over.
+func wait(t *testing.T, client *http.Client, url, name string) sessionResponse {
+	for {
+		resp, err := client.Get(url + "/v1/session?name=" + name)
+		require.NoError(t, err)
+		var state sessionResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&state))
+		require.NoError(t, resp.Body.Close())
+		if state.Status != "running" {
+			return state
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestKeygenSign(t *testing.T) {
+	for _, protocolName := range []string{protocolFROSTTaproot, protocolCMP} {
+		protocolName := protocolName
+		t.Run(protocolName, func(t *testing.T) {
+			ca := newTestCA(t)
+			ids := party.IDSlice{"a", "b"}
+			urls := startNodes(t, ca, ids)
+			operator := ca.client(t, "operator")
+
+			for _, id := range ids {
+				resp := post(t, operator, urls[id]+"/v1/keygen", keygenRequest{
+					Session: "key", Protocol: protocolName, Parties: ids, Threshold: 1,
+				})
+				require.Equal(t, http.StatusAccepted, resp.StatusCode)
+			}
+			keys := map[string]bool{}
+			for _, id := range ids {
+				state := wait(t, operator, urls[id], "key")
+				require.Equal(t, "done", state.Status, state.Error)
+				keys[state.PublicKey] = true
+			}
+			require.Len(t, keys, 1, "all nodes should have the same public key")
+
+			hash := sha256.Sum256([]byte("message"))
+			for _, id := range ids {
+				resp := post(t, operator, urls[id]+"/v1/sign", signRequest{
+					Session: "sig", Key: "key", Signers: ids, Message: hex.EncodeToString(hash[:]),
+				})
+				require.Equal(t, http.StatusAccepted, resp.StatusCode)
+			}
+			for _, id := range ids {
+				state := wait(t, operator, urls[id], "sig")
+				require.Equal(t, "done", state.Status, state.Error)
+				require.NotEmpty(t, state.Signature)
+			}
+		})
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	ca := newTestCA(t)
+	ids := party.IDSlice{"a", "b"}
+	urls := startNodes(t, ca, ids)
+
+	// the other nodes can't use the operator API.
+	resp := post(t, ca.client(t, "b"), urls["a"]+"/v1/keygen", keygenRequest{
+		Session: "key", Protocol: protocolFROSTTaproot, Parties: ids, Threshold: 1,
+	})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// clients without a certificate from the CA are rejected during the handshake.
+	_, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.pool}}}).Get(urls["a"] + "/v1/session?name=key")
+	require.Error(t, err)
+
+	deliver := func(client *http.Client, from party.ID) int {
+		msg := &protocol.Message{SSID: []byte("ssid"), From: from, To: "a", Protocol: "test", RoundNumber: 2}
+		data, err := msg.MarshalBinary()
+		require.NoError(t, err)
+		resp, err := client.Post(urls["a"]+"/v1/deliver", "application/octet-stream", bytes.NewReader(data))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+	// a node can only deliver its own messages, and operators can't deliver any.
+	require.Equal(t, http.StatusNotFound, deliver(ca.client(t, "b"), "b"))
+	require.Equal(t, http.StatusForbidden, deliver(ca.client(t, "b"), "c"))
+	require.Equal(t, http.StatusForbidden, deliver(ca.client(t, "operator"), "b"))
+}
+
+func TestEvict(t *testing.T) {
+	n := newNode("a", nil, time.Minute, nil, nil)
+	defer n.close()
+	now := time.Now()
+	n.sessions["old"] = &session{done: true, doneAt: now.Add(-2 * time.Minute)}
+	n.sessions["recent"] = &session{done: true, doneAt: now}
+	n.sessions["running"] = &session{}
+	n.sessions["key"] = &session{done: true, doneAt: now.Add(-2 * time.Minute), result: &frost.TaprootConfig{}}
+	n.finished["old"] = now.Add(-2 * time.Minute)
+	n.finished["recent"] = now
+
+	n.evict(now)
+	require.NotContains(t, n.sessions, "old")
+	require.Contains(t, n.sessions, "recent")
+	require.Contains(t, n.sessions, "running")
+	require.Contains(t, n.sessions, "key", "generated keys should never be evicted")
+	require.NotContains(t, n.finished, "old")
+	require.Contains(t, n.finished, "recent")
+}
@@ -0,0 +1,129 @@
+// Command mps-testvectors prints test vectors of the key generation and signing protocols of this library,
+// so that other implementations can check that they interoperate with it.
+//
+//	mps-testvectors -protocol frost-taproot -n 3 -t 1 -seed 00 -message <hex>
+//
+// The output is a JSON array with the vector of the key generation, followed by the vector of
+// the signature of the message by the first t+1 parties, whose inputs are the outputs of the key generation.
+// See package pkg/testvector for the format of the vectors.
+//
+// The supported protocols are "cmp", "frost" and "frost-taproot", all over secp256k1.
+// Generating cmp vectors takes a while, since the safe primes of the Paillier keys are searched for sequentially.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/testvector"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func main() {
+	name := flag.String("protocol", "frost-taproot", "protocol to generate vectors for: cmp, frost or frost-taproot")
+	n := flag.Int("n", 3, "number of parties")
+	threshold := flag.Int("t", 1, "threshold of the key")
+	seedHex := flag.String("seed", "00", "hex encoded seed of the random stream")
+	messageHex := flag.String("message", "", "hex encoded message to sign, by default the SHA-256 hash of \"test vector\"")
+	flag.Parse()
+
+	seed, err := hex.DecodeString(*seedHex)
+	if err != nil {
+		log.Fatalf("mps-testvectors: invalid seed: %v", err)
+	}
+	message := sha256.Sum256([]byte("test vector"))
+	messageHash := message[:]
+	if *messageHex != "" {
+		if messageHash, err = hex.DecodeString(*messageHex); err != nil {
+			log.Fatalf("mps-testvectors: invalid message: %v", err)
+		}
+	}
+	if *n < 1 || *threshold < 0 || *threshold >= *n {
+		log.Fatal("mps-testvectors: the threshold must be between 0 and n-1")
+	}
+
+	vectors, err := generate(*name, seed, partyIDs(*n), *threshold, messageHash)
+	if err != nil {
+		log.Fatalf("mps-testvectors: %v", err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(vectors); err != nil {
+		log.Fatalf("mps-testvectors: %v", err)
+	}
+}
+
+// partyIDs returns the IDs "a", "b", ..., of n parties.
+func partyIDs(n int) party.IDSlice {
+	ids := make(party.IDSlice, n)
+	for i := range ids {
+		ids[i] = party.ID(fmt.Sprintf("%c", 'a'+i%26))
+		if i >= 26 {
+			ids[i] += party.ID(fmt.Sprint(i / 26))
+		}
+	}
+	return party.NewIDSlice(ids)
+}
+
+// generate returns the keygen and sign vectors of a protocol.
+func generate(name string, seed []byte, ids party.IDSlice, threshold int, message []byte) ([]*testvector.Vector, error) {
+	var (
+		keygen func(id party.ID) protocol.StartFunc
+		sign   func(result interface{}, signers party.IDSlice) protocol.StartFunc
+	)
+	switch name {
+	case "cmp":
+		keygen = func(id party.ID) protocol.StartFunc {
+			return cmp.Keygen(curve.Secp256k1{}, id, ids, threshold, nil)
+		}
+		sign = func(result interface{}, signers party.IDSlice) protocol.StartFunc {
+			return cmp.Sign(result.(*cmp.Config), signers, message, nil)
+		}
+	case "frost":
+		keygen = func(id party.ID) protocol.StartFunc {
+			return frost.Keygen(curve.Secp256k1{}, id, ids, threshold)
+		}
+		sign = func(result interface{}, signers party.IDSlice) protocol.StartFunc {
+			return frost.Sign(result.(*frost.Config), signers, message)
+		}
+	case "frost-taproot":
+		keygen = func(id party.ID) protocol.StartFunc {
+			return frost.KeygenTaproot(id, ids, threshold)
+		}
+		sign = func(result interface{}, signers party.IDSlice) protocol.StartFunc {
+			return frost.SignTaproot(result.(*frost.TaprootConfig), signers, message)
+		}
+	default:
+		return nil, fmt.Errorf("unknown protocol %q", name)
+	}
+
+	keygenVector, err := testvector.Generate(name+"-keygen", seed, nil, ids, keygen)
+	if err != nil {
+		return nil, err
+	}
+	keygenVector.Threshold = threshold
+
+	signers := ids[:threshold+1]
+	signVector, err := testvector.Generate(name+"-sign", seed, nil, signers, func(id party.ID) protocol.StartFunc {
+		return sign(keygenVector.Results[id], signers)
+	})
+	if err != nil {
+		return nil, err
+	}
+	signVector.Threshold = threshold
+	signVector.Message = hex.EncodeToString(message)
+	signVector.Inputs = make(map[party.ID]string, len(signers))
+	for _, id := range signers {
+		signVector.Inputs[id] = keygenVector.Outputs[id]
+	}
+	return []*testvector.Vector{keygenVector, signVector}, nil
+}
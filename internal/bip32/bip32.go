@@ -2,9 +2,13 @@ package bip32
 
 import (
 	"crypto/hmac"
+	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/binary"
 	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
 
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 )
@@ -40,3 +44,84 @@ func DeriveScalar(public *curve.Secp256k1Point, chaining []byte, i uint32) (*cur
 
 	return scalar, out[32:], nil
 }
+
+// versionPublic is the version prefix of an extended public key on the Bitcoin mainnet, encoded as "xpub".
+var versionPublic = []byte{0x04, 0x88, 0xb2, 0x1e}
+
+// SerializePublic encodes a public point and chaining value as an extended public key, in Base58Check.
+//
+// The key is serialized as a master key, with a depth, parent fingerprint and child number of 0,
+// so that wallets deriving the child i from it obtain the same key as DeriveScalar with i.
+func SerializePublic(public *curve.Secp256k1Point, chaining []byte) (string, error) {
+	if len(chaining) != 32 {
+		return "", fmt.Errorf("chaining value must be 32 bytes, found %d", len(chaining))
+	}
+	compressed, err := public.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	data := make([]byte, 0, 82)
+	data = append(data, versionPublic...)
+	// depth, parent fingerprint and child number
+	data = append(data, make([]byte, 1+4+4)...)
+	data = append(data, chaining...)
+	data = append(data, compressed...)
+	first := sha256.Sum256(data)
+	checksum := sha256.Sum256(first[:])
+	return base58(append(data, checksum[:4]...)), nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58 encodes data with the alphabet of Bitcoin, where each leading zero byte becomes a '1'.
+func base58(data []byte) string {
+	x := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var encoded []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+// Hardened is added to the index of hardened children.
+const Hardened uint32 = 1 << 31
+
+// ParsePath parses a derivation path in the notation of BIP-32, such as "m/44'/60'/0'/0/3", into its indices.
+//
+// Hardened indices are marked with ' or h, and Hardened is added to them.
+// The path "m" contains no index.
+func ParsePath(path string) ([]uint32, error) {
+	components := strings.Split(path, "/")
+	if components[0] != "m" {
+		return nil, fmt.Errorf("path %q must start with m", path)
+	}
+	indices := make([]uint32, 0, len(components)-1)
+	for _, component := range components[1:] {
+		hardened := strings.HasSuffix(component, "'") || strings.HasSuffix(component, "h") || strings.HasSuffix(component, "H")
+		if hardened {
+			component = component[:len(component)-1]
+		}
+		// ParseUint accepts neither signs nor spaces
+		i, err := strconv.ParseUint(component, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: invalid index %q", path, component)
+		}
+		if hardened {
+			i += uint64(Hardened)
+		}
+		indices = append(indices, uint32(i))
+	}
+	return indices, nil
+}
@@ -1,9 +1,9 @@
 package elgamal
 
 import (
-	"crypto/rand"
 	"io"
 
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 )
@@ -23,7 +23,7 @@ type Ciphertext struct {
 // Encrypt returns the encryption of `message` as (L=nonce⋅G, M=message⋅G + nonce⋅public), as well as the `nonce`.
 func Encrypt(public PublicKey, message curve.Scalar) (*Ciphertext, Nonce) {
 	group := public.Curve()
-	nonce := sample.Scalar(rand.Reader, group)
+	nonce := sample.Scalar(random.Reader(), group)
 	L := nonce.ActOnBase()
 	M := message.ActOnBase().Add(nonce.Act(public))
 	return &Ciphertext{
@@ -0,0 +1,117 @@
+package mta
+
+import (
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	zkaffg "github.com/taurusgroup/multi-party-sig/pkg/zk/affg"
+	zkaffp "github.com/taurusgroup/multi-party-sig/pkg/zk/affp"
+)
+
+// Receiver contains the public data of a party receiving an MtA.
+type Receiver struct {
+	// EncryptedShare = Encⱼ(bⱼ)
+	EncryptedShare *paillier.Ciphertext
+	// Paillier is the receiver's public key, under which D is encrypted.
+	Paillier *paillier.PublicKey
+	// Pedersen are the receiver's parameters, used for the proof.
+	Pedersen *pedersen.Parameters
+}
+
+// AffG is the output of ProveAffG for a single receiver.
+type AffG struct {
+	Beta  *saferith.Int
+	D, F  *paillier.Ciphertext
+	Proof *zkaffg.Proof
+	// CompactProof is set instead of Proof by ProveAffGCompactBatch.
+	CompactProof *zkaffg.CompactProof
+}
+
+// AffP is the output of ProveAffP for a single receiver.
+type AffP struct {
+	Beta  *saferith.Int
+	D, F  *paillier.Ciphertext
+	Proof *zkaffp.Proof
+}
+
+// ProveAffGBatch runs ProveAffG with the same secret share, against all receivers of a round at once.
+//
+// The ciphertexts D of each receiver are computed modulo a different Nⱼ², so no reductions can be shared between them.
+// Instead, receivers are processed concurrently using pl, and each encryption avoids exponentiating (1+N).
+// newHash must return a fresh hash function initialized with the sender's ID on every call.
+func ProveAffGBatch(group curve.Curve, newHash func() *hash.Hash,
+	senderSecretShare *saferith.Int, senderSecretSharePoint curve.Point,
+	sender *paillier.SecretKey, receivers map[party.ID]Receiver, pl *pool.Pool) map[party.ID]AffG {
+	return proveAffGBatch(group, newHash, senderSecretShare, senderSecretSharePoint, sender, receivers, pl, false)
+}
+
+// ProveAffGCompactBatch is the same as ProveAffGBatch, but sets the CompactProof of each output.
+func ProveAffGCompactBatch(group curve.Curve, newHash func() *hash.Hash,
+	senderSecretShare *saferith.Int, senderSecretSharePoint curve.Point,
+	sender *paillier.SecretKey, receivers map[party.ID]Receiver, pl *pool.Pool) map[party.ID]AffG {
+	return proveAffGBatch(group, newHash, senderSecretShare, senderSecretSharePoint, sender, receivers, pl, true)
+}
+
+func proveAffGBatch(group curve.Curve, newHash func() *hash.Hash,
+	senderSecretShare *saferith.Int, senderSecretSharePoint curve.Point,
+	sender *paillier.SecretKey, receivers map[party.ID]Receiver, pl *pool.Pool, compact bool) map[party.ID]AffG {
+	ids, inputs := sortReceivers(receivers)
+	results := pl.Parallelize(len(ids), func(i int) interface{} {
+		in := inputs[i]
+		var out AffG
+		if compact {
+			out.Beta, out.D, out.F, out.CompactProof = ProveAffGCompact(group, newHash(),
+				senderSecretShare, senderSecretSharePoint, in.EncryptedShare,
+				sender, in.Paillier, in.Pedersen)
+		} else {
+			out.Beta, out.D, out.F, out.Proof = ProveAffG(group, newHash(),
+				senderSecretShare, senderSecretSharePoint, in.EncryptedShare,
+				sender, in.Paillier, in.Pedersen)
+		}
+		return out
+	})
+	outputs := make(map[party.ID]AffG, len(ids))
+	for i, id := range ids {
+		outputs[id] = results[i].(AffG)
+	}
+	return outputs
+}
+
+// ProveAffPBatch runs ProveAffP with the same encrypted secret share, against all receivers of a round at once,
+// in the same way as ProveAffGBatch.
+func ProveAffPBatch(group curve.Curve, newHash func() *hash.Hash,
+	senderSecretShare *saferith.Int, senderEncryptedShare *paillier.Ciphertext, senderEncryptedShareNonce *saferith.Nat,
+	sender *paillier.SecretKey, receivers map[party.ID]Receiver, pl *pool.Pool) map[party.ID]AffP {
+	ids, inputs := sortReceivers(receivers)
+	results := pl.Parallelize(len(ids), func(i int) interface{} {
+		in := inputs[i]
+		var out AffP
+		out.Beta, out.D, out.F, out.Proof = ProveAffP(group, newHash(),
+			senderSecretShare, senderEncryptedShare, senderEncryptedShareNonce, in.EncryptedShare,
+			sender, in.Paillier, in.Pedersen)
+		return out
+	})
+	outputs := make(map[party.ID]AffP, len(ids))
+	for i, id := range ids {
+		outputs[id] = results[i].(AffP)
+	}
+	return outputs
+}
+
+// sortReceivers returns the receivers in a fixed order, so that they can be indexed by the pool.
+func sortReceivers(receivers map[party.ID]Receiver) (party.IDSlice, []Receiver) {
+	ids := make([]party.ID, 0, len(receivers))
+	for id := range receivers {
+		ids = append(ids, id)
+	}
+	sorted := party.NewIDSlice(ids)
+	inputs := make([]Receiver, len(sorted))
+	for i, id := range sorted {
+		inputs[i] = receivers[id]
+	}
+	return sorted, inputs
+}
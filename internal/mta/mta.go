@@ -1,9 +1,8 @@
 package mta
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -26,8 +25,25 @@ import (
 func ProveAffG(group curve.Curve, h *hash.Hash,
 	senderSecretShare *saferith.Int, senderSecretSharePoint curve.Point, receiverEncryptedShare *paillier.Ciphertext,
 	sender *paillier.SecretKey, receiver *paillier.PublicKey, verifier *pedersen.Parameters) (Beta *saferith.Int, D, F *paillier.Ciphertext, Proof *zkaffg.Proof) {
+	public, private := affG(senderSecretShare, senderSecretSharePoint, receiverEncryptedShare, sender, receiver, verifier)
+	Proof = zkaffg.NewProof(group, h, public, private)
+	return private.Y.Clone().Neg(1), public.Dv, public.Fp, Proof
+}
+
+// ProveAffGCompact is the same as ProveAffG, but returns a zkaffg.CompactProof.
+func ProveAffGCompact(group curve.Curve, h *hash.Hash,
+	senderSecretShare *saferith.Int, senderSecretSharePoint curve.Point, receiverEncryptedShare *paillier.Ciphertext,
+	sender *paillier.SecretKey, receiver *paillier.PublicKey, verifier *pedersen.Parameters) (Beta *saferith.Int, D, F *paillier.Ciphertext, Proof *zkaffg.CompactProof) {
+	public, private := affG(senderSecretShare, senderSecretSharePoint, receiverEncryptedShare, sender, receiver, verifier)
+	Proof = zkaffg.NewCompactProof(group, h, public, private)
+	return private.Y.Clone().Neg(1), public.Dv, public.Fp, Proof
+}
+
+// affG runs the MtA for ProveAffG, and returns the statement and witness of its proof, where private.Y = -β.
+func affG(senderSecretShare *saferith.Int, senderSecretSharePoint curve.Point, receiverEncryptedShare *paillier.Ciphertext,
+	sender *paillier.SecretKey, receiver *paillier.PublicKey, verifier *pedersen.Parameters) (zkaffg.Public, zkaffg.Private) {
 	D, F, S, R, BetaNeg := newMta(senderSecretShare, receiverEncryptedShare, sender, receiver)
-	Proof = zkaffg.NewProof(group, h, zkaffg.Public{
+	return zkaffg.Public{
 		Kv:       receiverEncryptedShare,
 		Dv:       D,
 		Fp:       F,
@@ -40,9 +56,7 @@ func ProveAffG(group curve.Curve, h *hash.Hash,
 		Y: BetaNeg,
 		S: S,
 		R: R,
-	})
-	Beta = BetaNeg.Neg(1)
-	return
+	}
 }
 
 // ProveAffP generates a proof for the a specified verifier.
@@ -83,7 +97,7 @@ func ProveAffP(group curve.Curve, h *hash.Hash,
 
 func newMta(senderSecretShare *saferith.Int, receiverEncryptedShare *paillier.Ciphertext,
 	sender *paillier.SecretKey, receiver *paillier.PublicKey) (D, F *paillier.Ciphertext, S, R *saferith.Nat, BetaNeg *saferith.Int) {
-	BetaNeg = sample.IntervalLPrime(rand.Reader)
+	BetaNeg = sample.IntervalLPrime(random.Reader())
 
 	F, R = sender.Enc(BetaNeg) // F = encᵢ(-β, r)
 
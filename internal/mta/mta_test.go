@@ -11,6 +11,8 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/zk"
 	zkaffg "github.com/taurusgroup/multi-party-sig/pkg/zk/affg"
 	zkaffp "github.com/taurusgroup/multi-party-sig/pkg/zk/affp"
@@ -107,3 +109,48 @@ func Test_newMtA(t *testing.T) {
 	}
 
 }
+
+func TestProveAffGBatch(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	source := mrand.New(mrand.NewSource(2))
+	sender, receiver := zk.ProverPaillierSecret, zk.VerifierPaillierSecret
+	aScalar := sample.Scalar(source, group)
+	a, A := curve.MakeInt(aScalar), aScalar.ActOnBase()
+
+	receivers := make(map[party.ID]Receiver)
+	shares := make(map[party.ID]curve.Scalar)
+	for _, id := range []party.ID{"b", "c", "d"} {
+		b := sample.Scalar(source, group)
+		B, _ := receiver.Enc(curve.MakeInt(b))
+		shares[id] = b
+		receivers[id] = Receiver{
+			EncryptedShare: B,
+			Paillier:       receiver.PublicKey,
+			Pedersen:       zk.Pedersen,
+		}
+	}
+
+	outputs := ProveAffGBatch(group, func() *hash.Hash { return hash.New() }, a, A, sender, receivers, pl)
+	require.Len(t, outputs, len(receivers))
+	for id, out := range outputs {
+		assert.True(t, out.Proof.Verify(hash.New(), zkaffg.Public{
+			Kv:       receivers[id].EncryptedShare,
+			Dv:       out.D,
+			Fp:       out.F,
+			Xp:       A,
+			Prover:   sender.PublicKey,
+			Verifier: receiver.PublicKey,
+			Aux:      zk.Pedersen,
+		}), "proof for %s should verify", id)
+
+		// α + β = a•b
+		alpha, err := receiver.Dec(out.D)
+		require.NoError(t, err)
+		sum := group.NewScalar().SetNat(alpha.Add(alpha, out.Beta, -1).Mod(group.Order()))
+		expected := group.NewScalar().Set(aScalar).Mul(shares[id])
+		assert.True(t, expected.Equal(sum), "α + β should be equal to a•b for %s", id)
+	}
+}
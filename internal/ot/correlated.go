@@ -1,11 +1,11 @@
 package ot
 
 import (
-	"crypto/rand"
 	"errors"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
@@ -58,7 +58,7 @@ func (r *CorreOTSetupSender) Round1(msg *CorreOTSetupReceiveRound1Message) (*Cor
 		return nil, err
 	}
 
-	_, _ = rand.Read(r._Delta[:])
+	_, _ = random.Read(r._Delta[:])
 
 	randomOTNonces := r.hash.Fork(&hash.BytesWithDomain{
 		TheDomain: "CorreOT Random OT Nonces",
@@ -1,11 +1,11 @@
 package ot
 
 import (
-	"crypto/rand"
 	"encoding/binary"
 	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/zeebo/blake3"
 )
@@ -166,7 +166,7 @@ func ExtendedOTReceive(ctxHash *hash.Hash, setup *CorreOTReceiveSetup, choices [
 	inflatedBatchSize := 8*len(choices) + params.OTParam + params.StatParam
 	extraChoices := make([]byte, inflatedBatchSize/8)
 	copy(extraChoices, choices)
-	_, _ = rand.Read(extraChoices[len(choices):])
+	_, _ = random.Read(extraChoices[len(choices):])
 
 	correMsg, correResult := CorreOTReceive(ctxHash, setup, extraChoices)
 
@@ -1,11 +1,11 @@
 package ot
 
 import (
-	"crypto/rand"
 	"errors"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -27,7 +27,7 @@ func encode(beta curve.Scalar, noise []curve.Scalar) ([]byte, error) {
 	group := beta.Curve()
 
 	gamma := make([]byte, len(noise)/8)
-	_, _ = rand.Read(gamma)
+	_, _ = random.Read(gamma)
 
 	acc := group.NewScalar().Set(beta)
 	mulNat := new(saferith.Nat)
@@ -92,7 +92,7 @@ func NewMultiplySender(ctxHash *hash.Hash, setup *CorreOTSendSetup, alpha curve.
 	gadget := makeGadget(ctxHash, group)
 	var doubleAlpha [2]curve.Scalar
 	doubleAlpha[0] = alpha
-	doubleAlpha[1] = sample.Scalar(rand.Reader, group)
+	doubleAlpha[1] = sample.Scalar(random.Reader(), group)
 	return &MultiplySender{
 		ctxHash:     ctxHash,
 		group:       group,
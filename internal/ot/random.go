@@ -1,12 +1,12 @@
 package ot
 
 import (
-	"crypto/rand"
 	"crypto/subtle"
 	"fmt"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -45,7 +45,7 @@ type RandomOTSendSetup struct {
 //
 // This setup can be done once and then used for multiple executions.
 func RandomOTSetupSend(hash *hash.Hash, group curve.Curve) (*RandomOTSetupSendMessage, *RandomOTSendSetup) {
-	b := sample.Scalar(rand.Reader, group)
+	b := sample.Scalar(random.Reader(), group)
 	B := b.ActOnBase()
 	BProof := zksch.NewProof(hash, B, b, nil)
 	return &RandomOTSetupSendMessage{B: B, BProof: BProof}, &RandomOTSendSetup{_B: B, b: b, _bB: b.Act(B)}
@@ -125,7 +125,7 @@ func (r *RandomOTReceiever) Round1() (outMsg RandomOTReceiveRound1Message, err e
 	// We sample a <- Z_q, and then compute
 	//   A = a * G + w * B
 	//   randChoice = H(a * B)
-	a := sample.Scalar(rand.Reader, r.group)
+	a := sample.Scalar(random.Reader(), r.group)
 	A := a.ActOnBase()
 	outMsg.ABytes, err = A.MarshalBinary()
 	if err != nil {
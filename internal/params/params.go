@@ -7,6 +7,12 @@ const (
 	OTBytes   = OTParam / 8
 	StatParam = 80
 
+	// MinStatParam and MaxStatParam bound the number of repetitions of the zkmod and zkprm proofs,
+	// when another statistical security parameter than StatParam is configured.
+	// The maximum limits the work a verifier does for a single proof.
+	MinStatParam = StatParam
+	MaxStatParam = 256
+
 	// ZKModIterations is the number of iterations that are performed to prove the validity of
 	// a Paillier-Blum modulus N.
 	// Theoretically, the number of iterations corresponds to the statistical security parameter,
@@ -31,3 +37,29 @@ const (
 	BytesPaillier   = BitsPaillier / 8  // = 256
 	BytesCiphertext = 2 * BytesPaillier // = 512
 )
+
+// StatIterations returns the number of repetitions of a proof configured with iterations,
+// which is StatParam if iterations is 0, and otherwise iterations clamped to [MinStatParam, MaxStatParam].
+func StatIterations(iterations int) int {
+	switch {
+	case iterations == 0:
+		return StatParam
+	case iterations < MinStatParam:
+		return MinStatParam
+	case iterations > MaxStatParam:
+		return MaxStatParam
+	}
+	return iterations
+}
+
+// IsValidBitsPaillier returns true if a Paillier modulus may have the given bit length,
+// which must be BitsPaillier, or one of the larger sizes of 3072 and 4096 bits.
+//
+// All the moduli used in one session must have the same length.
+func IsValidBitsPaillier(bits int) bool {
+	switch bits {
+	case BitsPaillier, 3072, 4096:
+		return true
+	}
+	return false
+}
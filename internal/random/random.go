@@ -0,0 +1,46 @@
+// Package random provides the source of randomness of the protocols.
+//
+// This is crypto/rand.Reader, unless the application replaces it through pkg/random,
+// or it is overridden to generate test vectors, which makes executions deterministic.
+package random
+
+import (
+	"crypto/rand"
+	"io"
+	"sync/atomic"
+)
+
+type source struct {
+	reader io.Reader
+}
+
+var current atomic.Value
+
+func init() {
+	current.Store(source{rand.Reader})
+}
+
+// Reader returns the source of randomness to use.
+func Reader() io.Reader {
+	return current.Load().(source).reader
+}
+
+// Read fills b with random bytes from Reader.
+func Read(b []byte) (int, error) {
+	return io.ReadFull(Reader(), b)
+}
+
+// Set replaces the source returned by Reader with r.
+func Set(r io.Reader) {
+	current.Store(source{r})
+}
+
+// Override replaces the source returned by Reader with r, until restore is called.
+//
+// This affects every protocol execution in the process, and must only be used
+// in dedicated tools and tests, never while real keys are generated or used.
+func Override(r io.Reader) (restore func()) {
+	previous := current.Load().(source)
+	current.Store(source{r})
+	return func() { current.Store(previous) }
+}
@@ -0,0 +1,247 @@
+package round
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// Batch runs several sessions of the same protocol in lockstep, as a single session.
+//
+// In every round, the messages the sessions send to the same party are bundled into a single message,
+// so that a batch takes as many rounds and messages as a single session, whatever its size.
+// The sessions are otherwise independent, and each one verifies its own messages.
+type Batch struct {
+	*Helper
+	sessions []Session
+	output   func(results []interface{}) interface{}
+}
+
+// batchBroadcast is a Batch of broadcast rounds, so that the handlers can identify it as such.
+type batchBroadcast struct {
+	*Batch
+}
+
+// batchContent bundles the contents the sessions of a Batch send in the same message.
+// Contents[i] is the encoding of the content of the i-th session.
+type batchContent struct {
+	Number   Number
+	Contents []cbor.RawMessage
+}
+
+// batchBroadcastContent bundles the broadcast contents of the sessions of a Batch.
+type batchBroadcastContent struct {
+	batchContent
+	reliable bool
+}
+
+// NewBatch starts a Batch of the sessions created by starts, which must be sessions of the same protocol.
+//
+// Each session is given its own session ID, derived from the SSID of helper and its position in the batch.
+// Once all sessions have finished, output is called with their results, in order, to produce the result of the Batch.
+// If one of them aborts, the whole Batch aborts.
+func NewBatch(helper *Helper, starts []func(sessionID []byte) (Session, error), output func(results []interface{}) interface{}) (Session, error) {
+	if len(starts) == 0 {
+		return nil, errors.New("batch: no session")
+	}
+	sessions := make([]Session, len(starts))
+	for i, start := range starts {
+		index := make([]byte, 8)
+		binary.BigEndian.PutUint64(index, uint64(i))
+		h := helper.Hash()
+		_ = h.WriteAny(&hash.BytesWithDomain{TheDomain: "Batch Session", Bytes: index})
+		s, err := start(h.Sum())
+		if err != nil {
+			return nil, fmt.Errorf("batch: session %d: %w", i, err)
+		}
+		sessions[i] = s
+	}
+	return newBatch(&Batch{Helper: helper, sessions: sessions, output: output}), nil
+}
+
+// SetLogger sets the logger of the Batch, and of each of its sessions.
+func (b *Batch) SetLogger(l Logger) {
+	b.Helper.SetLogger(l)
+	for _, s := range b.sessions {
+		if s, ok := s.(interface{ SetLogger(Logger) }); ok {
+			s.SetLogger(l)
+		}
+	}
+}
+
+// newBatch returns b as a BroadcastRound if the current rounds of its sessions are.
+func newBatch(b *Batch) Session {
+	if _, ok := b.sessions[0].(BroadcastRound); ok {
+		return &batchBroadcast{Batch: b}
+	}
+	return b
+}
+
+// content decodes the content of the i-th session in a bundled message.
+func (b *Batch) content(i int, data cbor.RawMessage, broadcast bool) (Content, error) {
+	var content Content
+	if broadcast {
+		content = b.sessions[i].(BroadcastRound).BroadcastContent()
+	} else {
+		content = b.sessions[i].MessageContent()
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("batch: session %d: %w", i, ErrNilFields)
+	}
+	if err := cbor.Unmarshal(data, content); err != nil {
+		return nil, fmt.Errorf("batch: session %d: %w", i, err)
+	}
+	return content, nil
+}
+
+// each calls f with the message of every session bundled in msg.
+func (b *Batch) each(msg Message, contents []cbor.RawMessage, f func(s Session, msg Message) error) error {
+	if len(contents) != len(b.sessions) {
+		return fmt.Errorf("batch: got %d contents instead of %d", len(contents), len(b.sessions))
+	}
+	for i, s := range b.sessions {
+		content, err := b.content(i, contents[i], msg.Broadcast)
+		if err != nil {
+			return err
+		}
+		if err = f(s, Message{From: msg.From, To: msg.To, Broadcast: msg.Broadcast, Content: content}); err != nil {
+			return fmt.Errorf("batch: session %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (b *Batch) VerifyMessage(msg Message) error {
+	body, ok := msg.Content.(*batchContent)
+	if !ok || body == nil {
+		return ErrInvalidContent
+	}
+	return b.each(msg, body.Contents, func(s Session, msg Message) error {
+		return s.VerifyMessage(msg)
+	})
+}
+
+// StoreMessage implements round.Round.
+func (b *Batch) StoreMessage(msg Message) error {
+	body, ok := msg.Content.(*batchContent)
+	if !ok || body == nil {
+		return ErrInvalidContent
+	}
+	return b.each(msg, body.Contents, func(s Session, msg Message) error {
+		return s.StoreMessage(msg)
+	})
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+func (b *batchBroadcast) StoreBroadcastMessage(msg Message) error {
+	body, ok := msg.Content.(*batchBroadcastContent)
+	if !ok || body == nil {
+		return ErrInvalidContent
+	}
+	return b.each(msg, body.Contents, func(s Session, msg Message) error {
+		return s.(BroadcastRound).StoreBroadcastMessage(msg)
+	})
+}
+
+// Finalize implements round.Round.
+//
+// The sessions are finalized one after the other, since they may already use the pool themselves.
+func (b *Batch) Finalize(out chan<- *Message) (Session, error) {
+	next := make([]Session, len(b.sessions))
+	results := make([]interface{}, 0, len(b.sessions))
+	var broadcast *batchBroadcastContent
+	messages := make(map[party.ID]*batchContent)
+	var recipients []party.ID
+	for i, s := range b.sessions {
+		sessionOut := make(chan *Message, s.N()+1)
+		r, err := s.Finalize(sessionOut)
+		close(sessionOut)
+		if err != nil {
+			return b, fmt.Errorf("batch: session %d: %w", i, err)
+		}
+		switch r := r.(type) {
+		case *Abort:
+			return b.AbortRound(fmt.Errorf("batch: session %d: %w", i, r.Err), r.Culprits...), nil
+		case *Output:
+			results = append(results, r.Result)
+		}
+		next[i] = r
+
+		for msg := range sessionOut {
+			data, err := cbor.Marshal(msg.Content)
+			if err != nil {
+				return b, fmt.Errorf("batch: session %d: %w", i, err)
+			}
+			if msg.Broadcast {
+				if broadcast == nil {
+					broadcast = &batchBroadcastContent{
+						batchContent: batchContent{Number: msg.Content.RoundNumber(), Contents: make([]cbor.RawMessage, len(b.sessions))},
+						reliable:     msg.Content.(BroadcastContent).Reliable(),
+					}
+				}
+				broadcast.Contents[i] = data
+				continue
+			}
+			bundle, ok := messages[msg.To]
+			if !ok {
+				bundle = &batchContent{Number: msg.Content.RoundNumber(), Contents: make([]cbor.RawMessage, len(b.sessions))}
+				messages[msg.To] = bundle
+				recipients = append(recipients, msg.To)
+			}
+			bundle.Contents[i] = data
+		}
+	}
+
+	if len(results) == len(b.sessions) {
+		return b.ResultRound(b.output(results)), nil
+	}
+	if len(results) > 0 {
+		return b, errors.New("batch: sessions finished in different rounds")
+	}
+
+	if broadcast != nil {
+		if err := b.BroadcastMessage(out, broadcast); err != nil {
+			return b, err
+		}
+	}
+	for _, to := range recipients {
+		if err := b.SendMessage(out, messages[to], to); err != nil {
+			return b, err
+		}
+	}
+	return newBatch(&Batch{Helper: b.Helper, sessions: next, output: b.output}), nil
+}
+
+// MessageContent implements round.Round.
+func (b *Batch) MessageContent() Content {
+	if b.sessions[0].MessageContent() == nil {
+		return nil
+	}
+	return &batchContent{Number: b.Number()}
+}
+
+// BroadcastContent implements round.BroadcastRound.
+func (b *batchBroadcast) BroadcastContent() BroadcastContent {
+	content := b.sessions[0].(BroadcastRound).BroadcastContent()
+	if content == nil {
+		return nil
+	}
+	return &batchBroadcastContent{
+		batchContent: batchContent{Number: b.Number()},
+		reliable:     content.Reliable(),
+	}
+}
+
+// Number implements round.Round.
+func (b *Batch) Number() Number { return b.sessions[0].Number() }
+
+// RoundNumber implements round.Content.
+func (c *batchContent) RoundNumber() Number { return c.Number }
+
+// Reliable implements round.BroadcastContent.
+func (c *batchBroadcastContent) Reliable() bool { return c.reliable }
@@ -31,6 +31,9 @@ type Helper struct {
 
 	hash *hash.Hash
 
+	// logger is set by SetLogger, and is nil by default
+	logger Logger
+
 	mtx sync.Mutex
 }
 
@@ -46,6 +49,13 @@ func NewSession(info Info, sessionID []byte, pl *pool.Pool, auxInfo ...hash.Writ
 		return nil, errors.New("session: partyIDs invalid")
 	}
 
+	// the IDs must be distinct interpolation points
+	if info.Group != nil {
+		if err := partyIDs.CheckScalars(info.Group); err != nil {
+			return nil, fmt.Errorf("session: %w", err)
+		}
+	}
+
 	// verify our ID is present
 	if !partyIDs.Contains(info.SelfID) {
 		return nil, errors.New("session: selfID not included in partyIDs")
@@ -61,8 +71,12 @@ func NewSession(info Info, sessionID []byte, pl *pool.Pool, auxInfo ...hash.Writ
 		return nil, fmt.Errorf("session: threshold %d is invalid for number of parties %d", info.Threshold, n)
 	}
 
+	if !info.Hash.Valid() {
+		return nil, fmt.Errorf("session: unknown hash function %s", info.Hash)
+	}
+
 	var err error
-	h := hash.New()
+	h := hash.NewWithFunction(info.Hash)
 
 	if sessionID != nil {
 		if err = h.WriteAny(&hash.BytesWithDomain{
@@ -189,6 +203,7 @@ func (h *Helper) ResultRound(result interface{}) Session {
 // AbortRound returns a round that contains only the culprits that were able to be identified during
 // a faulty execution of the protocol. The error returned by Round.Finalize() in this case should still be nil.
 func (h *Helper) AbortRound(err error, culprits ...party.ID) Session {
+	h.Logger().Warn("aborting", "error", err, "culprits", culprits)
 	return &Abort{
 		Helper:   h,
 		Culprits: culprits,
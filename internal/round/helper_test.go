@@ -3,8 +3,11 @@ package round_test
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
@@ -114,3 +117,28 @@ func TestNewSession(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSessionHash(t *testing.T) {
+	partyIDs := test.PartyIDs(3)
+	info := round.Info{
+		ProtocolID:       "test",
+		FinalRoundNumber: 2,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs,
+		Threshold:        1,
+		Group:            curve.Secp256k1{},
+	}
+	ssids := map[string]bool{}
+	for _, f := range []hash.Function{hash.BLAKE3, hash.SHA256, hash.SHAKE256} {
+		info.Hash = f
+		h, err := round.NewSession(info, []byte("session"), nil)
+		require.NoError(t, err)
+		assert.Equal(t, f, h.Hash().Function())
+		ssids[string(h.SSID())] = true
+	}
+	assert.Len(t, ssids, 3, "the hash function should be bound into the SSID")
+
+	info.Hash = hash.Function(42)
+	_, err := round.NewSession(info, []byte("session"), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,66 @@
+package round
+
+import "encoding/hex"
+
+// Logger is a structured logger, as used by the handlers and rounds of a session.
+//
+// args are alternating keys and values. This is the method set of *slog.Logger,
+// and other structured loggers, such as logr, are easily adapted to it.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// NopLogger is a Logger discarding everything, used when no logger is set.
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...interface{}) {}
+func (NopLogger) Info(string, ...interface{})  {}
+func (NopLogger) Warn(string, ...interface{})  {}
+func (NopLogger) Error(string, ...interface{}) {}
+
+// WithContext returns a Logger adding args to every entry of l.
+func WithContext(l Logger, args ...interface{}) Logger {
+	if _, ok := l.(NopLogger); ok {
+		return l
+	}
+	return &sessionLogger{logger: l, args: args}
+}
+
+// sessionLogger adds the context of a session to every entry.
+type sessionLogger struct {
+	logger Logger
+	args   []interface{}
+}
+
+func (l *sessionLogger) with(args []interface{}) []interface{} {
+	return append(append(make([]interface{}, 0, len(l.args)+len(args)), l.args...), args...)
+}
+
+func (l *sessionLogger) Debug(msg string, args ...interface{}) { l.logger.Debug(msg, l.with(args)...) }
+func (l *sessionLogger) Info(msg string, args ...interface{})  { l.logger.Info(msg, l.with(args)...) }
+func (l *sessionLogger) Warn(msg string, args ...interface{})  { l.logger.Warn(msg, l.with(args)...) }
+func (l *sessionLogger) Error(msg string, args ...interface{}) { l.logger.Error(msg, l.with(args)...) }
+
+// SetLogger sets the logger used by the session, which is shared by all its rounds.
+//
+// The handlers in pkg/protocol call it with the logger they are given.
+func (h *Helper) SetLogger(l Logger) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.logger = l
+}
+
+// Logger returns the logger of the session, which adds the protocol, SSID and party to every entry.
+//
+// If no logger was set, this discards everything.
+func (h *Helper) Logger() Logger {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.logger == nil {
+		return NopLogger{}
+	}
+	return WithContext(h.logger, "protocol", h.info.ProtocolID, "ssid", hex.EncodeToString(h.ssid), "party", string(h.info.SelfID))
+}
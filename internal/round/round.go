@@ -1,5 +1,7 @@
 package round
 
+import "github.com/taurusgroup/multi-party-sig/pkg/party"
+
 type Round interface {
 	// VerifyMessage handles an incoming Message and validates its content with regard to the protocol specification.
 	// The content argument can be cast to the appropriate type for this round without error check.
@@ -52,3 +54,21 @@ type BroadcastRound interface {
 	// Round must be implemented by an inherited round which would otherwise function the same way.
 	Round
 }
+
+// Suspendable is implemented by rounds whose state can be saved, so that the session can be resumed by another process.
+//
+// The state must contain everything the round needs which was not received in the messages of the current round,
+// including the secrets sampled in previous rounds, so it must be stored securely.
+// When resuming, the messages of the current round are given to the round again.
+type Suspendable interface {
+	// MarshalState returns the state of the round.
+	MarshalState() ([]byte, error)
+}
+
+// PartialRound is implemented by rounds which only expect a normal message from some of the other parties,
+// for instance when all parties talk to a single coordinator.
+type PartialRound interface {
+	// Senders returns the parties a message is expected from in this round.
+	// If it is empty, the round is finalized without waiting for any message.
+	Senders() []party.ID
+}
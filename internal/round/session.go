@@ -19,6 +19,9 @@ type Info struct {
 	Threshold int
 	// Group returns the group used for this protocol execution.
 	Group curve.Curve
+	// Hash is the function used for the hash state of this protocol execution, and is bound into the SSID.
+	// The zero value is hash.BLAKE3.
+	Hash hash.Function
 }
 
 // Session represents the current execution of a round-based protocol.
@@ -0,0 +1,210 @@
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// Tracer records the rounds of the sessions it wraps, as well as the transitions between them,
+// so that they can be checked against a protocol.Model.
+type Tracer struct {
+	mtx    sync.Mutex
+	first  map[string]bool
+	rounds map[string]*protocol.RoundModel
+}
+
+// NewTracer returns a Tracer which hasn't recorded any round.
+func NewTracer() *Tracer {
+	return &Tracer{
+		first:  map[string]bool{},
+		rounds: map[string]*protocol.RoundModel{},
+	}
+}
+
+// Start wraps start so that the rounds of the sessions it creates are recorded.
+func (t *Tracer) Start(start protocol.StartFunc) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		s, err := start(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		return t.Session(s), nil
+	}
+}
+
+// Session wraps the first round of a session, so that the rounds which follow it are recorded.
+func (t *Tracer) Session(s round.Session) round.Session {
+	t.mtx.Lock()
+	t.first[roundName(s)] = true
+	t.mtx.Unlock()
+	return t.wrap(s)
+}
+
+func (t *Tracer) wrap(s round.Session) round.Session {
+	switch s.(type) {
+	case *round.Output, *round.Abort:
+		return s
+	}
+	traced := &tracedSession{Session: s, tracer: t}
+	traced.outer = traced
+	if b, ok := s.(round.BroadcastRound); ok {
+		traced.outer = &tracedBroadcastSession{tracedSession: traced, broadcast: b}
+	}
+	return traced.outer
+}
+
+// record adds the transition from r to next.
+func (t *Tracer) record(r, next round.Session) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	name := roundName(r)
+	observed, ok := t.rounds[name]
+	if !ok {
+		observed = &protocol.RoundModel{
+			Name:    name,
+			Number:  r.Number(),
+			Message: r.MessageContent() != nil,
+		}
+		if b, ok := r.(round.BroadcastRound); ok && b.BroadcastContent() != nil {
+			observed.Broadcast = protocol.NormalBroadcast
+			if b.BroadcastContent().Reliable() {
+				observed.Broadcast = protocol.ReliableBroadcast
+			}
+		}
+		t.rounds[name] = observed
+	}
+	switch next.(type) {
+	case *round.Output:
+		observed.Output = true
+	case *round.Abort:
+		observed.Abort = true
+	default:
+		nextName := roundName(next)
+		for _, n := range observed.Next {
+			if n == nextName {
+				return
+			}
+		}
+		observed.Next = append(observed.Next, nextName)
+	}
+}
+
+// Check returns an error if a recorded round or transition is not part of the model,
+// or if the round differs from the model in the messages it expects.
+//
+// The model may contain transitions which were not recorded, such as aborts which did not happen.
+func (t *Tracer) Check(model *protocol.Model) error {
+	if err := model.Validate(); err != nil {
+		return err
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if len(t.rounds) == 0 {
+		return fmt.Errorf("model %s: no round was recorded", model.Name)
+	}
+	for name := range t.first {
+		if name != model.Rounds[0].Name {
+			return fmt.Errorf("model %s: session started with %s instead of %s", model.Name, name, model.Rounds[0].Name)
+		}
+	}
+
+	names := make([]string, 0, len(t.rounds))
+	for name := range t.rounds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		observed := t.rounds[name]
+		expected := model.Round(name)
+		if expected == nil {
+			return fmt.Errorf("model %s: round %s is missing", model.Name, name)
+		}
+		if observed.Number != expected.Number {
+			return fmt.Errorf("model %s: round %s has number %d instead of %d", model.Name, name, observed.Number, expected.Number)
+		}
+		if observed.Broadcast != expected.Broadcast {
+			return fmt.Errorf("model %s: round %s expects %s broadcast instead of %s", model.Name, name, observed.Broadcast, expected.Broadcast)
+		}
+		if observed.Message != expected.Message {
+			return fmt.Errorf("model %s: round %s expects message: %t instead of %t", model.Name, name, observed.Message, expected.Message)
+		}
+		if observed.Output && !expected.Output {
+			return fmt.Errorf("model %s: round %s produced an output", model.Name, name)
+		}
+		if observed.Abort && !expected.Abort {
+			return fmt.Errorf("model %s: round %s aborted", model.Name, name)
+		}
+	next:
+		for _, n := range observed.Next {
+			for _, e := range expected.Next {
+				if n == e {
+					continue next
+				}
+			}
+			return fmt.Errorf("model %s: transition from %s to %s is missing", model.Name, name, n)
+		}
+	}
+	return nil
+}
+
+// tracedSession forwards all calls to Session, and records the round returned by Finalize.
+type tracedSession struct {
+	round.Session
+	tracer *Tracer
+	// outer is the wrapper handed out to the handler, which is returned when the round is retried
+	outer round.Session
+}
+
+func (s *tracedSession) Finalize(out chan<- *round.Message) (round.Session, error) {
+	next, err := s.Session.Finalize(out)
+	if err != nil {
+		return s.outer, err
+	}
+	s.tracer.record(s.Session, next)
+	return s.tracer.wrap(next), nil
+}
+
+// Senders forwards round.PartialRound, so that the handlers wait for the same messages as for Session.
+func (s *tracedSession) Senders() []party.ID {
+	if p, ok := s.Session.(round.PartialRound); ok {
+		return p.Senders()
+	}
+	return s.OtherPartyIDs()
+}
+
+// tracedBroadcastSession is a tracedSession around a round.BroadcastRound,
+// so that the handlers can still identify it as such.
+type tracedBroadcastSession struct {
+	*tracedSession
+	broadcast round.BroadcastRound
+}
+
+func (s *tracedBroadcastSession) StoreBroadcastMessage(msg round.Message) error {
+	return s.broadcast.StoreBroadcastMessage(msg)
+}
+
+func (s *tracedBroadcastSession) BroadcastContent() round.BroadcastContent {
+	return s.broadcast.BroadcastContent()
+}
+
+// roundName returns the name of the type implementing the round.
+func roundName(r round.Session) string {
+	switch s := r.(type) {
+	case *tracedSession:
+		return roundName(s.Session)
+	case *tracedBroadcastSession:
+		return roundName(s.Session)
+	}
+	t := reflect.TypeOf(r)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
@@ -0,0 +1,182 @@
+// Package backup defines an encrypted file format, holding everything a party needs to restore its share of a key.
+//
+// A Backup bundles the party's marshalled share with the derivations made from it,
+// address labels in the format of BIP-329, and references to the policies governing the key.
+// It is sealed with a passphrase into a single versioned file:
+//
+//	magic ‖ version ‖ Argon2id parameters ‖ salt ‖ nonce ‖ XChaCha20-Poly1305(body)
+//
+// where the header before the ciphertext is authenticated as additional data,
+// so that any modification of the file is detected when opening it.
+package backup
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Version is the version of the file format produced by Seal.
+const Version = 1
+
+var magic = []byte("MPSBACKUP")
+
+const (
+	saltLength   = 16
+	headerLength = 9 + 1 + 4 + 4 + 1 + saltLength + chacha20poly1305.NonceSizeX
+)
+
+// Default Argon2id parameters, as recommended by RFC 9106 for memory constrained environments.
+const (
+	defaultTime    = 3
+	defaultMemory  = 64 * 1024
+	defaultThreads = 4
+	// maxMemory bounds the memory an untrusted file can make Open use, in KiB.
+	maxMemory = 1024 * 1024
+	// maxTime bounds the number of passes an untrusted file can make Open perform.
+	maxTime = 64
+)
+
+var (
+	ErrFormat     = errors.New("backup: not a backup file")
+	ErrVersion    = errors.New("backup: unsupported version")
+	ErrPassphrase = errors.New("backup: wrong passphrase or corrupted file")
+)
+
+// Backup is the content of a backup file, for the share of a single party of a single key.
+type Backup struct {
+	// Protocol is the name of the protocol the share belongs to, such as "cmp" or "frost".
+	Protocol string
+	// Group is the name of the curve of the key, as returned by curve.Curve.Name.
+	Group string
+	// Share is the output of the share's MarshalBinary method.
+	Share []byte
+	// Derivations lists the child keys derived from the share.
+	Derivations []Derivation `cbor:",omitempty"`
+	// Labels are the labels attached to addresses and transactions of the key.
+	Labels []Label `cbor:",omitempty"`
+	// Policies references the policies governing the use of the key.
+	Policies []PolicyRef `cbor:",omitempty"`
+}
+
+// Derivation records a child key derived from the share, so that it can be derived again after a restore.
+type Derivation struct {
+	// Path contains the unhardened BIP-32 indices, applied in order.
+	Path []uint32
+	// PublicKey is the encoding of the derived public key, used to check the derivation after a restore.
+	PublicKey []byte `cbor:",omitempty"`
+}
+
+// PolicyRef identifies a policy by its digest, without including it.
+type PolicyRef struct {
+	// Name is a human readable identifier of the policy.
+	Name string
+	// Version is the version of the policy, such as a governance.Change.Version.
+	Version uint64
+	// Digest is the hash of the policy document, such as a governance.Change.Digest.
+	Digest []byte
+}
+
+// Validate checks that the backup contains a share, and describes what it belongs to.
+func (b *Backup) Validate() error {
+	if b == nil {
+		return errors.New("backup: nil backup")
+	}
+	if b.Protocol == "" || b.Group == "" {
+		return errors.New("backup: missing protocol or group")
+	}
+	if len(b.Share) == 0 {
+		return errors.New("backup: missing share")
+	}
+	for _, l := range b.Labels {
+		if err := l.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Seal encrypts the backup with a key derived from passphrase, and returns the content of the backup file.
+func Seal(b *Backup, passphrase []byte) ([]byte, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := cbor.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+
+	header := make([]byte, 0, headerLength)
+	header = append(header, magic...)
+	header = append(header, Version)
+	header = binary.BigEndian.AppendUint32(header, defaultTime)
+	header = binary.BigEndian.AppendUint32(header, defaultMemory)
+	header = append(header, defaultThreads)
+	random := make([]byte, saltLength+chacha20poly1305.NonceSizeX)
+	if _, err = rand.Read(random); err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+	header = append(header, random...)
+
+	aead, nonce, err := newAEAD(header, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, body, header), nil
+}
+
+// Open decrypts the content of a backup file created by Seal.
+func Open(data, passphrase []byte) (*Backup, error) {
+	if len(data) < headerLength || !bytes.Equal(data[:len(magic)], magic) {
+		return nil, ErrFormat
+	}
+	if v := data[len(magic)]; v != Version {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, v)
+	}
+	header, ciphertext := data[:headerLength], data[headerLength:]
+
+	aead, nonce, err := newAEAD(header, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	body, err := aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+
+	b := &Backup{}
+	if err = cbor.Unmarshal(body, b); err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+	if err = b.Validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// newAEAD returns the AEAD keyed by the passphrase and the parameters in header, as well as the nonce.
+func newAEAD(header, passphrase []byte) (aead cipher.AEAD, nonce []byte, err error) {
+	params := header[len(magic)+1:]
+	passes := binary.BigEndian.Uint32(params[0:4])
+	memory := binary.BigEndian.Uint32(params[4:8])
+	threads := params[8]
+	salt := params[9 : 9+saltLength]
+	nonce = params[9+saltLength:]
+	if passes == 0 || passes > maxTime || memory < 8*uint32(threads) || memory > maxMemory || threads == 0 {
+		return nil, nil, ErrFormat
+	}
+
+	key := argon2.IDKey(passphrase, salt, passes, memory, threads, chacha20poly1305.KeySize)
+	aead, err = chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("backup: %w", err)
+	}
+	return aead, nonce, nil
+}
@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBackup() *Backup {
+	spendable := false
+	return &Backup{
+		Protocol: "cmp",
+		Group:    "secp256k1",
+		Share:    []byte("share"),
+		Derivations: []Derivation{
+			{Path: []uint32{0, 1}, PublicKey: []byte{2, 3}},
+		},
+		Labels: []Label{
+			{Type: "tx", Ref: "f91d0a8a78462bc59398f2c5d7a84fcff491c26ba54c4833478b202796c8aafd", Label: "Transaction"},
+			{Type: "output", Ref: "f91d0a8a78462bc59398f2c5d7a84fcff491c26ba54c4833478b202796c8aafd:1", Spendable: &spendable},
+		},
+		Policies: []PolicyRef{
+			{Name: "treasury", Version: 2, Digest: []byte{4, 5, 6}},
+		},
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	b := testBackup()
+	passphrase := []byte("correct horse battery staple")
+
+	data, err := Seal(b, passphrase)
+	require.NoError(t, err)
+
+	opened, err := Open(data, passphrase)
+	require.NoError(t, err)
+	assert.Equal(t, b, opened)
+
+	_, err = Open(data, []byte("wrong"))
+	assert.True(t, errors.Is(err, ErrPassphrase))
+
+	for _, i := range []int{len(magic) + 2, headerLength - 1, len(data) - 1} {
+		tampered := append([]byte{}, data...)
+		tampered[i] ^= 1
+		_, err = Open(tampered, passphrase)
+		assert.Error(t, err, "modified byte %d", i)
+	}
+
+	tampered := append([]byte{}, data...)
+	tampered[len(magic)] = Version + 1
+	_, err = Open(tampered, passphrase)
+	assert.True(t, errors.Is(err, ErrVersion))
+
+	_, err = Open(data[:headerLength-1], passphrase)
+	assert.True(t, errors.Is(err, ErrFormat))
+
+	_, err = Seal(&Backup{Protocol: "cmp", Group: "secp256k1"}, passphrase)
+	assert.Error(t, err, "sealed a backup without share")
+}
+
+func TestLabels(t *testing.T) {
+	labels := testBackup().Labels
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteLabels(&buf, labels))
+	read, err := ReadLabels(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, labels, read)
+
+	_, err = ReadLabels(bytes.NewBufferString(`{"type":"addr","ref":"bc1q","spendable":true}`))
+	assert.Error(t, err, "read a spendable address")
+	_, err = ReadLabels(bytes.NewBufferString(`{"type":"unknown","ref":"x"}`))
+	assert.Error(t, err, "read an unknown type")
+}
@@ -0,0 +1,80 @@
+package backup
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Label is a record of BIP-329, attaching a label to a transaction, address, key, input or output.
+type Label struct {
+	// Type is one of "tx", "addr", "pubkey", "input", "output" or "xpub".
+	Type string `json:"type"`
+	// Ref is the object being labelled, such as a transaction ID or an address.
+	Ref string `json:"ref"`
+	// Label is the text of the label.
+	Label string `json:"label,omitempty"`
+	// Origin optionally describes the descriptor the object belongs to.
+	Origin string `json:"origin,omitempty"`
+	// Spendable is only used for outputs, and indicates whether the output may be spent.
+	Spendable *bool `json:"spendable,omitempty"`
+}
+
+var labelTypes = map[string]bool{
+	"tx":     true,
+	"addr":   true,
+	"pubkey": true,
+	"input":  true,
+	"output": true,
+	"xpub":   true,
+}
+
+// Validate checks that the label has a known type and a reference.
+func (l *Label) Validate() error {
+	if !labelTypes[l.Type] {
+		return fmt.Errorf("backup: unknown label type %q", l.Type)
+	}
+	if l.Ref == "" {
+		return fmt.Errorf("backup: %s label without reference", l.Type)
+	}
+	if l.Spendable != nil && l.Type != "output" {
+		return fmt.Errorf("backup: %s label cannot be spendable", l.Type)
+	}
+	return nil
+}
+
+// WriteLabels writes labels in the JSON Lines export format of BIP-329,
+// so that they can be imported in other wallets.
+func WriteLabels(w io.Writer, labels []Label) error {
+	enc := json.NewEncoder(w)
+	for i := range labels {
+		if err := enc.Encode(&labels[i]); err != nil {
+			return fmt.Errorf("backup: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadLabels reads labels in the JSON Lines export format of BIP-329.
+func ReadLabels(r io.Reader) ([]Label, error) {
+	var labels []Label
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var l Label
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return nil, fmt.Errorf("backup: line %d: %w", line, err)
+		}
+		if err := l.Validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		labels = append(labels, l)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+	return labels, nil
+}
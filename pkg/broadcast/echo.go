@@ -0,0 +1,104 @@
+// Package broadcast implements the echo broadcast of Goldwasser and Lindell,
+// which turns a point-to-point network into a broadcast channel with abort.
+//
+// Every party sends its message to all others, and includes in its messages for the next round a digest of
+// all the messages it received. If a party sent different messages to different parties, the digests
+// differ and the honest parties abort. See docs/Broadcast.md for details.
+//
+// With only two parties, each broadcast has a single recipient, so the check can be skipped.
+package broadcast
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+var (
+	// ErrEquivocation is returned when a party broadcasts two different messages in the same round.
+	ErrEquivocation = errors.New("broadcast: party sent two different messages")
+	// ErrInconsistent is returned when another party received different broadcast messages than we did.
+	ErrInconsistent = errors.New("broadcast: inconsistent digest")
+)
+
+// Echo collects the messages broadcast by a fixed set of parties during one round.
+type Echo struct {
+	parties  party.IDSlice
+	hash     *hash.Hash
+	messages map[party.ID][]byte
+	digest   []byte
+}
+
+// New returns an Echo for a round in which all parties broadcast a message, including ourselves.
+//
+// h should be bound to the session, so that digests can't be reused in another one. It is cloned, and not modified.
+func New(parties []party.ID, h *hash.Hash) *Echo {
+	ids := party.NewIDSlice(parties)
+	return &Echo{
+		parties:  ids,
+		hash:     h.Clone(),
+		messages: make(map[party.ID][]byte, len(ids)),
+	}
+}
+
+// Receive records the message broadcast by from, which is typically the hash of the message.
+// Receiving the same message twice has no effect.
+func (e *Echo) Receive(from party.ID, message []byte) error {
+	if !e.parties.Contains(from) {
+		return fmt.Errorf("broadcast: unknown party %s", from)
+	}
+	if previous, ok := e.messages[from]; ok {
+		if !bytes.Equal(previous, message) {
+			return fmt.Errorf("%w: %s", ErrEquivocation, from)
+		}
+		return nil
+	}
+	e.messages[from] = append([]byte(nil), message...)
+	return nil
+}
+
+// Missing returns the parties whose message wasn't received yet.
+func (e *Echo) Missing() []party.ID {
+	var missing []party.ID
+	for _, id := range e.parties {
+		if _, ok := e.messages[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// Digest returns the digest of the round's messages, which must be sent along with the messages
+// of the next round. It returns nil until the messages of all parties have been received.
+func (e *Echo) Digest() []byte {
+	if len(e.messages) != len(e.parties) {
+		return nil
+	}
+	if e.digest == nil {
+		h := e.hash.Clone()
+		for _, id := range e.parties {
+			_ = h.WriteAny(&hash.BytesWithDomain{
+				TheDomain: "Message",
+				Bytes:     e.messages[id],
+			})
+		}
+		e.digest = h.Sum()
+	}
+	return e.digest
+}
+
+// Verify checks that digest, sent by another party along with a message of the next round,
+// matches the messages we received.
+func (e *Echo) Verify(digest []byte) error {
+	expected := e.Digest()
+	if expected == nil {
+		return fmt.Errorf("broadcast: missing messages from %v", e.Missing())
+	}
+	if !bytes.Equal(expected, digest) {
+		return ErrInconsistent
+	}
+	return nil
+}
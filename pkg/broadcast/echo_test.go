@@ -0,0 +1,49 @@
+package broadcast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/broadcast"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+func TestEcho(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	session := hash.New(&hash.BytesWithDomain{TheDomain: "SSID", Bytes: []byte("session")})
+	a := broadcast.New(ids, session)
+	b := broadcast.New(ids, session)
+
+	for _, id := range ids {
+		assert.Nil(t, a.Digest())
+		assert.Contains(t, a.Missing(), id)
+		require.NoError(t, a.Receive(id, []byte(id)))
+		require.NoError(t, b.Receive(id, []byte(id)))
+	}
+	assert.Empty(t, a.Missing())
+	require.NotNil(t, a.Digest())
+	assert.Equal(t, a.Digest(), b.Digest())
+	assert.NoError(t, a.Verify(b.Digest()))
+
+	// duplicates are ignored, but not equivocations
+	assert.NoError(t, a.Receive("a", []byte("a")))
+	assert.ErrorIs(t, a.Receive("a", []byte("other")), broadcast.ErrEquivocation)
+	assert.Error(t, a.Receive("d", []byte("d")))
+
+	// another party received a different message from c
+	c := broadcast.New(ids, session)
+	require.NoError(t, c.Receive("a", []byte("a")))
+	require.NoError(t, c.Receive("b", []byte("b")))
+	require.NoError(t, c.Receive("c", []byte("c'")))
+	assert.ErrorIs(t, a.Verify(c.Digest()), broadcast.ErrInconsistent)
+	assert.Error(t, broadcast.New(ids, session).Verify(a.Digest()), "incomplete round")
+
+	// digests are bound to the session
+	other := broadcast.New(ids, hash.New(&hash.BytesWithDomain{TheDomain: "SSID", Bytes: []byte("other session")}))
+	for _, id := range ids {
+		require.NoError(t, other.Receive(id, []byte(id)))
+	}
+	assert.NotEqual(t, a.Digest(), other.Digest())
+}
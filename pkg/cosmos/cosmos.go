@@ -0,0 +1,221 @@
+// Package cosmos encodes secp256k1 keys and ECDSA signatures in the formats of Cosmos SDK chains.
+//
+// Cosmos SDK chains sign the SHA-256 digest of the sign bytes of a transaction, so a threshold signature for them is produced
+// by giving Digest(signBytes) to cmp.Sign, and converting the result with Signature.
+package cosmos
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	// PublicKeyLen is the number of bytes of a compressed secp256k1 public key.
+	PublicKeyLen = 33
+	// SignatureLen is the number of bytes of a signature r ‖ s.
+	SignatureLen = 64
+	// PublicKeyTypeURL is the type URL of the protobuf PubKey message of the Cosmos SDK, in an Any.
+	PublicKeyTypeURL = "/cosmos.crypto.secp256k1.PubKey"
+	// AminoPublicKeyName is the name under which amino registers secp256k1 public keys.
+	AminoPublicKeyName = "tendermint/PubKeySecp256k1"
+)
+
+// aminoPrefix is the prefix of the amino binary encoding of a secp256k1 public key, followed by its length.
+var aminoPrefix = []byte{0xeb, 0x5a, 0xe9, 0x87, PublicKeyLen}
+
+// Digest returns the SHA-256 digest of signBytes, which is the hash to sign for a Cosmos SDK transaction.
+func Digest(signBytes []byte) []byte {
+	digest := sha256.Sum256(signBytes)
+	return digest[:]
+}
+
+// PublicKey returns the compressed encoding of a secp256k1 public key.
+func PublicKey(p curve.Point) ([]byte, error) {
+	if _, ok := p.(*curve.Secp256k1Point); !ok {
+		return nil, errors.New("cosmos: public key must be on secp256k1")
+	}
+	if p.IsIdentity() {
+		return nil, errors.New("cosmos: public key is the identity")
+	}
+	return p.MarshalBinary()
+}
+
+// Signature returns the 64 bytes r ‖ s of sig, with S normalized to the lower half of the order,
+// as the Cosmos SDK rejects other signatures.
+func Signature(sig *ecdsa.Signature) ([]byte, error) {
+	if _, ok := sig.R.(*curve.Secp256k1Point); !ok {
+		return nil, errors.New("cosmos: signature must be on secp256k1")
+	}
+	normalized := sig.Normalize()
+	r, err := normalized.R.XScalar().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	s, err := normalized.S.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(r, s...), nil
+}
+
+// Verify checks a signature r ‖ s of signBytes by the compressed public key, as the Cosmos SDK does.
+func Verify(publicKey, signBytes, signature []byte) bool {
+	group := curve.Secp256k1{}
+	X := group.NewPoint()
+	if len(publicKey) != PublicKeyLen || X.UnmarshalBinary(publicKey) != nil || len(signature) != SignatureLen {
+		return false
+	}
+	r, s := group.NewScalar(), group.NewScalar()
+	if r.UnmarshalBinary(signature[:32]) != nil || s.UnmarshalBinary(signature[32:]) != nil ||
+		r.IsZero() || s.IsZero() || s.IsOverHalfOrder() {
+		return false
+	}
+	// R is recovered from its x coordinate r, up to its sign, which doesn't change R.XScalar
+	digest := Digest(signBytes)
+	m := curve.FromHash(group, digest)
+	sInv := group.NewScalar().Set(s).Invert()
+	R := sInv.Act(m.ActOnBase().Add(r.Act(X)))
+	return !R.IsIdentity() && R.XScalar().Equal(r)
+}
+
+// Any is a protobuf google.protobuf.Any message.
+type Any struct {
+	// TypeURL identifies the type of the message in Value.
+	TypeURL string
+	// Value is the protobuf encoding of the message.
+	Value []byte
+}
+
+// Marshal returns the protobuf encoding of a.
+func (a *Any) Marshal() []byte {
+	out := appendBytes(nil, 1, []byte(a.TypeURL))
+	return appendBytes(out, 2, a.Value)
+}
+
+// ProtoPublicKey returns the public key as an Any holding a cosmos.crypto.secp256k1.PubKey,
+// as found in the signer infos of transactions and in the accounts of the auth module.
+func ProtoPublicKey(p curve.Point) (*Any, error) {
+	key, err := PublicKey(p)
+	if err != nil {
+		return nil, err
+	}
+	return &Any{TypeURL: PublicKeyTypeURL, Value: appendBytes(nil, 1, key)}, nil
+}
+
+// AminoPublicKey returns the amino binary encoding of the public key,
+// which legacy bech32 public keys, such as those starting with "cosmospub", encode.
+func AminoPublicKey(p curve.Point) ([]byte, error) {
+	key, err := PublicKey(p)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, aminoPrefix...), key...), nil
+}
+
+// AminoJSONPublicKey returns the amino JSON encoding of the public key,
+// as found in legacy amino JSON sign docs and in the output of the Cosmos SDK CLI.
+func AminoJSONPublicKey(p curve.Point) ([]byte, error) {
+	key, err := PublicKey(p)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}{AminoPublicKeyName, base64.StdEncoding.EncodeToString(key)})
+}
+
+// Address returns the 20 bytes address of the public key, RIPEMD-160(SHA-256(key)).
+func Address(p curve.Point) ([]byte, error) {
+	key, err := PublicKey(p)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(key)
+	h := ripemd160.New()
+	_, _ = h.Write(digest[:])
+	return h.Sum(nil), nil
+}
+
+// Bech32Address returns the address of the public key in bech32, with the human readable part of the chain,
+// such as "cosmos" for the Cosmos Hub.
+func Bech32Address(hrp string, p curve.Point) (string, error) {
+	address, err := Address(p)
+	if err != nil {
+		return "", err
+	}
+	return bech32(hrp, address)
+}
+
+// appendBytes appends a protobuf length delimited field, whose lengths are always short enough for a single byte varint.
+func appendBytes(out []byte, field byte, data []byte) []byte {
+	return append(append(out, field<<3|2, byte(len(data))), data...)
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32 encodes data as specified by BIP-173.
+func bech32(hrp string, data []byte) (string, error) {
+	if hrp == "" || strings.ToLower(hrp) != hrp {
+		return "", errors.New("cosmos: invalid human readable part")
+	}
+	// regroup the bytes into 5 bits words
+	var words []byte
+	acc, bits := 0, 0
+	for _, b := range data {
+		acc = (acc<<8 | int(b)) & 0xfff
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			words = append(words, byte(acc>>bits&31))
+		}
+	}
+	if bits > 0 {
+		words = append(words, byte(acc<<(5-bits)&31))
+	}
+
+	values := make([]byte, 0, 2*len(hrp)+1+len(words)+6)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	values = append(values, words...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	checksum := bech32Polymod(values) ^ 1
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, w := range words {
+		sb.WriteByte(bech32Charset[w])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[checksum>>(5*(5-i))&31])
+	}
+	return sb.String(), nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if top>>i&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
@@ -0,0 +1,103 @@
+package cosmos
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// sign computes an ECDSA signature of digest with the whole secret x, as the signing protocols would.
+func sign(x curve.Scalar, digest []byte) *ecdsa.Signature {
+	group := curve.Secp256k1{}
+	k := sample.Scalar(rand.Reader, group)
+	R := k.ActOnBase()
+	m := curve.FromHash(group, digest)
+	s := group.NewScalar().Set(R.XScalar()).Mul(x).Add(m).Mul(group.NewScalar().Set(k).Invert())
+	return &ecdsa.Signature{R: R, S: s}
+}
+
+func TestSignature(t *testing.T) {
+	group := curve.Secp256k1{}
+	signBytes := []byte(`{"account_number":"1","chain_id":"cosmoshub-4","fee":{},"memo":"","msgs":[],"sequence":"0"}`)
+	for i := 0; i < 10; i++ {
+		x := sample.Scalar(rand.Reader, group)
+		publicKey, err := PublicKey(x.ActOnBase())
+		require.NoError(t, err)
+		require.Len(t, publicKey, PublicKeyLen)
+
+		sig := sign(x, Digest(signBytes))
+		encoded, err := Signature(sig)
+		require.NoError(t, err)
+		require.Len(t, encoded, SignatureLen)
+		assert.True(t, Verify(publicKey, signBytes, encoded))
+		assert.False(t, Verify(publicKey, append(signBytes, ' '), encoded))
+
+		// the high-S form of the same signature is rejected
+		s := group.NewScalar()
+		require.NoError(t, s.UnmarshalBinary(encoded[32:]))
+		high, _ := s.Negate().MarshalBinary()
+		assert.False(t, Verify(publicKey, signBytes, append(append([]byte{}, encoded[:32]...), high...)))
+	}
+
+	_, err := PublicKey(curve.Edwards25519{}.NewBasePoint())
+	assert.Error(t, err)
+	_, err = PublicKey(group.NewPoint())
+	assert.Error(t, err)
+}
+
+func TestPublicKeyEncodings(t *testing.T) {
+	group := curve.Secp256k1{}
+	X := sample.Scalar(rand.Reader, group).ActOnBase()
+	key, err := PublicKey(X)
+	require.NoError(t, err)
+
+	pk, err := ProtoPublicKey(X)
+	require.NoError(t, err)
+	assert.Equal(t, "/cosmos.crypto.secp256k1.PubKey", pk.TypeURL)
+	assert.Equal(t, append([]byte{0x0a, 0x21}, key...), pk.Value)
+	expected := append([]byte{0x0a, byte(len(PublicKeyTypeURL))}, PublicKeyTypeURL...)
+	expected = append(append(expected, 0x12, 0x23), pk.Value...)
+	assert.Equal(t, expected, pk.Marshal())
+
+	amino, err := AminoPublicKey(X)
+	require.NoError(t, err)
+	assert.Equal(t, "eb5ae98721"+hex.EncodeToString(key), hex.EncodeToString(amino))
+
+	aminoJSON, err := AminoJSONPublicKey(X)
+	require.NoError(t, err)
+	var decoded struct{ Type, Value string }
+	require.NoError(t, json.Unmarshal(aminoJSON, &decoded))
+	assert.Equal(t, "tendermint/PubKeySecp256k1", decoded.Type)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(key), decoded.Value)
+
+	address, err := Address(X)
+	require.NoError(t, err)
+	digest := sha256.Sum256(key)
+	h := ripemd160.New()
+	_, _ = h.Write(digest[:])
+	assert.Equal(t, h.Sum(nil), address)
+}
+
+func TestBech32(t *testing.T) {
+	// valid strings of BIP-173
+	encoded, err := bech32("a", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "a12uel5l", encoded)
+	data, _ := hex.DecodeString("00443214c74254b635cf84653a56d7c675be77df")
+	encoded, err = bech32("abcdef", data)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", encoded)
+
+	_, err = bech32("Cosmos", data)
+	assert.Error(t, err)
+}
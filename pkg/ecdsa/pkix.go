@@ -0,0 +1,135 @@
+package ecdsa
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+var (
+	// oidPublicKeyECDSA is id-ecPublicKey, from RFC 5480.
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	// oidNamedCurveSecp256k1 is secp256k1, from SEC 2.
+	oidNamedCurveSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// subjectPublicKeyInfo is the ASN.1 structure of a DER encoded public key, from RFC 5280.
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// secp256k1PublicKey returns the public key of p, which must be a point of secp256k1, other than the identity.
+func secp256k1PublicKey(p curve.Point) (*secp256k1.PublicKey, error) {
+	if _, ok := p.(*curve.Secp256k1Point); !ok || p.IsIdentity() {
+		return nil, errors.New("ecdsa: public key must be a point of secp256k1, other than the identity")
+	}
+	data, _ := curve.MarshalSEC1(p, false)
+	return secp256k1.ParsePubKey(data)
+}
+
+// StdlibPublicKey converts the public key p to a *crypto/ecdsa.PublicKey, over secp256k1.S256().
+//
+// The standard library doesn't support secp256k1, so this is only useful for APIs
+// taking an elliptic.Curve, such as crypto/ecdsa.Verify.
+func StdlibPublicKey(p curve.Point) (*stdecdsa.PublicKey, error) {
+	pk, err := secp256k1PublicKey(p)
+	if err != nil {
+		return nil, err
+	}
+	return pk.ToECDSA(), nil
+}
+
+// MarshalPKIXPublicKey returns the DER encoding of the public key p, as a SubjectPublicKeyInfo with
+// the secp256k1 named curve, which certificates and most key formats embed.
+//
+// crypto/x509.MarshalPKIXPublicKey doesn't support secp256k1.
+func MarshalPKIXPublicKey(p curve.Point) ([]byte, error) {
+	pk, err := secp256k1PublicKey(p)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(oidNamedCurveSecp256k1)
+	if err != nil {
+		return nil, err
+	}
+	point := pk.SerializeUncompressed()
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+// ParsePKIXPublicKey decodes a secp256k1 public key encoded by MarshalPKIXPublicKey.
+func ParsePKIXPublicKey(der []byte) (curve.Point, error) {
+	var info subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("ecdsa: trailing data after public key")
+	}
+	var namedCurve asn1.ObjectIdentifier
+	if !info.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, errors.New("ecdsa: not an elliptic curve public key")
+	}
+	if _, err = asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &namedCurve); err != nil || !namedCurve.Equal(oidNamedCurveSecp256k1) {
+		return nil, errors.New("ecdsa: public key is not over secp256k1")
+	}
+	pk, err := secp256k1.ParsePubKey(info.PublicKey.RightAlign())
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa: %w", err)
+	}
+	p := curve.Secp256k1{}.NewPoint()
+	if err = p.UnmarshalBinary(pk.SerializeCompressed()); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// MarshalPEMPublicKey returns the DER encoding of MarshalPKIXPublicKey in a "PUBLIC KEY" PEM block.
+func MarshalPEMPublicKey(p curve.Point) ([]byte, error) {
+	der, err := MarshalPKIXPublicKey(p)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// jwk is a JSON Web Key, from RFC 7517, with the secp256k1 curve name of RFC 8812.
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// MarshalJWK returns the public key p as a JSON Web Key, with the key ID kid, if not empty,
+// to be published in a JWKS and used with the ES256K algorithm.
+func MarshalJWK(p curve.Point, kid string) ([]byte, error) {
+	pk, err := secp256k1PublicKey(p)
+	if err != nil {
+		return nil, err
+	}
+	point := pk.SerializeUncompressed()
+	return json.Marshal(jwk{
+		Kty: "EC",
+		Crv: "secp256k1",
+		X:   base64.RawURLEncoding.EncodeToString(point[1:33]),
+		Y:   base64.RawURLEncoding.EncodeToString(point[33:]),
+		Kid: kid,
+	})
+}
@@ -0,0 +1,57 @@
+package ecdsa
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+func TestPKIXPublicKey(t *testing.T) {
+	group := curve.Secp256k1{}
+	x := sample.Scalar(rand.Reader, group)
+	X := x.ActOnBase()
+
+	der, err := MarshalPKIXPublicKey(X)
+	require.NoError(t, err)
+	decoded, err := ParsePKIXPublicKey(der)
+	require.NoError(t, err)
+	assert.True(t, X.Equal(decoded))
+
+	data, err := MarshalPEMPublicKey(X)
+	require.NoError(t, err)
+	block, _ := pem.Decode(data)
+	require.NotNil(t, block)
+	assert.Equal(t, "PUBLIC KEY", block.Type)
+	assert.Equal(t, der, block.Bytes)
+
+	// signatures of the library verify with the standard library
+	hash := sha256.Sum256([]byte("hello"))
+	sig := NewSignature(x, hash[:], nil)
+	pk, err := StdlibPublicKey(X)
+	require.NoError(t, err)
+	r, _ := sig.R.XScalar().MarshalBinary()
+	s, _ := sig.S.MarshalBinary()
+	assert.True(t, stdecdsa.Verify(pk, hash[:], new(big.Int).SetBytes(r), new(big.Int).SetBytes(s)))
+
+	key, err := MarshalJWK(X, "key-1")
+	require.NoError(t, err)
+	var fields map[string]string
+	require.NoError(t, json.Unmarshal(key, &fields))
+	assert.Equal(t, "secp256k1", fields["crv"])
+	assert.Equal(t, "key-1", fields["kid"])
+	assert.Len(t, fields["x"], 43)
+
+	_, err = MarshalPKIXPublicKey(group.NewPoint())
+	assert.Error(t, err)
+	_, err = StdlibPublicKey(curve.Edwards25519{}.NewBasePoint())
+	assert.Error(t, err)
+}
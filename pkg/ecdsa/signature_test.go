@@ -2,10 +2,12 @@ package ecdsa
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"testing"
 
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"golang.org/x/crypto/sha3"
 )
 
 func NewSignature(x curve.Scalar, hash []byte, k curve.Scalar) *Signature {
@@ -59,3 +61,38 @@ func TestSignature_Verify_Zero(t *testing.T) {
 		t.Error("zero R/S signature should not verify")
 	}
 }
+
+func TestSignature_VerifyMessage(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	m := []byte("hello")
+	digest := sha256.Sum256(m)
+	x := sample.Scalar(rand.Reader, group)
+	X := x.ActOnBase()
+	sig := NewSignature(x, digest[:], nil)
+	if !sig.VerifyMessage(X, m, sha256.New) {
+		t.Error("verify failed")
+	}
+	if sig.VerifyMessage(X, m, sha3.NewLegacyKeccak256) {
+		t.Error("verify should fail with another hash function")
+	}
+
+	// (R, S) and (-R, -S) are both valid, and one of them has a low S
+	high := &Signature{R: sig.R, S: sig.S}
+	if high.IsLowS() {
+		high = &Signature{R: sig.R.Negate(), S: group.NewScalar().Set(sig.S).Negate()}
+	}
+	low := &Signature{R: high.R.Negate(), S: group.NewScalar().Set(high.S).Negate()}
+	if !high.VerifyWith(X, digest[:]) || !low.VerifyWith(X, digest[:]) {
+		t.Error("both forms of S should verify by default")
+	}
+	if high.VerifyWith(X, digest[:], RequireLowS()) {
+		t.Error("high S should not verify with RequireLowS")
+	}
+	if !low.VerifyWith(X, digest[:], RequireLowS()) {
+		t.Error("low S should verify with RequireLowS")
+	}
+	if normalized := high.Normalize(); !normalized.IsLowS() || !normalized.VerifyWith(X, digest[:], RequireLowS()) {
+		t.Error("normalized signature should have a low S")
+	}
+}
@@ -0,0 +1,64 @@
+package ecdsa
+
+import (
+	"hash"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// VerifyOption changes the checks made by Signature.VerifyWith and Signature.VerifyMessage.
+type VerifyOption func(*verifyOptions)
+
+type verifyOptions struct {
+	lowS bool
+}
+
+// RequireLowS rejects signatures whose S is over half the group order.
+//
+// Bitcoin and Ethereum only accept such low-S signatures, so that a signature can't be modified
+// into another valid one by negating S. By default, both forms are accepted,
+// since the protocols of this library don't normalize S.
+func RequireLowS() VerifyOption {
+	return func(o *verifyOptions) {
+		o.lowS = true
+	}
+}
+
+// IsLowS returns true if S is at most half the group order.
+func (sig Signature) IsLowS() bool {
+	return !sig.S.IsOverHalfOrder()
+}
+
+// Normalize returns the low-S form of sig, which is (-R, -S) if S is over half the group order.
+func (sig Signature) Normalize() Signature {
+	if sig.IsLowS() {
+		return sig
+	}
+	return Signature{R: sig.R.Negate(), S: sig.S.Curve().NewScalar().Set(sig.S).Negate()}
+}
+
+// VerifyWith is like Verify, with additional checks given by opts.
+//
+// As in Verify, hash is the digest that was given to the signing protocol,
+// and is truncated to the bit length of the group order.
+func (sig Signature) VerifyWith(X curve.Point, hash []byte, opts ...VerifyOption) bool {
+	var o verifyOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.lowS && !sig.IsLowS() {
+		return false
+	}
+	return sig.Verify(X, hash)
+}
+
+// VerifyMessage hashes message with a new instance of hashFn, and verifies the signature on the digest,
+// as VerifyWith would.
+//
+// Use it when the digest passed to the signing protocol was computed as hashFn(message),
+// for instance with sha256.New for Bitcoin, or sha3.NewLegacyKeccak256 for Ethereum.
+func (sig Signature) VerifyMessage(X curve.Point, message []byte, hashFn func() hash.Hash, opts ...VerifyOption) bool {
+	h := hashFn()
+	_, _ = h.Write(message)
+	return sig.VerifyWith(X, h.Sum(nil), opts...)
+}
@@ -2,12 +2,12 @@ package hash
 
 import (
 	"bytes"
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
 
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 )
 
 type (
@@ -73,7 +73,7 @@ func (hash *Hash) Commit(data ...interface{}) (Commitment, Decommitment, error)
 	var err error
 	decommitment := Decommitment(make([]byte, params.SecBytes))
 
-	if _, err = rand.Read(decommitment); err != nil {
+	if _, err = random.Read(decommitment); err != nil {
 		return nil, nil, fmt.Errorf("hash.Commit: failed to generate decommitment: %w", err)
 	}
 
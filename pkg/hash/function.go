@@ -0,0 +1,124 @@
+package hash
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	stdhash "hash"
+	"io"
+
+	"github.com/zeebo/blake3"
+	"golang.org/x/crypto/sha3"
+)
+
+// Function identifies the hash function underlying a Hash.
+type Function uint8
+
+const (
+	// BLAKE3 is the default function, used by New.
+	BLAKE3 Function = iota
+	// SHA256 uses SHA-256, expanded in counter mode to produce outputs of arbitrary length.
+	SHA256
+	// SHAKE256 uses the extendable output function of SHA-3.
+	SHAKE256
+)
+
+var functionNames = [...]string{"BLAKE3", "SHA-256", "SHAKE256"}
+
+// String implements fmt.Stringer.
+func (f Function) String() string {
+	if f.Valid() {
+		return functionNames[f]
+	}
+	return fmt.Sprintf("Function(%d)", uint8(f))
+}
+
+// Valid returns true if f is one of the supported functions.
+func (f Function) Valid() bool {
+	return int(f) < len(functionNames)
+}
+
+// hasher is the state of one of the supported functions.
+type hasher interface {
+	io.Writer
+	io.StringWriter
+	// clone returns an independent copy of the state.
+	clone() hasher
+	// digest returns a stream of output bytes, without modifying the state.
+	digest() io.Reader
+}
+
+func newHasher(f Function) hasher {
+	switch f {
+	case SHA256:
+		return &sha256Hasher{sha256.New()}
+	case SHAKE256:
+		return &shakeHasher{sha3.NewShake256()}
+	default:
+		return &blake3Hasher{blake3.New()}
+	}
+}
+
+type blake3Hasher struct {
+	*blake3.Hasher
+}
+
+func (h *blake3Hasher) clone() hasher     { return &blake3Hasher{h.Hasher.Clone()} }
+func (h *blake3Hasher) digest() io.Reader { return h.Hasher.Digest() }
+
+type shakeHasher struct {
+	sha3.ShakeHash
+}
+
+func (h *shakeHasher) WriteString(s string) (int, error) { return h.ShakeHash.Write([]byte(s)) }
+func (h *shakeHasher) clone() hasher                     { return &shakeHasher{h.ShakeHash.Clone()} }
+
+// digest reads from a copy, since reading from a ShakeHash prevents further writes.
+func (h *shakeHasher) digest() io.Reader { return h.ShakeHash.Clone() }
+
+type sha256Hasher struct {
+	stdhash.Hash
+}
+
+func (h *sha256Hasher) WriteString(s string) (int, error) { return h.Hash.Write([]byte(s)) }
+
+func (h *sha256Hasher) clone() hasher {
+	state, err := h.Hash.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		panic(fmt.Sprintf("hash: failed to clone SHA-256 state: %v", err))
+	}
+	cloned := sha256.New()
+	if err = cloned.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		panic(fmt.Sprintf("hash: failed to clone SHA-256 state: %v", err))
+	}
+	return &sha256Hasher{cloned}
+}
+
+// digest returns the blocks SHA-256(seed ‖ i) for i = 0, 1, …, where seed is the hash of the current state.
+func (h *sha256Hasher) digest() io.Reader {
+	return &counterReader{seed: h.Hash.Sum(nil)}
+}
+
+type counterReader struct {
+	seed    []byte
+	counter uint64
+	block   []byte
+}
+
+func (r *counterReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.block) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], r.counter)
+			r.counter++
+			block := sha256.Sum256(append(append([]byte{}, r.seed...), counter[:]...))
+			r.block = block[:]
+		}
+		copied := copy(p[n:], r.block)
+		r.block = r.block[copied:]
+		n += copied
+	}
+	return n, nil
+}
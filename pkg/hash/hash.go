@@ -11,35 +11,56 @@ import (
 	"reflect"
 
 	"github.com/taurusgroup/multi-party-sig/internal/params"
-	"github.com/zeebo/blake3"
 )
 
 const DigestLengthBytes = params.SecBytes * 2 // 64
 
 // Hash is the hash function we use for generating commitments, consuming CMP types, etc.
 //
-// Internally, this is a wrapper around sha3.ShakeHash, but any hash function with
-// an easily extendable output would work as well.
+// Internally, this is a wrapper around BLAKE3 by default, but any hash function with
+// an easily extendable output would work as well, see Function.
 type Hash struct {
-	h *blake3.Hasher
+	f Function
+	h hasher
 }
 
 // New creates a Hash struct where the internal hash function is initialized with "CMP-BLAKE".
 func New(initialData ...WriterToWithDomain) *Hash {
-	hash := &Hash{h: blake3.New()}
-	_, _ = hash.h.WriteString("CMP-BLAKE")
+	return NewWithFunction(BLAKE3, initialData...)
+}
+
+// NewWithFunction creates a Hash using f as the internal hash function.
+//
+// The state is initialized with a string identifying f, so that hashes computed with
+// different functions are never confused with each other.
+// Unknown functions are replaced by BLAKE3.
+func NewWithFunction(f Function, initialData ...WriterToWithDomain) *Hash {
+	if !f.Valid() {
+		f = BLAKE3
+	}
+	hash := &Hash{f: f, h: newHasher(f)}
+	if f == BLAKE3 {
+		_, _ = hash.h.WriteString("CMP-BLAKE")
+	} else {
+		_, _ = hash.h.WriteString("CMP-" + f.String())
+	}
 	for _, d := range initialData {
 		_ = hash.WriteAny(d)
 	}
 	return hash
 }
 
+// Function returns the hash function used internally.
+func (hash *Hash) Function() Function {
+	return hash.f
+}
+
 // Digest returns a reader for the current output of the function.
 //
 // This finalizes the current state of the hash, and returns what's
 // essentially a stream of random bytes.
 func (hash *Hash) Digest() io.Reader {
-	return hash.h.Digest()
+	return hash.h.digest()
 }
 
 // Sum returns a slice of length DigestLengthBytes resulting from the current hash state.
@@ -127,7 +148,7 @@ func (hash *Hash) WriteAny(data ...interface{}) error {
 
 // Clone returns a copy of the Hash in its current state.
 func (hash *Hash) Clone() *Hash {
-	return &Hash{h: hash.h.Clone()}
+	return &Hash{f: hash.f, h: hash.h.clone()}
 }
 
 // Fork clones this hash, and then writes some data.
@@ -65,3 +65,27 @@ func TestHash_WriteAny_Collision(t *testing.T) {
 
 	assert.NotEqual(t, h1, h2)
 }
+
+func TestHash_Functions(t *testing.T) {
+	sums := map[string]bool{}
+	for _, f := range []Function{BLAKE3, SHA256, SHAKE256} {
+		h := NewWithFunction(f)
+		assert.Equal(t, f, h.Function())
+		assert.NoError(t, h.WriteAny([]byte{1, 2, 3}))
+
+		cloned := h.Clone()
+		sum := h.Sum()
+		assert.Len(t, sum, DigestLengthBytes)
+		assert.Equal(t, sum, h.Sum(), "%s: Sum should not modify the state", f)
+		assert.Equal(t, sum, cloned.Sum(), "%s: clone should have the same state", f)
+		assert.Equal(t, f, cloned.Function())
+
+		assert.NoError(t, cloned.WriteAny([]byte{4}))
+		assert.NotEqual(t, sum, cloned.Sum(), "%s: clone should be updated", f)
+		assert.Equal(t, sum, h.Sum(), "%s: updating the clone should not modify the original", f)
+
+		sums[string(sum)] = true
+	}
+	assert.Len(t, sums, 3, "functions should produce different outputs")
+	assert.Equal(t, New().Sum(), NewWithFunction(Function(42)).Sum())
+}
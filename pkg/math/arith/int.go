@@ -61,10 +61,10 @@ func IsInIntervalLPrimeEps(n *saferith.Int) bool {
 	return n.TrueLen() <= params.LPrimePlusEpsilon
 }
 
-// IsInIntervalLEpsPlus1RootN returns true if n ∈ [-2¹⁺ˡ⁺ᵉ√N,…,2¹⁺ˡ⁺ᵉ√N], for a Paillier modulus N.
-func IsInIntervalLEpsPlus1RootN(n *saferith.Int) bool {
+// IsInIntervalLEpsPlus1RootN returns true if n ∈ [-2¹⁺ˡ⁺ᵉ√N,…,2¹⁺ˡ⁺ᵉ√N], for the Paillier modulus N.
+func IsInIntervalLEpsPlus1RootN(n *saferith.Int, N *saferith.Modulus) bool {
 	if n == nil {
 		return false
 	}
-	return n.TrueLen() <= 1+params.LPlusEpsilon+(params.BitsIntModN/2)
+	return n.TrueLen() <= 1+params.LPlusEpsilon+(N.BitLen()/2)
 }
@@ -7,14 +7,18 @@ import (
 
 	"github.com/cronokirby/saferith"
 	"github.com/stretchr/testify/assert"
+	"github.com/taurusgroup/multi-party-sig/internal/params"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 )
 
+// modulusSize is a modulus of the size of a Paillier modulus, to size the sampled values.
+var modulusSize = saferith.ModulusFromNat(new(saferith.Nat).Lsh(new(saferith.Nat).SetUint64(1), params.BitsPaillier-1, -1))
+
 func sampleCoprime(r io.Reader) (*saferith.Nat, *saferith.Nat, *saferith.Modulus) {
-	a := sample.IntervalLEpsN(r).Abs()
+	a := sample.IntervalLEpsN(r, modulusSize).Abs()
 	b := new(saferith.Nat)
 	for b.Coprime(a) != 1 {
-		b = sample.IntervalLEpsN(r).Abs()
+		b = sample.IntervalLEpsN(r, modulusSize).Abs()
 	}
 	cNat := new(saferith.Nat).Mul(a, b, -1)
 	c := saferith.ModulusFromNat(cNat)
@@ -30,7 +34,7 @@ func TestModulus_Exp(t *testing.T) {
 	assert.True(t, cFast.Nat().Eq(cSlow.Nat()) == 1, "n moduli should be the same")
 
 	x := sample.ModN(r, c)
-	e := sample.IntervalLN(r).Abs()
+	e := sample.IntervalLN(r, modulusSize).Abs()
 	eNeg := new(saferith.Int).SetNat(e).Neg(1)
 
 	yExpected := new(saferith.Nat).Exp(x, e, c)
@@ -0,0 +1,249 @@
+package curve
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cronokirby/saferith"
+)
+
+// BLS12381 is the group G₁ of the BLS12-381 pairing friendly curve.
+//
+// Points are encoded in the compressed form of the Zcash serialization format,
+// which is the one used by Ethereum and drand, and decoding rejects points outside of G₁.
+//
+// The curve is only meant for signing with BLS, as in protocols/bls. It can't be used with the ECDSA protocols,
+// and Point.XScalar panics on it.
+type BLS12381 struct{}
+
+func (BLS12381) NewPoint() Point {
+	out := new(BLS12381Point)
+	out.value.SetIdentity()
+	return out
+}
+
+func (BLS12381) NewBasePoint() Point {
+	return &BLS12381Point{value: *bls12381.G1Generator()}
+}
+
+func (BLS12381) NewScalar() Scalar {
+	return new(BLS12381Scalar)
+}
+
+func (BLS12381) ScalarBits() int {
+	return 255
+}
+
+func (BLS12381) SafeScalarBytes() int {
+	return 64
+}
+
+var bls12381OrderNat = new(saferith.Nat).SetBytes(bls12381.Order())
+var bls12381Order = saferith.ModulusFromNat(bls12381OrderNat)
+
+func (BLS12381) Order() *saferith.Modulus {
+	return bls12381Order
+}
+
+func (BLS12381) Name() string {
+	return "bls12-381"
+}
+
+// BLS12381Scalar is a scalar of BLS12-381, which acts on both G₁ and G₂.
+type BLS12381Scalar struct {
+	value bls12381.Scalar
+	// g2 is set for scalars created by BLS12381G2, whose base point lies in G₂.
+	g2 bool
+}
+
+func bls12381CastScalar(generic Scalar) *BLS12381Scalar {
+	out, ok := generic.(*BLS12381Scalar)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to bls12381Scalar: %v", generic))
+	}
+	return out
+}
+
+func (s *BLS12381Scalar) Curve() Curve {
+	if s.g2 {
+		return BLS12381G2{}
+	}
+	return BLS12381{}
+}
+
+func (s *BLS12381Scalar) MarshalBinary() ([]byte, error) {
+	return s.value.MarshalBinary()
+}
+
+func (s *BLS12381Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != bls12381.ScalarSize {
+		return fmt.Errorf("invalid length for bls12381 scalar: %d", len(data))
+	}
+	if err := s.value.UnmarshalBinary(data); err != nil {
+		return errors.New("invalid bytes for bls12381 scalar")
+	}
+	return nil
+}
+
+func (s *BLS12381Scalar) Add(that Scalar) Scalar {
+	other := bls12381CastScalar(that)
+
+	s.value.Add(&s.value, &other.value)
+	return s
+}
+
+func (s *BLS12381Scalar) Sub(that Scalar) Scalar {
+	other := bls12381CastScalar(that)
+
+	s.value.Sub(&s.value, &other.value)
+	return s
+}
+
+func (s *BLS12381Scalar) Mul(that Scalar) Scalar {
+	other := bls12381CastScalar(that)
+
+	s.value.Mul(&s.value, &other.value)
+	return s
+}
+
+func (s *BLS12381Scalar) Invert() Scalar {
+	s.value.Inv(&s.value)
+	return s
+}
+
+func (s *BLS12381Scalar) Negate() Scalar {
+	s.value.Neg()
+	return s
+}
+
+func (s *BLS12381Scalar) IsOverHalfOrder() bool {
+	data, _ := s.value.MarshalBinary()
+	n := new(saferith.Nat).SetBytes(data)
+	half := new(saferith.Nat).Rsh(bls12381OrderNat, 1, -1)
+	gt, _, _ := n.Cmp(half)
+	return gt == 1
+}
+
+func (s *BLS12381Scalar) Equal(that Scalar) bool {
+	other := bls12381CastScalar(that)
+
+	return s.value.IsEqual(&other.value) == 1
+}
+
+func (s *BLS12381Scalar) IsZero() bool {
+	return s.value.IsZero() == 1
+}
+
+func (s *BLS12381Scalar) Set(that Scalar) Scalar {
+	other := bls12381CastScalar(that)
+
+	s.value.Set(&other.value)
+	return s
+}
+
+func (s *BLS12381Scalar) SetNat(x *saferith.Nat) Scalar {
+	reduced := new(saferith.Nat).Mod(x, bls12381Order)
+	s.value.SetBytes(reduced.Bytes())
+	return s
+}
+
+func (s *BLS12381Scalar) Act(that Point) Point {
+	if other, ok := that.(*BLS12381G2Point); ok {
+		out := new(BLS12381G2Point)
+		out.value.ScalarMult(&s.value, &other.value)
+		return out
+	}
+	other := bls12381CastPoint(that)
+	out := new(BLS12381Point)
+	out.value.ScalarMult(&s.value, &other.value)
+	return out
+}
+
+func (s *BLS12381Scalar) ActOnBase() Point {
+	if s.g2 {
+		out := new(BLS12381G2Point)
+		out.value.ScalarMult(&s.value, bls12381.G2Generator())
+		return out
+	}
+	out := new(BLS12381Point)
+	out.value.ScalarMult(&s.value, bls12381.G1Generator())
+	return out
+}
+
+type BLS12381Point struct {
+	value bls12381.G1
+}
+
+func bls12381CastPoint(generic Point) *BLS12381Point {
+	out, ok := generic.(*BLS12381Point)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to bls12381Point: %v", generic))
+	}
+	return out
+}
+
+func (*BLS12381Point) Curve() Curve {
+	return BLS12381{}
+}
+
+func (p *BLS12381Point) MarshalBinary() ([]byte, error) {
+	return p.value.BytesCompressed(), nil
+}
+
+func (p *BLS12381Point) UnmarshalBinary(data []byte) error {
+	if len(data) != bls12381.G1SizeCompressed {
+		return fmt.Errorf("invalid length for bls12381Point: %d", len(data))
+	}
+	// SetBytes checks that the point lies in G₁.
+	if err := p.value.SetBytes(data); err != nil {
+		return fmt.Errorf("bls12381Point.UnmarshalBinary: %w", err)
+	}
+	return nil
+}
+
+func (p *BLS12381Point) Add(that Point) Point {
+	other := bls12381CastPoint(that)
+
+	out := new(BLS12381Point)
+	out.value.Add(&p.value, &other.value)
+	return out
+}
+
+func (p *BLS12381Point) Sub(that Point) Point {
+	other := bls12381CastPoint(that)
+
+	negated := other.value
+	negated.Neg()
+	out := new(BLS12381Point)
+	out.value.Add(&p.value, &negated)
+	return out
+}
+
+func (p *BLS12381Point) Set(that Point) Point {
+	other := bls12381CastPoint(that)
+
+	p.value = other.value
+	return p
+}
+
+func (p *BLS12381Point) Negate() Point {
+	out := &BLS12381Point{value: p.value}
+	out.value.Neg()
+	return out
+}
+
+func (p *BLS12381Point) Equal(that Point) bool {
+	other := bls12381CastPoint(that)
+
+	return p.value.IsEqual(&other.value)
+}
+
+func (p *BLS12381Point) IsIdentity() bool {
+	return p == nil || p.value.IsIdentity()
+}
+
+// XScalar panics, since ECDSA is not defined over BLS12-381, and returning nil would only fail later.
+func (p *BLS12381Point) XScalar() Scalar {
+	panic("curve: XScalar is unsupported on BLS12-381, which is only meant for BLS signatures")
+}
@@ -0,0 +1,133 @@
+package curve
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cronokirby/saferith"
+)
+
+// BLS12381G2 is the group G₂ of the BLS12-381 pairing friendly curve.
+//
+// It shares its scalars with BLS12381, and points are encoded in the compressed
+// form of the Zcash serialization format.
+type BLS12381G2 struct{}
+
+func (BLS12381G2) NewPoint() Point {
+	out := new(BLS12381G2Point)
+	out.value.SetIdentity()
+	return out
+}
+
+func (BLS12381G2) NewBasePoint() Point {
+	return &BLS12381G2Point{value: *bls12381.G2Generator()}
+}
+
+func (BLS12381G2) NewScalar() Scalar {
+	return &BLS12381Scalar{g2: true}
+}
+
+func (BLS12381G2) ScalarBits() int {
+	return 255
+}
+
+func (BLS12381G2) SafeScalarBytes() int {
+	return 64
+}
+
+func (BLS12381G2) Order() *saferith.Modulus {
+	return bls12381Order
+}
+
+func (BLS12381G2) Name() string {
+	return "bls12-381-g2"
+}
+
+// HashToPoint uses the BLS12381G2_XMD:SHA-256_SSWU_RO_ suite of RFC 9380.
+func (BLS12381G2) HashToPoint(msg, dst []byte) Point {
+	out := new(BLS12381G2Point)
+	out.value.Hash(msg, dst)
+	return out
+}
+
+func (c BLS12381G2) HashToScalar(msg, dst []byte) Scalar {
+	return hashToScalar(c, sha256.New, msg, dst)
+}
+
+type BLS12381G2Point struct {
+	value bls12381.G2
+}
+
+func bls12381G2CastPoint(generic Point) *BLS12381G2Point {
+	out, ok := generic.(*BLS12381G2Point)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to bls12381G2Point: %v", generic))
+	}
+	return out
+}
+
+func (*BLS12381G2Point) Curve() Curve {
+	return BLS12381G2{}
+}
+
+func (p *BLS12381G2Point) MarshalBinary() ([]byte, error) {
+	return p.value.BytesCompressed(), nil
+}
+
+func (p *BLS12381G2Point) UnmarshalBinary(data []byte) error {
+	if len(data) != bls12381.G2SizeCompressed {
+		return fmt.Errorf("invalid length for bls12381G2Point: %d", len(data))
+	}
+	// SetBytes checks that the point lies in G₂.
+	if err := p.value.SetBytes(data); err != nil {
+		return fmt.Errorf("bls12381G2Point.UnmarshalBinary: %w", err)
+	}
+	return nil
+}
+
+func (p *BLS12381G2Point) Add(that Point) Point {
+	other := bls12381G2CastPoint(that)
+
+	out := new(BLS12381G2Point)
+	out.value.Add(&p.value, &other.value)
+	return out
+}
+
+func (p *BLS12381G2Point) Sub(that Point) Point {
+	other := bls12381G2CastPoint(that)
+
+	negated := other.value
+	negated.Neg()
+	out := new(BLS12381G2Point)
+	out.value.Add(&p.value, &negated)
+	return out
+}
+
+func (p *BLS12381G2Point) Set(that Point) Point {
+	other := bls12381G2CastPoint(that)
+
+	p.value = other.value
+	return p
+}
+
+func (p *BLS12381G2Point) Negate() Point {
+	out := &BLS12381G2Point{value: p.value}
+	out.value.Neg()
+	return out
+}
+
+func (p *BLS12381G2Point) Equal(that Point) bool {
+	other := bls12381G2CastPoint(that)
+
+	return p.value.IsEqual(&other.value)
+}
+
+func (p *BLS12381G2Point) IsIdentity() bool {
+	return p == nil || p.value.IsIdentity()
+}
+
+// XScalar panics, since ECDSA is not defined over BLS12-381, and returning nil would only fail later.
+func (p *BLS12381G2Point) XScalar() Scalar {
+	panic("curve: XScalar is unsupported on BLS12-381, which is only meant for BLS signatures")
+}
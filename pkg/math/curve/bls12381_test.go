@@ -0,0 +1,57 @@
+package curve_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+func TestBLS12381(t *testing.T) {
+	group := curve.BLS12381{}
+	testGroup(t, group)
+
+	// encodings which do not correspond to a point of G₁ must be rejected
+	notInG1 := make([]byte, 48)
+	notInG1[0] = 0x80
+	notInG1[47] = 0x04
+	assert.Error(t, group.NewPoint().UnmarshalBinary(notInG1))
+
+	// ECDSA is not defined on this curve
+	assert.Panics(t, func() { group.NewBasePoint().XScalar() })
+	assert.Panics(t, func() { group.G2().NewBasePoint().XScalar() })
+}
+
+func TestBLS12381G2(t *testing.T) {
+	testGroup(t, curve.BLS12381G2{})
+}
+
+func TestBLS12381Pairing(t *testing.T) {
+	var group curve.Pairing = curve.BLS12381{}
+	a := sample.Scalar(rand.Reader, group)
+	b := sample.Scalar(rand.Reader, group.G2())
+	G1 := group.NewBasePoint()
+	G2 := group.G2().NewBasePoint()
+
+	// e(a•G₁, b•G₂) = e(G₁, G₂)ᵃᵇ
+	base := group.Pair(G1, G2)
+	assert.False(t, base.IsIdentity())
+	ab := group.NewScalar().Set(a).Mul(b)
+	assert.True(t, group.Pair(a.ActOnBase(), b.ActOnBase()).Equal(base.Exp(ab)))
+	assert.True(t, group.Pair(a.ActOnBase(), G2).Equal(group.Pair(G1, a.Act(G2))))
+
+	// e(a•G₁, G₂)⋅e(-G₁, a•G₂) = 1
+	product := group.MultiPair([]curve.Point{a.ActOnBase(), G1.Negate()}, []curve.Point{G2, a.Act(G2)})
+	assert.True(t, product.IsIdentity())
+	assert.True(t, group.MultiPair([]curve.Point{G1, G1}, []curve.Point{G2, G2}).Equal(base.Mul(base)))
+
+	data, err := base.MarshalBinary()
+	require.NoError(t, err)
+	decoded := group.NewGT()
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.True(t, decoded.Equal(base))
+	assert.True(t, group.NewGT().IsIdentity())
+}
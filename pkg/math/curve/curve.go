@@ -2,6 +2,7 @@ package curve
 
 import (
 	"encoding"
+	"fmt"
 
 	"github.com/cronokirby/saferith"
 )
@@ -33,6 +34,13 @@ type Curve interface {
 	SafeScalarBytes() int
 	// Order returns a Modulus holding order of this group.
 	Order() *saferith.Modulus
+	// HashToPoint maps msg to a point, using the random oracle hash_to_curve encoding of RFC 9380
+	// with the domain separation tag dst.
+	//
+	// This point has no known discrete logarithm.
+	HashToPoint(msg, dst []byte) Point
+	// HashToScalar maps msg to a scalar, using hash_to_field from RFC 9380 with the order of the group as modulus.
+	HashToScalar(msg, dst []byte) Scalar
 }
 
 // Scalar represents a number modulo the order of some Elliptic Curve group.
@@ -173,3 +181,15 @@ func FromHash(group Curve, h []byte) Scalar {
 	}
 	return group.NewScalar().SetNat(s)
 }
+
+// FromName returns the Curve whose Name matches name.
+//
+// This is useful when unmarshalling a structure which stores the name of the group it uses.
+func FromName(name string) (Curve, error) {
+	for _, group := range []Curve{Secp256k1{}, Edwards25519{}, BLS12381{}, BLS12381G2{}, P256{}} {
+		if group.Name() == name {
+			return group, nil
+		}
+	}
+	return nil, fmt.Errorf("curve: unknown curve %q", name)
+}
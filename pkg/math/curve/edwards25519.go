@@ -0,0 +1,247 @@
+package curve
+
+import (
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"github.com/cronokirby/saferith"
+)
+
+// Edwards25519 is the prime order subgroup of the twisted Edwards curve birationally equivalent to Curve25519.
+//
+// Points are encoded as in RFC 8032, and decoding rejects any point outside of the prime order subgroup.
+// Scalars are encoded as 32 Big Endian bytes, like the other curves in this package.
+type Edwards25519 struct{}
+
+func (Edwards25519) NewPoint() Point {
+	return &Edwards25519Point{value: *edwards25519.NewIdentityPoint()}
+}
+
+func (Edwards25519) NewBasePoint() Point {
+	return &Edwards25519Point{value: *edwards25519.NewGeneratorPoint()}
+}
+
+func (Edwards25519) NewScalar() Scalar {
+	return &Edwards25519Scalar{value: *edwards25519.NewScalar()}
+}
+
+func (Edwards25519) ScalarBits() int {
+	return 253
+}
+
+func (Edwards25519) SafeScalarBytes() int {
+	return 64
+}
+
+var edwards25519OrderNat, _ = new(saferith.Nat).SetHex("1000000000000000000000000000000014DEF9DEA2F79CD65812631A5CF5D3ED")
+var edwards25519Order = saferith.ModulusFromNat(edwards25519OrderNat)
+
+func (Edwards25519) Order() *saferith.Modulus {
+	return edwards25519Order
+}
+
+func (Edwards25519) Name() string {
+	return "edwards25519"
+}
+
+type Edwards25519Scalar struct {
+	value edwards25519.Scalar
+}
+
+func edwards25519CastScalar(generic Scalar) *Edwards25519Scalar {
+	out, ok := generic.(*Edwards25519Scalar)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to edwards25519Scalar: %v", generic))
+	}
+	return out
+}
+
+// reverse returns a reversed copy of data, used to convert between Little and Big Endian.
+func reverse(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i := range data {
+		out[len(data)-1-i] = data[i]
+	}
+	return out
+}
+
+func (*Edwards25519Scalar) Curve() Curve {
+	return Edwards25519{}
+}
+
+func (s *Edwards25519Scalar) MarshalBinary() ([]byte, error) {
+	return reverse(s.value.Bytes()), nil
+}
+
+func (s *Edwards25519Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid length for edwards25519 scalar: %d", len(data))
+	}
+	if _, err := s.value.SetCanonicalBytes(reverse(data)); err != nil {
+		return errors.New("invalid bytes for edwards25519 scalar")
+	}
+	return nil
+}
+
+func (s *Edwards25519Scalar) Add(that Scalar) Scalar {
+	other := edwards25519CastScalar(that)
+
+	s.value.Add(&s.value, &other.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) Sub(that Scalar) Scalar {
+	other := edwards25519CastScalar(that)
+
+	s.value.Subtract(&s.value, &other.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) Mul(that Scalar) Scalar {
+	other := edwards25519CastScalar(that)
+
+	s.value.Multiply(&s.value, &other.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) Invert() Scalar {
+	s.value.Invert(&s.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) Negate() Scalar {
+	s.value.Negate(&s.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) IsOverHalfOrder() bool {
+	n := new(saferith.Nat).SetBytes(reverse(s.value.Bytes()))
+	half := new(saferith.Nat).Rsh(edwards25519OrderNat, 1, -1)
+	gt, _, _ := n.Cmp(half)
+	return gt == 1
+}
+
+func (s *Edwards25519Scalar) Equal(that Scalar) bool {
+	other := edwards25519CastScalar(that)
+
+	return s.value.Equal(&other.value) == 1
+}
+
+func (s *Edwards25519Scalar) IsZero() bool {
+	return s.value.Equal(edwards25519.NewScalar()) == 1
+}
+
+func (s *Edwards25519Scalar) Set(that Scalar) Scalar {
+	other := edwards25519CastScalar(that)
+
+	s.value.Set(&other.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) SetNat(x *saferith.Nat) Scalar {
+	reduced := new(saferith.Nat).Mod(x, edwards25519Order)
+	data := make([]byte, 32)
+	reduced.FillBytes(data)
+	_, _ = s.value.SetCanonicalBytes(reverse(data))
+	return s
+}
+
+func (s *Edwards25519Scalar) Act(that Point) Point {
+	other := edwards25519CastPoint(that)
+	out := new(Edwards25519Point)
+	out.value.ScalarMult(&s.value, &other.value)
+	return out
+}
+
+func (s *Edwards25519Scalar) ActOnBase() Point {
+	out := new(Edwards25519Point)
+	out.value.ScalarBaseMult(&s.value)
+	return out
+}
+
+type Edwards25519Point struct {
+	value edwards25519.Point
+}
+
+func edwards25519CastPoint(generic Point) *Edwards25519Point {
+	out, ok := generic.(*Edwards25519Point)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to edwards25519Point: %v", generic))
+	}
+	return out
+}
+
+func (*Edwards25519Point) Curve() Curve {
+	return Edwards25519{}
+}
+
+func (p *Edwards25519Point) MarshalBinary() ([]byte, error) {
+	return p.value.Bytes(), nil
+}
+
+func (p *Edwards25519Point) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid length for edwards25519Point: %d", len(data))
+	}
+	if _, err := p.value.SetBytes(data); err != nil {
+		return fmt.Errorf("edwards25519Point.UnmarshalBinary: %w", err)
+	}
+	// [ℓ]P = [ℓ-1]P + P must be the identity, otherwise P has a small order component.
+	minusOne := edwards25519.NewScalar().Negate(scalarOne)
+	check := new(edwards25519.Point).ScalarMult(minusOne, &p.value)
+	check.Add(check, &p.value)
+	if check.Equal(edwards25519.NewIdentityPoint()) != 1 {
+		return errors.New("edwards25519Point.UnmarshalBinary: point not in prime order subgroup")
+	}
+	return nil
+}
+
+var scalarOne, _ = edwards25519.NewScalar().SetCanonicalBytes([]byte{
+	1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+})
+
+func (p *Edwards25519Point) Add(that Point) Point {
+	other := edwards25519CastPoint(that)
+
+	out := new(Edwards25519Point)
+	out.value.Add(&p.value, &other.value)
+	return out
+}
+
+func (p *Edwards25519Point) Sub(that Point) Point {
+	other := edwards25519CastPoint(that)
+
+	out := new(Edwards25519Point)
+	out.value.Subtract(&p.value, &other.value)
+	return out
+}
+
+func (p *Edwards25519Point) Set(that Point) Point {
+	other := edwards25519CastPoint(that)
+
+	p.value.Set(&other.value)
+	return p
+}
+
+func (p *Edwards25519Point) Negate() Point {
+	out := new(Edwards25519Point)
+	out.value.Negate(&p.value)
+	return out
+}
+
+func (p *Edwards25519Point) Equal(that Point) bool {
+	other := edwards25519CastPoint(that)
+
+	return p.value.Equal(&other.value) == 1
+}
+
+func (p *Edwards25519Point) IsIdentity() bool {
+	return p == nil || p.value.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// XScalar is not supported on edwards25519, since ECDSA is not defined over this curve.
+func (p *Edwards25519Point) XScalar() Scalar {
+	return nil
+}
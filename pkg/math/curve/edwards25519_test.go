@@ -0,0 +1,67 @@
+package curve_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+func TestEdwards25519(t *testing.T) {
+	group := curve.Edwards25519{}
+	testGroup(t, group)
+
+	// a point of order 2 must be rejected
+	lowOrder := make([]byte, 32)
+	lowOrder[0] = 0xec
+	for i := 1; i < 31; i++ {
+		lowOrder[i] = 0xff
+	}
+	lowOrder[31] = 0x7f
+	assert.Error(t, group.NewPoint().UnmarshalBinary(lowOrder))
+}
+
+// testGroup checks the basic arithmetic and encoding properties any curve.Curve must satisfy.
+func testGroup(t *testing.T, group curve.Curve) {
+	a, A := sample.ScalarPointPair(rand.Reader, group)
+	b, B := sample.ScalarPointPair(rand.Reader, group)
+
+	// (a+b)⋅G = A+B
+	sum := group.NewScalar().Set(a).Add(b)
+	assert.True(t, sum.ActOnBase().Equal(A.Add(B)))
+	// (a⋅b)⋅G = a⋅B
+	assert.True(t, group.NewScalar().Set(a).Mul(b).ActOnBase().Equal(a.Act(B)))
+	// a⋅a⁻¹ = 1
+	one := group.NewScalar().Set(a).Invert().Mul(a)
+	assert.True(t, one.ActOnBase().Equal(group.NewBasePoint()))
+	assert.True(t, A.Sub(A).IsIdentity())
+
+	// scalars are Big Endian, so MakeInt and SetNat must round trip
+	aNat := curve.MakeInt(a).Abs()
+	assert.True(t, group.NewScalar().SetNat(aNat).Equal(a))
+
+	data, err := a.MarshalBinary()
+	require.NoError(t, err)
+	a2 := group.NewScalar()
+	require.NoError(t, a2.UnmarshalBinary(data))
+	assert.True(t, a.Equal(a2))
+
+	data, err = A.MarshalBinary()
+	require.NoError(t, err)
+	A2 := group.NewPoint()
+	require.NoError(t, A2.UnmarshalBinary(data))
+	assert.True(t, A.Equal(A2))
+
+	data, err = group.NewPoint().MarshalBinary()
+	require.NoError(t, err)
+	identity := group.NewBasePoint()
+	require.NoError(t, identity.UnmarshalBinary(data))
+	assert.True(t, identity.IsIdentity())
+
+	g, err := curve.FromName(group.Name())
+	require.NoError(t, err)
+	assert.Equal(t, group, g)
+}
@@ -0,0 +1,310 @@
+package curve
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+
+	"filippo.io/edwards25519"
+	"filippo.io/edwards25519/field"
+	"github.com/cronokirby/saferith"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// This file implements the hash_to_curve encodings of RFC 9380, using the following suites:
+//
+//   - secp256k1_XMD:SHA-256_SSWU_RO_
+//   - edwards25519_XMD:SHA-512_ELL2_RO_
+//   - BLS12381G1_XMD:SHA-256_SSWU_RO_
+//   - P256_XMD:SHA-256_SSWU_RO_
+//
+// HashToScalar uses hash_to_field with the order of the group as modulus,
+// with the same expansion function as the suite of the curve.
+
+// hashToFieldBytes is the number of bytes L used to sample each field element,
+// that is ceil((ceil(log2(p)) + k) / 8), with k = 128, for all of our curves.
+const hashToFieldBytes = 48
+
+// expandMessageXMD implements expand_message_xmd from RFC 9380, Section 5.3.1.
+//
+// It panics if length is larger than 255 times the output size of the hash function.
+func expandMessageXMD(newHash func() hash.Hash, msg, dst []byte, length int) []byte {
+	h := newHash()
+	bInBytes := h.Size()
+	ell := (length + bInBytes - 1) / bInBytes
+	if ell > 255 || length > 65535 {
+		panic("curve: expand_message_xmd: requested length too large")
+	}
+	if len(dst) > 255 {
+		_, _ = h.Write([]byte("H2C-OVERSIZE-DST-"))
+		_, _ = h.Write(dst)
+		dst = h.Sum(nil)
+		h.Reset()
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	// b₀ = H(Z_pad ‖ msg ‖ l_i_b_str ‖ 0 ‖ DST_prime)
+	_, _ = h.Write(make([]byte, h.BlockSize()))
+	_, _ = h.Write(msg)
+	_, _ = h.Write([]byte{byte(length >> 8), byte(length), 0})
+	_, _ = h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// bᵢ = H((b₀ ⊕ bᵢ₋₁) ‖ i ‖ DST_prime)
+	out := make([]byte, 0, ell*bInBytes)
+	previous := make([]byte, bInBytes)
+	for i := 1; i <= ell; i++ {
+		h.Reset()
+		for j := range previous {
+			previous[j] ^= b0[j]
+		}
+		_, _ = h.Write(previous)
+		_, _ = h.Write([]byte{byte(i)})
+		_, _ = h.Write(dstPrime)
+		previous = h.Sum(nil)
+		out = append(out, previous...)
+	}
+	return out[:length]
+}
+
+// hashToField implements hash_to_field from RFC 9380, Section 5.2, for a prime field of the given modulus.
+func hashToField(newHash func() hash.Hash, msg, dst []byte, count int, modulus *saferith.Modulus) []*saferith.Nat {
+	uniform := expandMessageXMD(newHash, msg, dst, count*hashToFieldBytes)
+	out := make([]*saferith.Nat, count)
+	for i := range out {
+		chunk := uniform[i*hashToFieldBytes : (i+1)*hashToFieldBytes]
+		out[i] = new(saferith.Nat).SetBytes(chunk)
+		out[i].Mod(out[i], modulus)
+	}
+	return out
+}
+
+// hashToScalar hashes msg to a scalar of group, using the given expansion.
+func hashToScalar(group Curve, newHash func() hash.Hash, msg, dst []byte) Scalar {
+	u := hashToField(newHash, msg, dst, 1, group.Order())
+	return group.NewScalar().SetNat(u[0])
+}
+
+func (c Secp256k1) HashToScalar(msg, dst []byte) Scalar {
+	return hashToScalar(c, sha256.New, msg, dst)
+}
+
+func (c Edwards25519) HashToScalar(msg, dst []byte) Scalar {
+	return hashToScalar(c, sha512.New, msg, dst)
+}
+
+func (c BLS12381) HashToScalar(msg, dst []byte) Scalar {
+	return hashToScalar(c, sha256.New, msg, dst)
+}
+
+func (BLS12381) HashToPoint(msg, dst []byte) Point {
+	out := new(BLS12381Point)
+	out.value.Hash(msg, dst)
+	return out
+}
+
+var secp256k1FieldModulus = saferith.ModulusFromNat(
+	new(saferith.Nat).SetBytes(mustDecodeHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f")),
+)
+
+func mustDecodeHex(s string) []byte {
+	out, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func secp256k1FieldFromHex(s string) *secp256k1.FieldVal {
+	out := new(secp256k1.FieldVal)
+	out.SetByteSlice(mustDecodeHex(s))
+	return out
+}
+
+// Constants for the simplified SWU map to the curve E': y² = x³ + A'x + B', which is 3-isogenous to secp256k1.
+// See RFC 9380, Section 8.7 and Appendix E.1.
+var (
+	secp256k1IsoA = secp256k1FieldFromHex("3f8731abdd661adca08a5558f0f5d272e953d363cb6f0e5d405447c01a444533")
+	secp256k1IsoB = new(secp256k1.FieldVal).SetInt(1771)
+	// Z = -11
+	secp256k1SSWUZ = new(secp256k1.FieldVal).SetInt(11).Negate(1).Normalize()
+
+	secp256k1IsoXNum = []*secp256k1.FieldVal{
+		secp256k1FieldFromHex("8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa8c7"),
+		secp256k1FieldFromHex("07d3d4c80bc321d5b9f315cea7fd44c5d595d2fc0bf63b92dfff1044f17c6581"),
+		secp256k1FieldFromHex("534c328d23f234e6e2a413deca25caece4506144037c40314ecbd0b53d9dd262"),
+		secp256k1FieldFromHex("8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa88c"),
+	}
+	secp256k1IsoXDen = []*secp256k1.FieldVal{
+		secp256k1FieldFromHex("d35771193d94918a9ca34ccbb7b640dd86cd409542f8487d9fe6b745781eb49b"),
+		secp256k1FieldFromHex("edadc6f64383dc1df7c4b2d51b54225406d36b641f5e41bbc52a56612a8c6d14"),
+		new(secp256k1.FieldVal).SetInt(1),
+	}
+	secp256k1IsoYNum = []*secp256k1.FieldVal{
+		secp256k1FieldFromHex("4bda12f684bda12f684bda12f684bda12f684bda12f684bda12f684b8e38e23c"),
+		secp256k1FieldFromHex("c75e0c32d5cb7c0fa9d0a54b12a0a6d5647ab046d686da6fdffc90fc201d71a3"),
+		secp256k1FieldFromHex("29a6194691f91a73715209ef6512e576722830a201be2018a765e85a9ecee931"),
+		secp256k1FieldFromHex("2f684bda12f684bda12f684bda12f684bda12f684bda12f684bda12f38e38d84"),
+	}
+	secp256k1IsoYDen = []*secp256k1.FieldVal{
+		secp256k1FieldFromHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffff93b"),
+		secp256k1FieldFromHex("7a06534bb8bdb49fd5e9e6632722c2989467c1bfc8e8d978dfb425d2685c2573"),
+		secp256k1FieldFromHex("6484aa716545ca2cf3a70c3fa8fe337e0a3d21162f0d6299a7bf8192bfd2a76f"),
+		new(secp256k1.FieldVal).SetInt(1),
+	}
+)
+
+// secp256k1Polynomial evaluates ∑ᵢ coefficients[i]⋅xⁱ.
+func secp256k1Polynomial(coefficients []*secp256k1.FieldVal, x *secp256k1.FieldVal) *secp256k1.FieldVal {
+	out := new(secp256k1.FieldVal)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		out.Mul(x).Add(coefficients[i]).Normalize()
+	}
+	return out
+}
+
+// secp256k1CurveRHS computes x³ + A'x + B'.
+func secp256k1CurveRHS(x *secp256k1.FieldVal) *secp256k1.FieldVal {
+	out := new(secp256k1.FieldVal).SquareVal(x).Mul(x)
+	ax := new(secp256k1.FieldVal).Mul2(secp256k1IsoA, x)
+	return out.Add(ax).Add(secp256k1IsoB).Normalize()
+}
+
+// secp256k1MapToCurve implements the simplified SWU map of RFC 9380, Section 6.6.2, followed by the 3-isogeny to secp256k1.
+func secp256k1MapToCurve(u *secp256k1.FieldVal) secp256k1.JacobianPoint {
+	// tv1 = inv0(Z²⋅u⁴ + Z⋅u²)
+	zu2 := new(secp256k1.FieldVal).SquareVal(u).Mul(secp256k1SSWUZ).Normalize()
+	tv1 := new(secp256k1.FieldVal).SquareVal(zu2).Add(zu2).Normalize()
+	tv1.Inverse().Normalize()
+
+	// x1 = (-B / A)⋅(1 + tv1), or B / (Z⋅A) if tv1 = 0
+	x1 := new(secp256k1.FieldVal)
+	invA := new(secp256k1.FieldVal).Set(secp256k1IsoA).Inverse()
+	if tv1.IsZero() {
+		x1.Mul2(secp256k1SSWUZ, secp256k1IsoA).Inverse().Mul(secp256k1IsoB)
+	} else {
+		x1.Set(tv1).AddInt(1).Mul(secp256k1IsoB).Mul(invA).Negate(1)
+	}
+	x1.Normalize()
+	gx1 := secp256k1CurveRHS(x1)
+
+	// x2 = Z⋅u²⋅x1
+	x2 := new(secp256k1.FieldVal).Mul2(zu2, x1).Normalize()
+	gx2 := secp256k1CurveRHS(x2)
+
+	x, y := new(secp256k1.FieldVal), new(secp256k1.FieldVal)
+	if y.SquareRootVal(gx1) {
+		x.Set(x1)
+	} else {
+		x.Set(x2)
+		y.SquareRootVal(gx2)
+	}
+	y.Normalize()
+	if u.IsOdd() != y.IsOdd() {
+		y.Negate(1).Normalize()
+	}
+
+	// (x, y) ↦ (x_num / x_den, y⋅y_num / y_den)
+	xDen := secp256k1Polynomial(secp256k1IsoXDen, x)
+	yDen := secp256k1Polynomial(secp256k1IsoYDen, x)
+	var out secp256k1.JacobianPoint
+	if xDen.IsZero() || yDen.IsZero() {
+		return out
+	}
+	out.X.Set(xDen).Inverse().Mul(secp256k1Polynomial(secp256k1IsoXNum, x)).Normalize()
+	out.Y.Set(yDen).Inverse().Mul(secp256k1Polynomial(secp256k1IsoYNum, x)).Mul(y).Normalize()
+	out.Z.SetInt(1)
+	return out
+}
+
+func (Secp256k1) HashToPoint(msg, dst []byte) Point {
+	u := hashToField(sha256.New, msg, dst, 2, secp256k1FieldModulus)
+	var u0, u1 secp256k1.FieldVal
+	u0.SetByteSlice(u[0].Bytes())
+	u1.SetByteSlice(u[1].Bytes())
+	q0 := secp256k1MapToCurve(&u0)
+	q1 := secp256k1MapToCurve(&u1)
+
+	out := new(Secp256k1Point)
+	secp256k1.AddNonConst(&q0, &q1, &out.value)
+	return out
+}
+
+var edwards25519FieldModulus = saferith.ModulusFromNat(
+	new(saferith.Nat).SetBytes(mustDecodeHex("7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffed")),
+)
+
+var (
+	edwards25519One = new(field.Element).One()
+	// J = 486662 is the A coefficient of Curve25519.
+	edwards25519J = new(field.Element).Mult32(edwards25519One, 486662)
+	// edwards25519SqrtNeg486664 = sqrt(-486664), used by the rational map from Curve25519 to edwards25519.
+	edwards25519SqrtNeg486664, _ = new(field.Element).SqrtRatio(
+		new(field.Element).Negate(new(field.Element).Mult32(edwards25519One, 486664)),
+		edwards25519One,
+	)
+)
+
+// edwards25519MapToCurve implements the Elligator 2 map to Curve25519 of RFC 9380, Section 6.7.1,
+// followed by the rational map to edwards25519 of Section 6.8.2.
+func edwards25519MapToCurve(u *field.Element) *edwards25519.Point {
+	zero := new(field.Element)
+	negJ := new(field.Element).Negate(edwards25519J)
+
+	// x1 = -J / (1 + Z⋅u²), with Z = 2, or -J if the denominator is 0
+	tv1 := new(field.Element).Square(u)
+	tv1.Add(tv1, tv1).Add(tv1, edwards25519One)
+	x1 := new(field.Element).Invert(tv1)
+	x1.Multiply(x1, negJ)
+	x1.Select(negJ, x1, x1.Equal(zero))
+
+	// gx = x⋅(x⋅(x + J) + 1)
+	curve25519RHS := func(x *field.Element) *field.Element {
+		out := new(field.Element).Add(x, edwards25519J)
+		out.Multiply(out, x).Add(out, edwards25519One)
+		return out.Multiply(out, x)
+	}
+	gx1 := curve25519RHS(x1)
+	// x2 = -x1 - J
+	x2 := new(field.Element).Subtract(negJ, x1)
+	gx2 := curve25519RHS(x2)
+
+	// SqrtRatio returns the non negative root, with sgn0 = 0.
+	y1, isSquare := new(field.Element).SqrtRatio(gx1, edwards25519One)
+	y1.Negate(y1)
+	y2, _ := new(field.Element).SqrtRatio(gx2, edwards25519One)
+	s := new(field.Element).Select(x1, x2, isSquare)
+	t := new(field.Element).Select(y1, y2, isSquare)
+
+	// (s, t) ↦ (sqrt(-486664)⋅s / t, (s - 1) / (s + 1)), or the identity if a denominator is 0
+	sPlusOne := new(field.Element).Add(s, edwards25519One)
+	exceptional := t.Equal(zero) | sPlusOne.Equal(zero)
+	x := new(field.Element).Invert(t)
+	x.Multiply(x, s).Multiply(x, edwards25519SqrtNeg486664)
+	y := new(field.Element).Invert(sPlusOne)
+	y.Multiply(y, new(field.Element).Subtract(s, edwards25519One))
+	x.Select(zero, x, exceptional)
+	y.Select(edwards25519One, y, exceptional)
+
+	out, err := new(edwards25519.Point).SetExtendedCoordinates(x, y, edwards25519One, new(field.Element).Multiply(x, y))
+	if err != nil {
+		panic("curve: elligator 2 produced a point outside of edwards25519")
+	}
+	return out
+}
+
+func (Edwards25519) HashToPoint(msg, dst []byte) Point {
+	u := hashToField(sha512.New, msg, dst, 2, edwards25519FieldModulus)
+	var elements [2]*field.Element
+	for i := range elements {
+		elements[i], _ = new(field.Element).SetBytes(reverse(u[i].FillBytes(make([]byte, 32))))
+	}
+	q0 := edwards25519MapToCurve(elements[0])
+	q1 := edwards25519MapToCurve(elements[1])
+
+	out := new(Edwards25519Point)
+	out.value.Add(q0, q1)
+	out.value.MultByCofactor(&out.value)
+	return out
+}
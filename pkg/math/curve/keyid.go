@@ -0,0 +1,22 @@
+package curve
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// KeyID returns a fingerprint of the public key p, which identifies it in databases and logs.
+//
+// It is the hex encoding of SHA-256(name ‖ 0x00 ‖ encoding), where name is the name of p's curve,
+// and encoding is the output of p.MarshalBinary, which is the compressed SEC 1 encoding for secp256k1 and P-256.
+// Other implementations can compute it from the public key alone, and it doesn't change when the shares
+// of the key are refreshed.
+func KeyID(p Point) string {
+	// the identity can't be marshalled, and is never a valid public key, so its ID only contains the curve.
+	data, _ := p.MarshalBinary()
+	h := sha256.New()
+	_, _ = h.Write([]byte(p.Curve().Name()))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
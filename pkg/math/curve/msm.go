@@ -0,0 +1,248 @@
+package curve
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// msmWindow is the width of the non adjacent forms used by Secp256k1MultiScalarMult.
+const msmWindow = 5
+
+// pippengerThreshold is the number of terms from which Secp256k1MultiScalarMult uses
+// Pippenger's bucket method instead of Straus' interleaved windows.
+const pippengerThreshold = 80
+
+// Secp256k1MultiScalarMult computes Σ scalars[i] * points[i], in variable time.
+//
+// The doublings are shared between all the terms, and the precomputed multiples of the points
+// are normalized together, which makes this much faster than computing each product separately.
+// For many terms, the points are instead accumulated into buckets, following Pippenger.
+// This should only be used with public values, such as when verifying signatures.
+func Secp256k1MultiScalarMult(scalars []*Secp256k1Scalar, points []*Secp256k1Point) *Secp256k1Point {
+	if len(scalars) != len(points) {
+		panic("Secp256k1MultiScalarMult: different number of scalars and points")
+	}
+	if len(points) >= pippengerThreshold {
+		return secp256k1Pippenger(scalars, points)
+	}
+	return NewSecp256k1MultiScalarTable(points).MultiScalarMult(scalars)
+}
+
+// secp256k1Pippenger computes Σ scalars[i] * points[i] with Pippenger's bucket method, using signed digits.
+func secp256k1Pippenger(scalars []*Secp256k1Scalar, points []*Secp256k1Point) *Secp256k1Point {
+	// the width minimizing (256/c)•(n + 2ᶜ), roughly
+	c := bits.Len(uint(len(points))) - 2
+	if c < 4 {
+		c = 4
+	}
+	if c > 16 {
+		c = 16
+	}
+	// enough windows for 256 bit scalars, and for the carry out of the top digit
+	windows := 256/c + 1
+
+	affine := make([]secp256k1.JacobianPoint, len(points))
+	multiples := make([]*secp256k1.JacobianPoint, len(points))
+	for i, p := range points {
+		affine[i].Set(&p.value)
+		multiples[i] = &affine[i]
+	}
+	secp256k1ToAffine(multiples)
+
+	// digits[w][i] is the digit of scalars[i] in window w, in [-2ᶜ⁻¹, 2ᶜ⁻¹]
+	digits := make([][]int32, windows)
+	for w := range digits {
+		digits[w] = make([]int32, len(scalars))
+	}
+	for i, s := range scalars {
+		if points[i].IsIdentity() {
+			continue
+		}
+		limbs := secp256k1Limbs(&s.value)
+		carry := 0
+		for w := 0; w < windows; w++ {
+			d := carry
+			if w*c < 256 {
+				d += limbs.window(w*c, c)
+			}
+			carry = 0
+			if d > 1<<(c-1) {
+				d -= 1 << c
+				carry = 1
+			}
+			digits[w][i] = int32(d)
+		}
+	}
+
+	out := new(Secp256k1Point)
+	buckets := make([]secp256k1.JacobianPoint, 1<<(c-1))
+	var neg, sum, acc secp256k1.JacobianPoint
+	for w := windows - 1; w >= 0; w-- {
+		for k := 0; k < c; k++ {
+			secp256k1.DoubleNonConst(&out.value, &out.value)
+		}
+		for b := range buckets {
+			buckets[b] = secp256k1.JacobianPoint{}
+		}
+		for i, d := range digits[w] {
+			switch {
+			case d > 0:
+				secp256k1.AddNonConst(&buckets[d-1], &affine[i], &buckets[d-1])
+			case d < 0:
+				neg.Set(&affine[i])
+				neg.Y.Negate(1).Normalize()
+				secp256k1.AddNonConst(&buckets[-d-1], &neg, &buckets[-d-1])
+			}
+		}
+		// Σ (b+1)•buckets[b], as the sum of the running sums from the top bucket down
+		sum, acc = secp256k1.JacobianPoint{}, secp256k1.JacobianPoint{}
+		for b := len(buckets) - 1; b >= 0; b-- {
+			secp256k1.AddNonConst(&sum, &buckets[b], &sum)
+			secp256k1.AddNonConst(&acc, &sum, &acc)
+		}
+		secp256k1.AddNonConst(&out.value, &acc, &out.value)
+	}
+	return out
+}
+
+// Secp256k1MultiScalarTable contains the precomputed multiples of a list of points,
+// so that several linear combinations of the same points only pay for the precomputation once.
+type Secp256k1MultiScalarTable struct {
+	// tables[i][j] = (2j+1) * points[i], in affine coordinates
+	tables [][1 << (msmWindow - 2)]secp256k1.JacobianPoint
+	// identity[i] is true if points[i] is the identity, which is left out since it can't be normalized
+	identity []bool
+}
+
+// NewSecp256k1MultiScalarTable precomputes the multiples of points used by Secp256k1MultiScalarMult.
+func NewSecp256k1MultiScalarTable(points []*Secp256k1Point) *Secp256k1MultiScalarTable {
+	t := &Secp256k1MultiScalarTable{
+		tables:   make([][1 << (msmWindow - 2)]secp256k1.JacobianPoint, len(points)),
+		identity: make([]bool, len(points)),
+	}
+	for i, p := range points {
+		if p.IsIdentity() {
+			t.identity[i] = true
+			continue
+		}
+		var double secp256k1.JacobianPoint
+		t.tables[i][0].Set(&p.value)
+		secp256k1.DoubleNonConst(&p.value, &double)
+		for j := 1; j < len(t.tables[i]); j++ {
+			secp256k1.AddNonConst(&t.tables[i][j-1], &double, &t.tables[i][j])
+		}
+	}
+	multiples := make([]*secp256k1.JacobianPoint, 0, len(t.tables)*len(t.tables[0]))
+	for i := range t.tables {
+		for j := range t.tables[i] {
+			multiples = append(multiples, &t.tables[i][j])
+		}
+	}
+	secp256k1ToAffine(multiples)
+	return t
+}
+
+// Len returns the number of points of the table.
+func (t *Secp256k1MultiScalarTable) Len() int {
+	return len(t.tables)
+}
+
+// MultiScalarMult computes Σ scalars[i] * points[i], in variable time, where points are the points of the table.
+func (t *Secp256k1MultiScalarTable) MultiScalarMult(scalars []*Secp256k1Scalar) *Secp256k1Point {
+	if len(scalars) != len(t.tables) {
+		panic("Secp256k1MultiScalarTable: different number of scalars and points")
+	}
+	digits := make([][]int8, len(scalars))
+	length := 0
+	for i, s := range scalars {
+		if t.identity[i] {
+			continue
+		}
+		b := s.value.Bytes()
+		digits[i] = nonAdjacentForm(new(big.Int).SetBytes(b[:]))
+		if len(digits[i]) > length {
+			length = len(digits[i])
+		}
+	}
+
+	out := new(Secp256k1Point)
+	var neg secp256k1.JacobianPoint
+	for k := length - 1; k >= 0; k-- {
+		secp256k1.DoubleNonConst(&out.value, &out.value)
+		for i := range digits {
+			if k >= len(digits[i]) || digits[i][k] == 0 {
+				continue
+			}
+			d := digits[i][k]
+			if d > 0 {
+				secp256k1.AddNonConst(&out.value, &t.tables[i][d/2], &out.value)
+			} else {
+				neg.Set(&t.tables[i][-d/2])
+				neg.Y.Negate(1).Normalize()
+				secp256k1.AddNonConst(&out.value, &neg, &out.value)
+			}
+		}
+	}
+	return out
+}
+
+// secp256k1ToAffine converts all the points to affine coordinates, with a single inversion.
+//
+// Points at infinity are skipped.
+func secp256k1ToAffine(points []*secp256k1.JacobianPoint) {
+	n := len(points)
+	point := func(i int) *secp256k1.JacobianPoint {
+		return points[i]
+	}
+	var one secp256k1.FieldVal
+	one.SetInt(1)
+	z := func(i int) *secp256k1.FieldVal {
+		if point(i).Z.IsZero() {
+			return &one
+		}
+		return &point(i).Z
+	}
+	// prefix[i] = Z₀ ⋯ Zᵢ₋₁
+	prefix := make([]secp256k1.FieldVal, n+1)
+	prefix[0].SetInt(1)
+	for i := 0; i < n; i++ {
+		prefix[i+1].Mul2(&prefix[i], z(i))
+	}
+	var inv, zInv, zInv2 secp256k1.FieldVal
+	inv.Set(&prefix[n]).Inverse()
+	for i := n - 1; i >= 0; i-- {
+		p := point(i)
+		if p.Z.IsZero() {
+			continue
+		}
+		zInv.Mul2(&inv, &prefix[i])
+		inv.Mul(&p.Z)
+		zInv2.SquareVal(&zInv)
+		p.X.Mul(&zInv2).Normalize()
+		p.Y.Mul(zInv2.Mul(&zInv)).Normalize()
+		p.Z.SetInt(1)
+	}
+}
+
+// nonAdjacentForm returns the width msmWindow NAF of k, least significant digit first.
+//
+// Every digit is either 0, or odd and less than 2^(msmWindow-1) in absolute value,
+// and a non zero digit is followed by at least msmWindow-1 zeros.
+func nonAdjacentForm(k *big.Int) []int8 {
+	var digits []int8
+	for k.Sign() > 0 {
+		var d int8
+		if k.Bit(0) == 1 {
+			d = int8(k.Bits()[0] & (1<<msmWindow - 1))
+			if d >= 1<<(msmWindow-1) {
+				d -= 1 << msmWindow
+			}
+			k.Sub(k, big.NewInt(int64(d)))
+		}
+		digits = append(digits, d)
+		k.Rsh(k, 1)
+	}
+	return digits
+}
@@ -0,0 +1,58 @@
+package curve
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func randomTerms(n int) ([]*Secp256k1Scalar, []*Secp256k1Point) {
+	scalars := make([]*Secp256k1Scalar, n)
+	points := make([]*Secp256k1Point, n)
+	for i := range points {
+		var b [32]byte
+		_, _ = rand.Read(b[:])
+		scalars[i] = new(Secp256k1Scalar)
+		scalars[i].value.SetBytes(&b)
+		_, _ = rand.Read(b[:])
+		var k secp256k1.ModNScalar
+		k.SetBytes(&b)
+		points[i] = Secp256k1{}.NewScalar().(*Secp256k1Scalar).Set(&Secp256k1Scalar{k}).ActOnBase().(*Secp256k1Point)
+	}
+	return scalars, points
+}
+
+func TestSecp256k1Pippenger(t *testing.T) {
+	for _, n := range []int{1, 2, 17, 200} {
+		scalars, points := randomTerms(n)
+		// the identity, and a scalar whose digits are all at the border of the windows
+		points[0] = new(Secp256k1Point)
+		var border [32]byte
+		for i := range border {
+			border[i] = 0xff
+		}
+		scalars[n-1].value.SetBytes(&border)
+		expected := NewSecp256k1MultiScalarTable(points).MultiScalarMult(scalars)
+		if !secp256k1Pippenger(scalars, points).Equal(expected) {
+			t.Errorf("%d terms: wrong result", n)
+		}
+	}
+}
+
+func BenchmarkSecp256k1MultiScalarMult(b *testing.B) {
+	for _, n := range []int{16, 64, 256} {
+		scalars, points := randomTerms(n)
+		b.Run(fmt.Sprintf("straus/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				NewSecp256k1MultiScalarTable(points).MultiScalarMult(scalars)
+			}
+		})
+		b.Run(fmt.Sprintf("pippenger/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				secp256k1Pippenger(scalars, points)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package curve_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+func TestSecp256k1MultiScalarMult(t *testing.T) {
+	group := curve.Secp256k1{}
+	for _, n := range []int{0, 1, 2, 7} {
+		scalars := make([]*curve.Secp256k1Scalar, n)
+		points := make([]*curve.Secp256k1Point, n)
+		for i := 0; i < n; i++ {
+			scalars[i] = sample.Scalar(rand.Reader, group).(*curve.Secp256k1Scalar)
+			points[i] = sample.Scalar(rand.Reader, group).ActOnBase().(*curve.Secp256k1Point)
+		}
+		if n > 1 {
+			// a small scalar, and the identity
+			scalars[0] = group.NewScalar().SetNat(new(saferith.Nat).SetUint64(3)).(*curve.Secp256k1Scalar)
+			points[1] = group.NewPoint().(*curve.Secp256k1Point)
+		}
+		expected := group.NewPoint()
+		for i := range points {
+			expected = expected.Add(scalars[i].Act(points[i]))
+		}
+		assert.True(t, curve.Secp256k1MultiScalarMult(scalars, points).Equal(expected), "%d terms", n)
+	}
+}
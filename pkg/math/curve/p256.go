@@ -0,0 +1,234 @@
+package curve
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/cloudflare/circl/group"
+	"github.com/cronokirby/saferith"
+)
+
+// P256 is the NIST P-256 curve, also known as secp256r1.
+//
+// Points are encoded in the compressed form of SEC 1.
+// The arithmetic of scalars isn't constant time, so this curve is intended for protocols
+// hashing to the curve, such as VRFs, rather than for the secret shares of the threshold protocols.
+type P256 struct{}
+
+func (P256) NewPoint() Point {
+	return &P256Point{value: group.P256.Identity()}
+}
+
+func (P256) NewBasePoint() Point {
+	return &P256Point{value: group.P256.Generator()}
+}
+
+func (P256) NewScalar() Scalar {
+	return &P256Scalar{value: group.P256.NewScalar()}
+}
+
+func (P256) ScalarBits() int {
+	return 256
+}
+
+func (P256) SafeScalarBytes() int {
+	return 64
+}
+
+var p256Order = saferith.ModulusFromNat(new(saferith.Nat).SetBig(elliptic.P256().Params().N, 256))
+
+func (P256) Order() *saferith.Modulus {
+	return p256Order
+}
+
+func (P256) Name() string {
+	return "p256"
+}
+
+func (c P256) HashToScalar(msg, dst []byte) Scalar {
+	return hashToScalar(c, sha256.New, msg, dst)
+}
+
+func (P256) HashToPoint(msg, dst []byte) Point {
+	return &P256Point{value: group.P256.HashToElement(msg, dst)}
+}
+
+type P256Scalar struct {
+	value group.Scalar
+}
+
+func p256CastScalar(generic Scalar) *P256Scalar {
+	out, ok := generic.(*P256Scalar)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to p256Scalar: %v", generic))
+	}
+	return out
+}
+
+func (*P256Scalar) Curve() Curve {
+	return P256{}
+}
+
+func (s *P256Scalar) MarshalBinary() ([]byte, error) {
+	return s.value.MarshalBinary()
+}
+
+func (s *P256Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid length for p256 scalar: %d", len(data))
+	}
+	if new(big.Int).SetBytes(data).Cmp(elliptic.P256().Params().N) >= 0 {
+		return fmt.Errorf("invalid bytes for p256 scalar")
+	}
+	return s.value.UnmarshalBinary(data)
+}
+
+func (s *P256Scalar) Add(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.Add(s.value, other.value)
+	return s
+}
+
+func (s *P256Scalar) Sub(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.Sub(s.value, other.value)
+	return s
+}
+
+func (s *P256Scalar) Mul(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.Mul(s.value, other.value)
+	return s
+}
+
+func (s *P256Scalar) Invert() Scalar {
+	s.value.Inv(s.value)
+	return s
+}
+
+func (s *P256Scalar) Negate() Scalar {
+	s.value.Neg(s.value)
+	return s
+}
+
+func (s *P256Scalar) IsOverHalfOrder() bool {
+	data, _ := s.value.MarshalBinary()
+	half := new(big.Int).Rsh(elliptic.P256().Params().N, 1)
+	return new(big.Int).SetBytes(data).Cmp(half) > 0
+}
+
+func (s *P256Scalar) Equal(that Scalar) bool {
+	other := p256CastScalar(that)
+
+	return s.value.IsEqual(other.value)
+}
+
+func (s *P256Scalar) IsZero() bool {
+	return s.value.IsZero()
+}
+
+func (s *P256Scalar) Set(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.Set(other.value)
+	return s
+}
+
+func (s *P256Scalar) SetNat(x *saferith.Nat) Scalar {
+	reduced := new(saferith.Nat).Mod(x, p256Order)
+	s.value.SetBigInt(reduced.Big())
+	return s
+}
+
+func (s *P256Scalar) Act(that Point) Point {
+	other := p256CastPoint(that)
+
+	return &P256Point{value: group.P256.NewElement().Mul(other.value, s.value)}
+}
+
+func (s *P256Scalar) ActOnBase() Point {
+	return &P256Point{value: group.P256.NewElement().MulGen(s.value)}
+}
+
+type P256Point struct {
+	value group.Element
+}
+
+func p256CastPoint(generic Point) *P256Point {
+	out, ok := generic.(*P256Point)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to p256Point: %v", generic))
+	}
+	return out
+}
+
+func (*P256Point) Curve() Curve {
+	return P256{}
+}
+
+func (p *P256Point) MarshalBinary() ([]byte, error) {
+	if p.value.IsIdentity() {
+		return make([]byte, 33), nil
+	}
+	return p.value.MarshalBinaryCompress()
+}
+
+func (p *P256Point) UnmarshalBinary(data []byte) error {
+	if len(data) != 33 {
+		return fmt.Errorf("invalid length for p256Point: %d", len(data))
+	}
+	value := group.P256.NewElement()
+	if data[0] != 0 {
+		if err := value.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("p256Point.UnmarshalBinary: %w", err)
+		}
+	} else if new(big.Int).SetBytes(data).Sign() != 0 {
+		return fmt.Errorf("p256Point.UnmarshalBinary: invalid encoding of the identity")
+	}
+	p.value = value
+	return nil
+}
+
+func (p *P256Point) Add(that Point) Point {
+	other := p256CastPoint(that)
+
+	return &P256Point{value: group.P256.NewElement().Add(p.value, other.value)}
+}
+
+func (p *P256Point) Sub(that Point) Point {
+	return p.Add(that.Negate())
+}
+
+func (p *P256Point) Set(that Point) Point {
+	other := p256CastPoint(that)
+
+	p.value = other.value.Copy()
+	return p
+}
+
+func (p *P256Point) Negate() Point {
+	return &P256Point{value: group.P256.NewElement().Neg(p.value)}
+}
+
+func (p *P256Point) Equal(that Point) bool {
+	other := p256CastPoint(that)
+
+	return p.value.IsEqual(other.value)
+}
+
+func (p *P256Point) IsIdentity() bool {
+	return p == nil || p.value.IsIdentity()
+}
+
+func (p *P256Point) XScalar() Scalar {
+	if p.value.IsIdentity() {
+		return P256{}.NewScalar()
+	}
+	data, _ := p.value.MarshalBinary()
+	return P256{}.NewScalar().SetNat(new(saferith.Nat).SetBytes(data[1:33]))
+}
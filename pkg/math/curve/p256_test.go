@@ -0,0 +1,33 @@
+package curve_test
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+func TestP256(t *testing.T) {
+	group := curve.P256{}
+	testGroup(t, group)
+
+	// the SEC 1 encoding must match the one of the standard library
+	a := sample.Scalar(rand.Reader, group)
+	data, err := a.MarshalBinary()
+	require.NoError(t, err)
+	x, y := elliptic.P256().ScalarBaseMult(data)
+	sec1, err := curve.MarshalSEC1(a.ActOnBase(), false)
+	require.NoError(t, err)
+	assert.Equal(t, elliptic.Marshal(elliptic.P256(), x, y), sec1)
+	compressed, err := a.ActOnBase().MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, elliptic.MarshalCompressed(elliptic.P256(), x, y), compressed)
+
+	// scalars must be reduced
+	order := elliptic.P256().Params().N.Bytes()
+	assert.Error(t, group.NewScalar().UnmarshalBinary(order))
+}
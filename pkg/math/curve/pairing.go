@@ -0,0 +1,123 @@
+package curve
+
+import (
+	"encoding"
+	"fmt"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Pairing is implemented by curves which come with a bilinear map e : G₁ × G₂ → Gₜ.
+//
+// The curve itself is G₁, and shares its scalars with G₂ and Gₜ.
+// This is kept apart from Curve, so that protocols which only need a group don't
+// have to care about pairings.
+type Pairing interface {
+	Curve
+	// G2 returns the second source group of the pairing.
+	G2() Curve
+	// NewGT creates the identity element of the target group.
+	NewGT() GT
+	// Pair computes e(p, q), where p is in G₁, and q in G₂.
+	Pair(p, q Point) GT
+	// MultiPair computes ∏ᵢ e(pᵢ, qᵢ).
+	//
+	// This shares a single final exponentiation between all the Miller loops,
+	// and should be preferred over multiplying the results of Pair.
+	MultiPair(p, q []Point) GT
+}
+
+// GT is an element of the target group of a Pairing.
+//
+// Like Point, the methods on GT never modify their receiver.
+type GT interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	// Mul returns the product of this element with another.
+	Mul(GT) GT
+	// Exp returns this element raised to the power of a scalar.
+	Exp(Scalar) GT
+	// Equal checks if this element is equal to another.
+	Equal(GT) bool
+	// IsIdentity checks if this is the identity element of Gₜ.
+	IsIdentity() bool
+}
+
+func (BLS12381) G2() Curve {
+	return BLS12381G2{}
+}
+
+func (BLS12381) NewGT() GT {
+	out := new(BLS12381GT)
+	out.value.SetIdentity()
+	return out
+}
+
+func (BLS12381) Pair(p, q Point) GT {
+	return &BLS12381GT{value: *bls12381.Pair(&bls12381CastPoint(p).value, &bls12381G2CastPoint(q).value)}
+}
+
+func (BLS12381) MultiPair(p, q []Point) GT {
+	if len(p) != len(q) {
+		panic(fmt.Sprintf("bls12381.MultiPair: got %d points in G1 and %d in G2", len(p), len(q)))
+	}
+	ps := make([]*bls12381.G1, len(p))
+	qs := make([]*bls12381.G2, len(q))
+	signs := make([]int, len(p))
+	for i := range p {
+		ps[i] = &bls12381CastPoint(p[i]).value
+		qs[i] = &bls12381G2CastPoint(q[i]).value
+		signs[i] = 1
+	}
+	return &BLS12381GT{value: *bls12381.ProdPairFrac(ps, qs, signs)}
+}
+
+type BLS12381GT struct {
+	value bls12381.Gt
+}
+
+func bls12381CastGT(generic GT) *BLS12381GT {
+	out, ok := generic.(*BLS12381GT)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to bls12381GT: %v", generic))
+	}
+	return out
+}
+
+func (g *BLS12381GT) MarshalBinary() ([]byte, error) {
+	return g.value.MarshalBinary()
+}
+
+func (g *BLS12381GT) UnmarshalBinary(data []byte) error {
+	if len(data) != bls12381.GtSize {
+		return fmt.Errorf("invalid length for bls12381GT: %d", len(data))
+	}
+	if err := g.value.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("bls12381GT.UnmarshalBinary: %w", err)
+	}
+	return nil
+}
+
+func (g *BLS12381GT) Mul(that GT) GT {
+	other := bls12381CastGT(that)
+
+	out := new(BLS12381GT)
+	out.value.Mul(&g.value, &other.value)
+	return out
+}
+
+func (g *BLS12381GT) Exp(s Scalar) GT {
+	out := new(BLS12381GT)
+	out.value.Exp(&g.value, &bls12381CastScalar(s).value)
+	return out
+}
+
+func (g *BLS12381GT) Equal(that GT) bool {
+	other := bls12381CastGT(that)
+
+	return g.value.IsEqual(&other.value)
+}
+
+func (g *BLS12381GT) IsIdentity() bool {
+	return g.value.IsIdentity()
+}
@@ -0,0 +1,52 @@
+package curve
+
+import "fmt"
+
+// SEC1Marshaler is implemented by the points of curves for which SEC 1 defines an encoding.
+type SEC1Marshaler interface {
+	// MarshalSEC1 returns the SEC 1 encoding of the point, as 33 bytes if compressed, or 65 bytes otherwise.
+	// The identity is encoded as a single 0 byte.
+	MarshalSEC1(compressed bool) []byte
+}
+
+// MarshalSEC1 returns the standard SEC 1 encoding of p, which most libraries and formats use for public keys.
+//
+// It returns an error if p belongs to a curve without such an encoding, such as Edwards25519.
+func MarshalSEC1(p Point, compressed bool) ([]byte, error) {
+	m, ok := p.(SEC1Marshaler)
+	if !ok {
+		return nil, fmt.Errorf("curve: %s points don't have a SEC 1 encoding", p.Curve().Name())
+	}
+	return m.MarshalSEC1(compressed), nil
+}
+
+func (p *Secp256k1Point) MarshalSEC1(compressed bool) []byte {
+	if p.IsIdentity() {
+		return []byte{0}
+	}
+	if compressed {
+		out, _ := p.MarshalBinary()
+		return out
+	}
+	v := p.value
+	v.ToAffine()
+	out := make([]byte, 65)
+	out[0] = 4
+	x, y := v.X.Bytes(), v.Y.Bytes()
+	copy(out[1:33], x[:])
+	copy(out[33:], y[:])
+	return out
+}
+
+func (p *P256Point) MarshalSEC1(compressed bool) []byte {
+	if p.IsIdentity() {
+		return []byte{0}
+	}
+	var out []byte
+	if compressed {
+		out, _ = p.value.MarshalBinaryCompress()
+	} else {
+		out, _ = p.value.MarshalBinary()
+	}
+	return out
+}
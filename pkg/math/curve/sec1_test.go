@@ -0,0 +1,50 @@
+package curve_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+func TestMarshalSEC1(t *testing.T) {
+	group := curve.Secp256k1{}
+	p := sample.Scalar(rand.Reader, group).ActOnBase()
+
+	uncompressed, err := curve.MarshalSEC1(p, false)
+	require.NoError(t, err)
+	assert.Len(t, uncompressed, 65)
+	compressed, err := curve.MarshalSEC1(p, true)
+	require.NoError(t, err)
+	assert.Len(t, compressed, 33)
+
+	pk, err := secp256k1.ParsePubKey(uncompressed)
+	require.NoError(t, err)
+	assert.Equal(t, pk.SerializeCompressed(), compressed)
+	assert.Equal(t, pk.SerializeUncompressed(), uncompressed)
+
+	identity, err := curve.MarshalSEC1(group.NewPoint(), true)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0}, identity)
+
+	_, err = curve.MarshalSEC1(curve.Edwards25519{}.NewBasePoint(), true)
+	assert.Error(t, err)
+}
+
+func TestKeyID(t *testing.T) {
+	secp := curve.Secp256k1{}
+	p := sample.Scalar(rand.Reader, secp).ActOnBase()
+	q := sample.Scalar(rand.Reader, secp).ActOnBase()
+
+	id := curve.KeyID(p)
+	assert.Len(t, id, 64)
+	assert.Equal(t, id, curve.KeyID(p.Add(secp.NewPoint())))
+	assert.NotEqual(t, id, curve.KeyID(q))
+
+	// derived from SHA-256("secp256k1" ‖ 0x00 ‖ G), with G compressed
+	assert.Equal(t, "b92b54f950c5f0a267aa4042ef4376741b09375b0984375daf07815b284acb55", curve.KeyID(secp.NewBasePoint()))
+}
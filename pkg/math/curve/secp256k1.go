@@ -171,7 +171,7 @@ func (s *Secp256k1Scalar) Act(that Point) Point {
 
 func (s *Secp256k1Scalar) ActOnBase() Point {
 	out := new(Secp256k1Point)
-	secp256k1.ScalarBaseMultNonConst(&s.value, &out.value)
+	secp256k1ScalarBaseMult(&s.value, &out.value)
 	return out
 }
 
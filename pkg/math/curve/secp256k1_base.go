@@ -0,0 +1,96 @@
+package curve
+
+import (
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// baseWindow is the width of the signed digits used to multiply the base point of secp256k1.
+//
+// Wider windows need fewer additions, but the table grows exponentially: with 10 bits,
+// a scalar takes 26 additions instead of the 32 of secp256k1.ScalarBaseMultNonConst,
+// for a table of about 1.5 MiB, computed the first time it's needed.
+const baseWindow = 10
+
+// baseWindows is the number of digits of a scalar.
+// The top digit covers fewer than baseWindow bits, so the carry out of it is always 0.
+const baseWindows = (256 + baseWindow - 1) / baseWindow
+
+var (
+	// secp256k1BaseTable[i][j] = (j+1)•2^(baseWindow•i)•G, in affine coordinates.
+	secp256k1BaseTable     *[baseWindows][1 << (baseWindow - 1)]secp256k1.JacobianPoint
+	secp256k1BaseTableOnce sync.Once
+)
+
+func initSecp256k1BaseTable() {
+	table := new([baseWindows][1 << (baseWindow - 1)]secp256k1.JacobianPoint)
+	var base secp256k1.JacobianPoint
+	one := new(secp256k1.ModNScalar).SetInt(1)
+	secp256k1.ScalarBaseMultNonConst(one, &base)
+	multiples := make([]*secp256k1.JacobianPoint, 0, baseWindows*len(table[0]))
+	for i := range table {
+		table[i][0].Set(&base)
+		for j := 1; j < len(table[i]); j++ {
+			secp256k1.AddNonConst(&table[i][j-1], &base, &table[i][j])
+		}
+		// the next base is 2^baseWindow•base = 2•((2^(baseWindow-1))•base)
+		secp256k1.DoubleNonConst(&table[i][len(table[i])-1], &base)
+		for j := range table[i] {
+			multiples = append(multiples, &table[i][j])
+		}
+	}
+	secp256k1ToAffine(multiples)
+	secp256k1BaseTable = table
+}
+
+// secp256k1ScalarBaseMult sets result = k•G, in variable time, using the precomputed multiples of G.
+func secp256k1ScalarBaseMult(k *secp256k1.ModNScalar, result *secp256k1.JacobianPoint) {
+	secp256k1BaseTableOnce.Do(initSecp256k1BaseTable)
+
+	limbs := secp256k1Limbs(k)
+
+	result.X.Zero()
+	result.Y.Zero()
+	result.Z.Zero()
+	var neg secp256k1.JacobianPoint
+	carry := 0
+	for i := 0; i < baseWindows; i++ {
+		// the digits are in (-2^(baseWindow-1), 2^(baseWindow-1)]
+		d := limbs.window(i*baseWindow, baseWindow) + carry
+		carry = 0
+		if d > 1<<(baseWindow-1) {
+			d -= 1 << baseWindow
+			carry = 1
+		}
+		switch {
+		case d > 0:
+			secp256k1.AddNonConst(result, &secp256k1BaseTable[i][d-1], result)
+		case d < 0:
+			neg.Set(&secp256k1BaseTable[i][-d-1])
+			neg.Y.Negate(1).Normalize()
+			secp256k1.AddNonConst(result, &neg, result)
+		}
+	}
+}
+
+// secp256k1ScalarLimbs holds a scalar in little endian order, with an extra limb for the windows reading past the top.
+type secp256k1ScalarLimbs [5]uint64
+
+func secp256k1Limbs(k *secp256k1.ModNScalar) secp256k1ScalarLimbs {
+	b := k.Bytes()
+	var limbs secp256k1ScalarLimbs
+	for i := 0; i < 32; i++ {
+		limbs[i/8] |= uint64(b[31-i]) << (8 * (i % 8))
+	}
+	return limbs
+}
+
+// window returns the width bits of the scalar starting at bit, with width < 64.
+func (limbs *secp256k1ScalarLimbs) window(bit, width int) int {
+	word := limbs[bit/64] >> (bit % 64)
+	if bit%64 > 64-width && bit/64+1 < len(limbs) {
+		word |= limbs[bit/64+1] << (64 - bit%64)
+	}
+	return int(word & (1<<width - 1))
+}
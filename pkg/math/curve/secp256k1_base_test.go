@@ -0,0 +1,59 @@
+package curve
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func TestSecp256k1ScalarBaseMult(t *testing.T) {
+	var scalars []*secp256k1.ModNScalar
+	for _, x := range []uint32{0, 1, 2, 511, 512, 513, 1023, 1024} {
+		scalars = append(scalars, new(secp256k1.ModNScalar).SetInt(x))
+	}
+	// -1, and digits which are all at the border of the window
+	scalars = append(scalars, new(secp256k1.ModNScalar).SetInt(1).Negate())
+	var border [32]byte
+	for i := range border {
+		border[i] = 0x80
+	}
+	borderScalar := new(secp256k1.ModNScalar)
+	borderScalar.SetBytes(&border)
+	scalars = append(scalars, borderScalar)
+	for i := 0; i < 100; i++ {
+		var b [32]byte
+		_, _ = rand.Read(b[:])
+		s := new(secp256k1.ModNScalar)
+		s.SetBytes(&b)
+		scalars = append(scalars, s)
+	}
+	for _, s := range scalars {
+		var expected, actual secp256k1.JacobianPoint
+		secp256k1.ScalarBaseMultNonConst(s, &expected)
+		secp256k1ScalarBaseMult(s, &actual)
+		if !(&Secp256k1Point{expected}).Equal(&Secp256k1Point{actual}) {
+			t.Errorf("%x: wrong multiple of the base point", s.Bytes())
+		}
+	}
+}
+
+func BenchmarkSecp256k1ScalarBaseMult(b *testing.B) {
+	var kb [32]byte
+	_, _ = rand.Read(kb[:])
+	k := new(secp256k1.ModNScalar)
+	k.SetBytes(&kb)
+	var out secp256k1.JacobianPoint
+	b.Run("table", func(b *testing.B) {
+		secp256k1ScalarBaseMult(k, &out)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			secp256k1ScalarBaseMult(k, &out)
+		}
+	})
+	b.Run("dcrd", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			secp256k1.ScalarBaseMultNonConst(k, &out)
+		}
+	})
+}
@@ -45,7 +45,14 @@ func NewPolynomialExponent(polynomial *Polynomial) *Exponent {
 }
 
 // Evaluate returns F(x) = [secret + a₁•x + … + aₜ•xᵗ]•G.
+//
+// Over secp256k1, this is computed as the multi-scalar multiplication [1, x, …, xᵗ]•[A₀, …, Aₜ],
+// whose doublings are shared between all the coefficients, in variable time.
 func (p *Exponent) Evaluate(x curve.Scalar) curve.Point {
+	if _, ok := p.group.(curve.Secp256k1); ok && len(p.coefficients) > 1 {
+		return curve.Secp256k1MultiScalarMult(p.powers(x), p.secp256k1Coefficients())
+	}
+
 	result := p.group.NewPoint()
 
 	for i := len(p.coefficients) - 1; i >= 0; i-- {
@@ -62,6 +69,52 @@ func (p *Exponent) Evaluate(x curve.Scalar) curve.Point {
 	return result
 }
 
+// EvaluateMulti returns F(x) for every x in xs, in the same order.
+//
+// Over secp256k1, the multiples of the coefficients are precomputed once, and each evaluation is
+// a single multi-scalar multiplication [1, x, …, xᵗ]•[A₀, …, Aₜ], which is much faster than
+// calling Evaluate for each x, such as when computing the public shares of all parties.
+// The computation isn't constant time, which is fine since the coefficients and the xs are public.
+func (p *Exponent) EvaluateMulti(xs []curve.Scalar) []curve.Point {
+	results := make([]curve.Point, len(xs))
+	if _, ok := p.group.(curve.Secp256k1); !ok || len(xs) < 2 {
+		for j, x := range xs {
+			results[j] = p.Evaluate(x)
+		}
+		return results
+	}
+
+	table := curve.NewSecp256k1MultiScalarTable(p.secp256k1Coefficients())
+	for j, x := range xs {
+		results[j] = table.MultiScalarMult(p.powers(x))
+	}
+	return results
+}
+
+// secp256k1Coefficients returns the coefficients of p, which must be over secp256k1.
+func (p *Exponent) secp256k1Coefficients() []*curve.Secp256k1Point {
+	points := make([]*curve.Secp256k1Point, len(p.coefficients))
+	for i, c := range p.coefficients {
+		points[i] = c.(*curve.Secp256k1Point)
+	}
+	return points
+}
+
+// powers returns the powers of x matching the coefficients of p, which must be over secp256k1:
+// xⁱ for the coefficient i, or xⁱ⁺¹ if the constant coefficient is left out.
+func (p *Exponent) powers(x curve.Scalar) []*curve.Secp256k1Scalar {
+	powers := make([]*curve.Secp256k1Scalar, len(p.coefficients))
+	xPower := p.group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
+	if p.IsConstant {
+		xPower.Mul(x)
+	}
+	for i := range powers {
+		powers[i] = p.group.NewScalar().Set(xPower).(*curve.Secp256k1Scalar)
+		xPower.Mul(x)
+	}
+	return powers
+}
+
 // evaluateClassic evaluates a polynomial in a given variable index
 // We do the classic method, where we compute all powers of x.
 func (p *Exponent) evaluateClassic(x curve.Scalar) curve.Point {
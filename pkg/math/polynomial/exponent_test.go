@@ -37,6 +37,43 @@ func TestExponent_Evaluate(t *testing.T) {
 	}
 }
 
+func TestExponent_EvaluateDegrees(t *testing.T) {
+	group := curve.Secp256k1{}
+	// degrees on both sides of the switch from Straus to Pippenger, and a constant term left out
+	for _, degree := range []int{0, 1, 2, 20, 100} {
+		for _, secret := range []curve.Scalar{group.NewScalar(), sample.Scalar(rand.Reader, group)} {
+			poly := NewPolynomial(group, degree, secret)
+			polyExp := NewPolynomialExponent(poly)
+			x := sample.Scalar(rand.Reader, group)
+			assert.True(t, polyExp.Evaluate(x).Equal(poly.Evaluate(x).ActOnBase()), "degree %d", degree)
+			assert.True(t, polyExp.Evaluate(group.NewScalar()).Equal(secret.ActOnBase()), "degree %d at 0", degree)
+		}
+	}
+}
+
+func TestEvaluateMulti(t *testing.T) {
+	for _, group := range []curve.Curve{curve.Secp256k1{}, curve.Edwards25519{}} {
+		for _, secret := range []curve.Scalar{group.NewScalar(), sample.Scalar(rand.Reader, group)} {
+			poly := NewPolynomial(group, 5, secret)
+			polyExp := NewPolynomialExponent(poly)
+			xs := make([]curve.Scalar, 4)
+			for i := range xs {
+				xs[i] = sample.Scalar(rand.Reader, group)
+			}
+
+			shares := poly.EvaluateMulti(xs)
+			publicShares := polyExp.EvaluateMulti(xs)
+			require.Len(t, shares, len(xs))
+			require.Len(t, publicShares, len(xs))
+			for i, x := range xs {
+				assert.True(t, shares[i].Equal(poly.Evaluate(x)), group.Name())
+				assert.True(t, publicShares[i].Equal(polyExp.Evaluate(x)), group.Name())
+				assert.True(t, publicShares[i].Equal(shares[i].ActOnBase()), group.Name())
+			}
+		}
+	}
+}
+
 func TestSum(t *testing.T) {
 	group := curve.Secp256k1{}
 
@@ -84,3 +121,21 @@ func TestMarshall(t *testing.T) {
 	require.NoError(t, err, "failed to Unmarshal")
 	assert.True(t, polyExp.Equal(*polyExp2), "should be the same")
 }
+
+func BenchmarkExponent_Evaluate(b *testing.B) {
+	group := curve.Secp256k1{}
+	for _, degree := range []int{2, 10, 50} {
+		polyExp := NewPolynomialExponent(NewPolynomial(group, degree, sample.Scalar(rand.Reader, group)))
+		x := sample.Scalar(rand.Reader, group)
+		b.Run(fmt.Sprintf("msm/%d", degree), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				polyExp.Evaluate(x)
+			}
+		})
+		b.Run(fmt.Sprintf("classic/%d", degree), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				polyExp.evaluateClassic(x)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package polynomial
+
+import (
+	"encoding/binary"
+	"strings"
+	"sync"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// LagrangeCache stores the Lagrange coefficients at 0 of recently used interpolation domains,
+// so that sessions signing with the same set of parties don't have to invert the same denominators again.
+//
+// A LagrangeCache is safe for concurrent use.
+// The coefficients it returns are copies, which callers are free to modify.
+type LagrangeCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]map[party.ID]curve.Scalar
+	// order contains the keys of the entries, from the oldest to the most recent.
+	order []string
+}
+
+// NewLagrangeCache returns a LagrangeCache holding the coefficients of at most capacity interpolation domains,
+// discarding the oldest domain when a new one doesn't fit.
+func NewLagrangeCache(capacity int) *LagrangeCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LagrangeCache{
+		capacity: capacity,
+		entries:  make(map[string]map[party.ID]curve.Scalar, capacity),
+	}
+}
+
+// Lagrange returns the Lagrange coefficients at 0 for all parties in the interpolation domain,
+// as polynomial.Lagrange does.
+//
+// A nil LagrangeCache computes the coefficients without caching them.
+func (c *LagrangeCache) Lagrange(group curve.Curve, interpolationDomain []party.ID) map[party.ID]curve.Scalar {
+	if c == nil {
+		return Lagrange(group, interpolationDomain)
+	}
+	key := lagrangeCacheKey(group, interpolationDomain)
+
+	c.mu.Lock()
+	coefficients, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok {
+		coefficients = Lagrange(group, interpolationDomain)
+		c.store(key, coefficients)
+	}
+
+	out := make(map[party.ID]curve.Scalar, len(coefficients))
+	for id, coefficient := range coefficients {
+		out[id] = group.NewScalar().Set(coefficient)
+	}
+	return out
+}
+
+// LagrangeSingle returns the Lagrange coefficient at 0 of the party with index j,
+// as polynomial.LagrangeSingle does.
+func (c *LagrangeCache) LagrangeSingle(group curve.Curve, interpolationDomain []party.ID, j party.ID) curve.Scalar {
+	if c == nil {
+		return LagrangeSingle(group, interpolationDomain, j)
+	}
+	return c.Lagrange(group, interpolationDomain)[j]
+}
+
+// Len returns the number of interpolation domains currently cached.
+func (c *LagrangeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// store adds the coefficients of a domain, unless another caller already did.
+func (c *LagrangeCache) store(key string, coefficients map[party.ID]curve.Scalar) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	if len(c.order) == c.capacity {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[key] = coefficients
+	c.order = append(c.order, key)
+}
+
+// lagrangeCacheKey identifies an interpolation domain over a group, independently of the order of its IDs.
+func lagrangeCacheKey(group curve.Curve, interpolationDomain []party.ID) string {
+	var b strings.Builder
+	var length [binary.MaxVarintLen64]byte
+	write := func(s string) {
+		b.Write(length[:binary.PutUvarint(length[:], uint64(len(s)))])
+		b.WriteString(s)
+	}
+	write(group.Name())
+	for _, id := range party.NewIDSlice(interpolationDomain) {
+		write(string(id))
+	}
+	return b.String()
+}
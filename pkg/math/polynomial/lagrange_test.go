@@ -8,6 +8,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
 
 func TestLagrange(t *testing.T) {
@@ -29,3 +30,29 @@ func TestLagrange(t *testing.T) {
 	assert.True(t, sumEven.Equal(one))
 	assert.True(t, sumOdd.Equal(one))
 }
+
+func TestLagrangeCache(t *testing.T) {
+	group := curve.Secp256k1{}
+	allIDs := test.PartyIDs(5)
+	cache := polynomial.NewLagrangeCache(2)
+
+	expected := polynomial.Lagrange(group, allIDs)
+	reversed := []party.ID{allIDs[4], allIDs[3], allIDs[2], allIDs[1], allIDs[0]}
+	for _, domain := range [][]party.ID{allIDs, reversed} {
+		coefficients := cache.Lagrange(group, domain)
+		for id, c := range expected {
+			assert.True(t, c.Equal(coefficients[id]))
+		}
+		// modifying the returned coefficients must not change the cached ones
+		coefficients[allIDs[0]].Add(coefficients[allIDs[0]])
+	}
+	assert.Equal(t, 1, cache.Len())
+	assert.True(t, expected[allIDs[1]].Equal(cache.LagrangeSingle(group, allIDs, allIDs[1])))
+
+	cache.Lagrange(group, allIDs[:3])
+	cache.Lagrange(group, allIDs[:4])
+	assert.Equal(t, 2, cache.Len())
+
+	var noCache *polynomial.LagrangeCache
+	assert.True(t, expected[allIDs[2]].Equal(noCache.LagrangeSingle(group, allIDs, allIDs[2])))
+}
@@ -1,8 +1,10 @@
 package polynomial
 
 import (
-	"crypto/rand"
+	"errors"
 
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 )
@@ -28,7 +30,7 @@ func NewPolynomial(group curve.Curve, degree int, constant curve.Scalar) *Polyno
 	polynomial.coefficients[0] = constant
 
 	for i := 1; i <= degree; i++ {
-		polynomial.coefficients[i] = sample.Scalar(rand.Reader, group)
+		polynomial.coefficients[i] = sample.Scalar(random.Reader(), group)
 	}
 
 	return polynomial
@@ -50,6 +52,30 @@ func (p *Polynomial) Evaluate(index curve.Scalar) curve.Scalar {
 	return result
 }
 
+// EvaluateMulti returns the evaluations of the polynomial at every index, in the same order.
+//
+// It panics if any index is zero, as Evaluate does.
+func (p *Polynomial) EvaluateMulti(indices []curve.Scalar) []curve.Scalar {
+	for _, index := range indices {
+		if index.IsZero() {
+			panic("attempt to leak secret")
+		}
+	}
+
+	results := make([]curve.Scalar, len(indices))
+	for j := range results {
+		results[j] = p.group.NewScalar()
+	}
+	// all the evaluations go through the coefficients together, so each one is only read once.
+	for i := len(p.coefficients) - 1; i >= 0; i-- {
+		for j, index := range indices {
+			// bₙ₋₁ = bₙ * x + aₙ₋₁
+			results[j].Mul(index).Add(p.coefficients[i])
+		}
+	}
+	return results
+}
+
 // Constant returns a reference to the constant coefficient of the polynomial.
 func (p *Polynomial) Constant() curve.Scalar {
 	return p.group.NewScalar().Set(p.coefficients[0])
@@ -59,3 +85,37 @@ func (p *Polynomial) Constant() curve.Scalar {
 func (p *Polynomial) Degree() uint32 {
 	return uint32(len(p.coefficients)) - 1
 }
+
+// EmptyPolynomial returns a Polynomial over group, ready for unmarshalling.
+func EmptyPolynomial(group curve.Curve) *Polynomial {
+	return &Polynomial{group: group}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The coefficients of a polynomial are usually secret, so the output must be stored securely.
+func (p *Polynomial) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(p.coefficients)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (p *Polynomial) UnmarshalBinary(data []byte) error {
+	if p == nil || p.group == nil {
+		return errors.New("can't unmarshal Polynomial with no group")
+	}
+	var raw []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) == 0 {
+		return errors.New("polynomial: no coefficients")
+	}
+	p.coefficients = make([]curve.Scalar, len(raw))
+	for i := range raw {
+		p.coefficients[i] = p.group.NewScalar()
+		if err := cbor.Unmarshal(raw[i], p.coefficients[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -43,29 +43,29 @@ func IntervalLPrimeEps(rand io.Reader) *saferith.Int {
 	return sampleNeg(rand, params.LPrimePlusEpsilon)
 }
 
-// IntervalLN returns an integer in the range ± 2ˡ•N, where N is the size of a Paillier modulus.
-func IntervalLN(rand io.Reader) *saferith.Int {
-	return sampleNeg(rand, params.L+params.BitsIntModN)
+// IntervalLN returns an integer in the range ± 2ˡ•N, where N is the size of the modulus n.
+func IntervalLN(rand io.Reader, n *saferith.Modulus) *saferith.Int {
+	return sampleNeg(rand, params.L+n.BitLen())
 }
 
-// IntervalLN2 returns an integer in the range ± 2ˡ•N², where N is the size of a Paillier modulus.
-func IntervalLN2(rand io.Reader) *saferith.Int {
-	return sampleNeg(rand, params.L+(2*params.BitsIntModN))
+// IntervalLN2 returns an integer in the range ± 2ˡ•N², where N is the size of the modulus n.
+func IntervalLN2(rand io.Reader, n *saferith.Modulus) *saferith.Int {
+	return sampleNeg(rand, params.L+(2*n.BitLen()))
 }
 
-// IntervalLEpsN returns an integer in the range ± 2ˡ⁺ᵉ•N, where N is the size of a Paillier modulus.
-func IntervalLEpsN(rand io.Reader) *saferith.Int {
-	return sampleNeg(rand, params.LPlusEpsilon+params.BitsIntModN)
+// IntervalLEpsN returns an integer in the range ± 2ˡ⁺ᵉ•N, where N is the size of the modulus n.
+func IntervalLEpsN(rand io.Reader, n *saferith.Modulus) *saferith.Int {
+	return sampleNeg(rand, params.LPlusEpsilon+n.BitLen())
 }
 
-// IntervalLEpsN2 returns an integer in the range ± 2ˡ⁺ᵉ•N², where N is the size of a Paillier modulus.
-func IntervalLEpsN2(rand io.Reader) *saferith.Int {
-	return sampleNeg(rand, params.LPlusEpsilon+(2*params.BitsIntModN))
+// IntervalLEpsN2 returns an integer in the range ± 2ˡ⁺ᵉ•N², where N is the size of the modulus n.
+func IntervalLEpsN2(rand io.Reader, n *saferith.Modulus) *saferith.Int {
+	return sampleNeg(rand, params.LPlusEpsilon+(2*n.BitLen()))
 }
 
-// IntervalLEpsRootN returns an integer in the range ± 2ˡ⁺ᵉ•√N, where N is the size of a Paillier modulus.
-func IntervalLEpsRootN(rand io.Reader) *saferith.Int {
-	return sampleNeg(rand, params.LPlusEpsilon+(params.BitsIntModN/2))
+// IntervalLEpsRootN returns an integer in the range ± 2ˡ⁺ᵉ•√N, where N is the size of the modulus n.
+func IntervalLEpsRootN(rand io.Reader, n *saferith.Modulus) *saferith.Int {
+	return sampleNeg(rand, params.LPlusEpsilon+(n.BitLen()/2))
 }
 
 // IntervalScalar returns an integer in the range ±q, with q the size of a Scalar.
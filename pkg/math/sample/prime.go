@@ -70,12 +70,12 @@ var sievePool = sync.Pool{
 	},
 }
 
-func tryBlumPrime(rand io.Reader) *saferith.Nat {
+func tryBlumPrime(rand io.Reader, bits int) *saferith.Nat {
 	initPrimes.Do(func() {
 		thePrimes = primes(primeBound)
 	})
 
-	bytes := make([]byte, (params.BitsBlumPrime+7)/8)
+	bytes := make([]byte, (bits+7)/8)
 
 	_, err := io.ReadFull(rand, bytes)
 	if err != nil {
@@ -135,7 +135,7 @@ func tryBlumPrime(rand io.Reader) *saferith.Nat {
 
 		p.SetUint64(uint64(delta))
 		p.Add(p, base)
-		if p.BitLen() > params.BitsBlumPrime {
+		if p.BitLen() > bits {
 			return nil
 		}
 		// Since p is odd, this is equivalent to (p - 1) / 2
@@ -150,7 +150,7 @@ func tryBlumPrime(rand io.Reader) *saferith.Nat {
 		if !p.ProbablyPrime(0) {
 			continue
 		}
-		return new(saferith.Nat).SetBig(p, params.BitsBlumPrime)
+		return new(saferith.Nat).SetBig(p, bits)
 	}
 
 	return nil
@@ -160,9 +160,15 @@ func tryBlumPrime(rand io.Reader) *saferith.Nat {
 // p, q are safe primes ((p - 1) / 2 is also prime), and Blum primes (p = 3 mod 4)
 // n = pq.
 func Paillier(rand io.Reader, pl *pool.Pool) (p, q *saferith.Nat) {
+	return PaillierBits(rand, pl, params.BitsPaillier)
+}
+
+// PaillierBits is like Paillier, but generates primes for a modulus n of the given bit length,
+// which must be a multiple of 16, instead of params.BitsPaillier.
+func PaillierBits(rand io.Reader, pl *pool.Pool, bits int) (p, q *saferith.Nat) {
 	reader := pool.NewLockedReader(rand)
 	results := pl.Search(2, func() interface{} {
-		q := tryBlumPrime(reader)
+		q := tryBlumPrime(reader, bits/2)
 		// You have to do this, because of how Go handles nil.
 		if q == nil {
 			return nil
@@ -6,7 +6,6 @@ import (
 	"math/big"
 
 	"github.com/cronokirby/saferith"
-	"github.com/taurusgroup/multi-party-sig/internal/params"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 )
 
@@ -59,7 +58,7 @@ func UnitModN(rand io.Reader, n *saferith.Modulus) *saferith.Nat {
 func QNR(rand io.Reader, n *saferith.Modulus) *saferith.Nat {
 	var w big.Int
 	nBig := n.Big()
-	buf := make([]byte, params.BitsIntModN/8)
+	buf := make([]byte, (n.BitLen()+7)/8)
 	for i := 0; i < maxIterations; i++ {
 		mustReadBits(rand, buf)
 		w.SetBytes(buf)
@@ -0,0 +1,196 @@
+// Package mobile exposes CMP keygen, refresh and signing over secp256k1 with an API that gomobile can bind,
+// so that iOS and Android wallets can embed a party:
+//
+//	gomobile bind -target=android github.com/taurusgroup/multi-party-sig/pkg/mobile
+//
+// Only types supported by gomobile appear: strings, integers, booleans, byte slices, errors and pointers to the structs
+// of this package. Lists of parties are passed as comma separated IDs, configs are in the format of cmp.Config.MarshalBinary,
+// and messages in that of protocol.Message.MarshalBinary.
+//
+// A Session is driven by the application: every message received from another party is given to Accept,
+// and the messages returned by Next are sent to their recipients, until Done returns true.
+package mobile
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+)
+
+// Message is a message produced by a Session, which must be delivered to the party To,
+// or to all other parties of the session if To is empty.
+type Message struct {
+	// To is the recipient of the message, or the empty string for a message to all parties.
+	To string
+	// Broadcast is true if the message must be sent over a reliable broadcast channel.
+	Broadcast bool
+	// Data is the encoded message, which recipients pass to Session.Accept.
+	Data []byte
+}
+
+// Session is one execution of a protocol, producing either a config or a signature.
+type Session struct {
+	handler *protocol.PollHandler
+	pool    *pool.Pool
+	pending []*protocol.Message
+}
+
+// NewKeygen starts a CMP keygen for party id, between the comma separated parties, any threshold+1 of which can sign.
+//
+// The result is the config of this party.
+func NewKeygen(id, parties string, threshold int, sessionID []byte) (*Session, error) {
+	ids, err := parseIDs(parties)
+	if err != nil {
+		return nil, err
+	}
+	pl := pool.NewPool(0)
+	return newSession(cmp.Keygen(curve.Secp256k1{}, party.ID(id), ids, threshold, pl), sessionID, pl)
+}
+
+// NewRefresh starts a refresh of config, with all the parties of the config.
+//
+// The result is the refreshed config of this party, which replaces config.
+func NewRefresh(config []byte, sessionID []byte) (*Session, error) {
+	c, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	pl := pool.NewPool(0)
+	return newSession(cmp.Refresh(c, pl), sessionID, pl)
+}
+
+// NewSign starts signing messageHash with config, between the comma separated signers.
+//
+// The result is the signature as the 65 bytes r || s || v, with a low s and the recovery id v,
+// as returned by ecdsa.Signature.SigEthereum.
+func NewSign(config []byte, signers string, messageHash []byte, sessionID []byte) (*Session, error) {
+	c, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := parseIDs(signers)
+	if err != nil {
+		return nil, err
+	}
+	pl := pool.NewPool(0)
+	return newSession(cmp.Sign(c, ids, messageHash, pl), sessionID, pl)
+}
+
+func newSession(create protocol.StartFunc, sessionID []byte, pl *pool.Pool) (*Session, error) {
+	h, err := protocol.NewPollHandler(create, sessionID)
+	if err != nil {
+		pl.TearDown()
+		return nil, err
+	}
+	return &Session{handler: h, pool: pl}, nil
+}
+
+// Accept processes a message received from another party.
+//
+// Messages which don't belong to the session are ignored. An error is only returned if data can't be decoded.
+func (s *Session) Accept(data []byte) error {
+	msg := new(protocol.Message)
+	if err := msg.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	s.handler.Accept(msg)
+	return nil
+}
+
+// Next returns the next message to send, or nil if there is none until more messages are accepted.
+func (s *Session) Next() (*Message, error) {
+	if len(s.pending) == 0 {
+		s.pending = s.handler.Poll()
+	}
+	if len(s.pending) == 0 {
+		return nil, nil
+	}
+	msg := s.pending[0]
+	s.pending = s.pending[1:]
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &Message{To: string(msg.To), Broadcast: msg.Broadcast, Data: data}, nil
+}
+
+// Done returns true once the session has finished, successfully or not.
+// The messages returned by Next should still be sent, since they may tell the other parties about an abort.
+func (s *Session) Done() bool {
+	return s.handler.Done()
+}
+
+// Result returns the outcome of the session: a config for keygen and refresh, or a signature for signing.
+func (s *Session) Result() ([]byte, error) {
+	result, err := s.handler.Result()
+	if err != nil {
+		return nil, err
+	}
+	switch r := result.(type) {
+	case *cmp.Config:
+		return r.MarshalBinary()
+	case *ecdsa.Signature:
+		return r.SigEthereum()
+	default:
+		return nil, errors.New("mobile: unexpected result")
+	}
+}
+
+// Stop aborts the session. The message telling the other parties is returned by Next.
+func (s *Session) Stop() {
+	s.handler.Stop()
+}
+
+// Close releases the workers of the session, which must not be used anymore.
+func (s *Session) Close() {
+	s.pool.TearDown()
+}
+
+// PublicKey returns the public key of config, as a compressed SEC1 point.
+func PublicKey(config []byte) ([]byte, error) {
+	c, err := parseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return c.PublicPoint().MarshalBinary()
+}
+
+// Verify checks a signature returned by a signing session against a compressed public key.
+func Verify(publicKey, messageHash, signature []byte) bool {
+	group := curve.Secp256k1{}
+	X := group.NewPoint()
+	if X.UnmarshalBinary(publicKey) != nil || len(signature) != 65 || signature[64] > 1 {
+		return false
+	}
+	sig := ecdsa.EmptySignature(group)
+	if sig.R.UnmarshalBinary(append([]byte{signature[64] + 2}, signature[:32]...)) != nil ||
+		sig.S.UnmarshalBinary(signature[32:64]) != nil {
+		return false
+	}
+	return sig.Verify(X, messageHash)
+}
+
+func parseConfig(data []byte) (*cmp.Config, error) {
+	c := cmp.EmptyConfig(curve.Secp256k1{})
+	if err := c.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func parseIDs(list string) ([]party.ID, error) {
+	var ids []party.ID
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			return nil, errors.New("mobile: empty party ID")
+		}
+		ids = append(ids, party.ID(id))
+	}
+	return ids, nil
+}
@@ -0,0 +1,74 @@
+package mobile
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// run delivers the messages of sessions to each other until they are all done, and returns their results.
+func run(t *testing.T, sessions map[string]*Session) map[string][]byte {
+	for {
+		delivered := false
+		for from, s := range sessions {
+			for {
+				msg, err := s.Next()
+				require.NoError(t, err)
+				if msg == nil {
+					break
+				}
+				delivered = true
+				for to, other := range sessions {
+					if to != from && (msg.To == "" || msg.To == to) {
+						require.NoError(t, other.Accept(msg.Data))
+					}
+				}
+			}
+		}
+		if !delivered {
+			break
+		}
+	}
+	results := make(map[string][]byte, len(sessions))
+	for id, s := range sessions {
+		require.True(t, s.Done())
+		result, err := s.Result()
+		require.NoError(t, err, "party %s", id)
+		results[id] = result
+		s.Close()
+	}
+	return results
+}
+
+func TestSession(t *testing.T) {
+	parties := "a, b"
+	sessions := map[string]*Session{}
+	for _, id := range []string{"a", "b"} {
+		s, err := NewKeygen(id, parties, 1, []byte("keygen"))
+		require.NoError(t, err)
+		sessions[id] = s
+	}
+	configs := run(t, sessions)
+
+	publicKey, err := PublicKey(configs["a"])
+	require.NoError(t, err)
+	otherKey, err := PublicKey(configs["b"])
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, otherKey)
+
+	hash := sha256.Sum256([]byte("hello"))
+	for id, config := range configs {
+		s, err := NewSign(config, parties, hash[:], []byte("sign"))
+		require.NoError(t, err)
+		sessions[id] = s
+	}
+	signatures := run(t, sessions)
+	assert.Equal(t, signatures["a"], signatures["b"])
+	assert.True(t, Verify(publicKey, hash[:], signatures["a"]))
+	assert.False(t, Verify(publicKey, []byte("other"), signatures["a"]))
+
+	_, err = NewSign(configs["a"], "a,,b", hash[:], nil)
+	assert.Error(t, err)
+}
@@ -1,11 +1,23 @@
+// Package paillier implements the Paillier cryptosystem, with the homomorphic operations used by the protocols.
+//
+// Ciphertexts encrypt integers modulo N, which Dec returns in the range [-(N-1)/2, …, (N-1)/2].
+// Given ciphertexts of a and b, and an integer k, anyone holding the public key can compute ciphertexts
+// of a+b (Add), a-b (Sub), a+k (AddPlaintext) and k⋅a (Mul), and refresh the randomness of a ciphertext (Randomize).
+// These methods modify and return their receiver, and never fail, so ciphertexts received from
+// other parties must first be checked with PublicKey.ValidateCiphertexts, and must not overflow the plaintext space,
+// since results are only defined modulo N.
+// Use Clone to keep the original ciphertext.
+//
+// Ciphertexts obtained through the homomorphic operations reveal how they were computed,
+// unless they are randomized before being shared.
 package paillier
 
 import (
-	"crypto/rand"
 	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 )
 
@@ -26,6 +38,36 @@ func (ct *Ciphertext) Add(pk *PublicKey, ct2 *Ciphertext) *Ciphertext {
 	return ct
 }
 
+// Sub sets ct to the homomorphic difference ct ⊖ ct₂.
+// ct ← ct•ct₂⁻¹ (mod N²).
+//
+// ct₂ must be a valid ciphertext, invertible modulo N².
+func (ct *Ciphertext) Sub(pk *PublicKey, ct2 *Ciphertext) *Ciphertext {
+	if ct2 == nil {
+		return ct
+	}
+
+	inverse := new(saferith.Nat).ModInverse(ct2.c, pk.nSquared.Modulus)
+	ct.c.ModMul(ct.c, inverse, pk.nSquared.Modulus)
+
+	return ct
+}
+
+// AddPlaintext sets ct to the homomorphic sum of ct and the plaintext m, without randomizing the result.
+// ct ← ct•(1+N)ᵐ = ct•(1 + m•N) (mod N²).
+func (ct *Ciphertext) AddPlaintext(pk *PublicKey, m *saferith.Int) *Ciphertext {
+	if m == nil {
+		return ct
+	}
+
+	// (1+N)ᵐ = 1 + m•N mod N², as in EncWithNonce
+	c := new(saferith.Nat).ModMul(m.Mod(pk.n.Modulus), pk.nNat, pk.nSquared.Modulus)
+	c.ModAdd(c, new(saferith.Nat).SetUint64(1), pk.nSquared.Modulus)
+	ct.c.ModMul(ct.c, c, pk.nSquared.Modulus)
+
+	return ct
+}
+
 // Mul sets ct to the homomorphic multiplication of k ⊙ ct.
 // ct ← ctᵏ (mod N²).
 func (ct *Ciphertext) Mul(pk *PublicKey, k *saferith.Int) *Ciphertext {
@@ -54,9 +96,11 @@ func (ct Ciphertext) Clone() *Ciphertext {
 // ct ← ct ⋅ nonceᴺ (mod N²).
 // If nonce is nil, a random one is generated.
 // The receiver is updated, and the nonce update is returned.
+//
+// The plaintext is unchanged, but the result can't be linked to the original ciphertext without the secret key.
 func (ct *Ciphertext) Randomize(pk *PublicKey, nonce *saferith.Nat) *saferith.Nat {
 	if nonce == nil {
-		nonce = sample.UnitModN(rand.Reader, pk.n.Modulus)
+		nonce = sample.UnitModN(random.Reader(), pk.n.Modulus)
 	}
 	// c = c*r^N
 	tmp := pk.nSquared.Exp(nonce, pk.nNat)
@@ -69,7 +113,12 @@ func (ct *Ciphertext) WriteTo(w io.Writer) (int64, error) {
 	if ct == nil {
 		return 0, io.ErrUnexpectedEOF
 	}
-	buf := make([]byte, params.BytesCiphertext)
+	// ciphertexts of larger moduli than the default don't fit in BytesCiphertext
+	size := params.BytesCiphertext
+	if bytes := (ct.c.TrueLen() + 7) / 8; bytes > size {
+		size = bytes
+	}
+	buf := make([]byte, size)
 	ct.c.FillBytes(buf)
 	n, err := w.Write(buf)
 	return int64(n), err
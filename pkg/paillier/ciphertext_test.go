@@ -137,6 +137,40 @@ func TestEncDecScalingHomomorphic(t *testing.T) {
 	}
 }
 
+func testEncDecSubAddPlaintext(a, b, k uint64, aNeg, bNeg bool) bool {
+	ma := new(saferith.Int).SetUint64(a)
+	if aNeg {
+		ma.Neg(1)
+	}
+	mb := new(saferith.Int).SetUint64(b)
+	if bNeg {
+		mb.Neg(1)
+	}
+	kInt := new(saferith.Int).SetUint64(k)
+	ca, _ := paillierPublic.Enc(ma)
+	cb, _ := paillierPublic.Enc(mb)
+	// a - b + k, randomized
+	expected := new(saferith.Int).Add(new(saferith.Int).Add(ma, mb.Clone().Neg(1), -1), kInt, -1)
+	c := ca.Clone().Sub(paillierPublic, cb).AddPlaintext(paillierPublic, kInt)
+	before := c.Clone()
+	c.Randomize(paillierPublic, nil)
+	if c.Equal(before) {
+		return false
+	}
+	actual, err := paillierSecret.Dec(c)
+	if err != nil {
+		return false
+	}
+	return actual.Eq(expected) == 1 && paillierPublic.ValidateCiphertexts(c)
+}
+
+func TestEncDecSubAddPlaintext(t *testing.T) {
+	err := quick.Check(testEncDecSubAddPlaintext, &quick.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func testDecWithRandomness(x, r uint64) bool {
 	mExpected := new(saferith.Int).SetUint64(x)
 	nonceExpected := new(saferith.Nat).SetUint64(r)
@@ -0,0 +1,118 @@
+package paillier
+
+import (
+	"sync"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+// PrimePool generates safe primes suitable for Paillier in the background, and caches them
+// so that key generation only has to wait when the cache is empty.
+//
+// A PrimePool is safe for concurrent use, but every prime it returns is only ever returned once.
+type PrimePool struct {
+	profile SecurityProfile
+	primes  chan *saferith.Nat
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewPrimePool starts generating primes in the background, until capacity of them are cached.
+//
+// The primes are searched for using pl, which must not be used by anything else until TearDown
+// has returned. pl may be nil, in which case a single goroutine is used.
+func NewPrimePool(capacity int, pl *pool.Pool) *PrimePool {
+	return Profile2048.NewPrimePool(capacity, pl)
+}
+
+// NewPrimePool is like the package level NewPrimePool, but generates primes for the moduli of p.
+func (p SecurityProfile) NewPrimePool(capacity int, pl *pool.Pool) *PrimePool {
+	if capacity < 2 {
+		capacity = 2
+	}
+	pp := &PrimePool{
+		profile: p,
+		primes:  make(chan *saferith.Nat, capacity),
+		stop:    make(chan struct{}),
+	}
+	pp.wg.Add(1)
+	go pp.generate(pl)
+	return pp
+}
+
+// generate fills the cache, two primes at a time, until the pool is torn down.
+func (pp *PrimePool) generate(pl *pool.Pool) {
+	defer pp.wg.Done()
+	for {
+		select {
+		case <-pp.stop:
+			return
+		default:
+		}
+		p, q := sample.PaillierBits(random.Reader(), pl, pp.profile.BitsPaillier())
+		for _, prime := range []*saferith.Nat{p, q} {
+			select {
+			case pp.primes <- prime:
+			case <-pp.stop:
+				return
+			}
+		}
+	}
+}
+
+// Profile returns the profile of the moduli which the primes of the pool make up.
+func (pp *PrimePool) Profile() SecurityProfile {
+	return pp.profile
+}
+
+// Len returns the number of primes currently cached.
+func (pp *PrimePool) Len() int {
+	return len(pp.primes)
+}
+
+// Prime returns a cached safe prime, waiting for one to be generated if necessary.
+//
+// Once the pool has been torn down and the cache is empty, primes are generated on the calling goroutine instead.
+func (pp *PrimePool) Prime() *saferith.Nat {
+	select {
+	case prime := <-pp.primes:
+		return prime
+	case <-pp.stop:
+	}
+	select {
+	case prime := <-pp.primes:
+		return prime
+	default:
+	}
+	p, q := sample.PaillierBits(random.Reader(), nil, pp.profile.BitsPaillier())
+	// keep q for the next call, unless other callers filled the cache in the meantime
+	select {
+	case pp.primes <- q:
+	default:
+	}
+	return p
+}
+
+// NewSecretKey returns a SecretKey created from two cached primes.
+// It is equivalent to the package level NewSecretKey.
+func (pp *PrimePool) NewSecretKey() *SecretKey {
+	return NewSecretKeyFromPrimes(pp.Prime(), pp.Prime())
+}
+
+// KeyGen returns a PublicKey and SecretKey created from two cached primes.
+// It is equivalent to the package level KeyGen.
+func (pp *PrimePool) KeyGen() (pk *PublicKey, sk *SecretKey) {
+	sk = pp.NewSecretKey()
+	pk = sk.PublicKey
+	return
+}
+
+// TearDown stops the background generation, and waits for the current search to finish.
+// Primes which are already cached can still be retrieved afterwards.
+func (pp *PrimePool) TearDown() {
+	close(pp.stop)
+	pp.wg.Wait()
+}
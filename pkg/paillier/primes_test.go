@@ -0,0 +1,38 @@
+package paillier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrimePool(t *testing.T) {
+	pp := NewPrimePool(4, nil)
+
+	deadline := time.Now().Add(time.Minute)
+	for pp.Len() < 4 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, 4, pp.Len(), "cache should fill up in the background")
+
+	pk, sk := pp.KeyGen()
+	require.NoError(t, ValidatePrime(sk.P()))
+	require.NoError(t, ValidatePrime(sk.Q()))
+	assert.NotEqual(t, saferith.Choice(1), sk.P().Eq(sk.Q()))
+	require.NoError(t, ValidateN(pk.N()))
+
+	m := new(saferith.Int).SetUint64(42)
+	ct, _ := pk.Enc(m)
+	decrypted, err := sk.Dec(ct)
+	require.NoError(t, err)
+	assert.Equal(t, saferith.Choice(1), decrypted.Eq(m))
+
+	pp.TearDown()
+	// the cached primes are still available, and more are generated on demand
+	for i := 0; i < 3; i++ {
+		require.NoError(t, ValidatePrime(pp.Prime()))
+	}
+}
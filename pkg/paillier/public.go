@@ -1,13 +1,13 @@
 package paillier
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 )
@@ -54,16 +54,18 @@ func NewPublicKey(n *saferith.Modulus) *PublicKey {
 }
 
 // ValidateN performs basic checks to make sure the modulus is valid:
-// - log₂(n) = params.BitsPaillier.
+// - log₂(n) is the size of one of the SecurityProfile, by default params.BitsPaillier.
 // - n is odd.
+//
+// SecurityProfile.ValidateN also checks that n has the size of a given profile.
 func ValidateN(n *saferith.Modulus) error {
 	if n == nil {
 		return ErrPaillierNil
 	}
-	// log₂(N) = BitsPaillier
+	// log₂(N) ∈ {2048, 3072, 4096}
 	nBig := n.Big()
-	if bits := nBig.BitLen(); bits != params.BitsPaillier {
-		return fmt.Errorf("have: %d, need %d: %w", bits, params.BitsPaillier, ErrPaillierLength)
+	if bits := nBig.BitLen(); !params.IsValidBitsPaillier(bits) {
+		return fmt.Errorf("have: %d: %w", bits, ErrPaillierLength)
 	}
 	if nBig.Bit(0) != 1 {
 		return ErrPaillierEven
@@ -78,7 +80,7 @@ func ValidateN(n *saferith.Modulus) error {
 //
 // ct = (1+N)ᵐρᴺ (mod N²).
 func (pk PublicKey) Enc(m *saferith.Int) (*Ciphertext, *saferith.Nat) {
-	nonce := sample.UnitModN(rand.Reader, pk.n.Modulus)
+	nonce := sample.UnitModN(random.Reader(), pk.n.Modulus)
 	return pk.EncWithNonce(m, nonce), nonce
 }
 
@@ -96,8 +98,9 @@ func (pk PublicKey) EncWithNonce(m *saferith.Int, nonce *saferith.Nat) *Cipherte
 		panic("paillier.Encrypt: tried to encrypt message outside of range [-(N-1)/2, …, (N-1)/2]")
 	}
 
-	// (N+1)ᵐ mod N²
-	c := pk.nSquared.ExpI(pk.nPlusOne, m)
+	// (N+1)ᵐ = 1 + m•N mod N², by the binomial theorem, which avoids an exponentiation.
+	c := new(saferith.Nat).ModMul(m.Mod(pk.n.Modulus), pk.nNat, pk.nSquared.Modulus)
+	c.ModAdd(c, new(saferith.Nat).SetUint64(1), pk.nSquared.Modulus)
 	// ρᴺ mod N²
 	rhoN := pk.nSquared.Exp(nonce, pk.nNat)
 	// (N+1)ᵐ rho ^ N
@@ -1,12 +1,12 @@
 package paillier
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
@@ -65,7 +65,7 @@ func KeyGen(pl *pool.Pool) (pk *PublicKey, sk *SecretKey) {
 // NewSecretKey generates primes p and q suitable for the scheme, and returns the initialized SecretKey.
 func NewSecretKey(pl *pool.Pool) *SecretKey {
 	// TODO maybe we could take the reader as argument?
-	return NewSecretKeyFromPrimes(sample.Paillier(rand.Reader, pl))
+	return NewSecretKeyFromPrimes(sample.Paillier(random.Reader(), pl))
 }
 
 // NewSecretKeyFromPrimes generates a new SecretKey. Assumes that P and Q are prime.
@@ -149,14 +149,36 @@ func (sk *SecretKey) DecWithRandomness(ct *Ciphertext) (*saferith.Int, *saferith
 }
 
 func (sk SecretKey) GeneratePedersen() (*pedersen.Parameters, *saferith.Nat) {
-	s, t, lambda := sample.Pedersen(rand.Reader, sk.phi, sk.n.Modulus)
+	s, t, lambda := sample.Pedersen(random.Reader(), sk.phi, sk.n.Modulus)
 	ped := pedersen.New(sk.n, s, t)
 	return ped, lambda
 }
 
+// Validate checks that both primes of sk are suitable for Paillier, as per ValidatePrime, and that they differ.
+//
+// This is useful for keys which were generated externally, for example offline or in an HSM.
+func (sk *SecretKey) Validate() error {
+	if sk == nil {
+		return errors.New("paillier: nil secret key")
+	}
+	if err := ValidatePrime(sk.p); err != nil {
+		return fmt.Errorf("paillier: p: %w", err)
+	}
+	if err := ValidatePrime(sk.q); err != nil {
+		return fmt.Errorf("paillier: q: %w", err)
+	}
+	if sk.p.Eq(sk.q) == 1 {
+		return errors.New("paillier: p and q are equal")
+	}
+	if sk.p.TrueLen() != sk.q.TrueLen() {
+		return errors.New("paillier: p and q have different sizes")
+	}
+	return nil
+}
+
 // ValidatePrime checks whether p is a suitable prime for Paillier.
 // Checks:
-// - log₂(p) ≡ params.BitsBlumPrime.
+// - log₂(p) is half the size of the moduli of one of the SecurityProfile, by default params.BitsBlumPrime.
 // - p ≡ 3 (mod 4).
 // - q := (p-1)/2 is prime.
 func ValidatePrime(p *saferith.Nat) error {
@@ -164,11 +186,10 @@ func ValidatePrime(p *saferith.Nat) error {
 		return ErrPrimeNil
 	}
 	// check bit lengths
-	const bitsWant = params.BitsBlumPrime
 	// Technically, this leaks the number of bits, but this is fine, since returning
 	// an error asserts this number statically, anyways.
-	if bits := p.TrueLen(); bits != bitsWant {
-		return fmt.Errorf("invalid prime size: have: %d, need %d: %w", bits, bitsWant, ErrPrimeBadLength)
+	if bits := p.TrueLen(); !params.IsValidBitsPaillier(2 * bits) {
+		return fmt.Errorf("invalid prime size: have: %d: %w", bits, ErrPrimeBadLength)
 	}
 	// check == 3 (mod 4)
 	if p.Byte(0)&0b11 != 3 {
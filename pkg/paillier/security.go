@@ -0,0 +1,76 @@
+package paillier
+
+import (
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+// SecurityProfile determines the size of the Paillier moduli used in a protocol.
+//
+// The Pedersen parameters share these moduli, and the intervals sampled by the proofs over them
+// scale with their size, so a profile fixes all of them consistently.
+// The zero value is the default profile, Profile2048.
+type SecurityProfile int
+
+const (
+	// Profile2048 uses 2048-bit moduli, for about 112 bits of security.
+	Profile2048 SecurityProfile = 2048
+	// Profile3072 uses 3072-bit moduli, for about 128 bits of security.
+	Profile3072 SecurityProfile = 3072
+	// Profile4096 uses 4096-bit moduli, for a margin above 128 bits of security.
+	Profile4096 SecurityProfile = 4096
+)
+
+// BitsPaillier returns the bit length of the moduli N of p.
+func (p SecurityProfile) BitsPaillier() int {
+	if p == 0 {
+		return params.BitsPaillier
+	}
+	return int(p)
+}
+
+// BitsBlumPrime returns the bit length of the prime factors of the moduli of p.
+func (p SecurityProfile) BitsBlumPrime() int {
+	return p.BitsPaillier() / 2
+}
+
+// Validate returns an error if p isn't one of the supported profiles.
+func (p SecurityProfile) Validate() error {
+	if !params.IsValidBitsPaillier(p.BitsPaillier()) {
+		return fmt.Errorf("paillier: unsupported security profile of %d bits", int(p))
+	}
+	return nil
+}
+
+// ValidateN checks n as the package level ValidateN does, and that it has exactly the size of p.
+func (p SecurityProfile) ValidateN(n *saferith.Modulus) error {
+	if err := ValidateN(n); err != nil {
+		return err
+	}
+	if bits := n.BitLen(); bits != p.BitsPaillier() {
+		return fmt.Errorf("have: %d, need %d: %w", bits, p.BitsPaillier(), ErrPaillierLength)
+	}
+	return nil
+}
+
+// NewSecretKey generates primes p and q of the size of the profile, and returns the initialized SecretKey.
+func (p SecurityProfile) NewSecretKey(pl *pool.Pool) *SecretKey {
+	return NewSecretKeyFromPrimes(sample.PaillierBits(random.Reader(), pl, p.BitsPaillier()))
+}
+
+// ProfileOf returns the profile whose moduli have the size of n.
+func ProfileOf(n *saferith.Modulus) (SecurityProfile, error) {
+	if n == nil {
+		return 0, ErrPaillierNil
+	}
+	p := SecurityProfile(n.BitLen())
+	if err := p.Validate(); err != nil {
+		return 0, fmt.Errorf("%v: %w", err, ErrPaillierLength)
+	}
+	return p, nil
+}
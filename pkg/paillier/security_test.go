@@ -0,0 +1,54 @@
+package paillier
+
+import (
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+// oddModulus returns an odd modulus of the given bit length.
+func oddModulus(bits int) *saferith.Modulus {
+	n := new(saferith.Nat).Lsh(new(saferith.Nat).SetUint64(1), uint(bits-1), -1)
+	return saferith.ModulusFromNat(n.Add(n, new(saferith.Nat).SetUint64(1), -1))
+}
+
+func TestSecurityProfile(t *testing.T) {
+	var zero SecurityProfile
+	assert.Equal(t, 2048, zero.BitsPaillier())
+	assert.Equal(t, 1536, Profile3072.BitsBlumPrime())
+	assert.NoError(t, zero.Validate())
+	assert.Error(t, SecurityProfile(1024).Validate())
+
+	for _, profile := range []SecurityProfile{Profile2048, Profile3072, Profile4096} {
+		n := oddModulus(profile.BitsPaillier())
+		assert.NoError(t, ValidateN(n))
+		assert.NoError(t, profile.ValidateN(n))
+		actual, err := ProfileOf(n)
+		require.NoError(t, err)
+		assert.Equal(t, profile, actual)
+	}
+	assert.ErrorIs(t, Profile3072.ValidateN(oddModulus(2048)), ErrPaillierLength)
+	assert.ErrorIs(t, ValidateN(oddModulus(2560)), ErrPaillierLength)
+	_, err := ProfileOf(oddModulus(1024))
+	assert.ErrorIs(t, err, ErrPaillierLength)
+}
+
+func TestSecurityProfileKeyGen(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generating 1536-bit safe primes is slow")
+	}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	sk := Profile3072.NewSecretKey(pl)
+	require.NoError(t, sk.Validate())
+	assert.NoError(t, Profile3072.ValidateN(sk.N()))
+
+	ct, _ := sk.Enc(new(saferith.Int).SetUint64(42))
+	m, err := sk.Dec(ct)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), m.Abs().Uint64())
+}
@@ -0,0 +1,204 @@
+package threshold
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+)
+
+// DecryptionShare is the contribution of a single party to the decryption of a ciphertext.
+type DecryptionShare struct {
+	// Index is the index of the SecretShare used to create this share.
+	Index int
+	// Value cᵢ = c^{2Δsᵢ} mod N².
+	Value *saferith.Nat
+	// Proof shows that Value was computed with the same sᵢ as the party's verification key.
+	Proof *Proof
+}
+
+// Proof is a non-interactive proof of equality of discrete logarithms, showing that
+// log_{c⁴}(cᵢ²) = log_v(vᵢ) = Δsᵢ.
+type Proof struct {
+	// A = (c⁴)ʳ, B = vʳ mod N².
+	A, B *saferith.Nat
+	// Z = r + e•Δsᵢ, computed over the integers.
+	Z *saferith.Nat
+}
+
+// Decrypt produces this party's DecryptionShare of ct.
+func (s *SecretShare) Decrypt(pk *PublicKey, ct *paillier.Ciphertext) (*DecryptionShare, error) {
+	if !pk.Paillier().ValidateCiphertexts(ct) {
+		return nil, errors.New("threshold: invalid ciphertext")
+	}
+	if _, ok := pk.VerificationKeys[s.Index]; !ok {
+		return nil, fmt.Errorf("threshold: share %d is not part of the public key", s.Index)
+	}
+	NSquared := pk.Paillier().ModulusSquared()
+	// w = Δsᵢ
+	w := new(saferith.Nat).Mul(pk.delta(), s.Share, -1)
+	value := NSquared.Exp(ct.Nat(), new(saferith.Nat).Lsh(w, 1, -1))
+
+	base := fourth(NSquared.Modulus, ct.Nat())
+	r := mustSampleBits(pk.proofBits())
+	proof := &Proof{
+		A: NSquared.Exp(base, r),
+		B: NSquared.Exp(pk.V, r),
+	}
+	e := challenge(pk, ct, s.Index, value, proof.A, proof.B)
+	proof.Z = new(saferith.Nat).Mul(e, w, -1)
+	proof.Z.Add(proof.Z, r, -1)
+
+	return &DecryptionShare{
+		Index: s.Index,
+		Value: value,
+		Proof: proof,
+	}, nil
+}
+
+// VerifyShare checks that share is a valid decryption share of ct.
+func (pk *PublicKey) VerifyShare(ct *paillier.Ciphertext, share *DecryptionShare) bool {
+	if share == nil || share.Value == nil || share.Proof == nil {
+		return false
+	}
+	p := share.Proof
+	if p.A == nil || p.B == nil || p.Z == nil {
+		return false
+	}
+	vi, ok := pk.VerificationKeys[share.Index]
+	if !ok {
+		return false
+	}
+	if !pk.Paillier().ValidateCiphertexts(ct) {
+		return false
+	}
+	NSquared := pk.Paillier().ModulusSquared()
+	for _, x := range []*saferith.Nat{share.Value, p.A, p.B} {
+		if x.IsUnit(NSquared.Modulus) != 1 {
+			return false
+		}
+	}
+	// z is the sum of r and e•Δsᵢ, which can't be larger than twice the size of r
+	if p.Z.TrueLen() > pk.proofBits()+1 {
+		return false
+	}
+
+	e := challenge(pk, ct, share.Index, share.Value, p.A, p.B)
+
+	// (c⁴)ᶻ = A • (cᵢ²)ᵉ
+	lhs := NSquared.Exp(fourth(NSquared.Modulus, ct.Nat()), p.Z)
+	valueSquared := new(saferith.Nat).ModMul(share.Value, share.Value, NSquared.Modulus)
+	rhs := NSquared.Exp(valueSquared, e)
+	rhs.ModMul(rhs, p.A, NSquared.Modulus)
+	if lhs.Eq(rhs) != 1 {
+		return false
+	}
+
+	// vᶻ = B • vᵢᵉ
+	lhs = NSquared.Exp(pk.V, p.Z)
+	rhs = NSquared.Exp(vi, e)
+	rhs.ModMul(rhs, p.B, NSquared.Modulus)
+	return lhs.Eq(rhs) == 1
+}
+
+// Combine recovers the plaintext of ct from the decryption shares of at least Threshold + 1 parties.
+// Every share is verified, and the plaintext is returned in ± (N-2)/2, like paillier.SecretKey.Dec.
+func (pk *PublicKey) Combine(ct *paillier.Ciphertext, shares []*DecryptionShare) (*saferith.Int, error) {
+	if len(shares) < pk.Threshold+1 {
+		return nil, fmt.Errorf("threshold: got %d shares, need %d", len(shares), pk.Threshold+1)
+	}
+	indices := make([]int, 0, pk.Threshold+1)
+	seen := make(map[int]bool, len(shares))
+	for _, share := range shares {
+		if share == nil {
+			return nil, errors.New("threshold: nil share")
+		}
+		if seen[share.Index] {
+			return nil, fmt.Errorf("threshold: duplicate share %d", share.Index)
+		}
+		if !pk.VerifyShare(ct, share) {
+			return nil, fmt.Errorf("threshold: invalid share %d", share.Index)
+		}
+		seen[share.Index] = true
+		indices = append(indices, share.Index)
+	}
+	// only the first Threshold + 1 shares are needed
+	indices = indices[:pk.Threshold+1]
+
+	NSquared := pk.Paillier().ModulusSquared()
+	delta := new(big.Int).MulRange(1, int64(pk.Parties))
+
+	// c' = ∏ cᵢ^{2λᵢ} = c^{4Δ²d} mod N²
+	result := new(saferith.Nat).SetUint64(1)
+	for k, i := range indices {
+		lambda := lagrange(delta, indices, i)
+		lambda.Lsh(lambda, 1)
+		exponent := new(saferith.Int).SetBig(lambda, lambda.BitLen())
+		result.ModMul(result, NSquared.ExpI(shares[k].Value, exponent), NSquared.Modulus)
+	}
+
+	// m = L(c') • (4Δ²)⁻¹ mod N
+	n := pk.N
+	result.Sub(result, new(saferith.Nat).SetUint64(1), -1)
+	result.Div(result, n, -1)
+	scale := new(big.Int).Mul(delta, delta)
+	scale.Lsh(scale, 2)
+	scaleInv := new(saferith.Nat).SetBig(scale, scale.BitLen())
+	scaleInv.ModInverse(scaleInv, n)
+	result.ModMul(result, scaleInv, n)
+	return new(saferith.Int).SetModSymmetric(result, n), nil
+}
+
+// lagrange returns the integer λᵢ = Δ • ∏ⱼ j/(j-i), for j ≠ i in indices.
+func lagrange(delta *big.Int, indices []int, i int) *big.Int {
+	num := new(big.Int).Set(delta)
+	den := big.NewInt(1)
+	for _, j := range indices {
+		if j == i {
+			continue
+		}
+		num.Mul(num, big.NewInt(int64(j)))
+		den.Mul(den, big.NewInt(int64(j-i)))
+	}
+	// Δ = n! is divisible by the denominator, so this is exact
+	return num.Quo(num, den)
+}
+
+// proofBits is the size of the nonce r used in Proof.
+// It hides e•Δsᵢ, where e has SecParam bits and sᵢ < N² bits, up to a statistical distance of 2^-StatParam.
+func (pk *PublicKey) proofBits() int {
+	return 2*pk.N.BitLen() + pk.delta().TrueLen() + params.SecParam + params.StatParam
+}
+
+// challenge computes the Fiat-Shamir challenge e ∈ [0, 2^SecParam) for a Proof.
+func challenge(pk *PublicKey, ct *paillier.Ciphertext, index int, values ...*saferith.Nat) *saferith.Nat {
+	h := hash.New()
+	_ = h.WriteAny(pk.N, pk.V, pk.VerificationKeys[index], ct)
+	for _, v := range values {
+		_ = h.WriteAny(v)
+	}
+	buf := make([]byte, params.SecBytes)
+	_, _ = h.Digest().Read(buf)
+	return new(saferith.Nat).SetBytes(buf)
+}
+
+// fourth returns x⁴ mod n.
+func fourth(n *saferith.Modulus, x *saferith.Nat) *saferith.Nat {
+	out := new(saferith.Nat).ModMul(x, x, n)
+	return out.ModMul(out, out, n)
+}
+
+func mustSampleBits(bits int) *saferith.Nat {
+	buf := make([]byte, (bits+7)/8)
+	if _, err := random.Read(buf); err != nil {
+		panic(fmt.Sprintf("threshold: failed to read from random: %v", err))
+	}
+	// clear the excess bits
+	buf[0] &= 0xff >> (8*len(buf) - bits)
+	return new(saferith.Nat).SetBytes(buf)
+}
@@ -0,0 +1,147 @@
+// Package threshold implements a threshold variant of the Paillier cryptosystem,
+// following Shoup's RSA threshold scheme as adapted by Damgård and Jurik:
+// https://www.brics.dk/RS/00/45/BRICS-RS-00-45.pdf.
+//
+// Ciphertexts are regular Paillier ciphertexts, created with PublicKey.Paillier,
+// but decrypting them requires the decryption shares of Threshold + 1 parties.
+// Each share comes with a proof of correctness, so that invalid shares can be attributed.
+//
+// Keys are created by a trusted dealer with Deal. Generating the modulus without
+// a dealer requires a distributed biprime generation protocol, which is not provided here.
+package threshold
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+// PublicKey is the public part of a threshold Paillier key.
+type PublicKey struct {
+	// N is the Paillier modulus.
+	N *saferith.Modulus
+	// Parties is the number n of shares of the decryption key.
+	Parties int
+	// Threshold is the maximum number of parties that can't decrypt together.
+	Threshold int
+	// V is a random square in ℤ/N²ℤ.
+	V *saferith.Nat
+	// VerificationKeys maps the index i of each party to vᵢ = V^{Δsᵢ} mod N², where Δ = n!.
+	VerificationKeys map[int]*saferith.Nat
+}
+
+// SecretShare is the share of the decryption key held by a single party.
+type SecretShare struct {
+	// Index is the index of this share, between 1 and PublicKey.Parties.
+	Index int
+	// Share sᵢ = f(i), where f is a polynomial of degree Threshold over ℤ/Nmℤ.
+	Share *saferith.Nat
+}
+
+// Paillier returns the public key used to encrypt messages.
+func (pk *PublicKey) Paillier() *paillier.PublicKey {
+	return paillier.NewPublicKey(pk.N)
+}
+
+// Deal generates a new threshold Paillier key, split into the given number of parties,
+// such that any threshold + 1 of them can decrypt.
+//
+// This corresponds to the key generation of section 4 in the paper:
+// with N = pq, p = 2p' + 1, q = 2q' + 1 and m = p'q', the decryption key d
+// satisfies d ≡ 0 (mod m), and d ≡ 1 (mod N), and is Shamir shared over ℤ/Nmℤ.
+//
+// The dealer learns the factorization of N, and must erase it after distributing the shares.
+func Deal(parties, threshold int, pl *pool.Pool) (*PublicKey, []*SecretShare, error) {
+	if parties <= 0 || threshold < 0 || threshold >= parties {
+		return nil, nil, fmt.Errorf("threshold.Deal: invalid threshold %d for %d parties", threshold, parties)
+	}
+
+	p, q := sample.Paillier(random.Reader(), pl)
+	N := new(saferith.Nat).Mul(p, q, -1)
+	NModulus := saferith.ModulusFromNat(N)
+	// m = p'q' = (p-1)/2 • (q-1)/2
+	pPrime := new(saferith.Nat).Rsh(p, 1, -1)
+	qPrime := new(saferith.Nat).Rsh(q, 1, -1)
+	m := new(saferith.Nat).Mul(pPrime, qPrime, -1)
+	NmModulus := saferith.ModulusFromNat(new(saferith.Nat).Mul(N, m, -1))
+
+	// d = m • (m⁻¹ mod N)
+	mInv := new(saferith.Nat).ModInverse(m, NModulus)
+	d := new(saferith.Nat).ModMul(m, mInv, NmModulus)
+
+	// f(X) = d + a₁X + … + aₜXᵗ
+	coefficients := make([]*saferith.Nat, threshold+1)
+	coefficients[0] = d
+	for k := 1; k <= threshold; k++ {
+		coefficients[k] = sample.ModN(random.Reader(), NmModulus)
+	}
+
+	pk := &PublicKey{
+		N:                NModulus,
+		Parties:          parties,
+		Threshold:        threshold,
+		VerificationKeys: make(map[int]*saferith.Nat, parties),
+	}
+	paillierPublic := pk.Paillier()
+	NSquared := paillierPublic.ModulusSquared()
+	r := sample.UnitModN(random.Reader(), NSquared.Modulus)
+	pk.V = new(saferith.Nat).ModMul(r, r, NSquared.Modulus)
+
+	delta := factorial(parties)
+	shares := make([]*SecretShare, 0, parties)
+	for i := 1; i <= parties; i++ {
+		// Horner's method
+		x := new(saferith.Nat).SetUint64(uint64(i))
+		s := new(saferith.Nat).SetNat(coefficients[threshold])
+		for k := threshold - 1; k >= 0; k-- {
+			s.ModMul(s, x, NmModulus)
+			s.ModAdd(s, coefficients[k], NmModulus)
+		}
+		shares = append(shares, &SecretShare{Index: i, Share: s})
+		pk.VerificationKeys[i] = NSquared.Exp(pk.V, new(saferith.Nat).Mul(delta, s, -1))
+	}
+	return pk, shares, nil
+}
+
+// Validate checks that the public key is well formed.
+func (pk *PublicKey) Validate() error {
+	if pk == nil || pk.N == nil || pk.V == nil {
+		return errors.New("threshold: nil public key")
+	}
+	if err := paillier.ValidateN(pk.N); err != nil {
+		return fmt.Errorf("threshold: %w", err)
+	}
+	if pk.Parties <= 0 || pk.Threshold < 0 || pk.Threshold >= pk.Parties {
+		return fmt.Errorf("threshold: invalid threshold %d for %d parties", pk.Threshold, pk.Parties)
+	}
+	if len(pk.VerificationKeys) != pk.Parties {
+		return errors.New("threshold: wrong number of verification keys")
+	}
+	NSquared := pk.Paillier().ModulusSquared().Modulus
+	if pk.V.IsUnit(NSquared) != 1 {
+		return errors.New("threshold: invalid V")
+	}
+	for i := 1; i <= pk.Parties; i++ {
+		vi, ok := pk.VerificationKeys[i]
+		if !ok || vi == nil || vi.IsUnit(NSquared) != 1 {
+			return fmt.Errorf("threshold: invalid verification key for party %d", i)
+		}
+	}
+	return nil
+}
+
+// delta returns Δ = n!, which clears the denominators of the Lagrange coefficients.
+func (pk *PublicKey) delta() *saferith.Nat {
+	return factorial(pk.Parties)
+}
+
+func factorial(n int) *saferith.Nat {
+	out := new(big.Int).MulRange(1, int64(n))
+	return new(saferith.Nat).SetBig(out, out.BitLen())
+}
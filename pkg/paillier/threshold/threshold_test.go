@@ -0,0 +1,72 @@
+package threshold
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+func TestThreshold(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N, T := 5, 2
+	pk, shares, err := Deal(N, T, pl)
+	require.NoError(t, err)
+	require.NoError(t, pk.Validate())
+	require.Len(t, shares, N)
+
+	m := new(saferith.Int).SetModSymmetric(sample.ModN(rand.Reader, pk.N), pk.N)
+	ct, _ := pk.Paillier().Enc(m)
+
+	decryptionShares := make([]*DecryptionShare, 0, N)
+	for _, s := range shares {
+		share, err := s.Decrypt(pk, ct)
+		require.NoError(t, err)
+		assert.True(t, pk.VerifyShare(ct, share), "valid share should verify")
+		decryptionShares = append(decryptionShares, share)
+	}
+
+	// any T+1 shares decrypt
+	for _, subset := range [][]*DecryptionShare{
+		decryptionShares[:T+1],
+		decryptionShares[N-T-1:],
+		{decryptionShares[4], decryptionShares[0], decryptionShares[2]},
+		decryptionShares,
+	} {
+		result, err := pk.Combine(ct, subset)
+		require.NoError(t, err)
+		assert.Equal(t, saferith.Choice(1), result.Eq(m), "decrypted message should match")
+	}
+
+	_, err = pk.Combine(ct, decryptionShares[:T])
+	assert.Error(t, err, "T shares should not be enough")
+
+	_, err = pk.Combine(ct, []*DecryptionShare{decryptionShares[0], decryptionShares[0], decryptionShares[1]})
+	assert.Error(t, err, "duplicate shares should be rejected")
+
+	// a share for a different ciphertext is detected
+	other, _ := pk.Paillier().Enc(new(saferith.Int).SetUint64(1))
+	otherShare, err := shares[1].Decrypt(pk, other)
+	require.NoError(t, err)
+	assert.False(t, pk.VerifyShare(ct, otherShare), "share of a different ciphertext should not verify")
+
+	// a tampered share is detected
+	bad := *decryptionShares[0]
+	bad.Value = new(saferith.Nat).ModMul(bad.Value, bad.Value, pk.Paillier().ModulusSquared().Modulus)
+	assert.False(t, pk.VerifyShare(ct, &bad), "tampered share should not verify")
+	_, err = pk.Combine(ct, []*DecryptionShare{&bad, decryptionShares[1], decryptionShares[2]})
+	assert.Error(t, err, "tampered share should be rejected")
+}
+
+func TestDealInvalid(t *testing.T) {
+	_, _, err := Deal(3, 3, nil)
+	assert.Error(t, err)
+	_, _, err = Deal(0, 0, nil)
+	assert.Error(t, err)
+}
@@ -1,11 +1,14 @@
 package party
 
 import (
+	"encoding/hex"
 	"errors"
 	"io"
+	"strings"
 
 	"github.com/cronokirby/saferith"
 	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 )
 
@@ -14,15 +17,56 @@ import (
 // You should think of this as a 32 byte slice. We represent it as a string
 // to have a comparable type, but using more than 32 bytes will lead to inconsistencies
 // because of how we use this ID numerically later.
+// Identifiers which are not human-readable strings, such as hashes of public keys,
+// should be wrapped with BinaryID instead.
 //
 // This ID is used as an interpolation point of a polynomial sharing of the secret key.
 type ID string
 
+// BinaryIDLen is the length of the identifiers wrapped by BinaryID.
+const BinaryIDLen = 32
+
+// binaryIDPrefix starts the IDs created by BinaryID.
+// Other IDs of the same length can't start with it, which printable strings never do.
+const binaryIDPrefix = "\x00"
+
+// BinaryID returns the ID of a participant identified by an opaque 32 byte string,
+// such as the hash of its public key.
+//
+// Its Scalar is obtained by hashing b, so the scalars of different binary IDs only collide
+// with negligible probability, which NewSession checks anyway.
+func BinaryID(b [BinaryIDLen]byte) ID {
+	return ID(binaryIDPrefix + string(b[:]))
+}
+
+// Binary returns the identifier wrapped by BinaryID, and false if id wasn't created by BinaryID.
+func (id ID) Binary() (b [BinaryIDLen]byte, ok bool) {
+	if len(id) != len(binaryIDPrefix)+BinaryIDLen || !strings.HasPrefix(string(id), binaryIDPrefix) {
+		return b, false
+	}
+	copy(b[:], id[len(binaryIDPrefix):])
+	return b, true
+}
+
+// String implements fmt.Stringer, and returns binary IDs in hexadecimal.
+func (id ID) String() string {
+	if b, ok := id.Binary(); ok {
+		return "0x" + hex.EncodeToString(b[:])
+	}
+	return string(id)
+}
+
 // Scalar converts this ID into a scalar.
 //
 // All of the IDs of our participants form a polynomial sharing of the secret
 // scalar value used for ECDSA.
 func (id ID) Scalar(group curve.Curve) curve.Scalar {
+	if b, ok := id.Binary(); ok {
+		// reducing 64 bytes makes the bias of the scalar negligible in all our groups
+		digest := make([]byte, 64)
+		_, _ = hash.New(hash.BytesWithDomain{TheDomain: "Binary Party ID", Bytes: b[:]}).Digest().Read(digest)
+		return group.NewScalar().SetNat(new(saferith.Nat).SetBytes(digest))
+	}
 	return group.NewScalar().SetNat(new(saferith.Nat).SetBytes([]byte(id)))
 }
 
@@ -69,6 +113,9 @@ func EmptyPointMap(group curve.Curve) *PointMap {
 	return &PointMap{group: group}
 }
 
+// pointMapEncMode sorts the map, so that equal PointMaps have the same encoding.
+var pointMapEncMode, _ = cbor.CanonicalEncOptions().EncMode()
+
 func (m *PointMap) MarshalBinary() ([]byte, error) {
 	pointBytes := make(map[ID]cbor.RawMessage, len(m.Points))
 	var err error
@@ -78,7 +125,7 @@ func (m *PointMap) MarshalBinary() ([]byte, error) {
 			return nil, err
 		}
 	}
-	return cbor.Marshal(pointBytes)
+	return pointMapEncMode.Marshal(pointBytes)
 }
 
 func (m *PointMap) UnmarshalBinary(data []byte) error {
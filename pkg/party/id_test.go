@@ -0,0 +1,37 @@
+package party_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+func TestBinaryID(t *testing.T) {
+	group := curve.Secp256k1{}
+	var a, b [party.BinaryIDLen]byte
+	b[31] = 1
+	idA, idB := party.BinaryID(a), party.BinaryID(b)
+
+	decoded, ok := idB.Binary()
+	assert.True(t, ok)
+	assert.Equal(t, b, decoded)
+	_, ok = party.ID("b").Binary()
+	assert.False(t, ok)
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000001", idB.String())
+	assert.Equal(t, "b", party.ID("b").String())
+
+	// the zero identifier is hashed, instead of being used as the scalar 0
+	assert.False(t, idA.Scalar(group).IsZero())
+	assert.False(t, idA.Scalar(group).Equal(idB.Scalar(group)))
+	assert.NoError(t, party.NewIDSlice([]party.ID{idA, idB, "c"}).CheckScalars(group))
+}
+
+func TestCheckScalars(t *testing.T) {
+	group := curve.Secp256k1{}
+	assert.NoError(t, party.NewIDSlice([]party.ID{"a", "b", "c"}).CheckScalars(group))
+	// leading zeros don't change the scalar of an ID
+	assert.Error(t, party.NewIDSlice([]party.ID{"a", "\x00a"}).CheckScalars(group))
+	assert.Error(t, party.NewIDSlice([]party.ID{"\x00", "b"}).CheckScalars(group))
+}
@@ -2,9 +2,12 @@ package party
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
 	"sort"
 	"strings"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 )
 
 type IDSlice []ID
@@ -38,6 +41,27 @@ func (partyIDs IDSlice) Valid() bool {
 	return true
 }
 
+// CheckScalars returns an error if the Scalar of one of the IDs in group is 0, or is the same as another's,
+// since the IDs couldn't be used as distinct interpolation points.
+func (partyIDs IDSlice) CheckScalars(group curve.Curve) error {
+	seen := make(map[string]ID, len(partyIDs))
+	for _, id := range partyIDs {
+		scalar := id.Scalar(group)
+		if scalar.IsZero() {
+			return fmt.Errorf("party: the scalar of ID %s is 0", id)
+		}
+		data, err := scalar.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if other, ok := seen[string(data)]; ok && other != id {
+			return fmt.Errorf("party: IDs %s and %s have the same scalar", other, id)
+		}
+		seen[string(data)] = id
+	}
+	return nil
+}
+
 // Copy returns an identical copy of the received.
 func (partyIDs IDSlice) Copy() IDSlice {
 	a := make(IDSlice, len(partyIDs))
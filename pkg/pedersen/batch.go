@@ -0,0 +1,82 @@
+package pedersen
+
+import (
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
+)
+
+// Equation is a single check sᵃ tᵇ ≡ S Tᵉ (mod N) performed by Parameters.Verify,
+// which can be verified together with others using VerifyBatch.
+type Equation struct {
+	Params  *Parameters
+	A, B, E *saferith.Int
+	S, T    *saferith.Nat
+}
+
+// VerifyBatch returns true if all equations hold, checking a single random linear combination
+// of the equations sharing the same Parameters.
+//
+// Each equation is raised to 2ρ, for a random ρ of StatParam bits.
+// The factor 2 removes the elements of order 2, which are the only elements of small order
+// when N is a product of safe primes, and could otherwise be used to make two invalid equations cancel out.
+// As a consequence, equations are only checked up to such a factor, which is also the extent to which
+// the commitments are binding.
+func VerifyBatch(equations []Equation) bool {
+	batches := map[*Parameters][]Equation{}
+	for _, eq := range equations {
+		if eq.Params == nil || eq.A == nil || eq.B == nil || eq.E == nil || eq.S == nil || eq.T == nil {
+			return false
+		}
+		if !arith.IsValidNatModN(eq.Params.n.Modulus, eq.S, eq.T) {
+			return false
+		}
+		batches[eq.Params] = append(batches[eq.Params], eq)
+	}
+	for p, batch := range batches {
+		if len(batch) == 1 {
+			eq := batch[0]
+			if !p.Verify(eq.A, eq.B, eq.E, eq.S, eq.T) {
+				return false
+			}
+			continue
+		}
+		if !p.verifyBatch(batch) {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyBatch checks s^{∑2ρᵢaᵢ} t^{∑2ρᵢbᵢ} ≡ ∏ (Sᵢ Tᵢ^{eᵢ})^{2ρᵢ} (mod N).
+func (p Parameters) verifyBatch(equations []Equation) bool {
+	nMod := p.n.Modulus
+	a, b := new(saferith.Int), new(saferith.Int)
+	rhs := new(saferith.Nat).SetUint64(1)
+	for _, eq := range equations {
+		rho := sampleRho()
+
+		a.Add(a, new(saferith.Int).Mul(rho, eq.A, -1), -1)
+		b.Add(b, new(saferith.Int).Mul(rho, eq.B, -1), -1)
+
+		rhoE := new(saferith.Int).Mul(rho, eq.E, -1)
+		rhs.ModMul(rhs, p.n.ExpI(eq.S, rho), nMod)
+		rhs.ModMul(rhs, p.n.ExpI(eq.T, rhoE), nMod)
+	}
+	lhs := p.n.ExpI(p.s, a)
+	lhs.ModMul(lhs, p.n.ExpI(p.t, b), nMod)
+	return lhs.Eq(rhs) == 1
+}
+
+// sampleRho returns 2ρ, for a uniform ρ ∈ [0, 2^StatParam).
+func sampleRho() *saferith.Int {
+	buf := make([]byte, (params.StatParam+7)/8)
+	if _, err := random.Read(buf); err != nil {
+		panic("pedersen: failed to read from random: " + err.Error())
+	}
+	buf[0] &= 0xff >> (8*len(buf) - params.StatParam)
+	rho := new(saferith.Nat).SetBytes(buf)
+	rho.Lsh(rho, 1, -1)
+	return new(saferith.Int).SetNat(rho)
+}
@@ -1,10 +1,14 @@
 package pedersen
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 
 	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 )
@@ -15,6 +19,10 @@ const (
 	ErrNilFields    Error = "contains nil field"
 	ErrSEqualT      Error = "S cannot be equal to T"
 	ErrNotValidModN Error = "S and T must be in [1,…,N-1] and coprime to N"
+	ErrModulus      Error = "N must be an odd composite of the size of a Paillier modulus"
+	ErrTrivial      Error = "S and T cannot be ±1 mod N"
+	ErrNotResidue   Error = "S and T must be quadratic residues mod N"
+	ErrOrder        Error = "T must generate the quadratic residues mod N"
 )
 
 func (e Error) Error() string {
@@ -56,6 +64,69 @@ func ValidateParameters(n *saferith.Modulus, s, t *saferith.Nat) error {
 	return nil
 }
 
+// Validate performs every check on the parameters which doesn't require the factorization of N,
+// in addition to those of ValidateParameters:
+//   - N has the size of a Paillier modulus, and is odd and composite.
+//   - s, t ≠ ±1 (mod N).
+//   - the Jacobi symbols of s and t are 1, as they are for quadratic residues.
+//
+// This doesn't prove that s belongs to the subgroup generated by t, which parameters received
+// from another party must come with a proof of, such as zkprm.
+// The owner of the parameters can check it with ValidateWithPrimes.
+func (p *Parameters) Validate() error {
+	if p == nil || p.n == nil {
+		return ErrNilFields
+	}
+	if err := ValidateParameters(p.n.Modulus, p.s, p.t); err != nil {
+		return err
+	}
+	n := p.n.Big()
+	if !params.IsValidBitsPaillier(n.BitLen()) || n.Bit(0) == 0 || n.ProbablyPrime(20) {
+		return ErrModulus
+	}
+	nMinusOne := new(big.Int).Sub(n, big.NewInt(1))
+	for _, x := range []*big.Int{p.s.Big(), p.t.Big()} {
+		if x.Cmp(big.NewInt(1)) == 0 || x.Cmp(nMinusOne) == 0 {
+			return ErrTrivial
+		}
+		if big.Jacobi(x, n) != 1 {
+			return ErrNotResidue
+		}
+	}
+	return nil
+}
+
+// ValidateWithPrimes checks the parameters with the factorization N = P•Q, where P = 2P'+1 and Q = 2Q'+1 are safe primes,
+// in addition to Validate:
+//   - s and t are quadratic residues mod P and Q.
+//   - t has order P'•Q', and thus generates the quadratic residues mod N, of which s is one.
+func (p *Parameters) ValidateWithPrimes(P, Q *saferith.Nat) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	pBig, qBig := P.Big(), Q.Big()
+	if new(big.Int).Mul(pBig, qBig).Cmp(p.n.Big()) != 0 {
+		return errors.New("pedersen: N is not the product of the given primes")
+	}
+	one := big.NewInt(1)
+	// P' = (P-1)/2, Q' = (Q-1)/2
+	pHalf := new(big.Int).Rsh(pBig, 1)
+	qHalf := new(big.Int).Rsh(qBig, 1)
+	var tmp big.Int
+	for _, x := range []*big.Int{p.s.Big(), p.t.Big()} {
+		// Euler's criterion
+		if tmp.Exp(x, pHalf, pBig).Cmp(one) != 0 || tmp.Exp(x, qHalf, qBig).Cmp(one) != 0 {
+			return ErrNotResidue
+		}
+	}
+	// the order of t divides P'•Q', so it is P'•Q' if t^P' ≠ 1 and t^Q' ≠ 1
+	n, t := p.n.Big(), p.t.Big()
+	if tmp.Exp(t, pHalf, n).Cmp(one) == 0 || tmp.Exp(t, qHalf, n).Cmp(one) == 0 {
+		return ErrOrder
+	}
+	return nil
+}
+
 // N = p•q, p ≡ q ≡ 3 mod 4.
 func (p Parameters) N() *saferith.Modulus { return p.n.Modulus }
 
@@ -101,13 +172,24 @@ func (p Parameters) Verify(a, b, e *saferith.Int, S, T *saferith.Nat) bool {
 	return lhs.Eq(rhs) == 1
 }
 
+// Recover returns sᵃ tᵇ T⁻ᵉ (mod N), which is the only S for which Verify(a, b, e, S, T) holds.
+//
+// This lets a verifier recompute a commitment from the response of a proof, instead of receiving it.
+// T must be a unit mod N.
+func (p Parameters) Recover(a, b, e *saferith.Int, T *saferith.Nat) *saferith.Nat {
+	nMod := p.n.Modulus
+	result := p.Commit(a, b)
+	te := p.n.ExpI(T, new(saferith.Int).SetInt(e).Neg(1)) // T⁻ᵉ (mod N)
+	return result.ModMul(result, te, nMod)
+}
+
 // WriteTo implements io.WriterTo and should be used within the hash.Hash function.
 func (p *Parameters) WriteTo(w io.Writer) (int64, error) {
 	if p == nil {
 		return 0, io.ErrUnexpectedEOF
 	}
 	nAll := int64(0)
-	buf := make([]byte, params.BytesIntModN)
+	buf := make([]byte, (p.n.BitLen()+7)/8)
 
 	// write N, S, T
 	for _, i := range []*saferith.Nat{p.n.Nat(), p.s, p.t} {
@@ -125,3 +207,40 @@ func (p *Parameters) WriteTo(w io.Writer) (int64, error) {
 func (Parameters) Domain() string {
 	return "Pedersen Parameters"
 }
+
+// Fingerprint returns the SHA-256 hash of the encoding of the parameters used by WriteTo,
+// which identifies them, for instance to remember which parameters were already verified with a proof.
+func (p *Parameters) Fingerprint() [32]byte {
+	h := sha256.New()
+	_, _ = p.WriteTo(h)
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
+
+type parametersMarshal struct {
+	N, S, T *saferith.Nat
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, so that parameters can be stored once verified,
+// and reused in later sessions.
+func (p *Parameters) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(parametersMarshal{N: p.n.Nat(), S: p.s, T: p.t})
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, and checks the parameters with Validate.
+func (p *Parameters) UnmarshalBinary(data []byte) error {
+	var pm parametersMarshal
+	if err := cbor.Unmarshal(data, &pm); err != nil {
+		return fmt.Errorf("pedersen: %w", err)
+	}
+	if pm.N == nil {
+		return ErrNilFields
+	}
+	parameters := New(arith.ModulusFromN(saferith.ModulusFromNat(pm.N)), pm.S, pm.T)
+	if err := parameters.Validate(); err != nil {
+		return err
+	}
+	*p = *parameters
+	return nil
+}
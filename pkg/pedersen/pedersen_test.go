@@ -2,15 +2,19 @@ package pedersen
 
 import (
 	"crypto/rand"
+	"math/big"
 	"testing"
 
 	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 )
 
 var benchParams *Parameters
 var benchN *saferith.Modulus
+var benchP, benchQ *saferith.Nat
 
 func init() {
 	p, _ := new(saferith.Nat).SetHex("D08769E92F80F7FDFB85EC02AFFDAED0FDE2782070757F191DCDC4D108110AC1E31C07FC253B5F7B91C5D9F203AA0572D3F2062A3D2904C535C6ACCA7D5674E1C2640720E762C72B66931F483C2D910908CF02EA6723A0CBBB1016CA696C38FEAC59B31E40584C8141889A11F7A38F5B17811D11F42CD15B8470F11C6183802B")
@@ -19,6 +23,7 @@ func init() {
 	t, _ := new(saferith.Nat).SetHex("376A2C4A49B8C27F943059A358BCD65BCC0BAB1ABBBE368FFD004580A49EE795B4ECF85B2FB2A24969129E34E9E5D91503D11DE9D11F51538AC66A418B2E31463A55AAFAA29B645C2D04FBC829E3B55F95BFB0B5DE464ED0516DF28D36B4225B4050B80271E1AD8F11866E01FF83D40A06A7F7298FD96B210BE56AA4D3C0524E7372E371D0C6E52E043D2E1BF38E435ED85EB032FAC86C049E9FB8280847ABED9F2025FE03C7B8B8E32914238E3281BA17A2DB4CB2ACAD033442EF55E1BF2E4A741A961833CBE87C8C751E8A59EF998528BA0658CB9342EEDBDF62894E4AE66414024361D916248801D2929326102081BB2F7AD1C57C55AE8038EE35CC2C9915")
 	n := arith.ModulusFromFactors(p, q)
 	benchN = n.Modulus
+	benchP, benchQ = p, q
 	benchParams = &Parameters{n: n, s: s, t: t}
 }
 
@@ -48,3 +53,73 @@ func BenchmarkPedersenVerify(b *testing.B) {
 		resultBool = benchParams.Verify(x, y, e, S, T)
 	}
 }
+
+func TestVerifyBatch(t *testing.T) {
+	params := New(benchParams.n, sample.UnitModN(rand.Reader, benchN), sample.UnitModN(rand.Reader, benchN))
+
+	const n = 4
+	equations := make([]Equation, n)
+	for i := range equations {
+		a := sample.IntervalL(rand.Reader)
+		b := sample.IntervalL(rand.Reader)
+		e := sample.IntervalL(rand.Reader)
+		// UnitModN doesn't reduce its output, which Verify would reject
+		T := new(saferith.Nat).Mod(sample.UnitModN(rand.Reader, benchN), benchN)
+		// S = sᵃ tᵇ T⁻ᵉ
+		S := params.Commit(a, b)
+		S.ModMul(S, params.n.ExpI(T, new(saferith.Int).SetInt(e).Neg(1)), benchN)
+		equations[i] = Equation{Params: params, A: a, B: b, E: e, S: S, T: T}
+		if !params.Verify(a, b, e, S, T) {
+			t.Fatal("invalid equation")
+		}
+	}
+	if !VerifyBatch(equations) {
+		t.Error("valid batch was rejected")
+	}
+	if !VerifyBatch(equations[:1]) {
+		t.Error("valid single equation was rejected")
+	}
+
+	equations[2].S = new(saferith.Nat).ModMul(equations[2].S, equations[2].T, benchN)
+	if VerifyBatch(equations) {
+		t.Error("invalid batch was accepted")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	p, q := benchP, benchQ
+	require.NoError(t, benchParams.Validate())
+	require.NoError(t, benchParams.ValidateWithPrimes(p, q))
+
+	data, err := benchParams.MarshalBinary()
+	require.NoError(t, err)
+	decoded := &Parameters{}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, benchParams.Fingerprint(), decoded.Fingerprint())
+
+	n := benchN.Big()
+	withS := func(s *big.Int) *Parameters {
+		return &Parameters{n: benchParams.n, s: new(saferith.Nat).SetBig(s, s.BitLen()), t: benchParams.t}
+	}
+	withT := func(t *big.Int) *Parameters {
+		return &Parameters{n: benchParams.n, s: benchParams.s, t: new(saferith.Nat).SetBig(t, t.BitLen())}
+	}
+	assert.ErrorIs(t, withS(big.NewInt(1)).Validate(), ErrTrivial)
+	assert.ErrorIs(t, withS(new(big.Int).Sub(n, big.NewInt(1))).Validate(), ErrTrivial)
+
+	nonResidue := big.NewInt(2)
+	for big.Jacobi(nonResidue, n) != -1 {
+		nonResidue.Add(nonResidue, big.NewInt(1))
+	}
+	assert.ErrorIs(t, withS(nonResidue).Validate(), ErrNotResidue)
+	data, err = withS(nonResidue).MarshalBinary()
+	require.NoError(t, err)
+	assert.ErrorIs(t, decoded.UnmarshalBinary(data), ErrNotResidue)
+
+	// t = x^(2P') only generates a subgroup of order Q'
+	pHalf := new(big.Int).Rsh(p.Big(), 1)
+	smallOrder := new(big.Int).Exp(big.NewInt(3), new(big.Int).Lsh(pHalf, 1), n)
+	assert.NoError(t, withT(smallOrder).Validate())
+	assert.ErrorIs(t, withT(smallOrder).ValidateWithPrimes(p, q), ErrOrder)
+	assert.Error(t, benchParams.ValidateWithPrimes(p, p))
+}
@@ -0,0 +1,48 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// skipped is returned by the functions wrapped by SearchContext once the context is done,
+// to end the search.
+type skipped struct{}
+
+// ParallelizeContext is like Parallelize, but stops calling f once ctx is done.
+//
+// Calls of f which have already started are waited for, but no new call is started,
+// so that the work of an abandoned session stops as soon as possible.
+// If some calls were skipped, ctx.Err() is returned instead of the results.
+func (p *Pool) ParallelizeContext(ctx context.Context, count int, f func(int) interface{}) ([]interface{}, error) {
+	var skips int64
+	results := p.Parallelize(count, func(i int) interface{} {
+		if ctx.Err() != nil {
+			atomic.AddInt64(&skips, 1)
+			return nil
+		}
+		return f(i)
+	})
+	if atomic.LoadInt64(&skips) > 0 {
+		return nil, ctx.Err()
+	}
+	return results, nil
+}
+
+// SearchContext is like Search, but gives up once ctx is done, returning ctx.Err().
+//
+// The candidate being tried by each worker when this happens is still waited for.
+func (p *Pool) SearchContext(ctx context.Context, count int, f func() interface{}) ([]interface{}, error) {
+	results := p.Search(count, func() interface{} {
+		if ctx.Err() != nil {
+			return skipped{}
+		}
+		return f()
+	})
+	for _, r := range results {
+		if _, ok := r.(skipped); ok {
+			return nil, ctx.Err()
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,33 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelizeContext(t *testing.T) {
+	for _, pl := range []*Pool{nil, NewPool(2), NewPoolWithExecutor(ExecutorFunc(func(f func()) { go f() }), 2)} {
+		results, err := pl.ParallelizeContext(context.Background(), 10, func(i int) interface{} { return i })
+		require.NoError(t, err)
+		assert.Equal(t, 9, results[9])
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls int64
+		_, err = pl.ParallelizeContext(ctx, 1000, func(i int) interface{} {
+			if atomic.AddInt64(&calls, 1) == 5 {
+				cancel()
+			}
+			return i
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, atomic.LoadInt64(&calls), int64(1000))
+
+		_, err = pl.SearchContext(ctx, 3, func() interface{} { return nil })
+		assert.ErrorIs(t, err, context.Canceled, "a search which can't succeed must stop")
+		pl.TearDown()
+	}
+}
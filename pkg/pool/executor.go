@@ -0,0 +1,92 @@
+package pool
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Executor runs functions on goroutines managed by the caller, such as those of an existing worker pool.
+//
+// An errgroup.Group can be used by wrapping its Go method:
+//
+//	pool.ExecutorFunc(func(f func()) { g.Go(func() error { f(); return nil }) })
+type Executor interface {
+	// Go runs f, usually on another goroutine.
+	//
+	// Go may block until f can be started, for example to respect a limit of the executor.
+	Go(f func())
+}
+
+// ExecutorFunc adapts a function to the Executor interface.
+type ExecutorFunc func(f func())
+
+// Go implements Executor.
+func (e ExecutorFunc) Go(f func()) { e(f) }
+
+// NewPoolWithExecutor creates a pool running its functions on executor, instead of its own goroutines.
+//
+// At most limit functions are run concurrently, including on the goroutine calling the pool,
+// which always takes part in the work. This way, operations finish even if the executor is saturated,
+// or is the one running the caller.
+// If limit ⩽ 0, this will use the number of available CPUs instead.
+//
+// TearDown does nothing for such a pool, and the executor remains owned by the caller.
+func NewPoolWithExecutor(executor Executor, limit int) *Pool {
+	if limit <= 0 {
+		limit = runtime.NumCPU()
+	}
+	return &Pool{workerCount: limit, executor: executor}
+}
+
+// spread runs task on the current goroutine, and on up to workerCount-1 functions given to the executor.
+//
+// Submitting is done on a separate goroutine, since the executor may block. This means that
+// task may still be called after the current call has returned, and must then do nothing.
+func (p *Pool) spread(task func()) {
+	if p.workerCount > 1 {
+		go func() {
+			for i := 1; i < p.workerCount; i++ {
+				p.executor.Go(task)
+			}
+		}()
+	}
+	task()
+}
+
+func (p *Pool) parallelizeExecutor(f func(int) interface{}, count int) []interface{} {
+	results := make([]interface{}, count)
+	next := int64(-1)
+	done := make(chan struct{}, count)
+	p.spread(func() {
+		for i := atomic.AddInt64(&next, 1); i < int64(count); i = atomic.AddInt64(&next, 1) {
+			results[i] = f(int(i))
+			done <- struct{}{}
+		}
+	})
+	for i := 0; i < count; i++ {
+		<-done
+	}
+	return results
+}
+
+func (p *Pool) searchExecutor(f func() interface{}, count int) []interface{} {
+	results := make([]interface{}, count)
+	remaining := int64(count)
+	done := make(chan struct{}, count)
+	p.spread(func() {
+		for atomic.LoadInt64(&remaining) > 0 {
+			res := f()
+			if res == nil {
+				continue
+			}
+			if i := atomic.AddInt64(&remaining, -1); i >= 0 {
+				results[i] = res
+				done <- struct{}{}
+			}
+		}
+	})
+	for i := 0; i < count; i++ {
+		<-done
+	}
+	return results
+}
@@ -0,0 +1,73 @@
+package pool
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolWithExecutor(t *testing.T) {
+	const limit = 3
+	var running, maxRunning int64
+	var wg sync.WaitGroup
+	executor := ExecutorFunc(func(f func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+	})
+	pl := NewPoolWithExecutor(executor, limit)
+	defer pl.TearDown()
+
+	track := func() func() {
+		n := atomic.AddInt64(&running, 1)
+		for {
+			m := atomic.LoadInt64(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt64(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return func() { atomic.AddInt64(&running, -1) }
+	}
+
+	results := pl.Parallelize(50, func(i int) interface{} {
+		defer track()()
+		return i * i
+	})
+	for i, r := range results {
+		assert.Equal(t, i*i, r)
+	}
+
+	var tries int64
+	found := pl.Search(10, func() interface{} {
+		defer track()()
+		if atomic.AddInt64(&tries, 1)%2 == 0 {
+			return nil
+		}
+		return true
+	})
+	for _, r := range found {
+		assert.Equal(t, true, r)
+	}
+
+	wg.Wait()
+	assert.LessOrEqual(t, maxRunning, int64(limit))
+}
+
+func TestPoolWithSaturatedExecutor(t *testing.T) {
+	// An executor which never runs anything, the caller must still do all the work.
+	blocked := make(chan struct{})
+	defer close(blocked)
+	pl := NewPoolWithExecutor(ExecutorFunc(func(func()) { <-blocked }), 4)
+
+	results := pl.Parallelize(5, func(i int) interface{} { return i })
+	require.Len(t, results, 5)
+	assert.Equal(t, 4, results[4])
+	assert.Len(t, pl.Search(2, func() interface{} { return 1 }), 2)
+}
@@ -57,8 +57,8 @@ func workerSearch(results []interface{}, ctrChanged chan<- struct{}, f func(int)
 		i := atomic.AddInt64(ctr, -1)
 		if i >= 0 {
 			results[i] = res
+			ctrChanged <- struct{}{}
 		}
-		ctrChanged <- struct{}{}
 	}
 }
 
@@ -92,14 +92,24 @@ type Pool struct {
 	commands chan command
 	// This holds the number of workers we've created
 	workerCount int
+	// When set, functions are run on executor instead of our own workers,
+	// with at most workerCount of them running at the same time.
+	executor Executor
 }
 
 // NewPool creates a new pool, with a certain number of workers.
 //
 // If count ⩽ 0, this will use the number of available CPUs instead.
+//
+// On WebAssembly, which runs a single thread, no workers are started and
+// the pool does the work on the calling goroutine, like a nil *Pool.
 func NewPool(count int) *Pool {
 	var p Pool
 
+	if singleThreaded {
+		return &p
+	}
+
 	if count <= 0 {
 		count = runtime.NumCPU()
 	}
@@ -116,7 +126,7 @@ func NewPool(count int) *Pool {
 
 // TearDown cleanly tears down a pool, closing channels, etc.
 func (p *Pool) TearDown() {
-	if p != nil {
+	if p != nil && p.commands != nil {
 		close(p.commands)
 	}
 }
@@ -128,14 +138,19 @@ func (p *Pool) TearDown() {
 //
 // The result will be an array containing the first count successes.
 func (p *Pool) Search(count int, f func() interface{}) []interface{} {
-	if p == nil {
+	if p.alone() {
 		return searchAlone(f, count)
 	}
+	if p.executor != nil {
+		return p.searchExecutor(f, count)
+	}
 
 	results := make([]interface{}, count)
 
 	ctr := int64(count)
-	ctrChanged := make(chan struct{})
+	// workers signal once for each of the count results they store, after storing it,
+	// so the channel is large enough for workers never to block after we've returned.
+	ctrChanged := make(chan struct{}, count)
 	cmd := command{
 		search:     true,
 		ctr:        &ctr,
@@ -143,15 +158,17 @@ func (p *Pool) Search(count int, f func() interface{}) []interface{} {
 		f:          func(i int) interface{} { return f() },
 		results:    results,
 	}
+	found := 0
 	cmdI := 0
 	for cmdI < p.workerCount {
 		select {
 		case p.commands <- cmd:
 			cmdI++
 		case <-ctrChanged:
+			found++
 		}
 	}
-	for atomic.LoadInt64(&ctr) > 0 {
+	for ; found < count; found++ {
 		<-ctrChanged
 	}
 
@@ -162,14 +179,18 @@ func (p *Pool) Search(count int, f func() interface{}) []interface{} {
 //
 // The result will be a slice containing [f(0), f(1), ..., f(count - 1)].
 func (p *Pool) Parallelize(count int, f func(int) interface{}) []interface{} {
-	if p == nil {
+	if p.alone() {
 		return parallelizeAlone(f, count)
 	}
+	if p.executor != nil {
+		return p.parallelizeExecutor(f, count)
+	}
 
 	results := make([]interface{}, count)
 
 	ctr := int64(count)
-	ctrChanged := make(chan struct{})
+	// every command signals once, so workers never block after we've returned.
+	ctrChanged := make(chan struct{}, count)
 	cmdI := 0
 	for cmdI < count {
 		cmd := command{
@@ -196,6 +217,11 @@ func (p *Pool) Parallelize(count int, f func(int) interface{}) []interface{} {
 	return results
 }
 
+// alone returns true if the work must be done on the calling goroutine, because p has neither workers nor an executor.
+func (p *Pool) alone() bool {
+	return p == nil || (p.commands == nil && p.executor == nil)
+}
+
 // LockedReader wraps an io.Reader to be safe for concurrent reads.
 //
 // This type implements io.Reader, returning the same output.
@@ -0,0 +1,5 @@
+//go:build !wasm
+
+package pool
+
+const singleThreaded = false
@@ -0,0 +1,7 @@
+//go:build wasm
+
+package pool
+
+// singleThreaded is true on WebAssembly, where goroutines share one thread,
+// so that workers would only add scheduling overhead.
+const singleThreaded = true
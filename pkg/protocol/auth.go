@@ -0,0 +1,47 @@
+package protocol
+
+import (
+	"crypto/ed25519"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// signatureContext separates the signatures of messages from other uses of the identity keys.
+const signatureContext = "multi-party-sig protocol.Message"
+
+// WithIdentity authenticates the messages of the session with long-term Ed25519 identity keys.
+//
+// Every message sent by the handler is signed with key, and received messages, including aborts,
+// are dropped unless they are signed by the key of their sender in identities.
+// This binds party IDs to keys, so that the protocols' assumption of authenticated channels holds
+// even over transports which don't authenticate the parties, such as a relay.
+// Confidentiality of the point-to-point messages is still left to the transport.
+//
+// All parties of a session must use this option, with the same identities.
+func WithIdentity(key ed25519.PrivateKey, identities map[party.ID]ed25519.PublicKey) HandlerOption {
+	return func(h *MultiHandler) {
+		h.identity = key
+		h.identities = identities
+	}
+}
+
+// sign sets the signature of msg, if the handler has an identity key.
+func (h *MultiHandler) sign(msg *Message) {
+	if h.identity == nil {
+		return
+	}
+	signature, _ := h.identity.Sign(nil, msg.Hash(), &ed25519.Options{Context: signatureContext})
+	msg.Signature = signature
+}
+
+// authentic returns true if msg is signed by the identity of its sender, or if messages aren't authenticated.
+func (h *MultiHandler) authentic(msg *Message) bool {
+	if h.identities == nil {
+		return true
+	}
+	key, ok := h.identities[msg.From]
+	if !ok || len(key) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.VerifyWithOptions(key, msg.Hash(), msg.Signature, &ed25519.Options{Context: signatureContext}) == nil
+}
@@ -0,0 +1,69 @@
+package protocol_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestWithIdentity(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	keys := make(map[party.ID]ed25519.PrivateKey, len(ids))
+	identities := make(map[party.ID]ed25519.PublicKey, len(ids))
+	for _, id := range ids {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		keys[id], identities[id] = private, public
+	}
+	_, impostor, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(ids))
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, 1), []byte("session"), protocol.WithIdentity(keys[id], identities))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+
+	// a message forged by someone without b's key is dropped, instead of aborting a or blaming b
+	forger, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), []byte("session"), protocol.WithIdentity(impostor, identities))
+	require.NoError(t, err)
+	for len(forger.Listen()) > 0 {
+		msg := <-forger.Listen()
+		assert.NotEmpty(t, msg.Signature)
+		handlers["a"].Accept(msg)
+	}
+	msg := <-handlers["b"].Listen()
+	unsigned := *msg
+	unsigned.Signature = nil
+	handlers["a"].Accept(&unsigned)
+	withoutContext := *msg
+	withoutContext.Signature = ed25519.Sign(keys["b"], msg.Hash())
+	handlers["a"].Accept(&withoutContext)
+	tampered := *msg
+	tampered.Data = append([]byte{0}, msg.Data...)
+	handlers["a"].Accept(&tampered)
+
+	// b's genuine message was taken from its channel, so it is delivered by hand
+	for _, id := range ids {
+		if msg.IsFor(id) {
+			handlers[id].Accept(msg)
+		}
+	}
+	messages := append([]*protocol.Message{msg}, deliver(ids, handlers)...)
+	for _, id := range ids {
+		_, err := handlers[id].Result()
+		assert.NoError(t, err, "party %s", id)
+	}
+	for _, msg := range messages {
+		assert.True(t, ed25519.VerifyWithOptions(identities[msg.From], msg.Hash(), msg.Signature, &ed25519.Options{Context: "multi-party-sig protocol.Message"}) == nil)
+	}
+}
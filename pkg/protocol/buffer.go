@@ -0,0 +1,141 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// ErrTooFarAhead is returned by handlers receiving a message for a round further ahead than their BufferLimits allow.
+var ErrTooFarAhead = errors.New("protocol: message for a round too far ahead")
+
+// ErrBufferFull is returned by handlers which can't keep a message for a future round within their BufferLimits.
+var ErrBufferFull = errors.New("protocol: too many messages buffered for future rounds")
+
+// Overflow is what a MultiHandler does with a message for a future round which doesn't fit in its BufferLimits.
+type Overflow uint8
+
+const (
+	// OverflowAbort aborts the session with ErrBufferFull, blaming the sender of the message.
+	OverflowAbort Overflow = iota
+	// OverflowDrop drops the message, as if it had never been received,
+	// so that the transport can deliver it again once the handler has caught up.
+	OverflowDrop
+	// OverflowEvict makes room by dropping the buffered message for the furthest round, if it is after that of the message,
+	// and otherwise drops the message.
+	// When the limit of the sender is reached, only its own messages are evicted.
+	// Since an evicted message was already recorded by the ReplayStore of the handler, if there is one,
+	// it will not be accepted again.
+	OverflowEvict
+)
+
+// BufferLimits bound the messages a MultiHandler keeps for the rounds after its current one,
+// which peers running ahead send before the handler can verify them.
+//
+// Without limits, a handler keeps at most one message and one broadcast per party and round,
+// for the rounds of the protocol, so that memory remains bounded by the size of the session.
+// The limits lower this bound, for sessions with many parties or facing semi-trusted peers.
+// A limit of 0 disables the corresponding check.
+type BufferLimits struct {
+	// MaxRoundsAhead is the number of rounds after the current one for which messages are accepted.
+	// A message for a later round aborts the session with ErrTooFarAhead, blaming its sender, regardless of Overflow.
+	// Honest parties are at most one round ahead, since a round can't finish without the messages of every party.
+	MaxRoundsAhead int
+	// MaxPerParty is the maximum number of messages buffered from a single party.
+	MaxPerParty int
+	// MaxTotal is the maximum number of messages buffered from all parties.
+	MaxTotal int
+	// Overflow is applied to the messages exceeding MaxPerParty or MaxTotal.
+	Overflow Overflow
+}
+
+// WithBufferLimits bounds the messages for future rounds the MultiHandler keeps.
+func WithBufferLimits(l BufferLimits) HandlerOption {
+	return func(h *MultiHandler) {
+		h.bufferLimits = l
+	}
+}
+
+// buffer checks that msg, for a round after the current one, fits in the BufferLimits of the handler,
+// evicting other messages if needed.
+// It returns false if msg must be dropped, and an error if the session must be aborted.
+func (h *MultiHandler) buffer(msg *Message) (bool, error) {
+	l := h.bufferLimits
+	current := h.currentRound.Number()
+	if msg.RoundNumber <= current {
+		return true, nil
+	}
+	if l.MaxRoundsAhead > 0 && int(msg.RoundNumber-current) > l.MaxRoundsAhead {
+		return false, fmt.Errorf("%w: round %d, while in round %d", ErrTooFarAhead, msg.RoundNumber, current)
+	}
+	// the limit of the sender is checked first, so that only its own messages are evicted for it
+	for _, from := range []party.ID{msg.From, ""} {
+		limit := l.MaxTotal
+		if from != "" {
+			limit = l.MaxPerParty
+		}
+		if limit <= 0 || h.buffered(from) < limit {
+			continue
+		}
+		switch l.Overflow {
+		case OverflowDrop:
+			return false, nil
+		case OverflowEvict:
+			if !h.evict(from, msg.RoundNumber) {
+				return false, nil
+			}
+		default:
+			return false, fmt.Errorf("%w: %d messages", ErrBufferFull, limit)
+		}
+	}
+	return true, nil
+}
+
+// buffered returns the number of messages kept for the rounds after the current one, sent by from,
+// or by any party if from is empty.
+func (h *MultiHandler) buffered(from party.ID) int {
+	n := 0
+	h.future(func(_ round.Number, _ map[party.ID]*Message, id party.ID) {
+		if from == "" || id == from {
+			n++
+		}
+	})
+	return n
+}
+
+// evict drops the buffered message for the furthest round after number, sent by from, or by any party if from is empty.
+// It returns false if there is no such message.
+func (h *MultiHandler) evict(from party.ID, number round.Number) bool {
+	var (
+		furthest round.Number
+		queue    map[party.ID]*Message
+		sender   party.ID
+	)
+	h.future(func(n round.Number, q map[party.ID]*Message, id party.ID) {
+		if (from == "" || id == from) && n > number && n > furthest {
+			furthest, queue, sender = n, q, id
+		}
+	})
+	if queue == nil {
+		return false
+	}
+	queue[sender] = nil
+	return true
+}
+
+// future calls f for every message kept for the rounds after the current one, with the queue holding it and its sender,
+// in the order of the rounds, then of the parties.
+func (h *MultiHandler) future(f func(number round.Number, q map[party.ID]*Message, from party.ID)) {
+	ids := h.currentRound.OtherPartyIDs()
+	for number := h.currentRound.Number() + 1; number <= h.currentRound.FinalRoundNumber(); number++ {
+		for _, q := range []map[party.ID]*Message{h.broadcast[number], h.messages[number]} {
+			for _, id := range ids {
+				if q[id] != nil {
+					f(number, q, id)
+				}
+			}
+		}
+	}
+}
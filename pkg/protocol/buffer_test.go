@@ -0,0 +1,127 @@
+package protocol_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// runAhead starts frost keygen between a, b and c, where b and c get the messages of a, and run ahead of it to round 3,
+// while a receives nothing. It returns the messages for a of each round.
+func runAhead(t *testing.T, a *protocol.MultiHandler) (round2, round3 []*protocol.Message) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	handlers := map[party.ID]*protocol.MultiHandler{}
+	for _, id := range ids[1:] {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, 1), []byte("session"))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	for len(a.Listen()) > 0 {
+		msg := <-a.Listen()
+		for _, h := range handlers {
+			h.Accept(msg)
+		}
+	}
+	for delivered := true; delivered; {
+		delivered = false
+		for _, id := range ids[1:] {
+			for len(handlers[id].Listen()) > 0 {
+				msg := <-handlers[id].Listen()
+				delivered = true
+				for _, other := range ids[1:] {
+					if other != id && msg.IsFor(other) {
+						handlers[other].Accept(msg)
+					}
+				}
+				if !msg.IsFor("a") {
+					continue
+				}
+				if msg.RoundNumber == 2 {
+					round2 = append(round2, msg)
+				} else {
+					round3 = append(round3, msg)
+				}
+			}
+		}
+	}
+	// a broadcast and a message from each of b and c
+	require.Len(t, round3, 4)
+	return round2, round3
+}
+
+func TestBufferLimits(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+
+	run := func(l protocol.BufferLimits) (*protocol.MultiHandler, []*protocol.Message, error) {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), []byte("session"), protocol.WithBufferLimits(l))
+		require.NoError(t, err)
+		round2, round3 := runAhead(t, h)
+		for _, msg := range round3 {
+			h.Accept(msg)
+		}
+		_, err = h.Result()
+		return h, append(round2, round3...), err
+	}
+
+	_, _, err := run(protocol.BufferLimits{MaxPerParty: 2, MaxTotal: 4})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, protocol.ErrBufferFull, "the handler is still waiting for round 2")
+
+	_, messages, err := run(protocol.BufferLimits{MaxPerParty: 1})
+	assert.ErrorIs(t, err, protocol.ErrBufferFull)
+	var protocolErr protocol.Error
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, []party.ID{messages[len(messages)-4].From}, protocolErr.Culprits)
+	assert.Equal(t, protocol.CategoryMisbehavior, protocolErr.Category)
+
+	_, messages, err = run(protocol.BufferLimits{MaxTotal: 3})
+	assert.ErrorIs(t, err, protocol.ErrBufferFull)
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, []party.ID{messages[len(messages)-1].From}, protocolErr.Culprits)
+
+	// dropped messages can be delivered again, once the handler has caught up with round 3
+	for _, overflow := range []protocol.Overflow{protocol.OverflowDrop, protocol.OverflowEvict} {
+		h, messages, err := run(protocol.BufferLimits{MaxPerParty: 1, MaxTotal: 1, Overflow: overflow})
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, protocol.ErrBufferFull)
+		for _, msg := range messages {
+			h.Accept(msg)
+		}
+		result, err := h.Result()
+		require.NoError(t, err, "overflow %d", overflow)
+		assert.IsType(t, &frost.Config{}, result)
+	}
+}
+
+func TestBufferLimitsRoundsAhead(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	ids := party.IDSlice{"a", "b"}
+
+	h, err := protocol.NewMultiHandler(cmp.Keygen(curve.Secp256k1{}, "a", ids, 1, pl), []byte("session"),
+		protocol.WithBufferLimits(protocol.BufferLimits{MaxRoundsAhead: 1}))
+	require.NoError(t, err)
+	msg := <-h.Listen()
+	require.EqualValues(t, 2, msg.RoundNumber)
+
+	h.Accept(&protocol.Message{
+		Version:     protocol.WireVersion,
+		SSID:        msg.SSID,
+		From:        "b",
+		Protocol:    msg.Protocol,
+		RoundNumber: 4,
+		Data:        []byte{0},
+		Broadcast:   true,
+	})
+	_, err = h.Result()
+	assert.ErrorIs(t, err, protocol.ErrTooFarAhead)
+}
@@ -0,0 +1,118 @@
+package protocol
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time used to expire sessions.
+//
+// It can be replaced to make tests deterministic, to speed up simulations,
+// or in environments where the wall-clock isn't trusted, and time is stepped by the host instead.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc calls f once d has elapsed, and returns a Timer which can cancel or reschedule the call.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a pending call created by Clock.AfterFunc. It behaves like a *time.Timer.
+type Timer interface {
+	// Stop prevents the call from happening, and returns false if it already happened or was stopped.
+	Stop() bool
+	// Reset schedules the call to happen after d, and returns false if it already happened or was stopped.
+	Reset(d time.Duration) bool
+}
+
+// SystemClock is the Clock based on the time package, which is used by default.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+// ManualClock is a Clock whose time only changes when Advance is called.
+//
+// Calls scheduled with AfterFunc happen during Advance, on the goroutine calling it,
+// in the order of their deadline.
+type ManualClock struct {
+	now time.Time
+	// timers contains the calls which haven't happened yet
+	timers map[*manualTimer]struct{}
+	mtx    sync.Mutex
+}
+
+type manualTimer struct {
+	clock    *ManualClock
+	deadline time.Time
+	f        func()
+}
+
+// NewManualClock returns a ManualClock starting at the given time.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{
+		now:    start,
+		timers: map[*manualTimer]struct{}{},
+	}
+}
+
+// Now implements Clock.
+func (c *ManualClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.now
+}
+
+// AfterFunc implements Clock.
+func (c *ManualClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	t := &manualTimer{
+		clock:    c,
+		deadline: c.now.Add(d),
+		f:        f,
+	}
+	c.timers[t] = struct{}{}
+	return t
+}
+
+// Advance moves the clock forward by d, and performs the calls which are due.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	c.now = c.now.Add(d)
+	var due []manualTimer
+	for t := range c.timers {
+		if !t.deadline.After(c.now) {
+			delete(c.timers, t)
+			due = append(due, *t)
+		}
+	}
+	c.mtx.Unlock()
+
+	sort.Slice(due, func(i, j int) bool { return due[i].deadline.Before(due[j].deadline) })
+	for _, t := range due {
+		t.f()
+	}
+}
+
+func (t *manualTimer) Stop() bool {
+	c := t.clock
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	_, pending := c.timers[t]
+	delete(c.timers, t)
+	return pending
+}
+
+func (t *manualTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	_, pending := c.timers[t]
+	t.deadline = c.now.Add(d)
+	c.timers[t] = struct{}{}
+	return pending
+}
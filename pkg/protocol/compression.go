@@ -0,0 +1,78 @@
+package protocol
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Compression selects how MarshalBinaryCompressed compresses a message.
+type Compression uint8
+
+const (
+	// NoCompression produces the same encoding as MarshalBinary.
+	NoCompression Compression = iota
+	// FlateCompression compresses the encoding with DEFLATE.
+	FlateCompression
+)
+
+// compressedPrefix starts every compressed message, and can't start the CBOR encoding of a Message,
+// so that UnmarshalBinary can tell both apart.
+const compressedPrefix = 0xff
+
+// maxDecompressedSize bounds the size of a decompressed message, so that a small message can't expand without limit.
+const maxDecompressedSize = 1 << 26
+
+// MarshalBinaryCompressed is like MarshalBinary, but compresses the encoding with c.
+//
+// UnmarshalBinary accepts both compressed and uncompressed messages, so parties can enable compression
+// on constrained links without coordinating with the others first.
+// Messages which don't get smaller are sent uncompressed.
+func (m *Message) MarshalBinaryCompressed(c Compression) ([]byte, error) {
+	data, err := m.MarshalBinary()
+	if err != nil || c == NoCompression {
+		return data, err
+	}
+	if c != FlateCompression {
+		return nil, fmt.Errorf("protocol: unknown compression %d", c)
+	}
+
+	var out bytes.Buffer
+	out.Write([]byte{compressedPrefix, byte(c)})
+	w, err := flate.NewWriter(&out, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	if out.Len() >= len(data) {
+		return data, nil
+	}
+	return out.Bytes(), nil
+}
+
+// decompress returns the CBOR encoding of a message produced by MarshalBinary or MarshalBinaryCompressed.
+func decompress(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != compressedPrefix {
+		return data, nil
+	}
+	if len(data) < 2 || Compression(data[1]) != FlateCompression {
+		return nil, errors.New("protocol: unknown message compression")
+	}
+	r := flate.NewReader(bytes.NewReader(data[2:]))
+	defer r.Close()
+	decompressed, err := io.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("protocol: decompressing message: %w", err)
+	}
+	if len(decompressed) > maxDecompressedSize {
+		return nil, errors.New("protocol: decompressed message is too large")
+	}
+	return decompressed, nil
+}
@@ -0,0 +1,49 @@
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+)
+
+// WithDomain returns a StartFunc which starts the protocol created by start, with every transcript hash
+// bound to the application domain, such as the name and version of a product.
+//
+// Every hash of the protocol, from the SSID to the challenges of the zero-knowledge proofs, is derived
+// from the session ID, which is replaced by a hash of domain and the original session ID.
+// Therefore, two applications using different domains can never produce messages valid for each other,
+// even when the parties, keys and session IDs are the same.
+// All the parties of a session must use the same domain.
+func WithDomain(domain []byte, start StartFunc) StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		return start(domainSessionID(domain, sessionID))
+	}
+}
+
+// ResumeWithDomain is the equivalent of WithDomain for resuming a suspended session,
+// which must have been started with the same domain.
+func ResumeWithDomain(domain []byte, resume ResumeFunc) ResumeFunc {
+	return func(sessionID []byte, number round.Number, state []byte) (round.Session, error) {
+		return resume(domainSessionID(domain, sessionID), number, state)
+	}
+}
+
+// domainSessionID returns SHA-256(tag || len(domain) || domain || sessionID), with a marker distinguishing
+// a nil session ID from an empty one.
+//
+// SHA-256 is used so that the derivation doesn't depend on the hash function of the protocol.
+func domainSessionID(domain, sessionID []byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write([]byte("multi-party-sig application domain"))
+	var length [binary.MaxVarintLen64]byte
+	_, _ = h.Write(length[:binary.PutUvarint(length[:], uint64(len(domain)))])
+	_, _ = h.Write(domain)
+	if sessionID == nil {
+		_, _ = h.Write([]byte{0})
+	} else {
+		_, _ = h.Write([]byte{1})
+		_, _ = h.Write(sessionID)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,73 @@
+package protocol
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+)
+
+// Driver runs a protocol synchronously, taking the messages received since the previous step,
+// and returning those to send, without goroutines or channels.
+//
+// This suits request/response backends, which can keep a Driver per session between requests,
+// and tests, in which the order of all messages is decided by the caller.
+//
+//	d, err := protocol.NewDriver(cmp.Sign(config, signers, hash, pl), sessionID)
+//	msgsIn := []*protocol.Message(nil)
+//	for {
+//		next, msgsOut, err := d.Advance(msgsIn)
+//		// send msgsOut, and handle err
+//		if !next {
+//			break
+//		}
+//		msgsIn = receive()
+//	}
+//	result, err := d.Result()
+type Driver struct {
+	h *MultiHandler
+}
+
+// NewDriver expects a StartFunc for the desired protocol, and returns a Driver running it.
+//
+// The messages of the first round are returned by the first call to Advance.
+func NewDriver(create StartFunc, sessionID []byte, opts ...HandlerOption) (*Driver, error) {
+	h, err := newPolledHandler(create, sessionID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Driver{h: h}, nil
+}
+
+// Advance processes msgsIn in order, finalizing every round whose messages have all been received,
+// and returns the messages produced in the meantime.
+//
+// Messages for later rounds are kept until their round is reached, and those which don't belong to the session are ignored.
+// next is false once the protocol has finished, in which case Result returns its outcome.
+// If it aborted, err is the Error also returned by Result, and msgsOut ends with the message telling the other parties.
+func (d *Driver) Advance(msgsIn []*Message) (next bool, msgsOut []*Message, err error) {
+	for _, msg := range msgsIn {
+		d.h.Accept(msg)
+	}
+	d.h.mtx.Lock()
+	defer d.h.mtx.Unlock()
+	msgsOut, d.h.pending = d.h.pending, nil
+	if d.h.err != nil {
+		return false, msgsOut, *d.h.err
+	}
+	return d.h.result == nil, msgsOut, nil
+}
+
+// Round returns the number of the current round.
+func (d *Driver) Round() round.Number {
+	d.h.mtx.Lock()
+	defer d.h.mtx.Unlock()
+	return d.h.currentRound.Number()
+}
+
+// Result returns the protocol result if the protocol completed successfully. Otherwise an error is returned.
+func (d *Driver) Result() (interface{}, error) {
+	return d.h.Result()
+}
+
+// Stop aborts the protocol. The message telling the other parties is returned by the next call to Advance.
+func (d *Driver) Stop() {
+	d.h.Stop()
+}
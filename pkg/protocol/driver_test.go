@@ -0,0 +1,78 @@
+package protocol_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestDriver(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+
+	drivers := make(map[party.ID]*protocol.Driver, len(ids))
+	inbox := make(map[party.ID][]*protocol.Message, len(ids))
+	for _, id := range ids {
+		d, err := protocol.NewDriver(frost.Keygen(group, id, ids, 1), []byte("session"))
+		require.NoError(t, err)
+		// the first round expects no message, so it's finalized right away
+		assert.EqualValues(t, 2, d.Round())
+		drivers[id] = d
+	}
+
+	for steps := 0; len(drivers) > 0; steps++ {
+		require.Less(t, steps, 10)
+		outbox := make(map[party.ID][]*protocol.Message, len(ids))
+		for _, id := range ids {
+			d, ok := drivers[id]
+			if !ok {
+				continue
+			}
+			next, msgsOut, err := d.Advance(inbox[id])
+			require.NoError(t, err)
+			for _, msg := range msgsOut {
+				for _, other := range ids {
+					if other != id && msg.IsFor(other) {
+						outbox[other] = append(outbox[other], msg)
+					}
+				}
+			}
+			if !next {
+				delete(drivers, id)
+				result, err := d.Result()
+				require.NoError(t, err)
+				assert.IsType(t, &frost.Config{}, result)
+			}
+		}
+		inbox = outbox
+	}
+}
+
+func TestDriverAbort(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b"}
+	a, err := protocol.NewDriver(frost.Keygen(group, "a", ids, 1), nil)
+	require.NoError(t, err)
+	b, err := protocol.NewDriver(frost.Keygen(group, "b", ids, 1), nil)
+	require.NoError(t, err)
+
+	_, _, err = b.Advance(nil)
+	require.NoError(t, err)
+	a.Stop()
+	next, msgsOut, err := a.Advance(nil)
+	assert.False(t, next)
+	require.Error(t, err)
+	require.NotEmpty(t, msgsOut)
+
+	next, _, err = b.Advance(msgsOut[len(msgsOut)-1:])
+	assert.False(t, next)
+	var protocolErr protocol.Error
+	require.True(t, errors.As(err, &protocolErr))
+	assert.Equal(t, []party.ID{"a"}, protocolErr.Culprits)
+}
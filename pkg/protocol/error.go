@@ -1,11 +1,60 @@
 package protocol
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
 
+var (
+	// ErrDecode is wrapped by the errors of messages whose content could not be decoded.
+	ErrDecode = errors.New("protocol: failed to decode message")
+	// ErrAbortedByPeer is wrapped by the error of a protocol aborted by another party, along with the reason it gave.
+	ErrAbortedByPeer = errors.New("aborted by other party with error")
+	// ErrBroadcastMismatch is the error of a protocol in which the parties did not receive the same broadcast messages.
+	ErrBroadcastMismatch = errors.New("broadcast verification failed")
+)
+
+// Category tells why a protocol aborted, so that callers can react without inspecting the error message.
+type Category uint8
+
+const (
+	// CategoryUnknown is the category of errors which were not produced by a handler.
+	CategoryUnknown Category = iota
+	// CategoryMisbehavior means that the culprits sent messages which failed verification.
+	CategoryMisbehavior
+	// CategoryDecode means that a message from the culprits could not be decoded,
+	// because it was malformed, exceeded the limits or used another wire version.
+	CategoryDecode
+	// CategoryAborted means that another party aborted the protocol, and told us.
+	CategoryAborted
+	// CategoryTimeout means that the culprits did not send their messages in time.
+	CategoryTimeout
+	// CategoryLocal means that the protocol failed on our side,
+	// for instance because it was stopped, or because our config does not match the other parties'.
+	CategoryLocal
+)
+
+// String implements fmt.Stringer.
+func (c Category) String() string {
+	switch c {
+	case CategoryMisbehavior:
+		return "misbehavior"
+	case CategoryDecode:
+		return "decode"
+	case CategoryAborted:
+		return "aborted"
+	case CategoryTimeout:
+		return "timeout"
+	case CategoryLocal:
+		return "local"
+	default:
+		return "unknown"
+	}
+}
+
 // Error is a custom error for protocols which contains information about the responsible round in which it occurred,
 // and the party responsible.
 type Error struct {
@@ -13,6 +62,14 @@ type Error struct {
 	Culprits []party.ID
 	// Err is the underlying error.
 	Err error
+	// Protocol is the ID of the protocol which aborted.
+	Protocol string
+	// Round is the round the protocol was in when it aborted.
+	Round round.Number
+	// Category classifies the failure.
+	Category Category
+	// Evidence proves the misbehavior of the culprits to third parties, if the messages of the session are signed.
+	Evidence []*Evidence
 }
 
 // Error implement error.
@@ -27,3 +84,26 @@ func (e Error) Error() string {
 func (e Error) Unwrap() error {
 	return e.Err
 }
+
+// categorize returns the category of err, returned by a handler with the given culprits.
+//
+// Errors which are not recognized are blamed on this party if it is the only culprit,
+// and otherwise on the misbehavior of the culprits.
+func categorize(err error, self party.ID, culprits []party.ID) Category {
+	switch {
+	case errors.Is(err, ErrAbortedByPeer):
+		return CategoryAborted
+	case errors.Is(err, ErrDecode), errors.Is(err, ErrIncompatibleVersion), errors.Is(err, ErrMessageTooLarge):
+		return CategoryDecode
+	case errors.Is(err, ErrRoundTimeout), errors.Is(err, ErrSessionExpired):
+		return CategoryTimeout
+	case errors.Is(err, ErrBroadcastMismatch):
+		return CategoryMisbehavior
+	}
+	for _, id := range culprits {
+		if id != self {
+			return CategoryMisbehavior
+		}
+	}
+	return CategoryLocal
+}
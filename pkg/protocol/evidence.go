@@ -0,0 +1,143 @@
+package protocol
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// Evidence shows that a party sent the messages which made a session abort,
+// in a form which can be checked by a party which did not participate, such as an arbiter.
+//
+// It is produced by a MultiHandler using WithIdentity, and is found in the Evidence of the Error returned by Result,
+// when the culprits misbehaved or sent messages which could not be decoded.
+// Since the messages are signed by the culprit, and bound to the session by its SSID,
+// which commits to the public parameters of the session, the culprit cannot deny having sent them.
+type Evidence struct {
+	// SSID identifies the session, as derived by DeriveSSID.
+	SSID []byte
+	// Protocol is the ID of the protocol which aborted.
+	Protocol string
+	// Round is the round whose messages failed.
+	Round round.Number
+	// Culprit is the party which sent the messages.
+	Culprit party.ID
+	// Category classifies the failure.
+	Category Category
+	// Reason is the error with which the session aborted.
+	Reason string
+	// Messages are the messages Culprit sent for Round. The offending one comes first, if it is known.
+	Messages []*Message
+	// Transcript contains the messages Culprit sent in the previous rounds, on which the verification of Messages depends.
+	Transcript []*Message
+}
+
+// Verify checks that the messages of e were sent by e.Culprit, for the session ssid,
+// which an arbiter recomputes from the public parameters of the session with DeriveSSID.
+// identities are the identity keys of the parties, as given to WithIdentity.
+//
+// Verify doesn't re-run the checks of the protocol: once it succeeds,
+// the proofs and decommitments in the messages can be checked against the public parameters of the session.
+func (e *Evidence) Verify(identities map[party.ID]ed25519.PublicKey, ssid []byte) error {
+	if !bytes.Equal(e.SSID, ssid) {
+		return errors.New("protocol: evidence: wrong session")
+	}
+	key, ok := identities[e.Culprit]
+	if !ok || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("protocol: evidence: no identity for party %s", e.Culprit)
+	}
+	if len(e.Messages) == 0 {
+		return errors.New("protocol: evidence: no messages")
+	}
+	check := func(msg *Message) error {
+		if msg == nil {
+			return errors.New("protocol: evidence: empty message")
+		}
+		if !bytes.Equal(msg.SSID, ssid) || msg.Protocol != e.Protocol {
+			return fmt.Errorf("protocol: evidence: message of round %d is from another session", msg.RoundNumber)
+		}
+		if msg.From != e.Culprit {
+			return fmt.Errorf("protocol: evidence: message of round %d is from %s", msg.RoundNumber, msg.From)
+		}
+		if ed25519.VerifyWithOptions(key, msg.Hash(), msg.Signature, &ed25519.Options{Context: signatureContext}) != nil {
+			return fmt.Errorf("protocol: evidence: message of round %d is not signed by %s", msg.RoundNumber, e.Culprit)
+		}
+		return nil
+	}
+	for _, msg := range e.Messages {
+		if err := check(msg); err != nil {
+			return err
+		}
+		if msg.RoundNumber != e.Round {
+			return fmt.Errorf("protocol: evidence: message of round %d instead of %d", msg.RoundNumber, e.Round)
+		}
+	}
+	for _, msg := range e.Transcript {
+		if err := check(msg); err != nil {
+			return err
+		}
+		if msg.RoundNumber >= e.Round {
+			return fmt.Errorf("protocol: evidence: transcript contains a message of round %d", msg.RoundNumber)
+		}
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e *Evidence) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal((*marshallableEvidence)(e))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (e *Evidence) UnmarshalBinary(data []byte) error {
+	return cbor.Unmarshal(data, (*marshallableEvidence)(e))
+}
+
+// marshallableEvidence has the fields of Evidence, without its methods, so that cbor doesn't call them recursively.
+type marshallableEvidence Evidence
+
+// evidence returns the Evidence against the culprits of an abort in round number,
+// starting with offending if it caused the abort.
+// It returns nil unless messages are signed, and the culprits misbehaved or sent invalid messages.
+func (h *MultiHandler) evidence(err error, category Category, number round.Number, offending *Message, culprits []party.ID) []*Evidence {
+	if h.identities == nil || (category != CategoryMisbehavior && category != CategoryDecode) {
+		return nil
+	}
+	var evidence []*Evidence
+	for _, id := range culprits {
+		if id == h.currentRound.SelfID() {
+			continue
+		}
+		e := &Evidence{
+			SSID:     h.currentRound.SSID(),
+			Protocol: h.currentRound.ProtocolID(),
+			Round:    number,
+			Culprit:  id,
+			Category: category,
+			Reason:   err.Error(),
+		}
+		if offending != nil && offending.From == id {
+			e.Messages = append(e.Messages, offending)
+		}
+		for n := round.Number(1); n <= h.currentRound.FinalRoundNumber(); n++ {
+			for _, msg := range []*Message{h.broadcast[n][id], h.messages[n][id]} {
+				switch {
+				case msg == nil || msg == offending:
+				case n < number:
+					e.Transcript = append(e.Transcript, msg)
+				case n == number:
+					e.Messages = append(e.Messages, msg)
+				}
+			}
+		}
+		if len(e.Messages) > 0 {
+			evidence = append(evidence, e)
+		}
+	}
+	return evidence
+}
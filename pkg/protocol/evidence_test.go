@@ -0,0 +1,77 @@
+package protocol_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestEvidence(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	keys := make(map[party.ID]ed25519.PrivateKey, len(ids))
+	identities := make(map[party.ID]ed25519.PublicKey, len(ids))
+	for _, id := range ids {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		keys[id], identities[id] = private, public
+	}
+	sign := func(msg *protocol.Message) {
+		msg.Signature, _ = keys[msg.From].Sign(nil, msg.Hash(), &ed25519.Options{Context: "multi-party-sig protocol.Message"})
+	}
+
+	h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), []byte("session"), protocol.WithIdentity(keys["a"], identities))
+	require.NoError(t, err)
+	ssid := (<-h.Listen()).SSID
+
+	// b replays the message of another session in this one, and signs it, so that its proof fails
+	other, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), []byte("other"), protocol.WithIdentity(keys["b"], identities))
+	require.NoError(t, err)
+	msg := <-other.Listen()
+	msg.SSID = ssid
+	sign(msg)
+	h.Accept(msg)
+
+	_, err = h.Result()
+	var protocolErr protocol.Error
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, protocol.CategoryMisbehavior, protocolErr.Category)
+	require.Len(t, protocolErr.Evidence, 1)
+	evidence := protocolErr.Evidence[0]
+	assert.Equal(t, party.ID("b"), evidence.Culprit)
+	assert.Equal(t, msg.RoundNumber, evidence.Round)
+	assert.Equal(t, protocolErr.Err.Error(), evidence.Reason)
+	require.NotEmpty(t, evidence.Messages)
+	assert.Equal(t, msg, evidence.Messages[0])
+
+	// an arbiter decodes the evidence, and checks it against its own view of the session
+	data, err := evidence.MarshalBinary()
+	require.NoError(t, err)
+	decoded := new(protocol.Evidence)
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.NoError(t, decoded.Verify(identities, ssid))
+	assert.Error(t, decoded.Verify(identities, []byte("another session")))
+
+	decoded.Culprit = "c"
+	assert.Error(t, decoded.Verify(identities, ssid), "c didn't sign b's message")
+	decoded.Culprit = "b"
+	decoded.Messages[0].Data = append(decoded.Messages[0].Data, 0)
+	assert.Error(t, decoded.Verify(identities, ssid), "the message was altered")
+
+	// without identities, messages can be denied, so no evidence is produced
+	h, err = protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), []byte("session"))
+	require.NoError(t, err)
+	msg.Signature = nil
+	h.Accept(msg)
+	_, err = h.Result()
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, protocol.CategoryMisbehavior, protocolErr.Category)
+	assert.Empty(t, protocolErr.Evidence)
+}
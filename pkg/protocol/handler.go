@@ -2,14 +2,18 @@ package protocol
 
 import (
 	"bytes"
+	"context"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
-	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/broadcast"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 )
 
 // StartFunc is function that creates the first round of a protocol.
@@ -20,10 +24,15 @@ import (
 type StartFunc func(sessionID []byte) (round.Session, error)
 
 // Handler represents some kind of handler for a protocol.
+//
+// It is implemented by MultiHandler and TwoPartyHandler, and consumed by functions such as transport.Run,
+// so that applications can wrap a handler, for instance to persist or count the messages it accepts, and pass the wrapper instead.
+// Implementations must be safe for concurrent use, since Listen and Accept are typically called from different goroutines.
 type Handler interface {
 	// Result should return the result of running the protocol, or an error
 	Result() (interface{}, error)
-	// Listen returns a channel which will receive new messages
+	// Listen returns a channel which will receive new messages.
+	// It must be closed once the protocol has finished, after which Result returns its outcome.
 	Listen() <-chan *Message
 	// Stop should abort the protocol execution.
 	Stop()
@@ -33,6 +42,11 @@ type Handler interface {
 	Accept(msg *Message)
 }
 
+var (
+	_ Handler = (*MultiHandler)(nil)
+	_ Handler = (*TwoPartyHandler)(nil)
+)
+
 // MultiHandler represents an execution of a given protocol.
 // It provides a simple interface for the user to receive/deliver protocol messages.
 type MultiHandler struct {
@@ -44,15 +58,110 @@ type MultiHandler struct {
 	broadcast       map[round.Number]map[party.ID]*Message
 	broadcastHashes map[round.Number][]byte
 	out             chan *Message
-	mtx             sync.Mutex
+	// finished is closed once out is closed
+	finished chan struct{}
+	mtx      sync.Mutex
+
+	// sessionID is the one given when the protocol was started
+	sessionID []byte
+	// sent contains the messages produced when the current round was reached
+	sent []*Message
+
+	// parallel is true if messages are verified together once the round has received all of them
+	parallel     bool
+	verification *pool.Pool
+	// ctx is cancelled once the protocol is stopped or has finished, to abandon parallel verifications
+	ctx  context.Context
+	stop context.CancelCauseFunc
+
+	// roundTimeout is the time each round may wait for its messages, if not zero
+	roundTimeout time.Duration
+	clock        Clock
+	roundTimer   Timer
+
+	observer Observer
+	logger   Logger
+	// sessionStart and roundStart are the times at which the handler was created, and the current round reached
+	sessionStart time.Time
+	roundStart   time.Time
+
+	// replays records the messages accepted by this handler and previous ones, if not nil
+	replays ReplayStore
+	// resumed is true if the handler was resumed from a snapshot, whose messages then replace replays
+	resumed bool
+
+	// transcriptSink receives the transcript of the session, if not nil
+	transcriptSink  TranscriptSink
+	transcriptLen   uint64
+	transcriptChain []byte
+
+	// limits bound the size of the messages accepted
+	limits Limits
+	// bufferLimits bound the messages kept for future rounds
+	bufferLimits BufferLimits
+	// rateLimit bounds the rate of the messages of each party, if allowances is not nil
+	rateLimit  RateLimit
+	allowances map[party.ID]*allowance
+
+	// polled is true if outgoing messages are kept in pending for a PollHandler, instead of being sent on out
+	polled  bool
+	pending []*Message
+
+	// identity signs the messages sent, and identities verify those received, if not nil
+	identity   ed25519.PrivateKey
+	identities map[party.ID]ed25519.PublicKey
+}
+
+// ErrRoundTimeout is the error returned by MultiHandler.Result when a round did not receive all its messages in time.
+var ErrRoundTimeout = errors.New("protocol: round timed out")
+
+// HandlerOption configures a MultiHandler.
+type HandlerOption func(*MultiHandler)
+
+// WithParallelVerification makes the MultiHandler wait until a round has received the messages of all parties,
+// and then run VerifyMessage for all senders concurrently on pl, instead of verifying each message as it is accepted.
+//
+// pl must not be the pool used by the protocol itself, since verifying a message may also use that pool,
+// and a Pool cannot be used from within one of its own workers.
+//
+// If the handler is stopped during a verification, the messages which haven't been verified yet are skipped.
+func WithParallelVerification(pl *pool.Pool) HandlerOption {
+	return func(h *MultiHandler) {
+		h.parallel = true
+		h.verification = pl
+	}
+}
+
+// WithRoundTimeout aborts the protocol with ErrRoundTimeout if a round has not received all its messages
+// within timeout of being reached, blaming the parties which did not send theirs.
+//
+// The timeout is measured with clock, or SystemClock if clock is nil.
+func WithRoundTimeout(timeout time.Duration, clock Clock) HandlerOption {
+	return func(h *MultiHandler) {
+		if clock == nil {
+			clock = SystemClock
+		}
+		h.roundTimeout = timeout
+		h.clock = clock
+	}
 }
 
 // NewMultiHandler expects a StartFunc for the desired protocol. It returns a handler that the user can interact with.
-func NewMultiHandler(create StartFunc, sessionID []byte) (*MultiHandler, error) {
+func NewMultiHandler(create StartFunc, sessionID []byte, opts ...HandlerOption) (*MultiHandler, error) {
 	r, err := create(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("protocol: failed to create round: %w", err)
 	}
+	h := newMultiHandler(r, sessionID, opts)
+	h.mtx.Lock()
+	h.startRound()
+	h.finalize()
+	h.mtx.Unlock()
+	return h, nil
+}
+
+// newMultiHandler returns a handler whose current round is r, without finalizing it.
+func newMultiHandler(r round.Session, sessionID []byte, opts []HandlerOption) *MultiHandler {
 	h := &MultiHandler{
 		currentRound:    r,
 		rounds:          map[round.Number]round.Session{r.Number(): r},
@@ -60,8 +169,37 @@ func NewMultiHandler(create StartFunc, sessionID []byte) (*MultiHandler, error)
 		broadcast:       newQueue(r.OtherPartyIDs(), r.FinalRoundNumber()),
 		broadcastHashes: map[round.Number][]byte{},
 		out:             make(chan *Message, 2*r.N()),
+		finished:        make(chan struct{}),
+		sessionID:       sessionID,
+		clock:           SystemClock,
+		observer:        NopObserver{},
+		limits:          DefaultLimits(),
+	}
+	h.ctx, h.stop = context.WithCancelCause(context.Background())
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.sessionStart = h.clock.Now()
+	return h
+}
+
+// NewMultiHandlerContext is like NewMultiHandler, but aborts the protocol once ctx is done,
+// with ctx.Err() as the underlying error, closing the channel returned by Listen.
+//
+// Rounds are not interrupted while they are being finalized, so the abort may only happen
+// once the computation triggered by the last accepted message has finished.
+func NewMultiHandlerContext(ctx context.Context, create StartFunc, sessionID []byte, opts ...HandlerOption) (*MultiHandler, error) {
+	h, err := NewMultiHandler(create, sessionID, opts...)
+	if err != nil {
+		return nil, err
 	}
-	h.finalize()
+	go func() {
+		select {
+		case <-ctx.Done():
+			h.cancel(ctx.Err())
+		case <-h.finished:
+		}
+	}()
 	return h, nil
 }
 
@@ -89,6 +227,13 @@ func (h *MultiHandler) Listen() <-chan *Message {
 
 // CanAccept returns true if the message is designated for this protocol protocol execution.
 func (h *MultiHandler) CanAccept(msg *Message) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.canAccept(msg)
+}
+
+// canAccept implements CanAccept. It must be called with mtx held, since the current round changes.
+func (h *MultiHandler) canAccept(msg *Message) bool {
 	r := h.currentRound
 	if msg == nil {
 		return false
@@ -130,40 +275,112 @@ func (h *MultiHandler) CanAccept(msg *Message) bool {
 // Accept tries to process the given message. If an abort occurs, the channel returned by Listen() is closed,
 // and an error is returned by Result().
 //
+// Messages go through the cheap checks first: recipient, session, sender, round, duplicates and size.
+// Only then is their signature checked, their sender charged for the RateLimit,
+// the structure of their content compared to the Limits, and their content decoded and verified.
+//
 // This function may be called concurrently from different threads but may block until all previous calls have finished.
 func (h *MultiHandler) Accept(msg *Message) {
+	h.accept(msg)
+}
+
+// accept is Accept, and returns true if msg was new and kept by the handler,
+// even if it then aborted the session.
+func (h *MultiHandler) accept(msg *Message) bool {
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
 
 	// exit early if the message is bad, or if we are already done
-	if !h.CanAccept(msg) || h.err != nil || h.result != nil || h.duplicate(msg) {
-		return
+	if !h.canAccept(msg) || h.err != nil || h.result != nil {
+		return false
+	}
+
+	// the cheap checks come first, so that floods are dropped before the signature and the content are processed
+	if h.duplicate(msg) {
+		return false
+	}
+	if err := h.limits.checkSize(msg); err != nil {
+		// without identities, the sender can't be authenticated anyway
+		if h.identities == nil {
+			h.abortAt(msg.RoundNumber, msg, err, msg.From)
+		}
+		return false
+	}
+
+	// forged messages are dropped rather than blamed on their supposed sender,
+	// and don't consume its allowance
+	if !h.authentic(msg) || !h.allow(msg.From) {
+		return false
+	}
+
+	if err := checkVersion(msg); err != nil {
+		h.abortAt(msg.RoundNumber, msg, err, msg.From)
+		return false
+	}
+
+	if err := h.limits.check(msg); err != nil {
+		h.abortAt(msg.RoundNumber, msg, err, msg.From)
+		return false
+	}
+
+	if keep, err := h.buffer(msg); err != nil {
+		h.abortAt(msg.RoundNumber, msg, err, msg.From)
+		return false
+	} else if !keep {
+		return false
 	}
 
 	// a msg with roundNumber 0 is considered an abort from another party
 	if msg.RoundNumber == 0 {
-		h.abort(fmt.Errorf("aborted by other party with error: \"%s\"", msg.Data), msg.From)
-		return
+		h.abort(fmt.Errorf("%w: \"%s\"", ErrAbortedByPeer, msg.Data), msg.From)
+		return false
 	}
 
+	if h.replays != nil {
+		replayed, err := h.replays.Record(ReplayKey{
+			SSID:      msg.SSID,
+			From:      msg.From,
+			To:        h.currentRound.SelfID(),
+			Round:     msg.RoundNumber,
+			Broadcast: msg.Broadcast,
+		})
+		if err != nil {
+			h.abort(err, h.currentRound.SelfID())
+			return false
+		}
+		// a resumed handler may have lost the messages it accepted after its snapshot was taken,
+		// and it already rejects those in the snapshot, so the redelivered ones must be accepted again
+		if replayed && !h.resumed {
+			return false
+		}
+	}
+
+	info := h.roundInfo(h.currentRound)
+	info.Round = msg.RoundNumber
+	h.observer.MessageReceived(info, msg.From, msg.Broadcast, len(msg.Data))
+	if err := h.record(msg, false); err != nil {
+		h.abort(err, h.currentRound.SelfID())
+		return false
+	}
 	h.store(msg)
 	if h.currentRound.Number() != msg.RoundNumber {
-		return
+		return true
 	}
 
 	if msg.Broadcast {
 		if err := h.verifyBroadcastMessage(msg); err != nil {
-			h.abort(err, msg.From)
-			return
+			h.abortAt(msg.RoundNumber, msg, err, msg.From)
+			return true
 		}
 	} else {
 		if err := h.verifyMessage(msg); err != nil {
-			h.abort(err, msg.From)
-			return
+			h.abortAt(msg.RoundNumber, msg, err, msg.From)
+			return true
 		}
 	}
 
 	h.finalize()
+	return true
 }
 
 func (h *MultiHandler) verifyBroadcastMessage(msg *Message) error {
@@ -172,17 +389,13 @@ func (h *MultiHandler) verifyBroadcastMessage(msg *Message) error {
 		return nil
 	}
 
-	// try to convert the raw message into a round.Message
-	roundMsg, err := getRoundMessage(msg, r)
+	start := h.clock.Now()
+	err := storeBroadcastMessage(r, msg)
+	h.observer.MessageVerified(h.roundInfo(r), msg.From, true, h.clock.Now().Sub(start), err)
 	if err != nil {
 		return err
 	}
 
-	// store the broadcast message for this round
-	if err = r.(round.BroadcastRound).StoreBroadcastMessage(roundMsg); err != nil {
-		return fmt.Errorf("round %d: %w", r.Number(), err)
-	}
-
 	// if the round only expected a broadcast message, we can safely return
 	if !expectsNormalMessage(r) {
 		return nil
@@ -205,6 +418,15 @@ func (h *MultiHandler) verifyMessage(msg *Message) error {
 		return nil
 	}
 
+	if !expectsMessageFrom(r, msg.From) {
+		return fmt.Errorf("round %d: unexpected message from %s", r.Number(), msg.From)
+	}
+
+	// the message will be verified once all of them have been received
+	if h.parallel {
+		return nil
+	}
+
 	// exit if we don't yet have the broadcast message
 	if _, ok = r.(round.BroadcastRound); ok {
 		q := h.broadcast[msg.RoundNumber]
@@ -213,6 +435,29 @@ func (h *MultiHandler) verifyMessage(msg *Message) error {
 		}
 	}
 
+	start := h.clock.Now()
+	err := verifyMessage(r, msg)
+	h.observer.MessageVerified(h.roundInfo(r), msg.From, false, h.clock.Now().Sub(start), err)
+	return err
+}
+
+// storeBroadcastMessage decodes msg, and stores it as the broadcast message of its sender in r.
+func storeBroadcastMessage(r round.Session, msg *Message) error {
+	// try to convert the raw message into a round.Message
+	roundMsg, err := getRoundMessage(msg, r)
+	if err != nil {
+		return err
+	}
+
+	// store the broadcast message for this round
+	if err = r.(round.BroadcastRound).StoreBroadcastMessage(roundMsg); err != nil {
+		return fmt.Errorf("round %d: %w", r.Number(), err)
+	}
+	return nil
+}
+
+// verifyMessage decodes msg, verifies it, and stores it in r.
+func verifyMessage(r round.Session, msg *Message) error {
 	roundMsg, err := getRoundMessage(msg, r)
 	if err != nil {
 		return err
@@ -230,15 +475,67 @@ func (h *MultiHandler) verifyMessage(msg *Message) error {
 	return nil
 }
 
+// verifyAll verifies the normal messages of the current round concurrently, and then stores them one by one.
+// If a message is invalid, its sender is returned with the error.
+func (h *MultiHandler) verifyAll() (party.ID, error) {
+	r := h.currentRound
+	number := r.Number()
+	if !expectsNormalMessage(r) || h.messages[number] == nil {
+		return "", nil
+	}
+	senders := messageSenders(r)
+	roundMsgs := make([]round.Message, len(senders))
+	elapsed := make([]time.Duration, len(senders))
+	errs, err := h.verification.ParallelizeContext(h.ctx, len(senders), func(i int) interface{} {
+		start := h.clock.Now()
+		defer func() { elapsed[i] = h.clock.Now().Sub(start) }()
+		roundMsg, err := getRoundMessage(h.messages[number][senders[i]], r)
+		if err != nil {
+			return err
+		}
+		if err = r.VerifyMessage(roundMsg); err != nil {
+			return fmt.Errorf("round %d: %w", number, err)
+		}
+		roundMsgs[i] = roundMsg
+		return nil
+	})
+	if err != nil {
+		return r.SelfID(), context.Cause(h.ctx)
+	}
+	for i, err := range errs {
+		if err != nil {
+			h.observer.MessageVerified(h.roundInfo(r), senders[i], false, elapsed[i], err.(error))
+			return senders[i], err.(error)
+		}
+	}
+	for i, roundMsg := range roundMsgs {
+		err := r.StoreMessage(roundMsg)
+		if err != nil {
+			err = fmt.Errorf("round %d: %w", number, err)
+		}
+		h.observer.MessageVerified(h.roundInfo(r), senders[i], false, elapsed[i], err)
+		if err != nil {
+			return senders[i], err
+		}
+	}
+	return "", nil
+}
+
 func (h *MultiHandler) finalize() {
 	// only finalize if we have received all messages
 	if !h.receivedAll() {
 		return
 	}
 	if !h.checkBroadcastHash() {
-		h.abort(errors.New("broadcast verification failed"))
+		h.abort(ErrBroadcastMismatch)
 		return
 	}
+	if h.parallel {
+		if culprit, err := h.verifyAll(); err != nil {
+			h.abort(err, culprit)
+			return
+		}
+	}
 
 	out := make(chan *round.Message, h.currentRound.N()+1)
 	// since we pass a large enough channel, we should never get an error
@@ -250,14 +547,17 @@ func (h *MultiHandler) finalize() {
 		h.abort(err, h.currentRound.SelfID())
 		return
 	}
+	finished := h.roundInfo(h.currentRound)
 
 	// forward messages with the correct header.
+	var sent []*Message
 	for roundMsg := range out {
 		data, err := cbor.Marshal(roundMsg.Content)
 		if err != nil {
 			panic(fmt.Errorf("failed to marshal round message: %w", err))
 		}
 		msg := &Message{
+			Version:               WireVersion,
 			SSID:                  r.SSID(),
 			From:                  r.SelfID(),
 			To:                    roundMsg.To,
@@ -267,10 +567,19 @@ func (h *MultiHandler) finalize() {
 			Broadcast:             roundMsg.Broadcast,
 			BroadcastVerification: h.broadcastHashes[r.Number()-1],
 		}
+		h.sign(msg)
+		if err = h.record(msg, true); err != nil {
+			h.abort(err, r.SelfID())
+			return
+		}
 		if msg.Broadcast {
 			h.store(msg)
 		}
-		h.out <- msg
+		sent = append(sent, msg)
+		h.send(msg)
+		info := h.roundInfo(r)
+		info.Round = msg.RoundNumber
+		h.observer.MessageSent(info, msg.To, msg.Broadcast, len(msg.Data))
 	}
 
 	roundNumber := r.Number()
@@ -278,14 +587,16 @@ func (h *MultiHandler) finalize() {
 	if _, ok := h.rounds[roundNumber]; ok {
 		return
 	}
+	h.observer.RoundFinished(finished, h.clock.Now().Sub(h.roundStart))
 	h.rounds[roundNumber] = r
 	h.currentRound = r
+	h.sent = sent
 
 	// either we get the current round, the next one, or one of the two final ones
 	switch R := r.(type) {
 	// An abort happened
 	case *round.Abort:
-		h.abort(R.Err, R.Culprits...)
+		h.abortAt(finished.Round, nil, R.Err, R.Culprits...)
 		return
 	// We have the result
 	case *round.Output:
@@ -294,17 +605,31 @@ func (h *MultiHandler) finalize() {
 		return
 	default:
 	}
+	h.startRound()
+
+	// if false, we aborted and so we return
+	if !h.verifyQueued() {
+		return
+	}
+
+	// we only do this if the current round has changed
+	h.finalize()
+}
 
+// verifyQueued handles the messages received for the current round before it was reached.
+// It returns false if one of them was invalid, in which case the protocol was aborted.
+func (h *MultiHandler) verifyQueued() bool {
+	r := h.currentRound
+	roundNumber := r.Number()
 	if _, ok := r.(round.BroadcastRound); ok {
 		// handle queued broadcast messages, which will then check the subsequent normal message
 		for id, m := range h.broadcast[roundNumber] {
 			if m == nil || id == r.SelfID() {
 				continue
 			}
-			// if false, we aborted and so we return
-			if err = h.verifyBroadcastMessage(m); err != nil {
-				h.abort(err, m.From)
-				return
+			if err := h.verifyBroadcastMessage(m); err != nil {
+				h.abortAt(roundNumber, m, err, m.From)
+				return false
 			}
 		}
 	} else {
@@ -313,49 +638,214 @@ func (h *MultiHandler) finalize() {
 			if m == nil {
 				continue
 			}
-			// if false, we aborted and so we return
-			if err = h.verifyMessage(m); err != nil {
-				h.abort(err, m.From)
-				return
+			if err := h.verifyMessage(m); err != nil {
+				h.abortAt(roundNumber, m, err, m.From)
+				return false
 			}
 		}
 	}
-
-	// we only do this if the current round has changed
-	h.finalize()
+	return true
 }
 
 func (h *MultiHandler) abort(err error, culprits ...party.ID) {
+	h.abortAt(h.currentRound.Number(), nil, err, culprits...)
+}
+
+// abortAt ends the session, with err if it failed in round number, in which case the other parties are told.
+// offending is the message which caused the failure, if there is one.
+func (h *MultiHandler) abortAt(number round.Number, offending *Message, err error, culprits ...party.ID) {
 	if err != nil {
+		category := categorize(err, h.currentRound.SelfID(), culprits)
 		h.err = &Error{
 			Culprits: culprits,
 			Err:      err,
+			Protocol: h.currentRound.ProtocolID(),
+			Round:    number,
+			Category: category,
+			Evidence: h.evidence(err, category, number, offending, culprits),
 		}
-		select {
-		case h.out <- &Message{
+		msg := &Message{
+			Version:  WireVersion,
 			SSID:     h.currentRound.SSID(),
 			From:     h.currentRound.SelfID(),
 			Protocol: h.currentRound.ProtocolID(),
 			Data:     []byte(h.err.Error()),
-		}:
-		default:
 		}
-
+		h.sign(msg)
+		// the session ends anyway, even if the sink fails
+		_ = h.record(msg, true)
+		if h.polled {
+			h.pending = append(h.pending, msg)
+		} else {
+			select {
+			case h.out <- msg:
+			default:
+			}
+		}
+	}
+	if h.roundTimer != nil {
+		h.roundTimer.Stop()
+	}
+	h.stop(err)
+	if err != nil {
+		err = h.err
 	}
+	h.observer.SessionFinished(h.roundInfo(h.currentRound), h.clock.Now().Sub(h.sessionStart), err)
 	close(h.out)
+	close(h.finished)
+}
+
+// send queues msg for the other parties, on out, or in pending for a PollHandler.
+func (h *MultiHandler) send(msg *Message) {
+	if h.polled {
+		h.pending = append(h.pending, msg)
+		return
+	}
+	h.out <- msg
+}
+
+// startRoundTimer schedules the timeout of the current round, replacing the one of the previous round.
+func (h *MultiHandler) startRoundTimer() {
+	if h.roundTimeout <= 0 {
+		return
+	}
+	if h.roundTimer != nil {
+		h.roundTimer.Stop()
+	}
+	number := h.currentRound.Number()
+	h.roundTimer = h.clock.AfterFunc(h.roundTimeout, func() {
+		h.mtx.Lock()
+		defer h.mtx.Unlock()
+		if h.err != nil || h.result != nil || h.currentRound.Number() != number {
+			return
+		}
+		h.abort(fmt.Errorf("%w: round %d", ErrRoundTimeout, number), h.missing()...)
+	})
 }
 
 // Stop cancels the current execution of the protocol, and alerts the other users.
 func (h *MultiHandler) Stop() {
+	h.cancel(errors.New("aborted by user"))
+}
+
+// cancel aborts the protocol with err if it is still running, blaming this party.
+//
+// Parallel verifications are abandoned first, since they run while holding the lock.
+func (h *MultiHandler) cancel(err error) {
+	h.stop(err)
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err == nil && h.result == nil {
+		h.abort(err, h.currentRound.SelfID())
+	}
+}
+
+// done returns true if the protocol has either completed or aborted.
+func (h *MultiHandler) done() bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.err != nil || h.result != nil
+}
+
+// expire aborts the protocol if it is still running, blaming the parties from which messages are still missing.
+// It returns false if the protocol had already finished.
+func (h *MultiHandler) expire() (*ExpiredSession, bool) {
+	return h.interrupt(ErrSessionExpired, true)
+}
+
+// interrupt aborts the protocol with err if it is still running, and describes the state it was in.
+// If blameMissing is false, this party is blamed instead of the parties from which messages are still missing.
+// It returns false if the protocol had already finished.
+func (h *MultiHandler) interrupt(err error, blameMissing bool) (*ExpiredSession, bool) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
 	if h.err != nil || h.result != nil {
-		h.abort(errors.New("aborted by user"), h.currentRound.SelfID())
+		return nil, false
+	}
+	interrupted := &ExpiredSession{
+		SSID:       h.currentRound.SSID(),
+		Protocol:   h.currentRound.ProtocolID(),
+		Round:      h.currentRound.Number(),
+		Transcript: h.transcript(),
+		Missing:    h.missing(),
+	}
+	if blameMissing {
+		h.abort(err, interrupted.Missing...)
+	} else {
+		h.abort(err, h.currentRound.SelfID())
+	}
+	return interrupted, true
+}
+
+// transcript returns all messages stored so far, ordered by round, and then by sender.
+func (h *MultiHandler) transcript() []*Message {
+	var messages []*Message
+	for number := round.Number(2); number <= h.currentRound.FinalRoundNumber(); number++ {
+		for _, id := range h.currentRound.PartyIDs() {
+			if msg := h.broadcast[number][id]; msg != nil {
+				messages = append(messages, msg)
+			}
+			if msg := h.messages[number][id]; msg != nil {
+				messages = append(messages, msg)
+			}
+		}
+	}
+	return messages
+}
+
+// completed returns a description of the session if the protocol produced a result.
+func (h *MultiHandler) completed() (*CompletedSession, bool) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.result == nil {
+		return nil, false
+	}
+	return &CompletedSession{
+		SSID:     h.currentRound.SSID(),
+		Protocol: h.currentRound.ProtocolID(),
+		Parties:  h.currentRound.PartyIDs().Copy(),
+	}, true
+}
+
+// missing returns the parties from which we are still waiting for a message in the current round.
+func (h *MultiHandler) missing() []party.ID {
+	r := h.currentRound
+	number := r.Number()
+	var missing []party.ID
+	for _, id := range r.OtherPartyIDs() {
+		_, expectsBroadcast := r.(round.BroadcastRound)
+		if expectsBroadcast && h.broadcast[number] != nil && h.broadcast[number][id] == nil {
+			missing = append(missing, id)
+			continue
+		}
+		if expectsNormalMessage(r) && h.messages[number] != nil && h.messages[number][id] == nil && expectsMessageFrom(r, id) {
+			missing = append(missing, id)
+		}
 	}
+	return missing
 }
 
 func expectsNormalMessage(r round.Session) bool {
 	return r.MessageContent() != nil
 }
 
+// messageSenders returns the parties from which r expects a normal message.
+func messageSenders(r round.Session) []party.ID {
+	if p, ok := r.(round.PartialRound); ok {
+		return p.Senders()
+	}
+	return r.OtherPartyIDs()
+}
+
+func expectsMessageFrom(r round.Session, id party.ID) bool {
+	for _, sender := range messageSenders(r) {
+		if sender == id {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *MultiHandler) receivedAll() bool {
 	r := h.currentRound
 	number := r.Number()
@@ -371,17 +861,16 @@ func (h *MultiHandler) receivedAll() bool {
 			}
 		}
 
-		// create hash of all message for this round
-		if h.broadcastHashes[number] == nil {
-			hashState := r.Hash()
+		// create hash of all message for this round.
+		// With only two parties, each broadcast has a single recipient, so there is nobody
+		// to equivocate to: the digest is neither computed, nor attached to and checked against
+		// the messages of the next round. There is no separate echo round to skip, so this only saves the hashing.
+		if h.broadcastHashes[number] == nil && r.N() > 2 {
+			echo := broadcast.New(r.PartyIDs(), r.Hash())
 			for _, id := range r.PartyIDs() {
-				msg := h.broadcast[number][id]
-				_ = hashState.WriteAny(&hash.BytesWithDomain{
-					TheDomain: "Message",
-					Bytes:     msg.Hash(),
-				})
+				_ = echo.Receive(id, h.broadcast[number][id].Hash())
 			}
-			h.broadcastHashes[number] = hashState.Sum()
+			h.broadcastHashes[number] = echo.Digest()
 		}
 	}
 
@@ -390,7 +879,7 @@ func (h *MultiHandler) receivedAll() bool {
 		if h.messages[number] == nil {
 			return true
 		}
-		for _, id := range r.OtherPartyIDs() {
+		for _, id := range messageSenders(r) {
 			if h.messages[number][id] == nil {
 				return false
 			}
@@ -447,7 +936,7 @@ func getRoundMessage(msg *Message, r round.Session) (round.Message, error) {
 
 	// unmarshal message
 	if err := cbor.Unmarshal(msg.Data, content); err != nil {
-		return round.Message{}, fmt.Errorf("failed to unmarshal: %w", err)
+		return round.Message{}, fmt.Errorf("%w: %w", ErrDecode, err)
 	}
 	roundMsg := round.Message{
 		From:      msg.From,
@@ -0,0 +1,557 @@
+package protocol_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// runKeygen runs frost keygen between ids, and returns the messages that were exchanged.
+func runKeygen(t *testing.T, ids party.IDSlice, opts ...protocol.HandlerOption) []*protocol.Message {
+	group := curve.Secp256k1{}
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(ids))
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, len(ids)-1), []byte("session"), opts...)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+
+	transcript := deliver(ids, handlers)
+	for _, id := range ids {
+		_, err := handlers[id].Result()
+		require.NoError(t, err)
+	}
+	return transcript
+}
+
+// deliver passes the messages sent by handlers to their recipients until there is none left,
+// and returns them in the order they were sent.
+func deliver(ids party.IDSlice, handlers map[party.ID]*protocol.MultiHandler) []*protocol.Message {
+	var transcript []*protocol.Message
+	for delivered := true; delivered; {
+		delivered = false
+		for _, id := range ids {
+			for len(handlers[id].Listen()) > 0 {
+				msg := <-handlers[id].Listen()
+				if msg == nil {
+					break
+				}
+				transcript = append(transcript, msg)
+				delivered = true
+				for _, other := range ids {
+					if other != id && msg.IsFor(other) {
+						handlers[other].Accept(msg)
+					}
+				}
+			}
+		}
+	}
+	return transcript
+}
+
+func TestMultiHandlerTwoParties(t *testing.T) {
+	for _, msg := range runKeygen(t, party.IDSlice{"a", "b"}) {
+		assert.Nil(t, msg.BroadcastVerification, "two party sessions should skip the echo broadcast")
+	}
+
+	verified := 0
+	for _, msg := range runKeygen(t, party.IDSlice{"a", "b", "c"}) {
+		if msg.BroadcastVerification != nil {
+			verified++
+		}
+	}
+	assert.NotZero(t, verified)
+}
+
+func TestMultiHandlerParallelVerification(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	runKeygen(t, party.IDSlice{"a", "b", "c", "d"}, protocol.WithParallelVerification(pl))
+	runKeygen(t, party.IDSlice{"a", "b", "c"}, protocol.WithParallelVerification(nil))
+}
+
+func TestMultiHandlerPartialRound(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c", "d"}
+	message := []byte("hello")
+
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(ids))
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, 2), []byte("keygen"))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	deliver(ids, handlers)
+	configs := make(map[party.ID]*frost.Config, len(ids))
+	for _, id := range ids {
+		r, err := handlers[id].Result()
+		require.NoError(t, err)
+		configs[id] = r.(*frost.Config)
+	}
+
+	for _, opts := range [][]protocol.HandlerOption{nil, {protocol.WithParallelVerification(nil)}} {
+		for _, id := range ids {
+			h, err := protocol.NewMultiHandler(frost.SignWithAggregator(configs[id], ids, "a", message), []byte("sign"), opts...)
+			require.NoError(t, err)
+			handlers[id] = h
+		}
+		// the others only talk to the aggregator, and wait for it in every round
+		for _, msg := range deliver(ids, handlers) {
+			assert.True(t, msg.From == "a" || msg.To == "a", "message from %s to %s", msg.From, msg.To)
+		}
+		for _, id := range ids {
+			r, err := handlers[id].Result()
+			require.NoError(t, err)
+			assert.True(t, r.(frost.Signature).Verify(configs[id].PublicKey, message))
+		}
+	}
+
+	// a message from a party other than the aggregator is rejected
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(frost.SignWithAggregator(configs[id], ids, "a", message), []byte("sign"))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	msg := <-handlers["b"].Listen()
+	msg.To = "c"
+	msg.RoundNumber = 3
+	handlers["c"].Accept(msg)
+	_, err := handlers["c"].Result()
+	var protocolErr protocol.Error
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, []party.ID{"b"}, protocolErr.Culprits)
+}
+
+// countingHandler wraps a Handler, counting the messages it accepts.
+type countingHandler struct {
+	protocol.Handler
+	accepted int64
+}
+
+func (h *countingHandler) Accept(msg *protocol.Message) {
+	atomic.AddInt64(&h.accepted, 1)
+	h.Handler.Accept(msg)
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	network := transport.NewMemoryNetwork(ids)
+
+	var wg sync.WaitGroup
+	handlers := make([]*countingHandler, 0, len(ids))
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, 1), []byte("session"))
+		require.NoError(t, err)
+		wrapper := &countingHandler{Handler: h}
+		handlers = append(handlers, wrapper)
+		wg.Add(1)
+		go func(id party.ID) {
+			defer wg.Done()
+			_, err := transport.Run(context.Background(), wrapper, network.Transport(id))
+			assert.NoError(t, err)
+		}(id)
+	}
+	wg.Wait()
+	for _, h := range handlers {
+		assert.NotZero(t, atomic.LoadInt64(&h.accepted))
+	}
+}
+
+func TestMultiHandlerContext(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h, err := protocol.NewMultiHandlerContext(ctx, frost.Keygen(group, "a", ids, 1), []byte("session"))
+	require.NoError(t, err)
+	cancel()
+
+	closed := make(chan struct{})
+	go func() {
+		for range h.Listen() {
+		}
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Listen was not closed after cancellation")
+	}
+	_, err = h.Result()
+	assert.ErrorIs(t, err, context.Canceled)
+
+	h, err = protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), []byte("session"))
+	require.NoError(t, err)
+	h.Stop()
+	h.Stop()
+	_, err = h.Result()
+	assert.Error(t, err)
+}
+
+func TestMultiHandlerRoundTimeout(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+
+	clock := protocol.NewManualClock(time.Unix(0, 0))
+	h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), sessionID, protocol.WithRoundTimeout(10*time.Second, clock))
+	require.NoError(t, err)
+
+	// b participates, but c never answers
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), sessionID)
+	require.NoError(t, err)
+	for len(hb.Listen()) > 0 {
+		if msg := <-hb.Listen(); msg.IsFor("a") {
+			h.Accept(msg)
+		}
+	}
+
+	clock.Advance(9 * time.Second)
+	_, err = h.Result()
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, protocol.ErrRoundTimeout)
+
+	clock.Advance(time.Second)
+	_, err = h.Result()
+	assert.ErrorIs(t, err, protocol.ErrRoundTimeout)
+	var protocolErr protocol.Error
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, []party.ID{"c"}, protocolErr.Culprits)
+	assert.Equal(t, protocol.CategoryTimeout, protocolErr.Category)
+}
+
+func TestMultiHandlerVersion(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+
+	h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), sessionID)
+	require.NoError(t, err)
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), sessionID)
+	require.NoError(t, err)
+
+	msg := <-hb.Listen()
+	assert.Equal(t, uint8(protocol.WireVersion), msg.Version)
+	// as sent by a release which doesn't know about versions
+	msg.Version = 0
+	h.Accept(msg)
+
+	_, err = h.Result()
+	assert.ErrorIs(t, err, protocol.ErrIncompatibleVersion)
+	var protocolErr protocol.Error
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, []party.ID{"b"}, protocolErr.Culprits)
+}
+
+func TestMultiHandlerErrorCategory(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), sessionID)
+	require.NoError(t, err)
+	msg := <-hb.Listen()
+
+	// content which isn't valid CBOR
+	h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), sessionID)
+	require.NoError(t, err)
+	garbage := *msg
+	garbage.Data = []byte{0xff}
+	h.Accept(&garbage)
+	_, err = h.Result()
+	assert.ErrorIs(t, err, protocol.ErrDecode)
+	var protocolErr protocol.Error
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, protocol.CategoryDecode, protocolErr.Category)
+	assert.Equal(t, []party.ID{"b"}, protocolErr.Culprits)
+	assert.Equal(t, "frost/keygen-threshold", protocolErr.Protocol)
+	assert.Equal(t, msg.RoundNumber, protocolErr.Round)
+
+	// "b" gives up, and tells the others
+	hb.Stop()
+	_, err = hb.Result()
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, protocol.CategoryLocal, protocolErr.Category)
+	var abort *protocol.Message
+	for m := range hb.Listen() {
+		abort = m
+	}
+	require.NotNil(t, abort)
+
+	h, err = protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), sessionID)
+	require.NoError(t, err)
+	h.Accept(abort)
+	_, err = h.Result()
+	assert.ErrorIs(t, err, protocol.ErrAbortedByPeer)
+	require.ErrorAs(t, err, &protocolErr)
+	assert.Equal(t, protocol.CategoryAborted, protocolErr.Category)
+	assert.Equal(t, "aborted", protocolErr.Category.String())
+}
+
+func TestMultiHandlerLimits(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), sessionID)
+	require.NoError(t, err)
+	msg := <-hb.Listen()
+
+	// the default limits accept the messages of the protocol
+	runKeygen(t, ids)
+
+	contents := map[string][]byte{
+		"message size": msg.Data,
+		// a byte string announcing 2³² bytes
+		"byte string": {0x5a, 0xff, 0xff, 0xff, 0xff},
+		// an array announcing 2³² elements
+		"elements": {0x9a, 0xff, 0xff, 0xff, 0xff},
+		// nested arrays
+		"depth": bytes.Repeat([]byte{0x81}, 100),
+	}
+	for name, data := range contents {
+		t.Run(name, func(t *testing.T) {
+			limits := protocol.DefaultLimits()
+			limits.MaxMessageSize = len(msg.Data) - 1
+			h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), sessionID, protocol.WithLimits(limits))
+			require.NoError(t, err)
+
+			m := *msg
+			m.Data = data
+			h.Accept(&m)
+			_, err = h.Result()
+			assert.ErrorIs(t, err, protocol.ErrMessageTooLarge)
+			var protocolErr protocol.Error
+			require.ErrorAs(t, err, &protocolErr)
+			assert.Equal(t, []party.ID{"b"}, protocolErr.Culprits)
+		})
+	}
+
+	// with identities, oversized messages are dropped before their signature is checked,
+	// since they can't be blamed on their sender
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	identities := map[party.ID]ed25519.PublicKey{"a": public, "b": public, "c": public}
+	limits := protocol.DefaultLimits()
+	limits.MaxMessageSize = len(msg.Data) - 1
+	hb, err = protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), sessionID, protocol.WithIdentity(private, identities))
+	require.NoError(t, err)
+	h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), sessionID,
+		protocol.WithLimits(limits), protocol.WithIdentity(private, identities))
+	require.NoError(t, err)
+	h.Accept(<-hb.Listen())
+	_, err = h.Result()
+	assert.EqualError(t, err, "protocol: not finished")
+}
+
+func TestMultiHandlerSuspend(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(ids))
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, 1), sessionID)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	resume := frost.ResumeKeygen(frost.Keygen(group, "a", ids, 1))
+
+	var queue []*protocol.Message
+	collect := func(h *protocol.MultiHandler) {
+		for len(h.Listen()) > 0 {
+			if msg := <-h.Listen(); msg != nil {
+				queue = append(queue, msg)
+			}
+		}
+	}
+	for _, id := range ids {
+		collect(handlers[id])
+	}
+	resumed := 0
+	for len(queue) > 0 {
+		msg := queue[0]
+		queue = queue[1:]
+		for _, id := range ids {
+			if !msg.IsFor(id) {
+				continue
+			}
+			handlers[id].Accept(msg)
+			collect(handlers[id])
+			if id != "a" {
+				continue
+			}
+			// a is restarted after every message it receives
+			snapshot, err := handlers["a"].Suspend()
+			if err != nil {
+				continue
+			}
+			handlers["a"], err = protocol.ResumeMultiHandler(resume, snapshot)
+			require.NoError(t, err)
+			resumed++
+			collect(handlers["a"])
+		}
+	}
+	assert.NotZero(t, resumed)
+
+	var publicKey curve.Point
+	for _, id := range ids {
+		result, err := handlers[id].Result()
+		require.NoError(t, err, "party %s", id)
+		c := result.(*frost.Config)
+		if publicKey == nil {
+			publicKey = c.PublicKey
+		}
+		assert.True(t, publicKey.Equal(c.PublicKey))
+	}
+}
+
+// countingObserver counts the notifications of a handler.
+type countingObserver struct {
+	protocol.NopObserver
+	started, finished     int
+	received, verified    int
+	receivedBytes, sentTo int
+	sessions              int
+	sessionErr            error
+}
+
+func (o *countingObserver) RoundStarted(protocol.RoundInfo)                 { o.started++ }
+func (o *countingObserver) RoundFinished(protocol.RoundInfo, time.Duration) { o.finished++ }
+
+func (o *countingObserver) MessageReceived(_ protocol.RoundInfo, _ party.ID, _ bool, size int) {
+	o.received++
+	o.receivedBytes += size
+}
+
+func (o *countingObserver) MessageVerified(_ protocol.RoundInfo, _ party.ID, _ bool, _ time.Duration, _ error) {
+	o.verified++
+}
+
+func (o *countingObserver) MessageSent(_ protocol.RoundInfo, to party.ID, _ bool, size int) {
+	if to == "" {
+		// a broadcast is received by the 2 other parties
+		size *= 2
+	}
+	o.sentTo += size
+}
+
+func (o *countingObserver) SessionFinished(_ protocol.RoundInfo, _ time.Duration, err error) {
+	o.sessions++
+	o.sessionErr = err
+}
+
+func TestMultiHandlerObserver(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	for _, opts := range [][]protocol.HandlerOption{nil, {protocol.WithParallelVerification(nil)}} {
+		observers := make(map[party.ID]*countingObserver, len(ids))
+		handlers := make(map[party.ID]*protocol.MultiHandler, len(ids))
+		for _, id := range ids {
+			observers[id] = &countingObserver{}
+			h, err := protocol.NewMultiHandler(frost.Keygen(curve.Secp256k1{}, id, ids, 1), nil,
+				append(opts, protocol.WithObserver(observers[id]))...)
+			require.NoError(t, err)
+			handlers[id] = h
+		}
+		deliver(ids, handlers)
+
+		sent, received := 0, 0
+		for _, id := range ids {
+			_, err := handlers[id].Result()
+			require.NoError(t, err)
+			o := observers[id]
+			assert.Equal(t, 3, o.started, "keygen has 3 rounds")
+			assert.Equal(t, 3, o.finished)
+			assert.Equal(t, o.received, o.verified)
+			assert.Equal(t, 1, o.sessions)
+			assert.NoError(t, o.sessionErr)
+			sent += o.sentTo
+			received += o.receivedBytes
+		}
+		assert.NotZero(t, received)
+		assert.Equal(t, sent, received)
+	}
+}
+
+// recordingLogger records the messages it logs, with their level, and the value of the "party" key.
+type recordingLogger struct {
+	mtx     sync.Mutex
+	entries []string
+}
+
+func (l *recordingLogger) log(level, msg string, args []interface{}) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	entry := level + " " + msg
+	for i := 0; i+1 < len(args); i += 2 {
+		if args[i] == "party" {
+			entry += " " + args[i+1].(string)
+		}
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) { l.log("DEBUG", msg, args) }
+func (l *recordingLogger) Info(msg string, args ...interface{})  { l.log("INFO", msg, args) }
+func (l *recordingLogger) Warn(msg string, args ...interface{})  { l.log("WARN", msg, args) }
+func (l *recordingLogger) Error(msg string, args ...interface{}) { l.log("ERROR", msg, args) }
+
+func TestMultiHandlerLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	observer := &countingObserver{}
+	runKeygen(t, party.IDSlice{"a", "b"}, protocol.WithLogger(logger), protocol.WithObserver(observer))
+	assert.Contains(t, logger.entries, "DEBUG round started a")
+	assert.Contains(t, logger.entries, "INFO session finished b")
+	assert.NotZero(t, observer.started, "both the logger and the observer are notified")
+
+	logger = &recordingLogger{}
+	h, err := protocol.NewMultiHandler(frost.Keygen(curve.Secp256k1{}, "a", party.IDSlice{"a", "b"}, 1), nil, protocol.WithLogger(logger))
+	require.NoError(t, err)
+	h.Stop()
+	assert.Contains(t, logger.entries, "ERROR session aborted a")
+}
+
+func TestWithDomain(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.NewIDSlice([]party.ID{"a", "b"})
+	ssid := func(start protocol.StartFunc, sessionID []byte) []byte {
+		s, err := start(sessionID)
+		require.NoError(t, err)
+		return s.SSID()
+	}
+	keygen := frost.Keygen(group, "a", ids, 1)
+
+	productA := ssid(protocol.WithDomain([]byte("product A"), keygen), nil)
+	assert.Equal(t, productA, ssid(protocol.WithDomain([]byte("product A"), keygen), nil))
+	assert.NotEqual(t, productA, ssid(protocol.WithDomain([]byte("product B"), keygen), nil))
+	assert.NotEqual(t, productA, ssid(keygen, nil))
+	assert.NotEqual(t, productA, ssid(protocol.WithDomain([]byte("product A"), keygen), []byte{}))
+
+	// the parties of a session with the same domain still complete the protocol
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(ids))
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(protocol.WithDomain([]byte("product A"), frost.Keygen(group, id, ids, 1)), []byte("session"))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	deliver(ids, handlers)
+	for _, h := range handlers {
+		_, err := h.Result()
+		assert.NoError(t, err)
+	}
+}
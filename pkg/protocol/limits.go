@@ -0,0 +1,211 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrMessageTooLarge is returned by handlers receiving a message which exceeds their Limits.
+var ErrMessageTooLarge = errors.New("protocol: message exceeds the limits")
+
+// Limits bound the size of the messages a MultiHandler accepts.
+//
+// They are checked on the encoded content of a message, before it is unmarshalled and verified,
+// so that a peer can't make the handler allocate and process arbitrarily large values.
+// A message exceeding them aborts the session, blaming its sender.
+// MaxMessageSize is checked before the signature of the message, so that large messages aren't even hashed;
+// when the parties are authenticated by WithIdentity, messages exceeding it are dropped instead,
+// since they can't be attributed to their sender.
+// A limit of 0 disables the corresponding check.
+type Limits struct {
+	// MaxMessageSize is the maximum length of Message.Data.
+	MaxMessageSize int
+	// MaxByteString is the maximum length of a byte or text string in the content,
+	// which bounds the size of integers, ciphertexts and points.
+	MaxByteString int
+	// MaxElements is the maximum number of elements of an array or map in the content,
+	// which bounds the number of parties and the length of proofs.
+	MaxElements int
+	// MaxDepth is the maximum nesting of arrays, maps and tags in the content.
+	MaxDepth int
+}
+
+// DefaultLimits are the Limits of a MultiHandler created without WithLimits.
+//
+// They are far above the sizes of the messages of the protocols in this library,
+// for up to a few thousand parties, and batches of a few hundred signatures.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxMessageSize: 1 << 24,
+		MaxByteString:  1 << 16,
+		MaxElements:    1 << 14,
+		MaxDepth:       32,
+	}
+}
+
+// WithLimits replaces the DefaultLimits of the MultiHandler with l.
+func WithLimits(l Limits) HandlerOption {
+	return func(h *MultiHandler) {
+		h.limits = l
+	}
+}
+
+// check returns an error wrapping ErrMessageTooLarge if msg exceeds l.
+// Malformed contents are left for the unmarshalling to reject.
+func (l Limits) check(msg *Message) error {
+	if err := l.checkSize(msg); err != nil {
+		return err
+	}
+	c := &limitChecker{Limits: l, data: msg.Data}
+	for c.offset < len(c.data) {
+		if err := c.item(0); errors.Is(err, errMalformed) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSize returns an error wrapping ErrMessageTooLarge if msg exceeds the MaxMessageSize of l.
+func (l Limits) checkSize(msg *Message) error {
+	if l.MaxMessageSize > 0 && len(msg.Data) > l.MaxMessageSize {
+		return fmt.Errorf("%w: %d bytes of content, the maximum is %d", ErrMessageTooLarge, len(msg.Data), l.MaxMessageSize)
+	}
+	return nil
+}
+
+// errMalformed stops the check of a content which isn't valid CBOR.
+var errMalformed = errors.New("malformed")
+
+// limitChecker walks the CBOR encoding of a content without decoding it.
+type limitChecker struct {
+	Limits
+	data   []byte
+	offset int
+}
+
+// header reads the header of the next item, and returns its major type and argument.
+// indefinite is true if the item has an indefinite length.
+func (c *limitChecker) header() (major byte, argument uint64, indefinite bool, err error) {
+	if c.offset >= len(c.data) {
+		return 0, 0, false, errMalformed
+	}
+	initial := c.data[c.offset]
+	c.offset++
+	major, info := initial>>5, initial&0x1f
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), false, nil
+	case info == 31:
+		return major, 0, true, nil
+	case info > 27:
+		return 0, 0, false, errMalformed
+	default:
+		size = 1 << (info - 24)
+	}
+	if len(c.data)-c.offset < size {
+		return 0, 0, false, errMalformed
+	}
+	var buf [8]byte
+	copy(buf[8-size:], c.data[c.offset:c.offset+size])
+	c.offset += size
+	return major, binary.BigEndian.Uint64(buf[:]), false, nil
+}
+
+// item checks the next item, at the given nesting depth.
+func (c *limitChecker) item(depth int) error {
+	major, argument, indefinite, err := c.header()
+	if err != nil {
+		return err
+	}
+	switch major {
+	case 0, 1, 7:
+		// integers and simple values have no content, and the arguments of floats were read with their header
+		return nil
+	case 2, 3:
+		if indefinite {
+			return c.chunks(major)
+		}
+		return c.skipString(argument)
+	case 4, 5, 6:
+		if depth++; c.MaxDepth > 0 && depth > c.MaxDepth {
+			return fmt.Errorf("%w: more than %d nested levels", ErrMessageTooLarge, c.MaxDepth)
+		}
+		count := uint64(1)
+		if major != 6 {
+			if indefinite {
+				return c.indefiniteElements(major, depth)
+			}
+			if c.MaxElements > 0 && argument > uint64(c.MaxElements) {
+				return fmt.Errorf("%w: %d elements, the maximum is %d", ErrMessageTooLarge, argument, c.MaxElements)
+			}
+			count = argument
+			if major == 5 {
+				count *= 2
+			}
+		}
+		for i := uint64(0); i < count; i++ {
+			if err = c.item(depth); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipString checks the length of a string, and skips it.
+func (c *limitChecker) skipString(length uint64) error {
+	if c.MaxByteString > 0 && length > uint64(c.MaxByteString) {
+		return fmt.Errorf("%w: string of %d bytes, the maximum is %d", ErrMessageTooLarge, length, c.MaxByteString)
+	}
+	if length > uint64(len(c.data)-c.offset) {
+		return errMalformed
+	}
+	c.offset += int(length)
+	return nil
+}
+
+// chunks checks a string of indefinite length, whose total length is bounded.
+func (c *limitChecker) chunks(major byte) error {
+	total := uint64(0)
+	for {
+		if c.offset < len(c.data) && c.data[c.offset] == 0xff {
+			c.offset++
+			return nil
+		}
+		chunkMajor, length, indefinite, err := c.header()
+		if err != nil || chunkMajor != major || indefinite {
+			return errMalformed
+		}
+		total += length
+		if c.MaxByteString > 0 && total > uint64(c.MaxByteString) {
+			return fmt.Errorf("%w: string of more than %d bytes", ErrMessageTooLarge, c.MaxByteString)
+		}
+		if err = c.skipString(length); err != nil {
+			return err
+		}
+	}
+}
+
+// indefiniteElements checks an array or map of indefinite length, whose number of elements is bounded.
+func (c *limitChecker) indefiniteElements(major byte, depth int) error {
+	perElement := uint64(1)
+	if major == 5 {
+		perElement = 2
+	}
+	for count := uint64(0); ; count++ {
+		if c.offset < len(c.data) && c.data[c.offset] == 0xff {
+			c.offset++
+			return nil
+		}
+		if c.MaxElements > 0 && count/perElement >= uint64(c.MaxElements) {
+			return fmt.Errorf("%w: more than %d elements", ErrMessageTooLarge, c.MaxElements)
+		}
+		if err := c.item(depth); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// Logger is a structured logger, whose args are alternating keys and values.
+//
+// This is the method set of *slog.Logger, and other structured loggers, such as logr, are easily adapted to it.
+type Logger = round.Logger
+
+// WithLogger makes the MultiHandler log the progress of the session to l, and gives l to the rounds of the protocol.
+//
+// Every entry has the protocol, SSID, and ID of this party as context, under the keys "protocol", "ssid" and "party".
+// Round transitions are logged at the debug level, the messages which fail verification at the warn level,
+// and the end of the session at the info level, or at the error level if it aborted.
+func WithLogger(l Logger) HandlerOption {
+	return func(h *MultiHandler) {
+		h.logger = l
+		addObserver(h, loggingObserver{logger: l})
+	}
+}
+
+// addObserver makes h notify o, in addition to the observers it already has.
+func addObserver(h *MultiHandler, o Observer) {
+	switch existing := h.observer.(type) {
+	case NopObserver:
+		h.observer = o
+	case multiObserver:
+		h.observer = append(existing, o)
+	default:
+		h.observer = multiObserver{existing, o}
+	}
+}
+
+// setLogger gives the logger of h to the current round, if it has one.
+func (h *MultiHandler) setLogger() {
+	if h.logger == nil {
+		return
+	}
+	if r, ok := h.currentRound.(interface{ SetLogger(round.Logger) }); ok {
+		r.SetLogger(h.logger)
+	}
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) RoundStarted(info RoundInfo) {
+	for _, o := range m {
+		o.RoundStarted(info)
+	}
+}
+
+func (m multiObserver) RoundFinished(info RoundInfo, elapsed time.Duration) {
+	for _, o := range m {
+		o.RoundFinished(info, elapsed)
+	}
+}
+
+func (m multiObserver) MessageReceived(info RoundInfo, from party.ID, broadcast bool, size int) {
+	for _, o := range m {
+		o.MessageReceived(info, from, broadcast, size)
+	}
+}
+
+func (m multiObserver) MessageVerified(info RoundInfo, from party.ID, broadcast bool, elapsed time.Duration, err error) {
+	for _, o := range m {
+		o.MessageVerified(info, from, broadcast, elapsed, err)
+	}
+}
+
+func (m multiObserver) MessageSent(info RoundInfo, to party.ID, broadcast bool, size int) {
+	for _, o := range m {
+		o.MessageSent(info, to, broadcast, size)
+	}
+}
+
+func (m multiObserver) SessionFinished(info RoundInfo, elapsed time.Duration, err error) {
+	for _, o := range m {
+		o.SessionFinished(info, elapsed, err)
+	}
+}
+
+// loggingObserver is the Observer logging the events of a session.
+type loggingObserver struct {
+	NopObserver
+	logger Logger
+}
+
+func (o loggingObserver) context(info RoundInfo, args ...interface{}) []interface{} {
+	return append([]interface{}{"protocol", info.Protocol, "ssid", hex.EncodeToString(info.SSID), "party", string(info.SelfID), "round", info.Round}, args...)
+}
+
+func (o loggingObserver) RoundStarted(info RoundInfo) {
+	o.logger.Debug("round started", o.context(info)...)
+}
+
+func (o loggingObserver) RoundFinished(info RoundInfo, elapsed time.Duration) {
+	o.logger.Debug("round finished", o.context(info, "duration", elapsed)...)
+}
+
+func (o loggingObserver) MessageVerified(info RoundInfo, from party.ID, broadcast bool, _ time.Duration, err error) {
+	if err != nil {
+		o.logger.Warn("invalid message", o.context(info, "from", string(from), "broadcast", broadcast, "error", err)...)
+	}
+}
+
+func (o loggingObserver) SessionFinished(info RoundInfo, elapsed time.Duration, err error) {
+	if err == nil {
+		o.logger.Info("session finished", o.context(info, "duration", elapsed)...)
+		return
+	}
+	args := []interface{}{"duration", elapsed, "error", err}
+	var protocolErr *Error
+	if errors.As(err, &protocolErr) {
+		args = append(args, "culprits", protocolErr.Culprits)
+	}
+	o.logger.Error("session aborted", o.context(info, args...)...)
+}
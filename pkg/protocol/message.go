@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/fxamacker/cbor/v2"
@@ -9,7 +10,17 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
 
+// WireVersion is the version of the encoding of messages and of the round contents they carry.
+// It is increased by every release changing them incompatibly.
+const WireVersion = 1
+
+// ErrIncompatibleVersion is returned by handlers receiving a message from a party running
+// a release with a different WireVersion.
+var ErrIncompatibleVersion = errors.New("protocol: incompatible wire version")
+
 type Message struct {
+	// Version is the WireVersion of the sender.
+	Version uint8
 	// SSID is a byte string which uniquely identifies the session this message belongs to.
 	SSID []byte
 	// From is the party.ID of the sender
@@ -26,7 +37,11 @@ type Message struct {
 	Broadcast bool
 	// BroadcastVerification is the hash of all messages broadcast by the parties,
 	// and is included in all messages in the round following a broadcast round.
+	// It is left empty in sessions between two parties, where the broadcast cannot be equivocated.
 	BroadcastVerification []byte
+	// Signature is the signature of the sender's identity key over the other fields, see WithIdentity.
+	// It is empty if the sender doesn't authenticate its messages.
+	Signature []byte
 }
 
 // String implements fmt.Stringer.
@@ -42,14 +57,24 @@ func (m Message) IsFor(id party.ID) bool {
 	return m.To == "" || m.To == id
 }
 
+// checkVersion returns ErrIncompatibleVersion if msg was produced with a different WireVersion.
+func checkVersion(msg *Message) error {
+	if msg.Version != WireVersion {
+		return fmt.Errorf("%w: %s uses version %d, and we use version %d", ErrIncompatibleVersion, msg.From, msg.Version, WireVersion)
+	}
+	return nil
+}
+
 // Hash returns a 64 byte hash of the message content, including the headers.
-// Can be used to produce a signature for the message.
+// Can be used to produce a signature for the message, and is the one signed by WithIdentity.
+// The Signature itself is not included.
 func (m *Message) Hash() []byte {
 	var broadcast byte
 	if m.Broadcast {
 		broadcast = 1
 	}
 	h := hash.New(
+		hash.BytesWithDomain{TheDomain: "Version", Bytes: []byte{m.Version}},
 		hash.BytesWithDomain{TheDomain: "SSID", Bytes: m.SSID},
 		m.From,
 		m.To,
@@ -67,6 +92,7 @@ func (m *Message) Hash() []byte {
 // This is a workaround to use cbor's default marshalling for Message, all while providing
 // a MarshalBinary method
 type marshallableMessage struct {
+	Version               uint8
 	SSID                  []byte
 	From                  party.ID
 	To                    party.ID
@@ -75,10 +101,12 @@ type marshallableMessage struct {
 	Data                  []byte
 	Broadcast             bool
 	BroadcastVerification []byte
+	Signature             []byte `cbor:",omitempty"`
 }
 
 func (m *Message) toMarshallable() *marshallableMessage {
 	return &marshallableMessage{
+		Version:               m.Version,
 		SSID:                  m.SSID,
 		From:                  m.From,
 		To:                    m.To,
@@ -87,6 +115,7 @@ func (m *Message) toMarshallable() *marshallableMessage {
 		Data:                  m.Data,
 		Broadcast:             m.Broadcast,
 		BroadcastVerification: m.BroadcastVerification,
+		Signature:             m.Signature,
 	}
 }
 
@@ -95,10 +124,15 @@ func (m *Message) MarshalBinary() ([]byte, error) {
 }
 
 func (m *Message) UnmarshalBinary(data []byte) error {
+	data, err := decompress(data)
+	if err != nil {
+		return err
+	}
 	deserialized := m.toMarshallable()
-	if err := cbor.Unmarshal(data, deserialized); err != nil {
+	if err = cbor.Unmarshal(data, deserialized); err != nil {
 		return nil
 	}
+	m.Version = deserialized.Version
 	m.SSID = deserialized.SSID
 	m.From = deserialized.From
 	m.To = deserialized.To
@@ -107,5 +141,6 @@ func (m *Message) UnmarshalBinary(data []byte) error {
 	m.Data = deserialized.Data
 	m.Broadcast = deserialized.Broadcast
 	m.BroadcastVerification = deserialized.BroadcastVerification
+	m.Signature = deserialized.Signature
 	return nil
 }
@@ -0,0 +1,45 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// jsonMessage is the JSON encoding of a Message, in which byte strings are encoded in standard base64.
+type jsonMessage struct {
+	Version               uint8        `json:"version"`
+	SSID                  []byte       `json:"ssid"`
+	From                  party.ID     `json:"from"`
+	To                    party.ID     `json:"to,omitempty"`
+	Protocol              string       `json:"protocol"`
+	RoundNumber           round.Number `json:"round"`
+	Data                  []byte       `json:"data"`
+	Broadcast             bool         `json:"broadcast,omitempty"`
+	BroadcastVerification []byte       `json:"broadcast_verification,omitempty"`
+	Signature             []byte       `json:"signature,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, for transports which can only carry JSON, such as webhooks.
+//
+// The headers are separate fields, such as "ssid", "from", "to" and "round", and byte strings such as the round's content
+// in "data" are encoded in base64. Party IDs are JSON strings, so IDs which aren't valid UTF-8, such as binary IDs, can't be encoded.
+func (m *Message) MarshalJSON() ([]byte, error) {
+	if !utf8.ValidString(string(m.From)) || !utf8.ValidString(string(m.To)) {
+		return nil, errors.New("protocol: party IDs must be valid UTF-8 to be encoded in JSON")
+	}
+	return json.Marshal(jsonMessage(*m.toMarshallable()))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the format of MarshalJSON.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var decoded jsonMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*m = Message(decoded)
+	return nil
+}
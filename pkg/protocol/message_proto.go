@@ -0,0 +1,154 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// Field numbers of message.proto.
+const (
+	protoFieldSSID = iota + 1
+	protoFieldFrom
+	protoFieldTo
+	protoFieldProtocol
+	protoFieldRoundNumber
+	protoFieldData
+	protoFieldBroadcast
+	protoFieldBroadcastVerification
+	protoFieldVersion
+	protoFieldSignature
+)
+
+// Protobuf wire types.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+var errProtoTruncated = errors.New("protocol: protobuf: truncated message")
+
+// MarshalProto returns the protobuf encoding of m, following the schema in message.proto,
+// for consumers which only need to read the headers without CBOR.
+//
+// The round's content in m.Data is left CBOR encoded, since round contents have no protobuf schema.
+func (m *Message) MarshalProto() ([]byte, error) {
+	var out []byte
+	appendBytes := func(field int, b []byte) {
+		if len(b) == 0 {
+			return
+		}
+		out = binary.AppendUvarint(out, uint64(field<<3|protoBytes))
+		out = binary.AppendUvarint(out, uint64(len(b)))
+		out = append(out, b...)
+	}
+	appendVarint := func(field int, v uint64) {
+		if v == 0 {
+			return
+		}
+		out = binary.AppendUvarint(out, uint64(field<<3|protoVarint))
+		out = binary.AppendUvarint(out, v)
+	}
+
+	appendBytes(protoFieldSSID, m.SSID)
+	appendBytes(protoFieldFrom, []byte(m.From))
+	appendBytes(protoFieldTo, []byte(m.To))
+	appendBytes(protoFieldProtocol, []byte(m.Protocol))
+	appendVarint(protoFieldRoundNumber, uint64(m.RoundNumber))
+	appendBytes(protoFieldData, m.Data)
+	if m.Broadcast {
+		appendVarint(protoFieldBroadcast, 1)
+	}
+	appendBytes(protoFieldBroadcastVerification, m.BroadcastVerification)
+	appendVarint(protoFieldVersion, uint64(m.Version))
+	appendBytes(protoFieldSignature, m.Signature)
+	return out, nil
+}
+
+// UnmarshalProto decodes a message produced by MarshalProto, or by any protobuf implementation of message.proto.
+// Unknown fields are skipped.
+func (m *Message) UnmarshalProto(data []byte) error {
+	*m = Message{}
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errProtoTruncated
+		}
+		data = data[n:]
+		field, wireType := tag>>3, tag&7
+
+		var value uint64
+		var bytes []byte
+		switch wireType {
+		case protoVarint:
+			if value, n = binary.Uvarint(data); n <= 0 {
+				return errProtoTruncated
+			}
+			data = data[n:]
+		case protoBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return errProtoTruncated
+			}
+			bytes = data[n : n+int(length)]
+			data = data[n+int(length):]
+		case protoFixed64, protoFixed32:
+			size := 8
+			if wireType == protoFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return errProtoTruncated
+			}
+			data = data[size:]
+		default:
+			return fmt.Errorf("protocol: protobuf: unsupported wire type %d", wireType)
+		}
+
+		expected := uint64(protoBytes)
+		if field == protoFieldRoundNumber || field == protoFieldBroadcast || field == protoFieldVersion {
+			expected = protoVarint
+		}
+		if field < protoFieldSSID || field > protoFieldSignature {
+			continue
+		}
+		if wireType != expected {
+			return fmt.Errorf("protocol: protobuf: field %d has wire type %d", field, wireType)
+		}
+		switch field {
+		case protoFieldSSID:
+			m.SSID = append([]byte(nil), bytes...)
+		case protoFieldFrom:
+			m.From = party.ID(bytes)
+		case protoFieldTo:
+			m.To = party.ID(bytes)
+		case protoFieldProtocol:
+			m.Protocol = string(bytes)
+		case protoFieldRoundNumber:
+			if value > math.MaxUint16 {
+				return fmt.Errorf("protocol: protobuf: round number %d out of range", value)
+			}
+			m.RoundNumber = round.Number(value)
+		case protoFieldData:
+			m.Data = append([]byte(nil), bytes...)
+		case protoFieldBroadcast:
+			m.Broadcast = value != 0
+		case protoFieldBroadcastVerification:
+			m.BroadcastVerification = append([]byte(nil), bytes...)
+		case protoFieldVersion:
+			if value > math.MaxUint8 {
+				return fmt.Errorf("protocol: protobuf: version %d out of range", value)
+			}
+			m.Version = uint8(value)
+		case protoFieldSignature:
+			m.Signature = append([]byte(nil), bytes...)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageProto(t *testing.T) {
+	msg := &Message{
+		Version:               WireVersion,
+		SSID:                  []byte{1, 2, 3},
+		From:                  "a",
+		To:                    "b",
+		Protocol:              "test",
+		RoundNumber:           300,
+		Data:                  []byte{4, 5},
+		Broadcast:             true,
+		BroadcastVerification: []byte{6},
+	}
+	data, err := msg.MarshalProto()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x0a, 3, 1, 2, 3,
+		0x12, 1, 'a',
+		0x1a, 1, 'b',
+		0x22, 4, 't', 'e', 's', 't',
+		0x28, 0xac, 0x02,
+		0x32, 2, 4, 5,
+		0x38, 1,
+		0x42, 1, 6,
+		0x48, WireVersion,
+	}, data)
+
+	decoded := &Message{}
+	require.NoError(t, decoded.UnmarshalProto(data))
+	assert.Equal(t, msg, decoded)
+
+	// unknown fields of every wire type are skipped
+	withUnknown := append([]byte{0x58, 7, 0x61, 0, 0, 0, 0, 0, 0, 0, 0, 0x6a, 1, 0, 0x75, 0, 0, 0, 0}, data...)
+	require.NoError(t, decoded.UnmarshalProto(withUnknown))
+	assert.Equal(t, msg, decoded)
+
+	signed := *msg
+	signed.Signature = []byte{7, 8}
+	signedData, err := signed.MarshalProto()
+	require.NoError(t, err)
+	require.NoError(t, decoded.UnmarshalProto(signedData))
+	assert.Equal(t, &signed, decoded)
+
+	empty, err := (&Message{}).MarshalProto()
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	assert.Error(t, decoded.UnmarshalProto(data[:len(data)-1]))
+	assert.Error(t, decoded.UnmarshalProto([]byte{0x28, 0x80, 0x80, 0x04}), "round number out of range")
+	assert.Error(t, decoded.UnmarshalProto([]byte{0x0d, 0, 0, 0, 0}), "wrong wire type")
+}
+
+func TestMessageJSON(t *testing.T) {
+	msg := &Message{
+		Version:               WireVersion,
+		SSID:                  []byte{1, 2, 3},
+		From:                  "a",
+		To:                    "b",
+		Protocol:              "test",
+		RoundNumber:           300,
+		Data:                  []byte{4, 5},
+		Broadcast:             true,
+		BroadcastVerification: []byte{6},
+	}
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version":1,"ssid":"AQID","from":"a","to":"b","protocol":"test","round":300,"data":"BAU=","broadcast":true,"broadcast_verification":"Bg=="}`, string(data))
+
+	decoded := &Message{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, msg, decoded)
+
+	// messages for all parties have no recipient
+	msg.To, msg.Broadcast, msg.BroadcastVerification = "", false, nil
+	data, err = json.Marshal(msg)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"to"`)
+	decoded = &Message{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, msg, decoded)
+
+	msg.From = "\xff"
+	_, err = json.Marshal(msg)
+	assert.Error(t, err)
+}
+
+func TestMessageCompression(t *testing.T) {
+	msg := &Message{
+		SSID:        []byte{1, 2, 3},
+		From:        "a",
+		To:          "b",
+		Protocol:    "test",
+		RoundNumber: 3,
+		Data:        bytes.Repeat([]byte("ciphertext"), 1000),
+	}
+	plain, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	data, err := msg.MarshalBinaryCompressed(NoCompression)
+	require.NoError(t, err)
+	assert.Equal(t, plain, data)
+
+	compressed, err := msg.MarshalBinaryCompressed(FlateCompression)
+	require.NoError(t, err)
+	assert.Less(t, len(compressed), len(plain)/10)
+
+	for _, data := range [][]byte{plain, compressed} {
+		decoded := &Message{}
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, msg, decoded)
+	}
+
+	short := &Message{From: "a"}
+	data, err = short.MarshalBinaryCompressed(FlateCompression)
+	require.NoError(t, err)
+	plain, err = short.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, plain, data, "messages which don't shrink are left uncompressed")
+
+	_, err = msg.MarshalBinaryCompressed(Compression(7))
+	assert.Error(t, err)
+	assert.Error(t, (&Message{}).UnmarshalBinary([]byte{compressedPrefix, 7}))
+	assert.Error(t, (&Message{}).UnmarshalBinary(compressed[:len(compressed)/2]))
+}
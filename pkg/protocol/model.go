@@ -0,0 +1,136 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+)
+
+// Model describes the round state machine followed by a party of a protocol,
+// as data which can be consumed by model checkers or documentation tools.
+type Model struct {
+	// Name identifies the protocol,
+	// and the role of the party for protocols where both parties behave differently.
+	Name string
+	// Rounds are the states of the machine, starting with the first round.
+	Rounds []RoundModel
+}
+
+// RoundModel is a state of a Model.
+//
+// A party enters it when the previous round is finalized, and leaves it by finalizing the round,
+// which happens once all the messages it expects have been received from the other parties.
+type RoundModel struct {
+	// Name identifies the round within the protocol, and is the name of the type implementing it.
+	Name string
+	// Number is the round number of the messages the round expects.
+	Number round.Number
+	// Broadcast is the kind of broadcast message the round expects from every other party.
+	Broadcast Broadcast
+	// Message is true if the round expects a point-to-point message from every other party.
+	Message bool
+	// Next contains the names of the rounds which can follow this one.
+	Next []string
+	// Output is true if finalizing the round can produce the result of the protocol.
+	Output bool
+	// Abort is true if finalizing the round can abort the protocol, possibly identifying culprits.
+	Abort bool
+}
+
+// Broadcast is the kind of broadcast message expected by a round.
+type Broadcast uint8
+
+const (
+	// NoBroadcast is used for rounds which do not expect any broadcast message.
+	NoBroadcast Broadcast = iota
+	// NormalBroadcast is used for rounds expecting a broadcast, which may not reach all parties.
+	NormalBroadcast
+	// ReliableBroadcast is used for rounds expecting a broadcast,
+	// which all parties check to have received identically before finalizing.
+	ReliableBroadcast
+)
+
+var broadcastNames = [...]string{"none", "normal", "reliable"}
+
+// String implements fmt.Stringer.
+func (b Broadcast) String() string {
+	if int(b) < len(broadcastNames) {
+		return broadcastNames[b]
+	}
+	return fmt.Sprintf("Broadcast(%d)", uint8(b))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (b Broadcast) MarshalText() ([]byte, error) {
+	if int(b) >= len(broadcastNames) {
+		return nil, fmt.Errorf("protocol: invalid broadcast kind %d", uint8(b))
+	}
+	return []byte(broadcastNames[b]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *Broadcast) UnmarshalText(text []byte) error {
+	for i, name := range broadcastNames {
+		if name == string(text) {
+			*b = Broadcast(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("protocol: invalid broadcast kind %q", text)
+}
+
+// Round returns the round with the given name, or nil if it is not part of the model.
+func (m *Model) Round(name string) *RoundModel {
+	for i := range m.Rounds {
+		if m.Rounds[i].Name == name {
+			return &m.Rounds[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks that the model is a well-formed state machine:
+// - round names are unique,
+// - transitions lead to rounds of the model,
+// - every round can be left, and can be reached from the first round.
+func (m *Model) Validate() error {
+	if len(m.Rounds) == 0 {
+		return errors.New("protocol: model has no rounds")
+	}
+	for i, r := range m.Rounds {
+		if r.Name == "" {
+			return fmt.Errorf("protocol: model %s: round %d has no name", m.Name, i)
+		}
+		if m.Round(r.Name) != &m.Rounds[i] {
+			return fmt.Errorf("protocol: model %s: duplicate round %s", m.Name, r.Name)
+		}
+		if len(r.Next) == 0 && !r.Output && !r.Abort {
+			return fmt.Errorf("protocol: model %s: round %s has no transition", m.Name, r.Name)
+		}
+		for _, next := range r.Next {
+			if m.Round(next) == nil {
+				return fmt.Errorf("protocol: model %s: round %s leads to unknown round %s", m.Name, r.Name, next)
+			}
+		}
+	}
+
+	reached := map[string]bool{m.Rounds[0].Name: true}
+	queue := []string{m.Rounds[0].Name}
+	for len(queue) > 0 {
+		r := m.Round(queue[0])
+		queue = queue[1:]
+		for _, next := range r.Next {
+			if !reached[next] {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	for _, r := range m.Rounds {
+		if !reached[r.Name] {
+			return fmt.Errorf("protocol: model %s: round %s is unreachable", m.Name, r.Name)
+		}
+	}
+	return nil
+}
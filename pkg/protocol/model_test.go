@@ -0,0 +1,57 @@
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelValidate(t *testing.T) {
+	valid := func() *Model {
+		return &Model{
+			Name: "test",
+			Rounds: []RoundModel{
+				{Name: "round1", Number: 1, Next: []string{"round2"}},
+				{Name: "round2", Number: 2, Broadcast: ReliableBroadcast, Next: []string{"abort"}, Output: true},
+				{Name: "abort", Number: 3, Broadcast: NormalBroadcast, Abort: true},
+			},
+		}
+	}
+	require.NoError(t, valid().Validate())
+
+	m := valid()
+	m.Rounds[2].Name = "round2"
+	m.Rounds[1].Next = nil
+	assert.Error(t, m.Validate(), "duplicate round")
+
+	m = valid()
+	m.Rounds[1].Next = []string{"unknown"}
+	assert.Error(t, m.Validate(), "unknown round")
+
+	m = valid()
+	m.Rounds[2].Abort = false
+	assert.Error(t, m.Validate(), "round without transition")
+
+	m = valid()
+	m.Rounds[1].Next = nil
+	assert.Error(t, m.Validate(), "unreachable round")
+}
+
+func TestModelJSON(t *testing.T) {
+	m := &Model{
+		Name: "test",
+		Rounds: []RoundModel{
+			{Name: "round1", Number: 1, Next: []string{"round2"}},
+			{Name: "round2", Number: 2, Broadcast: ReliableBroadcast, Message: true, Output: true},
+		},
+	}
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Broadcast":"reliable"`)
+
+	decoded := &Model{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, m, decoded)
+}
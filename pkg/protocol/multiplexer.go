@@ -0,0 +1,495 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// ErrSessionExpired is the error returned by MultiHandler.Result when a Multiplexer expired the session.
+var ErrSessionExpired = errors.New("protocol: session expired")
+
+// ErrSessionDrained is the error returned by MultiHandler.Result when the session was aborted by Multiplexer.Drain.
+var ErrSessionDrained = errors.New("protocol: session aborted while draining")
+
+// ErrDraining is returned when starting a session on a Multiplexer which is draining.
+var ErrDraining = errors.New("protocol: multiplexer is draining")
+
+// ErrSessionLimit is returned when starting a session for a tenant which already runs the maximum number of sessions.
+var ErrSessionLimit = errors.New("protocol: too many running sessions")
+
+// ExpiredSession describes a session which was expired by a Multiplexer before it could complete.
+type ExpiredSession struct {
+	// Tenant is the namespace the session was started in.
+	Tenant string
+	// SSID identifies the expired session.
+	SSID []byte
+	// Protocol is the ID of the protocol the session was running.
+	Protocol string
+	// Round is the number of the round the session was waiting on.
+	Round round.Number
+	// Transcript contains all the messages exchanged before the expiry, ordered by round and sender.
+	Transcript []*Message
+	// Missing contains the parties which failed to deliver their message for the current round.
+	Missing []party.ID
+}
+
+// ExpiryCallback is called by a Multiplexer when a session expires.
+type ExpiryCallback func(session *ExpiredSession)
+
+// CompletedSession describes a session which produced a result while registered with a Multiplexer.
+type CompletedSession struct {
+	// Tenant is the namespace the session was started in.
+	Tenant string
+	// SSID identifies the completed session.
+	SSID []byte
+	// Protocol is the ID of the protocol the session was running.
+	Protocol string
+	// Parties contains all the participants of the session.
+	Parties party.IDSlice
+}
+
+// CompletionCallback is called by a Multiplexer when a session completes successfully.
+type CompletionCallback func(session *CompletedSession)
+
+// FailedSession describes a session which aborted or expired while its Multiplexer was draining.
+type FailedSession struct {
+	// Tenant is the namespace the session was started in.
+	Tenant string
+	// SSID identifies the failed session.
+	SSID []byte
+	// Err is the error returned by the session's MultiHandler.Result.
+	Err error
+}
+
+// DrainSummary reports what happened to the sessions a Multiplexer was running when Drain was called.
+type DrainSummary struct {
+	// Completed contains the sessions which produced a result before the deadline.
+	Completed []*CompletedSession
+	// Failed contains the sessions which aborted or expired before the deadline.
+	Failed []*FailedSession
+	// Interrupted contains the sessions which were still running at the deadline, and were aborted by Drain.
+	Interrupted []*ExpiredSession
+}
+
+// SessionNotifier is implemented by Multiplexer and Namespace, which report the sessions they expire and complete.
+type SessionNotifier interface {
+	OnExpire(callback ExpiryCallback)
+	OnComplete(callback CompletionCallback)
+}
+
+// Multiplexer routes incoming messages to the MultiHandler of the session they belong to,
+// and expires sessions which have not made any progress in a given amount of time.
+// Sessions are unregistered as soon as their handler finishes, however it does.
+//
+// When a session expires, its handler is aborted, with the parties it was waiting on as culprits,
+// and every registered ExpiryCallback is called.
+//
+// Sessions started with Start and Accept belong to the default tenant "".
+// A Namespace gives access to the sessions of another tenant.
+type Multiplexer struct {
+	timeout  time.Duration
+	clock    Clock
+	limit    int
+	options  []HandlerOption
+	draining bool
+	// idle is closed once the last session is removed while draining
+	idle       chan struct{}
+	sessions   map[sessionKey]*multiplexedSession
+	namespaces map[string]*Namespace
+	callbacks  []ExpiryCallback
+	completed  []CompletionCallback
+	mtx        sync.Mutex
+}
+
+// sessionKey identifies a session within a Multiplexer, so that tenants may reuse each other's SSIDs.
+type sessionKey struct {
+	tenant string
+	ssid   string
+}
+
+type multiplexedSession struct {
+	handler *MultiHandler
+	// timer expires the session, if the Multiplexer has a timeout
+	timer Timer
+	// removed is closed once the session is unregistered
+	removed chan struct{}
+}
+
+// Namespace is the view of a Multiplexer restricted to the sessions of a single tenant.
+//
+// Messages accepted by a Namespace are only routed to sessions it started,
+// and its callbacks are only called for these sessions.
+type Namespace struct {
+	m         *Multiplexer
+	tenant    string
+	running   int
+	callbacks []ExpiryCallback
+	completed []CompletionCallback
+}
+
+// MultiplexerOption modifies the behaviour of a Multiplexer.
+type MultiplexerOption func(*Multiplexer)
+
+// WithClock makes the Multiplexer measure timeouts with clock, instead of SystemClock.
+func WithClock(clock Clock) MultiplexerOption {
+	return func(m *Multiplexer) {
+		m.clock = clock
+	}
+}
+
+// WithSessionLimit limits the number of sessions each tenant may run at the same time.
+// Starting more sessions fails with ErrSessionLimit until one of them completes, aborts or expires.
+func WithSessionLimit(limit int) MultiplexerOption {
+	return func(m *Multiplexer) {
+		m.limit = limit
+	}
+}
+
+// WithSessionOptions applies opts to the MultiHandler of every session the Multiplexer starts,
+// before the options given to Start. This sets the same Limits or identities for all sessions.
+func WithSessionOptions(opts ...HandlerOption) MultiplexerOption {
+	return func(m *Multiplexer) {
+		m.options = append(m.options, opts...)
+	}
+}
+
+// NewMultiplexer returns a Multiplexer which expires sessions after timeout has elapsed without them
+// accepting a message. If timeout is zero or negative, sessions never expire, and only end when they
+// finish or are stopped.
+func NewMultiplexer(timeout time.Duration, opts ...MultiplexerOption) *Multiplexer {
+	m := &Multiplexer{
+		timeout:    timeout,
+		clock:      SystemClock,
+		sessions:   map[sessionKey]*multiplexedSession{},
+		namespaces: map[string]*Namespace{},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// OnExpire registers a callback, which will be called in its own goroutine for every session expired from now on,
+// whatever its tenant.
+func (m *Multiplexer) OnExpire(callback ExpiryCallback) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.callbacks = append(m.callbacks, callback)
+}
+
+// OnComplete registers a callback, which will be called in its own goroutine for every session
+// of any tenant which produces a result from now on. Sessions which abort are not reported.
+func (m *Multiplexer) OnComplete(callback CompletionCallback) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.completed = append(m.completed, callback)
+}
+
+// Namespace returns the view of the Multiplexer restricted to the sessions of tenant.
+// Successive calls with the same tenant return the same Namespace.
+func (m *Multiplexer) Namespace(tenant string) *Namespace {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.namespace(tenant)
+}
+
+func (m *Multiplexer) namespace(tenant string) *Namespace {
+	n, ok := m.namespaces[tenant]
+	if !ok {
+		n = &Namespace{m: m, tenant: tenant}
+		m.namespaces[tenant] = n
+	}
+	return n
+}
+
+// Start creates a new MultiHandler for the given protocol, and registers it with the Multiplexer
+// under the default tenant.
+func (m *Multiplexer) Start(create StartFunc, sessionID []byte, opts ...HandlerOption) (*MultiHandler, error) {
+	return m.start("", create, sessionID, opts)
+}
+
+func (m *Multiplexer) start(tenant string, create StartFunc, sessionID []byte, opts []HandlerOption) (*MultiHandler, error) {
+	opts = append(append([]HandlerOption(nil), m.options...), opts...)
+	h, err := NewMultiHandler(create, sessionID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.draining {
+		h.abort(ErrDraining)
+		return nil, ErrDraining
+	}
+	key := sessionKey{tenant: tenant, ssid: string(h.currentRound.SSID())}
+	if _, ok := m.sessions[key]; ok {
+		h.abort(errors.New("protocol: duplicate session"))
+		return nil, fmt.Errorf("protocol: a session with SSID %x is already running", h.currentRound.SSID())
+	}
+	if h.done() {
+		return h, nil
+	}
+	n := m.namespace(tenant)
+	if m.limit > 0 && n.running >= m.limit {
+		h.abort(ErrSessionLimit)
+		return nil, fmt.Errorf("%w: tenant %q already runs %d sessions", ErrSessionLimit, tenant, n.running)
+	}
+	n.running++
+	s := &multiplexedSession{
+		handler: h,
+		removed: make(chan struct{}),
+	}
+	if m.timeout > 0 {
+		s.timer = m.clock.AfterFunc(m.timeout, func() { m.expire(key) })
+	}
+	m.sessions[key] = s
+	// sessions may also finish without a message, when stopped, cancelled or timed out
+	go func() {
+		select {
+		case <-h.finished:
+			m.finish(key, s)
+		case <-s.removed:
+		}
+	}()
+	return h, nil
+}
+
+// Accept forwards msg to the session of the default tenant it belongs to.
+// The session's expiry timer is reset if the handler keeps the message, so duplicates, replays
+// and messages which fail authentication don't keep a session alive.
+// Messages for unknown sessions are ignored.
+func (m *Multiplexer) Accept(msg *Message) {
+	m.accept("", msg)
+}
+
+func (m *Multiplexer) accept(tenant string, msg *Message) {
+	if msg == nil {
+		return
+	}
+	key := sessionKey{tenant: tenant, ssid: string(msg.SSID)}
+	m.mtx.Lock()
+	s, ok := m.sessions[key]
+	m.mtx.Unlock()
+	if !ok {
+		return
+	}
+
+	// accept checks that the handler can accept msg under its lock
+	stored := s.handler.accept(msg)
+	if s.handler.done() {
+		m.finish(key, s)
+		return
+	}
+	if !stored {
+		return
+	}
+	m.mtx.Lock()
+	if m.sessions[key] == s && s.timer != nil {
+		s.timer.Reset(m.timeout)
+	}
+	m.mtx.Unlock()
+}
+
+// finish unregisters s once its handler has finished, and calls the completion callbacks if it produced a result.
+func (m *Multiplexer) finish(key sessionKey, s *multiplexedSession) {
+	if !m.remove(key, s) {
+		return
+	}
+	completed, ok := s.handler.completed()
+	if !ok {
+		return
+	}
+	completed.Tenant = key.tenant
+	m.mtx.Lock()
+	callbacks := append([]CompletionCallback(nil), m.completed...)
+	callbacks = append(callbacks, m.namespace(key.tenant).completed...)
+	m.mtx.Unlock()
+	for _, callback := range callbacks {
+		go callback(completed)
+	}
+}
+
+// Sessions returns the SSIDs of the sessions of the default tenant which are still running, in increasing order.
+// Sessions are removed as soon as they complete, abort or expire.
+func (m *Multiplexer) Sessions() [][]byte {
+	return m.Namespace("").Sessions()
+}
+
+// Stop discards all running sessions without calling the expiry callbacks.
+func (m *Multiplexer) Stop() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	for key, s := range m.sessions {
+		m.unregister(key, s)
+	}
+}
+
+// remove unregisters s, and returns false if it was already removed.
+func (m *Multiplexer) remove(key sessionKey, s *multiplexedSession) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.sessions[key] != s {
+		return false
+	}
+	m.unregister(key, s)
+	return true
+}
+
+// unregister removes the session s registered under key. It must be called with mtx held.
+func (m *Multiplexer) unregister(key sessionKey, s *multiplexedSession) {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	delete(m.sessions, key)
+	m.namespace(key.tenant).running--
+	close(s.removed)
+	m.notifyIdle()
+}
+
+// notifyIdle wakes up Drain once no session is left. It must be called with mtx held.
+func (m *Multiplexer) notifyIdle() {
+	if m.idle != nil && len(m.sessions) == 0 {
+		close(m.idle)
+		m.idle = nil
+	}
+}
+
+func (m *Multiplexer) expire(key sessionKey) {
+	m.mtx.Lock()
+	s, ok := m.sessions[key]
+	if ok {
+		m.unregister(key, s)
+	}
+	callbacks := append([]ExpiryCallback(nil), m.callbacks...)
+	callbacks = append(callbacks, m.namespace(key.tenant).callbacks...)
+	m.mtx.Unlock()
+	if !ok {
+		return
+	}
+
+	expired, ok := s.handler.expire()
+	if !ok {
+		return
+	}
+	expired.Tenant = key.tenant
+	for _, callback := range callbacks {
+		go callback(expired)
+	}
+}
+
+// Drain stops the Multiplexer from starting new sessions, and waits for the running ones to finish,
+// for at most timeout as measured by the Multiplexer's Clock. Messages are still accepted in the meantime.
+//
+// Sessions still running after timeout are aborted with ErrSessionDrained, blaming this party,
+// which also sends an abort message to the other parties through the handler's Listen channel.
+// Their transcript is part of the returned summary, so that it can be persisted for later analysis.
+// The secrets sampled by their rounds are discarded though, so these sessions cannot be resumed,
+// and must be started again with a new SSID.
+func (m *Multiplexer) Drain(timeout time.Duration) *DrainSummary {
+	m.mtx.Lock()
+	m.draining = true
+	running := make(map[sessionKey]*multiplexedSession, len(m.sessions))
+	keys := make([]sessionKey, 0, len(m.sessions))
+	for key, s := range m.sessions {
+		running[key] = s
+		keys = append(keys, key)
+	}
+	idle := make(chan struct{})
+	if len(m.sessions) == 0 {
+		close(idle)
+	} else {
+		m.idle = idle
+	}
+	m.mtx.Unlock()
+
+	deadline := make(chan struct{})
+	timer := m.clock.AfterFunc(timeout, func() { close(deadline) })
+	select {
+	case <-idle:
+	case <-deadline:
+	}
+	timer.Stop()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].tenant != keys[j].tenant {
+			return keys[i].tenant < keys[j].tenant
+		}
+		return keys[i].ssid < keys[j].ssid
+	})
+	summary := &DrainSummary{}
+	for _, key := range keys {
+		s := running[key]
+		if interrupted, ok := s.handler.interrupt(ErrSessionDrained, false); ok {
+			m.remove(key, s)
+			interrupted.Tenant = key.tenant
+			summary.Interrupted = append(summary.Interrupted, interrupted)
+			continue
+		}
+		if completed, ok := s.handler.completed(); ok {
+			completed.Tenant = key.tenant
+			summary.Completed = append(summary.Completed, completed)
+			continue
+		}
+		_, err := s.handler.Result()
+		summary.Failed = append(summary.Failed, &FailedSession{
+			Tenant: key.tenant,
+			SSID:   []byte(key.ssid),
+			Err:    err,
+		})
+	}
+	return summary
+}
+
+// Tenant returns the name of the tenant the Namespace is restricted to.
+func (n *Namespace) Tenant() string {
+	return n.tenant
+}
+
+// Start creates a new MultiHandler for the given protocol, and registers it with the Multiplexer under n's tenant.
+//
+// The session is isolated from those of other tenants, even if they use the same SSID.
+func (n *Namespace) Start(create StartFunc, sessionID []byte, opts ...HandlerOption) (*MultiHandler, error) {
+	return n.m.start(n.tenant, create, sessionID, opts)
+}
+
+// Accept forwards msg to the session of n's tenant it belongs to, like Multiplexer.Accept.
+// Messages for sessions of other tenants are ignored.
+func (n *Namespace) Accept(msg *Message) {
+	n.m.accept(n.tenant, msg)
+}
+
+// Sessions returns the SSIDs of the sessions of n's tenant which are still running, in increasing order.
+func (n *Namespace) Sessions() [][]byte {
+	n.m.mtx.Lock()
+	defer n.m.mtx.Unlock()
+	var ssids [][]byte
+	for key := range n.m.sessions {
+		if key.tenant == n.tenant {
+			ssids = append(ssids, []byte(key.ssid))
+		}
+	}
+	sort.Slice(ssids, func(i, j int) bool { return string(ssids[i]) < string(ssids[j]) })
+	return ssids
+}
+
+// OnExpire registers a callback, which will be called in its own goroutine for every session of n's tenant
+// expired from now on.
+func (n *Namespace) OnExpire(callback ExpiryCallback) {
+	n.m.mtx.Lock()
+	defer n.m.mtx.Unlock()
+	n.callbacks = append(n.callbacks, callback)
+}
+
+// OnComplete registers a callback, which will be called in its own goroutine for every session of n's tenant
+// which produces a result from now on.
+func (n *Namespace) OnComplete(callback CompletionCallback) {
+	n.m.mtx.Lock()
+	defer n.m.mtx.Unlock()
+	n.completed = append(n.completed, callback)
+}
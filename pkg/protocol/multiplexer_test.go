@@ -0,0 +1,395 @@
+package protocol_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestMultiplexerExpiry(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+
+	m := protocol.NewMultiplexer(50 * time.Millisecond)
+	expired := make(chan *protocol.ExpiredSession, 1)
+	m.OnExpire(func(session *protocol.ExpiredSession) { expired <- session })
+
+	h, err := m.Start(frost.Keygen(group, "a", ids, 1), sessionID)
+	require.NoError(t, err)
+
+	// b participates, but c never answers
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), sessionID)
+	require.NoError(t, err)
+	delivered := 0
+	for len(hb.Listen()) > 0 {
+		msg := <-hb.Listen()
+		if msg.IsFor("a") {
+			m.Accept(msg)
+			delivered++
+		}
+	}
+	require.NotZero(t, delivered)
+
+	var session *protocol.ExpiredSession
+	select {
+	case session = <-expired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("session did not expire")
+	}
+
+	assert.Equal(t, []party.ID{"c"}, session.Missing)
+	fromB := 0
+	for _, msg := range session.Transcript {
+		assert.NotEqual(t, party.ID("c"), msg.From)
+		if msg.From == "b" {
+			fromB++
+		}
+	}
+	assert.Equal(t, delivered, fromB, "transcript should contain the messages received from b")
+
+	_, err = h.Result()
+	var protocolErr protocol.Error
+	require.True(t, errors.As(err, &protocolErr))
+	assert.ErrorIs(t, err, protocol.ErrSessionExpired)
+	assert.Equal(t, []party.ID{"c"}, protocolErr.Culprits)
+}
+
+func TestMultiplexerCompletion(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b"}
+	sessionID := []byte("session")
+
+	m := protocol.NewMultiplexer(time.Minute)
+	defer m.Stop()
+	completed := make(chan *protocol.CompletedSession, 1)
+	m.OnComplete(func(session *protocol.CompletedSession) { completed <- session })
+
+	ha, err := m.Start(frost.Keygen(group, "a", ids, 1), sessionID)
+	require.NoError(t, err)
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), sessionID)
+	require.NoError(t, err)
+
+	for ha.Listen() != nil || hb.Listen() != nil {
+		select {
+		case msg, ok := <-ha.Listen():
+			if !ok {
+				continue
+			}
+			if msg.IsFor("b") {
+				hb.Accept(msg)
+			}
+		case msg, ok := <-hb.Listen():
+			if !ok {
+				continue
+			}
+			if msg.IsFor("a") {
+				m.Accept(msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("protocol did not complete")
+		}
+		if _, err := ha.Result(); err == nil {
+			break
+		}
+	}
+
+	select {
+	case session := <-completed:
+		assert.Equal(t, ids, session.Parties)
+		assert.Equal(t, "frost/keygen-threshold", session.Protocol)
+	case <-time.After(5 * time.Second):
+		t.Fatal("completion was not reported")
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	const protocolID = "cmp/sign"
+	w := protocol.NewWatchdog(2, protocolID)
+	alerts := make(chan *protocol.StallAlert, 10)
+	w.OnAlert(func(alert *protocol.StallAlert) { alerts <- alert })
+
+	stall := func(ssid, protocolID string, missing ...party.ID) {
+		w.Expired(&protocol.ExpiredSession{
+			SSID:     []byte(ssid),
+			Protocol: protocolID,
+			Round:    3,
+			Missing:  missing,
+		})
+	}
+
+	stall("1", protocolID, "c")
+	stall("2", "frost/sign-threshold", "c")
+	assert.Len(t, w.Stalls("c"), 1, "other protocols should be ignored")
+	assert.Empty(t, alerts)
+
+	stall("3", protocolID, "b", "c")
+	require.Len(t, alerts, 1)
+	alert := <-alerts
+	assert.Equal(t, party.ID("c"), alert.Party)
+	require.Len(t, alert.Stalls, 2)
+	assert.Equal(t, []byte("1"), alert.Stalls[0].SSID)
+	assert.Equal(t, []byte("3"), alert.Stalls[1].SSID)
+	assert.EqualValues(t, 3, alert.Stalls[1].Round)
+	assert.Len(t, w.Stalls("b"), 1)
+
+	// a completed session resets the count of its participants only
+	w.Completed(&protocol.CompletedSession{SSID: []byte("4"), Protocol: protocolID, Parties: party.IDSlice{"a", "c"}})
+	assert.Empty(t, w.Stalls("c"))
+	assert.Len(t, w.Stalls("b"), 1)
+
+	stall("5", protocolID, "b")
+	require.Len(t, alerts, 1)
+	assert.Equal(t, party.ID("b"), (<-alerts).Party)
+}
+
+func TestMultiplexerClock(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+
+	clock := protocol.NewManualClock(time.Unix(0, 0))
+	m := protocol.NewMultiplexer(time.Minute, protocol.WithClock(clock))
+	expired := make(chan *protocol.ExpiredSession, 1)
+	m.OnExpire(func(session *protocol.ExpiredSession) { expired <- session })
+
+	h, err := m.Start(frost.Keygen(group, "a", ids, 1), sessionID)
+	require.NoError(t, err)
+
+	clock.Advance(59 * time.Second)
+	select {
+	case <-expired:
+		t.Fatal("session expired before its timeout")
+	default:
+	}
+
+	// accepting a message from b resets the timeout
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), sessionID)
+	require.NoError(t, err)
+	var accepted []*protocol.Message
+	for len(hb.Listen()) > 0 {
+		if msg := <-hb.Listen(); msg.IsFor("a") {
+			m.Accept(msg)
+			accepted = append(accepted, msg)
+		}
+	}
+	clock.Advance(59 * time.Second)
+	select {
+	case <-expired:
+		t.Fatal("session expired although it made progress")
+	default:
+	}
+
+	// duplicates don't
+	for _, msg := range accepted {
+		m.Accept(msg)
+	}
+	clock.Advance(time.Second)
+	select {
+	case session := <-expired:
+		assert.Equal(t, []party.ID{"c"}, session.Missing)
+	case <-time.After(5 * time.Second):
+		t.Fatal("session did not expire")
+	}
+	_, err = h.Result()
+	assert.ErrorIs(t, err, protocol.ErrSessionExpired)
+}
+
+func TestMultiplexerNoTimeout(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+
+	clock := protocol.NewManualClock(time.Unix(0, 0))
+	m := protocol.NewMultiplexer(0, protocol.WithClock(clock))
+	expired := make(chan *protocol.ExpiredSession, 1)
+	m.OnExpire(func(session *protocol.ExpiredSession) { expired <- session })
+
+	_, err := m.Start(frost.Keygen(group, "a", ids, 1), sessionID)
+	require.NoError(t, err)
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), sessionID)
+	require.NoError(t, err)
+	for len(hb.Listen()) > 0 {
+		if msg := <-hb.Listen(); msg.IsFor("a") {
+			m.Accept(msg)
+		}
+	}
+	clock.Advance(24 * time.Hour)
+	select {
+	case <-expired:
+		t.Fatal("session expired without a timeout")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Len(t, m.Sessions(), 1)
+	m.Stop()
+}
+
+func TestMultiplexerStoppedSession(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+
+	m := protocol.NewMultiplexer(time.Hour, protocol.WithSessionLimit(1))
+	h, err := m.Start(frost.Keygen(group, "a", ids, 1), []byte("stopped"))
+	require.NoError(t, err)
+	_, err = m.Start(frost.Keygen(group, "a", ids, 1), []byte("other"))
+	require.ErrorIs(t, err, protocol.ErrSessionLimit)
+
+	// a session which finishes without a message is unregistered, freeing its slot
+	h.Stop()
+	require.Eventually(t, func() bool { return len(m.Sessions()) == 0 }, 5*time.Second, time.Millisecond)
+	_, err = m.Start(frost.Keygen(group, "a", ids, 1), []byte("other"))
+	assert.NoError(t, err)
+	m.Stop()
+}
+
+func TestMultiplexerNamespaces(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b"}
+	sessionID := []byte("session")
+
+	m := protocol.NewMultiplexer(time.Minute, protocol.WithSessionLimit(1))
+	defer m.Stop()
+	x, y := m.Namespace("x"), m.Namespace("y")
+	assert.Same(t, x, m.Namespace("x"))
+	completed := make(chan *protocol.CompletedSession, 2)
+	x.OnComplete(func(session *protocol.CompletedSession) { completed <- session })
+	y.OnComplete(func(session *protocol.CompletedSession) { t.Error("completion reported to another tenant") })
+
+	// both tenants may use the same SSID
+	hx, err := x.Start(frost.Keygen(group, "a", ids, 1), sessionID)
+	require.NoError(t, err)
+	hy, err := y.Start(frost.Keygen(group, "a", ids, 1), sessionID)
+	require.NoError(t, err)
+	assert.Len(t, x.Sessions(), 1)
+	assert.Len(t, y.Sessions(), 1)
+
+	_, err = x.Start(frost.Keygen(group, "a", ids, 1), []byte("other"))
+	assert.ErrorIs(t, err, protocol.ErrSessionLimit)
+
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), sessionID)
+	require.NoError(t, err)
+	for done := false; !done; {
+		select {
+		case msg := <-hx.Listen():
+			if msg != nil && msg.IsFor("b") {
+				hb.Accept(msg)
+			}
+		case msg := <-hb.Listen():
+			if msg != nil && msg.IsFor("a") {
+				x.Accept(msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("protocol did not complete")
+		}
+		_, err := hx.Result()
+		done = err == nil
+	}
+
+	select {
+	case session := <-completed:
+		assert.Equal(t, "x", session.Tenant)
+	case <-time.After(5 * time.Second):
+		t.Fatal("completion was not reported")
+	}
+	assert.Empty(t, x.Sessions())
+	assert.Len(t, y.Sessions(), 1, "messages for x should not reach y")
+	_, err = hy.Result()
+	assert.Error(t, err)
+
+	_, err = x.Start(frost.Keygen(group, "a", ids, 1), []byte("other"))
+	assert.NoError(t, err, "limit should be released once the session completes")
+}
+
+func TestMultiplexerDrain(t *testing.T) {
+	group := curve.Secp256k1{}
+	clock := protocol.NewManualClock(time.Unix(0, 0))
+	m := protocol.NewMultiplexer(time.Minute, protocol.WithClock(clock))
+
+	// the first session completes while draining, but c never answers in the second one
+	ha, err := m.Start(frost.Keygen(group, "a", party.IDSlice{"a", "b"}, 1), []byte("completed"))
+	require.NoError(t, err)
+	stalled, err := m.Start(frost.Keygen(group, "a", party.IDSlice{"a", "b", "c"}, 1), []byte("stalled"))
+	require.NoError(t, err)
+
+	summaries := make(chan *protocol.DrainSummary)
+	go func() { summaries <- m.Drain(10 * time.Second) }()
+	require.Eventually(t, func() bool {
+		_, err := m.Start(frost.Keygen(group, "a", party.IDSlice{"a", "b"}, 1), []byte("new"))
+		return errors.Is(err, protocol.ErrDraining)
+	}, 5*time.Second, time.Millisecond)
+
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", party.IDSlice{"a", "b"}, 1), []byte("completed"))
+	require.NoError(t, err)
+	for done := false; !done; {
+		select {
+		case msg := <-ha.Listen():
+			if msg != nil && msg.IsFor("b") {
+				hb.Accept(msg)
+			}
+		case msg := <-hb.Listen():
+			if msg != nil && msg.IsFor("a") {
+				m.Accept(msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("protocol did not complete")
+		}
+		_, err := ha.Result()
+		done = err == nil
+	}
+
+	var summary *protocol.DrainSummary
+	for summary == nil {
+		clock.Advance(time.Second)
+		select {
+		case summary = <-summaries:
+		case <-time.After(time.Millisecond):
+		}
+	}
+	require.Len(t, summary.Completed, 1)
+	assert.Equal(t, party.IDSlice{"a", "b"}, summary.Completed[0].Parties)
+	assert.Empty(t, summary.Failed)
+	require.Len(t, summary.Interrupted, 1)
+	assert.Equal(t, []party.ID{"b", "c"}, summary.Interrupted[0].Missing)
+
+	_, err = stalled.Result()
+	assert.ErrorIs(t, err, protocol.ErrSessionDrained)
+	var aborted *protocol.Message
+	for msg := range stalled.Listen() {
+		aborted = msg
+	}
+	require.NotNil(t, aborted, "the other parties should be told about the abort")
+	assert.Zero(t, aborted.RoundNumber)
+}
+
+func TestMultiplexerSessionOptions(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b"}
+
+	m := protocol.NewMultiplexer(time.Minute, protocol.WithSessionOptions(protocol.WithLimits(protocol.Limits{MaxMessageSize: 1})))
+	defer m.Stop()
+	ha, err := m.Start(frost.Keygen(group, "a", ids, 1), []byte("first"))
+	require.NoError(t, err)
+	hOther, err := m.Start(frost.Keygen(group, "a", ids, 1), []byte("second"))
+	require.NoError(t, err)
+	assert.Len(t, m.Sessions(), 2)
+
+	hb, err := protocol.NewMultiHandler(frost.Keygen(group, "b", ids, 1), []byte("first"))
+	require.NoError(t, err)
+	msg := <-hb.Listen()
+	m.Accept(msg)
+
+	_, err = ha.Result()
+	assert.ErrorIs(t, err, protocol.ErrMessageTooLarge)
+	require.Len(t, m.Sessions(), 1, "the aborted session should be removed")
+	assert.NotEqual(t, msg.SSID, m.Sessions()[0])
+	assert.False(t, hOther.CanAccept(msg), "messages of one session are not accepted by another")
+	_, err = hOther.Result()
+	assert.NotErrorIs(t, err, protocol.ErrMessageTooLarge)
+}
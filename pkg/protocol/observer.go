@@ -0,0 +1,87 @@
+package protocol
+
+import (
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// RoundInfo identifies the round of a session an Observer is notified about.
+type RoundInfo struct {
+	Protocol string
+	SSID     []byte
+	SelfID   party.ID
+	Round    round.Number
+}
+
+// Observer is notified by a MultiHandler of the progress of its session, for example to export metrics.
+//
+// The methods are called while the handler is locked, and should return quickly.
+// Sizes are the lengths of the Data of the messages.
+// Embedding NopObserver makes it possible to only implement some of the methods.
+type Observer interface {
+	// RoundStarted is called when the session reaches a round.
+	RoundStarted(info RoundInfo)
+	// RoundFinished is called once a round has been finalized, with the time elapsed since it was reached.
+	RoundFinished(info RoundInfo, elapsed time.Duration)
+	// MessageReceived is called for every new message accepted by the handler.
+	MessageReceived(info RoundInfo, from party.ID, broadcast bool, size int)
+	// MessageVerified is called once a message of the round has been verified and stored,
+	// with the time it took, and the error making it invalid, if any.
+	MessageVerified(info RoundInfo, from party.ID, broadcast bool, elapsed time.Duration, err error)
+	// MessageSent is called for every message emitted on the Listen channel, except aborts.
+	MessageSent(info RoundInfo, to party.ID, broadcast bool, size int)
+	// SessionFinished is called once, when the session produces its result, or aborts with err, an *Error,
+	// with the time elapsed since the handler was created.
+	// info.Round is 0 if the session ended because a round returned a result or an abort.
+	SessionFinished(info RoundInfo, elapsed time.Duration, err error)
+}
+
+// NopObserver implements Observer, doing nothing.
+type NopObserver struct{}
+
+// RoundStarted implements Observer.
+func (NopObserver) RoundStarted(RoundInfo) {}
+
+// RoundFinished implements Observer.
+func (NopObserver) RoundFinished(RoundInfo, time.Duration) {}
+
+// MessageReceived implements Observer.
+func (NopObserver) MessageReceived(RoundInfo, party.ID, bool, int) {}
+
+// MessageVerified implements Observer.
+func (NopObserver) MessageVerified(RoundInfo, party.ID, bool, time.Duration, error) {}
+
+// MessageSent implements Observer.
+func (NopObserver) MessageSent(RoundInfo, party.ID, bool, int) {}
+
+// SessionFinished implements Observer.
+func (NopObserver) SessionFinished(RoundInfo, time.Duration, error) {}
+
+// WithObserver makes the MultiHandler notify o of the progress of the session.
+//
+// It can be given several times, to notify several observers.
+func WithObserver(o Observer) HandlerOption {
+	return func(h *MultiHandler) {
+		addObserver(h, o)
+	}
+}
+
+// roundInfo describes r to the observer.
+func (h *MultiHandler) roundInfo(r round.Session) RoundInfo {
+	return RoundInfo{
+		Protocol: r.ProtocolID(),
+		SSID:     r.SSID(),
+		SelfID:   r.SelfID(),
+		Round:    r.Number(),
+	}
+}
+
+// startRound is called whenever the current round changes, before its messages are handled.
+func (h *MultiHandler) startRound() {
+	h.roundStart = h.clock.Now()
+	h.setLogger()
+	h.startRoundTimer()
+	h.observer.RoundStarted(h.roundInfo(h.currentRound))
+}
@@ -0,0 +1,98 @@
+package protocol
+
+import (
+	"fmt"
+)
+
+// PollHandler runs a protocol like a MultiHandler, but returns the outgoing messages from Poll instead of a channel,
+// so that it can be driven from a single threaded event loop, such as that of a browser running WebAssembly.
+//
+// Accept does all the work triggered by a message before returning, and the messages it produces are kept
+// until the next call to Poll, so the caller never has to wait on the handler.
+// The options of MultiHandler apply, but WithRoundTimeout and WithParallelVerification rely on goroutines.
+type PollHandler struct {
+	h *MultiHandler
+}
+
+// NewPollHandler expects a StartFunc for the desired protocol, and returns a PollHandler running it.
+//
+// The messages of the first round are returned by the first call to Poll.
+func NewPollHandler(create StartFunc, sessionID []byte, opts ...HandlerOption) (*PollHandler, error) {
+	h, err := newPolledHandler(create, sessionID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &PollHandler{h: h}, nil
+}
+
+// newPolledHandler is like NewMultiHandler, but keeps the outgoing messages in pending instead of sending them on out.
+func newPolledHandler(create StartFunc, sessionID []byte, opts []HandlerOption) (*MultiHandler, error) {
+	r, err := create(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: failed to create round: %w", err)
+	}
+	h := newMultiHandler(r, sessionID, opts)
+	h.mtx.Lock()
+	h.polled = true
+	h.startRound()
+	h.finalize()
+	h.mtx.Unlock()
+	return h, nil
+}
+
+// ResumePollHandler continues a session suspended with PollHandler.Suspend, like ResumeMultiHandler.
+//
+// The messages this party sent when reaching the current round are returned again by the first call to Poll.
+func ResumePollHandler(resume ResumeFunc, data []byte, opts ...HandlerOption) (*PollHandler, error) {
+	h, err := resumeMultiHandler(resume, data, true, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &PollHandler{h: h}, nil
+}
+
+// Poll returns the messages produced since the previous call, in the order they were produced, or nil if there are none.
+//
+// As with MultiHandler.Listen, a message must be _reliably_ broadcast if msg.Broadcast is true.
+// If the protocol aborts, the last message returned tells the other parties about it.
+func (p *PollHandler) Poll() []*Message {
+	p.h.mtx.Lock()
+	defer p.h.mtx.Unlock()
+	pending := p.h.pending
+	p.h.pending = nil
+	return pending
+}
+
+// CanAccept checks whether or not a message can be accepted at the current point in the protocol.
+func (p *PollHandler) CanAccept(msg *Message) bool {
+	p.h.mtx.Lock()
+	defer p.h.mtx.Unlock()
+	return p.h.canAccept(msg)
+}
+
+// Accept processes msg, and advances the protocol as far as it can. The messages to send are then returned by Poll.
+func (p *PollHandler) Accept(msg *Message) {
+	p.h.Accept(msg)
+}
+
+// Done returns true once the protocol has either completed or aborted, after which Result returns its outcome.
+//
+// Poll should still be called once, to send the last messages.
+func (p *PollHandler) Done() bool {
+	return p.h.done()
+}
+
+// Result returns the protocol result if the protocol completed successfully. Otherwise an error is returned.
+func (p *PollHandler) Result() (interface{}, error) {
+	return p.h.Result()
+}
+
+// Stop cancels the current execution of the protocol. The message alerting the other parties is returned by Poll.
+func (p *PollHandler) Stop() {
+	p.h.Stop()
+}
+
+// Suspend returns a snapshot of the session, from which ResumePollHandler can continue the protocol, like MultiHandler.Suspend.
+func (p *PollHandler) Suspend() ([]byte, error) {
+	return p.h.Suspend()
+}
@@ -0,0 +1,80 @@
+package protocol_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestPollHandler(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+
+	handlers := make(map[party.ID]*protocol.PollHandler, len(ids))
+	for _, id := range ids {
+		h, err := protocol.NewPollHandler(frost.Keygen(group, id, ids, 1), sessionID)
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+
+	// messages are delivered all at once, latest first, so that "c" receives those of later rounds before earlier ones
+	// and processes several rounds in a single call to Accept
+	var queue []*protocol.Message
+	for {
+		for _, id := range ids {
+			queue = append(queue, handlers[id].Poll()...)
+		}
+		if len(queue) == 0 {
+			break
+		}
+		for i := len(queue) - 1; i >= 0; i-- {
+			for _, id := range ids {
+				if id != queue[i].From && queue[i].IsFor(id) {
+					handlers[id].Accept(queue[i])
+				}
+			}
+		}
+		queue = queue[:0]
+	}
+
+	var publicKey curve.Point
+	for _, id := range ids {
+		require.True(t, handlers[id].Done())
+		result, err := handlers[id].Result()
+		require.NoError(t, err, "party %s", id)
+		c := result.(*frost.Config)
+		if publicKey == nil {
+			publicKey = c.PublicKey
+		}
+		assert.True(t, publicKey.Equal(c.PublicKey))
+	}
+}
+
+func TestPollHandlerStop(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b"}
+	h, err := protocol.NewPollHandler(frost.Keygen(group, "a", ids, 1), nil)
+	require.NoError(t, err)
+	hb, err := protocol.NewPollHandler(frost.Keygen(group, "b", ids, 1), nil)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, h.Poll())
+	assert.Nil(t, h.Poll(), "messages are only returned once")
+	assert.False(t, h.Done())
+
+	h.Stop()
+	assert.True(t, h.Done())
+	messages := h.Poll()
+	require.Len(t, messages, 1)
+	hb.Poll()
+	hb.Accept(messages[0])
+	assert.True(t, hb.Done())
+	_, err = hb.Result()
+	assert.Error(t, err)
+}
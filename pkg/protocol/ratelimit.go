@@ -0,0 +1,74 @@
+package protocol
+
+import (
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// RateLimit bounds the rate at which a MultiHandler processes the messages of each party.
+//
+// Every party is allowed Burst messages at once, and then Rate messages per second.
+// Messages beyond this are dropped before their content is decoded,
+// so that a flooding peer costs little more than reading its messages and checking their signature.
+// Messages for future rounds count as well, but duplicates are dropped beforehand.
+//
+// A session only needs a couple of messages per party and round, and messages which were dropped can be delivered again later,
+// so the limit can be set close to the pace of the protocol.
+// A message is only charged to its sender once it is authenticated by WithIdentity,
+// so that messages forged in the name of a party don't consume its allowance.
+type RateLimit struct {
+	// Rate is the number of messages per second allowed for each party, after the Burst.
+	Rate float64
+	// Burst is the number of messages a party may send at once, at least 1.
+	Burst int
+}
+
+// WithRateLimit makes the MultiHandler drop the messages of a party exceeding l.
+//
+// The rate is measured with clock, or SystemClock if clock is nil.
+func WithRateLimit(l RateLimit, clock Clock) HandlerOption {
+	return func(h *MultiHandler) {
+		if clock == nil {
+			clock = SystemClock
+		}
+		h.clock = clock
+		h.rateLimit = l
+		h.allowances = make(map[party.ID]*allowance)
+	}
+}
+
+// allowance is the token bucket of a party.
+type allowance struct {
+	tokens float64
+	last   time.Time
+}
+
+// allow returns true if a message from id is within the RateLimit of the handler, and consumes it.
+func (h *MultiHandler) allow(id party.ID) bool {
+	if h.allowances == nil {
+		return true
+	}
+	burst := float64(h.rateLimit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	now := h.clock.Now()
+	a, ok := h.allowances[id]
+	if !ok {
+		a = &allowance{tokens: burst, last: now}
+		h.allowances[id] = a
+	}
+	if elapsed := now.Sub(a.last); elapsed > 0 {
+		a.tokens += elapsed.Seconds() * h.rateLimit.Rate
+		if a.tokens > burst {
+			a.tokens = burst
+		}
+	}
+	a.last = now
+	if a.tokens < 1 {
+		return false
+	}
+	a.tokens--
+	return true
+}
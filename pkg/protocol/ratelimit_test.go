@@ -0,0 +1,76 @@
+package protocol_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	keys := make(map[party.ID]ed25519.PrivateKey, len(ids))
+	identities := make(map[party.ID]ed25519.PublicKey, len(ids))
+	for _, id := range ids {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		keys[id], identities[id] = private, public
+	}
+
+	clock := protocol.NewManualClock(time.Unix(0, 0))
+	d, err := protocol.NewDriver(frost.Keygen(group, "a", ids, 1), []byte("session"),
+		protocol.WithIdentity(keys["a"], identities), protocol.WithRateLimit(protocol.RateLimit{Rate: 1, Burst: 1}, clock))
+	require.NoError(t, err)
+	_, fromA, err := d.Advance(nil)
+	require.NoError(t, err)
+	handlers := make(map[party.ID]*protocol.MultiHandler)
+	first := make(map[party.ID]*protocol.Message)
+	for _, id := range ids[1:] {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, 1), []byte("session"), protocol.WithIdentity(keys[id], identities))
+		require.NoError(t, err)
+		handlers[id], first[id] = h, <-h.Listen()
+	}
+
+	// a forged message is dropped without using up the allowance of b
+	forged := *first["b"]
+	forged.Signature = nil
+	_, _, err = d.Advance([]*protocol.Message{&forged, first["b"], first["c"]})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, d.Round())
+
+	// the next messages of b and c, a broadcast and a direct message each, come too soon, and are dropped
+	var third []*protocol.Message
+	for _, h := range handlers {
+		for _, msg := range append(fromA, first["b"], first["c"]) {
+			h.Accept(msg)
+		}
+		for len(h.Listen()) > 0 {
+			if msg := <-h.Listen(); msg.RoundNumber == 3 && msg.IsFor("a") {
+				third = append(third, msg)
+			}
+		}
+	}
+	require.Len(t, third, 4)
+	_, _, err = d.Advance(third)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, d.Round())
+
+	// once the allowance is back, a message of each party can be delivered every second
+	clock.Advance(time.Second)
+	next, _, err := d.Advance(third)
+	require.NoError(t, err)
+	assert.True(t, next, "only one message of each party should have been accepted")
+
+	clock.Advance(time.Second)
+	next, _, err = d.Advance(third)
+	require.NoError(t, err)
+	assert.False(t, next, "the protocol should have finished")
+}
@@ -0,0 +1,147 @@
+package protocol
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// ReplayKey identifies a message accepted by a handler.
+//
+// A party sends at most one broadcast and one normal message to each other party in every round,
+// so a second message with the same key is either a duplicate or a replay.
+type ReplayKey struct {
+	// SSID identifies the session the message belongs to.
+	SSID []byte
+	// From is the sender of the message.
+	From party.ID
+	// To is the party which accepted the message, so that several local parties can share a ReplayStore.
+	To party.ID
+	// Round is the number of the round the message belongs to.
+	Round round.Number
+	// Broadcast is true for broadcast messages.
+	Broadcast bool
+}
+
+// digest returns a fixed size encoding of the key.
+func (k ReplayKey) digest() string {
+	var broadcast byte
+	if k.Broadcast {
+		broadcast = 1
+	}
+	h := hash.New(
+		hash.BytesWithDomain{TheDomain: "SSID", Bytes: k.SSID},
+		k.From,
+		k.To,
+		k.Round,
+		hash.BytesWithDomain{TheDomain: "Broadcast", Bytes: []byte{broadcast}},
+	)
+	return hex.EncodeToString(h.Sum())
+}
+
+// ReplayStore records the messages accepted by handlers, so that duplicated or replayed messages are rejected,
+// even if they arrive after the process was restarted.
+type ReplayStore interface {
+	// Record marks the message identified by key as accepted, and returns true if it was already marked.
+	// It must be safe to call concurrently.
+	Record(key ReplayKey) (replayed bool, err error)
+}
+
+// WithReplayStore makes the MultiHandler record every message it accepts in store,
+// and ignore the messages store has already recorded.
+//
+// If store fails to record a message, the protocol is aborted, since the message can neither be
+// safely accepted nor ignored.
+//
+// A message is recorded when it is accepted, before the application can persist the snapshot containing it,
+// so a handler resumed with ResumeMultiHandler doesn't reject the messages recorded in store:
+// those accepted after its snapshot was taken were lost if the process crashed, and must be accepted again.
+// It relies on the snapshot instead, whose messages are rejected as duplicates, and on rejecting the earlier rounds.
+func WithReplayStore(store ReplayStore) HandlerOption {
+	return func(h *MultiHandler) {
+		h.replays = store
+	}
+}
+
+// MemoryReplayStore is a ReplayStore which only lasts as long as the process.
+type MemoryReplayStore struct {
+	seen map[string]struct{}
+	mtx  sync.Mutex
+}
+
+// NewMemoryReplayStore returns an empty MemoryReplayStore.
+func NewMemoryReplayStore() *MemoryReplayStore {
+	return &MemoryReplayStore{seen: map[string]struct{}{}}
+}
+
+// Record implements ReplayStore.
+func (s *MemoryReplayStore) Record(key ReplayKey) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	digest := key.digest()
+	if _, ok := s.seen[digest]; ok {
+		return true, nil
+	}
+	s.seen[digest] = struct{}{}
+	return false, nil
+}
+
+// FileReplayStore is a ReplayStore which appends the keys it records to a file,
+// and can therefore be reopened after a restart.
+type FileReplayStore struct {
+	file *os.File
+	seen map[string]struct{}
+	mtx  sync.Mutex
+}
+
+// OpenFileReplayStore opens the store at path, creating the file if it doesn't exist,
+// and loads the keys recorded so far.
+func OpenFileReplayStore(path string) (*FileReplayStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: replay store: %w", err)
+	}
+	s := &FileReplayStore{file: file, seen: map[string]struct{}{}}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			s.seen[line] = struct{}{}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("protocol: replay store: %w", err)
+	}
+	return s, nil
+}
+
+// Record implements ReplayStore.
+//
+// The key is synced to disk before Record returns.
+func (s *FileReplayStore) Record(key ReplayKey) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	digest := key.digest()
+	if _, ok := s.seen[digest]; ok {
+		return true, nil
+	}
+	if _, err := s.file.WriteString(digest + "\n"); err != nil {
+		return false, fmt.Errorf("protocol: replay store: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return false, fmt.Errorf("protocol: replay store: %w", err)
+	}
+	s.seen[digest] = struct{}{}
+	return false, nil
+}
+
+// Close closes the underlying file.
+func (s *FileReplayStore) Close() error {
+	return s.file.Close()
+}
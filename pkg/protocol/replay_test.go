@@ -0,0 +1,110 @@
+package protocol_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestFileReplayStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "replays")
+	key := protocol.ReplayKey{SSID: []byte("session"), From: "b", To: "a", Round: 2, Broadcast: true}
+
+	store, err := protocol.OpenFileReplayStore(path)
+	require.NoError(t, err)
+	replayed, err := store.Record(key)
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	replayed, err = store.Record(key)
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	require.NoError(t, store.Close())
+
+	store, err = protocol.OpenFileReplayStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	replayed, err = store.Record(key)
+	require.NoError(t, err)
+	assert.True(t, replayed, "keys should persist across reopening")
+
+	key.To = "c"
+	replayed, err = store.Record(key)
+	require.NoError(t, err)
+	assert.False(t, replayed, "other recipients should not be affected")
+}
+
+func TestMultiHandlerReplayStore(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+	store := protocol.NewMemoryReplayStore()
+
+	var round2 []*protocol.Message
+	for _, id := range ids[1:] {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, 1), sessionID)
+		require.NoError(t, err)
+		for len(h.Listen()) > 0 {
+			if msg := <-h.Listen(); msg.IsFor("a") {
+				round2 = append(round2, msg)
+			}
+		}
+	}
+
+	start := func() *protocol.MultiHandler {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), sessionID, protocol.WithReplayStore(store))
+		require.NoError(t, err)
+		for _, msg := range round2 {
+			h.Accept(msg)
+		}
+		return h
+	}
+
+	// the first handler reaches round 3 and sends its messages for it, along with its broadcast for round 2
+	assert.Greater(t, len(start().Listen()), 1)
+	// after a restart, the same messages are rejected, so only the broadcast for round 2 is sent
+	assert.Len(t, start().Listen(), 1)
+}
+
+func TestMultiHandlerReplayStoreResume(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	sessionID := []byte("session")
+	store := protocol.NewMemoryReplayStore()
+
+	var round2 []*protocol.Message
+	for _, id := range ids[1:] {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, 1), sessionID)
+		require.NoError(t, err)
+		for len(h.Listen()) > 0 {
+			if msg := <-h.Listen(); msg.IsFor("a") {
+				round2 = append(round2, msg)
+			}
+		}
+	}
+
+	h, err := protocol.NewMultiHandler(frost.Keygen(group, "a", ids, 1), sessionID, protocol.WithReplayStore(store))
+	require.NoError(t, err)
+	snapshot, err := h.Suspend()
+	require.NoError(t, err)
+	// the messages are recorded in the store, but the process crashes before the next snapshot is persisted
+	for _, msg := range round2 {
+		h.Accept(msg)
+	}
+	assert.Greater(t, len(h.Listen()), 1)
+
+	resume := frost.ResumeKeygen(frost.Keygen(group, "a", ids, 1))
+	h, err = protocol.ResumeMultiHandler(resume, snapshot, protocol.WithReplayStore(store))
+	require.NoError(t, err)
+	// the resumed handler only sends its broadcast for round 2 again, until the redelivered messages are accepted
+	require.Len(t, h.Listen(), 1)
+	for _, msg := range round2 {
+		h.Accept(msg)
+	}
+	assert.Greater(t, len(h.Listen()), 1, "the resumed handler should reach round 3")
+}
@@ -0,0 +1,52 @@
+package protocol
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// SessionParameters are the public values all the parties of a session agree on, and from which its SSID is derived.
+type SessionParameters struct {
+	// ProtocolID identifies the protocol, as found in Message.Protocol, such as "cmp/sign" or "frost/keygen-threshold".
+	ProtocolID string
+	// Group is the curve the protocol runs over.
+	Group curve.Curve
+	// PartyIDs contains all the participants of the session, in any order.
+	PartyIDs []party.ID
+	// Threshold is the threshold of the key being generated or used.
+	Threshold int
+	// Hash is the hash function of the session, hash.BLAKE3 if unset.
+	Hash hash.Function
+}
+
+// DeriveSSID returns the SSID of a session with the given parameters, started with sessionID,
+// which is the value found in Message.SSID and returned by the session's Suspend and Observer.
+//
+// This lets a coordinator assign SSIDs to sessions before the parties start them, and check the messages they exchange.
+// sessionID is the optional application context, and can be the identifier of the request the session serves,
+// so that each request maps to a single SSID.
+//
+// Some protocols also bind public data of their inputs, which must then be given as auxInfo in the same order.
+// For instance, cmp signing binds the config of the signers, whose hash only depends on public values,
+// followed by hash.BytesWithDomain{TheDomain: "Signature Message", Bytes: messageHash}.
+func DeriveSSID(params SessionParameters, sessionID []byte, auxInfo ...hash.WriterToWithDomain) ([]byte, error) {
+	if len(params.PartyIDs) == 0 {
+		return nil, errors.New("protocol: no parties")
+	}
+	helper, err := round.NewSession(round.Info{
+		ProtocolID: params.ProtocolID,
+		SelfID:     params.PartyIDs[0],
+		PartyIDs:   params.PartyIDs,
+		Threshold:  params.Threshold,
+		Group:      params.Group,
+		Hash:       params.Hash,
+	}, sessionID, nil, auxInfo...)
+	if err != nil {
+		return nil, err
+	}
+	return helper.SSID(), nil
+}
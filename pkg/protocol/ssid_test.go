@@ -0,0 +1,72 @@
+package protocol_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func TestDeriveSSID(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"c", "a", "b"}
+	requestID := []byte("request 42")
+
+	params := protocol.SessionParameters{
+		ProtocolID: "frost/keygen-threshold",
+		Group:      group,
+		PartyIDs:   ids,
+		Threshold:  1,
+	}
+	ssid, err := protocol.DeriveSSID(params, requestID)
+	require.NoError(t, err)
+	for _, id := range ids {
+		s, err := frost.Keygen(group, id, ids, 1)(requestID)
+		require.NoError(t, err)
+		assert.Equal(t, ssid, s.SSID(), "party %s", id)
+	}
+
+	other, err := protocol.DeriveSSID(params, []byte("request 43"))
+	require.NoError(t, err)
+	assert.NotEqual(t, ssid, other)
+	params.Hash = hash.SHA256
+	other, err = protocol.DeriveSSID(params, requestID)
+	require.NoError(t, err)
+	assert.NotEqual(t, ssid, other)
+
+	_, err = protocol.DeriveSSID(protocol.SessionParameters{ProtocolID: "frost/keygen-threshold", Group: group}, requestID)
+	assert.Error(t, err)
+	_, err = protocol.DeriveSSID(protocol.SessionParameters{ProtocolID: "frost/keygen-threshold", Group: group, PartyIDs: ids, Threshold: 3}, requestID)
+	assert.Error(t, err)
+}
+
+func TestDeriveSSIDAuxInfo(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+	configs, ids := test.GenerateConfig(group, 2, 1, rand.Reader, pl)
+	messageHash := []byte("message hash")
+	sessionID := []byte("request 42")
+
+	s, err := cmp.Sign(configs[ids[0]], ids, messageHash, pl)(sessionID)
+	require.NoError(t, err)
+
+	// the config of the other party hashes to the same value, since only public data is bound
+	ssid, err := protocol.DeriveSSID(protocol.SessionParameters{
+		ProtocolID: "cmp/sign",
+		Group:      group,
+		PartyIDs:   ids,
+		Threshold:  1,
+	}, sessionID, configs[ids[1]], hash.BytesWithDomain{TheDomain: "Signature Message", Bytes: messageHash})
+	require.NoError(t, err)
+	assert.Equal(t, s.SSID(), ssid)
+}
@@ -0,0 +1,130 @@
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+)
+
+// ErrNotSuspendable is returned by MultiHandler.Suspend when the current round of the protocol cannot be saved.
+var ErrNotSuspendable = errors.New("protocol: round cannot be suspended")
+
+// ResumeFunc recreates the round of a protocol with the given number, from the state saved by MultiHandler.Suspend.
+type ResumeFunc func(sessionID []byte, number round.Number, state []byte) (round.Session, error)
+
+const snapshotVersion = 1
+
+// snapshot is the serialized form of a suspended MultiHandler.
+type snapshot struct {
+	Version   uint8
+	SessionID []byte
+	SSID      []byte
+	Protocol  string
+	// Round is the number of the current round, and State its marshalled state.
+	Round round.Number
+	State []byte
+	// Messages contains all the messages stored by the handler, including queued ones and our own broadcasts.
+	Messages []*Message
+	// Sent contains the messages produced when the current round was reached.
+	Sent            []*Message
+	BroadcastHashes map[round.Number][]byte
+}
+
+// Suspend returns a snapshot of the session, from which ResumeMultiHandler can continue the protocol,
+// for example after the process was restarted.
+//
+// The snapshot contains the secrets of this party, and must be stored securely.
+// Rounds sample new randomness when they are finalized, so resuming from an older snapshot could make this party
+// send different messages for the same round. The snapshot should therefore be persisted after every call to Accept,
+// before sending the messages it produced.
+func (h *MultiHandler) Suspend() ([]byte, error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err != nil || h.result != nil {
+		return nil, errors.New("protocol: cannot suspend a finished session")
+	}
+	r, ok := h.currentRound.(round.Suspendable)
+	if !ok {
+		return nil, ErrNotSuspendable
+	}
+	state, err := r.MarshalState()
+	if err != nil {
+		return nil, fmt.Errorf("protocol: failed to suspend round %d: %w", h.currentRound.Number(), err)
+	}
+	var messages []*Message
+	for number := round.Number(1); number <= h.currentRound.FinalRoundNumber(); number++ {
+		for _, id := range h.currentRound.PartyIDs() {
+			if msg := h.broadcast[number][id]; msg != nil {
+				messages = append(messages, msg)
+			}
+			if msg := h.messages[number][id]; msg != nil {
+				messages = append(messages, msg)
+			}
+		}
+	}
+	return cbor.Marshal(&snapshot{
+		Version:         snapshotVersion,
+		SessionID:       h.sessionID,
+		SSID:            h.currentRound.SSID(),
+		Protocol:        h.currentRound.ProtocolID(),
+		Round:           h.currentRound.Number(),
+		State:           state,
+		Messages:        messages,
+		Sent:            h.sent,
+		BroadcastHashes: h.broadcastHashes,
+	})
+}
+
+// ResumeMultiHandler continues a session suspended with MultiHandler.Suspend, using resume to recreate its current round.
+//
+// The messages this party sent when reaching that round are emitted again on the Listen channel,
+// since the other parties may not have received them before the suspension. Parties ignore the duplicates.
+func ResumeMultiHandler(resume ResumeFunc, data []byte, opts ...HandlerOption) (*MultiHandler, error) {
+	return resumeMultiHandler(resume, data, false, opts)
+}
+
+// resumeMultiHandler implements ResumeMultiHandler, and ResumePollHandler if polled is true.
+func resumeMultiHandler(resume ResumeFunc, data []byte, polled bool, opts []HandlerOption) (*MultiHandler, error) {
+	var s snapshot
+	if err := cbor.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("protocol: invalid snapshot: %w", err)
+	}
+	if s.Version != snapshotVersion {
+		return nil, fmt.Errorf("protocol: unsupported snapshot version %d", s.Version)
+	}
+	r, err := resume(s.SessionID, s.Round, s.State)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: failed to resume round %d: %w", s.Round, err)
+	}
+	if r.Number() != s.Round || r.ProtocolID() != s.Protocol || !bytes.Equal(r.SSID(), s.SSID) {
+		return nil, errors.New("protocol: resumed round does not match the snapshot")
+	}
+
+	h := newMultiHandler(r, s.SessionID, opts)
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.polled = polled
+	h.resumed = true
+	for number, hash := range s.BroadcastHashes {
+		h.broadcastHashes[number] = hash
+	}
+	for _, msg := range s.Messages {
+		if msg == nil || msg.Protocol != s.Protocol || !bytes.Equal(msg.SSID, s.SSID) {
+			return nil, errors.New("protocol: snapshot contains a message of another session")
+		}
+		h.store(msg)
+	}
+	h.sent = s.Sent
+	for _, msg := range h.sent {
+		h.send(msg)
+	}
+
+	h.startRound()
+	if h.verifyQueued() {
+		h.finalize()
+	}
+	return h, nil
+}
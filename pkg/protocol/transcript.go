@@ -0,0 +1,169 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+)
+
+// TranscriptEntry is a message received or sent by a MultiHandler, as recorded in the transcript of its session.
+type TranscriptEntry struct {
+	// Sequence is the position of the entry in the transcript, starting at 0.
+	Sequence uint64
+	// Time is when the message was accepted or emitted, according to the clock of the handler.
+	Time time.Time
+	// Outbound is true for the messages sent by this party, including its abort message.
+	Outbound bool
+	Message  *Message
+	// MessageHash is Message.Hash().
+	MessageHash []byte
+	// Chain commits to this entry and to the Chain of the previous one,
+	// so that entries can't be modified, removed or reordered without VerifyTranscript noticing.
+	Chain []byte
+}
+
+// chain computes the Chain of e, which follows an entry whose chain is previous.
+func (e *TranscriptEntry) chain(previous []byte) []byte {
+	var header [17]byte
+	binary.BigEndian.PutUint64(header[:8], e.Sequence)
+	binary.BigEndian.PutUint64(header[8:16], uint64(e.Time.UnixNano()))
+	if e.Outbound {
+		header[16] = 1
+	}
+	return hash.New(
+		hash.BytesWithDomain{TheDomain: "Transcript Previous", Bytes: append([]byte{}, previous...)},
+		hash.BytesWithDomain{TheDomain: "Transcript Entry", Bytes: header[:]},
+		hash.BytesWithDomain{TheDomain: "Transcript Message", Bytes: e.MessageHash},
+	).Sum()
+}
+
+// TranscriptSink receives the entries of the transcripts of sessions, in order.
+type TranscriptSink interface {
+	// Record stores entry. It may be called concurrently for different sessions.
+	Record(entry *TranscriptEntry) error
+}
+
+// WithTranscript makes the MultiHandler record every message it accepts or emits in sink,
+// producing an auditable transcript of the session.
+//
+// If sink fails to record a message, the protocol is aborted, so that no message goes unrecorded.
+// A session resumed with ResumeMultiHandler starts a new transcript.
+func WithTranscript(sink TranscriptSink) HandlerOption {
+	return func(h *MultiHandler) {
+		h.transcriptSink = sink
+	}
+}
+
+// record adds msg to the transcript of the session, if there is one.
+func (h *MultiHandler) record(msg *Message, outbound bool) error {
+	if h.transcriptSink == nil {
+		return nil
+	}
+	entry := &TranscriptEntry{
+		Sequence:    h.transcriptLen,
+		Time:        h.clock.Now(),
+		Outbound:    outbound,
+		Message:     msg,
+		MessageHash: msg.Hash(),
+	}
+	entry.Chain = entry.chain(h.transcriptChain)
+	if err := h.transcriptSink.Record(entry); err != nil {
+		return fmt.Errorf("protocol: transcript: %w", err)
+	}
+	h.transcriptLen++
+	h.transcriptChain = entry.Chain
+	return nil
+}
+
+// VerifyTranscript checks that entries are an unaltered transcript, from its first entry,
+// and that the messages match their hashes.
+func VerifyTranscript(entries []*TranscriptEntry) error {
+	var previous []byte
+	for i, e := range entries {
+		if e == nil || e.Message == nil {
+			return fmt.Errorf("protocol: transcript: entry %d is empty", i)
+		}
+		if e.Sequence != uint64(i) {
+			return fmt.Errorf("protocol: transcript: entry %d has sequence number %d", i, e.Sequence)
+		}
+		if !bytes.Equal(e.MessageHash, e.Message.Hash()) {
+			return fmt.Errorf("protocol: transcript: entry %d doesn't match its message", i)
+		}
+		if !bytes.Equal(e.Chain, e.chain(previous)) {
+			return fmt.Errorf("protocol: transcript: entry %d isn't chained to the previous one", i)
+		}
+		previous = e.Chain
+	}
+	return nil
+}
+
+// MemoryTranscript is a TranscriptSink keeping the entries in memory.
+type MemoryTranscript struct {
+	entries []*TranscriptEntry
+	mtx     sync.Mutex
+}
+
+// Record implements TranscriptSink.
+func (t *MemoryTranscript) Record(entry *TranscriptEntry) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.entries = append(t.entries, entry)
+	return nil
+}
+
+// Entries returns the entries recorded so far.
+func (t *MemoryTranscript) Entries() []*TranscriptEntry {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return append([]*TranscriptEntry{}, t.entries...)
+}
+
+// WriterTranscript is a TranscriptSink encoding the entries to an io.Writer, such as an archive file,
+// from which ReadTranscript decodes them.
+//
+// It should only be used by a single session, since entries of different sessions would be interleaved.
+type WriterTranscript struct {
+	encoder *cbor.Encoder
+	mtx     sync.Mutex
+}
+
+// transcriptEncMode keeps the nanoseconds of the times, which are part of the chain.
+var transcriptEncMode, _ = cbor.EncOptions{Time: cbor.TimeRFC3339Nano}.EncMode()
+
+// NewWriterTranscript returns a WriterTranscript writing to w.
+func NewWriterTranscript(w io.Writer) *WriterTranscript {
+	return &WriterTranscript{encoder: transcriptEncMode.NewEncoder(w)}
+}
+
+// Record implements TranscriptSink.
+func (t *WriterTranscript) Record(entry *TranscriptEntry) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.encoder.Encode(entry)
+}
+
+// ReadTranscript decodes the entries written by a WriterTranscript, until the end of r.
+//
+// The entries are not verified, which VerifyTranscript can do.
+func ReadTranscript(r io.Reader) ([]*TranscriptEntry, error) {
+	decoder := cbor.NewDecoder(r)
+	var entries []*TranscriptEntry
+	for {
+		entry := &TranscriptEntry{}
+		err := decoder.Decode(entry)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("protocol: transcript: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+}
@@ -0,0 +1,54 @@
+package protocol_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+func TestTranscript(t *testing.T) {
+	memory := &protocol.MemoryTranscript{}
+	var archive bytes.Buffer
+	runKeygen(t, party.IDSlice{"a", "b"}, protocol.WithTranscript(memory))
+
+	entries := memory.Entries()
+	require.NotEmpty(t, entries)
+	// both handlers record into memory, so we check the transcript of a alone
+	var transcript []*protocol.TranscriptEntry
+	inbound, outbound := 0, 0
+	for _, e := range entries {
+		if (e.Outbound && e.Message.From == "a") || (!e.Outbound && e.Message.From == "b") {
+			transcript = append(transcript, e)
+			if e.Outbound {
+				outbound++
+			} else {
+				inbound++
+			}
+		}
+	}
+	assert.NotZero(t, inbound)
+	assert.NotZero(t, outbound)
+	require.NoError(t, protocol.VerifyTranscript(transcript))
+
+	sink := protocol.NewWriterTranscript(&archive)
+	for _, e := range transcript {
+		require.NoError(t, sink.Record(e))
+	}
+	decoded, err := protocol.ReadTranscript(&archive)
+	require.NoError(t, err)
+	require.Len(t, decoded, len(transcript))
+	require.NoError(t, protocol.VerifyTranscript(decoded))
+
+	// dropping, tampering with, or reordering entries is detected
+	assert.Error(t, protocol.VerifyTranscript(decoded[1:]))
+	decoded[1], decoded[2] = decoded[2], decoded[1]
+	assert.Error(t, protocol.VerifyTranscript(decoded))
+	decoded[1], decoded[2] = decoded[2], decoded[1]
+	decoded[1].Message.Data = append([]byte{}, decoded[1].Message.Data...)
+	decoded[1].Message.Data[0] ^= 1
+	assert.Error(t, protocol.VerifyTranscript(decoded))
+}
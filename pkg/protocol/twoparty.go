@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -18,6 +19,8 @@ type TwoPartyHandler struct {
 	result   interface{}
 	messages map[round.Number]*Message
 	out      chan *Message
+	// finished is closed once out is closed
+	finished chan struct{}
 	mtx      sync.Mutex
 }
 
@@ -33,6 +36,7 @@ func NewTwoPartyHandler(create StartFunc, sessionID []byte, leader bool) (*TwoPa
 		result:   nil,
 		messages: map[round.Number]*Message{},
 		out:      make(chan *Message, 2),
+		finished: make(chan struct{}),
 		mtx:      sync.Mutex{},
 	}
 	if leader {
@@ -41,6 +45,23 @@ func NewTwoPartyHandler(create StartFunc, sessionID []byte, leader bool) (*TwoPa
 	return handler, nil
 }
 
+// NewTwoPartyHandlerContext is like NewTwoPartyHandler, but aborts the protocol once ctx is done,
+// with ctx.Err() as the error, closing the channel returned by Listen.
+func NewTwoPartyHandlerContext(ctx context.Context, create StartFunc, sessionID []byte, leader bool) (*TwoPartyHandler, error) {
+	h, err := NewTwoPartyHandler(create, sessionID, leader)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			h.cancel(ctx.Err())
+		case <-h.finished:
+		}
+	}()
+	return h, nil
+}
+
 func (h *TwoPartyHandler) Result() (interface{}, error) {
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
@@ -60,8 +81,15 @@ func (h *TwoPartyHandler) Listen() <-chan *Message {
 }
 
 func (h *TwoPartyHandler) Stop() {
-	if h.err != nil || h.result != nil {
-		h.abort(errors.New("aborted by user"))
+	h.cancel(errors.New("aborted by user"))
+}
+
+// cancel aborts the protocol with err if it is still running.
+func (h *TwoPartyHandler) cancel(err error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.err == nil && h.result == nil {
+		h.abort(err)
 	}
 }
 
@@ -74,6 +102,7 @@ func (h *TwoPartyHandler) abort(err error) {
 		h.err = err
 		select {
 		case h.out <- &Message{
+			Version:  WireVersion,
 			SSID:     h.round.SSID(),
 			From:     h.round.SelfID(),
 			Protocol: h.round.ProtocolID(),
@@ -83,6 +112,7 @@ func (h *TwoPartyHandler) abort(err error) {
 		}
 	}
 	close(h.out)
+	close(h.finished)
 }
 
 func (h *TwoPartyHandler) canAdvance() bool {
@@ -98,7 +128,7 @@ func (h *TwoPartyHandler) canAdvance() bool {
 func extractRoundMessage(r round.Session, msg *Message) (round.Message, error) {
 	content := r.MessageContent()
 	if err := cbor.Unmarshal(msg.Data, content); err != nil {
-		return round.Message{}, fmt.Errorf("failed to unmarshal message: %w", err)
+		return round.Message{}, fmt.Errorf("%w: %w", ErrDecode, err)
 	}
 	roundMsg := round.Message{
 		From:      msg.From,
@@ -150,6 +180,7 @@ func (h *TwoPartyHandler) advance() {
 				panic(fmt.Errorf("failed to marshal round message: %w", err))
 			}
 			msg := &Message{
+				Version:               WireVersion,
 				SSID:                  newRound.SSID(),
 				From:                  newRound.SelfID(),
 				To:                    roundMsg.To,
@@ -178,6 +209,13 @@ func (h *TwoPartyHandler) advance() {
 }
 
 func (h *TwoPartyHandler) CanAccept(msg *Message) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.canAccept(msg)
+}
+
+// canAccept implements CanAccept. It must be called with mtx held.
+func (h *TwoPartyHandler) canAccept(msg *Message) bool {
 	r := h.round
 	if msg == nil {
 		return false
@@ -207,12 +245,17 @@ func (h *TwoPartyHandler) Accept(msg *Message) {
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
 
-	if !h.CanAccept(msg) || h.err != nil || h.result != nil {
+	if !h.canAccept(msg) || h.err != nil || h.result != nil {
+		return
+	}
+
+	if err := checkVersion(msg); err != nil {
+		h.abort(err)
 		return
 	}
 
 	if msg.RoundNumber == 0 {
-		h.abort(fmt.Errorf("aborted by other party with error: \"%s\"", msg.Data))
+		h.abort(fmt.Errorf("%w: \"%s\"", ErrAbortedByPeer, msg.Data))
 		return
 	}
 
@@ -0,0 +1,136 @@
+package protocol
+
+import (
+	"sync"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// Stall records a session which expired while waiting on a party's message.
+type Stall struct {
+	// SSID identifies the session which stalled.
+	SSID []byte
+	// Protocol is the ID of the protocol the session was running.
+	Protocol string
+	// Round is the number of the round for which the party's message was due.
+	Round round.Number
+}
+
+// StallAlert is raised by a Watchdog when a party has stalled too many consecutive sessions.
+type StallAlert struct {
+	// Party is the participant which withheld its messages.
+	Party party.ID
+	// Stalls contains the sessions stalled by Party since it last completed a session, oldest first.
+	Stalls []Stall
+}
+
+// AlertCallback is called by a Watchdog when it raises a StallAlert.
+type AlertCallback func(alert *StallAlert)
+
+// Watchdog correlates the sessions of a Multiplexer which expire with those which complete,
+// in order to detect parties which withhold their messages.
+//
+// Such a party never sends an invalid message, so it can't be identified by a protocol.Error alone,
+// but it appears in ExpiredSession.Missing every time it stalls a session.
+// Each party's count is reset once a session it took part in completes, and a StallAlert is raised
+// for every stall once the count reaches the threshold.
+type Watchdog struct {
+	threshold int
+	protocols map[string]bool
+	stalls    map[party.ID][]Stall
+	callbacks []AlertCallback
+	mtx       sync.Mutex
+}
+
+// NewWatchdog returns a Watchdog which raises an alert when a party stalls threshold consecutive sessions.
+//
+// If protocols are given, only sessions running a protocol with one of these IDs are taken into account,
+// for example "cmp/sign" to only monitor CMP signing sessions.
+func NewWatchdog(threshold int, protocols ...string) *Watchdog {
+	if threshold < 1 {
+		threshold = 1
+	}
+	w := &Watchdog{
+		threshold: threshold,
+		stalls:    map[party.ID][]Stall{},
+	}
+	if len(protocols) > 0 {
+		w.protocols = make(map[string]bool, len(protocols))
+		for _, p := range protocols {
+			w.protocols[p] = true
+		}
+	}
+	return w
+}
+
+// Watch registers the Watchdog's callbacks with m, so that it monitors the sessions m expires and completes.
+//
+// Parties are only identified by their ID, so a Watchdog should watch the Namespace of a single tenant,
+// rather than a Multiplexer shared by tenants whose parties may use the same IDs.
+func (w *Watchdog) Watch(m SessionNotifier) {
+	m.OnExpire(w.Expired)
+	m.OnComplete(w.Completed)
+}
+
+// OnAlert registers a callback, which will be called for every StallAlert raised from now on.
+func (w *Watchdog) OnAlert(callback AlertCallback) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.callbacks = append(w.callbacks, callback)
+}
+
+// Expired records a stall for each party the session was waiting on, and raises the resulting alerts.
+func (w *Watchdog) Expired(session *ExpiredSession) {
+	if !w.monitors(session.Protocol) {
+		return
+	}
+	stall := Stall{
+		SSID:     session.SSID,
+		Protocol: session.Protocol,
+		Round:    session.Round,
+	}
+
+	w.mtx.Lock()
+	var alerts []*StallAlert
+	for _, id := range session.Missing {
+		w.stalls[id] = append(w.stalls[id], stall)
+		if len(w.stalls[id]) >= w.threshold {
+			alerts = append(alerts, &StallAlert{
+				Party:  id,
+				Stalls: append([]Stall(nil), w.stalls[id]...),
+			})
+		}
+	}
+	callbacks := append([]AlertCallback(nil), w.callbacks...)
+	w.mtx.Unlock()
+
+	for _, alert := range alerts {
+		for _, callback := range callbacks {
+			callback(alert)
+		}
+	}
+}
+
+// Completed resets the count of every party which took part in the session.
+func (w *Watchdog) Completed(session *CompletedSession) {
+	if !w.monitors(session.Protocol) {
+		return
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	for _, id := range session.Parties {
+		delete(w.stalls, id)
+	}
+}
+
+// Stalls returns the sessions stalled by id since it last completed a session.
+func (w *Watchdog) Stalls(id party.ID) []Stall {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return append([]Stall(nil), w.stalls[id]...)
+}
+
+func (w *Watchdog) monitors(protocol string) bool {
+	return w.protocols == nil || w.protocols[protocol]
+}
@@ -0,0 +1,160 @@
+package random
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+)
+
+const (
+	// drbgEntropyBytes is the length of the entropy input, which is the security strength of HMAC_DRBG with SHA-256.
+	drbgEntropyBytes = 32
+	// drbgNonceBytes is the length of the nonce, half of the security strength.
+	drbgNonceBytes = 16
+	// drbgMaxRequest is the maximum number of bytes of a single request to the generate function, 2¹⁹ bits.
+	drbgMaxRequest = 1 << 16
+	// drbgReseedInterval is the number of requests after which new entropy is drawn.
+	// SP 800-90A allows up to 2⁴⁸, we reseed much more often.
+	drbgReseedInterval = 1 << 20
+)
+
+// ErrDRBGEntropy is returned by HMACDRBG when it fails to read entropy.
+var ErrDRBGEntropy = errors.New("random: failed to read entropy for the DRBG")
+
+// HMACDRBG is the HMAC_DRBG of NIST SP 800-90A, Section 10.1.2, instantiated with SHA-256,
+// without prediction resistance.
+//
+// It is seeded, and periodically reseeded, from an entropy source, and is safe for concurrent use,
+// so that it can be passed to SetReader.
+type HMACDRBG struct {
+	mu      sync.Mutex
+	entropy io.Reader
+	k, v    []byte
+	mac     hash.Hash
+	// counter is the number of requests since the last time the DRBG was (re)seeded.
+	counter uint64
+}
+
+// NewHMACDRBG instantiates an HMACDRBG, reading the entropy input and the nonce from entropy,
+// which is read again for each reseed.
+//
+// personalization is optional, and should identify the application or the device,
+// to differentiate instances seeded from the same source.
+func NewHMACDRBG(entropy io.Reader, personalization []byte) (*HMACDRBG, error) {
+	seed := make([]byte, drbgEntropyBytes+drbgNonceBytes)
+	if _, err := io.ReadFull(entropy, seed); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDRBGEntropy, err)
+	}
+	d := newHMACDRBG(seed[:drbgEntropyBytes], seed[drbgEntropyBytes:], personalization)
+	d.entropy = entropy
+	return d, nil
+}
+
+// newHMACDRBG implements the instantiate function, with explicit inputs.
+func newHMACDRBG(entropy, nonce, personalization []byte) *HMACDRBG {
+	d := &HMACDRBG{
+		k: make([]byte, sha256.Size),
+		v: make([]byte, sha256.Size),
+	}
+	for i := range d.v {
+		d.v[i] = 0x01
+	}
+	d.mac = hmac.New(sha256.New, d.k)
+	d.update(entropy, nonce, personalization)
+	d.counter = 1
+	return d
+}
+
+// update implements HMAC_DRBG_Update, where the provided data is the concatenation of data.
+func (d *HMACDRBG) update(data ...[]byte) {
+	empty := true
+	for _, b := range data {
+		empty = empty && len(b) == 0
+	}
+	for _, separator := range []byte{0x00, 0x01} {
+		if separator == 0x01 && empty {
+			return
+		}
+		// K = HMAC(K, V || separator || data)
+		d.mac.Reset()
+		_, _ = d.mac.Write(d.v)
+		_, _ = d.mac.Write([]byte{separator})
+		for _, b := range data {
+			_, _ = d.mac.Write(b)
+		}
+		d.k = d.mac.Sum(d.k[:0])
+		d.mac = hmac.New(sha256.New, d.k)
+		// V = HMAC(K, V)
+		d.next()
+	}
+}
+
+// next sets V = HMAC(K, V).
+func (d *HMACDRBG) next() {
+	d.mac.Reset()
+	_, _ = d.mac.Write(d.v)
+	d.v = d.mac.Sum(d.v[:0])
+}
+
+// Reseed mixes new entropy, read from the entropy source, and the optional additional input, into the state.
+func (d *HMACDRBG) Reseed(additional []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.reseed(additional)
+}
+
+func (d *HMACDRBG) reseed(additional []byte) error {
+	if d.entropy == nil {
+		return ErrDRBGEntropy
+	}
+	entropy := make([]byte, drbgEntropyBytes)
+	if _, err := io.ReadFull(d.entropy, entropy); err != nil {
+		return fmt.Errorf("%w: %v", ErrDRBGEntropy, err)
+	}
+	d.update(entropy, additional)
+	d.counter = 1
+	return nil
+}
+
+// generate implements the generate function, for a request of at most drbgMaxRequest bytes.
+func (d *HMACDRBG) generate(out, additional []byte) error {
+	if d.counter > drbgReseedInterval {
+		if err := d.reseed(additional); err != nil {
+			return err
+		}
+		additional = nil
+	}
+	if len(additional) > 0 {
+		d.update(additional)
+	}
+	for n := 0; n < len(out); {
+		d.next()
+		n += copy(out[n:], d.v)
+	}
+	d.update(additional)
+	d.counter++
+	return nil
+}
+
+// Read fills b with pseudo random bytes, in requests of at most 2¹⁶ bytes, implementing io.Reader.
+//
+// It returns an error only if the DRBG needs to be reseeded, and its entropy source fails,
+// in which case only the first n bytes of b were filled.
+func (d *HMACDRBG) Read(b []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for n := 0; n < len(b); n += drbgMaxRequest {
+		end := n + drbgMaxRequest
+		if end > len(b) {
+			end = len(b)
+		}
+		if err := d.generate(b[n:end], nil); err != nil {
+			return n, err
+		}
+	}
+	return len(b), nil
+}
@@ -0,0 +1,72 @@
+package random
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	source "github.com/taurusgroup/multi-party-sig/internal/random"
+)
+
+func TestHMACDRBGRFC6979(t *testing.T) {
+	// The nonces of RFC 6979 are the first output of an HMAC_DRBG, instantiated with the key as entropy,
+	// and the hash as nonce, as long as they are lower than the order of the curve.
+	for _, msg := range []string{"sample", "test"} {
+		key := sha256.Sum256([]byte("key " + msg))
+		hash := sha256.Sum256([]byte(msg))
+
+		d := newHMACDRBG(key[:], hash[:], nil)
+		k := make([]byte, 32)
+		_, err := d.Read(k)
+		require.NoError(t, err)
+
+		expected := secp256k1.NonceRFC6979(key[:], hash[:], nil, nil, 0).Bytes()
+		assert.Equal(t, expected[:], k, msg)
+	}
+}
+
+func TestHMACDRBG(t *testing.T) {
+	seed := make([]byte, 2*drbgEntropyBytes+drbgNonceBytes)
+	_, _ = rand.Read(seed)
+
+	a, err := NewHMACDRBG(bytes.NewReader(seed), []byte("a"))
+	require.NoError(t, err)
+	b, err := NewHMACDRBG(bytes.NewReader(seed), []byte("a"))
+	require.NoError(t, err)
+	c, err := NewHMACDRBG(bytes.NewReader(seed), []byte("c"))
+	require.NoError(t, err)
+
+	// requests larger than the maximum are split
+	outA, outB, outC := make([]byte, 3*drbgMaxRequest), make([]byte, 3*drbgMaxRequest), make([]byte, 3*drbgMaxRequest)
+	for d, out := range map[*HMACDRBG][]byte{a: outA, b: outB, c: outC} {
+		n, err := d.Read(out)
+		require.NoError(t, err)
+		assert.Equal(t, len(out), n)
+	}
+	assert.Equal(t, outA, outB)
+	assert.NotEqual(t, outA, outC)
+
+	require.NoError(t, a.Reseed(nil))
+	_, _ = a.Read(outA)
+	_, _ = b.Read(outB)
+	assert.NotEqual(t, outA, outB)
+
+	// the entropy source is exhausted
+	d, err := NewHMACDRBG(bytes.NewReader(seed[:drbgEntropyBytes+drbgNonceBytes]), nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, d.Reseed(nil), ErrDRBGEntropy)
+}
+
+func TestSetReader(t *testing.T) {
+	d, err := NewHMACDRBG(rand.Reader, nil)
+	require.NoError(t, err)
+	SetReader(d)
+	defer SetReader(nil)
+	assert.Equal(t, d, source.Reader())
+	SetReader(nil)
+	assert.Equal(t, rand.Reader, Reader())
+}
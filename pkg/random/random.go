@@ -0,0 +1,33 @@
+// Package random lets applications choose the source of randomness of the protocols in this library.
+//
+// By default, every secret, nonce and proof is sampled from crypto/rand.Reader.
+// Deployments which must use another generator, such as one backed by an HSM,
+// or a DRBG from NIST SP 800-90A like HMACDRBG, can install it with SetReader,
+// when the application starts and before any protocol is executed.
+package random
+
+import (
+	"crypto/rand"
+	"io"
+
+	source "github.com/taurusgroup/multi-party-sig/internal/random"
+)
+
+// SetReader makes all the protocols, and the functions of this library sampling secrets,
+// read their randomness from r, instead of crypto/rand.Reader.
+//
+// The setting is global to the process. r must be safe for concurrent use,
+// since concurrent sessions read from it at the same time,
+// and it must return an error rather than fewer bytes than requested when it fails.
+// A nil r restores crypto/rand.Reader.
+func SetReader(r io.Reader) {
+	if r == nil {
+		r = rand.Reader
+	}
+	source.Set(r)
+}
+
+// Reader returns the source of randomness currently used by the protocols.
+func Reader() io.Reader {
+	return source.Reader()
+}
@@ -0,0 +1,66 @@
+package taproot
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// VerifyBatch checks that sigs[i] is a valid signature of msgs[i] by pubs[i], for every i.
+//
+// This is faster than calling Verify on each signature, and returns true exactly when every call would,
+// except with negligible probability. When it returns false, Verify can be used to find the invalid signatures.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki#batch-verification
+func VerifyBatch(pubs []PublicKey, msgs [][]byte, sigs []Signature) bool {
+	n := len(sigs)
+	if len(pubs) != n || len(msgs) != n {
+		return false
+	}
+	if n == 0 {
+		return true
+	}
+
+	// We check that (Σ aᵢsᵢ)G = Σ aᵢRᵢ + Σ aᵢeᵢPᵢ, for a₁ = 1 and random 128 bit aᵢ.
+	scalars := make([]*curve.Secp256k1Scalar, 0, 2*n)
+	points := make([]*curve.Secp256k1Point, 0, 2*n)
+	sum := new(curve.Secp256k1Scalar)
+	seed := make([]byte, 32)
+	for i := 0; i < n; i++ {
+		sig, pk := sigs[i], pubs[i]
+		if len(sig) != SignatureLen || len(pk) != 32 {
+			return false
+		}
+		P, err := curve.Secp256k1{}.LiftX(pk)
+		if err != nil {
+			return false
+		}
+		R, err := curve.Secp256k1{}.LiftX(sig[:32])
+		if err != nil {
+			return false
+		}
+		s := new(curve.Secp256k1Scalar)
+		if err = s.UnmarshalBinary(sig[32:]); err != nil {
+			return false
+		}
+		e := new(curve.Secp256k1Scalar)
+		_ = e.UnmarshalBinary(TaggedHash("BIP0340/challenge", sig[:32], pk, msgs[i]))
+
+		a := new(curve.Secp256k1Scalar)
+		if i == 0 {
+			_ = a.UnmarshalBinary(append(make([]byte, 31), 1))
+		} else {
+			for a.IsZero() {
+				if _, err = random.Read(seed[16:]); err != nil {
+					return false
+				}
+				_ = a.UnmarshalBinary(seed)
+			}
+		}
+
+		sum.Add(s.Mul(a))
+		scalars = append(scalars, a, e.Mul(a).(*curve.Secp256k1Scalar))
+		points = append(points, R, P)
+	}
+
+	return sum.ActOnBase().Equal(curve.Secp256k1MultiScalarMult(scalars, points))
+}
@@ -0,0 +1,323 @@
+package taproot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SigHashType selects the parts of a transaction a signature commits to.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#common-signature-message
+type SigHashType byte
+
+const (
+	// SigHashDefault commits to the whole transaction, like SigHashAll, and is omitted from the signature.
+	SigHashDefault SigHashType = 0x00
+	SigHashAll     SigHashType = 0x01
+	SigHashNone    SigHashType = 0x02
+	SigHashSingle  SigHashType = 0x03
+	// SigHashAnyoneCanPay can be combined with SigHashAll, SigHashNone and SigHashSingle,
+	// to only commit to the input being signed.
+	SigHashAnyoneCanPay SigHashType = 0x80
+)
+
+func (t SigHashType) valid() bool {
+	switch t &^ SigHashAnyoneCanPay {
+	case SigHashAll, SigHashNone, SigHashSingle:
+		return true
+	}
+	return t == SigHashDefault
+}
+
+// Transaction is a Bitcoin transaction, as needed to compute signature hashes.
+type Transaction struct {
+	Version  uint32
+	Inputs   []TxInput
+	Outputs  []TxOutput
+	LockTime uint32
+}
+
+// TxInput is an input of a Transaction.
+type TxInput struct {
+	// PrevTxID is the ID of the transaction of the output being spent, in its serialized byte order,
+	// which is the reverse of the usual hexadecimal representation.
+	PrevTxID [32]byte
+	// PrevIndex is the index of the output being spent in its transaction.
+	PrevIndex uint32
+	ScriptSig []byte
+	Sequence  uint32
+	Witness   [][]byte
+}
+
+// TxOutput is an output of a Transaction, and the description of the output spent by an input.
+type TxOutput struct {
+	// Amount is in satoshis.
+	Amount   uint64
+	PkScript []byte
+}
+
+// ParseTransaction decodes a transaction in the serialization of the Bitcoin network, with or without witnesses.
+func ParseTransaction(raw []byte) (*Transaction, error) {
+	r := bytes.NewReader(raw)
+	tx := &Transaction{}
+	var err error
+	if tx.Version, err = readUint32(r); err != nil {
+		return nil, fmt.Errorf("taproot: transaction: %w", err)
+	}
+
+	// the segwit serialization has a marker 0x00 where the number of inputs would be, followed by the flag 0x01.
+	segwit := len(raw) > 6 && raw[4] == 0x00 && raw[5] == 0x01
+	if segwit {
+		_, _ = r.Seek(2, io.SeekCurrent)
+	}
+
+	inputs, err := readCount(r)
+	if err != nil {
+		return nil, fmt.Errorf("taproot: transaction: %w", err)
+	}
+	tx.Inputs = make([]TxInput, inputs)
+	for i := range tx.Inputs {
+		in := &tx.Inputs[i]
+		if _, err = io.ReadFull(r, in.PrevTxID[:]); err != nil {
+			return nil, fmt.Errorf("taproot: transaction: input %d: %w", i, err)
+		}
+		if in.PrevIndex, err = readUint32(r); err != nil {
+			return nil, fmt.Errorf("taproot: transaction: input %d: %w", i, err)
+		}
+		if in.ScriptSig, err = readBytes(r); err != nil {
+			return nil, fmt.Errorf("taproot: transaction: input %d: %w", i, err)
+		}
+		if in.Sequence, err = readUint32(r); err != nil {
+			return nil, fmt.Errorf("taproot: transaction: input %d: %w", i, err)
+		}
+	}
+
+	outputs, err := readCount(r)
+	if err != nil {
+		return nil, fmt.Errorf("taproot: transaction: %w", err)
+	}
+	tx.Outputs = make([]TxOutput, outputs)
+	for i := range tx.Outputs {
+		out := &tx.Outputs[i]
+		var amount [8]byte
+		if _, err = io.ReadFull(r, amount[:]); err != nil {
+			return nil, fmt.Errorf("taproot: transaction: output %d: %w", i, err)
+		}
+		out.Amount = binary.LittleEndian.Uint64(amount[:])
+		if out.PkScript, err = readBytes(r); err != nil {
+			return nil, fmt.Errorf("taproot: transaction: output %d: %w", i, err)
+		}
+	}
+
+	if segwit {
+		for i := range tx.Inputs {
+			items, err := readCount(r)
+			if err != nil {
+				return nil, fmt.Errorf("taproot: transaction: witness %d: %w", i, err)
+			}
+			for j := uint64(0); j < items; j++ {
+				item, err := readBytes(r)
+				if err != nil {
+					return nil, fmt.Errorf("taproot: transaction: witness %d: %w", i, err)
+				}
+				tx.Inputs[i].Witness = append(tx.Inputs[i].Witness, item)
+			}
+		}
+	}
+
+	if tx.LockTime, err = readUint32(r); err != nil {
+		return nil, fmt.Errorf("taproot: transaction: %w", err)
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("taproot: transaction: trailing bytes")
+	}
+	return tx, nil
+}
+
+// SigHash computes the hash signed by a key path spend of the given input, as specified by BIP-341.
+//
+// prevouts are the outputs spent by each of the inputs of tx, in order, since the signature commits to
+// their amounts and scripts, which are not part of the transaction itself.
+// The result is the messageHash to give to the signing protocol. For any hashType other than SigHashDefault,
+// the hashType byte must then be appended to the signature.
+//
+// Spends with an annex are not supported.
+func (tx *Transaction) SigHash(input int, prevouts []TxOutput, hashType SigHashType) ([]byte, error) {
+	if !hashType.valid() {
+		return nil, fmt.Errorf("taproot: invalid sighash type 0x%02x", byte(hashType))
+	}
+	if input < 0 || input >= len(tx.Inputs) {
+		return nil, fmt.Errorf("taproot: input %d out of range", input)
+	}
+	if len(prevouts) != len(tx.Inputs) {
+		return nil, fmt.Errorf("taproot: got %d prevouts for %d inputs", len(prevouts), len(tx.Inputs))
+	}
+	base := hashType &^ SigHashAnyoneCanPay
+	anyoneCanPay := hashType&SigHashAnyoneCanPay != 0
+	if base == SigHashSingle && input >= len(tx.Outputs) {
+		return nil, fmt.Errorf("taproot: SIGHASH_SINGLE without output for input %d", input)
+	}
+
+	// The signature message is prefixed with the epoch 0x00.
+	var msg bytes.Buffer
+	msg.WriteByte(0x00)
+	msg.WriteByte(byte(hashType))
+	writeUint32(&msg, tx.Version)
+	writeUint32(&msg, tx.LockTime)
+
+	if !anyoneCanPay {
+		var outpoints, amounts, scripts, sequences bytes.Buffer
+		for i, in := range tx.Inputs {
+			writeOutpoint(&outpoints, in)
+			writeUint64(&amounts, prevouts[i].Amount)
+			writeBytes(&scripts, prevouts[i].PkScript)
+			writeUint32(&sequences, in.Sequence)
+		}
+		msg.Write(sha256Sum(outpoints.Bytes()))
+		msg.Write(sha256Sum(amounts.Bytes()))
+		msg.Write(sha256Sum(scripts.Bytes()))
+		msg.Write(sha256Sum(sequences.Bytes()))
+	}
+	if base != SigHashNone && base != SigHashSingle {
+		var outputs bytes.Buffer
+		for _, out := range tx.Outputs {
+			writeOutput(&outputs, out)
+		}
+		msg.Write(sha256Sum(outputs.Bytes()))
+	}
+
+	// spend_type is 0: key path spend, without annex.
+	msg.WriteByte(0x00)
+	if anyoneCanPay {
+		writeOutpoint(&msg, tx.Inputs[input])
+		writeUint64(&msg, prevouts[input].Amount)
+		writeBytes(&msg, prevouts[input].PkScript)
+		writeUint32(&msg, tx.Inputs[input].Sequence)
+	} else {
+		writeUint32(&msg, uint32(input))
+	}
+	if base == SigHashSingle {
+		var output bytes.Buffer
+		writeOutput(&output, tx.Outputs[input])
+		msg.Write(sha256Sum(output.Bytes()))
+	}
+
+	return TaggedHash("TapSighash", msg.Bytes()), nil
+}
+
+// KeySpendSigHash parses rawTx, and computes the SigHashDefault hash signed by a key path spend of the given input.
+func KeySpendSigHash(rawTx []byte, input int, prevouts []TxOutput) ([]byte, error) {
+	tx, err := ParseTransaction(rawTx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.SigHash(input, prevouts, SigHashDefault)
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func writeOutpoint(w *bytes.Buffer, in TxInput) {
+	w.Write(in.PrevTxID[:])
+	writeUint32(w, in.PrevIndex)
+}
+
+func writeOutput(w *bytes.Buffer, out TxOutput) {
+	writeUint64(w, out.Amount)
+	writeBytes(w, out.PkScript)
+}
+
+func writeUint32(w *bytes.Buffer, v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	w.Write(b[:])
+}
+
+func writeUint64(w *bytes.Buffer, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	w.Write(b[:])
+}
+
+// writeBytes writes data prefixed by its length, as a CompactSize.
+func writeBytes(w *bytes.Buffer, data []byte) {
+	n := uint64(len(data))
+	switch {
+	case n < 0xfd:
+		w.WriteByte(byte(n))
+	case n <= 0xffff:
+		w.WriteByte(0xfd)
+		var b [2]byte
+		binary.LittleEndian.PutUint16(b[:], uint16(n))
+		w.Write(b[:])
+	case n <= 0xffffffff:
+		w.WriteByte(0xfe)
+		writeUint32(w, uint32(n))
+	default:
+		w.WriteByte(0xff)
+		writeUint64(w, n)
+	}
+	w.Write(data)
+}
+
+func readUint32(r *bytes.Reader) (uint32, error) {
+	var b [4]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b[:]), nil
+}
+
+// readCount reads a CompactSize, and checks that it isn't larger than the remaining bytes,
+// since every counted item takes at least one byte.
+func readCount(r *bytes.Reader) (uint64, error) {
+	prefix, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	switch prefix {
+	case 0xfd:
+		var b [2]byte
+		if _, err = io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		n = uint64(binary.LittleEndian.Uint16(b[:]))
+	case 0xfe:
+		v, err := readUint32(r)
+		if err != nil {
+			return 0, err
+		}
+		n = uint64(v)
+	case 0xff:
+		var b [8]byte
+		if _, err = io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		n = binary.LittleEndian.Uint64(b[:])
+	default:
+		n = uint64(prefix)
+	}
+	if n > uint64(r.Len()) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return n, nil
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := readCount(r)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, n)
+	if _, err = io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -3,6 +3,7 @@ package taproot
 import (
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -27,3 +28,157 @@ func TestSignatureVerification(t *testing.T) {
 	}
 
 }
+
+func TestOutputKey(t *testing.T) {
+	// The key path only output of the BIP-341 wallet test vectors.
+	internal, _ := hex.DecodeString("d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d")
+	tweak, err := TapTweak(internal, nil)
+	require.NoError(t, err)
+	tweakBytes, _ := tweak.MarshalBinary()
+	require.Equal(t, "b86e7be8f39bab32a6f2c0443abbc210f0edac0e2c53d501b36b64437d9c6c70", hex.EncodeToString(tweakBytes))
+	output, _, err := PublicKey(internal).OutputKey(nil)
+	require.NoError(t, err)
+	require.Equal(t, "53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343", hex.EncodeToString(output))
+
+	// An output with a single script leaf, from the same vectors.
+	internal, _ = hex.DecodeString("187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27")
+	merkleRoot, _ := hex.DecodeString("5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21")
+	output, _, err = PublicKey(internal).OutputKey(merkleRoot)
+	require.NoError(t, err)
+	require.Equal(t, "147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3", hex.EncodeToString(output))
+
+	_, err = TapTweak(internal, []byte{1, 2, 3})
+	require.Error(t, err, "merkle root must be empty or 32 bytes")
+}
+
+func TestSigHash(t *testing.T) {
+	// A segwit transaction with two inputs, the first with a witness, and two outputs.
+	raw, _ := hex.DecodeString("02000000" + "0001" + "02" +
+		"1111111111111111111111111111111111111111111111111111111111111111" + "00000000" + "00" + "fdffffff" +
+		"2222222222222222222222222222222222222222222222222222222222222222" + "01000000" + "00" + "ffffffff" +
+		"02" +
+		"e803000000000000" + "03" + "6a0101" +
+		"d007000000000000" + "02" + "5100" +
+		"01" + "40" + "3333333333333333333333333333333333333333333333333333333333333333" +
+		"3333333333333333333333333333333333333333333333333333333333333333" +
+		"00" +
+		"07000000")
+	tx, err := ParseTransaction(raw)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), tx.Version)
+	require.Len(t, tx.Inputs, 2)
+	require.Equal(t, uint32(1), tx.Inputs[1].PrevIndex)
+	require.Equal(t, uint32(0xfffffffd), tx.Inputs[0].Sequence)
+	require.Len(t, tx.Inputs[0].Witness, 1)
+	require.Len(t, tx.Inputs[1].Witness, 0)
+	require.Len(t, tx.Outputs, 2)
+	require.Equal(t, uint64(2000), tx.Outputs[1].Amount)
+	require.Equal(t, uint32(7), tx.LockTime)
+
+	_, err = ParseTransaction(raw[:len(raw)-1])
+	require.Error(t, err, "truncated transaction")
+	_, err = ParseTransaction(append(raw, 0))
+	require.Error(t, err, "trailing bytes")
+
+	sk, pk, err := GenKey(rand.Reader)
+	require.NoError(t, err)
+	prevouts := []TxOutput{
+		{Amount: 5000, PkScript: append([]byte{0x51, 0x20}, pk...)},
+		{Amount: 6000, PkScript: []byte{0x51, 0x20, 0x01}},
+	}
+
+	hash, err := KeySpendSigHash(raw, 0, prevouts)
+	require.NoError(t, err)
+	require.Len(t, hash, 32)
+	sig, err := sk.Sign(rand.Reader, hash)
+	require.NoError(t, err)
+	require.True(t, pk.Verify(sig, hash))
+
+	hashAll, err := tx.SigHash(0, prevouts, SigHashAll)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, hashAll, "the hash type is part of the message")
+	hash1, err := tx.SigHash(1, prevouts, SigHashDefault)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, hash1, "the input index is part of the message")
+
+	// Changing the amount of another input only changes the hash without ANYONECANPAY.
+	otherPrevouts := []TxOutput{prevouts[0], {Amount: 6001, PkScript: prevouts[1].PkScript}}
+	for _, hashType := range []SigHashType{SigHashDefault, SigHashAll | SigHashAnyoneCanPay} {
+		h1, err := tx.SigHash(0, prevouts, hashType)
+		require.NoError(t, err)
+		h2, err := tx.SigHash(0, otherPrevouts, hashType)
+		require.NoError(t, err)
+		require.Equal(t, hashType&SigHashAnyoneCanPay != 0, string(h1) == string(h2))
+	}
+
+	// Changing the outputs doesn't change the hash with SIGHASH_NONE.
+	otherTx := *tx
+	otherTx.Outputs = []TxOutput{{Amount: 1, PkScript: []byte{0x51}}}
+	hNone1, err := tx.SigHash(0, prevouts, SigHashNone)
+	require.NoError(t, err)
+	hNone2, err := otherTx.SigHash(0, prevouts, SigHashNone)
+	require.NoError(t, err)
+	require.Equal(t, hNone1, hNone2)
+	_, err = otherTx.SigHash(1, prevouts, SigHashSingle)
+	require.Error(t, err, "SIGHASH_SINGLE needs a matching output")
+
+	_, err = tx.SigHash(0, prevouts[:1], SigHashDefault)
+	require.Error(t, err, "missing prevout")
+	_, err = tx.SigHash(2, prevouts, SigHashDefault)
+	require.Error(t, err, "input out of range")
+	_, err = tx.SigHash(0, prevouts, SigHashType(0x04))
+	require.Error(t, err, "invalid hash type")
+}
+
+func TestVerifyBatch(t *testing.T) {
+	n := 8
+	pubs := make([]PublicKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([]Signature, n)
+	for i := 0; i < n; i++ {
+		sk, pk, err := GenKey(rand.Reader)
+		require.NoError(t, err)
+		m := sha256.Sum256([]byte{byte(i)})
+		sig, err := sk.Sign(rand.Reader, m[:])
+		require.NoError(t, err)
+		pubs[i], msgs[i], sigs[i] = pk, m[:], sig
+	}
+	require.True(t, VerifyBatch(pubs, msgs, sigs))
+	require.True(t, VerifyBatch(nil, nil, nil))
+	require.False(t, VerifyBatch(pubs, msgs, sigs[1:]))
+
+	// Swapping two messages invalidates both signatures.
+	msgs[2], msgs[3] = msgs[3], msgs[2]
+	require.False(t, VerifyBatch(pubs, msgs, sigs))
+	msgs[2], msgs[3] = msgs[3], msgs[2]
+
+	badSig := append(Signature{}, sigs[5]...)
+	badSig[63] ^= 1
+	sigs[5] = badSig
+	require.False(t, VerifyBatch(pubs, msgs, sigs))
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	n := 64
+	pubs := make([]PublicKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([]Signature, n)
+	for i := 0; i < n; i++ {
+		sk, pk, _ := GenKey(rand.Reader)
+		m := sha256.Sum256([]byte{byte(i)})
+		sigs[i], _ = sk.Sign(rand.Reader, m[:])
+		pubs[i], msgs[i] = pk, m[:]
+	}
+	b.Run("Verify", func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			for i := 0; i < n; i++ {
+				pubs[i].Verify(sigs[i], msgs[i])
+			}
+		}
+	})
+	b.Run("VerifyBatch", func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			VerifyBatch(pubs, msgs, sigs)
+		}
+	})
+}
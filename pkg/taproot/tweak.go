@@ -0,0 +1,44 @@
+package taproot
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// TapTweak computes the tweak t = hash_TapTweak(P ‖ merkleRoot) of BIP-341, for the internal key P.
+//
+// merkleRoot is the root of the script tree, or empty for an output without script path,
+// in which case the tweak of BIP-86 is obtained.
+//
+// See: https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#constructing-and-spending-taproot-outputs
+func TapTweak(internal PublicKey, merkleRoot []byte) (*curve.Secp256k1Scalar, error) {
+	if len(merkleRoot) != 0 && len(merkleRoot) != 32 {
+		return nil, fmt.Errorf("taproot: merkle root must have 0 or 32 bytes, found %d", len(merkleRoot))
+	}
+	t := new(curve.Secp256k1Scalar)
+	if err := t.UnmarshalBinary(TaggedHash("TapTweak", internal, merkleRoot)); err != nil {
+		return nil, fmt.Errorf("taproot: tweak: %w", err)
+	}
+	return t, nil
+}
+
+// OutputKey computes the output key Q = P + tG of BIP-341, for the internal key pk.
+//
+// It also returns whether Q has an odd y coordinate, which must be set in the control block
+// when spending the output through a script path.
+func (pk PublicKey) OutputKey(merkleRoot []byte) (PublicKey, bool, error) {
+	P, err := curve.Secp256k1{}.LiftX(pk)
+	if err != nil {
+		return nil, false, err
+	}
+	t, err := TapTweak(pk, merkleRoot)
+	if err != nil {
+		return nil, false, err
+	}
+	Q := P.Add(t.ActOnBase()).(*curve.Secp256k1Point)
+	if Q.IsIdentity() {
+		return nil, false, fmt.Errorf("taproot: output key is the identity")
+	}
+	return PublicKey(Q.XBytes()), !Q.HasEvenY(), nil
+}
@@ -0,0 +1,262 @@
+// Package testvector generates test vectors from executions of the protocols of this library,
+// so that other implementations can check that they interoperate with it.
+//
+// An execution is made deterministic by replacing the randomness of every party with a stream
+// derived from a seed, and by running the parties one after the other, in the order of their IDs.
+// The resulting Vector contains the content of every message exchanged, round by round,
+// and the output of every party, which another implementation should reproduce
+// when it is given the same inputs and random stream.
+//
+// The random stream is the output of the protocols' hash function (BLAKE3 by default),
+// in XOF mode, keyed by the label and the seed of the vector.
+// It is shared by all parties, which draw from it in turn.
+//
+// Since the randomness of the whole process is replaced while a vector is generated,
+// this must never be used in a process which also handles real keys.
+package testvector
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// Vector is the record of a deterministic execution of a protocol.
+//
+// Byte strings are hex encoded, and contents and outputs are encoded in deterministic CBOR,
+// as they would be by the handlers of this library.
+type Vector struct {
+	// Label identifies the execution, and is used along with Seed to derive the random stream.
+	Label string `json:"label"`
+	// Protocol is the ID of the protocol executed.
+	Protocol string `json:"protocol"`
+	// Seed is the seed of the random stream.
+	Seed string `json:"seed"`
+	// SessionID is the session ID given to the start functions, if any.
+	SessionID string `json:"session_id,omitempty"`
+	// SSID is the unique identifier of the session, which is the same for all parties.
+	SSID string `json:"ssid"`
+	// Parties is the sorted list of the parties, which is the order they were run in.
+	Parties []party.ID `json:"parties"`
+	// Threshold is the threshold of the key, if it applies to the protocol.
+	Threshold int `json:"threshold,omitempty"`
+	// Message is the message signed, if any.
+	Message string `json:"message,omitempty"`
+	// Inputs are the secret inputs of the parties, such as the configs used for signing, if any.
+	Inputs map[party.ID]string `json:"inputs,omitempty"`
+	// Rounds lists the messages of every round, in the order they were emitted.
+	Rounds []Round `json:"rounds"`
+	// Outputs are the results of the parties.
+	Outputs map[party.ID]string `json:"outputs"`
+
+	// Results are the decoded results of the parties, so that they can be used as inputs of another vector.
+	Results map[party.ID]interface{} `json:"-"`
+}
+
+// Round contains the messages received in a round.
+type Round struct {
+	Number   round.Number `json:"number"`
+	Messages []Message    `json:"messages"`
+}
+
+// Message is a message of a round.
+type Message struct {
+	From party.ID `json:"from"`
+	// To is empty if the message is sent to all parties.
+	To        party.ID `json:"to,omitempty"`
+	Broadcast bool     `json:"broadcast"`
+	Content   string   `json:"content"`
+}
+
+// encMode encodes maps in a fixed order, which vectors must not depend on.
+var encMode, _ = cbor.CoreDetEncOptions().EncMode()
+
+// Encode returns hex encoded deterministic CBOR of v, as used in vectors.
+func Encode(v interface{}) (string, error) {
+	data, err := encMode.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// Stream returns the random stream of a vector with the given label and seed.
+func Stream(label string, seed []byte) *pool.LockedReader {
+	return pool.NewLockedReader(hash.New(
+		hash.BytesWithDomain{TheDomain: "Test Vector Label", Bytes: []byte(label)},
+		hash.BytesWithDomain{TheDomain: "Test Vector Seed", Bytes: seed},
+	).Digest())
+}
+
+// overrideMtx prevents two vectors from being generated at the same time, since they would share the randomness.
+var overrideMtx sync.Mutex
+
+// Generate runs the protocol started by start for each party, and records its messages and outputs.
+//
+// Only one vector is generated at a time, and the protocols must be started without a pool.
+// The fields describing the inputs of the vector are left for the caller to set.
+func Generate(label string, seed, sessionID []byte, parties []party.ID, start func(id party.ID) protocol.StartFunc) (*Vector, error) {
+	if len(seed) == 0 {
+		return nil, errors.New("testvector: empty seed")
+	}
+	ids := party.NewIDSlice(parties)
+	if len(ids) == 0 {
+		return nil, errors.New("testvector: no parties")
+	}
+
+	overrideMtx.Lock()
+	defer overrideMtx.Unlock()
+	restore := random.Override(Stream(label, seed))
+	defer restore()
+
+	sessions := make([]round.Session, 0, len(ids))
+	for _, id := range ids {
+		s, err := start(id)(sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("testvector: party %s: %w", id, err)
+		}
+		sessions = append(sessions, s)
+	}
+
+	v := &Vector{
+		Label:     label,
+		Protocol:  sessions[0].ProtocolID(),
+		Seed:      hex.EncodeToString(seed),
+		SessionID: hex.EncodeToString(sessionID),
+		SSID:      hex.EncodeToString(sessions[0].SSID()),
+		Parties:   ids,
+	}
+	for {
+		done, err := v.finalize(sessions)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return v, nil
+		}
+	}
+}
+
+// finalize finalizes the current round of every session, in order, and delivers the messages they emit.
+// It returns true once every session has produced its output.
+func (v *Vector) finalize(sessions []round.Session) (bool, error) {
+	var (
+		messages []*round.Message
+		outputs  int
+	)
+	for i, s := range sessions {
+		out := make(chan *round.Message, 2*len(sessions))
+		next, err := s.Finalize(out)
+		close(out)
+		if err != nil {
+			return false, fmt.Errorf("testvector: party %s: %w", s.SelfID(), err)
+		}
+		for msg := range out {
+			messages = append(messages, msg)
+		}
+		switch r := next.(type) {
+		case *round.Abort:
+			return false, fmt.Errorf("testvector: party %s aborted: %w", s.SelfID(), r.Err)
+		case *round.Output:
+			outputs++
+		}
+		sessions[i] = next
+	}
+
+	if outputs > 0 {
+		if outputs != len(sessions) || len(messages) > 0 {
+			return false, errors.New("testvector: the parties didn't finish in the same round")
+		}
+		v.Outputs = make(map[party.ID]string, len(sessions))
+		v.Results = make(map[party.ID]interface{}, len(sessions))
+		for _, s := range sessions {
+			result := s.(*round.Output).Result
+			output, err := Encode(result)
+			if err != nil {
+				return false, fmt.Errorf("testvector: output of %s: %w", s.SelfID(), err)
+			}
+			v.Outputs[s.SelfID()] = output
+			v.Results[s.SelfID()] = result
+		}
+		return true, nil
+	}
+	if len(messages) == 0 {
+		return false, errors.New("testvector: round finalized without messages")
+	}
+
+	// broadcast messages must be stored before the others
+	recorded := Round{Number: messages[0].Content.RoundNumber()}
+	for _, broadcast := range []bool{true, false} {
+		for _, msg := range messages {
+			if msg.Broadcast != broadcast {
+				continue
+			}
+			content, err := Encode(msg.Content)
+			if err != nil {
+				return false, fmt.Errorf("testvector: message from %s: %w", msg.From, err)
+			}
+			recorded.Messages = append(recorded.Messages, Message{
+				From:      msg.From,
+				To:        msg.To,
+				Broadcast: msg.Broadcast,
+				Content:   content,
+			})
+			if err = deliver(sessions, msg); err != nil {
+				return false, err
+			}
+		}
+	}
+	v.Rounds = append(v.Rounds, recorded)
+	return false, nil
+}
+
+// deliver decodes msg for each of its recipients, and has their round verify and store it.
+func deliver(sessions []round.Session, msg *round.Message) error {
+	data, err := encMode.Marshal(msg.Content)
+	if err != nil {
+		return err
+	}
+	for _, r := range sessions {
+		if msg.From == r.SelfID() || (msg.To != "" && msg.To != r.SelfID()) {
+			continue
+		}
+		if msg.Content.RoundNumber() != r.Number() {
+			return fmt.Errorf("testvector: message from %s for round %d delivered in round %d", msg.From, msg.Content.RoundNumber(), r.Number())
+		}
+		m := *msg
+		if m.Broadcast {
+			b, ok := r.(round.BroadcastRound)
+			if !ok {
+				return fmt.Errorf("testvector: broadcast message from %s in round %d", msg.From, r.Number())
+			}
+			m.Content = b.BroadcastContent()
+			if err = cbor.Unmarshal(data, m.Content); err != nil {
+				return fmt.Errorf("testvector: message from %s: %w", msg.From, err)
+			}
+			if err = b.StoreBroadcastMessage(m); err != nil {
+				return fmt.Errorf("testvector: party %s rejected the message from %s: %w", r.SelfID(), msg.From, err)
+			}
+			continue
+		}
+		m.Content = r.MessageContent()
+		if err = cbor.Unmarshal(data, m.Content); err != nil {
+			return fmt.Errorf("testvector: message from %s: %w", msg.From, err)
+		}
+		if err = r.VerifyMessage(m); err != nil {
+			return fmt.Errorf("testvector: party %s rejected the message from %s: %w", r.SelfID(), msg.From, err)
+		}
+		if err = r.StoreMessage(m); err != nil {
+			return fmt.Errorf("testvector: party %s rejected the message from %s: %w", r.SelfID(), msg.From, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package testvector_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"github.com/taurusgroup/multi-party-sig/pkg/testvector"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func frostVectors(t *testing.T, seed []byte) (keygen, sign *testvector.Vector) {
+	ids := test.PartyIDs(3)
+	signers := ids[:2]
+	message := []byte("hello")
+
+	keygen, err := testvector.Generate("frost-taproot-keygen", seed, nil, ids, func(id party.ID) protocol.StartFunc {
+		return frost.KeygenTaproot(id, ids, 1)
+	})
+	require.NoError(t, err)
+
+	sign, err = testvector.Generate("frost-taproot-sign", seed, nil, signers, func(id party.ID) protocol.StartFunc {
+		return frost.SignTaproot(keygen.Results[id].(*frost.TaprootConfig), signers, message)
+	})
+	require.NoError(t, err)
+
+	config := keygen.Results[ids[0]].(*frost.TaprootConfig)
+	for _, id := range signers {
+		sig := sign.Results[id].(taproot.Signature)
+		assert.True(t, config.PublicKey.Verify(sig, message))
+	}
+	return keygen, sign
+}
+
+func TestGenerate(t *testing.T) {
+	keygen, sign := frostVectors(t, []byte("seed"))
+	assert.Len(t, keygen.Rounds, 2)
+	assert.Len(t, keygen.Outputs, 3)
+	assert.Len(t, sign.Outputs, 2)
+
+	keygenJSON, err := json.Marshal(keygen)
+	require.NoError(t, err)
+	signJSON, err := json.Marshal(sign)
+	require.NoError(t, err)
+
+	keygen2, sign2 := frostVectors(t, []byte("seed"))
+	keygenJSON2, err := json.Marshal(keygen2)
+	require.NoError(t, err)
+	signJSON2, err := json.Marshal(sign2)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(keygenJSON), string(keygenJSON2), "the same seed should give the same keygen vector")
+	assert.JSONEq(t, string(signJSON), string(signJSON2), "the same seed should give the same sign vector")
+
+	keygen3, _ := frostVectors(t, []byte("other seed"))
+	assert.NotEqual(t, keygen.Outputs, keygen3.Outputs, "different seeds should give different keys")
+}
@@ -0,0 +1,106 @@
+package transport
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// MemoryNetwork connects parties running in the same process, mostly for testing.
+type MemoryNetwork struct {
+	mtx     sync.RWMutex
+	parties map[party.ID]*memoryTransport
+}
+
+// NewMemoryNetwork returns a network between ids.
+func NewMemoryNetwork(ids party.IDSlice) *MemoryNetwork {
+	n := &MemoryNetwork{parties: make(map[party.ID]*memoryTransport, len(ids))}
+	for _, id := range ids {
+		n.parties[id] = &memoryTransport{id: id, network: n, inbox: newQueue()}
+	}
+	return n
+}
+
+// Transport returns the Transport of id, or nil if id is not part of the network.
+func (n *MemoryNetwork) Transport(id party.ID) Transport {
+	n.mtx.RLock()
+	defer n.mtx.RUnlock()
+	t, ok := n.parties[id]
+	if !ok {
+		return nil
+	}
+	return t
+}
+
+// deliver puts a copy of msg in the inbox of to.
+func (n *MemoryNetwork) deliver(to party.ID, msg *protocol.Message) error {
+	n.mtx.RLock()
+	t, ok := n.parties[to]
+	n.mtx.RUnlock()
+	if !ok {
+		return fmt.Errorf("transport: unknown party %s", to)
+	}
+	copied := *msg
+	t.inbox.push(&copied)
+	return nil
+}
+
+type memoryTransport struct {
+	id      party.ID
+	network *MemoryNetwork
+	inbox   *queue
+
+	mtx    sync.Mutex
+	closed bool
+}
+
+func (t *memoryTransport) isClosed() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.closed
+}
+
+// Send implements Transport.
+func (t *memoryTransport) Send(msg *protocol.Message) error {
+	if t.isClosed() {
+		return ErrClosed
+	}
+	return t.network.deliver(msg.To, msg)
+}
+
+// Broadcast implements Transport.
+func (t *memoryTransport) Broadcast(msg *protocol.Message) error {
+	if t.isClosed() {
+		return ErrClosed
+	}
+	t.network.mtx.RLock()
+	ids := make([]party.ID, 0, len(t.network.parties))
+	for id := range t.network.parties {
+		if id != t.id {
+			ids = append(ids, id)
+		}
+	}
+	t.network.mtx.RUnlock()
+	for _, id := range ids {
+		if err := t.network.deliver(id, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Receive implements Transport.
+func (t *memoryTransport) Receive() <-chan *protocol.Message {
+	return t.inbox.out
+}
+
+// Close implements Transport.
+func (t *memoryTransport) Close() error {
+	t.mtx.Lock()
+	t.closed = true
+	t.mtx.Unlock()
+	t.inbox.close()
+	return nil
+}
@@ -0,0 +1,305 @@
+package transport
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// minBackoff and maxBackoff bound the wait between two attempts to reach a peer.
+const (
+	minBackoff = 50 * time.Millisecond
+	maxBackoff = 5 * time.Second
+)
+
+// maxFrameSize bounds the size of a single message on the wire, so that a peer can't make us allocate arbitrarily.
+const maxFrameSize = 1 << 26
+
+// TCPConfig describes the endpoints of a TCP transport.
+type TCPConfig struct {
+	// Self is the party running the transport.
+	Self party.ID
+	// Listener accepts the connections of the other parties.
+	Listener net.Listener
+	// Peers maps every other party to the address it listens on.
+	Peers map[party.ID]string
+	// Dial opens a connection to addr, and defaults to a plain TCP connection.
+	//
+	// The other parties identify themselves in the clear when connecting, so Listener and Dial
+	// should be replaced by mutually authenticated TLS outside of tests, as done by NewTLS.
+	Dial func(addr string) (net.Conn, error)
+	// Authenticate, if not nil, checks that conn, opened by Dial or accepted on Listener, is with party id.
+	// It is called before any message is exchanged on the connection, which is dropped if it returns an error.
+	Authenticate func(conn net.Conn, id party.ID) error
+	// Compression is applied to outgoing messages. Incoming messages are accepted whether compressed or not.
+	Compression protocol.Compression
+	// Reconnect is how long sending keeps trying to reach a peer, waiting longer between each attempt.
+	// If it is zero, the connection is only opened again once.
+	Reconnect time.Duration
+}
+
+// TCP is a Transport over one TCP connection to every other party.
+//
+// Each message is sent as a big-endian uint32 length followed by its binary encoding.
+// Connections are opened on the first message to a peer, and reopened if sending fails, for up to TCPConfig.Reconnect.
+type TCP struct {
+	config TCPConfig
+	inbox  *queue
+
+	mtx      sync.Mutex
+	closed   bool
+	done     chan struct{}
+	outgoing map[party.ID]*tcpPeer
+	incoming map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+type tcpPeer struct {
+	mtx  sync.Mutex
+	conn net.Conn
+}
+
+// NewTCP starts accepting connections on config.Listener.
+func NewTCP(config TCPConfig) (*TCP, error) {
+	if config.Listener == nil {
+		return nil, errors.New("transport: tcp: no listener")
+	}
+	if _, ok := config.Peers[config.Self]; ok {
+		return nil, errors.New("transport: tcp: peers must not contain self")
+	}
+	if config.Dial == nil {
+		config.Dial = func(addr string) (net.Conn, error) {
+			return net.Dial("tcp", addr)
+		}
+	}
+	t := &TCP{
+		config:   config,
+		inbox:    newQueue(),
+		done:     make(chan struct{}),
+		outgoing: make(map[party.ID]*tcpPeer, len(config.Peers)),
+		incoming: map[net.Conn]struct{}{},
+	}
+	for id := range config.Peers {
+		t.outgoing[id] = &tcpPeer{}
+	}
+	t.wg.Add(1)
+	go t.accept()
+	return t, nil
+}
+
+// Addr returns the address the transport listens on.
+func (t *TCP) Addr() net.Addr {
+	return t.config.Listener.Addr()
+}
+
+// Send implements Transport.
+func (t *TCP) Send(msg *protocol.Message) error {
+	data, err := msg.MarshalBinaryCompressed(t.config.Compression)
+	if err != nil {
+		return fmt.Errorf("transport: tcp: %w", err)
+	}
+	return t.send(msg.To, data)
+}
+
+// Broadcast implements Transport.
+func (t *TCP) Broadcast(msg *protocol.Message) error {
+	data, err := msg.MarshalBinaryCompressed(t.config.Compression)
+	if err != nil {
+		return fmt.Errorf("transport: tcp: %w", err)
+	}
+	for id := range t.config.Peers {
+		if err = t.send(id, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Receive implements Transport.
+func (t *TCP) Receive() <-chan *protocol.Message {
+	return t.inbox.out
+}
+
+// Close implements Transport.
+func (t *TCP) Close() error {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return nil
+	}
+	t.closed = true
+	close(t.done)
+	err := t.config.Listener.Close()
+	for conn := range t.incoming {
+		_ = conn.Close()
+	}
+	t.mtx.Unlock()
+
+	for _, peer := range t.outgoing {
+		peer.mtx.Lock()
+		if peer.conn != nil {
+			_ = peer.conn.Close()
+			peer.conn = nil
+		}
+		peer.mtx.Unlock()
+	}
+	t.wg.Wait()
+	t.inbox.close()
+	return err
+}
+
+func (t *TCP) isClosed() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.closed
+}
+
+// send writes data to the connection to id, dialing it if needed.
+func (t *TCP) send(id party.ID, data []byte) error {
+	peer, ok := t.outgoing[id]
+	if !ok {
+		return fmt.Errorf("transport: tcp: unknown party %s", id)
+	}
+	peer.mtx.Lock()
+	defer peer.mtx.Unlock()
+
+	var err error
+	deadline := time.Now().Add(t.config.Reconnect)
+	backoff := minBackoff
+	for attempt := 0; attempt < 2 || time.Now().Before(deadline); attempt++ {
+		if t.isClosed() {
+			return ErrClosed
+		}
+		if attempt >= 2 {
+			select {
+			case <-time.After(backoff):
+			case <-t.done:
+				return ErrClosed
+			}
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+		if peer.conn == nil {
+			if peer.conn, err = t.dial(id); err != nil {
+				continue
+			}
+		}
+		if err = writeFrame(peer.conn, data); err == nil {
+			return nil
+		}
+		_ = peer.conn.Close()
+		peer.conn = nil
+	}
+	return fmt.Errorf("transport: tcp: sending to %s: %w", id, err)
+}
+
+// dial connects to id, and identifies ourselves.
+func (t *TCP) dial(id party.ID) (net.Conn, error) {
+	conn, err := t.config.Dial(t.config.Peers[id])
+	if err != nil {
+		return nil, err
+	}
+	if t.config.Authenticate != nil {
+		if err = t.config.Authenticate(conn, id); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	if err = writeFrame(conn, []byte(t.config.Self)); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (t *TCP) accept() {
+	defer t.wg.Done()
+	for {
+		conn, err := t.config.Listener.Accept()
+		if err != nil {
+			return
+		}
+		t.mtx.Lock()
+		if t.closed {
+			t.mtx.Unlock()
+			_ = conn.Close()
+			return
+		}
+		t.incoming[conn] = struct{}{}
+		t.wg.Add(1)
+		t.mtx.Unlock()
+		go t.read(conn)
+	}
+}
+
+// read delivers the messages received on conn, until it fails or is closed.
+// Messages which don't come from the party that opened the connection are dropped.
+func (t *TCP) read(conn net.Conn) {
+	defer t.wg.Done()
+	defer func() {
+		t.mtx.Lock()
+		delete(t.incoming, conn)
+		t.mtx.Unlock()
+		_ = conn.Close()
+	}()
+
+	hello, err := readFrame(conn)
+	if err != nil {
+		return
+	}
+	from := party.ID(hello)
+	if _, ok := t.config.Peers[from]; !ok {
+		return
+	}
+	if t.config.Authenticate != nil && t.config.Authenticate(conn, from) != nil {
+		return
+	}
+	for {
+		data, err := readFrame(conn)
+		if err != nil {
+			return
+		}
+		msg := &protocol.Message{}
+		if err = msg.UnmarshalBinary(data); err != nil || msg.From != from {
+			continue
+		}
+		if !t.inbox.push(msg) {
+			return
+		}
+	}
+}
+
+func writeFrame(w io.Writer, data []byte) error {
+	if len(data) > maxFrameSize {
+		return fmt.Errorf("frame of %d bytes is too large", len(data))
+	}
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+	_, err := w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("frame of %d bytes is too large", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
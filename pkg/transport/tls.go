@@ -0,0 +1,145 @@
+package transport
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// handshakeTimeout bounds the time a TLS handshake may take, so that a silent peer doesn't hold a connection forever.
+const handshakeTimeout = 30 * time.Second
+
+// TLSConfig describes the endpoints of a TCP transport over mutually authenticated TLS.
+//
+// Instead of relying on certificate authorities, each party is pinned to the public key of its certificate,
+// so self-signed certificates can be used, and renewed without changing the pins as long as the key is kept.
+type TLSConfig struct {
+	// Self is the party running the transport.
+	Self party.ID
+	// Certificate is the certificate of this party, along with its private key.
+	Certificate tls.Certificate
+	// Listener accepts the TCP connections of the other parties, and is wrapped in TLS.
+	Listener net.Listener
+	// Peers maps every other party to the address it listens on.
+	Peers map[party.ID]string
+	// Pins maps every other party to the PublicKeyPin of its certificate.
+	Pins map[party.ID][]byte
+	// Compression is applied to outgoing messages.
+	Compression protocol.Compression
+	// Reconnect is how long sending keeps trying to reach a peer, such as one which is restarting.
+	// It defaults to one minute.
+	Reconnect time.Duration
+}
+
+// PublicKeyPin returns the SHA-256 digest of the public key of cert, which identifies a party in TLSConfig.Pins.
+func PublicKeyPin(cert *x509.Certificate) []byte {
+	pin := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return pin[:]
+}
+
+// NewTLS returns a TCP transport whose connections use TLS 1.3, in both directions authenticated with the pinned certificates.
+//
+// A connection from a party is only accepted if its certificate matches the pin of the party it claims to be,
+// and connections to a party are only used if its certificate matches its pin.
+// TCP keep-alives are enabled, and lost connections are opened again when sending.
+func NewTLS(config TLSConfig) (*TCP, error) {
+	if len(config.Certificate.Certificate) == 0 {
+		return nil, errors.New("transport: tls: no certificate")
+	}
+	for id := range config.Peers {
+		if len(config.Pins[id]) != sha256.Size {
+			return nil, fmt.Errorf("transport: tls: no pin for party %s", id)
+		}
+	}
+	if config.Reconnect == 0 {
+		config.Reconnect = time.Minute
+	}
+
+	// the certificate chains are not verified, only the pins
+	verify := func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("transport: tls: no certificate")
+		}
+		cert, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return err
+		}
+		pin := PublicKeyPin(cert)
+		for _, expected := range config.Pins {
+			if subtle.ConstantTimeCompare(pin, expected) == 1 {
+				return nil
+			}
+		}
+		return errors.New("transport: tls: certificate of unknown party")
+	}
+	server := &tls.Config{
+		Certificates:          []tls.Certificate{config.Certificate},
+		ClientAuth:            tls.RequireAnyClientCert,
+		VerifyPeerCertificate: verify,
+		MinVersion:            tls.VersionTLS13,
+	}
+	client := &tls.Config{
+		Certificates:          []tls.Certificate{config.Certificate},
+		InsecureSkipVerify:    true, // the pins are checked instead, by verify and authenticate
+		VerifyPeerCertificate: verify,
+		MinVersion:            tls.VersionTLS13,
+	}
+	dialer := &net.Dialer{Timeout: handshakeTimeout, KeepAlive: 30 * time.Second}
+
+	return NewTCP(TCPConfig{
+		Self:     config.Self,
+		Listener: tls.NewListener(keepAliveListener{config.Listener}, server),
+		Peers:    config.Peers,
+		Dial: func(addr string) (net.Conn, error) {
+			return tls.DialWithDialer(dialer, "tcp", addr, client)
+		},
+		Authenticate: func(conn net.Conn, id party.ID) error {
+			return authenticate(conn, config.Pins[id])
+		},
+		Compression: config.Compression,
+		Reconnect:   config.Reconnect,
+	})
+}
+
+// authenticate completes the handshake of conn, and checks that the certificate of the peer matches pin.
+func authenticate(conn net.Conn, pin []byte) error {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return errors.New("transport: tls: not a TLS connection")
+	}
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 || subtle.ConstantTimeCompare(PublicKeyPin(certs[0]), pin) != 1 {
+		return errors.New("transport: tls: certificate doesn't match the party")
+	}
+	return nil
+}
+
+// keepAliveListener enables TCP keep-alives on the connections it accepts, so that dead peers are eventually noticed.
+type keepAliveListener struct {
+	net.Listener
+}
+
+func (l keepAliveListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetKeepAlive(true)
+		_ = tcp.SetKeepAlivePeriod(30 * time.Second)
+	}
+	return conn, nil
+}
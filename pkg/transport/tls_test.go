@@ -0,0 +1,84 @@
+package transport_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+)
+
+// selfSigned returns a self-signed certificate, and its pin.
+func selfSigned(t *testing.T) (tls.Certificate, []byte) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, transport.PublicKeyPin(cert)
+}
+
+func TestTLS(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	listeners := make(map[party.ID]net.Listener, len(ids))
+	certs := make(map[party.ID]tls.Certificate, len(ids))
+	pins := make(map[party.ID][]byte, len(ids))
+	for _, id := range ids {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		listeners[id] = l
+		certs[id], pins[id] = selfSigned(t)
+	}
+	config := func(id party.ID) transport.TLSConfig {
+		peers := make(map[party.ID]string, len(ids)-1)
+		for _, other := range ids {
+			if other != id {
+				peers[other] = listeners[other].Addr().String()
+			}
+		}
+		return transport.TLSConfig{Self: id, Certificate: certs[id], Listener: listeners[id], Peers: peers, Pins: pins}
+	}
+
+	transports := make(map[party.ID]transport.Transport, len(ids))
+	for _, id := range ids {
+		tcp, err := transport.NewTLS(config(id))
+		require.NoError(t, err)
+		transports[id] = tcp
+		defer tcp.Close()
+	}
+	runKeygen(t, ids, transports)
+
+	// a party without the certificate pinned for "a" can't impersonate it
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	impostor := config("a")
+	impostor.Listener = l
+	impostor.Certificate, _ = selfSigned(t)
+	impostor.Reconnect = time.Millisecond
+	tcp, err := transport.NewTLS(impostor)
+	require.NoError(t, err)
+	defer tcp.Close()
+	// the client's handshake completes before the server checks its certificate, so sending may succeed
+	_ = tcp.Send(&protocol.Message{Version: protocol.WireVersion, From: "a", To: "b"})
+	select {
+	case msg := <-transports["b"].Receive():
+		t.Fatalf("received %v from an impostor", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	missing := config("a")
+	missing.Pins = map[party.ID][]byte{"b": pins["b"]}
+	_, err = transport.NewTLS(missing)
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+// Package transport delivers the messages produced by a protocol.Handler to the other parties.
+//
+// A Transport only moves messages around. Authenticating the sender of a message,
+// and making sure that broadcasts are consistent, is left to the channel underneath
+// (for instance mutually authenticated TLS) and to the handler respectively.
+package transport
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// ErrClosed is returned when sending on, or running a handler over, a Transport which was closed.
+var ErrClosed = errors.New("transport: closed")
+
+// Transport sends and receives the messages of a single party.
+type Transport interface {
+	// Send delivers msg to the party msg.To.
+	Send(msg *protocol.Message) error
+	// Broadcast delivers msg to all other parties.
+	Broadcast(msg *protocol.Message) error
+	// Receive returns the channel on which incoming messages are delivered.
+	// It is closed once the Transport is closed.
+	Receive() <-chan *protocol.Message
+	// Close stops the Transport, and releases its resources.
+	Close() error
+}
+
+// Run drives h over t until the protocol finishes, and returns its result.
+//
+// Messages produced by h are sent or broadcast over t, and incoming messages which h can accept are
+// handed to it. If ctx is cancelled, or t fails, h is stopped and the corresponding error returned.
+// Run doesn't close t, so that it can be reused for the next session.
+func Run(ctx context.Context, h protocol.Handler, t Transport) (interface{}, error) {
+	out := h.Listen()
+	in := t.Receive()
+	for {
+		select {
+		case msg, ok := <-out:
+			if !ok {
+				return h.Result()
+			}
+			var err error
+			if msg.Broadcast || msg.To == "" {
+				err = t.Broadcast(msg)
+			} else {
+				err = t.Send(msg)
+			}
+			if err != nil {
+				h.Stop()
+				return nil, err
+			}
+		case msg, ok := <-in:
+			if !ok {
+				h.Stop()
+				return nil, ErrClosed
+			}
+			if h.CanAccept(msg) {
+				h.Accept(msg)
+			}
+		case <-ctx.Done():
+			h.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// queue is an unbounded buffer in front of a channel, so that senders are never blocked by a slow receiver.
+type queue struct {
+	mtx     sync.Mutex
+	pending []*protocol.Message
+	closed  bool
+	signal  chan struct{}
+	out     chan *protocol.Message
+}
+
+func newQueue() *queue {
+	q := &queue{
+		signal: make(chan struct{}, 1),
+		out:    make(chan *protocol.Message),
+	}
+	go q.run()
+	return q
+}
+
+// push adds msg to the queue, and returns false if the queue is closed.
+func (q *queue) push(msg *protocol.Message) bool {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	if q.closed {
+		return false
+	}
+	q.pending = append(q.pending, msg)
+	q.notify()
+	return true
+}
+
+// close drops the pending messages, and closes the output channel.
+func (q *queue) close() {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.pending = nil
+	q.notify()
+}
+
+func (q *queue) notify() {
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (q *queue) run() {
+	defer close(q.out)
+	for range q.signal {
+		for {
+			q.mtx.Lock()
+			if q.closed {
+				q.mtx.Unlock()
+				return
+			}
+			if len(q.pending) == 0 {
+				q.mtx.Unlock()
+				break
+			}
+			msg := q.pending[0]
+			q.pending = q.pending[1:]
+			q.mtx.Unlock()
+
+			select {
+			case q.out <- msg:
+			case <-q.signal:
+				// either closed, or more messages are pending; put msg back and look again
+				q.mtx.Lock()
+				if !q.closed {
+					q.pending = append([]*protocol.Message{msg}, q.pending...)
+				}
+				q.mtx.Unlock()
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package transport_test
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// runKeygen runs frost keygen between ids, with each party using the transport returned by transports.
+func runKeygen(t *testing.T, ids party.IDSlice, transports map[party.ID]transport.Transport) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	group := curve.Secp256k1{}
+	results := make(map[party.ID]*frost.Config, len(ids))
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, len(ids)-1), []byte("session"))
+		require.NoError(t, err)
+		wg.Add(1)
+		go func(id party.ID) {
+			defer wg.Done()
+			r, err := transport.Run(ctx, h, transports[id])
+			assert.NoError(t, err, id)
+			mtx.Lock()
+			results[id], _ = r.(*frost.Config)
+			mtx.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		require.NotNil(t, results[id], id)
+		assert.True(t, results[ids[0]].PublicKey.Equal(results[id].PublicKey))
+	}
+}
+
+func TestMemoryNetwork(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	network := transport.NewMemoryNetwork(ids)
+	transports := make(map[party.ID]transport.Transport, len(ids))
+	for _, id := range ids {
+		transports[id] = network.Transport(id)
+	}
+	runKeygen(t, ids, transports)
+
+	assert.Nil(t, network.Transport("d"))
+	require.NoError(t, transports["a"].Close())
+	_, ok := <-transports["a"].Receive()
+	assert.False(t, ok)
+	assert.ErrorIs(t, transports["a"].Broadcast(&protocol.Message{From: "a"}), transport.ErrClosed)
+}
+
+func TestTCP(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	listeners := make(map[party.ID]net.Listener, len(ids))
+	for _, id := range ids {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		listeners[id] = l
+	}
+
+	transports := make(map[party.ID]transport.Transport, len(ids))
+	for _, id := range ids {
+		peers := make(map[party.ID]string, len(ids)-1)
+		for _, other := range ids {
+			if other != id {
+				peers[other] = listeners[other].Addr().String()
+			}
+		}
+		config := transport.TCPConfig{Self: id, Listener: listeners[id], Peers: peers}
+		if id == ids[0] {
+			// the others must decode compressed messages without being configured for it
+			config.Compression = protocol.FlateCompression
+		}
+		tcp, err := transport.NewTCP(config)
+		require.NoError(t, err)
+		transports[id] = tcp
+	}
+	runKeygen(t, ids, transports)
+
+	for _, id := range ids {
+		require.NoError(t, transports[id].Close())
+	}
+	assert.Error(t, transports["a"].Send(&protocol.Message{From: "a", To: "b"}))
+}
+
+func TestRunContext(t *testing.T) {
+	ids := party.IDSlice{"a", "b"}
+	network := transport.NewMemoryNetwork(ids)
+	h, err := protocol.NewMultiHandler(frost.Keygen(curve.Secp256k1{}, "a", ids, 1), []byte("session"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = transport.Run(ctx, h, network.Transport("a"))
+	assert.Error(t, err)
+	_, err = h.Result()
+	assert.Error(t, err)
+}
+
+func TestWebSocket(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	relay := transport.NewWebSocketServer(ids)
+	server := httptest.NewServer(relay)
+	defer server.Close()
+	defer relay.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	transports := make(map[party.ID]transport.Transport, len(ids))
+	for _, id := range ids {
+		ws, err := transport.DialWebSocket(transport.WebSocketConfig{URL: url, Self: id})
+		require.NoError(t, err)
+		transports[id] = ws
+	}
+	runKeygen(t, ids, transports)
+
+	for _, id := range ids {
+		require.NoError(t, transports[id].Close())
+	}
+	_, err := transport.DialWebSocket(transport.WebSocketConfig{URL: url, Self: "d"})
+	assert.Error(t, err)
+}
+
+// TestEquivocatingRelay checks that parties connected through an untrusted coordinator abort
+// if it forwards different broadcasts to different parties.
+func TestEquivocatingRelay(t *testing.T) {
+	group := curve.Secp256k1{}
+	ids := party.IDSlice{"a", "b", "c"}
+	handlers := make(map[party.ID]*protocol.MultiHandler, len(ids))
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, 1), []byte("session"))
+		require.NoError(t, err)
+		handlers[id] = h
+	}
+	// the relay obtains another valid broadcast for c, which it forwards to b instead of c's
+	forged, err := protocol.NewMultiHandler(frost.Keygen(group, "c", ids, 1), []byte("session"))
+	require.NoError(t, err)
+	var forgedBroadcast *protocol.Message
+	for msg := range forged.Listen() {
+		if msg.Broadcast {
+			forgedBroadcast = msg
+			break
+		}
+	}
+	require.NotNil(t, forgedBroadcast)
+	forged.Stop()
+
+	for delivered := true; delivered; {
+		delivered = false
+		for _, id := range ids {
+			for len(handlers[id].Listen()) > 0 {
+				msg := <-handlers[id].Listen()
+				if msg == nil {
+					break
+				}
+				delivered = true
+				for _, other := range ids {
+					if !msg.IsFor(other) {
+						continue
+					}
+					if msg.From == "c" && other == "b" && msg.Broadcast && msg.RoundNumber == forgedBroadcast.RoundNumber {
+						handlers[other].Accept(forgedBroadcast)
+					} else {
+						handlers[other].Accept(msg)
+					}
+				}
+			}
+		}
+	}
+
+	for _, id := range ids {
+		_, err := handlers[id].Result()
+		assert.Error(t, err, id)
+	}
+}
@@ -0,0 +1,284 @@
+package transport
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// WebSocketServer relays messages between parties connected to it over WebSockets,
+// so that they only need to open outgoing connections.
+//
+// Messages for a party which is not connected are buffered until it connects again.
+// Since the server sees every message, it must be trusted with the confidentiality of
+// point-to-point messages, or be run by one of the parties.
+type WebSocketServer struct {
+	// Authenticate returns the party connecting with r. It defaults to the "party" query parameter,
+	// which does not authenticate anything, and should be replaced by a check of a client certificate
+	// or token outside of tests.
+	Authenticate func(r *http.Request) (party.ID, error)
+
+	links map[party.ID]*wsLink
+	mtx   sync.Mutex
+	conns map[*wsConn]struct{}
+	wg    sync.WaitGroup
+}
+
+// NewWebSocketServer returns a server relaying messages between ids.
+func NewWebSocketServer(ids party.IDSlice) *WebSocketServer {
+	s := &WebSocketServer{
+		links: make(map[party.ID]*wsLink, len(ids)),
+		conns: map[*wsConn]struct{}{},
+	}
+	for _, id := range ids {
+		s.links[id] = &wsLink{}
+	}
+	return s
+}
+
+func queryAuthenticate(r *http.Request) (party.ID, error) {
+	id := r.URL.Query().Get("party")
+	if id == "" {
+		return "", errors.New("missing party")
+	}
+	return party.ID(id), nil
+}
+
+// ServeHTTP implements http.Handler, and serves the connection of a single party.
+func (s *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	authenticate := s.Authenticate
+	if authenticate == nil {
+		authenticate = queryAuthenticate
+	}
+	id, err := authenticate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	link, ok := s.links[id]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown party %s", id), http.StatusForbidden)
+		return
+	}
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+	s.mtx.Lock()
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	s.mtx.Unlock()
+	defer func() {
+		link.detach(conn)
+		s.mtx.Lock()
+		delete(s.conns, conn)
+		s.mtx.Unlock()
+		s.wg.Done()
+	}()
+
+	if !link.attach(conn) {
+		return
+	}
+	for {
+		data, err := conn.readMessage()
+		if err != nil {
+			return
+		}
+		message, ok, err := link.receive(data)
+		if err != nil {
+			return
+		}
+		if ok {
+			s.relay(id, message)
+		}
+	}
+}
+
+// relay forwards a message received from id to its recipients.
+// Messages which claim to come from another party are dropped.
+func (s *WebSocketServer) relay(from party.ID, data []byte) {
+	msg := &protocol.Message{}
+	if err := msg.UnmarshalBinary(data); err != nil || msg.From != from {
+		return
+	}
+	if msg.To != "" && !msg.Broadcast {
+		if link, ok := s.links[msg.To]; ok {
+			_ = link.send(data)
+		}
+		return
+	}
+	for id, link := range s.links {
+		if id != from {
+			_ = link.send(data)
+		}
+	}
+}
+
+// Close disconnects all parties, and drops the buffered messages.
+// It does not stop the http.Server serving s.
+func (s *WebSocketServer) Close() error {
+	for _, link := range s.links {
+		link.close()
+	}
+	s.mtx.Lock()
+	for conn := range s.conns {
+		conn.close()
+	}
+	s.mtx.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+// WebSocketConfig describes how a party connects to a WebSocketServer.
+type WebSocketConfig struct {
+	// URL is the ws:// or wss:// address of the server.
+	URL string
+	// Self is the party running the transport. It is added to URL as the "party" query parameter.
+	Self party.ID
+	// Header is sent with every handshake, for instance to carry credentials.
+	Header http.Header
+	// TLSConfig is used for wss:// URLs.
+	TLSConfig *tls.Config
+	// RetryInterval is the delay between two connection attempts, and defaults to one second.
+	RetryInterval time.Duration
+	// Compression is applied to outgoing messages. Incoming messages are accepted whether compressed or not.
+	Compression protocol.Compression
+}
+
+// WebSocket is a Transport to a WebSocketServer.
+//
+// The connection is reopened whenever it is lost, and messages sent in the meantime are buffered,
+// so that parties behind unreliable networks can keep running a session.
+type WebSocket struct {
+	config WebSocketConfig
+	url    *url.URL
+	link   *wsLink
+	inbox  *queue
+
+	mtx    sync.Mutex
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// DialWebSocket connects to the server at config.URL.
+//
+// An error is returned if the first connection fails, and later failures are retried
+// until the transport is closed.
+func DialWebSocket(config WebSocketConfig) (*WebSocket, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("transport: websocket: %w", err)
+	}
+	query := u.Query()
+	query.Set("party", string(config.Self))
+	u.RawQuery = query.Encode()
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = time.Second
+	}
+
+	conn, err := dialWebSocket(u, config.Header, config.TLSConfig)
+	if err != nil {
+		return nil, fmt.Errorf("transport: websocket: %w", err)
+	}
+	t := &WebSocket{
+		config: config,
+		url:    u,
+		link:   &wsLink{},
+		inbox:  newQueue(),
+		done:   make(chan struct{}),
+	}
+	t.wg.Add(1)
+	go t.run(conn)
+	return t, nil
+}
+
+// run reads from conn, and reconnects when it is lost.
+func (t *WebSocket) run(conn *wsConn) {
+	defer t.wg.Done()
+	for {
+		if t.link.attach(conn) {
+			t.read(conn)
+		}
+		t.link.detach(conn)
+
+		for conn = nil; conn == nil; {
+			select {
+			case <-t.done:
+				return
+			case <-time.After(t.config.RetryInterval):
+			}
+			conn, _ = dialWebSocket(t.url, t.config.Header, t.config.TLSConfig)
+		}
+	}
+}
+
+func (t *WebSocket) read(conn *wsConn) {
+	for {
+		data, err := conn.readMessage()
+		if err != nil {
+			return
+		}
+		message, ok, err := t.link.receive(data)
+		if err != nil {
+			return
+		}
+		if !ok {
+			continue
+		}
+		msg := &protocol.Message{}
+		if err = msg.UnmarshalBinary(message); err != nil {
+			continue
+		}
+		if !t.inbox.push(msg) {
+			return
+		}
+	}
+}
+
+// Send implements Transport.
+func (t *WebSocket) Send(msg *protocol.Message) error {
+	data, err := msg.MarshalBinaryCompressed(t.config.Compression)
+	if err != nil {
+		return fmt.Errorf("transport: websocket: %w", err)
+	}
+	return t.link.send(data)
+}
+
+// Broadcast implements Transport.
+//
+// The server forwards one copy of msg to every other party.
+func (t *WebSocket) Broadcast(msg *protocol.Message) error {
+	return t.Send(msg)
+}
+
+// Receive implements Transport.
+func (t *WebSocket) Receive() <-chan *protocol.Message {
+	return t.inbox.out
+}
+
+// Close implements Transport.
+//
+// Buffered messages which the server has not acknowledged yet are dropped.
+func (t *WebSocket) Close() error {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return nil
+	}
+	t.closed = true
+	close(t.done)
+	t.mtx.Unlock()
+
+	t.link.close()
+	t.wg.Wait()
+	t.inbox.close()
+	return nil
+}
@@ -0,0 +1,469 @@
+package transport
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// This file implements the subset of RFC 6455 needed to exchange binary messages,
+// as well as the acknowledgements which let a link survive reconnections.
+
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xa
+)
+
+const (
+	wsGUID             = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsHandshakeTimeout = 10 * time.Second
+	wsWriteTimeout     = 10 * time.Second
+	wsPingInterval     = 15 * time.Second
+	// wsReadTimeout lets a few pings go missing before the connection is considered dead.
+	wsReadTimeout = 3 * wsPingInterval
+)
+
+var errWebSocketProtocol = errors.New("transport: websocket: protocol error")
+
+// wsConn is an established websocket connection.
+type wsConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	// client is true for the end which dialed the connection, and must mask its frames.
+	client bool
+	wmtx   sync.Mutex
+}
+
+func wsAcceptKey(key string) string {
+	h := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+func headerContains(header http.Header, name, value string) bool {
+	for _, v := range header.Values(name) {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// upgradeWebSocket completes the handshake of a client connecting to the server.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" || key == "" {
+		http.Error(w, "expected a websocket handshake", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: invalid handshake", errWebSocketProtocol)
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("transport: websocket: connection can't be hijacked")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAcceptKey(key) + "\r\n\r\n"
+	_ = conn.SetWriteDeadline(time.Now().Add(wsHandshakeTimeout))
+	if _, err = conn.Write([]byte(response)); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, r: rw.Reader}, nil
+}
+
+// dialWebSocket connects to a ws:// or wss:// URL.
+func dialWebSocket(u *url.URL, header http.Header, tlsConfig *tls.Config) (*wsConn, error) {
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "wss" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+	dialer := &net.Dialer{Timeout: wsHandshakeTimeout}
+	var conn net.Conn
+	var err error
+	switch u.Scheme {
+	case "ws":
+		conn, err = dialer.Dial("tcp", host)
+	case "wss":
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, tlsConfig)
+	default:
+		return nil, fmt.Errorf("transport: websocket: unsupported scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [16]byte
+	if _, err = rand.Read(nonce[:]); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header.Clone(),
+		Host:       u.Host,
+	}
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	_ = conn.SetDeadline(time.Now().Add(wsHandshakeTimeout))
+	if err = req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(key) {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w: handshake rejected with %s", errWebSocketProtocol, resp.Status)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return &wsConn{conn: conn, r: r, client: true}, nil
+}
+
+func (c *wsConn) close() {
+	_ = c.conn.Close()
+}
+
+// writeFrame writes a single, final frame.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.wmtx.Lock()
+	defer c.wmtx.Unlock()
+
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if c.client {
+		header[1] |= 0x80
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		header = append(header, mask[:]...)
+		masked := make([]byte, len(payload))
+		for i := range payload {
+			masked[i] = payload[i] ^ mask[i%4]
+		}
+		payload = masked
+	}
+	_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	_, err := c.conn.Write(append(header, payload...))
+	return err
+}
+
+// readMessage returns the next data message, answering the control frames received in between.
+func (c *wsConn) readMessage() ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		_ = c.conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+		var header [2]byte
+		if _, err := io.ReadFull(c.r, header[:]); err != nil {
+			return nil, err
+		}
+		fin := header[0]&0x80 != 0
+		opcode := header[0] & 0x0f
+		masked := header[1]&0x80 != 0
+		if header[0]&0x70 != 0 || masked == c.client {
+			return nil, errWebSocketProtocol
+		}
+		length := uint64(header[1] & 0x7f)
+		switch length {
+		case 126:
+			var ext [2]byte
+			if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+				return nil, err
+			}
+			length = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+				return nil, err
+			}
+			length = binary.BigEndian.Uint64(ext[:])
+		}
+		if length+uint64(len(message)) > maxFrameSize {
+			return nil, fmt.Errorf("%w: message too large", errWebSocketProtocol)
+		}
+		var mask [4]byte
+		if masked {
+			if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+				return nil, err
+			}
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.r, payload); err != nil {
+			return nil, err
+		}
+		if masked {
+			for i := range payload {
+				payload[i] ^= mask[i%4]
+			}
+		}
+
+		if opcode >= wsClose && (!fin || length > 125) {
+			return nil, errWebSocketProtocol
+		}
+		switch opcode {
+		case wsPing:
+			if err := c.writeFrame(wsPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			_ = c.writeFrame(wsClose, nil)
+			return nil, io.EOF
+		case wsText, wsBinary:
+			if started {
+				return nil, errWebSocketProtocol
+			}
+			started = true
+			message = payload
+		case wsContinuation:
+			if !started {
+				return nil, errWebSocketProtocol
+			}
+			message = append(message, payload...)
+		default:
+			return nil, errWebSocketProtocol
+		}
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// wsFrame is the unit exchanged over a link.
+type wsFrame struct {
+	// Seq numbers the messages sent over the link, starting at 1. It is 0 for frames carrying only an Ack.
+	Seq uint64
+	// Ack is the sequence number of the last message received in order.
+	Ack uint64
+	// Message is the encoding of a protocol.Message.
+	Message []byte
+}
+
+// wsLink is one end of an ordered, reliable channel carried over successive connections.
+//
+// Messages stay buffered until the other end acknowledges them, and are sent again on the next
+// connection if the current one is lost. Duplicates are dropped by the receiving end.
+type wsLink struct {
+	mtx      sync.Mutex
+	closed   bool
+	writer   *wsWriter
+	nextSeq  uint64
+	unacked  []wsFrame
+	received uint64
+}
+
+// wsWriter sends the pending frames of a link over one connection.
+type wsWriter struct {
+	conn   *wsConn
+	signal chan struct{}
+	// written is the number of unacked frames already written on conn.
+	written    int
+	ackPending bool
+}
+
+func (w *wsWriter) notify() {
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+// send buffers data, and writes it when possible.
+func (l *wsLink) send(data []byte) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.closed {
+		return ErrClosed
+	}
+	l.nextSeq++
+	l.unacked = append(l.unacked, wsFrame{Seq: l.nextSeq, Message: data})
+	if l.writer != nil {
+		l.writer.notify()
+	}
+	return nil
+}
+
+// attach makes conn the connection of the link, replacing the previous one,
+// and sends it all unacknowledged frames.
+func (l *wsLink) attach(conn *wsConn) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.closed {
+		return false
+	}
+	if l.writer != nil {
+		l.writer.conn.close()
+		l.writer.notify()
+	}
+	l.writer = &wsWriter{conn: conn, signal: make(chan struct{}, 1), ackPending: l.received > 0}
+	go l.write(l.writer)
+	return true
+}
+
+// detach closes conn, and removes it from the link if it is still the current one.
+func (l *wsLink) detach(conn *wsConn) {
+	l.mtx.Lock()
+	if l.writer != nil && l.writer.conn == conn {
+		l.writer.notify()
+		l.writer = nil
+	}
+	l.mtx.Unlock()
+	conn.close()
+}
+
+// close detaches the current connection, and drops the pending frames.
+func (l *wsLink) close() {
+	l.mtx.Lock()
+	l.closed = true
+	l.unacked = nil
+	writer := l.writer
+	l.writer = nil
+	l.mtx.Unlock()
+	if writer != nil {
+		writer.notify()
+		writer.conn.close()
+	}
+}
+
+// receive processes a frame read from the connection,
+// and returns its message if it is the next one in order.
+func (l *wsLink) receive(data []byte) ([]byte, bool, error) {
+	var f wsFrame
+	if err := cbor.Unmarshal(data, &f); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", errWebSocketProtocol, err)
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	acked := 0
+	for acked < len(l.unacked) && l.unacked[acked].Seq <= f.Ack {
+		acked++
+	}
+	l.unacked = l.unacked[acked:]
+	if l.writer != nil {
+		l.writer.written -= acked
+		if l.writer.written < 0 {
+			l.writer.written = 0
+		}
+	}
+	if f.Seq == 0 {
+		return nil, false, nil
+	}
+	if l.writer != nil {
+		l.writer.ackPending = true
+		l.writer.notify()
+	}
+	if f.Seq != l.received+1 {
+		// Already delivered before a reconnection; frames are never skipped.
+		return nil, false, nil
+	}
+	l.received = f.Seq
+	return f.Message, true, nil
+}
+
+// write runs until w is no longer the writer of the link.
+func (l *wsLink) write(w *wsWriter) {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	for {
+		l.mtx.Lock()
+		if l.writer != w {
+			l.mtx.Unlock()
+			return
+		}
+		var frame *wsFrame
+		if w.written < len(l.unacked) {
+			f := l.unacked[w.written]
+			f.Ack = l.received
+			frame = &f
+			w.written++
+			w.ackPending = false
+		} else if w.ackPending {
+			frame = &wsFrame{Ack: l.received}
+			w.ackPending = false
+		}
+		l.mtx.Unlock()
+
+		if frame == nil {
+			select {
+			case <-w.signal:
+			case <-ping.C:
+				if err := w.conn.writeFrame(wsPing, nil); err != nil {
+					w.conn.close()
+					return
+				}
+			}
+			continue
+		}
+		data, err := cbor.Marshal(frame)
+		if err == nil {
+			err = w.conn.writeFrame(wsBinary, data)
+		}
+		if err != nil {
+			// the reader notices the closed connection, and detaches it
+			w.conn.close()
+			return
+		}
+	}
+}
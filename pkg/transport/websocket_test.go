@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+func TestWebSocketReconnect(t *testing.T) {
+	ids := party.IDSlice{"a", "b"}
+	relay := NewWebSocketServer(ids)
+	server := httptest.NewServer(relay)
+	defer server.Close()
+	defer relay.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	a, err := DialWebSocket(WebSocketConfig{URL: url, Self: "a", RetryInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := DialWebSocket(WebSocketConfig{URL: url, Self: "b", RetryInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer b.Close()
+
+	const count = 100
+	go func() {
+		for i := 0; i < count; i++ {
+			assert.NoError(t, a.Send(&protocol.Message{From: "a", To: "b", Data: []byte(fmt.Sprint(i))}))
+			if i%10 == 0 {
+				// drop every connection, so that both ends have to reconnect and resend
+				relay.mtx.Lock()
+				for conn := range relay.conns {
+					conn.close()
+				}
+				relay.mtx.Unlock()
+			}
+		}
+	}()
+
+	for i := 0; i < count; i++ {
+		select {
+		case msg := <-b.Receive():
+			assert.Equal(t, fmt.Sprint(i), string(msg.Data))
+		case <-time.After(10 * time.Second):
+			t.Fatalf("message %d was not received", i)
+		}
+	}
+	select {
+	case msg := <-b.Receive():
+		t.Fatalf("unexpected message %s", msg.Data)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
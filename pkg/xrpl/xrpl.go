@@ -0,0 +1,163 @@
+// Package xrpl encodes keys, signatures and addresses in the formats of the XRP Ledger.
+//
+// Accounts of the XRP Ledger use either secp256k1 or Ed25519 keys:
+//
+//   - a secp256k1 key signs the SigningHash of a transaction with ECDSA, as cmp.Sign does,
+//     and the signature is DER encoded by Signature.
+//   - an Ed25519 key signs the SigningData of a transaction itself, as frost.SignCiphersuite with frost.Ed25519SHA512 does,
+//     and the signature is used as is.
+//
+// See: https://xrpl.org/docs/concepts/accounts/cryptographic-keys
+package xrpl
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// transactionPrefix is the hash prefix of single signed transactions, "STX\x00".
+var transactionPrefix = []byte{0x53, 0x54, 0x58, 0x00}
+
+// ed25519Prefix precedes Ed25519 public keys, to distinguish them from compressed secp256k1 keys.
+const ed25519Prefix = 0xed
+
+// alphabet is the Base58 alphabet of the XRP Ledger.
+const alphabet = "rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ2bcdeCg65jkm8oFqi1tuvAxyz"
+
+// SigningData returns the data signed for the binary serialized transaction tx, which is tx prefixed with "STX\x00".
+//
+// tx must be serialized without its TxnSignature field, and with the SigningPubKey of the account.
+func SigningData(tx []byte) []byte {
+	return append(append([]byte{}, transactionPrefix...), tx...)
+}
+
+// SigningHash returns SHA-512Half(SigningData(tx)), the digest signed by secp256k1 keys.
+func SigningHash(tx []byte) []byte {
+	digest := sha512.Sum512(SigningData(tx))
+	return digest[:32]
+}
+
+// PublicKey returns the 33 bytes encoding of a public key in the SigningPubKey field of transactions:
+// a compressed point for secp256k1, or 0xED followed by the point for Ed25519.
+func PublicKey(p curve.Point) ([]byte, error) {
+	if p.IsIdentity() {
+		return nil, errors.New("xrpl: public key is the identity")
+	}
+	switch p.(type) {
+	case *curve.Secp256k1Point:
+		return p.MarshalBinary()
+	case *curve.Edwards25519Point:
+		data, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{ed25519Prefix}, data...), nil
+	default:
+		return nil, errors.New("xrpl: public key must be on secp256k1 or edwards25519")
+	}
+}
+
+// Signature returns the DER encoding of a secp256k1 signature, with S normalized to the lower half of the order,
+// since the XRP Ledger requires fully canonical signatures.
+func Signature(sig *ecdsa.Signature) ([]byte, error) {
+	if _, ok := sig.R.(*curve.Secp256k1Point); !ok {
+		return nil, errors.New("xrpl: signature must be on secp256k1")
+	}
+	normalized := sig.Normalize()
+	r, err := normalized.R.XScalar().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	s, err := normalized.S.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(derSignature{R: new(big.Int).SetBytes(r), S: new(big.Int).SetBytes(s)})
+}
+
+type derSignature struct {
+	R, S *big.Int
+}
+
+// Verify checks the signature of the binary serialized transaction tx by the public key, as encoded by PublicKey.
+func Verify(publicKey, tx, signature []byte) bool {
+	if len(publicKey) == 33 && publicKey[0] == ed25519Prefix {
+		return ed25519.Verify(publicKey[1:], SigningData(tx), signature)
+	}
+
+	group := curve.Secp256k1{}
+	X := group.NewPoint()
+	if X.UnmarshalBinary(publicKey) != nil {
+		return false
+	}
+	var der derSignature
+	if rest, err := asn1.Unmarshal(signature, &der); err != nil || len(rest) != 0 ||
+		der.R.Sign() <= 0 || der.S.Sign() <= 0 || der.R.BitLen() > 256 || der.S.BitLen() > 256 {
+		return false
+	}
+	rBytes, sBytes := make([]byte, 32), make([]byte, 32)
+	der.R.FillBytes(rBytes)
+	der.S.FillBytes(sBytes)
+	r, s := group.NewScalar(), group.NewScalar()
+	if r.UnmarshalBinary(rBytes) != nil || s.UnmarshalBinary(sBytes) != nil || s.IsOverHalfOrder() {
+		return false
+	}
+	m := curve.FromHash(group, SigningHash(tx))
+	R := group.NewScalar().Set(s).Invert().Act(m.ActOnBase().Add(r.Act(X)))
+	return !R.IsIdentity() && R.XScalar().Equal(r)
+}
+
+// AccountID returns the 20 bytes identifier of the account of the public key, RIPEMD-160(SHA-256(PublicKey(p))).
+func AccountID(p curve.Point) ([]byte, error) {
+	key, err := PublicKey(p)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(key)
+	h := ripemd160.New()
+	_, _ = h.Write(digest[:])
+	return h.Sum(nil), nil
+}
+
+// Address returns the classic address of the account of the public key, which starts with 'r'.
+func Address(p curve.Point) (string, error) {
+	accountID, err := AccountID(p)
+	if err != nil {
+		return "", err
+	}
+	// the type prefix of account IDs is 0
+	data := append([]byte{0}, accountID...)
+	first := sha256.Sum256(data)
+	checksum := sha256.Sum256(first[:])
+	return base58(append(data, checksum[:4]...)), nil
+}
+
+// base58 encodes data with the alphabet of the XRP Ledger, where each leading zero byte becomes an 'r'.
+func base58(data []byte) string {
+	x := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var encoded []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, mod)
+		encoded = append(encoded, alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
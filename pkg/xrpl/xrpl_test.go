@@ -0,0 +1,65 @@
+package xrpl
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+func TestAddress(t *testing.T) {
+	// the genesis account, whose secret is derived from "masterpassphrase"
+	key, _ := hex.DecodeString("0330E7FC9D56BB25D6893BA3F317AE5BCF33B3291BD63DB32654A313222F7FD020")
+	X := curve.Secp256k1{}.NewPoint()
+	require.NoError(t, X.UnmarshalBinary(key))
+	address, err := Address(X)
+	require.NoError(t, err)
+	assert.Equal(t, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", address)
+
+	_, err = Address(curve.Secp256k1{}.NewPoint())
+	assert.Error(t, err)
+}
+
+func TestSecp256k1(t *testing.T) {
+	group := curve.Secp256k1{}
+	tx := []byte("serialized transaction")
+	for i := 0; i < 10; i++ {
+		x := sample.Scalar(rand.Reader, group)
+		publicKey, err := PublicKey(x.ActOnBase())
+		require.NoError(t, err)
+		require.Len(t, publicKey, 33)
+
+		// sign SigningHash(tx) with the whole secret, as cmp.Sign would
+		k := sample.Scalar(rand.Reader, group)
+		R := k.ActOnBase()
+		m := curve.FromHash(group, SigningHash(tx))
+		s := group.NewScalar().Set(R.XScalar()).Mul(x).Add(m).Mul(group.NewScalar().Set(k).Invert())
+		sig, err := Signature(&ecdsa.Signature{R: R, S: s})
+		require.NoError(t, err)
+		assert.Equal(t, byte(0x30), sig[0], "signatures are DER encoded")
+		assert.True(t, Verify(publicKey, tx, sig))
+		assert.False(t, Verify(publicKey, append(tx, 0), sig))
+		assert.False(t, Verify(publicKey, tx, sig[:len(sig)-1]))
+	}
+}
+
+func TestEd25519(t *testing.T) {
+	public, secret, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	X := curve.Edwards25519{}.NewPoint()
+	require.NoError(t, X.UnmarshalBinary(public))
+	publicKey, err := PublicKey(X)
+	require.NoError(t, err)
+	assert.Equal(t, append([]byte{0xed}, public...), publicKey)
+
+	tx := []byte("serialized transaction")
+	sig := ed25519.Sign(secret, SigningData(tx))
+	assert.True(t, Verify(publicKey, tx, sig))
+	assert.False(t, Verify(publicKey, append(tx, 0), sig))
+}
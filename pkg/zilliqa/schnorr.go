@@ -0,0 +1,128 @@
+// Package zilliqa implements the Schnorr signatures over secp256k1 used by Zilliqa transactions.
+//
+// A signature of a message m by the public key P = x•G is a pair (r, s) such that
+//
+//	r = H(Q ‖ P ‖ m) mod n, with Q = s•G + r•P
+//
+// where H is SHA-256, points are compressed, and the signer computes Q = k•G and s = k - r•x for a random nonce k.
+//
+// See: https://github.com/Zilliqa/Zilliqa/blob/master/src/libCrypto/Schnorr.cpp
+package zilliqa
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+// PublicKeyLen is the number of bytes in a PublicKey.
+const PublicKeyLen = 33
+
+// PublicKey is a compressed secp256k1 point, as found in Zilliqa transactions.
+type PublicKey []byte
+
+// SignatureLen is the number of bytes in a Signature.
+const SignatureLen = 64
+
+// Signature is the encoding r ‖ s of a signature, with both scalars in 32 bytes big endian.
+type Signature []byte
+
+// SecretKey is a secret scalar x, whose public key is x•G.
+type SecretKey struct {
+	x *curve.Secp256k1Scalar
+}
+
+// GenKey samples a new key pair from rand.
+func GenKey(rand io.Reader) (*SecretKey, PublicKey, error) {
+	x, ok := sample.Scalar(rand, curve.Secp256k1{}).(*curve.Secp256k1Scalar)
+	if !ok {
+		return nil, nil, errors.New("zilliqa: unexpected scalar")
+	}
+	sk := &SecretKey{x: x}
+	return sk, sk.Public(), nil
+}
+
+// Public returns the public key x•G.
+func (sk *SecretKey) Public() PublicKey {
+	public, _ := sk.x.ActOnBase().MarshalBinary()
+	return public
+}
+
+// Sign signs m with a nonce sampled from rand.
+//
+// m is the serialized transaction itself, which is hashed as part of the challenge.
+func (sk *SecretKey) Sign(rand io.Reader, m []byte) (Signature, error) {
+	group := curve.Secp256k1{}
+	for {
+		k := sample.Scalar(rand, group)
+		r, err := Challenge(k.ActOnBase(), sk.x.ActOnBase(), m)
+		if err != nil {
+			return nil, err
+		}
+		s := group.NewScalar().Set(r).Mul(sk.x).Negate().Add(k)
+		// both scalars must be non zero, so another nonce is tried in the unlikely case one is
+		if r.IsZero() || s.IsZero() {
+			continue
+		}
+		return Encode(r, s)
+	}
+}
+
+// Challenge returns r = H(Q ‖ P ‖ m) mod n.
+func Challenge(Q, P curve.Point, m []byte) (curve.Scalar, error) {
+	QBytes, err := Q.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	PBytes, err := P.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	_, _ = h.Write(QBytes)
+	_, _ = h.Write(PBytes)
+	_, _ = h.Write(m)
+	return curve.Secp256k1{}.NewScalar().SetNat(new(saferith.Nat).SetBytes(h.Sum(nil))), nil
+}
+
+// Encode returns the Signature r ‖ s.
+func Encode(r, s curve.Scalar) (Signature, error) {
+	rBytes, err := r.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	sBytes, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(rBytes, sBytes...), nil
+}
+
+// Verify checks that sig is a valid signature of m by the public key pk.
+func (pk PublicKey) Verify(sig Signature, m []byte) bool {
+	if len(sig) != SignatureLen || len(pk) != PublicKeyLen {
+		return false
+	}
+	group := curve.Secp256k1{}
+	P := group.NewPoint()
+	if err := P.UnmarshalBinary(pk); err != nil || P.IsIdentity() {
+		return false
+	}
+	r, s := group.NewScalar(), group.NewScalar()
+	if r.UnmarshalBinary(sig[:32]) != nil || s.UnmarshalBinary(sig[32:]) != nil || r.IsZero() || s.IsZero() {
+		return false
+	}
+	Q := s.ActOnBase().Add(r.Act(P))
+	if Q.IsIdentity() {
+		return false
+	}
+	expected, err := Challenge(Q, P, m)
+	if err != nil {
+		return false
+	}
+	return expected.Equal(r)
+}
@@ -0,0 +1,44 @@
+package zilliqa
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+func TestSignatureVerification(t *testing.T) {
+	m := []byte("serialized transaction")
+	for i := 0; i < 10; i++ {
+		sk, pk, err := GenKey(rand.Reader)
+		require.NoError(t, err)
+		require.Len(t, pk, PublicKeyLen)
+
+		sig, err := sk.Sign(rand.Reader, m)
+		require.NoError(t, err)
+		require.Len(t, sig, SignatureLen)
+		assert.True(t, pk.Verify(sig, m))
+		assert.False(t, pk.Verify(sig, append(m, 0)))
+
+		// r is the hash of Q = s•G + r•P
+		group := curve.Secp256k1{}
+		r, s := group.NewScalar(), group.NewScalar()
+		require.NoError(t, r.UnmarshalBinary(sig[:32]))
+		require.NoError(t, s.UnmarshalBinary(sig[32:]))
+		P := group.NewPoint()
+		require.NoError(t, P.UnmarshalBinary(pk))
+		c, err := Challenge(s.ActOnBase().Add(r.Act(P)), P, m)
+		require.NoError(t, err)
+		assert.True(t, c.Equal(r))
+
+		tampered := append(Signature{}, sig...)
+		tampered[40] ^= 1
+		assert.False(t, pk.Verify(tampered, m))
+		assert.False(t, pk.Verify(sig[:63], m))
+		_, other, err := GenKey(rand.Reader)
+		require.NoError(t, err)
+		assert.False(t, other.Verify(sig, m))
+	}
+}
@@ -1,15 +1,15 @@
 package zkaffg
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 )
 
 type Public struct {
@@ -102,6 +102,12 @@ func (p *Proof) IsValid(public Public) bool {
 }
 
 func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
+	proof, _ := newProof(group, hash, public, private)
+	return proof
+}
+
+// newProof returns the proof, and its challenge e.
+func newProof(group curve.Curve, hash *hash.Hash, public Public, private Private) (*Proof, *saferith.Int) {
 	N0 := public.Verifier.N()
 	N1 := public.Prover.N()
 	N0Modulus := public.Verifier.Modulus()
@@ -110,16 +116,16 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 	verifier := public.Verifier
 	prover := public.Prover
 
-	alpha := sample.IntervalLEps(rand.Reader)
-	beta := sample.IntervalLPrimeEps(rand.Reader)
+	alpha := sample.IntervalLEps(random.Reader())
+	beta := sample.IntervalLPrimeEps(random.Reader())
 
-	rho := sample.UnitModN(rand.Reader, N0)
-	rhoY := sample.UnitModN(rand.Reader, N1)
+	rho := sample.UnitModN(random.Reader(), N0)
+	rhoY := sample.UnitModN(random.Reader(), N1)
 
-	gamma := sample.IntervalLEpsN(rand.Reader)
-	m := sample.IntervalLN(rand.Reader)
-	delta := sample.IntervalLEpsN(rand.Reader)
-	mu := sample.IntervalLN(rand.Reader)
+	gamma := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
+	m := sample.IntervalLN(random.Reader(), public.Aux.N())
+	delta := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
+	mu := sample.IntervalLN(random.Reader(), public.Aux.N())
 
 	cAlpha := public.Kv.Clone().Mul(verifier, alpha)            // = Cᵃ mod N₀ = α ⊙ Kv
 	A := verifier.EncWithNonce(beta, rho).Add(verifier, cAlpha) // = Enc₀(β,ρ) ⊕ (α ⊙ Kv)
@@ -170,35 +176,75 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 		Z4:         z4,
 		W:          w,
 		Wy:         wY,
-	}
+	}, e
 }
 
 func (p *Proof) Verify(hash *hash.Hash, public Public) bool {
-	if !p.IsValid(public) {
+	e, ok := p.verify(hash, public)
+	if !ok {
 		return false
 	}
+	for _, eq := range p.equations(public, e) {
+		if !public.Aux.Verify(eq.A, eq.B, eq.E, eq.S, eq.T) {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyBatch returns true if every proof is valid for the corresponding hash and public statement.
+//
+// As in zkenc.VerifyBatch, the checks over the Paillier moduli and the curve are performed separately,
+// in parallel using pl, and only the two checks of each proof over the Pedersen parameters are combined.
+func VerifyBatch(hashes []*hash.Hash, publics []Public, proofs []*Proof, pl *pool.Pool) bool {
+	n := len(proofs)
+	if len(hashes) != n || len(publics) != n {
+		return false
+	}
+	challenges := pl.Parallelize(n, func(i int) interface{} {
+		if e, ok := proofs[i].verify(hashes[i], publics[i]); ok {
+			return e
+		}
+		return nil
+	})
+	equations := make([]pedersen.Equation, 0, 2*n)
+	for i, e := range challenges {
+		if e == nil {
+			return false
+		}
+		equations = append(equations, proofs[i].equations(publics[i], e.(*saferith.Int))...)
+	}
+	return pedersen.VerifyBatch(equations)
+}
+
+// equations returns the checks sᶻ¹tᶻ³ = E Sᵉ and sᶻ²tᶻ⁴ = F Tᵉ over the Pedersen parameters.
+func (p *Proof) equations(public Public, e *saferith.Int) []pedersen.Equation {
+	return []pedersen.Equation{
+		{Params: public.Aux, A: p.Z1, B: p.Z3, E: e, S: p.E, T: p.S},
+		{Params: public.Aux, A: p.Z2, B: p.Z4, E: e, S: p.F, T: p.T},
+	}
+}
+
+// verify performs all checks of Verify, except for the ones over the Pedersen parameters,
+// and returns the challenge.
+func (p *Proof) verify(hash *hash.Hash, public Public) (*saferith.Int, bool) {
+	if !p.IsValid(public) {
+		return nil, false
+	}
 
 	verifier := public.Verifier
 	prover := public.Prover
 
 	if !arith.IsInIntervalLEps(p.Z1) {
-		return false
+		return nil, false
 	}
 	if !arith.IsInIntervalLPrimeEps(p.Z2) {
-		return false
+		return nil, false
 	}
 
 	e, err := challenge(hash, p.group, public, p.Commitment)
 	if err != nil {
-		return false
-	}
-
-	if !public.Aux.Verify(p.Z1, p.Z3, e, p.E, p.S) {
-		return false
-	}
-
-	if !public.Aux.Verify(p.Z2, p.Z4, e, p.F, p.T) {
-		return false
+		return nil, false
 	}
 
 	{
@@ -211,7 +257,7 @@ func (p *Proof) Verify(hash *hash.Hash, public Public) bool {
 		rhs := public.Dv.Clone().Mul(verifier, e).Add(verifier, p.A)
 
 		if !lhs.Equal(rhs) {
-			return false
+			return nil, false
 		}
 	}
 
@@ -223,7 +269,7 @@ func (p *Proof) Verify(hash *hash.Hash, public Public) bool {
 		rhs := p.group.NewScalar().SetNat(e.Mod(p.group.Order())).Act(public.Xp)
 		rhs = rhs.Add(p.Bx)
 		if !lhs.Equal(rhs) {
-			return false
+			return nil, false
 		}
 	}
 
@@ -235,11 +281,11 @@ func (p *Proof) Verify(hash *hash.Hash, public Public) bool {
 		rhs := public.Fp.Clone().Mul(prover, e).Add(prover, p.By)
 
 		if !lhs.Equal(rhs) {
-			return false
+			return nil, false
 		}
 	}
 
-	return true
+	return e, true
 }
 
 func challenge(hash *hash.Hash, group curve.Curve, public Public, commitment *Commitment) (e *saferith.Int, err error) {
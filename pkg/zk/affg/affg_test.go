@@ -64,3 +64,42 @@ func TestAffG(t *testing.T) {
 	assert.True(t, proof3.Verify(hash.New(), public))
 
 }
+
+func TestAffGCompact(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	verifierPaillier := zk.VerifierPaillierPublic
+	prover := zk.ProverPaillierPublic
+
+	C, _ := verifierPaillier.Enc(new(saferith.Int).SetUint64(12))
+	x := sample.IntervalL(rand.Reader)
+	y := sample.IntervalLPrime(rand.Reader)
+	Y, rhoY := prover.Enc(y)
+	D, rho := verifierPaillier.Enc(y)
+	D.Add(verifierPaillier, C.Clone().Mul(verifierPaillier, x))
+
+	public := Public{
+		Kv:       C,
+		Dv:       D,
+		Fp:       Y,
+		Xp:       group.NewScalar().SetNat(x.Mod(group.Order())).ActOnBase(),
+		Prover:   prover,
+		Verifier: verifierPaillier,
+		Aux:      zk.Pedersen,
+	}
+	proof := NewCompactProof(group, hash.New(), public, Private{X: x, Y: y, S: rho, R: rhoY})
+	assert.True(t, proof.Verify(group, hash.New(), public))
+
+	out, err := cbor.Marshal(proof)
+	require.NoError(t, err, "failed to marshal proof")
+	decoded := &CompactProof{}
+	require.NoError(t, cbor.Unmarshal(out, decoded), "failed to unmarshal proof")
+	assert.True(t, decoded.Verify(group, hash.New(), public))
+
+	full, err := cbor.Marshal(NewProof(group, hash.New(), public, Private{X: x, Y: y, S: rho, R: rhoY}))
+	require.NoError(t, err, "failed to marshal proof")
+	assert.Less(t, len(out), len(full))
+
+	proof.Z4 = proof.Z3
+	assert.False(t, proof.Verify(group, hash.New(), public), "proof should have failed")
+}
@@ -0,0 +1,109 @@
+package zkaffg
+
+import (
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// CompactProof is a Proof which only keeps the commitments S and T to the witness,
+// with the challenge e instead of A, Bₓ, By, E and F.
+//
+// This removes two ciphertexts, a point and two Pedersen commitments from the proof,
+// which the verifier recomputes from the responses, as for zkenc.CompactProof.
+type CompactProof struct {
+	// S = sˣ tᵐ (mod N)
+	S *saferith.Nat
+	// T = sʸ tᵘ (mod N)
+	T *saferith.Nat
+	// E = e
+	E *saferith.Int
+	// Z1 = Z₁ = α + e⋅x
+	Z1 *saferith.Int
+	// Z2 = Z₂ = β + e⋅y
+	Z2 *saferith.Int
+	// Z3 = Z₃ = γ + e⋅m
+	Z3 *saferith.Int
+	// Z4 = Z₄ = δ + e⋅μ
+	Z4 *saferith.Int
+	// W = w = ρ⋅sᵉ (mod N₀)
+	W *saferith.Nat
+	// Wy = wy = ρy⋅rᵉ (mod N₁)
+	Wy *saferith.Nat
+}
+
+// NewCompactProof generates the same proof as NewProof, in compact form.
+func NewCompactProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *CompactProof {
+	proof, e := newProof(group, hash, public, private)
+	return &CompactProof{
+		S:  proof.S,
+		T:  proof.T,
+		E:  e,
+		Z1: proof.Z1,
+		Z2: proof.Z2,
+		Z3: proof.Z3,
+		Z4: proof.Z4,
+		W:  proof.W,
+		Wy: proof.Wy,
+	}
+}
+
+// Verify performs the same checks as Proof.Verify, on the recomputed commitments.
+func (p *CompactProof) Verify(group curve.Curve, hash *hash.Hash, public Public) bool {
+	if p == nil || p.E == nil || p.Z3 == nil || p.Z4 == nil {
+		return false
+	}
+	if p.E.TrueLen() > group.ScalarBits() {
+		return false
+	}
+
+	verifier := public.Verifier
+	prover := public.Prover
+
+	if !arith.IsValidNatModN(prover.N(), p.Wy) {
+		return false
+	}
+	if !arith.IsValidNatModN(verifier.N(), p.W) {
+		return false
+	}
+	if !arith.IsValidNatModN(public.Aux.N(), p.S, p.T) {
+		return false
+	}
+	if !arith.IsInIntervalLEps(p.Z1) {
+		return false
+	}
+	if !arith.IsInIntervalLPrimeEps(p.Z2) {
+		return false
+	}
+
+	// Bₓ = [z₁]G - [e]Xp
+	Bx := group.NewScalar().SetNat(p.Z1.Mod(group.Order())).ActOnBase()
+	Bx = Bx.Sub(group.NewScalar().SetNat(p.E.Mod(group.Order())).Act(public.Xp))
+	if Bx.IsIdentity() {
+		return false
+	}
+
+	// A = (z₁ ⊙ Kv) ⊕ Enc₀(z₂;w) ⊖ (e ⊙ Dv)
+	A := verifier.EncWithNonce(p.Z2, p.W).Add(verifier, public.Kv.Clone().Mul(verifier, p.Z1))
+	A.Sub(verifier, public.Dv.Clone().Mul(verifier, p.E))
+
+	commitment := &Commitment{
+		A:  A,
+		Bx: Bx,
+		// By = Enc₁(z₂;wy) ⊖ (e ⊙ Fp)
+		By: prover.EncWithNonce(p.Z2, p.Wy).Sub(prover, public.Fp.Clone().Mul(prover, p.E)),
+		// E = sᶻ¹ tᶻ³ S⁻ᵉ
+		E: public.Aux.Recover(p.Z1, p.Z3, p.E, p.S),
+		S: p.S,
+		// F = sᶻ² tᶻ⁴ T⁻ᵉ
+		F: public.Aux.Recover(p.Z2, p.Z4, p.E, p.T),
+		T: p.T,
+	}
+
+	e, err := challenge(hash, group, public, commitment)
+	if err != nil {
+		return false
+	}
+	return e.Eq(p.E) == 1
+}
@@ -1,9 +1,8 @@
 package zkaffp
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -112,17 +111,17 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 	verifier := public.Verifier
 	prover := public.Prover
 
-	alpha := sample.IntervalLEps(rand.Reader)
-	beta := sample.IntervalLPrimeEps(rand.Reader)
+	alpha := sample.IntervalLEps(random.Reader())
+	beta := sample.IntervalLPrimeEps(random.Reader())
 
-	rho := sample.UnitModN(rand.Reader, N0)
-	rhoX := sample.UnitModN(rand.Reader, N1)
-	rhoY := sample.UnitModN(rand.Reader, N1)
+	rho := sample.UnitModN(random.Reader(), N0)
+	rhoX := sample.UnitModN(random.Reader(), N1)
+	rhoY := sample.UnitModN(random.Reader(), N1)
 
-	gamma := sample.IntervalLEpsN(rand.Reader)
-	m := sample.IntervalLN(rand.Reader)
-	delta := sample.IntervalLEpsN(rand.Reader)
-	mu := sample.IntervalLN(rand.Reader)
+	gamma := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
+	m := sample.IntervalLN(random.Reader(), public.Aux.N())
+	delta := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
+	mu := sample.IntervalLN(random.Reader(), public.Aux.N())
 
 	cAlpha := public.Kv.Clone().Mul(verifier, alpha)            // = Cᵃ mod N₀ = α ⊙ Kv
 	A := verifier.EncWithNonce(beta, rho).Add(verifier, cAlpha) // = Enc₀(β,ρ) ⊕ (α ⊙ Kv)
@@ -1,9 +1,8 @@
 package zkdec
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -73,11 +72,11 @@ func (p *Proof) IsValid(public Public) bool {
 func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
 	N := public.Prover.N()
 	NModulus := public.Prover.Modulus()
-	alpha := sample.IntervalLEps(rand.Reader)
+	alpha := sample.IntervalLEps(random.Reader())
 
-	mu := sample.IntervalLN(rand.Reader)
-	nu := sample.IntervalLEpsN(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
+	mu := sample.IntervalLN(random.Reader(), public.Aux.N())
+	nu := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
+	r := sample.UnitModN(random.Reader(), N)
 
 	gamma := group.NewScalar().SetNat(alpha.Mod(group.Order()))
 
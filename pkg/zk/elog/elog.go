@@ -1,9 +1,8 @@
 package zkelog
 
 import (
-	"crypto/rand"
-
 	"github.com/taurusgroup/multi-party-sig/internal/elgamal"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -67,8 +66,8 @@ func (p *Proof) IsValid(public Public) bool {
 }
 
 func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
-	alpha := sample.Scalar(rand.Reader, group)
-	m := sample.Scalar(rand.Reader, group)
+	alpha := sample.Scalar(random.Reader(), group)
+	m := sample.Scalar(random.Reader(), group)
 
 	commitment := &Commitment{
 		A: alpha.ActOnBase(),                                  // A = α⋅G
@@ -0,0 +1,73 @@
+package zkenc
+
+import (
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// CompactProof is a Proof in which the commitments A and C are replaced by the challenge e.
+//
+// The verifier recomputes A and C from the responses, and accepts if they hash to e,
+// which saves a ciphertext and a Pedersen commitment on the wire.
+// The Pedersen check can't be batched with other proofs in this form.
+type CompactProof struct {
+	// S = sᵏtᵘ
+	S *saferith.Nat
+	// E = e
+	E *saferith.Int
+	// Z₁ = α + e⋅k
+	Z1 *saferith.Int
+	// Z₂ = r ⋅ ρᵉ mod N₀
+	Z2 *saferith.Nat
+	// Z₃ = γ + e⋅μ
+	Z3 *saferith.Int
+}
+
+// NewCompactProof generates the same proof as NewProof, in compact form.
+func NewCompactProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *CompactProof {
+	proof, e := newProof(group, hash, public, private)
+	return &CompactProof{
+		S:  proof.S,
+		E:  e,
+		Z1: proof.Z1,
+		Z2: proof.Z2,
+		Z3: proof.Z3,
+	}
+}
+
+// Verify performs the same checks as Proof.Verify, on the recomputed commitments.
+func (p *CompactProof) Verify(group curve.Curve, hash *hash.Hash, public Public) bool {
+	if p == nil || p.E == nil || p.Z3 == nil {
+		return false
+	}
+	// the challenge is sampled in ±2^|q|
+	if p.E.TrueLen() > group.ScalarBits() {
+		return false
+	}
+	if !arith.IsValidNatModN(public.Prover.N(), p.Z2) {
+		return false
+	}
+	if !arith.IsValidNatModN(public.Aux.N(), p.S) {
+		return false
+	}
+	if !arith.IsInIntervalLEps(p.Z1) {
+		return false
+	}
+
+	prover := public.Prover
+	commitment := &Commitment{
+		S: p.S,
+		// A = Enc(z₁;z₂) ⊖ (e ⊙ K)
+		A: prover.EncWithNonce(p.Z1, p.Z2).Sub(prover, public.K.Clone().Mul(prover, p.E)),
+		// C = sᶻ¹ tᶻ³ S⁻ᵉ
+		C: public.Aux.Recover(p.Z1, p.Z3, p.E, p.S),
+	}
+
+	e, err := challenge(hash, group, public, commitment)
+	if err != nil {
+		return false
+	}
+	return e.Eq(p.E) == 1
+}
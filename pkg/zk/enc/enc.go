@@ -1,15 +1,15 @@
 package zkenc
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 )
 
 type Public struct {
@@ -62,13 +62,19 @@ func (p *Proof) IsValid(public Public) bool {
 }
 
 func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
+	proof, _ := newProof(group, hash, public, private)
+	return proof
+}
+
+// newProof returns the proof, and its challenge e.
+func newProof(group curve.Curve, hash *hash.Hash, public Public, private Private) (*Proof, *saferith.Int) {
 	N := public.Prover.N()
 	NModulus := public.Prover.Modulus()
 
-	alpha := sample.IntervalLEps(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
-	mu := sample.IntervalLN(rand.Reader)
-	gamma := sample.IntervalLEpsN(rand.Reader)
+	alpha := sample.IntervalLEps(random.Reader())
+	r := sample.UnitModN(random.Reader(), N)
+	mu := sample.IntervalLN(random.Reader(), public.Aux.N())
+	gamma := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
 
 	A := public.Prover.EncWithNonce(alpha, r)
 
@@ -95,27 +101,66 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 		Z1:         z1,
 		Z2:         z2,
 		Z3:         z3,
-	}
+	}, e
 }
 
 func (p *Proof) Verify(group curve.Curve, hash *hash.Hash, public Public) bool {
-	if !p.IsValid(public) {
+	e, ok := p.verify(group, hash, public)
+	if !ok {
+		return false
+	}
+	return public.Aux.Verify(p.Z1, p.Z3, e, p.C, p.S)
+}
+
+// VerifyBatch returns true if every proof is valid for the corresponding hash and public statement.
+//
+// The checks over each prover's Paillier modulus are performed separately, in parallel using pl,
+// while the checks over the Pedersen parameters are combined with pedersen.VerifyBatch.
+// This is faster than calling Verify for each proof when many of them share the same Aux.
+func VerifyBatch(group curve.Curve, hashes []*hash.Hash, publics []Public, proofs []*Proof, pl *pool.Pool) bool {
+	n := len(proofs)
+	if len(hashes) != n || len(publics) != n {
 		return false
 	}
+	challenges := pl.Parallelize(n, func(i int) interface{} {
+		if e, ok := proofs[i].verify(group, hashes[i], publics[i]); ok {
+			return e
+		}
+		return nil
+	})
+	equations := make([]pedersen.Equation, 0, n)
+	for i, e := range challenges {
+		if e == nil {
+			return false
+		}
+		equations = append(equations, pedersen.Equation{
+			Params: publics[i].Aux,
+			A:      proofs[i].Z1,
+			B:      proofs[i].Z3,
+			E:      e.(*saferith.Int),
+			S:      proofs[i].C,
+			T:      proofs[i].S,
+		})
+	}
+	return pedersen.VerifyBatch(equations)
+}
+
+// verify performs all checks of Verify, except for the one over the Pedersen parameters,
+// and returns the challenge.
+func (p *Proof) verify(group curve.Curve, hash *hash.Hash, public Public) (*saferith.Int, bool) {
+	if !p.IsValid(public) {
+		return nil, false
+	}
 
 	prover := public.Prover
 
 	if !arith.IsInIntervalLEps(p.Z1) {
-		return false
+		return nil, false
 	}
 
 	e, err := challenge(hash, group, public, p.Commitment)
 	if err != nil {
-		return false
-	}
-
-	if !public.Aux.Verify(p.Z1, p.Z3, e, p.C, p.S) {
-		return false
+		return nil, false
 	}
 
 	{
@@ -125,11 +170,11 @@ func (p *Proof) Verify(group curve.Curve, hash *hash.Hash, public Public) bool {
 		// rhs = (e ⊙ K) ⊕ A
 		rhs := public.K.Clone().Mul(prover, e).Add(prover, p.A)
 		if !lhs.Equal(rhs) {
-			return false
+			return nil, false
 		}
 	}
 
-	return true
+	return e, true
 }
 
 func challenge(hash *hash.Hash, group curve.Curve, public Public, commitment *Commitment) (e *saferith.Int, err error) {
@@ -44,3 +44,63 @@ func TestEnc(t *testing.T) {
 
 	assert.True(t, proof3.Verify(group, hash.New(), public))
 }
+
+func TestEncCompact(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	k := sample.IntervalL(rand.Reader)
+	K, rho := zk.ProverPaillierPublic.Enc(k)
+	public := Public{
+		K:      K,
+		Prover: zk.ProverPaillierPublic,
+		Aux:    zk.Pedersen,
+	}
+
+	proof := NewCompactProof(group, hash.New(), public, Private{K: k, Rho: rho})
+	assert.True(t, proof.Verify(group, hash.New(), public))
+
+	out, err := cbor.Marshal(proof)
+	require.NoError(t, err, "failed to marshal proof")
+	decoded := &CompactProof{}
+	require.NoError(t, cbor.Unmarshal(out, decoded), "failed to unmarshal proof")
+	assert.True(t, decoded.Verify(group, hash.New(), public))
+
+	other, _ := zk.ProverPaillierPublic.Enc(k)
+	assert.False(t, proof.Verify(group, hash.New(), Public{K: other, Prover: public.Prover, Aux: public.Aux}), "proof is for another ciphertext")
+	proof.E.Neg(1)
+	assert.False(t, proof.Verify(group, hash.New(), public), "proof should fail with another challenge")
+}
+
+func TestEncBatch(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	verifier := zk.Pedersen
+	prover := zk.ProverPaillierPublic
+
+	const n = 4
+	hashes := make([]*hash.Hash, n)
+	publics := make([]Public, n)
+	proofs := make([]*Proof, n)
+	for i := range proofs {
+		k := sample.IntervalL(rand.Reader)
+		K, rho := prover.Enc(k)
+		publics[i] = Public{
+			K:      K,
+			Prover: prover,
+			Aux:    verifier,
+		}
+		proofs[i] = NewProof(group, hash.New(), publics[i], Private{
+			K:   k,
+			Rho: rho,
+		})
+		hashes[i] = hash.New()
+	}
+	assert.True(t, VerifyBatch(group, hashes, publics, proofs, nil))
+
+	hashes = make([]*hash.Hash, n)
+	for i := range hashes {
+		hashes[i] = hash.New()
+	}
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	assert.False(t, VerifyBatch(group, hashes, publics, proofs, nil))
+}
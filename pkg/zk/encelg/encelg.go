@@ -1,9 +1,8 @@
 package zkencelg
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -85,12 +84,12 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 	N := public.Prover.N()
 	NModulus := public.Prover.Modulus()
 
-	alpha := sample.IntervalLEps(rand.Reader)
+	alpha := sample.IntervalLEps(random.Reader())
 	alphaScalar := group.NewScalar().SetNat(alpha.Mod(group.Order()))
-	mu := sample.IntervalLN(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
-	beta := sample.Scalar(rand.Reader, group)
-	gamma := sample.IntervalLEpsN(rand.Reader)
+	mu := sample.IntervalLN(random.Reader(), public.Aux.N())
+	r := sample.UnitModN(random.Reader(), N)
+	beta := sample.Scalar(random.Reader(), group)
+	gamma := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
 
 	commitment := &Commitment{
 		S: public.Aux.Commit(private.X, mu),
@@ -1,9 +1,8 @@
 package zkfac
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -41,14 +40,14 @@ func NewProof(private Private, hash *hash.Hash, public Public) *Proof {
 	Nhat := public.Aux.NArith()
 
 	// Figure 28, point 1.
-	alpha := sample.IntervalLEpsRootN(rand.Reader)
-	beta := sample.IntervalLEpsRootN(rand.Reader)
-	mu := sample.IntervalLN(rand.Reader)
-	nu := sample.IntervalLN(rand.Reader)
-	sigma := sample.IntervalLN2(rand.Reader)
-	r := sample.IntervalLEpsN2(rand.Reader)
-	x := sample.IntervalLEpsN(rand.Reader)
-	y := sample.IntervalLEpsN(rand.Reader)
+	alpha := sample.IntervalLEpsRootN(random.Reader(), public.N)
+	beta := sample.IntervalLEpsRootN(random.Reader(), public.N)
+	mu := sample.IntervalLN(random.Reader(), public.Aux.N())
+	nu := sample.IntervalLN(random.Reader(), public.Aux.N())
+	sigma := sample.IntervalLN2(random.Reader(), public.Aux.N())
+	r := sample.IntervalLEpsN2(random.Reader(), public.Aux.N())
+	x := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
+	y := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
 
 	pInt := new(saferith.Int).SetNat(private.P)
 	qInt := new(saferith.Int).SetNat(private.Q)
@@ -131,7 +130,7 @@ func (p *Proof) Verify(public Public, hash *hash.Hash) bool {
 	}
 
 	// DEVIATION: for the bounds to work, we add an extra bit, to ensure that we don't have spurious failures.
-	return arith.IsInIntervalLEpsPlus1RootN(p.Z1) && arith.IsInIntervalLEpsPlus1RootN(p.Z2)
+	return arith.IsInIntervalLEpsPlus1RootN(p.Z1, N0) && arith.IsInIntervalLEpsPlus1RootN(p.Z2, N0)
 }
 
 func challenge(hash *hash.Hash, public Public, commitment Commitment) (*saferith.Int, error) {
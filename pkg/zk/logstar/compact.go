@@ -0,0 +1,81 @@
+package zklogstar
+
+import (
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// CompactProof is a Proof in which the commitments A, Y and D are replaced by the challenge e,
+// in the same way as zkenc.CompactProof.
+type CompactProof struct {
+	// S = sˣ tᵘ (mod N)
+	S *saferith.Nat
+	// E = e
+	E *saferith.Int
+	// Z1 = α + e x
+	Z1 *saferith.Int
+	// Z2 = r ρᵉ mod N
+	Z2 *saferith.Nat
+	// Z3 = γ + e μ
+	Z3 *saferith.Int
+}
+
+// NewCompactProof generates the same proof as NewProof, in compact form.
+func NewCompactProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *CompactProof {
+	proof, e := newProof(group, hash, public, private)
+	return &CompactProof{
+		S:  proof.S,
+		E:  e,
+		Z1: proof.Z1,
+		Z2: proof.Z2,
+		Z3: proof.Z3,
+	}
+}
+
+// Verify performs the same checks as Proof.Verify, on the recomputed commitments.
+func (p *CompactProof) Verify(group curve.Curve, hash *hash.Hash, public Public) bool {
+	if p == nil || p.E == nil || p.Z3 == nil {
+		return false
+	}
+	if p.E.TrueLen() > group.ScalarBits() {
+		return false
+	}
+	if !arith.IsValidNatModN(public.Prover.N(), p.Z2) {
+		return false
+	}
+	if !arith.IsValidNatModN(public.Aux.N(), p.S) {
+		return false
+	}
+	if !arith.IsInIntervalLEps(p.Z1) {
+		return false
+	}
+
+	if public.G == nil {
+		public.G = group.NewBasePoint()
+	}
+
+	// Y = [z₁]G - [e]X
+	Y := group.NewScalar().SetNat(p.Z1.Mod(group.Order())).Act(public.G)
+	Y = Y.Sub(group.NewScalar().SetNat(p.E.Mod(group.Order())).Act(public.X))
+	if Y.IsIdentity() {
+		return false
+	}
+
+	prover := public.Prover
+	commitment := &Commitment{
+		S: p.S,
+		// A = Enc(z₁;z₂) ⊖ (e ⊙ C)
+		A: prover.EncWithNonce(p.Z1, p.Z2).Sub(prover, public.C.Clone().Mul(prover, p.E)),
+		Y: Y,
+		// D = sᶻ¹ tᶻ³ S⁻ᵉ
+		D: public.Aux.Recover(p.Z1, p.Z3, p.E, p.S),
+	}
+
+	e, err := challenge(hash, group, public, commitment)
+	if err != nil {
+		return false
+	}
+	return e.Eq(p.E) == 1
+}
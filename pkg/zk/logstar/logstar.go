@@ -1,15 +1,15 @@
 package zklogstar
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 )
 
 type Public struct {
@@ -75,6 +75,12 @@ func (p *Proof) IsValid(public Public) bool {
 }
 
 func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
+	proof, _ := newProof(group, hash, public, private)
+	return proof
+}
+
+// newProof returns the proof, and its challenge e.
+func newProof(group curve.Curve, hash *hash.Hash, public Public, private Private) (*Proof, *saferith.Int) {
 	N := public.Prover.N()
 	NModulus := public.Prover.Modulus()
 
@@ -82,10 +88,10 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 		public.G = group.NewBasePoint()
 	}
 
-	alpha := sample.IntervalLEps(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
-	mu := sample.IntervalLN(rand.Reader)
-	gamma := sample.IntervalLEpsN(rand.Reader)
+	alpha := sample.IntervalLEps(random.Reader())
+	r := sample.UnitModN(random.Reader(), N)
+	mu := sample.IntervalLN(random.Reader(), public.Aux.N())
+	gamma := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
 
 	commitment := &Commitment{
 		A: public.Prover.EncWithNonce(alpha, r),
@@ -113,31 +119,69 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 		Z1:         z1,
 		Z2:         z2,
 		Z3:         z3,
-	}
+	}, e
 }
 
 func (p *Proof) Verify(hash *hash.Hash, public Public) bool {
-	if !p.IsValid(public) {
+	e, ok := p.verify(hash, public)
+	if !ok {
 		return false
 	}
+	return public.Aux.Verify(p.Z1, p.Z3, e, p.D, p.S)
+}
+
+// VerifyBatch returns true if every proof is valid for the corresponding hash and public statement.
+//
+// As in zkenc.VerifyBatch, the checks over the Paillier moduli and the curve are performed separately,
+// in parallel using pl, and only the checks over the Pedersen parameters are combined.
+func VerifyBatch(hashes []*hash.Hash, publics []Public, proofs []*Proof, pl *pool.Pool) bool {
+	n := len(proofs)
+	if len(hashes) != n || len(publics) != n {
+		return false
+	}
+	challenges := pl.Parallelize(n, func(i int) interface{} {
+		if e, ok := proofs[i].verify(hashes[i], publics[i]); ok {
+			return e
+		}
+		return nil
+	})
+	equations := make([]pedersen.Equation, 0, n)
+	for i, e := range challenges {
+		if e == nil {
+			return false
+		}
+		equations = append(equations, pedersen.Equation{
+			Params: publics[i].Aux,
+			A:      proofs[i].Z1,
+			B:      proofs[i].Z3,
+			E:      e.(*saferith.Int),
+			S:      proofs[i].D,
+			T:      proofs[i].S,
+		})
+	}
+	return pedersen.VerifyBatch(equations)
+}
+
+// verify performs all checks of Verify, except for the one over the Pedersen parameters,
+// and returns the challenge.
+func (p *Proof) verify(hash *hash.Hash, public Public) (*saferith.Int, bool) {
+	if !p.IsValid(public) {
+		return nil, false
+	}
 
 	if public.G == nil {
 		public.G = p.group.NewBasePoint()
 	}
 
 	if !arith.IsInIntervalLEps(p.Z1) {
-		return false
+		return nil, false
 	}
 
 	prover := public.Prover
 
 	e, err := challenge(hash, p.group, public, p.Commitment)
 	if err != nil {
-		return false
-	}
-
-	if !public.Aux.Verify(p.Z1, p.Z3, e, p.D, p.S) {
-		return false
+		return nil, false
 	}
 
 	{
@@ -147,7 +191,7 @@ func (p *Proof) Verify(hash *hash.Hash, public Public) bool {
 		// rhs = (e ⊙ C) ⊕ A
 		rhs := public.C.Clone().Mul(prover, e).Add(prover, p.A)
 		if !lhs.Equal(rhs) {
-			return false
+			return nil, false
 		}
 	}
 
@@ -160,12 +204,12 @@ func (p *Proof) Verify(hash *hash.Hash, public Public) bool {
 		rhs = rhs.Add(p.Y)
 
 		if !lhs.Equal(rhs) {
-			return false
+			return nil, false
 		}
 
 	}
 
-	return true
+	return e, true
 }
 
 func challenge(hash *hash.Hash, group curve.Curve, public Public, commitment *Commitment) (e *saferith.Int, err error) {
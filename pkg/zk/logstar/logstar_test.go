@@ -49,3 +49,28 @@ func TestLogStar(t *testing.T) {
 
 	assert.True(t, proof3.Verify(hash.New(), public))
 }
+
+func TestLogStarCompact(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	x := sample.IntervalL(rand.Reader)
+	C, rho := zk.ProverPaillierPublic.Enc(x)
+	public := Public{
+		C:      C,
+		X:      group.NewScalar().SetNat(x.Mod(group.Order())).ActOnBase(),
+		Prover: zk.ProverPaillierPublic,
+		Aux:    zk.Pedersen,
+	}
+
+	proof := NewCompactProof(group, hash.New(), public, Private{X: x, Rho: rho})
+	assert.True(t, proof.Verify(group, hash.New(), public))
+
+	out, err := cbor.Marshal(proof)
+	require.NoError(t, err, "failed to marshal proof")
+	decoded := &CompactProof{}
+	require.NoError(t, cbor.Unmarshal(out, decoded), "failed to unmarshal proof")
+	assert.True(t, decoded.Verify(group, hash.New(), public))
+
+	public.X = sample.Scalar(rand.Reader, group).ActOnBase()
+	assert.False(t, proof.Verify(group, hash.New(), public), "proof is for another point")
+}
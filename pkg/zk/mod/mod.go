@@ -1,11 +1,12 @@
 package zkmod
 
 import (
-	"crypto/rand"
+	"io"
 	"math/big"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -15,6 +16,10 @@ import (
 type Public struct {
 	// N = p*q
 	N *saferith.Modulus
+	// Iterations is the number of repetitions of the proof, which determines its soundness.
+	// 0 means params.StatParam, other values are clamped by params.StatIterations.
+	// The prover and the verifier must use the same value.
+	Iterations int
 }
 
 type Private struct {
@@ -36,7 +41,7 @@ type Response struct {
 
 type Proof struct {
 	W         *big.Int
-	Responses [params.StatParam]Response
+	Responses []Response
 }
 
 // isQRModPQ checks that y is a quadratic residue mod both p and q.
@@ -154,40 +159,17 @@ func (p *Proof) IsValid(public Public) bool {
 //   - a, b s.t. y' = (-1)ᵃ wᵇ y
 //   - R = [(xᵢ aᵢ, bᵢ), zᵢ] for i = 1, …, m
 func NewProof(hash *hash.Hash, private Private, public Public, pl *pool.Pool) *Proof {
-	n, p, q, phi := public.N, private.P, private.Q, private.Phi
-	nModulus := arith.ModulusFromFactors(p, q)
-	pHalf := new(saferith.Nat).Rsh(p, 1, -1)
-	pMod := saferith.ModulusFromNat(p)
-	qHalf := new(saferith.Nat).Rsh(q, 1, -1)
-	qMod := saferith.ModulusFromNat(q)
-	phiMod := saferith.ModulusFromNat(phi)
+	n := public.N
+	pr := newProver(private, n)
 	// W can be leaked so no need to make this sampling return a nat.
-	w := sample.QNR(rand.Reader, n)
-
-	nInverse := new(saferith.Nat).ModInverse(n.Nat(), phiMod)
-
-	e := fourthRootExponent(phi)
-
-	ys, _ := challenge(hash, n, w.Big())
-
-	var rs [params.StatParam]Response
-	pl.Parallelize(params.StatParam, func(i int) interface{} {
-		y := ys[i]
-
-		// Z = y^{n⁻¹ (mod n)}
-		z := nModulus.Exp(y, nInverse)
+	w := sample.QNR(random.Reader(), n)
 
-		a, b, yPrime := makeQuadraticResidue(y, w, pHalf, qHalf, n, pMod, qMod)
-		// X = (y')¹/4
-		x := nModulus.Exp(yPrime, e)
-
-		rs[i] = Response{
-			A: a,
-			B: b,
-			X: x.Big(),
-			Z: z.Big(),
-		}
+	iterations := params.StatIterations(public.Iterations)
+	ys, _ := challenge(hash, n, w.Big(), iterations)
 
+	rs := make([]Response, iterations)
+	pl.Parallelize(iterations, func(i int) interface{} {
+		rs[i] = pr.respond(ys[i], w)
 		return nil
 	})
 
@@ -197,6 +179,46 @@ func NewProof(hash *hash.Hash, private Private, public Public, pl *pool.Pool) *P
 	}
 }
 
+// prover holds the values derived from the factorization of N, which are shared by all responses.
+type prover struct {
+	n, p, q      *saferith.Modulus
+	nCRT         *arith.Modulus
+	pHalf, qHalf *saferith.Nat
+	nInverse, e  *saferith.Nat
+}
+
+func newProver(private Private, n *saferith.Modulus) *prover {
+	p, q, phi := private.P, private.Q, private.Phi
+	phiMod := saferith.ModulusFromNat(phi)
+	return &prover{
+		n:        n,
+		nCRT:     arith.ModulusFromFactors(p, q),
+		p:        saferith.ModulusFromNat(p),
+		q:        saferith.ModulusFromNat(q),
+		pHalf:    new(saferith.Nat).Rsh(p, 1, -1),
+		qHalf:    new(saferith.Nat).Rsh(q, 1, -1),
+		nInverse: new(saferith.Nat).ModInverse(n.Nat(), phiMod),
+		e:        fourthRootExponent(phi),
+	}
+}
+
+// respond returns the response to the challenge y, for the quadratic non residue w.
+func (pr *prover) respond(y, w *saferith.Nat) Response {
+	// Z = y^{n⁻¹ (mod n)}
+	z := pr.nCRT.Exp(y, pr.nInverse)
+
+	a, b, yPrime := makeQuadraticResidue(y, w, pr.pHalf, pr.qHalf, pr.n, pr.p, pr.q)
+	// X = (y')¹/4
+	x := pr.nCRT.Exp(yPrime, pr.e)
+
+	return Response{
+		A: a,
+		B: b,
+		X: x.Big(),
+		Z: z.Big(),
+	}
+}
+
 func (r *Response) Verify(n, w, y *big.Int) bool {
 	var lhs, rhs big.Int
 
@@ -228,6 +250,10 @@ func (p *Proof) Verify(public Public, hash *hash.Hash, pl *pool.Pool) bool {
 	if p == nil {
 		return false
 	}
+	iterations := params.StatIterations(public.Iterations)
+	if len(p.Responses) != iterations {
+		return false
+	}
 	n := public.N.Big()
 	nMod := public.N
 	// check if n is odd and prime
@@ -244,11 +270,11 @@ func (p *Proof) Verify(public Public, hash *hash.Hash, pl *pool.Pool) bool {
 	}
 
 	// get [yᵢ] <- ℤₙ
-	ys, err := challenge(hash, nMod, p.W)
+	ys, err := challenge(hash, nMod, p.W, iterations)
 	if err != nil {
 		return false
 	}
-	verifications := pl.Parallelize(params.StatParam, func(i int) interface{} {
+	verifications := pl.Parallelize(iterations, func(i int) interface{} {
 		return p.Responses[i].Verify(n, p.W, ys[i].Big())
 	})
 	for i := 0; i < len(verifications); i++ {
@@ -259,12 +285,17 @@ func (p *Proof) Verify(public Public, hash *hash.Hash, pl *pool.Pool) bool {
 	return true
 }
 
-func challenge(hash *hash.Hash, n *saferith.Modulus, w *big.Int) (es []*saferith.Nat, err error) {
-	err = hash.WriteAny(n, w)
-	es = make([]*saferith.Nat, params.StatParam)
-	var digest = hash.Digest()
+func challenge(hash *hash.Hash, n *saferith.Modulus, w *big.Int, iterations int) (es []*saferith.Nat, err error) {
+	digest, err := challengeDigest(hash, n, w)
+	es = make([]*saferith.Nat, iterations)
 	for i := range es {
 		es[i] = sample.ModN(digest, n)
 	}
 	return
 }
+
+// challengeDigest returns the stream from which the challenges yᵢ are sampled, in order.
+func challengeDigest(hash *hash.Hash, n *saferith.Modulus, w *big.Int) (io.Reader, error) {
+	err := hash.WriteAny(n, w)
+	return hash.Digest(), err
+}
@@ -1,6 +1,7 @@
 package zkmod
 
 import (
+	"bytes"
 	"crypto/rand"
 	"math/big"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/params"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -50,6 +52,50 @@ func TestMod(t *testing.T) {
 	assert.False(t, proof.Verify(public, hash.New(), pl), "proof should have failed")
 }
 
+func TestModIterations(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	sk := zk.ProverPaillierSecret
+	private := Private{P: sk.P(), Q: sk.Q(), Phi: sk.Phi()}
+	public := Public{N: sk.PublicKey.N(), Iterations: 128}
+	proof := NewProof(hash.New(), private, public, pl)
+	assert.Len(t, proof.Responses, 128)
+	assert.True(t, proof.Verify(public, hash.New(), pl))
+	assert.False(t, proof.Verify(Public{N: sk.PublicKey.N()}, hash.New(), pl), "the verifier expects a different number of iterations")
+
+	// a number of iterations below the floor is raised to it
+	weak := NewProof(hash.New(), private, Public{N: sk.PublicKey.N(), Iterations: 10}, pl)
+	assert.Len(t, weak.Responses, params.MinStatParam)
+	assert.True(t, weak.Verify(Public{N: sk.PublicKey.N()}, hash.New(), pl))
+}
+
+func TestModStream(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	sk := zk.ProverPaillierSecret
+	private := Private{P: sk.P(), Q: sk.Q(), Phi: sk.Phi()}
+	public := Public{N: sk.PublicKey.N()}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteProof(&buf, hash.New(), private, public))
+	streamed := buf.Bytes()
+	assert.True(t, VerifyStream(public, hash.New(), bytes.NewReader(streamed)))
+	assert.False(t, VerifyStream(public, hash.New(), bytes.NewReader(streamed[:len(streamed)-1])), "truncated proof should fail")
+	assert.False(t, VerifyStream(public, hash.New(&hash.BytesWithDomain{TheDomain: "other", Bytes: []byte{1}}), bytes.NewReader(streamed)), "different hash state should fail")
+
+	proof := NewProof(hash.New(), private, public, pl)
+	buf.Reset()
+	require.NoError(t, proof.Encode(&buf))
+	assert.True(t, VerifyStream(public, hash.New(), &buf))
+
+	proof.Responses[len(proof.Responses)-1].A = !proof.Responses[len(proof.Responses)-1].A
+	buf.Reset()
+	require.NoError(t, proof.Encode(&buf))
+	assert.False(t, VerifyStream(public, hash.New(), &buf), "proof should have failed")
+}
+
 func Test_set4thRoot(t *testing.T) {
 	var p, q uint64 = 311, 331
 	pMod := saferith.ModulusFromUint64(p)
@@ -83,7 +129,7 @@ func Test_hashFix(t *testing.T) {
 	N := zk.ProverPaillierSecret.N()
 	w := sample.QNR(rand.Reader, N).Big()
 	h := hash.New()
-	es, err := challenge(h, N, w)
+	es, err := challenge(h, N, w, params.StatParam)
 	assert.NoError(t, err, "failed to compute challenge")
 
 	allEqual := true
@@ -107,7 +153,7 @@ func BenchmarkCRT(b *testing.B) {
 	ped, _ := sk.GeneratePedersen()
 
 	public := Public{
-		ped.N(),
+		N: ped.N(),
 	}
 
 	private := Private{
@@ -0,0 +1,97 @@
+package zkmod
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+// WriteProof generates the same proof as NewProof, and writes it to w as a sequence of CBOR items:
+// W, followed by each response.
+//
+// Each response is written as soon as it is computed, so that only one is held in memory at a time.
+// The number of responses isn't encoded, the verifier derives it from public.Iterations.
+func WriteProof(w io.Writer, hash *hash.Hash, private Private, public Public) error {
+	n := public.N
+	pr := newProver(private, n)
+	// W can be leaked so no need to make this sampling return a nat.
+	qnr := sample.QNR(random.Reader(), n)
+
+	enc := cbor.NewEncoder(w)
+	if err := enc.Encode(qnr.Big()); err != nil {
+		return err
+	}
+	digest, err := challengeDigest(hash, n, qnr.Big())
+	if err != nil {
+		return err
+	}
+	for i := 0; i < params.StatIterations(public.Iterations); i++ {
+		r := pr.respond(sample.ModN(digest, n), qnr)
+		if err = enc.Encode(&r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encode writes p to w in the format of WriteProof.
+func (p *Proof) Encode(w io.Writer) error {
+	enc := cbor.NewEncoder(w)
+	if err := enc.Encode(p.W); err != nil {
+		return err
+	}
+	for i := range p.Responses {
+		if err := enc.Encode(&p.Responses[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyStream verifies a proof written by WriteProof or Encode, reading one response at a time from r.
+//
+// It returns false as soon as a response is invalid, or r doesn't contain enough of them.
+// Data following the proof may be consumed from r, since it is read through a buffer.
+func VerifyStream(public Public, hash *hash.Hash, r io.Reader) bool {
+	n := public.N.Big()
+	nMod := public.N
+	// check if n is odd and prime
+	if n.Bit(0) == 0 || n.ProbablyPrime(20) {
+		return false
+	}
+
+	dec := cbor.NewDecoder(r)
+	var w big.Int
+	if err := dec.Decode(&w); err != nil {
+		return false
+	}
+	if big.Jacobi(&w, n) != -1 || !arith.IsValidBigModN(n, &w) {
+		return false
+	}
+
+	// get [yᵢ] <- ℤₙ, one at a time
+	digest, err := challengeDigest(hash, nMod, &w)
+	if err != nil {
+		return false
+	}
+	for i := 0; i < params.StatIterations(public.Iterations); i++ {
+		var response Response
+		if err = dec.Decode(&response); err != nil {
+			return false
+		}
+		if !arith.IsValidBigModN(n, response.X, response.Z) {
+			return false
+		}
+		y := sample.ModN(digest, nMod)
+		if !response.Verify(n, &w, y.Big()) {
+			return false
+		}
+	}
+	return true
+}
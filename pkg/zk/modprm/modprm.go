@@ -0,0 +1,83 @@
+// Package zkmodprm proves that a Paillier public key and Pedersen parameters were correctly generated,
+// by combining the proofs of zkmod and zkprm over their common modulus, as the cmp keygen does.
+//
+// It allows a party to publish its auxiliary parameters with a proof, and anyone to audit them,
+// outside of a protocol execution. The hash passed to NewProof and Verify should contain a context,
+// such as the identity of the party and the purpose of the proof, so that it can't be replayed elsewhere.
+package zkmodprm
+
+import (
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	zkmod "github.com/taurusgroup/multi-party-sig/pkg/zk/mod"
+	zkprm "github.com/taurusgroup/multi-party-sig/pkg/zk/prm"
+)
+
+type Public struct {
+	// Paillier is the public key, with modulus N.
+	Paillier *paillier.PublicKey
+	// Aux are the Pedersen parameters (N, s, t), over the same modulus.
+	Aux *pedersen.Parameters
+	// Iterations is the number of repetitions of both proofs, see zkmod.Public.
+	Iterations int
+}
+
+type Private struct {
+	// Paillier is the secret key of Public.Paillier.
+	Paillier *paillier.SecretKey
+	// Lambda = logₜ(s) mod ϕ(N).
+	Lambda *saferith.Nat
+}
+
+type Proof struct {
+	// Mod proves that N is the product of two primes congruent to 3 mod 4.
+	Mod *zkmod.Proof
+	// Prm proves that s is in the subgroup of ℤₙˣ generated by t.
+	Prm *zkprm.Proof
+}
+
+// IsValid returns true if the public statement is well formed.
+func (p *Proof) IsValid(public Public) bool {
+	if p == nil || p.Mod == nil || p.Prm == nil {
+		return false
+	}
+	if public.Paillier == nil || public.Aux == nil {
+		return false
+	}
+	if err := paillier.ValidateN(public.Paillier.N()); err != nil {
+		return false
+	}
+	_, eq, _ := public.Paillier.N().Cmp(public.Aux.N())
+	return eq == 1
+}
+
+// NewProof proves that public.Paillier and public.Aux were generated from the primes of private.Paillier.
+func NewProof(private Private, hash *hash.Hash, public Public, pl *pool.Pool) *Proof {
+	sk := private.Paillier
+	mod := zkmod.NewProof(hash.Clone(), zkmod.Private{
+		P:   sk.P(),
+		Q:   sk.Q(),
+		Phi: sk.Phi(),
+	}, zkmod.Public{N: public.Paillier.N(), Iterations: public.Iterations}, pl)
+	prm := zkprm.NewProof(zkprm.Private{
+		Lambda: private.Lambda,
+		Phi:    sk.Phi(),
+		P:      sk.P(),
+		Q:      sk.Q(),
+	}, hash.Clone(), zkprm.Public{Aux: public.Aux, Iterations: public.Iterations}, pl)
+	return &Proof{Mod: mod, Prm: prm}
+}
+
+// Verify returns true if both proofs are valid for the same modulus N, with the given hash.
+func (p *Proof) Verify(public Public, hash *hash.Hash, pl *pool.Pool) bool {
+	if !p.IsValid(public) {
+		return false
+	}
+	if !p.Mod.Verify(zkmod.Public{N: public.Paillier.N(), Iterations: public.Iterations}, hash.Clone(), pl) {
+		return false
+	}
+	return p.Prm.Verify(zkprm.Public{Aux: public.Aux, Iterations: public.Iterations}, hash.Clone(), pl)
+}
@@ -0,0 +1,42 @@
+package zkmodprm
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/zk"
+)
+
+func TestModPrm(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	sk := zk.VerifierPaillierSecret
+	aux, lambda := sk.GeneratePedersen()
+	public := Public{Paillier: sk.PublicKey, Aux: aux}
+	context := func() *hash.Hash {
+		h := hash.New()
+		_ = h.WriteAny([]byte("audit of party a"))
+		return h
+	}
+
+	proof := NewProof(Private{Paillier: sk, Lambda: lambda}, context(), public, pl)
+	assert.True(t, proof.Verify(public, context(), pl))
+	assert.False(t, proof.Verify(public, hash.New(), pl), "the proof must be bound to its context")
+
+	out, err := cbor.Marshal(proof)
+	require.NoError(t, err, "failed to marshal proof")
+	proof2 := &Proof{}
+	require.NoError(t, cbor.Unmarshal(out, proof2), "failed to unmarshal proof")
+	assert.True(t, proof2.Verify(public, context(), pl))
+
+	// the Pedersen parameters must be over the modulus of the Paillier key
+	otherAux, _ := zk.ProverPaillierSecret.GeneratePedersen()
+	assert.False(t, proof.Verify(Public{Paillier: sk.PublicKey, Aux: otherAux}, context(), pl))
+	assert.False(t, proof.Verify(Public{Paillier: paillier.NewPublicKey(otherAux.N()), Aux: otherAux}, context(), pl))
+}
@@ -1,9 +1,8 @@
 package zkmul
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -72,9 +71,9 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 
 	prover := public.Prover
 
-	alpha := sample.IntervalLEps(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
-	s := sample.UnitModN(rand.Reader, N)
+	alpha := sample.IntervalLEps(random.Reader())
+	r := sample.UnitModN(random.Reader(), N)
+	s := sample.UnitModN(random.Reader(), N)
 
 	A := public.Y.Clone().Mul(prover, alpha)
 	A.Randomize(prover, r)
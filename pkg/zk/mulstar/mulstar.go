@@ -1,9 +1,8 @@
 package zkmulstar
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -79,12 +78,12 @@ func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private
 
 	verifier := public.Verifier
 
-	alpha := sample.IntervalLEps(rand.Reader)
+	alpha := sample.IntervalLEps(random.Reader())
 
-	r := sample.UnitModN(rand.Reader, N0)
+	r := sample.UnitModN(random.Reader(), N0)
 
-	gamma := sample.IntervalLEpsN(rand.Reader)
-	m := sample.IntervalLEpsN(rand.Reader)
+	gamma := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
+	m := sample.IntervalLEpsN(random.Reader(), public.Aux.N())
 
 	A := public.C.Clone().Mul(verifier, alpha)
 	A.Randomize(verifier, r)
@@ -3,9 +3,8 @@
 package zknth
 
 import (
-	"crypto/rand"
-
 	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -52,7 +51,7 @@ func (p *Proof) IsValid(public Public) bool {
 func NewProof(hash *hash.Hash, public Public, private Private) *Proof {
 	N := public.N.N()
 	// α ← ℤₙˣ
-	alpha := sample.UnitModN(rand.Reader, N)
+	alpha := sample.UnitModN(random.Reader(), N)
 	// A = αⁿ (mod n²)
 	A := public.N.ModulusSquared().Exp(alpha, N.Nat())
 	commitment := Commitment{
@@ -1,12 +1,12 @@
 package zkprm
 
 import (
-	"crypto/rand"
 	"io"
 	"math/big"
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -16,21 +16,27 @@ import (
 
 type Public struct {
 	Aux *pedersen.Parameters
+	// Iterations is the number of challenges, with the same semantics as zkmod.Public.Iterations.
+	Iterations int
 }
 type Private struct {
 	Lambda, Phi, P, Q *saferith.Nat
 }
 
 type Proof struct {
-	As, Zs [params.StatParam]*big.Int
+	As, Zs []*big.Int
 }
 
 func (p *Proof) IsValid(public Public) bool {
 	if p == nil {
 		return false
 	}
+	iterations := params.StatIterations(public.Iterations)
+	if len(p.As) != iterations || len(p.Zs) != iterations {
+		return false
+	}
 
-	if !arith.IsValidBigModN(public.Aux.N().Big(), append(p.As[:], p.Zs[:]...)...) {
+	if !arith.IsValidBigModN(public.Aux.N().Big(), append(append([]*big.Int{}, p.As...), p.Zs...)...) {
 		return false
 	}
 	return true
@@ -44,12 +50,11 @@ func NewProof(private Private, hash *hash.Hash, public Public, pl *pool.Pool) *P
 
 	n := arith.ModulusFromFactors(private.P, private.Q)
 
-	var (
-		as [params.StatParam]*saferith.Nat
-		As [params.StatParam]*big.Int
-	)
-	lockedRand := pool.NewLockedReader(rand.Reader)
-	pl.Parallelize(params.StatParam, func(i int) interface{} {
+	iterations := params.StatIterations(public.Iterations)
+	as := make([]*saferith.Nat, iterations)
+	As := make([]*big.Int, iterations)
+	lockedRand := pool.NewLockedReader(random.Reader())
+	pl.Parallelize(iterations, func(i int) interface{} {
 		// aᵢ ∈ mod ϕ(N)
 		as[i] = sample.ModN(lockedRand, phi)
 
@@ -61,8 +66,8 @@ func NewProof(private Private, hash *hash.Hash, public Public, pl *pool.Pool) *P
 
 	es, _ := challenge(hash, public, As)
 	// Modular addition is not expensive enough to warrant parallelizing
-	var Zs [params.StatParam]*big.Int
-	for i := 0; i < params.StatParam; i++ {
+	Zs := make([]*big.Int, iterations)
+	for i := 0; i < iterations; i++ {
 		z := as[i]
 		// The challenge is public, so branching is ok
 		if es[i] {
@@ -81,6 +86,10 @@ func (p *Proof) Verify(public Public, hash *hash.Hash, pl *pool.Pool) bool {
 	if p == nil {
 		return false
 	}
+	iterations := params.StatIterations(public.Iterations)
+	if len(p.As) != iterations || len(p.Zs) != iterations {
+		return false
+	}
 	if err := pedersen.ValidateParameters(public.Aux.N(), public.Aux.S(), public.Aux.T()); err != nil {
 		return false
 	}
@@ -93,7 +102,7 @@ func (p *Proof) Verify(public Public, hash *hash.Hash, pl *pool.Pool) bool {
 	}
 
 	one := big.NewInt(1)
-	verifications := pl.Parallelize(params.StatParam, func(i int) interface{} {
+	verifications := pl.Parallelize(iterations, func(i int) interface{} {
 		var lhs, rhs big.Int
 		z := p.Zs[i]
 		a := p.As[i]
@@ -129,20 +138,24 @@ func (p *Proof) Verify(public Public, hash *hash.Hash, pl *pool.Pool) bool {
 	return true
 }
 
-func challenge(hash *hash.Hash, public Public, A [params.StatParam]*big.Int) (es []bool, err error) {
+func challenge(hash *hash.Hash, public Public, A []*big.Int) (es []bool, err error) {
 	err = hash.WriteAny(public.Aux)
 	for _, a := range A {
 		_ = hash.WriteAny(a)
 	}
+	return challengeBits(hash, len(A)), err
+}
 
-	tmpBytes := make([]byte, params.StatParam)
+// challengeBits returns the iterations challenge bits, once the hash contains the commitments.
+func challengeBits(hash *hash.Hash, iterations int) []bool {
+	tmpBytes := make([]byte, iterations)
 	_, _ = io.ReadFull(hash.Digest(), tmpBytes)
 
-	es = make([]bool, params.StatParam)
+	es := make([]bool, iterations)
 	for i := range es {
 		b := (tmpBytes[i] & 1) == 1
 		es[i] = b
 	}
 
-	return
+	return es
 }
@@ -1,6 +1,7 @@
 package zkprm
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/fxamacker/cbor/v2"
@@ -42,6 +43,34 @@ func TestPrm(t *testing.T) {
 	assert.True(t, proof3.Verify(public, hash.New(), pl))
 }
 
+func TestPrmStream(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	sk := paillier.NewSecretKey(pl)
+	ped, lambda := sk.GeneratePedersen()
+	public := Public{Aux: ped}
+	private := Private{Lambda: lambda, Phi: sk.Phi(), P: sk.P(), Q: sk.Q()}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteProof(&buf, private, hash.New(), public))
+	streamed := buf.Bytes()
+	assert.True(t, VerifyStream(public, hash.New(), bytes.NewReader(streamed)))
+	assert.False(t, VerifyStream(public, hash.New(), bytes.NewReader(streamed[:len(streamed)-1])), "truncated proof should fail")
+	assert.False(t, VerifyStream(Public{Aux: ped, Iterations: 100}, hash.New(), bytes.NewReader(streamed)), "verifier expects more iterations")
+
+	// a proof from NewProof verifies in the streaming format, and the other way around
+	proof := NewProof(private, hash.New(), public, pl)
+	buf.Reset()
+	require.NoError(t, proof.Encode(&buf))
+	assert.True(t, VerifyStream(public, hash.New(), &buf))
+
+	proof.Zs[0] = proof.Zs[1]
+	buf.Reset()
+	require.NoError(t, proof.Encode(&buf))
+	assert.False(t, VerifyStream(public, hash.New(), &buf), "proof should have failed")
+}
+
 var p *Proof
 
 func BenchmarkCRT(b *testing.B) {
@@ -0,0 +1,128 @@
+package zkprm
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+)
+
+// WriteProof generates a proof with the same statement as NewProof, and writes it to w
+// as a sequence of CBOR items: each Aᵢ, followed by each Zᵢ.
+//
+// The commitments Aᵢ are written as they are computed, so that only the secret aᵢ are kept
+// until the challenge is known.
+func WriteProof(w io.Writer, private Private, hash *hash.Hash, public Public) error {
+	lambda := private.Lambda
+	phi := saferith.ModulusFromNat(private.Phi)
+
+	n := arith.ModulusFromFactors(private.P, private.Q)
+
+	enc := cbor.NewEncoder(w)
+	if err := hash.WriteAny(public.Aux); err != nil {
+		return err
+	}
+	iterations := params.StatIterations(public.Iterations)
+	as := make([]*saferith.Nat, iterations)
+	rand := random.Reader()
+	for i := range as {
+		// aᵢ ∈ mod ϕ(N)
+		as[i] = sample.ModN(rand, phi)
+
+		// Aᵢ = tᵃ mod N
+		A := n.Exp(public.Aux.T(), as[i]).Big()
+		_ = hash.WriteAny(A)
+		if err := enc.Encode(A); err != nil {
+			return err
+		}
+	}
+
+	es := challengeBits(hash, iterations)
+	for i, z := range as {
+		// The challenge is public, so branching is ok
+		if es[i] {
+			z.ModAdd(z, lambda, phi)
+		}
+		if err := enc.Encode(z.Big()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encode writes p to w in the format of WriteProof.
+func (p *Proof) Encode(w io.Writer) error {
+	enc := cbor.NewEncoder(w)
+	for _, a := range p.As {
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+	}
+	for _, z := range p.Zs {
+		if err := enc.Encode(z); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyStream verifies a proof written by WriteProof or Encode, reading it from r.
+//
+// The challenge depends on all the Aᵢ, so these are kept until the end,
+// but each Zᵢ is checked as soon as it is read.
+// Data following the proof may be consumed from r, since it is read through a buffer.
+func VerifyStream(public Public, hash *hash.Hash, r io.Reader) bool {
+	if err := pedersen.ValidateParameters(public.Aux.N(), public.Aux.S(), public.Aux.T()); err != nil {
+		return false
+	}
+	n, s, t := public.Aux.N().Big(), public.Aux.S().Big(), public.Aux.T().Big()
+	if err := hash.WriteAny(public.Aux); err != nil {
+		return false
+	}
+
+	dec := cbor.NewDecoder(r)
+	one := big.NewInt(1)
+	iterations := params.StatIterations(public.Iterations)
+	As := make([]*big.Int, iterations)
+	for i := range As {
+		a := new(big.Int)
+		if err := dec.Decode(a); err != nil {
+			return false
+		}
+		if !arith.IsValidBigModN(n, a) || a.Cmp(one) == 0 {
+			return false
+		}
+		_ = hash.WriteAny(a)
+		As[i] = a
+	}
+
+	es := challengeBits(hash, iterations)
+	var z, lhs, rhs big.Int
+	for i, a := range As {
+		if err := dec.Decode(&z); err != nil {
+			return false
+		}
+		if !arith.IsValidBigModN(n, &z) {
+			return false
+		}
+
+		lhs.Exp(t, &z, n)
+		if es[i] {
+			rhs.Mul(a, s)
+			rhs.Mod(&rhs, n)
+		} else {
+			rhs.Set(a)
+		}
+		if lhs.Cmp(&rhs) != 0 {
+			return false
+		}
+	}
+	return true
+}
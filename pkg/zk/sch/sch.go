@@ -1,9 +1,9 @@
 package zksch
 
 import (
-	"crypto/rand"
 	"io"
 
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -35,7 +35,7 @@ type Proof struct {
 func NewProof(hash *hash.Hash, public curve.Point, private curve.Scalar, gen curve.Point) *Proof {
 	group := private.Curve()
 
-	a := NewRandomness(rand.Reader, group, gen)
+	a := NewRandomness(random.Reader(), group, gen)
 	z := a.Prove(hash, public, private, gen)
 	return &Proof{
 		C: *a.Commitment(),
@@ -0,0 +1,47 @@
+// Package bls implements threshold BLS signatures over BLS12-381.
+//
+// Public keys and verification shares live in G₁, and signatures in G₂,
+// which is the variant used by Ethereum's consensus layer and by drand.
+//
+// The distributed key generation is the one used by FROST, run over G₁.
+// Once the keys are generated, signing is non-interactive: each signer computes a
+// partial signature on its own, and any party can aggregate threshold + 1 valid
+// partial signatures into a regular BLS signature.
+package bls
+
+import (
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost/keygen"
+)
+
+// Config contains the key share of a participant, as well as the verification shares of all the others.
+type Config = keygen.Config
+
+// EmptyConfig creates an empty Config over BLS12-381.
+//
+// This needs to be called before unmarshalling, instead of just using new(Config).
+func EmptyConfig() *Config {
+	return keygen.EmptyConfig(curve.BLS12381{})
+}
+
+// Keygen initiates the distributed generation of a BLS key.
+//
+// participants is a complete set of parties that will hold a share of the secret key.
+// Future signers must come from this set.
+//
+// threshold is the number of participants that can be corrupted without breaking
+// the security of the protocol. threshold + 1 partial signatures are needed to create a signature.
+//
+// selfID is the identifier for the local party calling this function.
+func Keygen(selfID party.ID, participants []party.ID, threshold int) protocol.StartFunc {
+	return keygen.StartKeygenCommon(false, curve.BLS12381{}, participants, threshold, selfID, nil, nil, nil)
+}
+
+// Refresh allows the participants to refresh their shares of an existing BLS key.
+//
+// The public key stays the same, but the shares and verification shares change.
+func Refresh(config *Config, participants []party.ID) protocol.StartFunc {
+	return keygen.StartKeygenCommon(false, curve.BLS12381{}, participants, config.Threshold, config.ID, config.PrivateShare, config.PublicKey, config.VerificationShares.Points)
+}
@@ -0,0 +1,77 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+func TestBLS(t *testing.T) {
+	N := 4
+	threshold := 2
+	partyIDs := test.PartyIDs(N)
+
+	rounds := make([]round.Session, 0, N)
+	for _, id := range partyIDs {
+		r, err := Keygen(id, partyIDs, threshold)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	configs := make(map[party.ID]*Config, N)
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		c := r.(*round.Output).Result.(*Config)
+		configs[c.ID] = c
+	}
+
+	m := []byte("hello")
+	partials := make([]*PartialSignature, 0, N)
+	for _, id := range partyIDs {
+		p, err := Sign(configs[id], m)
+		require.NoError(t, err)
+		assert.True(t, p.Verify(configs[partyIDs[0]], m), "partial signature should verify")
+		partials = append(partials, p)
+	}
+
+	// any subset of threshold + 1 signers gives the same signature
+	sig1, err := Aggregate(configs[partyIDs[0]], m, partials[:threshold+1])
+	require.NoError(t, err)
+	sig2, err := Aggregate(configs[partyIDs[0]], m, partials[1:])
+	require.NoError(t, err)
+	assert.True(t, sig1.Verify(configs[partyIDs[0]].PublicKey, m))
+	assert.True(t, sig1.value.Equal(&sig2.value))
+	assert.False(t, sig1.Verify(configs[partyIDs[0]].PublicKey, []byte("bye")))
+
+	_, err = Aggregate(configs[partyIDs[0]], m, partials[:threshold])
+	assert.Error(t, err, "not enough partial signatures")
+
+	// a bad share is attributed to its sender
+	bad, err := Sign(configs[partyIDs[1]], []byte("bye"))
+	require.NoError(t, err)
+	_, err = Aggregate(configs[partyIDs[0]], m, []*PartialSignature{partials[0], bad, partials[2]})
+	assert.ErrorContains(t, err, string(partyIDs[1]))
+
+	data, err := cbor.Marshal(sig1)
+	require.NoError(t, err)
+	sig3 := new(Signature)
+	require.NoError(t, cbor.Unmarshal(data, sig3))
+	assert.True(t, sig3.Verify(configs[partyIDs[0]].PublicKey, m))
+
+	data, err = cbor.Marshal(configs[partyIDs[0]])
+	require.NoError(t, err)
+	c := EmptyConfig()
+	require.NoError(t, cbor.Unmarshal(data, c))
+	assert.True(t, c.PublicKey.Equal(configs[partyIDs[0]].PublicKey))
+}
@@ -0,0 +1,129 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// DST is the domain separation tag used to hash messages to G₂.
+//
+// It is the one defined for the proof of possession scheme of the IETF BLS signature draft,
+// which is also used by Ethereum.
+const DST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+
+// group provides the pairing, with public keys in G₁ and signatures in G₂.
+var group curve.Pairing = curve.BLS12381{}
+
+// Signature is a BLS signature, which is a point in G₂.
+type Signature struct {
+	value curve.BLS12381G2Point
+}
+
+// PartialSignature is the share of a signature produced by a single participant.
+type PartialSignature struct {
+	// ID is the participant who produced this partial signature.
+	ID party.ID
+	// Signature = sᵢ•H(m), where sᵢ is the private share of ID.
+	Signature Signature
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, using the compressed encoding of the point.
+func (s *Signature) MarshalBinary() ([]byte, error) {
+	return s.value.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, and checks that the point lies in G₂.
+func (s *Signature) UnmarshalBinary(data []byte) error {
+	if err := s.value.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("bls.Signature: %w", err)
+	}
+	return nil
+}
+
+// Verify checks that the signature is valid for m under the given public key,
+// with e(Y, H(m)) = e(G, σ).
+func (s *Signature) Verify(public curve.Point, m []byte) bool {
+	if _, ok := public.(*curve.BLS12381Point); !ok || public.IsIdentity() {
+		return false
+	}
+	G := group.NewBasePoint()
+	// e(Y, H(m))⋅e(-G, σ) = 1
+	return group.MultiPair(
+		[]curve.Point{public, G.Negate()},
+		[]curve.Point{hashToG2(m), &s.value},
+	).IsIdentity()
+}
+
+// Sign produces the partial signature of this participant on m.
+//
+// Signing is non-interactive, and the partial signatures of threshold + 1 participants
+// can be combined with Aggregate.
+func Sign(config *Config, m []byte) (*PartialSignature, error) {
+	if _, ok := config.PrivateShare.(*curve.BLS12381Scalar); !ok {
+		return nil, errors.New("bls.Sign: scalar is not in BLS12-381")
+	}
+	out := &PartialSignature{ID: config.ID}
+	out.Signature.value.Set(config.PrivateShare.Act(hashToG2(m)))
+	return out, nil
+}
+
+// Verify checks that p is a valid partial signature on m, using the verification share of its sender.
+func (p *PartialSignature) Verify(config *Config, m []byte) bool {
+	share, ok := config.VerificationShares.Points[p.ID]
+	if !ok {
+		return false
+	}
+	return p.Signature.Verify(share, m)
+}
+
+// Aggregate combines the partial signatures of threshold + 1 participants into a signature on m,
+// valid under config.PublicKey.
+//
+// Every partial signature is verified first, so that an invalid share can be attributed to its sender.
+// Additional partial signatures beyond the threshold are ignored.
+func Aggregate(config *Config, m []byte, partials []*PartialSignature) (*Signature, error) {
+	if len(partials) < config.Threshold+1 {
+		return nil, fmt.Errorf("bls.Aggregate: need %d partial signatures, got %d", config.Threshold+1, len(partials))
+	}
+
+	ids := make([]party.ID, 0, config.Threshold+1)
+	signatures := make(map[party.ID]*Signature, config.Threshold+1)
+	for _, p := range partials {
+		if len(ids) == config.Threshold+1 {
+			break
+		}
+		if p == nil {
+			return nil, errors.New("bls.Aggregate: nil partial signature")
+		}
+		if _, ok := signatures[p.ID]; ok {
+			return nil, fmt.Errorf("bls.Aggregate: duplicate partial signature from %v", p.ID)
+		}
+		if !p.Verify(config, m) {
+			return nil, fmt.Errorf("bls.Aggregate: invalid partial signature from %v", p.ID)
+		}
+		ids = append(ids, p.ID)
+		signatures[p.ID] = &p.Signature
+	}
+
+	// σ = ∑ᵢ λᵢ•σᵢ
+	lambdas := polynomial.Lagrange(group, ids)
+	sum := group.G2().NewPoint()
+	for _, id := range ids {
+		sum = sum.Add(lambdas[id].Act(&signatures[id].value))
+	}
+	out := new(Signature)
+	out.value.Set(sum)
+
+	if !out.Verify(config.PublicKey, m) {
+		return nil, errors.New("bls.Aggregate: aggregated signature failed to verify")
+	}
+	return out, nil
+}
+
+func hashToG2(m []byte) curve.Point {
+	return group.G2().HashToPoint(m, []byte(DST))
+}
@@ -0,0 +1,68 @@
+// Package check implements a protocol by which parties confirm that they hold consistent cmp configs,
+// before starting a session which would otherwise fail with an unhelpful error.
+//
+// Each party sends the config.Summary of its Config to the others, and compares the summaries it receives to its own.
+// The summaries aren't broadcast reliably, so this detects configurations which diverged by accident,
+// such as a party which missed a refresh, rather than a malicious party.
+package check
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+const (
+	protocolID                  = "cmp/check"
+	protocolRounds round.Number = 2
+)
+
+// MismatchError is the error of a check which found parties whose config differs from ours.
+type MismatchError struct {
+	// Differences maps each party whose config differs to the descriptions returned by config.Summary.Diff.
+	Differences map[party.ID][]string
+}
+
+// Error implements error.
+func (e *MismatchError) Error() string {
+	ids := make([]party.ID, 0, len(e.Differences))
+	for j := range e.Differences {
+		ids = append(ids, j)
+	}
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+	parts := make([]string, 0, len(ids))
+	for _, j := range ids {
+		parts = append(parts, fmt.Sprintf("%s: %s", j, strings.Join(e.Differences[j], ", ")))
+	}
+	return "check: configs differ: " + strings.Join(parts, "; ")
+}
+
+// Start returns the check of c between parties, which must include c.ID, but can be a subset of the parties of c,
+// such as the signers of the next session.
+//
+// The threshold and group aren't bound to the session, so that parties disagreeing on them still find each other
+// and report the difference. The parties must agree on the list of participants however.
+func Start(c *config.Config, parties []party.ID, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		info := round.Info{
+			ProtocolID:       protocolID,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           c.ID,
+			PartyIDs:         parties,
+		}
+		helper, err := round.NewSession(info, sessionID, pl)
+		if err != nil {
+			return nil, fmt.Errorf("check: %w", err)
+		}
+		return &round1{
+			Helper:  helper,
+			Summary: c.Summary(),
+		}, nil
+	}
+}
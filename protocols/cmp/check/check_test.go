@@ -0,0 +1,82 @@
+package check
+
+import (
+	"errors"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+func run(t *testing.T, configs map[party.ID]*config.Config, parties party.IDSlice) []round.Session {
+	tracer := test.NewTracer()
+	rounds := make([]round.Session, 0, len(parties))
+	for _, id := range parties {
+		r, err := Start(configs[id], parties, nil)(nil)
+		require.NoError(t, err)
+		rounds = append(rounds, tracer.Session(r))
+	}
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	require.NoError(t, tracer.Check(Model()), "rounds should follow the model")
+	return rounds
+}
+
+func TestCheck(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	source := mrand.New(mrand.NewSource(1))
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, source, pl)
+
+	for _, r := range run(t, configs, partyIDs) {
+		require.IsType(t, &round.Output{}, r)
+		summary := r.(*round.Output).Result.(*config.Summary)
+		assert.Empty(t, summary.Diff(configs[partyIDs[0]].Summary()))
+	}
+
+	// the last party missed a refresh of the share of the first one, and uses another threshold
+	odd := *configs[partyIDs[2]]
+	odd.Threshold = 2
+	odd.Public = make(map[party.ID]*config.Public, len(configs[partyIDs[2]].Public))
+	for j, p := range configs[partyIDs[2]].Public {
+		q := *p
+		odd.Public[j] = &q
+	}
+	odd.Public[partyIDs[0]].ECDSA = sample.Scalar(source, group).ActOnBase()
+	configs[partyIDs[2]] = &odd
+
+	for _, r := range run(t, configs, partyIDs[:2]) {
+		require.IsType(t, &round.Output{}, r, "a subset of consistent parties should succeed")
+	}
+
+	for _, r := range run(t, configs, partyIDs) {
+		require.IsType(t, &round.Abort{}, r)
+		abort := r.(*round.Abort)
+		var mismatch *MismatchError
+		require.True(t, errors.As(abort.Err, &mismatch))
+		if abort.SelfID() == partyIDs[2] {
+			assert.ElementsMatch(t, partyIDs[:2], abort.Culprits)
+			continue
+		}
+		assert.Equal(t, []party.ID{partyIDs[2]}, abort.Culprits)
+		assert.Equal(t, []string{
+			"threshold is 2, instead of 1",
+			"public data of party " + string(partyIDs[0]) + " differs",
+		}, mismatch.Differences[partyIDs[2]])
+	}
+}
@@ -0,0 +1,14 @@
+package check
+
+import "github.com/taurusgroup/multi-party-sig/pkg/protocol"
+
+// Model returns the state machine of the check protocol.
+func Model() *protocol.Model {
+	return &protocol.Model{
+		Name: protocolID,
+		Rounds: []protocol.RoundModel{
+			{Name: "round1", Number: 1, Next: []string{"round2"}},
+			{Name: "round2", Number: 2, Message: true, Output: true, Abort: true},
+		},
+	}
+}
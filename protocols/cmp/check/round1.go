@@ -0,0 +1,41 @@
+package check
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+var _ round.Round = (*round1)(nil)
+
+type round1 struct {
+	*round.Helper
+
+	// Summary is the summary of our config.
+	Summary *config.Summary
+}
+
+// VerifyMessage implements round.Round.
+func (r *round1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - send the summary of our config to all parties.
+func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	if err := r.SendMessage(out, &message2{Summary: r.Summary}, ""); err != nil {
+		return r, err
+	}
+	return &round2{
+		round1:    r,
+		Summaries: make(map[party.ID]*config.Summary, r.N()-1),
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1) Number() round.Number { return 1 }
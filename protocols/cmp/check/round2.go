@@ -0,0 +1,65 @@
+package check
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+var _ round.Round = (*round2)(nil)
+
+type round2 struct {
+	*round1
+
+	// Summaries[j] is the summary of the config of party j.
+	Summaries map[party.ID]*config.Summary
+}
+
+type message2 struct {
+	Summary *config.Summary
+}
+
+// VerifyMessage implements round.Round.
+func (r *round2) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.Summary == nil {
+		return round.ErrNilFields
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round2) StoreMessage(msg round.Message) error {
+	r.Summaries[msg.From] = msg.Content.(*message2).Summary
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - compare the summaries of all parties with ours, and abort with a MismatchError naming those which differ.
+func (r *round2) Finalize(chan<- *round.Message) (round.Session, error) {
+	differences := map[party.ID][]string{}
+	var culprits []party.ID
+	for _, j := range r.OtherPartyIDs() {
+		if diff := r.Summary.Diff(r.Summaries[j]); len(diff) > 0 {
+			differences[j] = diff
+			culprits = append(culprits, j)
+		}
+	}
+	if len(culprits) > 0 {
+		return r.AbortRound(&MismatchError{Differences: differences}, culprits...), nil
+	}
+	return r.ResultRound(r.Summary), nil
+}
+
+// RoundNumber implements round.Content.
+func (message2) RoundNumber() round.Number { return 2 }
+
+// MessageContent implements round.Round.
+func (round2) MessageContent() round.Content { return &message2{} }
+
+// Number implements round.Round.
+func (round2) Number() round.Number { return 2 }
@@ -3,10 +3,13 @@ package cmp
 import (
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/check"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/keygen"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/presign"
@@ -17,6 +20,79 @@ import (
 // It contains secret key material and should be safely stored.
 type Config = config.Config
 
+// KeygenOption modifies the behaviour of Keygen.
+type KeygenOption = keygen.Option
+
+// WithPrimePool makes Keygen and Refresh draw the primes of this party's Paillier key from primes,
+// which generates them ahead of time, instead of searching for them while the protocol runs.
+//
+// It only affects the local party, so parties can choose independently whether to use it.
+func WithPrimePool(primes *paillier.PrimePool) KeygenOption {
+	return keygen.WithPrimePool(primes)
+}
+
+// WithPaillierKey makes Keygen and Refresh use sk as this party's Paillier key, instead of generating one,
+// so that a key prepared offline or in an HSM can be used without waiting on prime generation.
+//
+// Peers still verify the proofs that sk's modulus and the derived Pedersen parameters are well formed.
+func WithPaillierKey(sk *paillier.SecretKey) KeygenOption {
+	return keygen.WithPaillierKey(sk)
+}
+
+// WithHash makes Keygen and Refresh use f as the hash function of the session, instead of BLAKE3,
+// for example SHA-256 or SHAKE256 in environments where only FIPS approved functions may be used.
+//
+// The function is bound into the SSID, so all parties must choose the same one.
+func WithHash(f hash.Function) KeygenOption {
+	return keygen.WithHash(f)
+}
+
+// WithStatisticalSecurity makes Keygen and Refresh repeat the proofs of correctness of the Paillier and Pedersen
+// parameters iterations times, trading latency for soundness. The default is 80, which is also the minimum.
+//
+// The value is bound into the SSID, so all parties must choose the same one.
+func WithStatisticalSecurity(iterations int) KeygenOption {
+	return keygen.WithStatisticalSecurity(iterations)
+}
+
+// WithSecurityProfile makes Keygen and Refresh generate Paillier moduli of the size of profile, for example
+// paillier.Profile3072 for 128 bits of security, instead of the default of 2048 bits.
+// The proofs exchanged during signing adapt to the size of the moduli in the Config.
+//
+// As with WithHash, all parties must choose the same profile.
+func WithSecurityProfile(profile paillier.SecurityProfile) KeygenOption {
+	return keygen.WithSecurityProfile(profile)
+}
+
+// WithAuxInfoOnly makes Refresh regenerate the Paillier keys, Pedersen parameters and ElGamal keys of the parties,
+// while keeping their ECDSA shares, so that this material can be rotated on a different schedule than the shares.
+//
+// All parties must use this option, and Keygen fails with it.
+// It also completes configs which only contain shares, such as those returned by tsslib.Import or zengo.Import.
+func WithAuxInfoOnly() KeygenOption {
+	return keygen.WithAuxInfoOnly()
+}
+
+// WithEntropy makes Keygen mix entropy, such as the output of a public randomness beacon, into the RID and chain key.
+//
+// The entropy is committed to and revealed along with the other contributions of this party, so that it can be audited.
+// Each party may contribute its own entropy, and Refresh fails with this option.
+func WithEntropy(entropy []byte) KeygenOption {
+	return keygen.WithEntropy(entropy)
+}
+
+// SignOption modifies the behaviour of Sign and SignBatch.
+type SignOption = sign.Option
+
+// WithCompactProofs makes Sign and SignBatch send the zero-knowledge proofs of each round with their challenge,
+// instead of the commitments that the receiver can recompute, which makes the messages much smaller
+// but prevents verifying the proofs of all signers in a batch.
+//
+// The choice is bound into the SSID, so all signers must make the same one.
+func WithCompactProofs() SignOption {
+	return sign.WithCompactProofs()
+}
+
 // EmptyConfig creates an empty Config with a fixed group, ready for unmarshalling.
 //
 // This needs to be used for unmarshalling, otherwise the points on the curve can't
@@ -31,8 +107,9 @@ func EmptyConfig(group curve.Curve) *Config {
 // all participants posses a unique share of this key, as well as auxiliary parameters required during signing.
 //
 // For better performance, a `pool.Pool` can be provided in order to parallelize certain steps of the protocol.
+// Options such as WithStatisticalSecurity must be the same for all participants.
 // Returns *cmp.Config if successful.
-func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, pl *pool.Pool) protocol.StartFunc {
+func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, pl *pool.Pool, opts ...KeygenOption) protocol.StartFunc {
 	info := round.Info{
 		ProtocolID:       "cmp/keygen-threshold",
 		FinalRoundNumber: keygen.Rounds,
@@ -41,13 +118,19 @@ func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, thresho
 		Threshold:        threshold,
 		Group:            group,
 	}
-	return keygen.Start(info, pl, nil)
+	return keygen.Start(info, pl, nil, opts...)
 }
 
 // Refresh allows the parties to refresh all existing cryptographic keys from a previously generated Config.
 // The group's ECDSA public key remains the same, but any previous shares are rendered useless.
 // Returns *cmp.Config if successful.
-func Refresh(config *Config, pl *pool.Pool) protocol.StartFunc {
+//
+// Options such as WithPrimePool can be given as for Keygen.
+// With WithAuxInfoOnly, the ECDSA shares are kept, and only the other keys of the parties are replaced.
+//
+// Afterwards, each party can publish a proof from Config.ProveRefresh, which external auditors
+// check against the public data of both configs with config.VerifyRefresh.
+func Refresh(config *Config, pl *pool.Pool, opts ...KeygenOption) protocol.StartFunc {
 	info := round.Info{
 		ProtocolID:       "cmp/refresh-threshold",
 		FinalRoundNumber: keygen.Rounds,
@@ -56,13 +139,27 @@ func Refresh(config *Config, pl *pool.Pool) protocol.StartFunc {
 		Threshold:        config.Threshold,
 		Group:            config.Group,
 	}
-	return keygen.Start(info, pl, config)
+	return keygen.Start(info, pl, config, opts...)
 }
 
 // Sign generates an ECDSA signature for `messageHash` among the given `signers`.
 // Returns *ecdsa.Signature if successful.
-func Sign(config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool) protocol.StartFunc {
-	return sign.StartSign(config, signers, messageHash, pl)
+//
+// With exactly two signers, the handler doesn't attach the digest of each round's broadcasts
+// to the messages of the next round, since a broadcast with a single recipient can't be equivocated.
+// The number of rounds is the same however, since each one depends on the messages of the previous one;
+// Presign lets two parties sign in a single online round instead.
+func Sign(config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool, opts ...SignOption) protocol.StartFunc {
+	return sign.StartSign(config, signers, messageHash, pl, opts...)
+}
+
+// SignBatch generates ECDSA signatures for all `messageHashes` in a single session among the given `signers`.
+// Returns []*ecdsa.Signature if successful, in the same order as `messageHashes`.
+//
+// The batch takes as many rounds and messages as a single signature, but as much computation as signing
+// each message on its own, since no nonce or proof can be shared between two signatures.
+func SignBatch(config *Config, signers []party.ID, messageHashes [][]byte, pl *pool.Pool, opts ...SignOption) protocol.StartFunc {
+	return sign.StartSignBatch(config, signers, messageHashes, pl, opts...)
 }
 
 // Presign generates a preprocessed signature that does not depend on the message being signed.
@@ -79,3 +176,22 @@ func Presign(config *Config, signers []party.ID, pl *pool.Pool) protocol.StartFu
 func PresignOnline(config *Config, preSignature *ecdsa.PreSignature, messageHash []byte, pl *pool.Pool) protocol.StartFunc {
 	return presign.StartPresignOnline(config, preSignature, messageHash, pl)
 }
+
+// CheckConfig lets parties confirm that their configs have the same public data, RID, chain key and threshold,
+// for example before starting to sign with them.
+// Returns the *config.Summary of config if successful.
+//
+// Otherwise, it fails with a protocol.Error whose culprits are the parties whose config differs from ours,
+// wrapping a *check.MismatchError which describes the differences.
+func CheckConfig(config *Config, parties []party.ID, pl *pool.Pool) protocol.StartFunc {
+	return check.Start(config, parties, pl)
+}
+
+// ReconstructSecret recovers the full ECDSA private key from the configs of at least Threshold+1 parties.
+//
+// WARNING: this defeats the purpose of threshold signing, since whoever holds the key can sign alone.
+// It is only meant for disaster recovery procedures, such as sweeping funds to a new key before decommissioning
+// the parties; see config.ReconstructSecret.
+func ReconstructSecret(configs []*Config) (curve.Scalar, error) {
+	return config.ReconstructSecret(configs)
+}
@@ -1,7 +1,10 @@
 package cmp
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
 	"math"
 	"sync"
 	"testing"
@@ -14,6 +17,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
 func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte, pl *pool.Pool, n *test.Network, wg *sync.WaitGroup) {
@@ -45,6 +49,18 @@ func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte
 	signature := signResult.(*ecdsa.Signature)
 	assert.True(t, signature.Verify(c.PublicPoint(), message))
 
+	messages := [][]byte{message, []byte("world")}
+	h, err = protocol.NewMultiHandler(SignBatch(c, ids, messages, pl), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, []*ecdsa.Signature{}, signResult)
+	for i, signature := range signResult.([]*ecdsa.Signature) {
+		assert.True(t, signature.Verify(c.PublicPoint(), messages[i]))
+	}
+
 	h, err = protocol.NewMultiHandler(Presign(c, ids, pl), nil)
 	require.NoError(t, err)
 
@@ -161,3 +177,87 @@ func TestStart(t *testing.T) {
 		})
 	}
 }
+
+func TestReconstructSecret(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	configs, partyIDs := test.GenerateConfig(group, 4, 2, rand.Reader, pl)
+
+	subset := []*Config{configs[partyIDs[3]], configs[partyIDs[0]], configs[partyIDs[2]]}
+	secret, err := ReconstructSecret(subset)
+	require.NoError(t, err)
+	assert.True(t, secret.ActOnBase().Equal(configs[partyIDs[0]].PublicPoint()))
+
+	child, err := configs[partyIDs[0]].DeriveBIP32(1)
+	require.NoError(t, err)
+	_, err = ReconstructSecret([]*Config{child, configs[partyIDs[1]], configs[partyIDs[2]]})
+	assert.Error(t, err, "configs of different keys")
+	_, err = ReconstructSecret(subset[:2])
+	assert.Error(t, err, "not enough configs")
+	_, err = ReconstructSecret([]*Config{subset[0], subset[1], subset[0]})
+	assert.Error(t, err, "duplicate config")
+	_, err = ReconstructSecret(nil)
+	assert.Error(t, err)
+}
+
+// bip32Vector2 returns the config of a single party holding the master key of test vector 2 of BIP-32.
+func bip32Vector2(t *testing.T) *Config {
+	seed, _ := hex.DecodeString("fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542")
+	h := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	_, _ = h.Write(seed)
+	master := h.Sum(nil)
+
+	group := curve.Secp256k1{}
+	secret := group.NewScalar()
+	require.NoError(t, secret.UnmarshalBinary(master[:32]))
+	return &Config{
+		Group:    group,
+		ID:       "a",
+		ECDSA:    secret,
+		ChainKey: master[32:],
+		Public:   map[party.ID]*config.Public{"a": {ECDSA: secret.ActOnBase()}},
+	}
+}
+
+func TestExtendedPublicKey(t *testing.T) {
+	c := bip32Vector2(t)
+	assert.Len(t, c.ChainCode(), 32)
+
+	xpub, err := c.ExtendedPublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB", xpub)
+
+	// m/0, as derived by a wallet from the xpub
+	child, err := c.DeriveBIP32(0)
+	require.NoError(t, err)
+	childPublic, err := child.PublicPoint().MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, "02fc9e5af0ac8d9b3cecfe2a888e2117ba3d089d8585886c9c826b6b22a98d12ea", hex.EncodeToString(childPublic))
+	assert.Equal(t, "f0909affaa7ee7abe5dd4e100598d4dc53cd709d5a5c2cac40e7412f232f7c9c", hex.EncodeToString(child.ChainCode()))
+}
+
+func TestDerivePath(t *testing.T) {
+	c := bip32Vector2(t)
+
+	expected := c
+	for _, i := range []uint32{0, 7, 3} {
+		var err error
+		expected, err = expected.DeriveBIP32(i)
+		require.NoError(t, err)
+	}
+	derived, err := c.DerivePath("m/0/7/3")
+	require.NoError(t, err)
+	assert.True(t, expected.PublicPoint().Equal(derived.PublicPoint()))
+	assert.Equal(t, expected.ChainKey, derived.ChainKey)
+	assert.True(t, expected.ECDSA.Equal(derived.ECDSA))
+
+	root, err := c.DerivePath("m")
+	require.NoError(t, err)
+	assert.True(t, c.PublicPoint().Equal(root.PublicPoint()))
+
+	for _, path := range []string{"m/44'/60'/0'/0/3", "m/0/1h", "m/2147483648", "", "0/1", "m/", "m//1", "m/-1", "m/+1", "m/a", "n/0"} {
+		_, err := c.DerivePath(path)
+		assert.Error(t, err, path)
+	}
+}
@@ -56,6 +56,11 @@ type Public struct {
 	Pedersen *pedersen.Parameters
 }
 
+// SecurityProfile returns the profile of the Paillier moduli of the parties, which all have the same size.
+func (c *Config) SecurityProfile() paillier.SecurityProfile {
+	return paillier.SecurityProfile(c.Paillier.N().BitLen())
+}
+
 // PublicPoint returns the group's public ECC point.
 func (c *Config) PublicPoint() curve.Point {
 	sum := c.Group.NewPoint()
@@ -70,6 +75,31 @@ func (c *Config) PublicPoint() curve.Point {
 	return sum
 }
 
+// PublicKeyBytes returns the SEC 1 encoding of the group's public key,
+// in 33 bytes if compressed, or 65 bytes otherwise.
+func (c *Config) PublicKeyBytes(compressed bool) ([]byte, error) {
+	return curve.MarshalSEC1(c.PublicPoint(), compressed)
+}
+
+// KeyID returns the fingerprint of the group's public key computed by curve.KeyID,
+// which stays the same across refreshes, and between the configs of all parties.
+func (c *Config) KeyID() string {
+	return curve.KeyID(c.PublicPoint())
+}
+
+// VerificationShareBytes returns the SEC 1 encodings of the public ECDSA shares of all parties.
+func (c *Config) VerificationShareBytes(compressed bool) (map[party.ID][]byte, error) {
+	shares := make(map[party.ID][]byte, len(c.Public))
+	for j, public := range c.Public {
+		data, err := curve.MarshalSEC1(public.ECDSA, compressed)
+		if err != nil {
+			return nil, err
+		}
+		shares[j] = data
+	}
+	return shares, nil
+}
+
 // PartyIDs returns a sorted slice of party IDs.
 func (c *Config) PartyIDs() party.IDSlice {
 	ids := make([]party.ID, 0, len(c.Public))
@@ -251,6 +281,51 @@ func (c *Config) Derive(adjust curve.Scalar, newChainKey []byte) (*Config, error
 	}, nil
 }
 
+// DerivePath derives a sharing of the key at path, written in the notation of BIP-32 such as "m/0/3",
+// by applying DeriveBIP32 with each of its indices in turn.
+//
+// Hardened indices, such as 44' in "m/44'/60'/0'/0/3", are rejected since they would require hashing the secret key.
+// Paths with hardened levels can still be served by running a keygen for the account level,
+// and deriving the remaining unhardened levels from its config.
+func (c *Config) DerivePath(path string) (*Config, error) {
+	indices, err := bip32.ParsePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("DerivePath: %w", err)
+	}
+	for _, i := range indices {
+		if i >= bip32.Hardened {
+			return nil, fmt.Errorf("DerivePath: %q contains the hardened index %d', which can't be derived from shares", path, i-bip32.Hardened)
+		}
+	}
+	derived := c
+	for _, i := range indices {
+		if derived, err = derived.DeriveBIP32(i); err != nil {
+			return nil, fmt.Errorf("DerivePath: %w", err)
+		}
+	}
+	return derived, nil
+}
+
+// ChainCode returns a copy of the chain key, which is the BIP-32 chain code of the public key of the consortium.
+//
+// Together with PublicPoint, it forms the extended public key returned by ExtendedPublicKey.
+func (c *Config) ChainCode() []byte {
+	return c.ChainKey.Copy()
+}
+
+// ExtendedPublicKey returns the BIP-32 extended public key ("xpub") of the consortium, made of the public key and chain key.
+//
+// The key is encoded as a master key, so that the child i derived from it by a conventional wallet,
+// for instance one held by auditors, matches the public key of DeriveBIP32(i), and so do the addresses of their descendants.
+// Configs returned by DeriveBIP32 are also encoded as a master key, so the extended key of the root config should be shared.
+func (c *Config) ExtendedPublicKey() (string, error) {
+	publicPoint, ok := c.PublicPoint().(*curve.Secp256k1Point)
+	if !ok {
+		return "", errors.New("ExtendedPublicKey must be called with secp256k1")
+	}
+	return bip32.SerializePublic(publicPoint, c.ChainKey)
+}
+
 // DeriveBIP32 derives a sharing of the ith child of the consortium signing key.
 //
 // This function uses unhardened derivation, deriving a key without including the
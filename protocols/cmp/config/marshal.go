@@ -94,7 +94,15 @@ func (c *Config) UnmarshalBinary(data []byte) error {
 	if err := paillier.ValidatePrime(cm.Q); err != nil {
 		return fmt.Errorf("config: prime Q: %w", err)
 	}
+	if cm.P.TrueLen() != cm.Q.TrueLen() {
+		return errors.New("config: primes P and Q have different sizes")
+	}
 	paillierSecret := paillier.NewSecretKeyFromPrimes(cm.P, cm.Q)
+	// all the parties must use moduli of the same size as ours
+	profile, err := paillier.ProfileOf(paillierSecret.N())
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
 
 	// handle public parameters
 	ps := make(map[party.ID]*Public, len(cm.Public))
@@ -112,31 +120,37 @@ func (c *Config) UnmarshalBinary(data []byte) error {
 
 		// handle our own key separately
 		if p.ID == cm.ID {
+			ped := pedersen.New(paillierSecret.Modulus(), p.S, p.T)
+			if err := ped.ValidateWithPrimes(cm.P, cm.Q); err != nil {
+				return fmt.Errorf("config: party %s: %w", p.ID, err)
+			}
 			ps[p.ID] = &Public{
 				ECDSA:    cm.ECDSA.ActOnBase(),
 				ElGamal:  cm.ElGamal.ActOnBase(),
 				Paillier: paillierSecret.PublicKey,
-				Pedersen: pedersen.New(paillierSecret.Modulus(), p.S, p.T),
+				Pedersen: ped,
 			}
 			continue
 		}
 
-		if err := paillier.ValidateN(p.N); err != nil {
+		if err := profile.ValidateN(p.N); err != nil {
 			return fmt.Errorf("config: party %s: %w", p.ID, err)
 		}
-		if err := pedersen.ValidateParameters(p.N, p.S, p.T); err != nil {
+		paillierPublic := paillier.NewPublicKey(p.N)
+		// the other parties proved that their parameters are correct during keygen, which we can't check again
+		ped := pedersen.New(paillierPublic.Modulus(), p.S, p.T)
+		if err := ped.Validate(); err != nil {
 			return fmt.Errorf("config: party %s: %w", p.ID, err)
 		}
 		if p.ECDSA.IsIdentity() || p.ElGamal.IsIdentity() {
 			return fmt.Errorf("config: party %s: ECDSA or ElGamal public key is identity", p.ID)
 		}
 
-		paillierPublic := paillier.NewPublicKey(p.N)
 		ps[p.ID] = &Public{
 			ECDSA:    p.ECDSA,
 			ElGamal:  p.ElGamal,
 			Paillier: paillierPublic,
-			Pedersen: pedersen.New(paillierPublic.Modulus(), p.S, p.T),
+			Pedersen: ped,
 		}
 	}
 
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// ReconstructSecret recovers the ECDSA private key shared by the given configs, of at least Threshold+1 distinct parties.
+//
+// WARNING: the whole point of threshold signing is that this key never exists in a single place.
+// Whoever holds the result can sign anything on their own, without the other parties noticing,
+// and it remains valid after any refresh of the shares. It is only meant for disaster recovery,
+// for example to sweep funds to a new key when the parties are decommissioned,
+// after which it should be erased, and the key it controls abandoned.
+//
+// The configs are checked to be consistent with each other, and the result to match the public key.
+func ReconstructSecret(configs []*Config) (curve.Scalar, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("config: no configs to reconstruct the secret from")
+	}
+	first := configs[0]
+	if len(configs) <= first.Threshold {
+		return nil, fmt.Errorf("config: %d configs are needed to reconstruct the secret, got %d", first.Threshold+1, len(configs))
+	}
+	summary := first.Summary()
+	ids := make([]party.ID, 0, len(configs))
+	shares := make(map[party.ID]curve.Scalar, len(configs))
+	for _, c := range configs {
+		if c.ECDSA == nil {
+			return nil, fmt.Errorf("config: the config of %s has no secret share", c.ID)
+		}
+		if _, ok := shares[c.ID]; ok {
+			return nil, fmt.Errorf("config: several configs belong to %s", c.ID)
+		}
+		if diff := summary.Diff(c.Summary()); len(diff) > 0 {
+			return nil, fmt.Errorf("config: the config of %s is inconsistent with that of %s: %s", c.ID, first.ID, strings.Join(diff, ", "))
+		}
+		public, ok := c.Public[c.ID]
+		if !ok || !c.ECDSA.ActOnBase().Equal(public.ECDSA) {
+			return nil, fmt.Errorf("config: the secret share of %s doesn't match its public share", c.ID)
+		}
+		ids = append(ids, c.ID)
+		shares[c.ID] = c.ECDSA
+	}
+
+	// x = ∑ⱼ λⱼ⋅xⱼ
+	group := first.Group
+	secret := group.NewScalar()
+	for j, lambda := range polynomial.Lagrange(group, ids) {
+		secret.Add(lambda.Mul(shares[j]))
+	}
+	if !secret.ActOnBase().Equal(first.PublicPoint()) {
+		return nil, errors.New("config: the reconstructed secret doesn't match the public key")
+	}
+	return secret, nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+)
+
+// RefreshProof is produced by a party after a refresh, and lets observers check
+// that its new share is a re-randomization of its previous one.
+//
+// Together with the check that both configs have the same public key, this shows
+// that the refresh did not substitute a different key.
+type RefreshProof struct {
+	// ID is the party that produced the proof.
+	ID party.ID
+	// Proof is a proof of knowledge of δ = x'ᵢ - xᵢ, such that X'ᵢ - Xᵢ = δ•G.
+	Proof *zksch.Proof
+}
+
+// EmptyRefreshProof creates a RefreshProof ready to be unmarshalled.
+func EmptyRefreshProof(group curve.Curve) *RefreshProof {
+	return &RefreshProof{Proof: zksch.EmptyProof(group)}
+}
+
+// ProveRefresh creates a RefreshProof for this party, showing that c was obtained by refreshing previous.
+func (c *Config) ProveRefresh(previous *Config) (*RefreshProof, error) {
+	if c.ID != previous.ID {
+		return nil, errors.New("config: ProveRefresh: configs belong to different parties")
+	}
+	if err := checkRefresh(previous, c); err != nil {
+		return nil, fmt.Errorf("config: ProveRefresh: %w", err)
+	}
+	delta := c.Group.NewScalar().Set(c.ECDSA).Sub(previous.ECDSA)
+	Delta := c.Public[c.ID].ECDSA.Sub(previous.Public[c.ID].ECDSA)
+	return &RefreshProof{
+		ID:    c.ID,
+		Proof: zksch.NewProof(refreshHash(previous, c, c.ID), Delta, delta, nil),
+	}, nil
+}
+
+// VerifyRefresh checks that next was obtained by refreshing previous,
+// given the RefreshProof of every party.
+//
+// Only the public data of both configs is used, so observers can
+// create them without any secret share.
+func VerifyRefresh(previous, next *Config, proofs []*RefreshProof) error {
+	if err := checkRefresh(previous, next); err != nil {
+		return fmt.Errorf("config: VerifyRefresh: %w", err)
+	}
+	verified := make(map[party.ID]bool, len(proofs))
+	for _, p := range proofs {
+		if p == nil || p.Proof == nil {
+			return errors.New("config: VerifyRefresh: nil proof")
+		}
+		if _, ok := next.Public[p.ID]; !ok {
+			return fmt.Errorf("config: VerifyRefresh: party %s: not part of the config", p.ID)
+		}
+		if verified[p.ID] {
+			return fmt.Errorf("config: VerifyRefresh: party %s: duplicate proof", p.ID)
+		}
+		Delta := next.Public[p.ID].ECDSA.Sub(previous.Public[p.ID].ECDSA)
+		if !p.Proof.Verify(refreshHash(previous, next, p.ID), Delta, nil) {
+			return fmt.Errorf("config: VerifyRefresh: party %s: invalid proof", p.ID)
+		}
+		verified[p.ID] = true
+	}
+	for id := range next.Public {
+		if !verified[id] {
+			return fmt.Errorf("config: VerifyRefresh: party %s: missing proof", id)
+		}
+	}
+	return nil
+}
+
+// checkRefresh verifies that both configs have the same parties and public key.
+func checkRefresh(previous, next *Config) error {
+	if previous.Group.Name() != next.Group.Name() {
+		return errors.New("configs use different groups")
+	}
+	if previous.Threshold != next.Threshold {
+		return errors.New("configs have different thresholds")
+	}
+	if len(previous.Public) != len(next.Public) {
+		return errors.New("configs have different parties")
+	}
+	for id := range next.Public {
+		if _, ok := previous.Public[id]; !ok {
+			return fmt.Errorf("party %s is not part of the previous config", id)
+		}
+	}
+	if !previous.PublicPoint().Equal(next.PublicPoint()) {
+		return errors.New("public key changed")
+	}
+	return nil
+}
+
+// refreshHash binds a RefreshProof to the public data of both configs, and to the prover.
+func refreshHash(previous, next *Config, id party.ID) *hash.Hash {
+	h := hash.New()
+	for _, j := range next.PartyIDs() {
+		_ = h.WriteAny(j, previous.Public[j], next.Public[j])
+	}
+	_ = h.WriteAny(&hash.BytesWithDomain{TheDomain: "Refresh Prover", Bytes: []byte(id)})
+	return h
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// Summary contains the data of a Config which must be the same for all parties, in a compact form
+// which can be exchanged to check that their configs are consistent.
+type Summary struct {
+	// Group is the name of the curve of the key.
+	Group string
+	// Threshold is the threshold of the key.
+	Threshold int
+	// RID is the random identifier of the last keygen or refresh.
+	RID types.RID
+	// ChainKey is the BIP-32 chain key.
+	ChainKey types.RID
+	// Public maps each party to a digest of its public data.
+	Public map[party.ID][]byte
+}
+
+// Summary returns the Summary of c, which contains no secret data.
+func (c *Config) Summary() *Summary {
+	public := make(map[party.ID][]byte, len(c.Public))
+	for j, p := range c.Public {
+		public[j] = hash.New(p).Sum()
+	}
+	return &Summary{
+		Group:     c.Group.Name(),
+		Threshold: c.Threshold,
+		RID:       c.RID.Copy(),
+		ChainKey:  c.ChainKey.Copy(),
+		Public:    public,
+	}
+}
+
+// Diff returns a description of every difference between s and other, or nil if they have the same contents.
+//
+// The descriptions name the fields which differ, and the parties whose public data don't match,
+// such as "threshold is 2, instead of 1" or "public data of party b differs".
+func (s *Summary) Diff(other *Summary) []string {
+	var diff []string
+	if s.Group != other.Group {
+		diff = append(diff, fmt.Sprintf("group is %s, instead of %s", other.Group, s.Group))
+	}
+	if s.Threshold != other.Threshold {
+		diff = append(diff, fmt.Sprintf("threshold is %d, instead of %d", other.Threshold, s.Threshold))
+	}
+	if !bytes.Equal(s.RID, other.RID) {
+		diff = append(diff, "RID differs, the configs weren't produced by the same keygen or refresh")
+	}
+	if !bytes.Equal(s.ChainKey, other.ChainKey) {
+		diff = append(diff, "chain key differs")
+	}
+
+	ids := make([]party.ID, 0, len(s.Public))
+	for j := range s.Public {
+		ids = append(ids, j)
+	}
+	for j := range other.Public {
+		if _, ok := s.Public[j]; !ok {
+			ids = append(ids, j)
+		}
+	}
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+	for _, j := range ids {
+		mine, ok := s.Public[j]
+		theirs, otherOK := other.Public[j]
+		switch {
+		case !otherOK:
+			diff = append(diff, fmt.Sprintf("party %s is missing", j))
+		case !ok:
+			diff = append(diff, fmt.Sprintf("party %s is unexpected", j))
+		case !bytes.Equal(mine, theirs):
+			diff = append(diff, fmt.Sprintf("public data of party %s differs", j))
+		}
+	}
+	return diff
+}
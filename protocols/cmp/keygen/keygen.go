@@ -1,10 +1,15 @@
 package keygen
 
 import (
-	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
 
+	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -16,14 +21,73 @@ import (
 
 const Rounds round.Number = 5
 
-func Start(info round.Info, pl *pool.Pool, c *config.Config) protocol.StartFunc {
+func Start(info round.Info, pl *pool.Pool, c *config.Config, opts ...Option) protocol.StartFunc {
 	return func(sessionID []byte) (_ round.Session, err error) {
-		var helper *round.Helper
-		if c == nil {
-			helper, err = round.NewSession(info, sessionID, pl)
-		} else {
-			helper, err = round.NewSession(info, sessionID, pl, c)
+		var o options
+		for _, opt := range opts {
+			opt(&o)
 		}
+		if c != nil {
+			if o.profile == 0 && c.Paillier != nil {
+				o.profile = c.SecurityProfile()
+			}
+		}
+		if len(o.entropy) > 0 && c != nil {
+			return nil, errors.New("keygen: entropy can only be contributed to a new key")
+		}
+		if len(o.entropy) > MaxEntropy {
+			return nil, fmt.Errorf("keygen: entropy is longer than %d bytes", MaxEntropy)
+		}
+		if o.auxInfoOnly && c == nil {
+			return nil, errors.New("keygen: aux info can only be generated for an existing config")
+		}
+		if err := o.profile.Validate(); err != nil {
+			return nil, fmt.Errorf("keygen: %w", err)
+		}
+		if o.primes != nil && o.primes.Profile().BitsPaillier() != o.profile.BitsPaillier() {
+			return nil, errors.New("keygen: the prime pool doesn't generate primes for the security profile")
+		}
+		if o.paillierSecret != nil {
+			if err := o.paillierSecret.Validate(); err != nil {
+				return nil, fmt.Errorf("keygen: invalid Paillier key: %w", err)
+			}
+			if err := o.profile.ValidateN(o.paillierSecret.N()); err != nil {
+				return nil, fmt.Errorf("keygen: invalid Paillier key: %w", err)
+			}
+			if c != nil && c.Paillier != nil && c.Paillier.PublicKey.Equal(o.paillierSecret.PublicKey) {
+				return nil, errors.New("keygen: refresh must use a new Paillier key")
+			}
+		}
+
+		var auxInfo []hash.WriterToWithDomain
+		if c != nil && o.auxInfoOnly {
+			auxInfo = append(auxInfo, sharesOf{c})
+		} else if c != nil {
+			auxInfo = append(auxInfo, c)
+		}
+		if iterations := params.StatIterations(o.iterations); iterations != params.StatParam {
+			// the number of repetitions changes the proofs exchanged, so all parties must agree on it.
+			auxInfo = append(auxInfo, hash.BytesWithDomain{
+				TheDomain: "Statistical Security",
+				Bytes:     []byte(strconv.Itoa(iterations)),
+			})
+		}
+		if bits := o.profile.BitsPaillier(); bits != params.BitsPaillier {
+			// the other parties reject moduli of another size, so fail early if we don't agree on it.
+			auxInfo = append(auxInfo, hash.BytesWithDomain{
+				TheDomain: "Security Profile",
+				Bytes:     []byte(strconv.Itoa(bits)),
+			})
+		}
+		if o.auxInfoOnly {
+			// parties keeping their shares reject the polynomials of parties refreshing them.
+			auxInfo = append(auxInfo, hash.BytesWithDomain{
+				TheDomain: "Aux Info Only",
+				Bytes:     []byte{1},
+			})
+		}
+		info.Hash = o.hash
+		helper, err := round.NewSession(info, sessionID, pl, auxInfo...)
 		if err != nil {
 			return nil, fmt.Errorf("keygen: %w", err)
 		}
@@ -35,22 +99,73 @@ func Start(info round.Info, pl *pool.Pool, c *config.Config) protocol.StartFunc
 			for id, public := range c.Public {
 				PublicSharesECDSA[id] = public.ECDSA
 			}
+			// fᵢ(X) deg(fᵢ) = t, fᵢ(0) = 0
+			VSSSecret := polynomial.NewPolynomial(group, helper.Threshold(), group.NewScalar())
+			if o.auxInfoOnly {
+				// fᵢ(X) = 0, so that the shares don't change
+				VSSSecret = polynomial.NewPolynomial(group, 0, group.NewScalar())
+			}
 			return &round1{
 				Helper:                    helper,
+				Primes:                    o.primes,
+				PaillierSecret:            o.paillierSecret,
+				Iterations:                o.iterations,
+				Profile:                   o.profile,
 				PreviousSecretECDSA:       c.ECDSA,
 				PreviousPublicSharesECDSA: PublicSharesECDSA,
 				PreviousChainKey:          c.ChainKey,
-				VSSSecret:                 polynomial.NewPolynomial(group, helper.Threshold(), group.NewScalar()), // fᵢ(X) deg(fᵢ) = t, fᵢ(0) = 0
+				AuxInfoOnly:               o.auxInfoOnly,
+				VSSSecret:                 VSSSecret,
 			}, nil
 		}
 
 		// sample fᵢ(X) deg(fᵢ) = t, fᵢ(0) = secretᵢ
-		VSSConstant := sample.Scalar(rand.Reader, group)
+		VSSConstant := sample.Scalar(random.Reader(), group)
 		VSSSecret := polynomial.NewPolynomial(group, helper.Threshold(), VSSConstant)
 		return &round1{
-			Helper:    helper,
-			VSSSecret: VSSSecret,
+			Helper:         helper,
+			Primes:         o.primes,
+			PaillierSecret: o.paillierSecret,
+			Iterations:     o.iterations,
+			Profile:        o.profile,
+			Entropy:        o.entropy,
+			VSSSecret:      VSSSecret,
 		}, nil
 
 	}
 }
+
+// sharesOf is the part of a Config kept by an aux info refresh, which is bound to its session instead of the whole Config.
+//
+// This lets configs which only contain the shares, such as those imported from other implementations, get their aux info.
+type sharesOf struct {
+	c *config.Config
+}
+
+// Domain implements hash.WriterToWithDomain.
+func (sharesOf) Domain() string { return "CMP Shares" }
+
+// WriteTo implements io.WriterTo.
+func (s sharesOf) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	// imported configs may have no chain key, in which case a new one is generated
+	if s.c.ChainKey != nil {
+		n, err := s.c.ChainKey.WriteTo(w)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	for _, j := range s.c.PartyIDs() {
+		data, err := s.c.Public[j].ECDSA.MarshalBinary()
+		if err != nil {
+			return total, err
+		}
+		n, err := w.Write(data)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
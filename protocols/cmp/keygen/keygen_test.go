@@ -4,13 +4,17 @@ import (
 	mrand "math/rand"
 	"testing"
 
+	"github.com/cronokirby/saferith"
 	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/zk"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
@@ -55,12 +59,122 @@ func checkOutput(t *testing.T, rounds []round.Session) {
 }
 
 func TestKeygen(t *testing.T) {
+	testKeygen(t)
+}
+
+func TestKeygenPaillierKey(t *testing.T) {
+	sk := zk.ProverPaillierSecret
+	partyIDs := test.PartyIDs(2)
+	rounds := testKeygenWith(t, partyIDs, func(id party.ID) []Option {
+		if id == partyIDs[0] {
+			return []Option{WithPaillierKey(sk)}
+		}
+		return nil
+	})
+	for _, r := range rounds {
+		c := r.(*round.Output).Result.(*config.Config)
+		assert.True(t, sk.PublicKey.Equal(c.Public[partyIDs[0]].Paillier), "the given Paillier key should be used")
+	}
+
+	info := round.Info{
+		ProtocolID:       "cmp/keygen-test",
+		FinalRoundNumber: Rounds,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs,
+		Threshold:        1,
+		Group:            group,
+	}
+	invalid := paillier.NewSecretKeyFromPrimes(sk.P(), sk.P())
+	_, err := Start(info, nil, nil, WithPaillierKey(invalid))(nil)
+	assert.Error(t, err, "a Paillier key with p = q should be rejected")
+}
+
+func TestKeygenEntropy(t *testing.T) {
+	partyIDs := test.PartyIDs(2)
+	beacon := []byte("randomness beacon output")
+	rounds := testKeygenWith(t, partyIDs, func(id party.ID) []Option {
+		if id == partyIDs[0] {
+			return []Option{WithEntropy(beacon)}
+		}
+		return nil
+	})
+	c := rounds[0].(*round.Output).Result.(*config.Config)
+	assert.NoError(t, c.RID.Validate())
+	assert.NoError(t, c.ChainKey.Validate())
+
+	info := round.Info{
+		ProtocolID:       "cmp/keygen-test",
+		FinalRoundNumber: Rounds,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs,
+		Threshold:        1,
+		Group:            group,
+	}
+	_, err := Start(info, nil, nil, WithEntropy(make([]byte, MaxEntropy+1)))(nil)
+	assert.Error(t, err, "entropy longer than MaxEntropy should be rejected")
+	_, err = Start(info, nil, c, WithEntropy(beacon))(nil)
+	assert.Error(t, err, "a refresh should reject entropy")
+}
+
+// shareRule makes the first party send an encryption of 1 as the share of the others.
+type shareRule struct{ from party.ID }
+
+func (shareRule) ModifyBefore(round.Session) {}
+func (shareRule) ModifyAfter(round.Session)  {}
+func (rule shareRule) ModifyContent(rNext round.Session, to party.ID, content round.Content) {
+	r, ok := rNext.(*round4)
+	body, isShare := content.(*message4)
+	if !ok || !isShare || r.SelfID() != rule.from {
+		return
+	}
+	body.Share, _ = r.PaillierPublic[to].Enc(new(saferith.Int).SetUint64(1))
+}
+
+func TestKeygenInvalidShare(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	partyIDs := test.PartyIDs(2)
+	rounds := make([]round.Session, 0, len(partyIDs))
+	for _, partyID := range partyIDs {
+		info := round.Info{
+			ProtocolID:       "cmp/keygen-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           partyID,
+			PartyIDs:         partyIDs,
+			Threshold:        1,
+			Group:            group,
+		}
+		r, err := Start(info, pl, nil)(nil)
+		require.NoError(t, err)
+		rounds = append(rounds, r)
+	}
+	for {
+		err, done := test.Rounds(rounds, shareRule{from: partyIDs[0]})
+		if err != nil || done {
+			break
+		}
+	}
+	for _, r := range rounds {
+		if r.SelfID() == partyIDs[0] {
+			continue
+		}
+		require.IsType(t, &round.Abort{}, r, "the receiver of an invalid share should abort")
+		assert.Equal(t, []party.ID{partyIDs[0]}, r.(*round.Abort).Culprits)
+	}
+}
+
+func testKeygen(t *testing.T, opts ...Option) []round.Session {
+	return testKeygenWith(t, test.PartyIDs(2), func(party.ID) []Option { return opts })
+}
+
+func testKeygenWith(t *testing.T, partyIDs party.IDSlice, opts func(party.ID) []Option) []round.Session {
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
 
-	N := 2
-	partyIDs := test.PartyIDs(N)
+	N := len(partyIDs)
 
+	tracer := test.NewTracer()
 	rounds := make([]round.Session, 0, N)
 	for _, partyID := range partyIDs {
 		info := round.Info{
@@ -71,9 +185,9 @@ func TestKeygen(t *testing.T) {
 			Threshold:        N - 1,
 			Group:            group,
 		}
-		r, err := Start(info, pl, nil)(nil)
+		r, err := Start(info, pl, nil, opts(partyID)...)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
-		rounds = append(rounds, r)
+		rounds = append(rounds, tracer.Session(r))
 	}
 
 	for {
@@ -83,7 +197,9 @@ func TestKeygen(t *testing.T) {
 			break
 		}
 	}
+	require.NoError(t, tracer.Check(Model()), "rounds should follow the model")
 	checkOutput(t, rounds)
+	return rounds
 }
 
 func TestRefresh(t *testing.T) {
@@ -118,4 +234,79 @@ func TestRefresh(t *testing.T) {
 		}
 	}
 	checkOutput(t, rounds)
+
+	proofs := make([]*config.RefreshProof, 0, N)
+	for _, r := range rounds {
+		c := r.(*round.Output).Result.(*config.Config)
+		proof, err := c.ProveRefresh(configs[c.ID])
+		require.NoError(t, err)
+		data, err := cbor.Marshal(proof)
+		require.NoError(t, err)
+		decoded := config.EmptyRefreshProof(group)
+		require.NoError(t, cbor.Unmarshal(data, decoded))
+		proofs = append(proofs, decoded)
+	}
+	previous := configs[proofs[0].ID]
+	next := rounds[0].(*round.Output).Result.(*config.Config)
+	assert.Equal(t, previous.KeyID(), next.KeyID(), "the key ID should survive a refresh")
+	// observers only need the public data
+	observed := &config.Config{Group: group, Threshold: next.Threshold, Public: next.Public}
+	assert.NoError(t, config.VerifyRefresh(previous, observed, proofs))
+	assert.Error(t, config.VerifyRefresh(previous, next, proofs[1:]), "missing proof")
+	assert.Error(t, config.VerifyRefresh(next, previous, proofs), "proofs for the wrong direction")
+}
+
+func TestRefreshAuxInfo(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N, T := 3, 1
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(2)), pl)
+
+	rounds := make([]round.Session, 0, N)
+	for _, c := range configs {
+		info := round.Info{
+			ProtocolID:       "cmp/refresh-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           c.ID,
+			PartyIDs:         c.PartyIDs(),
+			Threshold:        T,
+			Group:            group,
+		}
+		r, err := Start(info, pl, c, WithAuxInfoOnly())(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	checkOutput(t, rounds)
+
+	for _, r := range rounds {
+		c := r.(*round.Output).Result.(*config.Config)
+		previous := configs[c.ID]
+		assert.True(t, previous.ECDSA.Equal(c.ECDSA), "the ECDSA share should be kept")
+		assert.Equal(t, previous.ChainKey, c.ChainKey)
+		assert.False(t, previous.Paillier.PublicKey.Equal(c.Paillier.PublicKey), "the Paillier key should be new")
+		for _, id := range partyIDs {
+			assert.True(t, previous.Public[id].ECDSA.Equal(c.Public[id].ECDSA))
+			assert.False(t, previous.Public[id].Pedersen.N().Nat().Eq(c.Public[id].Pedersen.N().Nat()) == 1)
+		}
+	}
+
+	info := round.Info{
+		ProtocolID:       "cmp/keygen-test",
+		FinalRoundNumber: Rounds,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs,
+		Threshold:        T,
+		Group:            group,
+	}
+	_, err := Start(info, pl, nil, WithAuxInfoOnly())(nil)
+	assert.Error(t, err, "keygen has no shares to keep")
 }
@@ -0,0 +1,17 @@
+package keygen
+
+import "github.com/taurusgroup/multi-party-sig/pkg/protocol"
+
+// Model returns the state machine of the keygen and refresh protocols, which share the same rounds.
+func Model() *protocol.Model {
+	return &protocol.Model{
+		Name: "cmp/keygen",
+		Rounds: []protocol.RoundModel{
+			{Name: "round1", Number: 1, Next: []string{"round2"}},
+			{Name: "round2", Number: 2, Broadcast: protocol.ReliableBroadcast, Next: []string{"round3"}},
+			{Name: "round3", Number: 3, Broadcast: protocol.NormalBroadcast, Next: []string{"round4"}},
+			{Name: "round4", Number: 4, Broadcast: protocol.NormalBroadcast, Message: true, Next: []string{"round5"}, Abort: true},
+			{Name: "round5", Number: 5, Broadcast: protocol.NormalBroadcast, Output: true, Abort: true},
+		},
+	}
+}
@@ -0,0 +1,107 @@
+package keygen
+
+import (
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+)
+
+// Option modifies the behaviour of the key generation protocol.
+//
+// All parties must use the same options, since they are bound to the session.
+type Option func(*options)
+
+type options struct {
+	// primes supplies the primes of the Paillier key, if not nil.
+	primes *paillier.PrimePool
+	// paillierSecret is used as the Paillier key, if not nil.
+	paillierSecret *paillier.SecretKey
+	// hash is the function used for the transcript of the session.
+	hash hash.Function
+	// iterations is the number of repetitions of the zkmod and zkprm proofs, or 0 for the default.
+	iterations int
+	// profile determines the size of the Paillier moduli, or is 0 for the default.
+	profile paillier.SecurityProfile
+	// auxInfoOnly keeps the ECDSA shares during a refresh.
+	auxInfoOnly bool
+	// entropy is mixed into the RID and chain key, if not empty.
+	entropy []byte
+}
+
+// MaxEntropy is the maximum length of the entropy given to WithEntropy.
+const MaxEntropy = 256
+
+// WithPrimePool draws the primes of this party's Paillier key from primes, instead of generating them during the protocol.
+//
+// Unlike other options, this one only affects the local party, and doesn't need to be shared with the others.
+func WithPrimePool(primes *paillier.PrimePool) Option {
+	return func(o *options) {
+		o.primes = primes
+	}
+}
+
+// WithPaillierKey uses sk as this party's Paillier key, instead of generating a new one during the protocol,
+// for example when sk was prepared offline or in an HSM.
+//
+// The other parties still receive proofs that N is a Paillier-Blum modulus, and that the Pedersen parameters
+// derived from sk are correct. As with WithPrimePool, this option only affects the local party.
+// A refresh fails if sk is the key of the config being refreshed.
+func WithPaillierKey(sk *paillier.SecretKey) Option {
+	return func(o *options) {
+		o.paillierSecret = sk
+	}
+}
+
+// WithHash uses f for the transcript of the session, and for the challenges of the proofs exchanged during it.
+func WithHash(f hash.Function) Option {
+	return func(o *options) {
+		o.hash = f
+	}
+}
+
+// WithStatisticalSecurity sets the number of repetitions of the proofs that each Paillier modulus is a Blum integer,
+// and of the correctness of the Pedersen parameters, which is their statistical security parameter.
+//
+// The default is params.StatParam (80). Values below that floor are raised to it,
+// and values above params.MaxStatParam (256) are lowered to it.
+func WithStatisticalSecurity(iterations int) Option {
+	return func(o *options) {
+		o.iterations = iterations
+	}
+}
+
+// WithSecurityProfile generates Paillier keys, and the Pedersen parameters over them, with the moduli size of profile,
+// and rejects the moduli of other parties of another size.
+//
+// During a refresh, the profile of the config being refreshed is kept unless this option is given.
+func WithSecurityProfile(profile paillier.SecurityProfile) Option {
+	return func(o *options) {
+		o.profile = profile
+	}
+}
+
+// WithAuxInfoOnly makes a refresh only regenerate the auxiliary information of each party:
+// its Paillier key, Pedersen parameters and ElGamal key, as in the auxiliary info phase of CGGMP.
+// The ECDSA shares are kept, so the refresh doesn't need to happen at the same time as those of the shares.
+//
+// Each party then deals the zero polynomial of degree 0, and rejects the others if they don't.
+// This option is bound to the session, and Keygen fails with it.
+func WithAuxInfoOnly() Option {
+	return func(o *options) {
+		o.auxInfoOnly = true
+	}
+}
+
+// WithEntropy contributes entropy, such as the output of a randomness beacon, to the RID and chain key of the keygen.
+//
+// The entropy is committed to in the first round along with the other values of this party, and revealed in the third,
+// so the transcript shows that it was chosen before the contributions of the other parties were known.
+// The RID and chain key are then derived from the XOR of the random contributions and from all the entropies revealed.
+// The random contributions are still sampled, so public entropy doesn't make the outputs predictable.
+//
+// As with WithPrimePool, this option only affects the local party: each party may contribute its own entropy, or none.
+// It is at most MaxEntropy bytes long, and Refresh fails with this option.
+func WithEntropy(entropy []byte) Option {
+	return func(o *options) {
+		o.entropy = entropy
+	}
+}
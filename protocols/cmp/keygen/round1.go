@@ -1,9 +1,9 @@
 package keygen
 
 import (
-	"crypto/rand"
 	"errors"
 
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
@@ -21,6 +21,15 @@ var _ round.Round = (*round1)(nil)
 type round1 struct {
 	*round.Helper
 
+	// Primes, if not nil, supplies the primes for our Paillier key.
+	Primes *paillier.PrimePool
+	// PaillierSecret, if not nil, is used as our Paillier key instead of generating one.
+	PaillierSecret *paillier.SecretKey
+	// Iterations is the number of repetitions of the zkmod and zkprm proofs, see params.StatIterations.
+	Iterations int
+	// Profile determines the size of the Paillier moduli of all parties.
+	Profile paillier.SecurityProfile
+
 	// PreviousSecretECDSA = sk'ᵢ
 	// Contains the previous secret ECDSA key share which is being refreshed
 	// Keygen:  sk'ᵢ = nil
@@ -37,10 +46,17 @@ type round1 struct {
 	// In that case, we will simply use the previous chain key at the very end.
 	PreviousChainKey types.RID
 
+	// AuxInfoOnly is true if this refresh keeps the ECDSA shares, in which case all VSS polynomials are 0.
+	AuxInfoOnly bool
+
+	// Entropy is our contribution to the RID and chain key on top of the random ones, if not empty.
+	Entropy []byte
+
 	// VSSSecret = fᵢ(X)
 	// Polynomial from which the new secret shares are computed.
 	// Keygen:  fᵢ(0) = xⁱ
 	// Refresh: fᵢ(0) = 0
+	// Aux info: fᵢ(X) = 0
 	VSSSecret *polynomial.Polynomial
 }
 
@@ -62,11 +78,17 @@ func (r *round1) StoreMessage(round.Message) error { return nil }
 // - commit to message.
 func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// generate Paillier and Pedersen
-	PaillierSecret := paillier.NewSecretKey(nil)
+	PaillierSecret := r.PaillierSecret
+	if PaillierSecret == nil && r.Primes != nil {
+		PaillierSecret = r.Primes.NewSecretKey()
+	}
+	if PaillierSecret == nil {
+		PaillierSecret = r.Profile.NewSecretKey(nil)
+	}
 	SelfPaillierPublic := PaillierSecret.PublicKey
 	SelfPedersenPublic, PedersenSecret := PaillierSecret.GeneratePedersen()
 
-	ElGamalSecret, ElGamalPublic := sample.ScalarPointPair(rand.Reader, r.Group())
+	ElGamalSecret, ElGamalPublic := sample.ScalarPointPair(random.Reader(), r.Group())
 
 	// save our own share already so we are consistent with what we receive from others
 	SelfShare := r.VSSSecret.Evaluate(r.SelfID().Scalar(r.Group()))
@@ -75,22 +97,22 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	SelfVSSPolynomial := polynomial.NewPolynomialExponent(r.VSSSecret)
 
 	// generate Schnorr randomness
-	SchnorrRand := zksch.NewRandomness(rand.Reader, r.Group(), nil)
+	SchnorrRand := zksch.NewRandomness(random.Reader(), r.Group(), nil)
 
 	// Sample RIDᵢ
-	SelfRID, err := types.NewRID(rand.Reader)
+	SelfRID, err := types.NewRID(random.Reader())
 	if err != nil {
 		return r, errors.New("failed to sample Rho")
 	}
-	chainKey, err := types.NewRID(rand.Reader)
+	chainKey, err := types.NewRID(random.Reader())
 	if err != nil {
 		return r, errors.New("failed to sample c")
 	}
 
 	// commit to data in message 2
-	SelfCommitment, Decommitment, err := r.HashForID(r.SelfID()).Commit(
+	SelfCommitment, Decommitment, err := r.HashForID(r.SelfID()).Commit(append([]interface{}{
 		SelfRID, chainKey, SelfVSSPolynomial, SchnorrRand.Commitment(), ElGamalPublic,
-		SelfPedersenPublic.N(), SelfPedersenPublic.S(), SelfPedersenPublic.T())
+		SelfPedersenPublic.N(), SelfPedersenPublic.S(), SelfPedersenPublic.T()}, entropyData(r.Entropy)...)...)
 	if err != nil {
 		return r, errors.New("failed to commit")
 	}
@@ -108,6 +130,7 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 		Commitments:    map[party.ID]hash.Commitment{r.SelfID(): SelfCommitment},
 		RIDs:           map[party.ID]types.RID{r.SelfID(): SelfRID},
 		ChainKeys:      map[party.ID]types.RID{r.SelfID(): chainKey},
+		Entropies:      map[party.ID][]byte{r.SelfID(): r.Entropy},
 		ShareReceived:  map[party.ID]curve.Scalar{r.SelfID(): SelfShare},
 		ElGamalPublic:  map[party.ID]curve.Point{r.SelfID(): ElGamalPublic},
 		PaillierPublic: map[party.ID]*paillier.PublicKey{r.SelfID(): SelfPaillierPublic},
@@ -129,3 +152,12 @@ func (round1) MessageContent() round.Content { return nil }
 
 // Number implements round.Round.
 func (round1) Number() round.Number { return 1 }
+
+// entropyData returns the entropy contributed by a party as committed data, or nothing if it didn't contribute any,
+// so that the commitments of parties without entropy don't change.
+func entropyData(entropy []byte) []interface{} {
+	if len(entropy) == 0 {
+		return nil
+	}
+	return []interface{}{hash.BytesWithDomain{TheDomain: "Keygen Entropy", Bytes: entropy}}
+}
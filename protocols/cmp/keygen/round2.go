@@ -28,6 +28,8 @@ type round2 struct {
 	RIDs map[party.ID]types.RID
 	// ChainKeys[j] = cⱼ
 	ChainKeys map[party.ID]types.RID
+	// Entropies[j] is the entropy contributed by party j, which may be empty
+	Entropies map[party.ID][]byte
 
 	// ShareReceived[j] = xʲᵢ
 	// share received from party j
@@ -98,6 +100,7 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 		S:                  r.Pedersen[r.SelfID()].S(),
 		T:                  r.Pedersen[r.SelfID()].T(),
 		Decommitment:       r.Decommitment,
+		Entropy:            r.Entropy,
 	})
 	if err != nil {
 		return r, err
@@ -47,6 +47,8 @@ type broadcast3 struct {
 	T *saferith.Nat
 	// Decommitment = uᵢ decommitment bytes
 	Decommitment hash.Decommitment
+	// Entropy is the entropy contributed by the party, if any
+	Entropy []byte `cbor:",omitempty"`
 }
 
 // StoreBroadcastMessage implements round.BroadcastRound.
@@ -55,6 +57,7 @@ type broadcast3 struct {
 // - verify degree of VSS polynomial Fⱼ "in-the-exponent"
 //   - if keygen, verify Fⱼ(0) != ∞
 //   - if refresh, verify Fⱼ(0) == ∞
+//   - if aux info only, verify Fⱼ(X) == ∞
 //
 // - validate Paillier
 // - validate Pedersen
@@ -82,6 +85,12 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	if err := body.Decommitment.Validate(); err != nil {
 		return err
 	}
+	if len(body.Entropy) > MaxEntropy {
+		return errors.New("entropy is too long")
+	}
+	if len(body.Entropy) > 0 && r.PreviousSecretECDSA != nil {
+		return errors.New("entropy can only be contributed to a new key")
+	}
 
 	// Save all X, VSSCommitments
 	VSSPolynomial := body.VSSPolynomial
@@ -90,13 +99,17 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	if !(r.VSSSecret.Constant().IsZero() == VSSPolynomial.IsConstant) {
 		return errors.New("vss polynomial has incorrect constant")
 	}
-	// check deg(Fⱼ) = t
-	if VSSPolynomial.Degree() != r.Threshold() {
+	// check deg(Fⱼ) = t, or Fⱼ(X) = 0 if only the aux info is refreshed
+	if r.AuxInfoOnly {
+		if VSSPolynomial.Degree() != 0 {
+			return errors.New("vss polynomial must be 0 when refreshing aux info")
+		}
+	} else if VSSPolynomial.Degree() != r.Threshold() {
 		return errors.New("vss polynomial has incorrect degree")
 	}
 
 	// Set Paillier
-	if err := paillier.ValidateN(body.N); err != nil {
+	if err := r.Profile.ValidateN(body.N); err != nil {
 		return err
 	}
 
@@ -105,12 +118,14 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 		return err
 	}
 	// Verify decommit
-	if !r.HashForID(from).Decommit(r.Commitments[from], body.Decommitment,
-		body.RID, body.C, VSSPolynomial, body.SchnorrCommitments, body.ElGamalPublic, body.N, body.S, body.T) {
+	if !r.HashForID(from).Decommit(r.Commitments[from], body.Decommitment, append([]interface{}{
+		body.RID, body.C, VSSPolynomial, body.SchnorrCommitments, body.ElGamalPublic, body.N, body.S, body.T},
+		entropyData(body.Entropy)...)...) {
 		return errors.New("failed to decommit")
 	}
 	r.RIDs[from] = body.RID
 	r.ChainKeys[from] = body.C
+	r.Entropies[from] = body.Entropy
 	r.PaillierPublic[from] = paillier.NewPublicKey(body.N)
 	r.Pedersen[from] = pedersen.New(arith.ModulusFromN(body.N), body.S, body.T)
 	r.VSSPolynomials[from] = body.VSSPolynomial
@@ -120,6 +135,18 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	return nil
 }
 
+// entropies returns the entropy contributed by each party, along with its ID, ordered by party.
+func (r *round3) entropies() []interface{} {
+	var entropies []interface{}
+	for _, j := range r.PartyIDs() {
+		if data := entropyData(r.Entropies[j]); data != nil {
+			entropies = append(entropies, j)
+			entropies = append(entropies, data...)
+		}
+	}
+	return entropies
+}
+
 // VerifyMessage implements round.Round.
 func (round3) VerifyMessage(round.Message) error { return nil }
 
@@ -149,6 +176,17 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	for _, j := range r.PartyIDs() {
 		rid.XOR(r.RIDs[j])
 	}
+	if entropies := r.entropies(); len(entropies) > 0 {
+		// (rid, c) = H(rid, c, entropies), which only happens during a keygen
+		digest := r.Hash().Fork(append([]interface{}{rid, chainKey}, entropies...)...).Digest()
+		var err error
+		if rid, err = types.NewRID(digest); err != nil {
+			return r, err
+		}
+		if chainKey, err = types.NewRID(digest); err != nil {
+			return r, err
+		}
+	}
 
 	// temporary hash which does not modify the state
 	h := r.Hash()
@@ -159,7 +197,7 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 		P:   r.PaillierSecret.P(),
 		Q:   r.PaillierSecret.Q(),
 		Phi: r.PaillierSecret.Phi(),
-	}, zkmod.Public{N: r.PaillierPublic[r.SelfID()].N()}, r.Pool)
+	}, zkmod.Public{N: r.PaillierPublic[r.SelfID()].N(), Iterations: r.Iterations}, r.Pool)
 
 	// prove s, t are correct as aux parameters with zkprm
 	prm := zkprm.NewProof(zkprm.Private{
@@ -167,7 +205,7 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 		Phi:    r.PaillierSecret.Phi(),
 		P:      r.PaillierSecret.P(),
 		Q:      r.PaillierSecret.Q(),
-	}, h.Clone(), zkprm.Public{Aux: r.Pedersen[r.SelfID()]}, r.Pool)
+	}, h.Clone(), zkprm.Public{Aux: r.Pedersen[r.SelfID()], Iterations: r.Iterations}, r.Pool)
 
 	if err := r.BroadcastMessage(out, &broadcast4{
 		Mod: mod,
@@ -202,9 +240,10 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Write rid to the hash state
 	r.UpdateHashState(rid)
 	return &round4{
-		round3:   r,
-		RID:      rid,
-		ChainKey: chainKey,
+		round3:          r,
+		RID:             rid,
+		ChainKey:        chainKey,
+		EncryptedShares: make(map[party.ID]*paillier.Ciphertext, r.N()-1),
 	}, nil
 }
 
@@ -12,6 +12,7 @@ import (
 	zkfac "github.com/taurusgroup/multi-party-sig/pkg/zk/fac"
 	zkmod "github.com/taurusgroup/multi-party-sig/pkg/zk/mod"
 	zkprm "github.com/taurusgroup/multi-party-sig/pkg/zk/prm"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
 
@@ -25,6 +26,8 @@ type round4 struct {
 	RID types.RID
 	// ChainKey is a sequence of random bytes agreed upon together
 	ChainKey types.RID
+	// EncryptedShares[j] = Encᵢ(xʲᵢ), which is decrypted and checked against Fⱼ for all j at once, in Finalize
+	EncryptedShares map[party.ID]*paillier.Ciphertext
 }
 
 type message4 struct {
@@ -50,12 +53,12 @@ func (r *round4) StoreBroadcastMessage(msg round.Message) error {
 	}
 
 	// verify zkmod
-	if !body.Mod.Verify(zkmod.Public{N: r.Pedersen[from].N()}, r.HashForID(from), r.Pool) {
+	if !body.Mod.Verify(zkmod.Public{N: r.Pedersen[from].N(), Iterations: r.Iterations}, r.HashForID(from), r.Pool) {
 		return errors.New("failed to validate mod proof")
 	}
 
 	// verify zkprm
-	if !body.Prm.Verify(zkprm.Public{Aux: r.Pedersen[from]}, r.HashForID(from), r.Pool) {
+	if !body.Prm.Verify(zkprm.Public{Aux: r.Pedersen[from], Iterations: r.Iterations}, r.HashForID(from), r.Pool) {
 		return errors.New("failed to validate prm proof")
 	}
 
@@ -86,33 +89,57 @@ func (r *round4) VerifyMessage(msg round.Message) error {
 
 // StoreMessage implements round.Round.
 //
-// Since this message is only intended for us, we need to do the VSS verification here.
-// - check that the decrypted share did not overflow.
-// - check VSS condition.
-// - save share.
+// - save the encrypted share, which is verified in Finalize.
 func (r *round4) StoreMessage(msg round.Message) error {
 	from, body := msg.From, msg.Content.(*message4)
+	r.EncryptedShares[from] = body.Share
+	return nil
+}
 
-	// decrypt share
-	DecryptedShare, err := r.PaillierSecret.Dec(body.Share)
-	if err != nil {
-		return err
-	}
-	Share := r.Group().NewScalar().SetNat(DecryptedShare.Mod(r.Group().Order()))
-	if DecryptedShare.Eq(curve.MakeInt(Share)) != 1 {
-		return errors.New("decrypted share is not in correct range")
-	}
+// decryptShares decrypts the shares of all other parties, and verifies them against their VSS polynomials,
+// concurrently on the pool. If some shares are invalid, their senders are returned with the first error.
+//
+// - check that the decrypted share did not overflow.
+// - check VSS condition.
+// - save share.
+func (r *round4) decryptShares() ([]party.ID, error) {
+	otherIDs := r.OtherPartyIDs()
+	self := r.SelfID().Scalar(r.Group())
+	results := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
+		from := otherIDs[i]
+		// decrypt share
+		DecryptedShare, err := r.PaillierSecret.Dec(r.EncryptedShares[from])
+		if err != nil {
+			return err
+		}
+		Share := r.Group().NewScalar().SetNat(DecryptedShare.Mod(r.Group().Order()))
+		if DecryptedShare.Eq(curve.MakeInt(Share)) != 1 {
+			return errors.New("decrypted share is not in correct range")
+		}
 
-	// verify share with VSS
-	ExpectedPublicShare := r.VSSPolynomials[from].Evaluate(r.SelfID().Scalar(r.Group())) // Fⱼ(i)
-	PublicShare := Share.ActOnBase()
-	// X == Fⱼ(i)
-	if !PublicShare.Equal(ExpectedPublicShare) {
-		return errors.New("failed to validate VSS share")
+		// verify share with VSS
+		ExpectedPublicShare := r.VSSPolynomials[from].Evaluate(self) // Fⱼ(i)
+		PublicShare := Share.ActOnBase()
+		// X == Fⱼ(i)
+		if !PublicShare.Equal(ExpectedPublicShare) {
+			return errors.New("failed to validate VSS share")
+		}
+		return Share
+	})
+
+	var culprits []party.ID
+	var firstErr error
+	for i, result := range results {
+		if err, ok := result.(error); ok {
+			culprits = append(culprits, otherIDs[i])
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		r.ShareReceived[otherIDs[i]] = result.(curve.Scalar)
 	}
-
-	r.ShareReceived[from] = Share
-	return nil
+	return culprits, firstErr
 }
 
 // Finalize implements round.Round
@@ -124,6 +151,10 @@ func (r *round4) StoreMessage(msg round.Message) error {
 // - write new ssid hash to old hash state
 // - create proof of knowledge of secret.
 func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
+	if culprits, err := r.decryptShares(); len(culprits) > 0 {
+		return r.AbortRound(err, culprits...), nil
+	}
+
 	// add all shares to our secret
 	UpdatedSecretECDSA := r.Group().NewScalar()
 	if r.PreviousSecretECDSA != nil {
@@ -147,8 +178,13 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 
 	// compute the new public key share Xⱼ = F(j) (+X'ⱼ if doing a refresh)
 	PublicData := make(map[party.ID]*config.Public, len(r.PartyIDs()))
-	for _, j := range r.PartyIDs() {
-		PublicECDSAShare := ShamirPublicPolynomial.Evaluate(j.Scalar(r.Group()))
+	xs := make([]curve.Scalar, len(r.PartyIDs()))
+	for i, j := range r.PartyIDs() {
+		xs[i] = j.Scalar(r.Group())
+	}
+	PublicECDSAShares := ShamirPublicPolynomial.EvaluateMulti(xs)
+	for i, j := range r.PartyIDs() {
+		PublicECDSAShare := PublicECDSAShares[i]
 		if r.PreviousPublicSharesECDSA != nil {
 			PublicECDSAShare = PublicECDSAShare.Add(r.PreviousPublicSharesECDSA[j])
 		}
@@ -187,8 +223,9 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 
 	r.UpdateHashState(UpdatedConfig)
 	return &round5{
-		round4:        r,
-		UpdatedConfig: UpdatedConfig,
+		round4:           r,
+		UpdatedConfig:    UpdatedConfig,
+		SchnorrResponses: make(map[party.ID]*zksch.Response, r.N()-1),
 	}, nil
 }
 
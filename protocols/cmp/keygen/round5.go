@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	sch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
 	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
 )
@@ -13,6 +14,8 @@ var _ round.Round = (*round5)(nil)
 type round5 struct {
 	*round4
 	UpdatedConfig *config.Config
+	// SchnorrResponses[j] proves knowledge of the new secret share of j, and is verified in Finalize
+	SchnorrResponses map[party.ID]*sch.Response
 }
 
 type broadcast5 struct {
@@ -23,9 +26,8 @@ type broadcast5 struct {
 
 // StoreBroadcastMessage implements round.BroadcastRound.
 //
-// - verify all Schnorr proof for the new ecdsa share.
+// - save the Schnorr proof for the new ecdsa share, which is verified in Finalize.
 func (r *round5) StoreBroadcastMessage(msg round.Message) error {
-	from := msg.From
 	body, ok := msg.Content.(*broadcast5)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
@@ -35,11 +37,7 @@ func (r *round5) StoreBroadcastMessage(msg round.Message) error {
 		return round.ErrNilFields
 	}
 
-	if !body.SchnorrResponse.Verify(r.HashForID(from),
-		r.UpdatedConfig.Public[from].ECDSA,
-		r.SchnorrCommitments[from], nil) {
-		return errors.New("failed to validate schnorr proof for received share")
-	}
+	r.SchnorrResponses[msg.From] = body.SchnorrResponse
 	return nil
 }
 
@@ -50,7 +48,25 @@ func (round5) VerifyMessage(round.Message) error { return nil }
 func (r *round5) StoreMessage(round.Message) error { return nil }
 
 // Finalize implements round.Round.
+//
+// - verify all Schnorr proofs for the new ecdsa shares, concurrently.
 func (r *round5) Finalize(chan<- *round.Message) (round.Session, error) {
+	otherIDs := r.OtherPartyIDs()
+	valid := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
+		from := otherIDs[i]
+		return r.SchnorrResponses[from].Verify(r.HashForID(from),
+			r.UpdatedConfig.Public[from].ECDSA,
+			r.SchnorrCommitments[from], nil)
+	})
+	var culprits []party.ID
+	for i, ok := range valid {
+		if !ok.(bool) {
+			culprits = append(culprits, otherIDs[i])
+		}
+	}
+	if len(culprits) > 0 {
+		return r.AbortRound(errors.New("failed to validate schnorr proof for received share"), culprits...), nil
+	}
 	return r.ResultRound(r.UpdatedConfig), nil
 }
 
@@ -0,0 +1,38 @@
+package presign
+
+import "github.com/taurusgroup/multi-party-sig/pkg/protocol"
+
+// Model returns the state machine of the presigning protocol.
+//
+// When a message is given, presign7 continues directly with the online signature in sign2,
+// and the presignature is not output.
+// If the presignature is inconsistent, presign6 and presign7 lead to abort1 and abort2 respectively,
+// in which parties reveal enough of their state to identify the culprits.
+func Model() *protocol.Model {
+	return &protocol.Model{
+		Name: protocolFullID,
+		Rounds: []protocol.RoundModel{
+			{Name: "presign1", Number: 1, Next: []string{"presign2"}},
+			{Name: "presign2", Number: 2, Broadcast: protocol.ReliableBroadcast, Message: true, Next: []string{"presign3"}},
+			{Name: "presign3", Number: 3, Broadcast: protocol.NormalBroadcast, Message: true, Next: []string{"presign4"}, Abort: true},
+			{Name: "presign4", Number: 4, Broadcast: protocol.NormalBroadcast, Next: []string{"presign5"}},
+			{Name: "presign5", Number: 5, Broadcast: protocol.NormalBroadcast, Message: true, Next: []string{"presign6"}},
+			{Name: "presign6", Number: 6, Broadcast: protocol.NormalBroadcast, Next: []string{"presign7", "abort1"}},
+			{Name: "presign7", Number: 7, Broadcast: protocol.NormalBroadcast, Next: []string{"sign2", "abort2"}, Output: true},
+			{Name: "abort1", Number: 7, Broadcast: protocol.NormalBroadcast, Abort: true},
+			{Name: "abort2", Number: 8, Broadcast: protocol.NormalBroadcast, Abort: true},
+			{Name: "sign2", Number: 8, Broadcast: protocol.NormalBroadcast, Output: true, Abort: true},
+		},
+	}
+}
+
+// OnlineModel returns the state machine of the online signing protocol, using a presignature.
+func OnlineModel() *protocol.Model {
+	return &protocol.Model{
+		Name: protocolOnlineID,
+		Rounds: []protocol.RoundModel{
+			{Name: "sign1", Number: 1, Next: []string{"sign2"}},
+			{Name: "sign2", Number: 8, Broadcast: protocol.NormalBroadcast, Output: true, Abort: true},
+		},
+	}
+}
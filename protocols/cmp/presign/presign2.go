@@ -12,8 +12,6 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
-	zkaffg "github.com/taurusgroup/multi-party-sig/pkg/zk/affg"
-	zkaffp "github.com/taurusgroup/multi-party-sig/pkg/zk/affp"
 	zkencelg "github.com/taurusgroup/multi-party-sig/pkg/zk/encelg"
 )
 
@@ -127,38 +125,22 @@ func (r *presign2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	otherIDs := r.OtherPartyIDs()
 	n := len(otherIDs)
 
-	type mtaOut struct {
-		DeltaBeta  *saferith.Int
-		DeltaD     *paillier.Ciphertext
-		DeltaF     *paillier.Ciphertext
-		DeltaProof *zkaffp.Proof
-		ChiBeta    *saferith.Int
-		ChiD       *paillier.Ciphertext
-		ChiF       *paillier.Ciphertext
-		ChiProof   *zkaffg.Proof
-	}
-	mtaOuts := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
-		j := otherIDs[i]
-
-		DeltaBeta, DeltaD, DeltaF, DeltaProof := mta.ProveAffP(r.Group(), r.HashForID(r.SelfID()),
-			r.GammaShare, r.G[r.SelfID()], r.GNonce, r.K[j],
-			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
-
-		ChiBeta, ChiD, ChiF, ChiProof := mta.ProveAffG(r.Group(), r.HashForID(r.SelfID()),
-			curve.MakeInt(r.SecretECDSA), r.ECDSA[r.SelfID()], r.K[j],
-			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
-
-		return mtaOut{
-			DeltaBeta:  DeltaBeta,
-			DeltaD:     DeltaD,
-			DeltaF:     DeltaF,
-			DeltaProof: DeltaProof,
-			ChiBeta:    ChiBeta,
-			ChiD:       ChiD,
-			ChiF:       ChiF,
-			ChiProof:   ChiProof,
+	receivers := make(map[party.ID]mta.Receiver, n)
+	for _, j := range otherIDs {
+		receivers[j] = mta.Receiver{
+			EncryptedShare: r.K[j],
+			Paillier:       r.Paillier[j],
+			Pedersen:       r.Pedersen[j],
 		}
-	})
+	}
+	newHash := func() *hash.Hash { return r.HashForID(r.SelfID()) }
+	DeltaMtA := mta.ProveAffPBatch(r.Group(), newHash,
+		r.GammaShare, r.G[r.SelfID()], r.GNonce,
+		r.SecretPaillier, receivers, r.Pool)
+	ChiMtA := mta.ProveAffGBatch(r.Group(), newHash,
+		curve.MakeInt(r.SecretECDSA), r.ECDSA[r.SelfID()],
+		r.SecretPaillier, receivers, r.Pool)
+
 	ChiCiphertext := make(map[party.ID]*paillier.Ciphertext, n)
 	DeltaCiphertext := make(map[party.ID]*paillier.Ciphertext, n)
 	DeltaShareBeta := make(map[party.ID]*saferith.Int, n)
@@ -170,18 +152,17 @@ func (r *presign2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 
 	msgs := make(map[party.ID]*message3, n)
-	for idx, mtaOutRaw := range mtaOuts {
-		j := otherIDs[idx]
-		m := mtaOutRaw.(mtaOut)
-		DeltaShareBeta[j] = m.DeltaBeta
-		DeltaCiphertext[j] = m.DeltaD
-		ChiShareBeta[j] = m.ChiBeta
-		ChiCiphertext[j] = m.ChiD
+	for _, j := range otherIDs {
+		delta, chi := DeltaMtA[j], ChiMtA[j]
+		DeltaShareBeta[j] = delta.Beta
+		DeltaCiphertext[j] = delta.D
+		ChiShareBeta[j] = chi.Beta
+		ChiCiphertext[j] = chi.D
 		msgs[j] = &message3{
-			DeltaF:     m.DeltaF,
-			DeltaProof: m.DeltaProof,
-			ChiF:       m.ChiF,
-			ChiProof:   m.ChiProof,
+			DeltaF:     delta.F,
+			DeltaProof: delta.Proof,
+			ChiF:       chi.F,
+			ChiProof:   chi.Proof,
 		}
 	}
 
@@ -26,6 +26,10 @@ const (
 	protocolFullRounds    round.Number = 8
 )
 
+// lagrangeCache holds the Lagrange coefficients of the most recent sets of signers,
+// which are usually the same from one session to the next.
+var lagrangeCache = polynomial.NewLagrangeCache(16)
+
 func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool.Pool) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		if c == nil {
@@ -62,7 +66,7 @@ func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool
 		Paillier := make(map[party.ID]*paillier.PublicKey, T)
 		Pedersen := make(map[party.ID]*pedersen.Parameters, T)
 		PublicKey := group.NewPoint()
-		lagrange := polynomial.Lagrange(group, signers)
+		lagrange := lagrangeCache.Lagrange(group, signers)
 		// Scale own secret
 		SecretECDSA := group.NewScalar().Set(lagrange[c.ID]).Mul(c.ECDSA)
 		for _, j := range helper.PartyIDs() {
@@ -44,14 +44,14 @@ func init() {
 }
 
 func TestRound(t *testing.T) {
-
+	tracer := test.NewTracer()
 	rounds := make([]round.Session, 0, N)
 	for _, c := range configs {
 		pl := pool.NewPool(1)
 		defer pl.TearDown()
 		r, err := StartPresign(c, partyIDs, messageHash, pl)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
-		rounds = append(rounds, r)
+		rounds = append(rounds, tracer.Session(r))
 	}
 
 	for {
@@ -61,6 +61,7 @@ func TestRound(t *testing.T) {
 			break
 		}
 	}
+	require.NoError(t, tracer.Check(Model()), "rounds should follow the model")
 	for _, r := range rounds {
 		assert.IsType(t, &round.Output{}, r)
 		signature, ok := r.(*round.Output).Result.(*ecdsa.Signature)
@@ -0,0 +1,284 @@
+// Package rotation periodically refreshes the shares of a cmp key, for proactive security.
+//
+// A Scheduler holds a party's current Config, and runs cmp.Refresh with the other parties every interval,
+// or after a number of signatures, saving the refreshed Config to a Store before using it.
+// All parties must run a Scheduler with the same policy, since a refresh only completes once all of them take part.
+package rotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+)
+
+// ErrRunning is returned by Scheduler.Refresh when a refresh is already in progress.
+var ErrRunning = errors.New("rotation: a refresh is already running")
+
+// RefreshCallback is called by a Scheduler after a refresh, with the previous and the new Config.
+type RefreshCallback func(previous, next *cmp.Config)
+
+// FailureCallback is called by a Scheduler when a refresh, or saving its result, fails.
+type FailureCallback func(err error)
+
+// Option modifies the policy of a Scheduler.
+type Option func(*Scheduler)
+
+// WithInterval makes the Scheduler refresh the shares every d after Start, and after the previous refresh.
+func WithInterval(d time.Duration) Option {
+	return func(s *Scheduler) { s.interval = d }
+}
+
+// WithSignatures makes the Scheduler refresh the shares once Signed has been called n times since the previous refresh.
+//
+// Each party counts the signatures it took part in, so this only keeps parties in step
+// if every signature is produced by all of them.
+func WithSignatures(n int) Option {
+	return func(s *Scheduler) { s.signatures = n }
+}
+
+// WithTimeout sets the time after which a refresh is aborted, if some party hasn't taken part. The default is 5 minutes.
+func WithTimeout(d time.Duration) Option {
+	return func(s *Scheduler) { s.timeout = d }
+}
+
+// WithClock makes the Scheduler use c to schedule refreshes, instead of protocol.SystemClock.
+func WithClock(c protocol.Clock) Option {
+	return func(s *Scheduler) { s.clock = c }
+}
+
+// WithRefreshOptions passes opts to every cmp.Refresh run by the Scheduler.
+func WithRefreshOptions(opts ...cmp.KeygenOption) Option {
+	return func(s *Scheduler) { s.refreshOptions = append(s.refreshOptions, opts...) }
+}
+
+// Scheduler runs cmp.Refresh according to a policy, and keeps the resulting Config.
+//
+// The Transport is used for refreshes only: messages which don't belong to the refresh in progress are dropped,
+// so signing sessions must use another one.
+type Scheduler struct {
+	store          Store
+	transport      transport.Transport
+	pool           *pool.Pool
+	interval       time.Duration
+	signatures     int
+	timeout        time.Duration
+	clock          protocol.Clock
+	refreshOptions []cmp.KeygenOption
+
+	mtx    sync.Mutex
+	config *cmp.Config
+	// unsaved is true if config was refreshed, but not saved to the store yet
+	unsaved bool
+	signed  int
+	running bool
+	timer   protocol.Timer
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	onRefresh []RefreshCallback
+	onFailure []FailureCallback
+}
+
+// New returns a Scheduler for the Config in store, refreshing it over t.
+//
+// No refresh is scheduled until Start is called.
+func New(store Store, t transport.Transport, pl *pool.Pool, opts ...Option) (*Scheduler, error) {
+	config, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("rotation: failed to load config: %w", err)
+	}
+	s := &Scheduler{
+		store:     store,
+		transport: t,
+		pool:      pl,
+		timeout:   5 * time.Minute,
+		clock:     protocol.SystemClock,
+		config:    config,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// OnRefresh registers a callback, which will be called after every successful refresh.
+func (s *Scheduler) OnRefresh(callback RefreshCallback) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.onRefresh = append(s.onRefresh, callback)
+}
+
+// OnFailure registers a callback, which will be called for every refresh which fails.
+func (s *Scheduler) OnFailure(callback FailureCallback) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.onFailure = append(s.onFailure, callback)
+}
+
+// Config returns the current Config, which should be used for the next signing session.
+func (s *Scheduler) Config() *cmp.Config {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.config
+}
+
+// Start schedules refreshes according to the policy, until Stop is called.
+func (s *Scheduler) Start() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.cancel != nil {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.schedule()
+}
+
+// Stop cancels the scheduled refreshes, aborts the one in progress, and waits for it to return.
+func (s *Scheduler) Stop() {
+	s.mtx.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mtx.Unlock()
+	s.wg.Wait()
+}
+
+// Signed records that a signature was produced with the current Config,
+// and starts a refresh in the background if the limit set by WithSignatures is reached.
+func (s *Scheduler) Signed() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.signed++
+	if s.signatures > 0 && s.signed >= s.signatures && s.cancel != nil && s.ctx.Err() == nil {
+		s.trigger()
+	}
+}
+
+// Refresh runs a refresh now, and returns the new Config.
+//
+// The other parties must call Refresh at the same time, or have their Scheduler start one.
+// If the refresh fails, the current Config is kept, and the failure callbacks are called.
+func (s *Scheduler) Refresh(ctx context.Context) (*cmp.Config, error) {
+	s.mtx.Lock()
+	if s.running {
+		s.mtx.Unlock()
+		return nil, ErrRunning
+	}
+	s.running = true
+	s.mtx.Unlock()
+	return s.refresh(ctx)
+}
+
+// schedule arms the timer for the next refresh, if an interval is set. It must be called with s.mtx held.
+func (s *Scheduler) schedule() {
+	if s.interval <= 0 || s.ctx.Err() != nil {
+		return
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = s.clock.AfterFunc(s.interval, func() {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		s.trigger()
+	})
+}
+
+// trigger starts a refresh in the background, unless one is already running. It must be called with s.mtx held.
+func (s *Scheduler) trigger() {
+	if s.running || s.ctx.Err() != nil {
+		return
+	}
+	s.running = true
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		_, _ = s.refresh(s.ctx)
+	}()
+}
+
+// refresh runs cmp.Refresh on the current Config, with s.running set by the caller.
+func (s *Scheduler) refresh(ctx context.Context) (*cmp.Config, error) {
+	s.mtx.Lock()
+	previous, unsaved := s.config, s.unsaved
+	s.mtx.Unlock()
+
+	next, err := s.run(ctx, previous, unsaved)
+
+	s.mtx.Lock()
+	s.running = false
+	if next != nil {
+		s.config = next
+		s.unsaved = err != nil
+		s.signed = 0
+	}
+	if s.cancel != nil {
+		s.schedule()
+	}
+	onRefresh := append([]RefreshCallback(nil), s.onRefresh...)
+	onFailure := append([]FailureCallback(nil), s.onFailure...)
+	s.mtx.Unlock()
+
+	if err != nil {
+		for _, callback := range onFailure {
+			callback(err)
+		}
+		return next, err
+	}
+	for _, callback := range onRefresh {
+		callback(previous, next)
+	}
+	return next, nil
+}
+
+// run executes the refresh protocol, and saves its result.
+//
+// If the result can't be saved, it is returned along with the error, since the other parties have moved on to it.
+// Saving it is then attempted again before the next refresh.
+func (s *Scheduler) run(ctx context.Context, previous *cmp.Config, unsaved bool) (*cmp.Config, error) {
+	if unsaved {
+		if err := s.store.Save(previous); err != nil {
+			return nil, fmt.Errorf("rotation: failed to save config: %w", err)
+		}
+		s.mtx.Lock()
+		s.unsaved = false
+		s.mtx.Unlock()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	h, err := protocol.NewMultiHandlerContext(ctx, cmp.Refresh(previous, s.pool, s.refreshOptions...), sessionID(previous))
+	if err != nil {
+		return nil, fmt.Errorf("rotation: %w", err)
+	}
+	result, err := transport.Run(ctx, h, s.transport)
+	if err != nil {
+		return nil, fmt.Errorf("rotation: refresh failed: %w", err)
+	}
+	next := result.(*cmp.Config)
+	if err = s.store.Save(next); err != nil {
+		return next, fmt.Errorf("rotation: failed to save refreshed config: %w", err)
+	}
+	return next, nil
+}
+
+// sessionID derives the ID of the refresh of c from its public data, so that parties agree on it without communicating,
+// and each refresh gets a different one.
+func sessionID(c *cmp.Config) []byte {
+	h := hash.New(c)
+	_ = h.WriteAny([]byte("rotation"))
+	return h.Sum()
+}
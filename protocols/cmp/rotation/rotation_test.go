@@ -0,0 +1,75 @@
+package rotation
+
+import (
+	"crypto/rand"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+)
+
+func TestScheduler(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	configs, ids := test.GenerateConfig(group, 2, 1, rand.Reader, pl)
+	network := transport.NewMemoryNetwork(ids)
+	clock := protocol.NewManualClock(time.Now())
+	dir := t.TempDir()
+
+	var refreshed sync.WaitGroup
+	schedulers := make([]*Scheduler, 0, len(ids))
+	stores := make([]*FileStore, 0, len(ids))
+	for _, id := range ids {
+		store := NewFileStore(filepath.Join(dir, string(id)), group)
+		require.NoError(t, store.Save(configs[id]))
+		s, err := New(store, network.Transport(id), pl, WithInterval(time.Hour), WithSignatures(2), WithClock(clock))
+		require.NoError(t, err)
+		s.OnRefresh(func(previous, next *cmp.Config) {
+			assert.True(t, previous.PublicPoint().Equal(next.PublicPoint()))
+			assert.False(t, previous.ECDSA.Equal(next.ECDSA))
+			refreshed.Done()
+		})
+		s.OnFailure(func(err error) { t.Error(err) })
+		s.Start()
+		defer s.Stop()
+		schedulers = append(schedulers, s)
+		stores = append(stores, store)
+	}
+
+	check := func() {
+		refreshed.Wait()
+		for i, s := range schedulers {
+			stored, err := stores[i].Load()
+			require.NoError(t, err)
+			assert.True(t, stored.ECDSA.Equal(s.Config().ECDSA), "the refreshed config should be saved")
+		}
+	}
+
+	// nothing happens before the interval elapses
+	clock.Advance(time.Minute)
+	for _, s := range schedulers {
+		assert.True(t, s.Config().ECDSA.Equal(configs[s.Config().ID].ECDSA))
+	}
+
+	refreshed.Add(len(ids))
+	clock.Advance(time.Hour)
+	check()
+
+	refreshed.Add(len(ids))
+	for _, s := range schedulers {
+		s.Signed()
+		s.Signed()
+	}
+	check()
+}
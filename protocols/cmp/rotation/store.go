@@ -0,0 +1,87 @@
+package rotation
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+)
+
+// Store holds the current Config of a party.
+type Store interface {
+	// Load returns the stored Config.
+	Load() (*cmp.Config, error)
+	// Save replaces the stored Config by c.
+	//
+	// It must be atomic: if it fails, or the process crashes while it runs,
+	// Load must return either the previous Config or c, never a mix of both.
+	Save(c *cmp.Config) error
+}
+
+// FileStore is a Store keeping the Config in a single file, in the format of Config.MarshalBinary.
+type FileStore struct {
+	path  string
+	group curve.Curve
+}
+
+// NewFileStore returns a FileStore for the file at path, containing a Config over group.
+func NewFileStore(path string, group curve.Curve) *FileStore {
+	return &FileStore{path: path, group: group}
+}
+
+// Load implements Store.
+func (s *FileStore) Load() (*cmp.Config, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	c := cmp.EmptyConfig(s.group)
+	if err = c.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Save implements Store.
+//
+// The Config is written to a temporary file in the same directory, which is synced and then renamed over the file,
+// so that a crash leaves either the old or the new file in place.
+// The file is only readable by the current user, since it contains the secret share.
+func (s *FileStore) Save(c *cmp.Config) error {
+	data, err := c.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err = tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+	// make the rename itself durable
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,61 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+const protocolSignBatchID = "cmp/sign-batch"
+
+// StartSignBatch signs all messages in a single session.
+//
+// Every signature still needs its own nonce, and therefore its own MtA exchanges and proofs,
+// since reusing them for two messages would reveal the secret key.
+// What is shared is the session: the signatures are generated in lockstep,
+// and the messages sent to each party in a round are bundled into one.
+func StartSignBatch(config *config.Config, signers []party.ID, messages [][]byte, pl *pool.Pool, opts ...Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if len(messages) == 0 {
+			return nil, errors.New("sign.CreateBatch: no message")
+		}
+
+		info := round.Info{
+			ProtocolID:       protocolSignBatchID,
+			FinalRoundNumber: protocolSignRounds,
+			SelfID:           config.ID,
+			PartyIDs:         signers,
+			Threshold:        config.Threshold,
+			Group:            config.Group,
+		}
+
+		auxInfo := []hash.WriterToWithDomain{config}
+		for _, message := range messages {
+			auxInfo = append(auxInfo, types.SigningMessage(message))
+		}
+		helper, err := round.NewSession(info, sessionID, pl, auxInfo...)
+		if err != nil {
+			return nil, fmt.Errorf("sign.CreateBatch: %w", err)
+		}
+
+		starts := make([]func([]byte) (round.Session, error), 0, len(messages))
+		for _, message := range messages {
+			starts = append(starts, StartSign(config, signers, message, pl, opts...))
+		}
+		return round.NewBatch(helper, starts, func(results []interface{}) interface{} {
+			signatures := make([]*ecdsa.Signature, 0, len(results))
+			for _, result := range results {
+				signatures = append(signatures, result.(*ecdsa.Signature))
+			}
+			return signatures
+		})
+	}
+}
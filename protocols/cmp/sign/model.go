@@ -0,0 +1,17 @@
+package sign
+
+import "github.com/taurusgroup/multi-party-sig/pkg/protocol"
+
+// Model returns the state machine of the signing protocol.
+func Model() *protocol.Model {
+	return &protocol.Model{
+		Name: protocolSignID,
+		Rounds: []protocol.RoundModel{
+			{Name: "round1", Number: 1, Next: []string{"round2"}},
+			{Name: "round2", Number: 2, Broadcast: protocol.ReliableBroadcast, Message: true, Next: []string{"round3"}, Abort: true},
+			{Name: "round3", Number: 3, Broadcast: protocol.NormalBroadcast, Message: true, Next: []string{"round4"}, Abort: true},
+			{Name: "round4", Number: 4, Broadcast: protocol.NormalBroadcast, Message: true, Next: []string{"round5"}, Abort: true},
+			{Name: "round5", Number: 5, Broadcast: protocol.NormalBroadcast, Output: true, Abort: true},
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package sign
+
+// Option modifies the behaviour of the signing protocol.
+//
+// All signers must use the same options, since they are bound to the session.
+type Option func(*options)
+
+type options struct {
+	// compact selects the compact encoding of the enc, affg and log* proofs.
+	compact bool
+}
+
+// WithCompactProofs sends each proof with its challenge, instead of the commitments
+// that the verifier can recompute from the responses.
+//
+// This shrinks the messages of rounds 2 to 4 by about a third, at the cost of verifying each proof on its own,
+// rather than in a batch with the proofs of the other signers.
+func WithCompactProofs() Option {
+	return func(o *options) {
+		o.compact = true
+	}
+}
@@ -1,8 +1,7 @@
 package sign
 
 import (
-	"crypto/rand"
-
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -26,6 +25,9 @@ type round1 struct {
 	ECDSA          map[party.ID]curve.Point
 
 	Message []byte
+
+	// Compact is true if the proofs are sent as CompactProof.
+	Compact bool
 }
 
 // VerifyMessage implements round.Round.
@@ -51,12 +53,12 @@ func (round1) StoreMessage(round.Message) error { return nil }
 func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// γᵢ <- 𝔽,
 	// Γᵢ = [γᵢ]⋅G
-	GammaShare, BigGammaShare := sample.ScalarPointPair(rand.Reader, r.Group())
+	GammaShare, BigGammaShare := sample.ScalarPointPair(random.Reader(), r.Group())
 	// Gᵢ = Encᵢ(γᵢ;νᵢ)
 	G, GNonce := r.Paillier[r.SelfID()].Enc(curve.MakeInt(GammaShare))
 
 	// kᵢ <- 𝔽,
-	KShare := sample.Scalar(rand.Reader, r.Group())
+	KShare := sample.Scalar(random.Reader(), r.Group())
 	// Kᵢ = Encᵢ(kᵢ;ρᵢ)
 	K, KNonce := r.Paillier[r.SelfID()].Enc(curve.MakeInt(KShare))
 
@@ -67,18 +69,23 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 	errors := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
-		proof := zkenc.NewProof(r.Group(), r.HashForID(r.SelfID()), zkenc.Public{
+		public := zkenc.Public{
 			K:      K,
 			Prover: r.Paillier[r.SelfID()],
 			Aux:    r.Pedersen[j],
-		}, zkenc.Private{
+		}
+		private := zkenc.Private{
 			K:   curve.MakeInt(KShare),
 			Rho: KNonce,
-		})
+		}
+		var msg message2
+		if r.Compact {
+			msg.CompactProofEnc = zkenc.NewCompactProof(r.Group(), r.HashForID(r.SelfID()), public, private)
+		} else {
+			msg.ProofEnc = zkenc.NewProof(r.Group(), r.HashForID(r.SelfID()), public, private)
+		}
 
-		err := r.SendMessage(out, &message2{
-			ProofEnc: proof,
-		}, j)
+		err := r.SendMessage(out, &msg, j)
 		if err != nil {
 			return err
 		}
@@ -91,14 +98,16 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 
 	return &round2{
-		round1:        r,
-		K:             map[party.ID]*paillier.Ciphertext{r.SelfID(): K},
-		G:             map[party.ID]*paillier.Ciphertext{r.SelfID(): G},
-		BigGammaShare: map[party.ID]curve.Point{r.SelfID(): BigGammaShare},
-		GammaShare:    curve.MakeInt(GammaShare),
-		KShare:        KShare,
-		KNonce:        KNonce,
-		GNonce:        GNonce,
+		round1:          r,
+		K:               map[party.ID]*paillier.Ciphertext{r.SelfID(): K},
+		G:               map[party.ID]*paillier.Ciphertext{r.SelfID(): G},
+		BigGammaShare:   map[party.ID]curve.Point{r.SelfID(): BigGammaShare},
+		GammaShare:      curve.MakeInt(GammaShare),
+		KShare:          KShare,
+		KNonce:          KNonce,
+		GNonce:          GNonce,
+		ProofEnc:        map[party.ID]*zkenc.Proof{},
+		CompactProofEnc: map[party.ID]*zkenc.CompactProof{},
 	}, nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/mta"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
@@ -37,6 +38,11 @@ type round2 struct {
 	// GNonce = νᵢ <- ℤₙ
 	// used to encrypt Gᵢ = Encᵢ(γᵢ)
 	GNonce *saferith.Nat
+
+	// ProofEnc[j] = zkenc(Kⱼ), verified in Finalize
+	ProofEnc map[party.ID]*zkenc.Proof
+	// CompactProofEnc[j] replaces ProofEnc[j] if r.Compact
+	CompactProofEnc map[party.ID]*zkenc.CompactProof
 }
 
 type broadcast2 struct {
@@ -48,7 +54,8 @@ type broadcast2 struct {
 }
 
 type message2 struct {
-	ProofEnc *zkenc.Proof
+	ProofEnc        *zkenc.Proof        `cbor:",omitempty"`
+	CompactProofEnc *zkenc.CompactProof `cbor:",omitempty"`
 }
 
 // StoreBroadcastMessage implements round.Round.
@@ -73,37 +80,41 @@ func (r *round2) StoreBroadcastMessage(msg round.Message) error {
 
 // VerifyMessage implements round.Round.
 //
-// - verify zkenc(Kⱼ).
+// The proof is only verified in Finalize, together with the others.
 func (r *round2) VerifyMessage(msg round.Message) error {
-	from, to := msg.From, msg.To
 	body, ok := msg.Content.(*message2)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
 
-	if body.ProofEnc == nil {
+	if (r.Compact && body.CompactProofEnc == nil) || (!r.Compact && body.ProofEnc == nil) {
 		return round.ErrNilFields
 	}
-
-	if !body.ProofEnc.Verify(r.Group(), r.HashForID(from), zkenc.Public{
-		K:      r.K[from],
-		Prover: r.Paillier[from],
-		Aux:    r.Pedersen[to],
-	}) {
-		return errors.New("failed to validate enc proof for K")
-	}
 	return nil
 }
 
 // StoreMessage implements round.Round.
 //
-// - store Kⱼ, Gⱼ.
-func (round2) StoreMessage(round.Message) error { return nil }
+// - store zkenc(Kⱼ).
+func (r *round2) StoreMessage(msg round.Message) error {
+	body := msg.Content.(*message2)
+	if r.Compact {
+		r.CompactProofEnc[msg.From] = body.CompactProofEnc
+	} else {
+		r.ProofEnc[msg.From] = body.ProofEnc
+	}
+	return nil
+}
 
 // Finalize implements round.Round
 //
+// - verify all zkenc(Kⱼ) at once.
 // - compute Hash(ssid, K₁, G₁, …, Kₙ, Gₙ).
 func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	if culprits := r.verifyProofs(); len(culprits) > 0 {
+		return r.AbortRound(errors.New("failed to validate enc proof for K"), culprits...), nil
+	}
+
 	if err := r.BroadcastMessage(out, &broadcast3{
 		BigGammaShare: r.BigGammaShare[r.SelfID()],
 	}); err != nil {
@@ -111,57 +122,67 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 
 	otherIDs := r.OtherPartyIDs()
-	type mtaOut struct {
-		err       error
-		DeltaBeta *saferith.Int
-		ChiBeta   *saferith.Int
+	receivers := make(map[party.ID]mta.Receiver, len(otherIDs))
+	for _, j := range otherIDs {
+		receivers[j] = mta.Receiver{
+			EncryptedShare: r.K[j],
+			Paillier:       r.Paillier[j],
+			Pedersen:       r.Pedersen[j],
+		}
+	}
+	newHash := func() *hash.Hash { return r.HashForID(r.SelfID()) }
+	proveAffG := mta.ProveAffGBatch
+	if r.Compact {
+		proveAffG = mta.ProveAffGCompactBatch
 	}
-	mtaOuts := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
-		j := otherIDs[i]
-
-		DeltaBeta, DeltaD, DeltaF, DeltaProof := mta.ProveAffG(r.Group(), r.HashForID(r.SelfID()),
-			r.GammaShare, r.BigGammaShare[r.SelfID()], r.K[j],
-			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
-		ChiBeta, ChiD, ChiF, ChiProof := mta.ProveAffG(r.Group(),
-			r.HashForID(r.SelfID()), curve.MakeInt(r.SecretECDSA), r.ECDSA[r.SelfID()], r.K[j],
-			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
-
-		proof := zklogstar.NewProof(r.Group(), r.HashForID(r.SelfID()),
-			zklogstar.Public{
-				C:      r.G[r.SelfID()],
-				X:      r.BigGammaShare[r.SelfID()],
-				Prover: r.Paillier[r.SelfID()],
-				Aux:    r.Pedersen[j],
-			}, zklogstar.Private{
-				X:   r.GammaShare,
-				Rho: r.GNonce,
-			})
-
-		err := r.SendMessage(out, &message3{
-			DeltaD:     DeltaD,
-			DeltaF:     DeltaF,
-			DeltaProof: DeltaProof,
-			ChiD:       ChiD,
-			ChiF:       ChiF,
-			ChiProof:   ChiProof,
-			ProofLog:   proof,
-		}, j)
-		return mtaOut{
-			err:       err,
-			DeltaBeta: DeltaBeta,
-			ChiBeta:   ChiBeta,
+	DeltaMtA := proveAffG(r.Group(), newHash,
+		r.GammaShare, r.BigGammaShare[r.SelfID()],
+		r.SecretPaillier, receivers, r.Pool)
+	ChiMtA := proveAffG(r.Group(), newHash,
+		curve.MakeInt(r.SecretECDSA), r.ECDSA[r.SelfID()],
+		r.SecretPaillier, receivers, r.Pool)
+
+	logProofs := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
+		public := zklogstar.Public{
+			C:      r.G[r.SelfID()],
+			X:      r.BigGammaShare[r.SelfID()],
+			Prover: r.Paillier[r.SelfID()],
+			Aux:    r.Pedersen[otherIDs[i]],
+		}
+		private := zklogstar.Private{
+			X:   r.GammaShare,
+			Rho: r.GNonce,
 		}
+		if r.Compact {
+			return zklogstar.NewCompactProof(r.Group(), r.HashForID(r.SelfID()), public, private)
+		}
+		return zklogstar.NewProof(r.Group(), r.HashForID(r.SelfID()), public, private)
 	})
+
 	DeltaShareBetas := make(map[party.ID]*saferith.Int, len(otherIDs)-1)
 	ChiShareBetas := make(map[party.ID]*saferith.Int, len(otherIDs)-1)
-	for idx, mtaOutRaw := range mtaOuts {
-		j := otherIDs[idx]
-		m := mtaOutRaw.(mtaOut)
-		if m.err != nil {
-			return r, m.err
+	for idx, j := range otherIDs {
+		delta, chi := DeltaMtA[j], ChiMtA[j]
+		msg := &message3{
+			DeltaD:            delta.D,
+			DeltaF:            delta.F,
+			DeltaProof:        delta.Proof,
+			CompactDeltaProof: delta.CompactProof,
+			ChiD:              chi.D,
+			ChiF:              chi.F,
+			ChiProof:          chi.Proof,
+			CompactChiProof:   chi.CompactProof,
+		}
+		if r.Compact {
+			msg.CompactProofLog = logProofs[idx].(*zklogstar.CompactProof)
+		} else {
+			msg.ProofLog = logProofs[idx].(*zklogstar.Proof)
 		}
-		DeltaShareBetas[j] = m.DeltaBeta
-		ChiShareBetas[j] = m.ChiBeta
+		if err := r.SendMessage(out, msg, j); err != nil {
+			return r, err
+		}
+		DeltaShareBetas[j] = delta.Beta
+		ChiShareBetas[j] = chi.Beta
 	}
 
 	return &round3{
@@ -170,9 +191,37 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 		ChiShareBeta:    ChiShareBetas,
 		DeltaShareAlpha: map[party.ID]*saferith.Int{},
 		ChiShareAlpha:   map[party.ID]*saferith.Int{},
+		Messages:        map[party.ID]*message3{},
 	}, nil
 }
 
+// verifyProofs returns the parties whose zkenc(Kⱼ) is invalid.
+func (r *round2) verifyProofs() []party.ID {
+	otherIDs := r.OtherPartyIDs()
+	hashes := make([]*hash.Hash, len(otherIDs))
+	publics := make([]zkenc.Public, len(otherIDs))
+	proofs := make([]*zkenc.Proof, len(otherIDs))
+	for i, j := range otherIDs {
+		hashes[i] = r.HashForID(j)
+		publics[i] = zkenc.Public{
+			K:      r.K[j],
+			Prover: r.Paillier[j],
+			Aux:    r.Pedersen[r.SelfID()],
+		}
+		proofs[i] = r.ProofEnc[j]
+	}
+	if r.Compact {
+		return verifyEach(r.Pool, otherIDs, func(i int) bool {
+			return r.CompactProofEnc[otherIDs[i]].Verify(r.Group(), r.HashForID(otherIDs[i]), publics[i])
+		})
+	}
+	return findCulprits(r.Pool, otherIDs, func() bool {
+		return zkenc.VerifyBatch(r.Group(), hashes, publics, proofs, r.Pool)
+	}, func(i int) bool {
+		return proofs[i].Verify(r.Group(), r.HashForID(otherIDs[i]), publics[i])
+	})
+}
+
 // RoundNumber implements round.Content.
 func (message2) RoundNumber() round.Number { return 2 }
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/cronokirby/saferith"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
@@ -26,16 +27,24 @@ type round3 struct {
 	ChiShareAlpha map[party.ID]*saferith.Int
 	// ChiShareBeta[j] = β̂ᵢⱼ
 	ChiShareBeta map[party.ID]*saferith.Int
+
+	// Messages[j] is the message received from j, whose proofs are verified in Finalize
+	Messages map[party.ID]*message3
 }
 
 type message3 struct {
 	DeltaD     *paillier.Ciphertext // DeltaD = Dᵢⱼ
 	DeltaF     *paillier.Ciphertext // DeltaF = Fᵢⱼ
-	DeltaProof *zkaffg.Proof
+	DeltaProof *zkaffg.Proof        `cbor:",omitempty"`
 	ChiD       *paillier.Ciphertext // DeltaD = D̂_{ij}
 	ChiF       *paillier.Ciphertext // ChiF = F̂ᵢⱼ
-	ChiProof   *zkaffg.Proof
-	ProofLog   *zklogstar.Proof
+	ChiProof   *zkaffg.Proof        `cbor:",omitempty"`
+	ProofLog   *zklogstar.Proof     `cbor:",omitempty"`
+
+	// The compact proofs replace the ones above if the signers use compact proofs.
+	CompactDeltaProof *zkaffg.CompactProof    `cbor:",omitempty"`
+	CompactChiProof   *zkaffg.CompactProof    `cbor:",omitempty"`
+	CompactProofLog   *zklogstar.CompactProof `cbor:",omitempty"`
 }
 
 type broadcast3 struct {
@@ -60,54 +69,27 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 
 // VerifyMessage implements round.Round.
 //
-// - verify zkproofs affg (2x) zklog*.
+// The proofs are only verified in Finalize, together with the others.
 func (r *round3) VerifyMessage(msg round.Message) error {
-	from, to := msg.From, msg.To
 	body, ok := msg.Content.(*message3)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
 
-	if !body.DeltaProof.Verify(r.HashForID(from), zkaffg.Public{
-		Kv:       r.K[to],
-		Dv:       body.DeltaD,
-		Fp:       body.DeltaF,
-		Xp:       r.BigGammaShare[from],
-		Prover:   r.Paillier[from],
-		Verifier: r.Paillier[to],
-		Aux:      r.Pedersen[to],
-	}) {
-		return errors.New("failed to validate affg proof for Delta MtA")
-	}
-
-	if !body.ChiProof.Verify(r.HashForID(from), zkaffg.Public{
-		Kv:       r.K[to],
-		Dv:       body.ChiD,
-		Fp:       body.ChiF,
-		Xp:       r.ECDSA[from],
-		Prover:   r.Paillier[from],
-		Verifier: r.Paillier[to],
-		Aux:      r.Pedersen[to],
-	}) {
-		return errors.New("failed to validate affg proof for Chi MtA")
-	}
-
-	if !body.ProofLog.Verify(r.HashForID(from), zklogstar.Public{
-		C:      r.G[from],
-		X:      r.BigGammaShare[from],
-		Prover: r.Paillier[from],
-		Aux:    r.Pedersen[to],
-	}) {
-		return errors.New("failed to validate log proof")
+	if r.Compact {
+		if body.CompactDeltaProof == nil || body.CompactChiProof == nil || body.CompactProofLog == nil {
+			return round.ErrNilFields
+		}
+	} else if body.DeltaProof == nil || body.ChiProof == nil || body.ProofLog == nil {
+		return round.ErrNilFields
 	}
-
 	return nil
 }
 
 // StoreMessage implements round.Round.
 //
 // - Decrypt MtA shares,
-// - save αᵢⱼ, α̂ᵢⱼ.
+// - save αᵢⱼ, α̂ᵢⱼ and the proofs.
 func (r *round3) StoreMessage(msg round.Message) error {
 	from, body := msg.From, msg.Content.(*message3)
 
@@ -124,17 +106,23 @@ func (r *round3) StoreMessage(msg round.Message) error {
 
 	r.DeltaShareAlpha[from] = DeltaShareAlpha
 	r.ChiShareAlpha[from] = ChiShareAlpha
+	r.Messages[from] = body
 
 	return nil
 }
 
 // Finalize implements round.Round
 //
+// - verify all zkproofs affg (2x) zklog* at once.
 // - Γ = ∑ⱼ Γⱼ
 // - Δᵢ = [kᵢ]Γ
 // - δᵢ = γᵢ kᵢ + ∑ⱼ δᵢⱼ
 // - χᵢ = xᵢ kᵢ + ∑ⱼ χᵢⱼ.
 func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
+	if culprits := r.verifyProofs(); len(culprits) > 0 {
+		return r.AbortRound(errors.New("failed to validate MtA proofs"), culprits...), nil
+	}
+
 	// Γ = ∑ⱼ Γⱼ
 	Gamma := r.Group().NewPoint()
 	for _, BigGammaShare := range r.BigGammaShare {
@@ -178,17 +166,21 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	errs := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
 
-		proofLog := zklogstar.NewProof(r.Group(), r.HashForID(r.SelfID()), zklogstar.Public{
+		zkPublic := zklogstar.Public{
 			C:      r.K[r.SelfID()],
 			X:      BigDeltaShare,
 			G:      Gamma,
 			Prover: r.Paillier[r.SelfID()],
 			Aux:    r.Pedersen[j],
-		}, zkPrivate)
+		}
+		var msg message4
+		if r.Compact {
+			msg.CompactProofLog = zklogstar.NewCompactProof(r.Group(), r.HashForID(r.SelfID()), zkPublic, zkPrivate)
+		} else {
+			msg.ProofLog = zklogstar.NewProof(r.Group(), r.HashForID(r.SelfID()), zkPublic, zkPrivate)
+		}
 
-		err := r.SendMessage(out, &message4{
-			ProofLog: proofLog,
-		}, j)
+		err := r.SendMessage(out, &msg, j)
 		if err != nil {
 			return err
 		}
@@ -201,19 +193,83 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 
 	return &round4{
-		round3:         r,
-		DeltaShares:    map[party.ID]curve.Scalar{r.SelfID(): DeltaShareScalar},
-		BigDeltaShares: map[party.ID]curve.Point{r.SelfID(): BigDeltaShare},
-		Gamma:          Gamma,
-		ChiShare:       r.Group().NewScalar().SetNat(ChiShare.Mod(r.Group().Order())),
+		round3:          r,
+		DeltaShares:     map[party.ID]curve.Scalar{r.SelfID(): DeltaShareScalar},
+		BigDeltaShares:  map[party.ID]curve.Point{r.SelfID(): BigDeltaShare},
+		Gamma:           Gamma,
+		ChiShare:        r.Group().NewScalar().SetNat(ChiShare.Mod(r.Group().Order())),
+		ProofLog:        map[party.ID]*zklogstar.Proof{},
+		CompactProofLog: map[party.ID]*zklogstar.CompactProof{},
 	}, nil
 }
 
+// verifyProofs returns the parties which sent an invalid affg proof for either MtA, or an invalid log* proof.
+func (r *round3) verifyProofs() []party.ID {
+	otherIDs := r.OtherPartyIDs()
+	n := len(otherIDs)
+	// the Delta and Chi proofs of party otherIDs[i] are at i and n+i.
+	affgHashes := make([]*hash.Hash, 2*n)
+	affgPublics := make([]zkaffg.Public, 2*n)
+	affgProofs := make([]*zkaffg.Proof, 2*n)
+	logHashes := make([]*hash.Hash, n)
+	logPublics := make([]zklogstar.Public, n)
+	logProofs := make([]*zklogstar.Proof, n)
+	for i, j := range otherIDs {
+		body := r.Messages[j]
+		affgHashes[i], affgHashes[n+i] = r.HashForID(j), r.HashForID(j)
+		affgPublics[i] = r.affgPublic(j, body.DeltaD, body.DeltaF, r.BigGammaShare[j])
+		affgPublics[n+i] = r.affgPublic(j, body.ChiD, body.ChiF, r.ECDSA[j])
+		affgProofs[i], affgProofs[n+i] = body.DeltaProof, body.ChiProof
+
+		logHashes[i] = r.HashForID(j)
+		logPublics[i] = zklogstar.Public{
+			C:      r.G[j],
+			X:      r.BigGammaShare[j],
+			Prover: r.Paillier[j],
+			Aux:    r.Pedersen[r.SelfID()],
+		}
+		logProofs[i] = body.ProofLog
+	}
+	if r.Compact {
+		return verifyEach(r.Pool, otherIDs, func(i int) bool {
+			j, body := otherIDs[i], r.Messages[otherIDs[i]]
+			return body.CompactDeltaProof.Verify(r.Group(), r.HashForID(j), affgPublics[i]) &&
+				body.CompactChiProof.Verify(r.Group(), r.HashForID(j), affgPublics[n+i]) &&
+				body.CompactProofLog.Verify(r.Group(), r.HashForID(j), logPublics[i])
+		})
+	}
+	return findCulprits(r.Pool, otherIDs, func() bool {
+		return zkaffg.VerifyBatch(affgHashes, affgPublics, affgProofs, r.Pool) &&
+			zklogstar.VerifyBatch(logHashes, logPublics, logProofs, r.Pool)
+	}, func(i int) bool {
+		j := otherIDs[i]
+		return affgProofs[i].Verify(r.HashForID(j), affgPublics[i]) &&
+			affgProofs[n+i].Verify(r.HashForID(j), affgPublics[n+i]) &&
+			logProofs[i].Verify(r.HashForID(j), logPublics[i])
+	})
+}
+
+// affgPublic returns the statement of the affg proof sent by j for an MtA with D, F and X.
+func (r *round3) affgPublic(j party.ID, D, F *paillier.Ciphertext, X curve.Point) zkaffg.Public {
+	return zkaffg.Public{
+		Kv:       r.K[r.SelfID()],
+		Dv:       D,
+		Fp:       F,
+		Xp:       X,
+		Prover:   r.Paillier[j],
+		Verifier: r.Paillier[r.SelfID()],
+		Aux:      r.Pedersen[r.SelfID()],
+	}
+}
+
 // RoundNumber implements round.Content.
 func (message3) RoundNumber() round.Number { return 3 }
 
 // MessageContent implements round.Round.
 func (r *round3) MessageContent() round.Content {
+	if r.Compact {
+		return &message3{}
+	}
 	return &message3{
 		ProofLog:   zklogstar.Empty(r.Group()),
 		DeltaProof: zkaffg.Empty(r.Group()),
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	zklogstar "github.com/taurusgroup/multi-party-sig/pkg/zk/logstar"
@@ -24,10 +25,16 @@ type round4 struct {
 
 	// ChiShare = χᵢ
 	ChiShare curve.Scalar
+
+	// ProofLog[j] = Π(log*)(ϕ''ᵢⱼ, Δⱼ, Γ), verified in Finalize
+	ProofLog map[party.ID]*zklogstar.Proof
+	// CompactProofLog[j] replaces ProofLog[j] if r.Compact
+	CompactProofLog map[party.ID]*zklogstar.CompactProof
 }
 
 type message4 struct {
-	ProofLog *zklogstar.Proof
+	ProofLog        *zklogstar.Proof        `cbor:",omitempty"`
+	CompactProofLog *zklogstar.CompactProof `cbor:",omitempty"`
 }
 
 type broadcast4 struct {
@@ -56,40 +63,44 @@ func (r *round4) StoreBroadcastMessage(msg round.Message) error {
 
 // VerifyMessage implements round.Round.
 //
-// - Verify Π(log*)(ϕ''ᵢⱼ, Δⱼ, Γ).
+// The proof is only verified in Finalize, together with the others.
 func (r *round4) VerifyMessage(msg round.Message) error {
-	from, to := msg.From, msg.To
 	body, ok := msg.Content.(*message4)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
 
-	zkLogPublic := zklogstar.Public{
-		C:      r.K[from],
-		X:      r.BigDeltaShares[from],
-		G:      r.Gamma,
-		Prover: r.Paillier[from],
-		Aux:    r.Pedersen[to],
-	}
-	if !body.ProofLog.Verify(r.HashForID(from), zkLogPublic) {
-		return errors.New("failed to validate log proof")
+	if (r.Compact && body.CompactProofLog == nil) || (!r.Compact && body.ProofLog == nil) {
+		return round.ErrNilFields
 	}
-
 	return nil
 }
 
 // StoreMessage implements round.Round.
-func (round4) StoreMessage(round.Message) error {
+//
+// - store Π(log*)(ϕ''ᵢⱼ, Δⱼ, Γ).
+func (r *round4) StoreMessage(msg round.Message) error {
+	body := msg.Content.(*message4)
+	if r.Compact {
+		r.CompactProofLog[msg.From] = body.CompactProofLog
+	} else {
+		r.ProofLog[msg.From] = body.ProofLog
+	}
 	return nil
 }
 
 // Finalize implements round.Round
 //
+// - verify all Π(log*)(ϕ''ᵢⱼ, Δⱼ, Γ) at once
 // - set δ = ∑ⱼ δⱼ
 // - set Δ = ∑ⱼ Δⱼ
 // - verify Δ = [δ]G
 // - compute σᵢ = rχᵢ + kᵢm.
 func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
+	if culprits := r.verifyProofs(); len(culprits) > 0 {
+		return r.AbortRound(errors.New("failed to validate log proof"), culprits...), nil
+	}
+
 	// δ = ∑ⱼ δⱼ
 	// Δ = ∑ⱼ Δⱼ
 	Delta := r.Group().NewScalar()
@@ -131,11 +142,43 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}, nil
 }
 
+// verifyProofs returns the parties whose Π(log*)(ϕ''ᵢⱼ, Δⱼ, Γ) is invalid.
+func (r *round4) verifyProofs() []party.ID {
+	otherIDs := r.OtherPartyIDs()
+	hashes := make([]*hash.Hash, len(otherIDs))
+	publics := make([]zklogstar.Public, len(otherIDs))
+	proofs := make([]*zklogstar.Proof, len(otherIDs))
+	for i, j := range otherIDs {
+		hashes[i] = r.HashForID(j)
+		publics[i] = zklogstar.Public{
+			C:      r.K[j],
+			X:      r.BigDeltaShares[j],
+			G:      r.Gamma,
+			Prover: r.Paillier[j],
+			Aux:    r.Pedersen[r.SelfID()],
+		}
+		proofs[i] = r.ProofLog[j]
+	}
+	if r.Compact {
+		return verifyEach(r.Pool, otherIDs, func(i int) bool {
+			return r.CompactProofLog[otherIDs[i]].Verify(r.Group(), r.HashForID(otherIDs[i]), publics[i])
+		})
+	}
+	return findCulprits(r.Pool, otherIDs, func() bool {
+		return zklogstar.VerifyBatch(hashes, publics, proofs, r.Pool)
+	}, func(i int) bool {
+		return proofs[i].Verify(r.HashForID(otherIDs[i]), publics[i])
+	})
+}
+
 // RoundNumber implements round.Content.
 func (message4) RoundNumber() round.Number { return 4 }
 
 // MessageContent implements round.Round.
 func (r *round4) MessageContent() round.Content {
+	if r.Compact {
+		return &message4{}
+	}
 	return &message4{
 		ProofLog: zklogstar.Empty(r.Group()),
 	}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
 	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
@@ -22,9 +23,17 @@ const (
 	protocolSignRounds round.Number = 5
 )
 
-func StartSign(config *config.Config, signers []party.ID, message []byte, pl *pool.Pool) protocol.StartFunc {
+// lagrangeCache holds the Lagrange coefficients of the most recent sets of signers,
+// which are usually the same from one session to the next.
+var lagrangeCache = polynomial.NewLagrangeCache(16)
+
+func StartSign(config *config.Config, signers []party.ID, message []byte, pl *pool.Pool, opts ...Option) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		group := config.Group
+		var o options
+		for _, opt := range opts {
+			opt(&o)
+		}
 
 		// this could be used to indicate a pre-signature later on
 		if len(message) == 0 {
@@ -40,7 +49,15 @@ func StartSign(config *config.Config, signers []party.ID, message []byte, pl *po
 			Group:            config.Group,
 		}
 
-		helper, err := round.NewSession(info, sessionID, pl, config, types.SigningMessage(message))
+		auxInfo := []hash.WriterToWithDomain{config, types.SigningMessage(message)}
+		if o.compact {
+			// the messages don't have the same format, so all signers must agree on it.
+			auxInfo = append(auxInfo, hash.BytesWithDomain{
+				TheDomain: "Compact Proofs",
+				Bytes:     []byte{1},
+			})
+		}
+		helper, err := round.NewSession(info, sessionID, pl, auxInfo...)
 		if err != nil {
 			return nil, fmt.Errorf("sign.Create: %w", err)
 		}
@@ -55,7 +72,7 @@ func StartSign(config *config.Config, signers []party.ID, message []byte, pl *po
 		Paillier := make(map[party.ID]*paillier.PublicKey, T)
 		Pedersen := make(map[party.ID]*pedersen.Parameters, T)
 		PublicKey := group.NewPoint()
-		lagrange := polynomial.Lagrange(group, signers)
+		lagrange := lagrangeCache.Lagrange(group, signers)
 		// Scale own secret
 		SecretECDSA := group.NewScalar().Set(lagrange[config.ID]).Mul(config.ECDSA)
 		SecretPaillier := config.Paillier
@@ -77,6 +94,7 @@ func StartSign(config *config.Config, signers []party.ID, message []byte, pl *po
 			Pedersen:       Pedersen,
 			ECDSA:          ECDSA,
 			Message:        message,
+			Compact:        o.compact,
 		}, nil
 	}
 }
@@ -33,12 +33,13 @@ func TestRound(t *testing.T) {
 	messageHash := make([]byte, 64)
 	sha3.ShakeSum128(messageHash, messageToSign)
 
+	tracer := test.NewTracer()
 	rounds := make([]round.Session, 0, N)
 	for _, partyID := range partyIDs {
 		c := configs[partyID]
 		r, err := StartSign(c, partyIDs, messageHash, pl)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
-		rounds = append(rounds, r)
+		rounds = append(rounds, tracer.Session(r))
 	}
 
 	for {
@@ -48,6 +49,7 @@ func TestRound(t *testing.T) {
 			break
 		}
 	}
+	require.NoError(t, tracer.Check(Model()), "rounds should follow the model")
 
 	for _, r := range rounds {
 		require.IsType(t, &round.Output{}, r, "expected result round")
@@ -57,3 +59,85 @@ func TestRound(t *testing.T) {
 		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature")
 	}
 }
+
+func TestCompactProofs(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := N - 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	messageHash := make([]byte, 64)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	tracer := test.NewTracer()
+	rounds := make([]round.Session, 0, N)
+	for _, partyID := range partyIDs {
+		r, err := StartSign(configs[partyID], partyIDs, messageHash, pl, WithCompactProofs())(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, tracer.Session(r))
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	require.NoError(t, tracer.Check(Model()), "rounds should follow the model")
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		signature := r.(*round.Output).Result.(*ecdsa.Signature)
+		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature")
+	}
+}
+
+func TestSignBatch(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := N - 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	messageHashes := make([][]byte, 3)
+	for i := range messageHashes {
+		messageHashes[i] = make([]byte, 64)
+		sha3.ShakeSum128(messageHashes[i], []byte{byte(i)})
+	}
+
+	rounds := make([]round.Session, 0, N)
+	for _, partyID := range partyIDs {
+		r, err := StartSignBatch(configs[partyID], partyIDs, messageHashes, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		resultRound := r.(*round.Output)
+		require.IsType(t, []*ecdsa.Signature{}, resultRound.Result, "expected signatures")
+		signatures := resultRound.Result.([]*ecdsa.Signature)
+		require.Len(t, signatures, len(messageHashes))
+		for i, signature := range signatures {
+			assert.True(t, signature.Verify(publicPoint, messageHashes[i]), "expected valid signature")
+		}
+	}
+}
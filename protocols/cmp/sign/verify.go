@@ -0,0 +1,31 @@
+package sign
+
+import (
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+// findCulprits returns the parties whose proofs are invalid.
+//
+// The proofs of all parties are first checked at once with batch.
+// Only if that fails, the proofs of each party are checked separately with single, in order to identify the culprits.
+func findCulprits(pl *pool.Pool, ids []party.ID, batch func() bool, single func(i int) bool) []party.ID {
+	if batch() {
+		return nil
+	}
+	return verifyEach(pl, ids, single)
+}
+
+// verifyEach runs single for every party in parallel, and returns the parties for which it failed.
+func verifyEach(pl *pool.Pool, ids []party.ID, single func(i int) bool) []party.ID {
+	valid := pl.Parallelize(len(ids), func(i int) interface{} {
+		return single(i)
+	})
+	var culprits []party.ID
+	for i, ok := range valid {
+		if !ok.(bool) {
+			culprits = append(culprits, ids[i])
+		}
+	}
+	return culprits
+}
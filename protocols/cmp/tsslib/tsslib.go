@@ -0,0 +1,286 @@
+// Package tsslib converts key shares between cmp.Config and the LocalPartySaveData of bnb-chain/tss-lib,
+// so that keys generated by GG18/GG20 deployments of tss-lib can be migrated to this library, and back.
+//
+// Both libraries share the key as a degree t polynomial over secp256k1, evaluated at each party's key,
+// so a share can be moved from one to the other without changing the public key.
+// The auxiliary parameters differ however: tss-lib has no ElGamal keys, and uses Pedersen parameters over a modulus
+// distinct from the Paillier one. Imported configs therefore only contain the shares, and all parties must run
+// cmp.Refresh with cmp.WithAuxInfoOnly on them before they can be stored or used to sign.
+package tsslib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// curveName is the name tss-lib gives to secp256k1, the only curve supported here.
+const curveName = "secp256k1"
+
+// SaveData mirrors the JSON encoding of tss-lib's ecdsa/keygen.LocalPartySaveData,
+// whose embedded LocalPreParams and LocalSecrets fields appear at the top level.
+//
+// The slices are indexed by the position of each party, sorted by Ks.
+type SaveData struct {
+	PaillierSK *PaillierSecretKey `json:"PaillierSK"`
+	NTildei    *big.Int           `json:"NTildei"`
+	H1i        *big.Int           `json:"H1i"`
+	H2i        *big.Int           `json:"H2i"`
+	Alpha      *big.Int           `json:"Alpha"`
+	Beta       *big.Int           `json:"Beta"`
+	P          *big.Int           `json:"P"`
+	Q          *big.Int           `json:"Q"`
+
+	Xi      *big.Int `json:"Xi"`
+	ShareID *big.Int `json:"ShareID"`
+
+	Ks          []*big.Int           `json:"Ks"`
+	NTildej     []*big.Int           `json:"NTildej"`
+	H1j         []*big.Int           `json:"H1j"`
+	H2j         []*big.Int           `json:"H2j"`
+	BigXj       []*ECPoint           `json:"BigXj"`
+	PaillierPKs []*PaillierPublicKey `json:"PaillierPKs"`
+	ECDSAPub    *ECPoint             `json:"ECDSAPub"`
+}
+
+// PaillierPublicKey mirrors tss-lib's paillier.PublicKey.
+type PaillierPublicKey struct {
+	N *big.Int `json:"N"`
+}
+
+// PaillierSecretKey mirrors tss-lib's paillier.PrivateKey, whose embedded PublicKey appears as N.
+type PaillierSecretKey struct {
+	N       *big.Int `json:"N"`
+	LambdaN *big.Int `json:"LambdaN"`
+	PhiN    *big.Int `json:"PhiN"`
+	P       *big.Int `json:"P,omitempty"`
+	Q       *big.Int `json:"Q,omitempty"`
+}
+
+// ECPoint mirrors the JSON encoding of tss-lib's crypto.ECPoint. Older versions of tss-lib omit Curve.
+type ECPoint struct {
+	Curve  string      `json:"Curve,omitempty"`
+	Coords [2]*big.Int `json:"Coords"`
+}
+
+// Import converts the share of a tss-lib key with the given threshold into a Config.
+//
+// The ID of each party is the big-endian encoding of its key in Ks, which makes it the same interpolation point.
+// The Config only contains the shares, see the package documentation.
+func Import(data *SaveData, threshold int) (*cmp.Config, error) {
+	group := curve.Secp256k1{}
+	if data.Xi == nil || data.ShareID == nil || data.ECDSAPub == nil {
+		return nil, errors.New("tsslib: missing share")
+	}
+	n := len(data.Ks)
+	if len(data.BigXj) != n {
+		return nil, fmt.Errorf("tsslib: %d parties but %d public shares", n, len(data.BigXj))
+	}
+	if !config.ValidThreshold(threshold, n) {
+		return nil, fmt.Errorf("tsslib: threshold %d is invalid for %d parties", threshold, n)
+	}
+
+	public := make(map[party.ID]*config.Public, n)
+	ids := make([]party.ID, 0, n)
+	var self party.ID
+	for i, k := range data.Ks {
+		if k == nil || k.Sign() <= 0 {
+			return nil, fmt.Errorf("tsslib: invalid key for party %d", i)
+		}
+		id := party.ID(k.Bytes())
+		if scalarBig(id.Scalar(group)).Cmp(k) != 0 {
+			return nil, fmt.Errorf("tsslib: key of party %d isn't reduced modulo the order", i)
+		}
+		if _, ok := public[id]; ok {
+			return nil, fmt.Errorf("tsslib: duplicate key for party %d", i)
+		}
+		X, err := data.BigXj[i].point()
+		if err != nil {
+			return nil, fmt.Errorf("tsslib: public share of party %d: %w", i, err)
+		}
+		public[id] = &config.Public{ECDSA: X}
+		ids = append(ids, id)
+		if k.Cmp(data.ShareID) == 0 {
+			self = id
+		}
+	}
+	if self == "" {
+		return nil, errors.New("tsslib: ShareID is not one of Ks")
+	}
+	if err := party.NewIDSlice(ids).CheckScalars(group); err != nil {
+		return nil, fmt.Errorf("tsslib: %w", err)
+	}
+
+	secret := group.NewScalar().SetNat(new(saferith.Nat).SetBig(data.Xi, data.Xi.BitLen()))
+	if secret.IsZero() || !secret.ActOnBase().Equal(public[self].ECDSA) {
+		return nil, errors.New("tsslib: Xi doesn't match the public share")
+	}
+	publicKey, err := data.ECDSAPub.point()
+	if err != nil {
+		return nil, fmt.Errorf("tsslib: public key: %w", err)
+	}
+	if err = checkShares(group, ids, public, threshold, publicKey); err != nil {
+		return nil, err
+	}
+
+	return &cmp.Config{
+		Group:     group,
+		ID:        self,
+		Threshold: threshold,
+		ECDSA:     secret,
+		Public:    public,
+	}, nil
+}
+
+// checkShares verifies that the public shares lie on a polynomial of degree threshold, whose constant is publicKey.
+//
+// Every set made of the first threshold parties and one of the others must interpolate to the public key.
+func checkShares(group curve.Curve, ids []party.ID, public map[party.ID]*config.Public, threshold int, publicKey curve.Point) error {
+	for _, j := range ids[threshold:] {
+		subset := append(append([]party.ID{}, ids[:threshold]...), j)
+		sum := group.NewPoint()
+		for id, lambda := range polynomial.Lagrange(group, subset) {
+			sum = sum.Add(lambda.Act(public[id].ECDSA))
+		}
+		if !sum.Equal(publicKey) {
+			return fmt.Errorf("tsslib: the public shares don't match the public key with threshold %d", threshold)
+		}
+	}
+	return nil
+}
+
+// Export converts c into the save data of tss-lib, for the same public key and shares.
+//
+// The Paillier keys are kept, and the Paillier modulus of each party doubles as its NTilde,
+// with H1 = t and H2 = s from the Pedersen parameters, which satisfy the same relation H2 = H1^α.
+// Alpha and Beta are left empty, since the exponent relating them isn't kept in c.
+// tss-lib doesn't need them to sign, but resharing the key with tss-lib requires new pre-parameters.
+func Export(c *cmp.Config) (*SaveData, error) {
+	if c.Group.Name() != curveName {
+		return nil, fmt.Errorf("tsslib: unsupported curve %s", c.Group.Name())
+	}
+	if c.Paillier == nil {
+		return nil, errors.New("tsslib: the config has no Paillier key")
+	}
+
+	// tss-lib sorts the parties by key
+	ids := c.PartyIDs()
+	keys := make(map[party.ID]*big.Int, len(ids))
+	for _, j := range ids {
+		keys[j] = scalarBig(j.Scalar(c.Group))
+	}
+	sortByKey(ids, keys)
+
+	P, Q := c.Paillier.P().Big(), c.Paillier.Q().Big()
+	one := big.NewInt(1)
+	pMinus1, qMinus1 := new(big.Int).Sub(P, one), new(big.Int).Sub(Q, one)
+	phi := new(big.Int).Mul(pMinus1, qMinus1)
+	lambda := new(big.Int).Div(phi, new(big.Int).GCD(nil, nil, pMinus1, qMinus1))
+
+	self := c.Public[c.ID]
+	data := &SaveData{
+		PaillierSK: &PaillierSecretKey{
+			N:       self.Paillier.N().Big(),
+			LambdaN: lambda,
+			PhiN:    phi,
+			P:       P,
+			Q:       Q,
+		},
+		NTildei: self.Pedersen.N().Big(),
+		H1i:     self.Pedersen.T().Big(),
+		H2i:     self.Pedersen.S().Big(),
+		// the Paillier primes are safe primes 2p+1 and 2q+1, of which tss-lib keeps p and q
+		P:       new(big.Int).Rsh(P, 1),
+		Q:       new(big.Int).Rsh(Q, 1),
+		Xi:      scalarBig(c.ECDSA),
+		ShareID: keys[c.ID],
+	}
+	for _, j := range ids {
+		public := c.Public[j]
+		X, err := newECPoint(public.ECDSA)
+		if err != nil {
+			return nil, err
+		}
+		data.Ks = append(data.Ks, keys[j])
+		data.NTildej = append(data.NTildej, public.Pedersen.N().Big())
+		data.H1j = append(data.H1j, public.Pedersen.T().Big())
+		data.H2j = append(data.H2j, public.Pedersen.S().Big())
+		data.BigXj = append(data.BigXj, X)
+		data.PaillierPKs = append(data.PaillierPKs, &PaillierPublicKey{N: public.Paillier.N().Big()})
+	}
+	publicKey, err := newECPoint(c.PublicPoint())
+	if err != nil {
+		return nil, err
+	}
+	data.ECDSAPub = publicKey
+	return data, nil
+}
+
+// point decodes p, checking that it lies on secp256k1.
+func (p *ECPoint) point() (curve.Point, error) {
+	if p == nil || p.Coords[0] == nil || p.Coords[1] == nil {
+		return nil, errors.New("missing coordinates")
+	}
+	if p.Curve != "" && p.Curve != curveName {
+		return nil, fmt.Errorf("unsupported curve %s", p.Curve)
+	}
+	x, y := p.Coords[0], p.Coords[1]
+	if x.Sign() < 0 || y.Sign() < 0 || x.BitLen() > 256 || y.BitLen() > 256 {
+		return nil, errors.New("invalid coordinates")
+	}
+	uncompressed := make([]byte, 65)
+	uncompressed[0] = 4
+	x.FillBytes(uncompressed[1:33])
+	y.FillBytes(uncompressed[33:])
+
+	// decode the compressed form, and compare it to the coordinates, which rejects points off the curve
+	compressed := make([]byte, 33)
+	compressed[0] = byte(2 + y.Bit(0))
+	copy(compressed[1:], uncompressed[1:33])
+	point := curve.Secp256k1{}.NewPoint()
+	if err := point.UnmarshalBinary(compressed); err != nil {
+		return nil, err
+	}
+	encoded, err := curve.MarshalSEC1(point, false)
+	if err != nil {
+		return nil, err
+	}
+	if string(encoded) != string(uncompressed) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return point, nil
+}
+
+func newECPoint(p curve.Point) (*ECPoint, error) {
+	encoded, err := curve.MarshalSEC1(p, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) != 65 {
+		return nil, errors.New("tsslib: cannot export the identity")
+	}
+	return &ECPoint{
+		Curve:  curveName,
+		Coords: [2]*big.Int{new(big.Int).SetBytes(encoded[1:33]), new(big.Int).SetBytes(encoded[33:])},
+	}, nil
+}
+
+func scalarBig(s curve.Scalar) *big.Int {
+	data, _ := s.MarshalBinary()
+	return new(big.Int).SetBytes(data)
+}
+
+func sortByKey(ids []party.ID, keys map[party.ID]*big.Int) {
+	for i := 1; i < len(ids); i++ {
+		for j := i; j > 0 && keys[ids[j]].Cmp(keys[ids[j-1]]) < 0; j-- {
+			ids[j], ids[j-1] = ids[j-1], ids[j]
+		}
+	}
+}
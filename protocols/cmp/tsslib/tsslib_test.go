@@ -0,0 +1,113 @@
+package tsslib
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+func TestExportImport(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	N, T := 3, 1
+	configs, partyIDs := test.GenerateConfig(group, N, T, rand.Reader, pl)
+
+	imported := make(map[party.ID]*cmp.Config, N)
+	for _, id := range partyIDs {
+		data, err := Export(configs[id])
+		require.NoError(t, err)
+		encoded, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		var fields map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(encoded, &fields))
+		for _, field := range []string{"PaillierSK", "NTildei", "H1i", "H2i", "Xi", "ShareID", "Ks", "BigXj", "PaillierPKs", "ECDSAPub"} {
+			assert.Contains(t, fields, field)
+		}
+
+		decoded := new(SaveData)
+		require.NoError(t, json.Unmarshal(encoded, decoded))
+		c, err := Import(decoded, T)
+		require.NoError(t, err)
+		assert.Equal(t, id, c.ID)
+		assert.True(t, c.ECDSA.Equal(configs[id].ECDSA))
+		assert.True(t, c.PublicPoint().Equal(configs[id].PublicPoint()))
+		imported[id] = c
+	}
+
+	// the imported configs must get auxiliary parameters before signing
+	message := []byte("hello")
+	signers := partyIDs[:T+1]
+	network := test.NewNetwork(signers)
+	var wg sync.WaitGroup
+	for _, id := range signers {
+		wg.Add(1)
+		go func(c *cmp.Config) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(cmp.Refresh(c, pl, cmp.WithAuxInfoOnly()), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, network)
+			r, err := h.Result()
+			require.NoError(t, err)
+			c = r.(*cmp.Config)
+			assert.True(t, c.ECDSA.Equal(configs[c.ID].ECDSA))
+
+			h, err = protocol.NewMultiHandler(cmp.Sign(c, signers, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, network)
+			r, err = h.Result()
+			require.NoError(t, err)
+			assert.True(t, r.(*ecdsa.Signature).Verify(configs[c.ID].PublicPoint(), message))
+		}(subset(imported[id], signers))
+	}
+	wg.Wait()
+}
+
+// subset returns a copy of c restricted to the signers, since a refresh involves all the parties of a config.
+func subset(c *cmp.Config, signers []party.ID) *cmp.Config {
+	restricted := *c
+	restricted.Public = make(map[party.ID]*config.Public, len(signers))
+	for _, j := range signers {
+		restricted.Public[j] = c.Public[j]
+	}
+	return &restricted
+}
+
+func TestImportInvalid(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	group := curve.Secp256k1{}
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, rand.Reader, pl)
+	data, err := Export(configs[partyIDs[0]])
+	require.NoError(t, err)
+
+	_, err = Import(data, 0)
+	assert.Error(t, err, "the shares are of degree 1")
+
+	wrongShare := *data
+	wrongShare.Xi = new(big.Int).Add(data.Xi, big.NewInt(1))
+	_, err = Import(&wrongShare, 1)
+	assert.Error(t, err)
+
+	offCurve := *data
+	offCurve.BigXj = append([]*ECPoint(nil), data.BigXj...)
+	offCurve.BigXj[1] = &ECPoint{Coords: [2]*big.Int{data.BigXj[1].Coords[0], new(big.Int).Add(data.BigXj[1].Coords[1], big.NewInt(1))}}
+	_, err = Import(&offCurve, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,196 @@
+// Package zengo imports the key shares produced by the GG20 keygen of ZenGo-X/multi-party-ecdsa,
+// whose LocalKey is serialized to JSON with the formats of the curv library, so that Rust deployments can migrate to this library.
+//
+// Both libraries share the key as a degree t polynomial over secp256k1, and ZenGo evaluates it at the index of each party,
+// starting from 1. The imported Config uses PartyID(i) as the ID of party i, so that the shares keep the same interpolation points.
+//
+// ZenGo has no ElGamal keys, and its Paillier keys aren't checked to have the form required here,
+// so imported configs only contain the shares. All parties must run cmp.Refresh with cmp.WithAuxInfoOnly on them
+// before they can be stored or used to sign.
+package zengo
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp/config"
+)
+
+// curveName is the name curv gives to secp256k1, the only curve supported here.
+const curveName = "secp256k1"
+
+// LocalKey mirrors the JSON encoding of multi-party-ecdsa's gg_2020 state_machine keygen.LocalKey,
+// with the fields needed to import the share. The Paillier keys and the Pedersen parameters are ignored.
+type LocalKey struct {
+	// PkVec contains the public share of each party, by index.
+	PkVec []Point `json:"pk_vec"`
+	// KeysLinear contains the share of this party.
+	KeysLinear SharedKeys `json:"keys_linear"`
+	// YSumS is the public key.
+	YSumS Point `json:"y_sum_s"`
+	// VSSScheme contains the commitments to the polynomial sharing the key.
+	VSSScheme VerifiableSS `json:"vss_scheme"`
+	// I is the index of this party, starting from 1.
+	I int `json:"i"`
+	// T is the threshold: T+1 parties are needed to sign.
+	T int `json:"t"`
+	// N is the number of parties.
+	N int `json:"n"`
+}
+
+// SharedKeys mirrors gg_2020 party_i.SharedKeys.
+type SharedKeys struct {
+	Y  Point  `json:"y"`
+	Xi Scalar `json:"x_i"`
+}
+
+// VerifiableSS mirrors curv's Feldman VerifiableSS.
+type VerifiableSS struct {
+	Parameters  ShamirParameters `json:"parameters"`
+	Commitments []Point          `json:"commitments"`
+}
+
+// ShamirParameters mirrors curv's ShamirSecretSharing.
+type ShamirParameters struct {
+	Threshold  int `json:"threshold"`
+	ShareCount int `json:"share_count"`
+}
+
+// Point mirrors the JSON encoding of curv's Point, a compressed point in hex.
+type Point struct {
+	Curve string `json:"curve"`
+	Point string `json:"point"`
+}
+
+// Scalar mirrors the JSON encoding of curv's Scalar, a big-endian integer in hex.
+type Scalar struct {
+	Curve  string `json:"curve"`
+	Scalar string `json:"scalar"`
+}
+
+// PartyID returns the ID given to the party with index i by Import.
+//
+// It is the big-endian encoding of i, such as "\x01" for the first party, which isn't printable.
+func PartyID(i int) party.ID {
+	return party.ID(big.NewInt(int64(i)).Bytes())
+}
+
+// Import converts the LocalKey of a party into a Config.
+//
+// The public shares are checked against the commitments of the VSS scheme, and the share against its public share.
+// The Config only contains the shares, see the package documentation.
+func Import(key *LocalKey) (*cmp.Config, error) {
+	group := curve.Secp256k1{}
+	n, t := key.N, key.T
+	if n > 0xffff || len(key.PkVec) != n || key.I < 1 || key.I > n {
+		return nil, fmt.Errorf("zengo: invalid index %d for %d parties and %d public shares", key.I, n, len(key.PkVec))
+	}
+	if !config.ValidThreshold(t, n) || key.VSSScheme.Parameters.Threshold != t || key.VSSScheme.Parameters.ShareCount != n {
+		return nil, errors.New("zengo: invalid threshold")
+	}
+	if len(key.VSSScheme.Commitments) != t+1 {
+		return nil, fmt.Errorf("zengo: %d commitments for threshold %d", len(key.VSSScheme.Commitments), t)
+	}
+
+	commitments := make([]curve.Point, 0, t+1)
+	for k, c := range key.VSSScheme.Commitments {
+		point, err := c.point()
+		if err != nil {
+			return nil, fmt.Errorf("zengo: commitment %d: %w", k, err)
+		}
+		commitments = append(commitments, point)
+	}
+	publicKey, err := key.YSumS.point()
+	if err != nil {
+		return nil, fmt.Errorf("zengo: public key: %w", err)
+	}
+	y, err := key.KeysLinear.Y.point()
+	if err != nil {
+		return nil, fmt.Errorf("zengo: public key: %w", err)
+	}
+	if !publicKey.Equal(commitments[0]) || !publicKey.Equal(y) {
+		return nil, errors.New("zengo: the public key doesn't match the commitments")
+	}
+
+	public := make(map[party.ID]*config.Public, n)
+	for i := 1; i <= n; i++ {
+		X, err := key.PkVec[i-1].point()
+		if err != nil {
+			return nil, fmt.Errorf("zengo: public share of party %d: %w", i, err)
+		}
+		if !X.Equal(evaluate(group, commitments, i)) {
+			return nil, fmt.Errorf("zengo: public share of party %d doesn't match the commitments", i)
+		}
+		public[PartyID(i)] = &config.Public{ECDSA: X}
+	}
+
+	self := PartyID(key.I)
+	secret, err := key.KeysLinear.Xi.scalar()
+	if err != nil {
+		return nil, fmt.Errorf("zengo: share: %w", err)
+	}
+	if secret.IsZero() || !secret.ActOnBase().Equal(public[self].ECDSA) {
+		return nil, errors.New("zengo: x_i doesn't match the public share")
+	}
+
+	return &cmp.Config{
+		Group:     group,
+		ID:        self,
+		Threshold: t,
+		ECDSA:     secret,
+		Public:    public,
+	}, nil
+}
+
+// evaluate returns the polynomial in the exponent with the given coefficients, at x.
+func evaluate(group curve.Curve, coefficients []curve.Point, x int) curve.Point {
+	scalar := PartyID(x).Scalar(group)
+	result := group.NewPoint()
+	for k := len(coefficients) - 1; k >= 0; k-- {
+		result = scalar.Act(result).Add(coefficients[k])
+	}
+	return result
+}
+
+func (p Point) point() (curve.Point, error) {
+	if p.Curve != curveName {
+		return nil, fmt.Errorf("unsupported curve %q", p.Curve)
+	}
+	data, err := hex.DecodeString(p.Point)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 33 || (data[0] != 2 && data[0] != 3) {
+		return nil, errors.New("point is not compressed")
+	}
+	point := curve.Secp256k1{}.NewPoint()
+	if err = point.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return point, nil
+}
+
+func (s Scalar) scalar() (curve.Scalar, error) {
+	if s.Curve != curveName {
+		return nil, fmt.Errorf("unsupported curve %q", s.Curve)
+	}
+	data, err := hex.DecodeString(s.Scalar)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 32 {
+		return nil, errors.New("scalar too large")
+	}
+	padded := make([]byte, 32)
+	copy(padded[32-len(data):], data)
+	scalar := curve.Secp256k1{}.NewScalar()
+	if err = scalar.UnmarshalBinary(padded); err != nil {
+		return nil, err
+	}
+	return scalar, nil
+}
@@ -0,0 +1,147 @@
+package zengo
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/cmp"
+)
+
+var group = curve.Secp256k1{}
+
+func encodePoint(p curve.Point) map[string]string {
+	data, _ := p.MarshalBinary()
+	return map[string]string{"curve": "secp256k1", "point": hex.EncodeToString(data)}
+}
+
+// localKeys deals a key as ZenGo's GG20 keygen would, and returns the JSON encoding of each LocalKey.
+func localKeys(t *testing.T, n, threshold int) ([][]byte, curve.Point) {
+	coefficients := make([]curve.Scalar, threshold+1)
+	commitments := make([]interface{}, threshold+1)
+	for k := range coefficients {
+		coefficients[k] = sample.Scalar(rand.Reader, group)
+		commitments[k] = encodePoint(coefficients[k].ActOnBase())
+	}
+	shares := make([]curve.Scalar, n)
+	pkVec := make([]interface{}, n)
+	for i := range shares {
+		x := PartyID(i + 1).Scalar(group)
+		share := group.NewScalar()
+		for k := threshold; k >= 0; k-- {
+			share = share.Mul(x).Add(coefficients[k])
+		}
+		shares[i] = share
+		pkVec[i] = encodePoint(share.ActOnBase())
+	}
+	publicKey := coefficients[0].ActOnBase()
+
+	keys := make([][]byte, n)
+	for i := range keys {
+		xi, _ := shares[i].MarshalBinary()
+		data, err := json.Marshal(map[string]interface{}{
+			"paillier_dk": map[string]string{"p": "0b", "q": "0d"},
+			"pk_vec":      pkVec,
+			"keys_linear": map[string]interface{}{
+				"y":   encodePoint(publicKey),
+				"x_i": map[string]string{"curve": "secp256k1", "scalar": hex.EncodeToString(xi)},
+			},
+			"y_sum_s": encodePoint(publicKey),
+			"vss_scheme": map[string]interface{}{
+				"parameters":  map[string]int{"threshold": threshold, "share_count": n},
+				"commitments": commitments,
+			},
+			"i": i + 1,
+			"t": threshold,
+			"n": n,
+		})
+		require.NoError(t, err)
+		keys[i] = data
+	}
+	return keys, publicKey
+}
+
+func TestImport(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N, T := 2, 1
+	keys, publicKey := localKeys(t, N, T)
+	configs := make([]*cmp.Config, 0, N)
+	for i, data := range keys {
+		var key LocalKey
+		require.NoError(t, json.Unmarshal(data, &key))
+		c, err := Import(&key)
+		require.NoError(t, err)
+		assert.Equal(t, PartyID(i+1), c.ID)
+		assert.True(t, c.PublicPoint().Equal(publicKey))
+		configs = append(configs, c)
+	}
+
+	message := []byte("hello")
+	ids := configs[0].PartyIDs()
+	network := test.NewNetwork(ids)
+	var wg sync.WaitGroup
+	for _, c := range configs {
+		wg.Add(1)
+		go func(c *cmp.Config) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(cmp.Refresh(c, pl, cmp.WithAuxInfoOnly()), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, network)
+			r, err := h.Result()
+			require.NoError(t, err)
+			c = r.(*cmp.Config)
+
+			h, err = protocol.NewMultiHandler(cmp.Sign(c, ids, message, pl), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(c.ID, h, network)
+			r, err = h.Result()
+			require.NoError(t, err)
+			assert.True(t, r.(*ecdsa.Signature).Verify(publicKey, message))
+		}(c)
+	}
+	wg.Wait()
+}
+
+func TestImportInvalid(t *testing.T) {
+	keys, _ := localKeys(t, 3, 1)
+	decode := func() *LocalKey {
+		var key LocalKey
+		require.NoError(t, json.Unmarshal(keys[0], &key))
+		return &key
+	}
+
+	key := decode()
+	key.I = 2
+	_, err := Import(key)
+	assert.Error(t, err, "x_i belongs to party 1")
+
+	key = decode()
+	key.PkVec[2] = key.PkVec[1]
+	_, err = Import(key)
+	assert.Error(t, err, "public shares must match the commitments")
+
+	key = decode()
+	key.T = 2
+	_, err = Import(key)
+	assert.Error(t, err)
+
+	key = decode()
+	key.YSumS = key.PkVec[0]
+	_, err = Import(key)
+	assert.Error(t, err)
+
+	assert.Equal(t, party.ID("\x01"), PartyID(1))
+}
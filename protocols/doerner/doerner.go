@@ -1,3 +1,9 @@
+// Package doerner implements the two-party ECDSA protocol of Doerner, Kondi, Lee and shelat,
+// often called DKLs18: https://eprint.iacr.org/2018/499.
+//
+// Instead of Paillier encryption, secret products are computed with a multiplication
+// protocol based on oblivious transfer extension, which makes signing much cheaper than
+// the general threshold protocol in protocols/cmp, at the cost of only supporting two parties.
 package doerner
 
 import (
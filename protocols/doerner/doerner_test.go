@@ -14,6 +14,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/pool"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
 	"github.com/taurusgroup/multi-party-sig/protocols/doerner/keygen"
+	"github.com/taurusgroup/multi-party-sig/protocols/doerner/sign"
 )
 
 func runHandler(wg *sync.WaitGroup, id party.ID, handler protocol.Handler, network *test.Network) {
@@ -27,11 +28,12 @@ func runKeygen(partyIDs party.IDSlice) (*ConfigSender, *ConfigReceiver, error) {
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
 
-	h0, err := protocol.NewTwoPartyHandler(Keygen(testGroup, true, partyIDs[0], partyIDs[1], pl), []byte("session"), true)
+	tracerReceiver, tracerSender := test.NewTracer(), test.NewTracer()
+	h0, err := protocol.NewTwoPartyHandler(tracerReceiver.Start(Keygen(testGroup, true, partyIDs[0], partyIDs[1], pl)), []byte("session"), true)
 	if err != nil {
 		return nil, nil, err
 	}
-	h1, err := protocol.NewTwoPartyHandler(Keygen(testGroup, false, partyIDs[1], partyIDs[0], pl), []byte("session"), false)
+	h1, err := protocol.NewTwoPartyHandler(tracerSender.Start(Keygen(testGroup, false, partyIDs[1], partyIDs[0], pl)), []byte("session"), false)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -60,6 +62,12 @@ func runKeygen(partyIDs party.IDSlice) (*ConfigSender, *ConfigReceiver, error) {
 		return nil, nil, errors.New("failed to cast result to *ConfigSender")
 	}
 
+	if err = tracerReceiver.Check(keygen.ModelReceiver()); err != nil {
+		return nil, nil, err
+	}
+	if err = tracerSender.Check(keygen.ModelSender()); err != nil {
+		return nil, nil, err
+	}
 	return configSender, configReceiver, nil
 }
 
@@ -109,11 +117,12 @@ func runSign(partyIDs party.IDSlice, configSender *keygen.ConfigSender, configRe
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
 
-	h0, err := protocol.NewTwoPartyHandler(SignReceiver(configReceiver, partyIDs[0], partyIDs[1], testHash, pl), []byte("session"), true)
+	tracerReceiver, tracerSender := test.NewTracer(), test.NewTracer()
+	h0, err := protocol.NewTwoPartyHandler(tracerReceiver.Start(SignReceiver(configReceiver, partyIDs[0], partyIDs[1], testHash, pl)), []byte("session"), true)
 	if err != nil {
 		return nil, err
 	}
-	h1, err := protocol.NewTwoPartyHandler(SignSender(configSender, partyIDs[1], partyIDs[0], testHash, pl), []byte("session"), true)
+	h1, err := protocol.NewTwoPartyHandler(tracerSender.Start(SignSender(configSender, partyIDs[1], partyIDs[0], testHash, pl)), []byte("session"), true)
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +141,12 @@ func runSign(partyIDs party.IDSlice, configSender *keygen.ConfigSender, configRe
 	if !ok {
 		return nil, errors.New("failed to cast result to Signature")
 	}
+	if err = tracerReceiver.Check(sign.ModelReceiver()); err != nil {
+		return nil, err
+	}
+	if err = tracerSender.Check(sign.ModelSender()); err != nil {
+		return nil, err
+	}
 	return sig, nil
 }
 
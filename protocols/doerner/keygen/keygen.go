@@ -1,13 +1,13 @@
 package keygen
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/bip32"
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -118,7 +118,7 @@ func StartKeygen(group curve.Curve, receiver bool, selfID, otherID party.ID, sec
 
 		refresh := true
 		if secretShare == nil && public == nil {
-			secretShare = sample.Scalar(rand.Reader, group)
+			secretShare = sample.Scalar(random.Reader(), group)
 			refresh = false
 		}
 		publicShare := secretShare.ActOnBase()
@@ -0,0 +1,27 @@
+package keygen
+
+import "github.com/taurusgroup/multi-party-sig/pkg/protocol"
+
+// ModelReceiver returns the state machine of the keygen and refresh protocols, for the Receiver.
+func ModelReceiver() *protocol.Model {
+	return &protocol.Model{
+		Name: "doerner/keygen/receiver",
+		Rounds: []protocol.RoundModel{
+			{Name: "round1R", Number: 1, Next: []string{"round2R"}},
+			{Name: "round2R", Number: 2, Message: true, Next: []string{"round3R"}},
+			{Name: "round3R", Number: 3, Message: true, Output: true},
+		},
+	}
+}
+
+// ModelSender returns the state machine of the keygen and refresh protocols, for the Sender.
+func ModelSender() *protocol.Model {
+	return &protocol.Model{
+		Name: "doerner/keygen/sender",
+		Rounds: []protocol.RoundModel{
+			{Name: "round1S", Number: 1, Message: true, Next: []string{"round2S"}},
+			{Name: "round2S", Number: 2, Message: true, Next: []string{"round3S"}},
+			{Name: "round3S", Number: 3, Message: true, Output: true},
+		},
+	}
+}
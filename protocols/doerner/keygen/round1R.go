@@ -1,10 +1,9 @@
 package keygen
 
 import (
-	"crypto/rand"
-
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -56,12 +55,12 @@ func (r *round1R) Finalize(out chan<- *round.Message) (round.Session, error) {
 		return r, err
 	}
 	chainKey := make([]byte, params.SecBytes)
-	_, _ = rand.Read(chainKey)
+	_, _ = random.Read(chainKey)
 	chainKeyCommit, chainKeyDecommit, err := r.Hash().Commit(chainKey)
 	if err != nil {
 		return r, err
 	}
-	refreshScalar := sample.Scalar(rand.Reader, r.Group())
+	refreshScalar := sample.Scalar(random.Reader(), r.Group())
 	refreshCommit, refreshDecommit, err := r.Hash().Commit(refreshScalar)
 	if err != nil {
 		return r, err
@@ -1,10 +1,9 @@
 package keygen
 
 import (
-	"crypto/rand"
-
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
 	"github.com/taurusgroup/multi-party-sig/internal/params"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -87,8 +86,8 @@ func (r *round1S) StoreMessage(msg round.Message) (err error) {
 func (r *round1S) Finalize(out chan<- *round.Message) (round.Session, error) {
 	proof := zksch.NewProof(r.Hash(), r.publicShare, r.secretShare, nil)
 	chainKey := make([]byte, params.SecBytes)
-	_, _ = rand.Read(chainKey)
-	refreshScalar := sample.Scalar(rand.Reader, r.Group())
+	_, _ = random.Read(chainKey)
+	refreshScalar := sample.Scalar(random.Reader(), r.Group())
 	if err := r.SendMessage(out, &message1S{r.publicShare, chainKey, refreshScalar, proof, r.otMsg}, ""); err != nil {
 		return r, err
 	}
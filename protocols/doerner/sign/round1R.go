@@ -1,9 +1,8 @@
 package sign
 
 import (
-	"crypto/rand"
-
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -34,7 +33,7 @@ func (r *round1R) VerifyMessage(round.Message) error { return nil }
 func (r *round1R) StoreMessage(round.Message) error { return nil }
 
 func (r *round1R) Finalize(out chan<- *round.Message) (round.Session, error) {
-	kB := sample.Scalar(rand.Reader, r.Group())
+	kB := sample.Scalar(random.Reader(), r.Group())
 	D := kB.ActOnBase()
 	kB.Invert()
 	tag0 := &hash.BytesWithDomain{TheDomain: "Multiply0", Bytes: nil}
@@ -1,10 +1,10 @@
 package sign
 
 import (
-	"crypto/rand"
 	"errors"
 
 	"github.com/taurusgroup/multi-party-sig/internal/ot"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
@@ -65,7 +65,7 @@ func (r *round1S) StoreMessage(msg round.Message) error {
 func (r *round1S) Finalize(out chan<- *round.Message) (round.Session, error) {
 	group := r.Group()
 
-	kAPrime := sample.Scalar(rand.Reader, group)
+	kAPrime := sample.Scalar(random.Reader(), group)
 	RPrime := kAPrime.Act(r.D)
 
 	H := r.Hash()
@@ -75,7 +75,7 @@ func (r *round1S) Finalize(out chan<- *round.Message) (round.Session, error) {
 	R := kA.Act(r.D)
 	RProof := zksch.NewProof(r.Hash(), R, kA, r.D)
 
-	phi := sample.Scalar(rand.Reader, group)
+	phi := sample.Scalar(random.Reader(), group)
 	kAInv := group.NewScalar().Set(kA).Invert()
 	alpha1 := group.NewScalar().Set(r.config.SecretShare).Mul(kAInv)
 	alpha2 := group.NewScalar().Set(kAInv)
@@ -1,8 +1,7 @@
 package xor
 
 import (
-	"crypto/rand"
-
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
@@ -21,7 +20,7 @@ func (r *Round1) StoreMessage(round.Message) error { return nil }
 
 // Finalize uses the out channel to communicate messages to other parties.
 func (r *Round1) Finalize(out chan<- *round.Message) (round.Session, error) {
-	xor, err := types.NewRID(rand.Reader)
+	xor, err := types.NewRID(random.Reader())
 	if err != nil {
 		// return the round since we did not actually abort due to malicious behaviour.
 		return r, err
@@ -0,0 +1,222 @@
+package blind
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost/keygen"
+)
+
+const (
+	// Frost blind nonce generation with threshold.
+	protocolIDCommit = "frost/blind-commit-threshold"
+	// Frost blind signing with threshold.
+	protocolIDSign = "frost/blind-sign-threshold"
+	// Both protocols have 2 concrete rounds.
+	protocolRounds round.Number = 2
+)
+
+// ErrNonceUsed is returned when attempting to sign a second challenge with the same Nonce.
+var ErrNonceUsed = errors.New("blind: nonce has already been used")
+
+// Nonce is the result of the commitment phase, from the perspective of a single signer.
+//
+// The group commitment R must be sent to the client, who uses it to blind their message.
+// The remaining fields must be kept until the client returns a blinded challenge.
+//
+// A Nonce must only ever be used to answer a single challenge, otherwise the private
+// share of the signer can be recovered.
+// StartSign marks it as Used and erases KShare, so a stored Nonce must be overwritten
+// before the challenge is answered, and copies made before then must be deleted.
+type Nonce struct {
+	// ID is the identifier of the signer holding this nonce.
+	ID party.ID
+	// Signers is the list of all parties who contributed to R.
+	Signers []party.ID
+	// R = ∑ⱼ Kⱼ is the group commitment.
+	R curve.Point
+	// Commitments[j] = Kⱼ = kⱼ•G.
+	Commitments *party.PointMap
+	// KShare = kᵢ is our secret nonce.
+	KShare curve.Scalar
+	// Used is set once the nonce was consumed, after which it can't answer a challenge anymore.
+	Used bool
+}
+
+// EmptyNonce returns a Nonce with a given group, ready for unmarshalling.
+func EmptyNonce(group curve.Curve) *Nonce {
+	return &Nonce{
+		R:           group.NewPoint(),
+		Commitments: party.EmptyPointMap(group),
+		KShare:      group.NewScalar(),
+	}
+}
+
+// Group returns the elliptic curve group associated with this Nonce.
+func (n *Nonce) Group() curve.Curve {
+	return n.R.Curve()
+}
+
+// consume marks the nonce as used, and erases kᵢ.
+func (n *Nonce) consume() {
+	n.Used = true
+	if n.KShare != nil {
+		n.KShare = n.Group().NewScalar()
+	}
+}
+
+// StartCommit returns the first phase of the blind signing protocol.
+//
+// Every signer samples a nonce kᵢ and broadcasts Kᵢ = kᵢ•G.
+// The result is a *Nonce, which contains the group commitment R.
+// It stays open in limit until it is consumed by StartSign or Limit.Discard,
+// and no nonce is generated while limit is reached for the key of config.
+func StartCommit(config *keygen.Config, limit *Limit, signers []party.ID) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if limit == nil {
+			return nil, errors.New("blind.StartCommit: limit must be non nil")
+		}
+		if err := limit.check(config.PublicKey); err != nil {
+			return nil, fmt.Errorf("blind.StartCommit: %w", err)
+		}
+		info := round.Info{
+			ProtocolID:       protocolIDCommit,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           config.ID,
+			PartyIDs:         signers,
+			Threshold:        config.Threshold,
+			Group:            config.PublicKey.Curve(),
+		}
+
+		helper, err := round.NewSession(info, sessionID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("blind.StartCommit: %w", err)
+		}
+		return &commit1{
+			Helper: helper,
+			s_i:    config.PrivateShare,
+			Y:      config.PublicKey,
+			limit:  limit,
+		}, nil
+	}
+}
+
+// StartSign returns the second phase of the blind signing protocol.
+//
+// The signers must be the same as the ones who created the nonce, and challenge
+// is the blinded challenge c returned by the client.
+// The nonce is consumed, and released from limit, which must be the one given to StartCommit.
+// The result is the blinded response z, such that z•G = R + c•Y.
+func StartSign(config *keygen.Config, limit *Limit, nonce *Nonce, challenge curve.Scalar) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if limit == nil || nonce == nil || challenge == nil {
+			return nil, errors.New("blind.StartSign: limit, nonce and challenge must be non nil")
+		}
+		if nonce.ID != config.ID {
+			return nil, errors.New("blind.StartSign: nonce does not belong to this party")
+		}
+		if nonce.Used || nonce.KShare == nil || nonce.KShare.IsZero() {
+			return nil, ErrNonceUsed
+		}
+		info := round.Info{
+			ProtocolID:       protocolIDSign,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           config.ID,
+			PartyIDs:         nonce.Signers,
+			Threshold:        config.Threshold,
+			Group:            config.PublicKey.Curve(),
+		}
+
+		RBytes, err := nonce.R.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("blind.StartSign: %w", err)
+		}
+		cBytes, err := challenge.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("blind.StartSign: %w", err)
+		}
+		// bind the session to the nonce and the challenge being answered
+		helper, err := round.NewSession(info, sessionID, nil,
+			hash.BytesWithDomain{TheDomain: "Blind Nonce", Bytes: RBytes},
+			hash.BytesWithDomain{TheDomain: "Blind Challenge", Bytes: cBytes})
+		if err != nil {
+			return nil, fmt.Errorf("blind.StartSign: %w", err)
+		}
+		for _, j := range helper.PartyIDs() {
+			if _, ok := nonce.Commitments.Points[j]; !ok {
+				return nil, fmt.Errorf("blind.StartSign: missing nonce commitment for %v", j)
+			}
+		}
+		k_i := config.PublicKey.Curve().NewScalar().Set(nonce.KShare)
+		nonce.consume()
+		if err = limit.release(config.PublicKey, nonce.R); err != nil {
+			return nil, fmt.Errorf("blind.StartSign: %w", err)
+		}
+		return &sign1{
+			Helper:  helper,
+			c:       challenge,
+			Y:       config.PublicKey,
+			YShares: config.VerificationShares.Points,
+			s_i:     config.PrivateShare,
+			k_i:     k_i,
+			nonce:   nonce,
+		}, nil
+	}
+}
+
+// messageHash is a wrapper around bytes to provide some domain separation.
+//
+// This matches the one used in frost/sign, so that challenges are computed identically.
+type messageHash []byte
+
+// WriteTo makes messageHash implement the io.WriterTo interface.
+func (m messageHash) WriteTo(w io.Writer) (int64, error) {
+	if m == nil {
+		return 0, io.ErrUnexpectedEOF
+	}
+	n, err := w.Write(m)
+	return int64(n), err
+}
+
+// Domain implements hash.WriterToWithDomain, and separates this type within hash.Hash.
+func (messageHash) Domain() string {
+	return "messageHash"
+}
+
+// computeChallenge computes H(R, Y, m), as in sign.Signature.
+func computeChallenge(R, public curve.Point, m []byte) curve.Scalar {
+	challengeHash := hash.New()
+	_ = challengeHash.WriteAny(R, public, messageHash(m))
+	return sample.Scalar(challengeHash.Digest(), public.Curve())
+}
+
+// Signature is an unblinded Schnorr signature.
+//
+// It satisfies the same equation as sign.Signature:
+//
+//	Z * G = R + H(R, Y, m) * Y
+type Signature struct {
+	// R is the commitment point.
+	R curve.Point
+	// Z is the response scalar.
+	Z curve.Scalar
+}
+
+// Verify checks if a signature equation actually holds.
+//
+// Note that m is the hash of a message, and not the message itself.
+func (sig Signature) Verify(public curve.Point, m []byte) bool {
+	if sig.R == nil || sig.Z == nil || public == nil {
+		return false
+	}
+	c := computeChallenge(sig.R, public, m)
+	expected := c.Act(public).Add(sig.R)
+	return sig.Z.ActOnBase().Equal(expected)
+}
@@ -0,0 +1,158 @@
+package blind
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost/keygen"
+)
+
+func runRounds(t *testing.T, rounds []round.Session, model *protocol.Model) []interface{} {
+	tracer := test.NewTracer()
+	for i := range rounds {
+		rounds[i] = tracer.Session(rounds[i])
+	}
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	require.NoError(t, tracer.Check(model), "rounds should follow the model")
+	results := make([]interface{}, 0, len(rounds))
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		results = append(results, r.(*round.Output).Result)
+	}
+	return results
+}
+
+func TestBlindSign(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	N := 5
+	threshold := 2
+
+	partyIDs := test.PartyIDs(N)
+	signers := partyIDs[:threshold+1]
+
+	secret := sample.Scalar(rand.Reader, group)
+	f := polynomial.NewPolynomial(group, threshold, secret)
+	publicKey := secret.ActOnBase()
+
+	verificationShares := make(map[party.ID]curve.Point, N)
+	configs := make(map[party.ID]*keygen.Config, N)
+	for _, id := range partyIDs {
+		share := f.Evaluate(id.Scalar(group))
+		verificationShares[id] = share.ActOnBase()
+		configs[id] = &keygen.Config{
+			ID:           id,
+			Threshold:    threshold,
+			PublicKey:    publicKey,
+			PrivateShare: share,
+		}
+	}
+	for _, id := range partyIDs {
+		configs[id].VerificationShares = party.NewPointMap(verificationShares)
+	}
+
+	limits := make(map[party.ID]*Limit, len(signers))
+	for _, id := range signers {
+		limits[id] = NewLimit(1)
+	}
+
+	// commitment phase
+	rounds := make([]round.Session, 0, len(signers))
+	for _, id := range signers {
+		r, err := StartCommit(configs[id], limits[id], signers)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	nonces := make(map[party.ID]*Nonce, len(signers))
+	for _, result := range runRounds(t, rounds, CommitModel()) {
+		require.IsType(t, &Nonce{}, result)
+		nonce := result.(*Nonce)
+		nonces[nonce.ID] = nonce
+	}
+	R := nonces[signers[0]].R
+	for _, nonce := range nonces {
+		require.True(t, R.Equal(nonce.R), "all signers should agree on R")
+	}
+
+	// a second nonce can't be opened for the same key before the first one is consumed
+	_, err := StartCommit(configs[signers[0]], limits[signers[0]], signers)(nil)
+	assert.ErrorIs(t, err, ErrTooManyNonces)
+
+	// the client blinds their message
+	m := sha256.Sum256([]byte("hello"))
+	blinding, c, err := Blind(rand.Reader, publicKey, R, m[:])
+	require.NoError(t, err)
+
+	// signing phase
+	rounds = rounds[:0]
+	for _, id := range signers {
+		r, err := StartSign(configs[id], limits[id], nonces[id], c)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	results := runRounds(t, rounds, SignModel())
+
+	for _, result := range results {
+		require.Implements(t, (*curve.Scalar)(nil), result)
+		sig, err := blinding.Unblind(result.(curve.Scalar))
+		require.NoError(t, err)
+		assert.True(t, sig.Verify(publicKey, m[:]), "expected valid signature")
+		assert.False(t, sig.R.Equal(R), "signature should not reveal the signers' commitment")
+	}
+
+	// nonces cannot be reused, even after being persisted
+	_, err = StartSign(configs[signers[0]], limits[signers[0]], nonces[signers[0]], c)(nil)
+	assert.ErrorIs(t, err, ErrNonceUsed)
+	data, err := cbor.Marshal(nonces[signers[0]])
+	require.NoError(t, err)
+	nonce := EmptyNonce(group)
+	require.NoError(t, cbor.Unmarshal(data, nonce))
+	_, err = StartSign(configs[signers[0]], limits[signers[0]], nonce, c)(nil)
+	assert.ErrorIs(t, err, ErrNonceUsed)
+
+	// once consumed, nonces are released from the limit
+	_, err = StartCommit(configs[signers[0]], limits[signers[0]], signers)(nil)
+	assert.NoError(t, err)
+}
+
+func TestLimit(t *testing.T) {
+	group := curve.Secp256k1{}
+	public := sample.Scalar(rand.Reader, group).ActOnBase()
+	nonce := func() *Nonce {
+		k := sample.Scalar(rand.Reader, group)
+		return &Nonce{R: k.ActOnBase(), KShare: k}
+	}
+
+	l := NewLimit(2)
+	n1, n2 := nonce(), nonce()
+	require.NoError(t, l.acquire(public, n1.R))
+	require.NoError(t, l.acquire(public, n2.R))
+	assert.ErrorIs(t, l.acquire(public, nonce().R), ErrTooManyNonces)
+	assert.ErrorIs(t, l.check(public), ErrTooManyNonces)
+
+	// other keys are counted separately
+	other := sample.Scalar(rand.Reader, group).ActOnBase()
+	assert.NoError(t, l.check(other))
+
+	require.NoError(t, l.Discard(public, n1))
+	assert.True(t, n1.Used)
+	assert.True(t, n1.KShare.IsZero(), "discarded nonce should be erased")
+	assert.NoError(t, l.check(public))
+}
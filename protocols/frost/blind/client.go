@@ -0,0 +1,80 @@
+package blind
+
+import (
+	"errors"
+	"io"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+)
+
+// Blinding holds the client side state of a blind signature request.
+//
+// It is created by Blind, and must be kept until the signers return
+// their response to the blinded challenge.
+type Blinding struct {
+	// public = Y
+	public curve.Point
+	// R is the group commitment sent by the signers.
+	R curve.Point
+	// RPrime = R + α•G + β•Y is the commitment of the unblinded signature.
+	RPrime curve.Point
+	// alpha = α, beta = β are the blinding factors.
+	alpha, beta curve.Scalar
+	// c = c' + β is the blinded challenge.
+	c curve.Scalar
+}
+
+// Blind is run by the client, in order to obtain a signature on m, without the signers learning
+// either m or the resulting signature.
+//
+// public is the public key of the signers, and R is the group commitment returned by
+// the commitment phase. The returned challenge should be sent to the signers.
+//
+// Note that m is the hash of a message, and not the message itself.
+func Blind(rand io.Reader, public, R curve.Point, m []byte) (*Blinding, curve.Scalar, error) {
+	if public == nil || R == nil || m == nil {
+		return nil, nil, errors.New("blind.Blind: nil argument")
+	}
+	if R.IsIdentity() {
+		return nil, nil, errors.New("blind.Blind: nonce commitment is the identity point")
+	}
+	group := public.Curve()
+
+	// α, β ← 𝔽
+	alpha := sample.Scalar(rand, group)
+	beta := sample.Scalar(rand, group)
+
+	// R' = R + α•G + β•Y
+	RPrime := R.Add(alpha.ActOnBase()).Add(beta.Act(public))
+
+	// c = H(R', Y, m) + β
+	c := computeChallenge(RPrime, public, m)
+	c.Add(beta)
+
+	return &Blinding{
+		public: public,
+		R:      R,
+		RPrime: RPrime,
+		alpha:  alpha,
+		beta:   beta,
+		c:      c,
+	}, group.NewScalar().Set(c), nil
+}
+
+// Unblind checks the blinded response z returned by the signers, and returns the signature (R', z + α).
+func (b *Blinding) Unblind(z curve.Scalar) (Signature, error) {
+	if z == nil {
+		return Signature{}, errors.New("blind.Unblind: nil response")
+	}
+	// z•G ?= R + c•Y
+	expected := b.c.Act(b.public).Add(b.R)
+	if !z.ActOnBase().Equal(expected) {
+		return Signature{}, errors.New("blind.Unblind: invalid response")
+	}
+	group := b.public.Curve()
+	return Signature{
+		R: b.RPrime,
+		Z: group.NewScalar().Set(z).Add(b.alpha),
+	}, nil
+}
@@ -0,0 +1,142 @@
+package blind
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/zeebo/blake3"
+)
+
+var (
+	_ round.Round          = (*commit1)(nil)
+	_ round.BroadcastRound = (*commit2)(nil)
+)
+
+type commit1 struct {
+	*round.Helper
+	// s_i = sᵢ is our private secret share, only used to hedge the nonce.
+	s_i curve.Scalar
+	// Y is the public key the nonce is generated for.
+	Y curve.Point
+	// limit holds the open nonces of Y.
+	limit *Limit
+}
+
+type broadcast2 struct {
+	round.ReliableBroadcastContent
+	// K_i = kᵢ•G is the nonce commitment of the sender.
+	K_i curve.Point
+}
+
+// VerifyMessage implements round.Round.
+func (commit1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (commit1) StoreMessage(round.Message) error { return nil }
+
+const deriveHashKeyContext = "github.com/taurusgroup/multi-party-sig/frost/blind 2026-10-14T09:00+00:00 Derive hash Key"
+
+// Finalize implements round.Round.
+//
+// - sample kᵢ using the same hedged process as frost/sign,
+// - broadcast Kᵢ = kᵢ•G.
+func (r *commit1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	s_iBytes, err := r.s_i.MarshalBinary()
+	if err != nil {
+		return r, err
+	}
+
+	hashKey := make([]byte, 32)
+	blake3.DeriveKey(deriveHashKeyContext, s_iBytes, hashKey)
+	nonceHasher, _ := blake3.NewKeyed(hashKey)
+	_, _ = nonceHasher.Write(r.Hash().Sum())
+	a := make([]byte, 32)
+	_, _ = random.Read(a)
+	_, _ = nonceHasher.Write(a)
+
+	k_i := sample.ScalarUnit(nonceHasher.Digest(), r.Group())
+	K_i := k_i.ActOnBase()
+
+	if err = r.BroadcastMessage(out, &broadcast2{K_i: K_i}); err != nil {
+		return r, err
+	}
+	return &commit2{
+		commit1: r,
+		k_i:     k_i,
+		K:       map[party.ID]curve.Point{r.SelfID(): K_i},
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (commit1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (commit1) Number() round.Number { return 1 }
+
+type commit2 struct {
+	*commit1
+	// k_i = kᵢ is our secret nonce.
+	k_i curve.Scalar
+	// K[j] = Kⱼ
+	K map[party.ID]curve.Point
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - save Kⱼ.
+func (r *commit2) StoreBroadcastMessage(msg round.Message) error {
+	body, ok := msg.Content.(*broadcast2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.K_i.IsIdentity() {
+		return fmt.Errorf("nonce commitment is the identity point")
+	}
+	r.K[msg.From] = body.K_i
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (commit2) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (commit2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round.
+//
+// - compute R = ∑ⱼ Kⱼ,
+// - record R in the Limit of Y.
+func (r *commit2) Finalize(chan<- *round.Message) (round.Session, error) {
+	R := r.Group().NewPoint()
+	for _, K_j := range r.K {
+		R = R.Add(K_j)
+	}
+	if err := r.limit.acquire(r.Y, R); err != nil {
+		return r, err
+	}
+	return r.ResultRound(&Nonce{
+		ID:          r.SelfID(),
+		Signers:     r.PartyIDs(),
+		R:           R,
+		Commitments: party.NewPointMap(r.K),
+		KShare:      r.k_i,
+	}), nil
+}
+
+// MessageContent implements round.Round.
+func (commit2) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast2) RoundNumber() round.Number { return 2 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *commit2) BroadcastContent() round.BroadcastContent {
+	return &broadcast2{K_i: r.Group().NewPoint()}
+}
+
+// Number implements round.Round.
+func (commit2) Number() round.Number { return 2 }
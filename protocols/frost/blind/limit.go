@@ -0,0 +1,114 @@
+package blind
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+)
+
+// ErrTooManyNonces is returned when a signer already holds as many open nonces for a key as its Limit allows.
+var ErrTooManyNonces = errors.New("blind: too many open nonces for this key")
+
+// Limit bounds the number of nonces a signer holds open for each key, that is,
+// nonces whose commitment was generated but which weren't consumed by StartSign or Discard.
+//
+// Blind Schnorr signatures are only secure when signing sessions don't overlap:
+// a client holding ℓ open nonces at once can choose its ℓ challenges so as to obtain ℓ+1 signatures,
+// in polynomial time once ℓ exceeds the bit length of the group order (the ROS attack),
+// and with Wagner's algorithm for much smaller ℓ.
+// A Limit of 1 serializes the sessions of each key, which avoids the attack, and should be used
+// unless every client is trusted not to mount it.
+//
+// A Limit is only kept in memory, and is safe for concurrent use.
+// The same Limit must be used for all the sessions of a signer.
+type Limit struct {
+	max  int
+	mtx  sync.Mutex
+	open map[string]map[string]struct{}
+}
+
+// NewLimit returns a Limit allowing at most max open nonces per key, and at least 1.
+func NewLimit(max int) *Limit {
+	if max < 1 {
+		max = 1
+	}
+	return &Limit{
+		max:  max,
+		open: make(map[string]map[string]struct{}),
+	}
+}
+
+// Discard consumes a nonce for the key public which will never be used to answer a challenge,
+// for instance because the client abandoned its request, so that a new one can be generated.
+func (l *Limit) Discard(public curve.Point, nonce *Nonce) error {
+	if nonce == nil {
+		return errors.New("blind.Discard: nil nonce")
+	}
+	nonce.consume()
+	return l.release(public, nonce.R)
+}
+
+// acquire records R as an open nonce for public, unless the Limit is reached.
+func (l *Limit) acquire(public, R curve.Point) error {
+	key, nonce, err := keys(public, R)
+	if err != nil {
+		return err
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	open := l.open[key]
+	if len(open) >= l.max {
+		return ErrTooManyNonces
+	}
+	if open == nil {
+		open = make(map[string]struct{}, 1)
+		l.open[key] = open
+	}
+	open[nonce] = struct{}{}
+	return nil
+}
+
+// check returns ErrTooManyNonces if no nonce can currently be acquired for public.
+func (l *Limit) check(public curve.Point) error {
+	key, err := public.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if len(l.open[string(key)]) >= l.max {
+		return ErrTooManyNonces
+	}
+	return nil
+}
+
+// release removes R from the open nonces of public, if present.
+func (l *Limit) release(public, R curve.Point) error {
+	key, nonce, err := keys(public, R)
+	if err != nil {
+		return err
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	delete(l.open[key], nonce)
+	if len(l.open[key]) == 0 {
+		delete(l.open, key)
+	}
+	return nil
+}
+
+func keys(public, R curve.Point) (string, string, error) {
+	if public == nil || R == nil {
+		return "", "", errors.New("blind: nil point")
+	}
+	key, err := public.MarshalBinary()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := R.MarshalBinary()
+	if err != nil {
+		return "", "", err
+	}
+	return string(key), string(nonce), nil
+}
@@ -0,0 +1,25 @@
+package blind
+
+import "github.com/taurusgroup/multi-party-sig/pkg/protocol"
+
+// CommitModel returns the state machine of the protocol generating the nonce commitment.
+func CommitModel() *protocol.Model {
+	return &protocol.Model{
+		Name: protocolIDCommit,
+		Rounds: []protocol.RoundModel{
+			{Name: "commit1", Number: 1, Next: []string{"commit2"}},
+			{Name: "commit2", Number: 2, Broadcast: protocol.ReliableBroadcast, Output: true},
+		},
+	}
+}
+
+// SignModel returns the state machine of the protocol answering a blinded challenge.
+func SignModel() *protocol.Model {
+	return &protocol.Model{
+		Name: protocolIDSign,
+		Rounds: []protocol.RoundModel{
+			{Name: "sign1", Number: 1, Next: []string{"sign2"}},
+			{Name: "sign2", Number: 2, Broadcast: protocol.NormalBroadcast, Output: true, Abort: true},
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package blind
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+var (
+	_ round.Round          = (*sign1)(nil)
+	_ round.BroadcastRound = (*sign2)(nil)
+)
+
+type sign1 struct {
+	*round.Helper
+	// c is the blinded challenge provided by the client.
+	c curve.Scalar
+	// Y is the public key we're signing for.
+	Y curve.Point
+	// YShares[j] = Yⱼ are the verification shares of each participant.
+	YShares map[party.ID]curve.Point
+	// s_i = sᵢ is our private secret share.
+	s_i curve.Scalar
+	// k_i = kᵢ is our secret nonce, erased from nonce by StartSign.
+	k_i curve.Scalar
+	// nonce is the result of the commitment phase with the same signers.
+	nonce *Nonce
+}
+
+type broadcast2Sign struct {
+	round.NormalBroadcastContent
+	// Z_i is the response scalar computed by the sender of this message.
+	Z_i curve.Scalar
+}
+
+// VerifyMessage implements round.Round.
+func (sign1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (sign1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round.
+//
+// - compute zᵢ = kᵢ + c⋅λᵢ⋅sᵢ and broadcast it,
+// - erase kᵢ.
+func (r *sign1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	Lambdas := polynomial.Lagrange(r.Group(), r.PartyIDs())
+
+	z_i := r.Group().NewScalar().Set(Lambdas[r.SelfID()]).Mul(r.s_i).Mul(r.c)
+	z_i.Add(r.k_i)
+
+	// The nonce can never be used again, so we might as well get rid of it.
+	r.k_i = r.Group().NewScalar()
+
+	if err := r.BroadcastMessage(out, &broadcast2Sign{Z_i: z_i}); err != nil {
+		return r, err
+	}
+	return &sign2{
+		sign1:  r,
+		z:      map[party.ID]curve.Scalar{r.SelfID(): z_i},
+		Lambda: Lambdas,
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (sign1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (sign1) Number() round.Number { return 1 }
+
+type sign2 struct {
+	*sign1
+	// z[j] = zⱼ
+	z map[party.ID]curve.Scalar
+	// Lambda[j] = λⱼ
+	Lambda map[party.ID]curve.Scalar
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - verify zⱼ•G = Kⱼ + c⋅λⱼ•Yⱼ.
+func (r *sign2) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast2Sign)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.Z_i == nil {
+		return round.ErrNilFields
+	}
+
+	YShare, ok := r.YShares[from]
+	if !ok {
+		return fmt.Errorf("missing verification share for %v", from)
+	}
+	expected := r.c.Act(r.Lambda[from].Act(YShare)).Add(r.nonce.Commitments.Points[from])
+	if !body.Z_i.ActOnBase().Equal(expected) {
+		return fmt.Errorf("failed to verify response from %v", from)
+	}
+
+	r.z[from] = body.Z_i
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (sign2) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (sign2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round.
+//
+// - compute z = ∑ⱼ zⱼ and check z•G = R + c•Y.
+func (r *sign2) Finalize(chan<- *round.Message) (round.Session, error) {
+	z := r.Group().NewScalar()
+	for _, z_j := range r.z {
+		z.Add(z_j)
+	}
+
+	expected := r.c.Act(r.Y).Add(r.nonce.R)
+	if !z.ActOnBase().Equal(expected) {
+		return r.AbortRound(fmt.Errorf("generated blind signature failed to verify")), nil
+	}
+	return r.ResultRound(z), nil
+}
+
+// MessageContent implements round.Round.
+func (sign2) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast2Sign) RoundNumber() round.Number { return 2 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *sign2) BroadcastContent() round.BroadcastContent {
+	return &broadcast2Sign{Z_i: r.Group().NewScalar()}
+}
+
+// Number implements round.Round.
+func (sign2) Number() round.Number { return 2 }
@@ -1,10 +1,13 @@
 package frost
 
 import (
+	"fmt"
+
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost/blind"
 	"github.com/taurusgroup/multi-party-sig/protocols/frost/keygen"
 	"github.com/taurusgroup/multi-party-sig/protocols/frost/sign"
 )
@@ -13,6 +16,14 @@ type (
 	Config        = keygen.Config
 	TaprootConfig = keygen.TaprootConfig
 	Signature     = sign.Signature
+	Ciphersuite   = sign.Ciphersuite
+)
+
+var (
+	// Ed25519SHA512 is the FROST(Ed25519, SHA-512) ciphersuite of RFC 9591, whose signatures are Ed25519 signatures.
+	Ed25519SHA512 = sign.Ed25519SHA512
+	// Secp256k1SHA256 is the FROST(secp256k1, SHA-256) ciphersuite of RFC 9591.
+	Secp256k1SHA256 = sign.Secp256k1SHA256
 )
 
 // EmptyConfig creates an empty Config with a specific group.
@@ -57,6 +68,12 @@ func KeygenTaproot(selfID party.ID, participants []party.ID, threshold int) prot
 	return keygen.StartKeygenCommon(true, curve.Secp256k1{}, participants, threshold, selfID, nil, nil, nil)
 }
 
+// ResumeKeygen returns a protocol.ResumeFunc continuing a suspended session of Keygen, KeygenTaproot,
+// Refresh or RefreshTaproot. start must be created with the same arguments as the suspended session.
+func ResumeKeygen(start protocol.StartFunc) protocol.ResumeFunc {
+	return keygen.Resume(start)
+}
+
 // Refresh
 func Refresh(config *Config, participants []party.ID) protocol.StartFunc {
 	return keygen.StartKeygenCommon(false, config.Curve(), participants, config.Threshold, config.ID, config.PrivateShare, config.PublicKey, config.VerificationShares.Points)
@@ -110,23 +127,155 @@ func Sign(config *Config, signers []party.ID, messageHash []byte) protocol.Start
 // This needs to result of a Taproot compatible key generation phase, naturally.
 //
 // See: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+//
+// The public key of config is used as is. To spend a Taproot output whose internal key is config.PublicKey,
+// sign with the result of config.TapTweak(merkleRoot) instead, as described in BIP-341.
 func SignTaproot(config *TaprootConfig, signers []party.ID, messageHash []byte) protocol.StartFunc {
-	publicKey, err := curve.Secp256k1{}.LiftX(config.PublicKey)
+	normalResult, err := genericConfig(config)
 	if err != nil {
 		return func([]byte) (round.Session, error) {
 			return nil, err
 		}
 	}
+	return sign.StartSignCommon(true, normalResult, signers, messageHash)
+}
+
+// SignZilliqa is like Sign, but generates a Zilliqa Schnorr signature, which can be attached to a Zilliqa transaction.
+//
+// config must be the result of a key generation on secp256k1, its public key being the compressed
+// config.PublicKey, and message is the serialized transaction, rather than its hash.
+//
+// Returns a zilliqa.Signature if successful.
+func SignZilliqa(config *Config, signers []party.ID, message []byte) protocol.StartFunc {
+	return sign.StartSignZilliqa(config, signers, message)
+}
+
+// SignCiphersuite is like Sign, but follows one of the ciphersuites of RFC 9591, so that the signatures
+// interoperate with other implementations of the standard.
+//
+// config must be the result of a key generation on the group of suite, and message is the message itself,
+// rather than its hash. The identifier of each signer is id.Scalar(suite.Group()).
+//
+// Returns the []byte encoding of the signature specified by suite, which suite.Verify checks.
+func SignCiphersuite(suite *Ciphersuite, config *Config, signers []party.ID, message []byte) protocol.StartFunc {
+	return sign.StartSignCiphersuite(suite, config, signers, message)
+}
+
+// SignBatch is like Sign, but generates a signature for each of messageHashes, in the same order,
+// in a single 3 round session.
+//
+// Returns []Signature if successful.
+func SignBatch(config *Config, signers []party.ID, messageHashes [][]byte) protocol.StartFunc {
+	return sign.StartSignBatch(false, config, signers, messageHashes)
+}
+
+// SignTaprootBatch is like SignBatch, but generates Taproot / BIP-340 compatible signatures.
+//
+// Returns []taproot.Signature if successful.
+func SignTaprootBatch(config *TaprootConfig, signers []party.ID, messageHashes [][]byte) protocol.StartFunc {
+	normalResult, err := genericConfig(config)
+	if err != nil {
+		return func([]byte) (round.Session, error) {
+			return nil, err
+		}
+	}
+	return sign.StartSignBatch(true, normalResult, signers, messageHashes)
+}
+
+// SignWithAggregator is like Sign, but one of the signers plays the role of the signature aggregator
+// from the Frost paper.
+//
+// Instead of broadcasting their commitments and responses, the other signers send them to the aggregator,
+// which checks the responses, computes the signature, and sends it to them.
+// This reduces the number of messages from O(n²) to O(n), at the cost of two more rounds.
+//
+// aggregator must be one of the signers, and the same for all of them.
+// A malicious aggregator can make the protocol abort, but not produce an invalid signature.
+func SignWithAggregator(config *Config, signers []party.ID, aggregator party.ID, messageHash []byte) protocol.StartFunc {
+	return sign.StartSignAggregator(false, config, signers, aggregator, messageHash)
+}
+
+// SignTaprootWithAggregator is like SignWithAggregator, but will generate a Taproot / BIP-340 compatible signature.
+func SignTaprootWithAggregator(config *TaprootConfig, signers []party.ID, aggregator party.ID, messageHash []byte) protocol.StartFunc {
+	normalResult, err := genericConfig(config)
+	if err != nil {
+		return func([]byte) (round.Session, error) {
+			return nil, err
+		}
+	}
+	return sign.StartSignAggregator(true, normalResult, signers, aggregator, messageHash)
+}
+
+// genericConfig converts a TaprootConfig into the Config used by the signing protocol.
+func genericConfig(config *TaprootConfig) (*keygen.Config, error) {
+	publicKey, err := curve.Secp256k1{}.LiftX(config.PublicKey)
+	if err != nil {
+		return nil, err
+	}
 	genericVerificationShares := make(map[party.ID]curve.Point)
 	for k, v := range config.VerificationShares {
 		genericVerificationShares[k] = v
 	}
-	normalResult := &keygen.Config{
+	return &keygen.Config{
 		ID:                 config.ID,
 		Threshold:          config.Threshold,
 		PrivateShare:       config.PrivateShare,
 		PublicKey:          publicKey,
 		VerificationShares: party.NewPointMap(genericVerificationShares),
+	}, nil
+}
+
+// BlindCommit initiates the first phase of the blind signing protocol.
+//
+// The signers jointly generate a nonce, whose commitment R is returned as part of the
+// *blind.Nonce. R should then be sent to the client, who calls blind.Blind to derive
+// a blinded challenge for their message.
+//
+// Each *blind.Nonce must be passed to BlindSign exactly once, or discarded with blind.Limit.Discard.
+//
+// This is a plain blind Schnorr signature, which a client answered in concurrent sessions can forge
+// with the ROS attack; limit bounds the nonces open at once for the key, see blind.Limit.
+func BlindCommit(config *Config, limit *blind.Limit, signers []party.ID) protocol.StartFunc {
+	return blind.StartCommit(config, limit, signers)
+}
+
+// BlindSign initiates the second phase of the blind signing protocol.
+//
+// The same signers as in BlindCommit answer the blinded challenge sent by the client.
+// The result is a curve.Scalar z which should be sent back to the client, who can unblind
+// it into a signature of their message using blind.Blinding.Unblind.
+//
+// The signers learn neither the message, nor the final signature.
+func BlindSign(config *Config, limit *blind.Limit, nonce *blind.Nonce, challenge curve.Scalar) protocol.StartFunc {
+	return blind.StartSign(config, limit, nonce, challenge)
+}
+
+// ReconstructSecret recovers the full private key from the configs of at least Threshold+1 parties.
+//
+// WARNING: this defeats the purpose of threshold signing, since whoever holds the key can sign alone.
+// It is only meant for disaster recovery procedures; see keygen.ReconstructSecret.
+func ReconstructSecret(configs []*Config) (curve.Scalar, error) {
+	return keygen.ReconstructSecret(configs)
+}
+
+// ReconstructSecretTaproot is like ReconstructSecret, for Taproot configs.
+//
+// The result is the BIP-340 secret key of config.PublicKey, whose public point has an even Y coordinate.
+func ReconstructSecretTaproot(configs []*TaprootConfig) (*curve.Secp256k1Scalar, error) {
+	generic := make([]*Config, 0, len(configs))
+	for _, c := range configs {
+		if c.PrivateShare == nil {
+			return nil, fmt.Errorf("frost: the config of %s has no secret share", c.ID)
+		}
+		g, err := genericConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		generic = append(generic, g)
 	}
-	return sign.StartSignCommon(true, normalResult, signers, messageHash)
+	secret, err := keygen.ReconstructSecret(generic)
+	if err != nil {
+		return nil, err
+	}
+	return secret.(*curve.Secp256k1Scalar), nil
 }
@@ -2,6 +2,7 @@ package frost
 
 import (
 	"bytes"
+	"crypto/rand"
 	"fmt"
 	"sync"
 	"testing"
@@ -75,6 +76,74 @@ func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte
 	require.IsType(t, taproot.Signature{}, signResult)
 	taprootSignature := signResult.(taproot.Signature)
 	assert.True(t, cTaproot.PublicKey.Verify(taprootSignature, message))
+
+	// spend a Taproot output with a script path through its key path
+	merkleRoot := make([]byte, 32)
+	outputKey, _, err := cTaproot.PublicKey.OutputKey(merkleRoot)
+	require.NoError(t, err)
+	cTweaked, err := cTaproot.TapTweak(merkleRoot)
+	require.NoError(t, err)
+	require.Equal(t, outputKey, cTweaked.PublicKey)
+	h, err = protocol.NewMultiHandler(SignTaproot(cTweaked, ids, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, taproot.Signature{}, signResult)
+	assert.True(t, outputKey.Verify(signResult.(taproot.Signature), message))
+
+	h, err = protocol.NewMultiHandler(SignCiphersuite(Secp256k1SHA256, c, ids, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, []byte{}, signResult)
+	assert.True(t, Secp256k1SHA256.Verify(c.PublicKey, message, signResult.([]byte)))
+
+	messages := [][]byte{message, []byte("world"), []byte("!")}
+	h, err = protocol.NewMultiHandler(SignBatch(c, ids, messages), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, []Signature{}, signResult)
+	for i, signature := range signResult.([]Signature) {
+		assert.True(t, signature.Verify(c.PublicKey, messages[i]))
+	}
+
+	h, err = protocol.NewMultiHandler(SignTaprootBatch(cTaproot, ids, messages), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, []taproot.Signature{}, signResult)
+	for i, signature := range signResult.([]taproot.Signature) {
+		assert.True(t, cTaproot.PublicKey.Verify(signature, messages[i]))
+	}
+
+	h, err = protocol.NewMultiHandler(SignWithAggregator(c, ids, ids[0], message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, Signature{}, signResult)
+	signature = signResult.(Signature)
+	assert.True(t, signature.Verify(c.PublicKey, message))
+
+	h, err = protocol.NewMultiHandler(SignTaprootWithAggregator(cTaproot, ids, ids[0], message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, taproot.Signature{}, signResult)
+	taprootSignature = signResult.(taproot.Signature)
+	assert.True(t, cTaproot.PublicKey.Verify(taprootSignature, message))
 }
 
 func TestFrost(t *testing.T) {
@@ -94,3 +163,50 @@ func TestFrost(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestReconstructSecret(t *testing.T) {
+	N, T := 3, 1
+	partyIDs := test.PartyIDs(N)
+	n := test.NewNetwork(partyIDs)
+
+	configs := make([]*Config, N)
+	taprootConfigs := make([]*TaprootConfig, N)
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for i, id := range partyIDs {
+		go func(i int, id party.ID) {
+			defer wg.Done()
+			h, err := protocol.NewMultiHandler(Keygen(curve.Secp256k1{}, id, partyIDs, T), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(id, h, n)
+			r, err := h.Result()
+			require.NoError(t, err)
+			configs[i] = r.(*Config)
+
+			h, err = protocol.NewMultiHandler(KeygenTaproot(id, partyIDs, T), nil)
+			require.NoError(t, err)
+			test.HandlerLoop(id, h, n)
+			r, err = h.Result()
+			require.NoError(t, err)
+			taprootConfigs[i] = r.(*TaprootConfig)
+		}(i, id)
+	}
+	wg.Wait()
+
+	secret, err := ReconstructSecret(configs[1:])
+	require.NoError(t, err)
+	assert.True(t, secret.ActOnBase().Equal(configs[0].PublicKey))
+	_, err = ReconstructSecret(configs[:1])
+	assert.Error(t, err, "not enough configs")
+	_, err = ReconstructSecret([]*Config{configs[0], configs[0]})
+	assert.Error(t, err, "duplicate config")
+
+	taprootSecret, err := ReconstructSecretTaproot(taprootConfigs[:2])
+	require.NoError(t, err)
+	message := []byte("hello")
+	data, err := taprootSecret.MarshalBinary()
+	require.NoError(t, err)
+	signature, err := taproot.SecretKey(data).Sign(rand.Reader, message)
+	require.NoError(t, err)
+	assert.True(t, taprootConfigs[0].PublicKey.Verify(signature, message))
+}
@@ -54,6 +54,31 @@ func (r *Config) Curve() curve.Curve {
 	return r.PublicKey.Curve()
 }
 
+// PublicKeyBytes returns the SEC 1 encoding of PublicKey, in 33 bytes if compressed, or 65 bytes otherwise.
+//
+// This fails for curves such as Edwards25519, which SEC 1 doesn't cover.
+func (r *Config) PublicKeyBytes(compressed bool) ([]byte, error) {
+	return curve.MarshalSEC1(r.PublicKey, compressed)
+}
+
+// KeyID returns the fingerprint of PublicKey computed by curve.KeyID.
+func (r *Config) KeyID() string {
+	return curve.KeyID(r.PublicKey)
+}
+
+// VerificationShareBytes returns the SEC 1 encodings of the VerificationShares of all parties.
+func (r *Config) VerificationShareBytes(compressed bool) (map[party.ID][]byte, error) {
+	shares := make(map[party.ID][]byte, len(r.VerificationShares.Points))
+	for j, point := range r.VerificationShares.Points {
+		data, err := curve.MarshalSEC1(point, compressed)
+		if err != nil {
+			return nil, err
+		}
+		shares[j] = data
+	}
+	return shares, nil
+}
+
 // Derive performs an arbitrary derivation of a related key, by adding a scalar.
 //
 // This can support methods like BIP32, but is more general.
@@ -157,7 +182,36 @@ func (r *TaprootConfig) Derive(adjust *curve.Secp256k1Scalar, newChainKey []byte
 	if len(newChainKey) != params.SecBytes {
 		return nil, fmt.Errorf("expecte %d bytes for chain key, found %d", params.SecBytes, len(newChainKey))
 	}
+	return r.adjust(adjust, newChainKey)
+}
+
+// TapTweak adjusts the shares to represent the output key of BIP-341, for which this key is the internal key.
+//
+// merkleRoot is the root of the script tree of the output, or empty for an output without script path, as in BIP-86.
+// The PublicKey of the result is then the output key, and its signatures spend the output through the key path.
+func (r *TaprootConfig) TapTweak(merkleRoot []byte) (*TaprootConfig, error) {
+	t, err := taproot.TapTweak(r.PublicKey, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	return r.adjust(t, r.ChainKey)
+}
+
+// Tweak adjusts the shares to represent the key P + tG, where P is our public key, and t is
+// the scalar encoded by tweak, as 32 big endian bytes.
+//
+// Like every Taproot key, the result represents the version of P + tG with an even y coordinate,
+// and the shares are negated accordingly. The chain key is kept as is.
+func (r *TaprootConfig) Tweak(tweak []byte) (*TaprootConfig, error) {
+	t := new(curve.Secp256k1Scalar)
+	if err := t.UnmarshalBinary(tweak); err != nil {
+		return nil, fmt.Errorf("tweak: %w", err)
+	}
+	return r.adjust(t, r.ChainKey)
+}
 
+// adjust adds adjust to the secret key, and negates it if the resulting public key has an odd y coordinate.
+func (r *TaprootConfig) adjust(adjust *curve.Secp256k1Scalar, newChainKey []byte) (*TaprootConfig, error) {
 	adjustG := adjust.ActOnBase()
 	verificationShares := make(map[party.ID]*curve.Secp256k1Point, len(r.VerificationShares))
 	for k, v := range r.VerificationShares {
@@ -1,6 +1,7 @@
 package keygen
 
 import (
+	"crypto/rand"
 	"testing"
 
 	"github.com/fxamacker/cbor/v2"
@@ -10,6 +11,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/internal/test"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 )
 
@@ -57,6 +59,13 @@ func checkOutput(t *testing.T, rounds []round.Session, parties party.IDSlice) {
 			expected := shares[id].ActOnBase()
 			require.True(t, result.VerificationShares.Points[id].Equal(expected), "different verification shares", id)
 		}
+		publicKeyBytes, err := result.PublicKeyBytes(true)
+		require.NoError(t, err)
+		expectedBytes, _ := publicKey.MarshalBinary()
+		assert.Equal(t, expectedBytes, publicKeyBytes)
+		shareBytes, err := result.VerificationShareBytes(false)
+		require.NoError(t, err)
+		assert.Len(t, shareBytes, len(parties))
 		marshalled, err := cbor.Marshal(result)
 		require.NoError(t, err)
 		unmarshalledResult := EmptyConfig(group)
@@ -74,11 +83,12 @@ func TestKeygen(t *testing.T) {
 	N := 5
 	partyIDs := test.PartyIDs(N)
 
+	tracer := test.NewTracer()
 	rounds := make([]round.Session, 0, N)
 	for _, partyID := range partyIDs {
 		r, err := StartKeygenCommon(false, group, partyIDs, N-1, partyID, nil, nil, nil)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
-		rounds = append(rounds, r)
+		rounds = append(rounds, tracer.Session(r))
 	}
 
 	for {
@@ -88,6 +98,7 @@ func TestKeygen(t *testing.T) {
 			break
 		}
 	}
+	require.NoError(t, tracer.Check(Model()), "rounds should follow the model")
 
 	checkOutput(t, rounds, partyIDs)
 }
@@ -164,3 +175,50 @@ func TestKeygenTaproot(t *testing.T) {
 
 	checkOutputTaproot(t, rounds, partyIDs)
 }
+
+func TestTaprootConfigTweak(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(3)
+	lagrange := polynomial.Lagrange(group, partyIDs)
+
+	secret := sample.Scalar(rand.Reader, group)
+	public := secret.ActOnBase().(*curve.Secp256k1Point)
+	if !public.HasEvenY() {
+		secret.Negate()
+	}
+	f := polynomial.NewPolynomial(group, 2, secret)
+	configs := make([]*TaprootConfig, 0, len(partyIDs))
+	verificationShares := make(map[party.ID]*curve.Secp256k1Point)
+	for _, id := range partyIDs {
+		share := f.Evaluate(id.Scalar(group)).(*curve.Secp256k1Scalar)
+		verificationShares[id] = share.ActOnBase().(*curve.Secp256k1Point)
+		configs = append(configs, &TaprootConfig{ID: id, Threshold: 2, PrivateShare: share, PublicKey: public.XBytes()})
+	}
+	for _, c := range configs {
+		c.VerificationShares = verificationShares
+	}
+
+	// the resulting key has an odd y coordinate about half of the time
+	for i := 0; i < 8; i++ {
+		tweak, _ := sample.Scalar(rand.Reader, group).MarshalBinary()
+		privateKey := group.NewScalar()
+		var tweaked *TaprootConfig
+		for _, c := range configs {
+			var err error
+			tweaked, err = c.Tweak(tweak)
+			require.NoError(t, err)
+			privateKey.Add(group.NewScalar().Set(lagrange[c.ID]).Mul(tweaked.PrivateShare))
+			assert.True(t, tweaked.PrivateShare.ActOnBase().Equal(tweaked.VerificationShares[c.ID]))
+		}
+		publicKey := privateKey.ActOnBase().(*curve.Secp256k1Point)
+		assert.True(t, publicKey.HasEvenY(), "tweaked key should have an even y coordinate")
+		assert.Equal(t, []byte(tweaked.PublicKey), publicKey.XBytes())
+	}
+
+	invalid := make([]byte, 32)
+	for i := range invalid {
+		invalid[i] = 0xff
+	}
+	_, err := configs[0].Tweak(invalid)
+	assert.Error(t, err, "tweak is larger than the order")
+}
@@ -0,0 +1,81 @@
+package keygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// ReconstructSecret recovers the private key shared by the given configs, of at least Threshold+1 distinct parties.
+//
+// WARNING: the result lets its holder sign on their own, which threshold signing exists to prevent.
+// It is only meant for disaster recovery, such as sweeping funds before the parties are decommissioned,
+// and should be erased right after.
+//
+// The configs must have the same threshold, public key and verification shares,
+// and the result is checked against the public key.
+func ReconstructSecret(configs []*Config) (curve.Scalar, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("keygen: no configs to reconstruct the secret from")
+	}
+	first := configs[0]
+	if len(configs) <= first.Threshold {
+		return nil, fmt.Errorf("keygen: %d configs are needed to reconstruct the secret, got %d", first.Threshold+1, len(configs))
+	}
+	ids := make([]party.ID, 0, len(configs))
+	shares := make(map[party.ID]curve.Scalar, len(configs))
+	for _, c := range configs {
+		if c.PrivateShare == nil {
+			return nil, fmt.Errorf("keygen: the config of %s has no secret share", c.ID)
+		}
+		if _, ok := shares[c.ID]; ok {
+			return nil, fmt.Errorf("keygen: several configs belong to %s", c.ID)
+		}
+		if err := samePublicData(first, c); err != nil {
+			return nil, fmt.Errorf("keygen: the config of %s is inconsistent with that of %s: %w", c.ID, first.ID, err)
+		}
+		public, ok := c.VerificationShares.Points[c.ID]
+		if !ok || !c.PrivateShare.ActOnBase().Equal(public) {
+			return nil, fmt.Errorf("keygen: the secret share of %s doesn't match its verification share", c.ID)
+		}
+		ids = append(ids, c.ID)
+		shares[c.ID] = c.PrivateShare
+	}
+
+	// s = ∑ⱼ λⱼ⋅sⱼ
+	group := first.Curve()
+	secret := group.NewScalar()
+	for j, lambda := range polynomial.Lagrange(group, ids) {
+		secret.Add(lambda.Mul(shares[j]))
+	}
+	if !secret.ActOnBase().Equal(first.PublicKey) {
+		return nil, errors.New("keygen: the reconstructed secret doesn't match the public key")
+	}
+	return secret, nil
+}
+
+// samePublicData returns an error describing the first difference between the public data of a and b.
+func samePublicData(a, b *Config) error {
+	if a.Threshold != b.Threshold {
+		return fmt.Errorf("threshold is %d, instead of %d", b.Threshold, a.Threshold)
+	}
+	if !a.PublicKey.Equal(b.PublicKey) {
+		return errors.New("public key differs")
+	}
+	if len(a.VerificationShares.Points) != len(b.VerificationShares.Points) {
+		return errors.New("parties differ")
+	}
+	for j, share := range a.VerificationShares.Points {
+		other, ok := b.VerificationShares.Points[j]
+		if !ok {
+			return fmt.Errorf("party %s is missing", j)
+		}
+		if !share.Equal(other) {
+			return fmt.Errorf("verification share of party %s differs", j)
+		}
+	}
+	return nil
+}
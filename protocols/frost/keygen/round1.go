@@ -1,9 +1,9 @@
 package keygen
 
 import (
-	"crypto/rand"
 	"fmt"
 
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/internal/types"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
@@ -73,7 +73,7 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	a_i0 := group.NewScalar()
 	a_i0_times_G := group.NewPoint()
 	if !r.refresh {
-		a_i0 = sample.Scalar(rand.Reader, r.Group())
+		a_i0 = sample.Scalar(random.Reader(), r.Group())
 		a_i0_times_G = a_i0.ActOnBase()
 	}
 	f_i := polynomial.NewPolynomial(r.Group(), r.threshold, a_i0)
@@ -111,7 +111,7 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	Phi_i := polynomial.NewPolynomialExponent(f_i)
 
 	// c_i is our contribution to the chaining key
-	c_i, err := types.NewRID(rand.Reader)
+	c_i, err := types.NewRID(random.Reader())
 	if err != nil {
 		return r, fmt.Errorf("failed to sample ChainKey")
 	}
@@ -133,8 +133,14 @@ func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
 	if err != nil {
 		panic(err)
 	}
-	for k, v := range r.verificationShares {
-		r.verificationShares[k] = v.Add(verificationExponent.Evaluate(k.Scalar(r.Group())))
+	ids := make([]party.ID, 0, len(r.verificationShares))
+	xs := make([]curve.Scalar, 0, len(r.verificationShares))
+	for k := range r.verificationShares {
+		ids = append(ids, k)
+		xs = append(xs, k.Scalar(r.Group()))
+	}
+	for i, share := range verificationExponent.EvaluateMulti(xs) {
+		r.verificationShares[ids[i]] = r.verificationShares[ids[i]].Add(share)
 	}
 
 	if r.taproot {
@@ -0,0 +1,109 @@
+package keygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/types"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+var _ round.Suspendable = (*round2)(nil)
+
+// suspendedState is the state of round2 and round3 which doesn't come from the messages of the current round.
+//
+// The secret shares of round3 are not included: our own is recomputed from f_i,
+// and the others are received again when resuming.
+type suspendedState struct {
+	F_i                  []byte
+	Phi                  map[party.ID][]byte
+	ChainKeys            map[party.ID]types.RID
+	ChainKeyDecommitment hash.Decommitment
+	ChainKeyCommitments  map[party.ID]hash.Commitment
+}
+
+// MarshalState implements round.Suspendable.
+func (r *round2) MarshalState() ([]byte, error) {
+	f_i, err := r.f_i.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	phi := make(map[party.ID][]byte, len(r.Phi))
+	for id, phi_l := range r.Phi {
+		if phi[id], err = phi_l.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	return cbor.Marshal(&suspendedState{
+		F_i:                  f_i,
+		Phi:                  phi,
+		ChainKeys:            r.ChainKeys,
+		ChainKeyDecommitment: r.ChainKeyDecommitment,
+		ChainKeyCommitments:  r.ChainKeyCommitments,
+	})
+}
+
+// Resume returns a protocol.ResumeFunc for sessions created by start, which must be
+// the StartFunc returned by StartKeygenCommon with the same arguments as the suspended session.
+func Resume(start protocol.StartFunc) protocol.ResumeFunc {
+	return func(sessionID []byte, number round.Number, data []byte) (round.Session, error) {
+		if number != 2 && number != 3 {
+			return nil, fmt.Errorf("keygen: cannot resume round %d", number)
+		}
+		r, err := start(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		r1, ok := r.(*round1)
+		if !ok {
+			return nil, errors.New("keygen: start does not create a keygen session")
+		}
+
+		var s suspendedState
+		if err = cbor.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("keygen: invalid state: %w", err)
+		}
+		group := r1.Group()
+		r2 := &round2{
+			round1:               r1,
+			f_i:                  polynomial.EmptyPolynomial(group),
+			Phi:                  make(map[party.ID]*polynomial.Exponent, len(s.Phi)),
+			ChainKeys:            s.ChainKeys,
+			ChainKeyDecommitment: s.ChainKeyDecommitment,
+			ChainKeyCommitments:  s.ChainKeyCommitments,
+		}
+		if err = r2.f_i.UnmarshalBinary(s.F_i); err != nil {
+			return nil, fmt.Errorf("keygen: invalid polynomial: %w", err)
+		}
+		for id, data := range s.Phi {
+			r2.Phi[id] = polynomial.EmptyExponent(group)
+			if err = r2.Phi[id].UnmarshalBinary(data); err != nil {
+				return nil, fmt.Errorf("keygen: invalid commitment of %s: %w", id, err)
+			}
+		}
+		if r2.ChainKeys == nil {
+			r2.ChainKeys = map[party.ID]types.RID{}
+		}
+		if r2.ChainKeyCommitments == nil {
+			r2.ChainKeyCommitments = map[party.ID]hash.Commitment{}
+		}
+		if r2.ChainKeys[r2.SelfID()] == nil || r2.Phi[r2.SelfID()] == nil {
+			return nil, errors.New("keygen: state is missing our own contribution")
+		}
+
+		if number == 2 {
+			return r2, nil
+		}
+		selfShare := r2.f_i.Evaluate(r2.SelfID().Scalar(group))
+		return &round3{
+			round2:    r2,
+			shareFrom: map[party.ID]curve.Scalar{r2.SelfID(): selfShare},
+		}, nil
+	}
+}
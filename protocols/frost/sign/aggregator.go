@@ -0,0 +1,384 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// These rounds follow Figure 3 of the Frost paper more closely:
+//
+//	https://eprint.iacr.org/2020/852.pdf
+//
+// One of the signers plays the role of the signature aggregator SA. The other signers only talk to SA,
+// which sends them the bundle of commitments B, collects their responses, and sends them the signature.
+// Every party thus sends O(1) messages, and SA O(n), instead of the O(n²) messages of the broadcast rounds.
+//
+// Since SA is also a signer, and every party checks what it receives, a malicious SA can only make the protocol abort.
+
+// aggregatorRound1 generates our nonces, and sends the commitments to SA.
+type aggregatorRound1 struct {
+	*round1
+	// aggregator is the ID of SA.
+	aggregator party.ID
+}
+
+// Finalize implements round.Round.
+func (r *aggregatorRound1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	d_i, e_i, err := r.nonces()
+	if err != nil {
+		return r, err
+	}
+
+	D_i := d_i.ActOnBase()
+	E_i := e_i.ActOnBase()
+
+	if r.SelfID() != r.aggregator {
+		if err = r.SendMessage(out, &aggregatorMessage2{D_i: D_i, E_i: E_i}, r.aggregator); err != nil {
+			return r, err
+		}
+	}
+	return &aggregatorRound2{
+		aggregatorRound1: r,
+		d_i:              d_i,
+		e_i:              e_i,
+		D:                map[party.ID]curve.Point{r.SelfID(): D_i},
+		E:                map[party.ID]curve.Point{r.SelfID(): E_i},
+	}, nil
+}
+
+// aggregatorRound2 is where SA collects the commitments of the other signers.
+type aggregatorRound2 struct {
+	*aggregatorRound1
+	// d_i = dᵢ is the first nonce we've created.
+	d_i curve.Scalar
+	// e_i = eᵢ is the second nonce we've created.
+	e_i curve.Scalar
+	// D[i] = Dᵢ will contain all of the commitments created by each party, ourself included.
+	D map[party.ID]curve.Point
+	// E[i] = Eᵢ will contain all of the commitments created by each party, ourself included.
+	E map[party.ID]curve.Point
+}
+
+type aggregatorMessage2 struct {
+	// D_i is the first commitment produced by the sender of this message.
+	D_i curve.Point
+	// E_i is the second commitment produced by the sender of this message.
+	E_i curve.Point
+}
+
+// Senders implements round.PartialRound.
+func (r *aggregatorRound2) Senders() []party.ID {
+	if r.SelfID() == r.aggregator {
+		return r.OtherPartyIDs()
+	}
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (aggregatorRound2) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*aggregatorMessage2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.D_i == nil || body.E_i == nil {
+		return round.ErrNilFields
+	}
+	if body.D_i.IsIdentity() || body.E_i.IsIdentity() {
+		return fmt.Errorf("nonce commitment is the identity point")
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *aggregatorRound2) StoreMessage(msg round.Message) error {
+	body := msg.Content.(*aggregatorMessage2)
+	r.D[msg.From] = body.D_i
+	r.E[msg.From] = body.E_i
+	return nil
+}
+
+// Finalize implements round.Round.
+func (r *aggregatorRound2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// 2. "SA then sends (m, B) to each Pᵢ in S."
+	//
+	// The message was already agreed upon, so SA only sends B.
+	if r.SelfID() == r.aggregator {
+		for _, j := range r.OtherPartyIDs() {
+			err := r.SendMessage(out, &aggregatorMessage3{D: party.NewPointMap(r.D), E: party.NewPointMap(r.E)}, j)
+			if err != nil {
+				return r, err
+			}
+		}
+	}
+	return &aggregatorRound3{aggregatorRound2: r}, nil
+}
+
+// MessageContent implements round.Round.
+func (r *aggregatorRound2) MessageContent() round.Content {
+	return &aggregatorMessage2{
+		D_i: r.Group().NewPoint(),
+		E_i: r.Group().NewPoint(),
+	}
+}
+
+// RoundNumber implements round.Content.
+func (aggregatorMessage2) RoundNumber() round.Number { return 2 }
+
+// Number implements round.Round.
+func (aggregatorRound2) Number() round.Number { return 2 }
+
+// aggregatorRound3 is where the signers receive the bundle of commitments B from SA, and send their response to SA.
+type aggregatorRound3 struct {
+	*aggregatorRound2
+}
+
+type aggregatorMessage3 struct {
+	// D[l] = Dₗ is the first commitment of party l.
+	D *party.PointMap
+	// E[l] = Eₗ is the second commitment of party l.
+	E *party.PointMap
+}
+
+// Senders implements round.PartialRound.
+func (r *aggregatorRound3) Senders() []party.ID {
+	if r.SelfID() == r.aggregator {
+		return nil
+	}
+	return []party.ID{r.aggregator}
+}
+
+// VerifyMessage implements round.Round.
+func (r *aggregatorRound3) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*aggregatorMessage3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.D == nil || body.E == nil {
+		return round.ErrNilFields
+	}
+
+	// 3. "After receiving (m, B), each Pᵢ first validates the message m,
+	// and then checks Dₗ, Eₗ in Gˣ for each commitment in B, aborting if
+	// either check fails."
+	//
+	// We also check that B contains our own commitments, so that SA can't compute a signature for different nonces.
+	if len(body.D.Points) != r.N() || len(body.E.Points) != r.N() {
+		return errors.New("bundle has the wrong number of commitments")
+	}
+	for _, l := range r.PartyIDs() {
+		D_l, E_l := body.D.Points[l], body.E.Points[l]
+		if D_l == nil || E_l == nil {
+			return fmt.Errorf("bundle is missing the commitments of %s", l)
+		}
+		if D_l.IsIdentity() || E_l.IsIdentity() {
+			return fmt.Errorf("nonce commitment of %s is the identity point", l)
+		}
+	}
+	if !body.D.Points[r.SelfID()].Equal(r.D[r.SelfID()]) || !body.E.Points[r.SelfID()].Equal(r.E[r.SelfID()]) {
+		return errors.New("bundle contains the wrong commitments for us")
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *aggregatorRound3) StoreMessage(msg round.Message) error {
+	body := msg.Content.(*aggregatorMessage3)
+	r.D = body.D.Points
+	r.E = body.E.Points
+	return nil
+}
+
+// Finalize implements round.Round.
+func (r *aggregatorRound3) Finalize(out chan<- *round.Message) (round.Session, error) {
+	com, err := r.commit(r.D, r.E)
+	if err != nil {
+		return r, err
+	}
+
+	// Lambdas[i] = λᵢ
+	Lambdas := lagrangeCache.Lagrange(r.Group(), r.PartyIDs())
+	z_i := r.response(com, Lambdas[r.SelfID()], r.d_i, r.e_i)
+
+	// 6. "Each Pᵢ securely deletes ((dᵢ, Dᵢ), (eᵢ, Eᵢ)) from their local storage,
+	// and returns zᵢ to SA."
+
+	// TODO: Securely delete the nonces.
+
+	if r.SelfID() != r.aggregator {
+		if err = r.SendMessage(out, &aggregatorMessage4{Z_i: z_i}, r.aggregator); err != nil {
+			return r, err
+		}
+	}
+	return &aggregatorRound4{
+		aggregatorRound3: r,
+		commitment:       com,
+		z:                map[party.ID]curve.Scalar{r.SelfID(): z_i},
+		Lambda:           Lambdas,
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (r *aggregatorRound3) MessageContent() round.Content {
+	return &aggregatorMessage3{
+		D: party.EmptyPointMap(r.Group()),
+		E: party.EmptyPointMap(r.Group()),
+	}
+}
+
+// RoundNumber implements round.Content.
+func (aggregatorMessage3) RoundNumber() round.Number { return 3 }
+
+// Number implements round.Round.
+func (aggregatorRound3) Number() round.Number { return 3 }
+
+// aggregatorRound4 is where SA verifies the responses, and sends the signature to the other signers.
+type aggregatorRound4 struct {
+	*aggregatorRound3
+	*commitment
+	// z contains the response from each participant
+	//
+	// z[i] corresponds to zᵢ in the Frost paper
+	z map[party.ID]curve.Scalar
+	// Lambda contains all Lagrange coefficients of the parties participating in this session.
+	// Lambda[l] = λₗ
+	Lambda map[party.ID]curve.Scalar
+}
+
+type aggregatorMessage4 struct {
+	// Z_i is the response scalar computed by the sender of this message.
+	Z_i curve.Scalar
+}
+
+// Senders implements round.PartialRound.
+func (r *aggregatorRound4) Senders() []party.ID {
+	if r.SelfID() == r.aggregator {
+		return r.OtherPartyIDs()
+	}
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (r *aggregatorRound4) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*aggregatorMessage4)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.Z_i == nil {
+		return round.ErrNilFields
+	}
+
+	// 7.b "Verify the validity of each response by checking
+	//
+	//    zᵢ • G = Rᵢ + c * λᵢ * Yᵢ
+	//
+	// for each share zᵢ, i in S. If the equality does not hold, identify and report the
+	// misbehaving participant, and then abort. Otherwise, continue."
+	return r.verifyResponse(r.RShares[msg.From], r.c, r.Lambda[msg.From], msg.From, body.Z_i)
+}
+
+// StoreMessage implements round.Round.
+func (r *aggregatorRound4) StoreMessage(msg round.Message) error {
+	r.z[msg.From] = msg.Content.(*aggregatorMessage4).Z_i
+	return nil
+}
+
+// Finalize implements round.Round.
+func (r *aggregatorRound4) Finalize(out chan<- *round.Message) (round.Session, error) {
+	if r.SelfID() != r.aggregator {
+		return &aggregatorRound5{aggregatorRound4: r}, nil
+	}
+
+	// 7.c "Compute the group's response z = ∑ᵢ zᵢ"
+	z := r.Group().NewScalar()
+	for _, z_l := range r.z {
+		z.Add(z_l)
+	}
+
+	// 7.d "Publish σ = (R, z) along with m."
+	sig, err := r.signature(r.R, z)
+	if err != nil {
+		return r.AbortRound(err), nil
+	}
+	for _, j := range r.OtherPartyIDs() {
+		if err = r.SendMessage(out, &aggregatorMessage5{Z: z}, j); err != nil {
+			return r, err
+		}
+	}
+	return &aggregatorRound5{aggregatorRound4: r, sig: sig}, nil
+}
+
+// MessageContent implements round.Round.
+func (r *aggregatorRound4) MessageContent() round.Content {
+	return &aggregatorMessage4{Z_i: r.Group().NewScalar()}
+}
+
+// RoundNumber implements round.Content.
+func (aggregatorMessage4) RoundNumber() round.Number { return 4 }
+
+// Number implements round.Round.
+func (aggregatorRound4) Number() round.Number { return 4 }
+
+// aggregatorRound5 is where the signers receive the signature from SA.
+type aggregatorRound5 struct {
+	*aggregatorRound4
+	// sig is the signature computed or received by SA.
+	sig interface{}
+}
+
+type aggregatorMessage5 struct {
+	// Z is the group's response z.
+	//
+	// The group commitment R is already known to every signer.
+	Z curve.Scalar
+}
+
+// Senders implements round.PartialRound.
+func (r *aggregatorRound5) Senders() []party.ID {
+	if r.SelfID() == r.aggregator {
+		return nil
+	}
+	return []party.ID{r.aggregator}
+}
+
+// VerifyMessage implements round.Round.
+func (r *aggregatorRound5) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*aggregatorMessage5)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.Z == nil {
+		return round.ErrNilFields
+	}
+	_, err := r.signature(r.R, body.Z)
+	return err
+}
+
+// StoreMessage implements round.Round.
+func (r *aggregatorRound5) StoreMessage(msg round.Message) error {
+	sig, err := r.signature(r.R, msg.Content.(*aggregatorMessage5).Z)
+	if err != nil {
+		return err
+	}
+	r.sig = sig
+	return nil
+}
+
+// Finalize implements round.Round.
+func (r *aggregatorRound5) Finalize(chan<- *round.Message) (round.Session, error) {
+	return r.ResultRound(r.sig), nil
+}
+
+// MessageContent implements round.Round.
+func (r *aggregatorRound5) MessageContent() round.Content {
+	return &aggregatorMessage5{Z: r.Group().NewScalar()}
+}
+
+// RoundNumber implements round.Content.
+func (aggregatorMessage5) RoundNumber() round.Number { return 5 }
+
+// Number implements round.Round.
+func (aggregatorRound5) Number() round.Number { return 5 }
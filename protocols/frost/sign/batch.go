@@ -0,0 +1,79 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost/keygen"
+)
+
+const (
+	protocolIDBatch        = "frost/sign-batch"
+	protocolIDBatchTaproot = "frost/sign-batch-taproot"
+)
+
+// StartSignBatch is like StartSignCommon, but produces one signature for each of messageHashes.
+//
+// Each signature gets its own pair of nonces, and all of them are committed to in the first broadcast,
+// so the session still takes 3 rounds.
+func StartSignBatch(taproot bool, result *keygen.Config, signers []party.ID, messageHashes [][]byte) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if len(messageHashes) == 0 {
+			return nil, errors.New("sign.StartSignBatch: no message")
+		}
+		info := round.Info{
+			FinalRoundNumber: protocolRounds,
+			SelfID:           result.ID,
+			PartyIDs:         signers,
+			Threshold:        result.Threshold,
+			Group:            result.PublicKey.Curve(),
+		}
+		if taproot {
+			info.ProtocolID = protocolIDBatchTaproot
+		} else {
+			info.ProtocolID = protocolIDBatch
+		}
+
+		auxInfo := make([]hash.WriterToWithDomain, 0, len(messageHashes))
+		for _, m := range messageHashes {
+			auxInfo = append(auxInfo, messageHash(m))
+		}
+		helper, err := round.NewSession(info, sessionID, nil, auxInfo...)
+		if err != nil {
+			return nil, fmt.Errorf("sign.StartSignBatch: %w", err)
+		}
+
+		starts := make([]func([]byte) (round.Session, error), 0, len(messageHashes))
+		for _, m := range messageHashes {
+			starts = append(starts, StartSignCommon(taproot, result, signers, m))
+		}
+		output := signatures
+		if taproot {
+			output = taprootSignatures
+		}
+		return round.NewBatch(helper, starts, output)
+	}
+}
+
+// signatures converts the results of a batch into a []Signature.
+func signatures(results []interface{}) interface{} {
+	sigs := make([]Signature, 0, len(results))
+	for _, r := range results {
+		sigs = append(sigs, r.(Signature))
+	}
+	return sigs
+}
+
+// taprootSignatures converts the results of a batch into a []taproot.Signature.
+func taprootSignatures(results []interface{}) interface{} {
+	sigs := make([]taproot.Signature, 0, len(results))
+	for _, r := range results {
+		sigs = append(sigs, r.(taproot.Signature))
+	}
+	return sigs
+}
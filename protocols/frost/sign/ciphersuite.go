@@ -0,0 +1,210 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+)
+
+// Ciphersuite is one of the ciphersuites of RFC 9591 (formerly draft-irtf-cfrg-frost),
+// which fixes the group, the hash functions, and the encodings used by the signing protocol.
+//
+// With a ciphersuite, the signatures, binding factors, and challenges are computed as specified,
+// so that the signatures can be verified by, and the signature shares combined with, other conforming implementations.
+// The identifier of a party is the scalar id.Scalar(group).
+//
+// FROST(P-256, SHA-256) is not available, since P-256 isn't one of the groups implemented by this library.
+type Ciphersuite struct {
+	// ID is the context string of the ciphersuite.
+	ID    string
+	group curve.Curve
+	// h1, h2 and h3 hash to scalars, and h4 and h5 to bytes, as the functions of the same name in RFC 9591.
+	h1, h2, h3 func(m []byte) curve.Scalar
+	h4, h5     func(m []byte) []byte
+	// serializeScalar is SerializeScalar, since MarshalBinary is always big endian.
+	serializeScalar func(s curve.Scalar) []byte
+}
+
+var (
+	// Ed25519SHA512 is FROST(Ed25519, SHA-512).
+	//
+	// Its signatures are Ed25519 signatures, which can be verified with crypto/ed25519.
+	Ed25519SHA512 = newCiphersuite("FROST-ED25519-SHA512-v1", curve.Edwards25519{})
+	// Secp256k1SHA256 is FROST(secp256k1, SHA-256).
+	Secp256k1SHA256 = newCiphersuite("FROST-secp256k1-SHA256-v1", curve.Secp256k1{})
+)
+
+func newCiphersuite(id string, group curve.Curve) *Ciphersuite {
+	s := &Ciphersuite{ID: id, group: group}
+	switch group.(type) {
+	case curve.Edwards25519:
+		// Scalars are encoded in little endian, and H2 doesn't use the context string, as in RFC 8032.
+		hashToScalar := func(prefix string) func([]byte) curve.Scalar {
+			return func(m []byte) curve.Scalar {
+				h := sha512.New()
+				if prefix != "" {
+					_, _ = h.Write([]byte(id + prefix))
+				}
+				_, _ = h.Write(m)
+				return group.NewScalar().SetNat(new(saferith.Nat).SetBytes(reverse(h.Sum(nil))))
+			}
+		}
+		s.h1 = hashToScalar("rho")
+		s.h2 = hashToScalar("")
+		s.h3 = hashToScalar("nonce")
+		s.h4 = func(m []byte) []byte { return sha512Sum(id+"msg", m) }
+		s.h5 = func(m []byte) []byte { return sha512Sum(id+"com", m) }
+		s.serializeScalar = func(x curve.Scalar) []byte {
+			data, _ := x.MarshalBinary()
+			return reverse(data)
+		}
+	default:
+		hashToScalar := func(prefix string) func([]byte) curve.Scalar {
+			return func(m []byte) curve.Scalar {
+				return group.HashToScalar(m, []byte(id+prefix))
+			}
+		}
+		s.h1 = hashToScalar("rho")
+		s.h2 = hashToScalar("chal")
+		s.h3 = hashToScalar("nonce")
+		s.h4 = func(m []byte) []byte { return sha256Sum(id+"msg", m) }
+		s.h5 = func(m []byte) []byte { return sha256Sum(id+"com", m) }
+		s.serializeScalar = func(x curve.Scalar) []byte {
+			data, _ := x.MarshalBinary()
+			return data
+		}
+	}
+	return s
+}
+
+// Group returns the group of the ciphersuite.
+func (s *Ciphersuite) Group() curve.Curve { return s.group }
+
+// Verify checks that signature is a valid encoding of a signature of message by public.
+func (s *Ciphersuite) Verify(public curve.Point, message, signature []byte) bool {
+	R, z, err := s.decodeSignature(signature)
+	if err != nil {
+		return false
+	}
+	c, err := s.challenge(R, public, message)
+	if err != nil {
+		return false
+	}
+	return z.ActOnBase().Equal(c.Act(public).Add(R))
+}
+
+// nonce implements nonce_generate, with the given random bytes.
+func (s *Ciphersuite) nonce(random []byte, secret curve.Scalar) curve.Scalar {
+	return s.h3(append(append([]byte{}, random...), s.serializeScalar(secret)...))
+}
+
+// bindingFactors implements compute_binding_factors, where D and E are the hiding and binding commitments of the signers.
+func (s *Ciphersuite) bindingFactors(public curve.Point, signers []party.ID, D, E map[party.ID]curve.Point, message []byte) (map[party.ID]curve.Scalar, error) {
+	// the commitment list is sorted by identifier
+	ids := make([]party.ID, len(signers))
+	copy(ids, signers)
+	identifiers := make(map[party.ID][]byte, len(ids))
+	for _, id := range ids {
+		identifiers[id], _ = id.Scalar(s.group).MarshalBinary()
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(identifiers[ids[i]], identifiers[ids[j]]) < 0 })
+
+	var commitments []byte
+	for _, id := range ids {
+		hiding, err := D[id].MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		binding, err := E[id].MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		commitments = append(commitments, s.serializeScalar(id.Scalar(s.group))...)
+		commitments = append(commitments, hiding...)
+		commitments = append(commitments, binding...)
+	}
+
+	publicBytes, err := public.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	prefix := append(append(publicBytes, s.h4(message)...), s.h5(commitments)...)
+	rho := make(map[party.ID]curve.Scalar, len(ids))
+	for _, id := range ids {
+		input := append(append([]byte{}, prefix...), s.serializeScalar(id.Scalar(s.group))...)
+		rho[id] = s.h1(input)
+	}
+	return rho, nil
+}
+
+// challenge implements compute_challenge.
+func (s *Ciphersuite) challenge(R, public curve.Point, message []byte) (curve.Scalar, error) {
+	RBytes, err := R.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	publicBytes, err := public.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return s.h2(append(append(RBytes, publicBytes...), message...)), nil
+}
+
+// encodeSignature returns SerializeElement(R) ‖ SerializeScalar(z).
+func (s *Ciphersuite) encodeSignature(R curve.Point, z curve.Scalar) ([]byte, error) {
+	RBytes, err := R.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(RBytes, s.serializeScalar(z)...), nil
+}
+
+func (s *Ciphersuite) decodeSignature(signature []byte) (curve.Point, curve.Scalar, error) {
+	scalarLen := len(s.serializeScalar(s.group.NewScalar()))
+	if len(signature) <= scalarLen {
+		return nil, nil, errors.New("signature is too short")
+	}
+	R := s.group.NewPoint()
+	if err := R.UnmarshalBinary(signature[:len(signature)-scalarLen]); err != nil {
+		return nil, nil, fmt.Errorf("signature: %w", err)
+	}
+	zBytes := signature[len(signature)-scalarLen:]
+	if _, ok := s.group.(curve.Edwards25519); ok {
+		zBytes = reverse(zBytes)
+	}
+	z := s.group.NewScalar()
+	if err := z.UnmarshalBinary(zBytes); err != nil {
+		return nil, nil, fmt.Errorf("signature: %w", err)
+	}
+	return R, z, nil
+}
+
+func sha512Sum(prefix string, m []byte) []byte {
+	h := sha512.New()
+	_, _ = h.Write([]byte(prefix))
+	_, _ = h.Write(m)
+	return h.Sum(nil)
+}
+
+func sha256Sum(prefix string, m []byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write([]byte(prefix))
+	_, _ = h.Write(m)
+	return h.Sum(nil)
+}
+
+// reverse returns a reversed copy of data, to convert between little and big endian.
+func reverse(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i := range data {
+		out[len(data)-1-i] = data[i]
+	}
+	return out
+}
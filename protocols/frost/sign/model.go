@@ -0,0 +1,29 @@
+package sign
+
+import "github.com/taurusgroup/multi-party-sig/pkg/protocol"
+
+// Model returns the state machine of the signing protocol.
+func Model() *protocol.Model {
+	return &protocol.Model{
+		Name: protocolID,
+		Rounds: []protocol.RoundModel{
+			{Name: "round1", Number: 1, Next: []string{"round2"}},
+			{Name: "round2", Number: 2, Broadcast: protocol.ReliableBroadcast, Next: []string{"round3"}},
+			{Name: "round3", Number: 3, Broadcast: protocol.NormalBroadcast, Output: true, Abort: true},
+		},
+	}
+}
+
+// AggregatorModel returns the state machine of the signing protocol with a signature aggregator.
+func AggregatorModel() *protocol.Model {
+	return &protocol.Model{
+		Name: protocolIDAggregator,
+		Rounds: []protocol.RoundModel{
+			{Name: "aggregatorRound1", Number: 1, Next: []string{"aggregatorRound2"}},
+			{Name: "aggregatorRound2", Number: 2, Message: true, Next: []string{"aggregatorRound3"}},
+			{Name: "aggregatorRound3", Number: 3, Message: true, Next: []string{"aggregatorRound4"}},
+			{Name: "aggregatorRound4", Number: 4, Message: true, Next: []string{"aggregatorRound5"}, Abort: true},
+			{Name: "aggregatorRound5", Number: 5, Message: true, Output: true},
+		},
+	}
+}
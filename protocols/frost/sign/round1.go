@@ -1,8 +1,7 @@
 package sign
 
 import (
-	"crypto/rand"
-
+	"github.com/taurusgroup/multi-party-sig/internal/random"
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
@@ -27,6 +26,15 @@ type round1 struct {
 	// and we need to make sure to generate our challenge in the correct way. Naturally,
 	// we also return a taproot.Signature instead a generic signature.
 	taproot bool
+	// zilliqa indicates whether we generate Zilliqa Schnorr signatures, in which case M is the message itself.
+	//
+	// Zilliqa signatures use r = H(R, Y, m) as the challenge, and s = k - r•x as the response,
+	// so c = -r for the responses of the signers.
+	zilliqa bool
+	// suite is the RFC 9591 ciphersuite to follow, if any.
+	//
+	// If so, the nonces, binding values and challenge are computed as specified, and M is the message itself.
+	suite *Ciphersuite
 	// M is the hash of the message we're signing.
 	//
 	// This plays the same role as m in the Frost paper. One slight difference
@@ -52,10 +60,42 @@ const deriveHashKeyContext = "github.com/taurusgroup/multi-party-sig/frost 2021-
 
 // Finalize implements round.Round.
 func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	d_i, e_i, err := r.nonces()
+	if err != nil {
+		return r, err
+	}
+
+	D_i := d_i.ActOnBase()
+	E_i := e_i.ActOnBase()
+
+	// Broadcast the commitments
+	err = r.BroadcastMessage(out, &broadcast2{D_i: D_i, E_i: E_i})
+	if err != nil {
+		return r, err
+	}
+	return &round2{
+		round1: r,
+		d_i:    d_i,
+		e_i:    e_i,
+		D:      map[party.ID]curve.Point{r.SelfID(): D_i},
+		E:      map[party.ID]curve.Point{r.SelfID(): E_i},
+	}, nil
+}
+
+// nonces generates our pair of nonces (dᵢ, eᵢ).
+func (r *round1) nonces() (curve.Scalar, curve.Scalar, error) {
 	// We can think of this as roughly implementing Figure 2. The idea is
 	// to generate two nonces (dᵢ, eᵢ) in Z/(q)ˣ, then two commitments
 	// Dᵢ = dᵢ * G, Eᵢ = eᵢ * G, and then broadcast them.
 
+	if r.suite != nil {
+		randomness := make([]byte, 64)
+		if _, err := random.Read(randomness); err != nil {
+			return nil, nil, err
+		}
+		return r.suite.nonce(randomness[:32], r.s_i), r.suite.nonce(randomness[32:], r.s_i), nil
+	}
+
 	// We use a hedged deterministic process, instead of simply sampling (d_i, e_i):
 	//
 	//   a = random()
@@ -66,7 +106,7 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// and fault attacks against the hash function, because of the randomness.
 	s_iBytes, err := r.s_i.MarshalBinary()
 	if err != nil {
-		return r, err
+		return nil, nil, err
 	}
 
 	hashKey := make([]byte, 32)
@@ -75,28 +115,13 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	_, _ = nonceHasher.Write(r.Hash().Sum())
 	_, _ = nonceHasher.Write(r.M)
 	a := make([]byte, 32)
-	_, _ = rand.Read(a)
+	_, _ = random.Read(a)
 	_, _ = nonceHasher.Write(a)
 	nonceDigest := nonceHasher.Digest()
 
 	d_i := sample.ScalarUnit(nonceDigest, r.Group())
 	e_i := sample.ScalarUnit(nonceDigest, r.Group())
-
-	D_i := d_i.ActOnBase()
-	E_i := e_i.ActOnBase()
-
-	// Broadcast the commitments
-	err = r.BroadcastMessage(out, &broadcast2{D_i: D_i, E_i: E_i})
-	if err != nil {
-		return r, err
-	}
-	return &round2{
-		round1: r,
-		d_i:    d_i,
-		e_i:    e_i,
-		D:      map[party.ID]curve.Point{r.SelfID(): D_i},
-		E:      map[party.ID]curve.Point{r.SelfID(): E_i},
-	}, nil
+	return d_i, e_i, nil
 }
 
 // MessageContent implements round.Round.
@@ -7,10 +7,10 @@ import (
 	"github.com/taurusgroup/multi-party-sig/internal/round"
 	"github.com/taurusgroup/multi-party-sig/pkg/hash"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
-	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"github.com/taurusgroup/multi-party-sig/pkg/zilliqa"
 )
 
 // This round roughly corresponds with steps 3-6 of Figure 3 in the Frost paper:
@@ -82,6 +82,57 @@ func (round2) StoreMessage(round.Message) error { return nil }
 // Finalize implements round.Round.
 func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// This essentially follows parts of Figure 3.
+	com, err := r.commit(r.D, r.E)
+	if err != nil {
+		return r, err
+	}
+
+	// Lambdas[i] = λᵢ
+	Lambdas := lagrangeCache.Lagrange(r.Group(), r.PartyIDs())
+	z_i := r.response(com, Lambdas[r.SelfID()], r.d_i, r.e_i)
+
+	// 6. "Each Pᵢ securely deletes ((dᵢ, Dᵢ), (eᵢ, Eᵢ)) from their local storage,
+	// and returns zᵢ to SA."
+	//
+	// Since we don't have a signing authority, we instead broadcast zᵢ.
+
+	// TODO: Securely delete the nonces.
+
+	// Broadcast our response
+	err = r.BroadcastMessage(out, &broadcast3{Z_i: z_i})
+	if err != nil {
+		return r, err
+	}
+
+	return &round3{
+		round2:  r,
+		R:       com.R,
+		RShares: com.RShares,
+		c:       com.c,
+		z:       map[party.ID]curve.Scalar{r.SelfID(): z_i},
+		Lambda:  Lambdas,
+	}, nil
+}
+
+// commitment is the group commitment computed from the nonce commitments of all signers.
+type commitment struct {
+	// rho[l] = ρₗ is the binding value of party l.
+	rho map[party.ID]curve.Scalar
+	// R is the group commitment.
+	R curve.Point
+	// RShares[l] = Rₗ is the fraction party l contributes to R.
+	RShares map[party.ID]curve.Point
+	// c is the challenge.
+	c curve.Scalar
+	// negated is set if R had to be negated to have an even y coordinate, for Taproot.
+	negated bool
+}
+
+// commit computes the group commitment and the challenge from the nonce commitments D and E of all signers.
+func (r *round1) commit(D, E map[party.ID]curve.Point) (*commitment, error) {
+	if r.suite != nil {
+		return r.commitCiphersuite(D, E)
+	}
 
 	// 4. "Each Pᵢ then computes the set of binding values ρₗ = H₁(l, m, B).
 	// Each Pᵢ then derives the group commitment R = ∑ₗ Dₗ + ρₗ * Eₗ and
@@ -98,7 +149,7 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	rhoPreHash := hash.New()
 	_ = rhoPreHash.WriteAny(r.M)
 	for _, l := range r.PartyIDs() {
-		_ = rhoPreHash.WriteAny(r.D[l], r.E[l])
+		_ = rhoPreHash.WriteAny(D[l], E[l])
 	}
 	for _, l := range r.PartyIDs() {
 		rhoHash := rhoPreHash.Clone()
@@ -109,11 +160,11 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	R := r.Group().NewPoint()
 	RShares := make(map[party.ID]curve.Point)
 	for _, l := range r.PartyIDs() {
-		RShares[l] = rho[l].Act(r.E[l])
-		RShares[l] = RShares[l].Add(r.D[l])
+		RShares[l] = rho[l].Act(E[l])
+		RShares[l] = RShares[l].Add(D[l])
 		R = R.Add(RShares[l])
 	}
-	var c curve.Scalar
+	com := &commitment{rho: rho, R: R, RShares: RShares}
 	if r.taproot {
 		// BIP-340 adjustment: We need R to have an even y coordinate. This means
 		// conditionally negating k = ∑ᵢ (dᵢ + (eᵢ ρᵢ)), which we can accomplish
@@ -121,8 +172,7 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 		// as well.
 		RSecp := R.(*curve.Secp256k1Point)
 		if !RSecp.HasEvenY() {
-			r.d_i.Negate()
-			r.e_i.Negate()
+			com.negated = true
 			for _, l := range r.PartyIDs() {
 				RShares[l] = RShares[l].Negate()
 			}
@@ -133,44 +183,56 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 		RBytes := RSecp.XBytes()
 		PBytes := r.Y.(*curve.Secp256k1Point).XBytes()
 		cHash := taproot.TaggedHash("BIP0340/challenge", RBytes, PBytes, r.M)
-		c = r.Group().NewScalar().SetNat(new(saferith.Nat).SetBytes(cHash))
+		com.c = r.Group().NewScalar().SetNat(new(saferith.Nat).SetBytes(cHash))
+	} else if r.zilliqa {
+		c, err := zilliqa.Challenge(R, r.Y, r.M)
+		if err != nil {
+			return nil, err
+		}
+		com.c = c.Negate()
 	} else {
 		cHash := hash.New()
 		_ = cHash.WriteAny(R, r.Y, r.M)
-		c = sample.Scalar(cHash.Digest(), r.Group())
+		com.c = sample.Scalar(cHash.Digest(), r.Group())
 	}
+	return com, nil
+}
 
-	// Lambdas[i] = λᵢ
-	Lambdas := polynomial.Lagrange(r.Group(), r.PartyIDs())
+// commitCiphersuite is commit, following the ciphersuite.
+func (r *round1) commitCiphersuite(D, E map[party.ID]curve.Point) (*commitment, error) {
+	rho, err := r.suite.bindingFactors(r.Y, r.PartyIDs(), D, E, r.M)
+	if err != nil {
+		return nil, err
+	}
+	R := r.Group().NewPoint()
+	RShares := make(map[party.ID]curve.Point)
+	for _, l := range r.PartyIDs() {
+		RShares[l] = rho[l].Act(E[l]).Add(D[l])
+		R = R.Add(RShares[l])
+	}
+	c, err := r.suite.challenge(R, r.Y, r.M)
+	if err != nil {
+		return nil, err
+	}
+	return &commitment{rho: rho, R: R, RShares: RShares, c: c}, nil
+}
+
+// response computes our share zᵢ of the signature, where lambda is our Lagrange coefficient.
+func (r *round1) response(com *commitment, lambda, d_i, e_i curve.Scalar) curve.Scalar {
+	d := r.Group().NewScalar().Set(d_i)
+	e := r.Group().NewScalar().Set(e_i)
+	if com.negated {
+		d.Negate()
+		e.Negate()
+	}
 	// 5. "Each Pᵢ computes their response using their long-lived secret share sᵢ
 	// by computing zᵢ = dᵢ + (eᵢ ρᵢ) + λᵢ sᵢ c, using S to determine
 	// the ith lagrange coefficient λᵢ"
-	z_i := r.Group().NewScalar().Set(Lambdas[r.SelfID()]).Mul(r.s_i).Mul(c)
-	z_i.Add(r.d_i)
-	ed := r.Group().NewScalar().Set(rho[r.SelfID()]).Mul(r.e_i)
+	z_i := r.Group().NewScalar().Set(lambda).Mul(r.s_i).Mul(com.c)
+	z_i.Add(d)
+	ed := r.Group().NewScalar().Set(com.rho[r.SelfID()]).Mul(e)
 	z_i.Add(ed)
-
-	// 6. "Each Pᵢ securely deletes ((dᵢ, Dᵢ), (eᵢ, Eᵢ)) from their local storage,
-	// and returns zᵢ to SA."
-	//
-	// Since we don't have a signing authority, we instead broadcast zᵢ.
-
-	// TODO: Securely delete the nonces.
-
-	// Broadcast our response
-	err := r.BroadcastMessage(out, &broadcast3{Z_i: z_i})
-	if err != nil {
-		return r, err
-	}
-
-	return &round3{
-		round2:  r,
-		R:       R,
-		RShares: RShares,
-		c:       c,
-		z:       map[party.ID]curve.Scalar{r.SelfID(): z_i},
-		Lambda:  Lambdas,
-	}, nil
+	return z_i
 }
 
 // MessageContent implements round.Round.
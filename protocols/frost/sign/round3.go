@@ -7,6 +7,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"github.com/taurusgroup/multi-party-sig/pkg/zilliqa"
 )
 
 // This corresponds with step 7 of Figure 3 in the Frost paper:
@@ -65,12 +66,8 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	// Note that step 7.a is an artifact of having a signing authority. In our case,
 	// we've already computed everything that step computes.
 
-	expected := r.c.Act(r.Lambda[from].Act(r.YShares[from])).Add(r.RShares[from])
-
-	actual := body.Z_i.ActOnBase()
-
-	if !actual.Equal(expected) {
-		return fmt.Errorf("failed to verify response from %v", from)
+	if err := r.verifyResponse(r.RShares[from], r.c, r.Lambda[from], from, body.Z_i); err != nil {
+		return err
 	}
 
 	r.z[from] = body.Z_i
@@ -94,35 +91,81 @@ func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
 		z.Add(z_l)
 	}
 
+	sig, err := r.signature(r.R, z)
+	if err != nil {
+		return r.AbortRound(err), nil
+	}
+	return r.ResultRound(sig), nil
+}
+
+// verifyResponse checks the response z of party from, given its share R_from of the group commitment.
+func (r *round1) verifyResponse(R_from curve.Point, c, lambda curve.Scalar, from party.ID, z curve.Scalar) error {
+	expected := c.Act(lambda.Act(r.YShares[from])).Add(R_from)
+
+	actual := z.ActOnBase()
+
+	if !actual.Equal(expected) {
+		return fmt.Errorf("failed to verify response from %v", from)
+	}
+	return nil
+}
+
+// signature assembles the signature (R, z), and checks that it is valid.
+func (r *round1) signature(R curve.Point, z curve.Scalar) (interface{}, error) {
 	// The format of our signature depends on using taproot, naturally
+	if r.suite != nil {
+		sig, err := r.suite.encodeSignature(R, z)
+		if err != nil {
+			return nil, err
+		}
+		if !r.suite.Verify(r.Y, r.M, sig) {
+			return nil, fmt.Errorf("generated signature failed to verify")
+		}
+		return sig, nil
+	}
 	if r.taproot {
 		sig := taproot.Signature(make([]byte, 0, taproot.SignatureLen))
-		sig = append(sig, r.R.(*curve.Secp256k1Point).XBytes()...)
+		sig = append(sig, R.(*curve.Secp256k1Point).XBytes()...)
 		zBytes, err := z.MarshalBinary()
 		if err != nil {
-			return r, err
+			return nil, err
 		}
 		sig = append(sig, zBytes[:]...)
 
 		taprootPub := taproot.PublicKey(r.Y.(*curve.Secp256k1Point).XBytes())
 
 		if !taprootPub.Verify(sig, r.M) {
-			return r.AbortRound(fmt.Errorf("generated signature failed to verify")), nil
+			return nil, fmt.Errorf("generated signature failed to verify")
 		}
-
-		return r.ResultRound(sig), nil
-	} else {
-		sig := Signature{
-			R: r.R,
-			z: z,
+		return sig, nil
+	}
+	if r.zilliqa {
+		c, err := zilliqa.Challenge(R, r.Y, r.M)
+		if err != nil {
+			return nil, err
 		}
-
-		if !sig.Verify(r.Y, r.M) {
-			return r.AbortRound(fmt.Errorf("generated signature failed to verify")), nil
+		sig, err := zilliqa.Encode(c, z)
+		if err != nil {
+			return nil, err
+		}
+		public, err := r.Y.MarshalBinary()
+		if err != nil {
+			return nil, err
 		}
+		if !zilliqa.PublicKey(public).Verify(sig, r.M) {
+			return nil, fmt.Errorf("generated signature failed to verify")
+		}
+		return sig, nil
+	}
+	sig := Signature{
+		R: R,
+		z: z,
+	}
 
-		return r.ResultRound(sig), nil
+	if !sig.Verify(r.Y, r.M) {
+		return nil, fmt.Errorf("generated signature failed to verify")
 	}
+	return sig, nil
 }
 
 // MessageContent implements round.Round.
@@ -1,9 +1,12 @@
 package sign
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/polynomial"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
 	"github.com/taurusgroup/multi-party-sig/protocols/frost/keygen"
@@ -13,10 +16,21 @@ const (
 	// Frost Sign with Threshold.
 	protocolID        = "frost/sign-threshold"
 	protocolIDTaproot = "frost/sign-threshold-taproot"
+	protocolIDZilliqa = "frost/sign-threshold-zilliqa"
 	// This protocol has 3 concrete rounds.
 	protocolRounds round.Number = 3
+
+	// Frost Sign with a signature aggregator.
+	protocolIDAggregator        = "frost/sign-aggregator"
+	protocolIDAggregatorTaproot = "frost/sign-aggregator-taproot"
+	// With an aggregator, the protocol has 5 concrete rounds.
+	protocolRoundsAggregator round.Number = 5
 )
 
+// lagrangeCache holds the Lagrange coefficients of the most recent sets of signers,
+// which are usually the same from one session to the next.
+var lagrangeCache = polynomial.NewLagrangeCache(16)
+
 func StartSignCommon(taproot bool, result *keygen.Config, signers []party.ID, messageHash []byte) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
 		info := round.Info{
@@ -46,3 +60,105 @@ func StartSignCommon(taproot bool, result *keygen.Config, signers []party.ID, me
 		}, nil
 	}
 }
+
+// StartSignAggregator is like StartSignCommon, but the commitments and responses are sent to the aggregator,
+// which computes the signature and sends it to the other signers.
+func StartSignAggregator(taproot bool, result *keygen.Config, signers []party.ID, aggregator party.ID, messageHash []byte) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if !party.NewIDSlice(signers).Contains(aggregator) {
+			return nil, fmt.Errorf("sign.StartSignAggregator: aggregator %s is not a signer", aggregator)
+		}
+		info := round.Info{
+			FinalRoundNumber: protocolRoundsAggregator,
+			SelfID:           result.ID,
+			PartyIDs:         signers,
+			Threshold:        result.Threshold,
+			Group:            result.PublicKey.Curve(),
+		}
+		if taproot {
+			info.ProtocolID = protocolIDAggregatorTaproot
+		} else {
+			info.ProtocolID = protocolIDAggregator
+		}
+
+		helper, err := round.NewSession(info, sessionID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("sign.StartSignAggregator: %w", err)
+		}
+		return &aggregatorRound1{
+			round1: &round1{
+				Helper:  helper,
+				taproot: taproot,
+				M:       messageHash,
+				Y:       result.PublicKey,
+				YShares: result.VerificationShares.Points,
+				s_i:     result.PrivateShare,
+			},
+			aggregator: aggregator,
+		}, nil
+	}
+}
+
+// StartSignCiphersuite is like StartSignCommon, but follows suite, and signs message itself instead of its hash.
+//
+// The result is the encoding of the signature specified by suite.
+func StartSignCiphersuite(suite *Ciphersuite, result *keygen.Config, signers []party.ID, message []byte) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if result.PublicKey.Curve().Name() != suite.Group().Name() {
+			return nil, fmt.Errorf("sign.StartSignCiphersuite: %s requires a key on %s", suite.ID, suite.Group().Name())
+		}
+		info := round.Info{
+			ProtocolID:       protocolID + "-" + suite.ID,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           result.ID,
+			PartyIDs:         signers,
+			Threshold:        result.Threshold,
+			Group:            result.PublicKey.Curve(),
+		}
+
+		helper, err := round.NewSession(info, sessionID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("sign.StartSignCiphersuite: %w", err)
+		}
+		return &round1{
+			Helper:  helper,
+			suite:   suite,
+			M:       message,
+			Y:       result.PublicKey,
+			YShares: result.VerificationShares.Points,
+			s_i:     result.PrivateShare,
+		}, nil
+	}
+}
+
+// StartSignZilliqa is like StartSignCommon, but produces a Zilliqa Schnorr signature of message itself, instead of its hash.
+//
+// The result is a zilliqa.Signature.
+func StartSignZilliqa(result *keygen.Config, signers []party.ID, message []byte) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if _, ok := result.PublicKey.(*curve.Secp256k1Point); !ok {
+			return nil, errors.New("sign.StartSignZilliqa: Zilliqa requires a key on secp256k1")
+		}
+		info := round.Info{
+			ProtocolID:       protocolIDZilliqa,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           result.ID,
+			PartyIDs:         signers,
+			Threshold:        result.Threshold,
+			Group:            result.PublicKey.Curve(),
+		}
+
+		helper, err := round.NewSession(info, sessionID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("sign.StartSignZilliqa: %w", err)
+		}
+		return &round1{
+			Helper:  helper,
+			zilliqa: true,
+			M:       message,
+			Y:       result.PublicKey,
+			YShares: result.VerificationShares.Points,
+			s_i:     result.PrivateShare,
+		}, nil
+	}
+}
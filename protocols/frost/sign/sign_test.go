@@ -1,6 +1,7 @@
 package sign
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/sha256"
 	"testing"
@@ -15,6 +16,7 @@ import (
 	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
 	"github.com/taurusgroup/multi-party-sig/pkg/party"
 	"github.com/taurusgroup/multi-party-sig/pkg/taproot"
+	"github.com/taurusgroup/multi-party-sig/pkg/zilliqa"
 	"github.com/taurusgroup/multi-party-sig/protocols/frost/keygen"
 )
 
@@ -54,6 +56,7 @@ func TestSign(t *testing.T) {
 	}
 
 	var newPublicKey curve.Point
+	tracer := test.NewTracer()
 	rounds := make([]round.Session, 0, N)
 	for _, id := range partyIDs {
 		result := &keygen.Config{
@@ -70,7 +73,7 @@ func TestSign(t *testing.T) {
 		}
 		r, err := StartSignCommon(false, result, partyIDs, steak)(nil)
 		require.NoError(t, err, "round creation should not result in an error")
-		rounds = append(rounds, r)
+		rounds = append(rounds, tracer.Session(r))
 	}
 
 	for {
@@ -80,10 +83,127 @@ func TestSign(t *testing.T) {
 			break
 		}
 	}
+	require.NoError(t, tracer.Check(Model()), "rounds should follow the model")
 
 	checkOutput(t, rounds, newPublicKey, steak)
 }
 
+func TestSignAggregator(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	N := 5
+	threshold := 2
+
+	partyIDs := test.PartyIDs(N)
+	aggregator := partyIDs[2]
+
+	secret := sample.Scalar(rand.Reader, group)
+	f := polynomial.NewPolynomial(group, threshold, secret)
+	publicKey := secret.ActOnBase()
+	steak := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+
+	privateShares := make(map[party.ID]curve.Scalar, N)
+	verificationShares := make(map[party.ID]curve.Point, N)
+	for _, id := range partyIDs {
+		privateShares[id] = f.Evaluate(id.Scalar(group))
+		verificationShares[id] = privateShares[id].ActOnBase()
+	}
+
+	tracer := test.NewTracer()
+	rounds := make([]round.Session, 0, N)
+	for _, id := range partyIDs {
+		result := &keygen.Config{
+			ID:                 id,
+			Threshold:          threshold,
+			PublicKey:          publicKey,
+			PrivateShare:       privateShares[id],
+			VerificationShares: party.NewPointMap(verificationShares),
+		}
+		r, err := StartSignAggregator(false, result, partyIDs, aggregator, steak)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, tracer.Session(r))
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	require.NoError(t, tracer.Check(AggregatorModel()), "rounds should follow the model")
+
+	checkOutput(t, rounds, publicKey, steak)
+
+	result := &keygen.Config{
+		ID:                 partyIDs[0],
+		Threshold:          threshold,
+		PublicKey:          publicKey,
+		PrivateShare:       privateShares[partyIDs[0]],
+		VerificationShares: party.NewPointMap(verificationShares),
+	}
+	_, err := StartSignAggregator(false, result, partyIDs[:3], partyIDs[4], steak)(nil)
+	assert.Error(t, err, "the aggregator must be a signer")
+}
+
+func TestSignCiphersuite(t *testing.T) {
+	for _, suite := range []*Ciphersuite{Ed25519SHA512, Secp256k1SHA256} {
+		t.Run(suite.ID, func(t *testing.T) {
+			group := suite.Group()
+			N := 4
+			threshold := 2
+			partyIDs := test.PartyIDs(N)
+
+			secret := sample.Scalar(rand.Reader, group)
+			f := polynomial.NewPolynomial(group, threshold, secret)
+			publicKey := secret.ActOnBase()
+			message := []byte("message")
+
+			privateShares := make(map[party.ID]curve.Scalar, N)
+			verificationShares := make(map[party.ID]curve.Point, N)
+			for _, id := range partyIDs {
+				privateShares[id] = f.Evaluate(id.Scalar(group))
+				verificationShares[id] = privateShares[id].ActOnBase()
+			}
+
+			signers := partyIDs[1:]
+			rounds := make([]round.Session, 0, len(signers))
+			for _, id := range signers {
+				result := &keygen.Config{
+					ID:                 id,
+					Threshold:          threshold,
+					PublicKey:          publicKey,
+					PrivateShare:       privateShares[id],
+					VerificationShares: party.NewPointMap(verificationShares),
+				}
+				r, err := StartSignCiphersuite(suite, result, signers, message)(nil)
+				require.NoError(t, err, "round creation should not result in an error")
+				rounds = append(rounds, r)
+			}
+
+			for {
+				err, done := test.Rounds(rounds, nil)
+				require.NoError(t, err, "failed to process round")
+				if done {
+					break
+				}
+			}
+
+			publicKeyBytes, err := publicKey.MarshalBinary()
+			require.NoError(t, err)
+			for _, r := range rounds {
+				require.IsType(t, &round.Output{}, r, "expected result round")
+				sig := r.(*round.Output).Result.([]byte)
+				assert.True(t, suite.Verify(publicKey, message, sig), "expected valid signature")
+				assert.False(t, suite.Verify(publicKey, []byte("other message"), sig))
+				if suite == Ed25519SHA512 {
+					assert.True(t, ed25519.Verify(publicKeyBytes, message, sig), "expected valid Ed25519 signature")
+				}
+			}
+		})
+	}
+}
+
 func checkOutputTaproot(t *testing.T, rounds []round.Session, public taproot.PublicKey, m []byte) {
 	for _, r := range rounds {
 		require.IsType(t, &round.Output{}, r, "expected result round")
@@ -166,3 +286,61 @@ func TestSignTaproot(t *testing.T) {
 
 	checkOutputTaproot(t, rounds, newPublicKey, steak)
 }
+
+func TestSignZilliqa(t *testing.T) {
+	group := curve.Secp256k1{}
+	N := 3
+	threshold := 1
+
+	partyIDs := test.PartyIDs(N)
+
+	secret := sample.Scalar(rand.Reader, group)
+	f := polynomial.NewPolynomial(group, threshold, secret)
+	publicKey := secret.ActOnBase()
+	// Zilliqa signs the serialized transaction, rather than its hash
+	transaction := []byte("serialized zilliqa transaction")
+
+	verificationShares := make(map[party.ID]curve.Point, N)
+	privateShares := make(map[party.ID]curve.Scalar, N)
+	for _, id := range partyIDs {
+		privateShares[id] = f.Evaluate(id.Scalar(group))
+		verificationShares[id] = privateShares[id].ActOnBase()
+	}
+
+	rounds := make([]round.Session, 0, N)
+	for _, id := range partyIDs {
+		result := &keygen.Config{
+			ID:                 id,
+			Threshold:          threshold,
+			PublicKey:          publicKey,
+			PrivateShare:       privateShares[id],
+			VerificationShares: party.NewPointMap(verificationShares),
+		}
+		r, err := StartSignZilliqa(result, partyIDs, transaction)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	publicBytes, err := publicKey.MarshalBinary()
+	require.NoError(t, err)
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		sig, ok := r.(*round.Output).Result.(zilliqa.Signature)
+		require.True(t, ok, "expected zilliqa signature result")
+		assert.Len(t, sig, zilliqa.SignatureLen)
+		assert.True(t, zilliqa.PublicKey(publicBytes).Verify(sig, transaction))
+		assert.False(t, zilliqa.PublicKey(publicBytes).Verify(sig, []byte("another transaction")))
+	}
+
+	ed25519Config := &keygen.Config{ID: partyIDs[0], PublicKey: curve.Edwards25519{}.NewPoint()}
+	_, err = StartSignZilliqa(ed25519Config, partyIDs, transaction)(nil)
+	assert.Error(t, err)
+}
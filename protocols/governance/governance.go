@@ -0,0 +1,188 @@
+// Package governance lets a committee approve changes to its operational configuration.
+//
+// A Change is a statement describing a new configuration version, such as a new policy,
+// a new address book for the parties, or a new refresh schedule.
+// Each Change commits to the digest of the previously approved one, so that the approved versions form a chain.
+//
+// A Change is approved either with a threshold signature under the committee's FROST key,
+// or by threshold + 1 members endorsing it with their individual authentication keys.
+// Approved changes are recorded in a Store, which checks that every new version extends the chain.
+package governance
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+// Change is a configuration change statement, to be approved by the committee.
+type Change struct {
+	// Version is the number of this configuration version, starting at 1.
+	Version uint64
+	// Previous is the digest of the previously approved Change, and is empty for the first version.
+	Previous []byte
+	// PolicyHash is the hash of the signing policy in effect from this version on.
+	PolicyHash []byte
+	// AddressBook maps each party to the network address it can be reached at.
+	AddressBook map[party.ID]string
+	// RefreshInterval is the time between two proactive refreshes of the key shares.
+	RefreshInterval time.Duration
+}
+
+// Digest returns the hash of the statement, which is what the committee signs.
+func (c *Change) Digest() []byte {
+	version := make([]byte, 8)
+	binary.BigEndian.PutUint64(version, c.Version)
+	interval := make([]byte, 8)
+	binary.BigEndian.PutUint64(interval, uint64(c.RefreshInterval))
+
+	h := hash.New(
+		hash.BytesWithDomain{TheDomain: "Governance Version", Bytes: version},
+		hash.BytesWithDomain{TheDomain: "Governance Previous", Bytes: append([]byte{}, c.Previous...)},
+		hash.BytesWithDomain{TheDomain: "Governance Policy", Bytes: append([]byte{}, c.PolicyHash...)},
+		hash.BytesWithDomain{TheDomain: "Governance Refresh Interval", Bytes: interval},
+	)
+	ids := make(party.IDSlice, 0, len(c.AddressBook))
+	for id := range c.AddressBook {
+		ids = append(ids, id)
+	}
+	sort.Sort(ids)
+	for _, id := range ids {
+		_ = h.WriteAny(id, hash.BytesWithDomain{TheDomain: "Governance Address", Bytes: []byte(c.AddressBook[id])})
+	}
+	return h.Sum()
+}
+
+// Next returns a copy of c, with the version incremented and chained to c.
+//
+// The caller can then modify the fields which should change.
+func (c *Change) Next() *Change {
+	addressBook := make(map[party.ID]string, len(c.AddressBook))
+	for id, address := range c.AddressBook {
+		addressBook[id] = address
+	}
+	return &Change{
+		Version:         c.Version + 1,
+		Previous:        c.Digest(),
+		PolicyHash:      append([]byte(nil), c.PolicyHash...),
+		AddressBook:     addressBook,
+		RefreshInterval: c.RefreshInterval,
+	}
+}
+
+// Sign initiates the protocol for the signers to approve change with the committee's threshold key.
+//
+// The result is a frost.Signature, which can be combined with the change using NewApproval.
+func Sign(config *frost.Config, signers []party.ID, change *Change) protocol.StartFunc {
+	return frost.Sign(config, signers, change.Digest())
+}
+
+// Endorsement is the approval of a Change by a single member, using their authentication key.
+type Endorsement struct {
+	// ID is the member endorsing the change.
+	ID party.ID
+	// Signature is the ed25519 signature of the member on the digest of the change.
+	Signature []byte
+}
+
+// Endorse produces the endorsement of change by id, using its authentication key.
+func Endorse(id party.ID, key ed25519.PrivateKey, change *Change) Endorsement {
+	return Endorsement{
+		ID:        id,
+		Signature: ed25519.Sign(key, change.Digest()),
+	}
+}
+
+// Approval is a Change along with the proof that the committee approved it.
+type Approval struct {
+	Change *Change
+	// Signature is the threshold signature of the committee, if the change was approved with the threshold key.
+	Signature *frost.Signature
+	// Endorsements are the signatures of individual members, if the change was approved with authentication keys.
+	Endorsements []Endorsement
+}
+
+// clone returns a deep copy of a, or nil if a is nil.
+//
+// The Signature is copied by value, since its point can't be modified, and its scalar isn't exported.
+func (a *Approval) clone() *Approval {
+	if a == nil {
+		return nil
+	}
+	c := &Approval{}
+	if a.Change != nil {
+		change := *a.Change
+		change.Previous = append([]byte(nil), a.Change.Previous...)
+		change.PolicyHash = append([]byte(nil), a.Change.PolicyHash...)
+		if a.Change.AddressBook != nil {
+			change.AddressBook = make(map[party.ID]string, len(a.Change.AddressBook))
+			for id, address := range a.Change.AddressBook {
+				change.AddressBook[id] = address
+			}
+		}
+		c.Change = &change
+	}
+	if a.Signature != nil {
+		sig := *a.Signature
+		c.Signature = &sig
+	}
+	for _, e := range a.Endorsements {
+		c.Endorsements = append(c.Endorsements, Endorsement{ID: e.ID, Signature: append([]byte(nil), e.Signature...)})
+	}
+	return c
+}
+
+// NewApproval returns the Approval of change by the threshold signature sig.
+func NewApproval(change *Change, sig frost.Signature) *Approval {
+	return &Approval{Change: change, Signature: &sig}
+}
+
+// Committee describes the keys which can approve changes.
+type Committee struct {
+	// PublicKey is the threshold key of the committee.
+	PublicKey curve.Point
+	// AuthenticationKeys are the individual keys of the members, used for endorsements.
+	AuthenticationKeys map[party.ID]ed25519.PublicKey
+	// Threshold is such that threshold + 1 endorsements are needed to approve a change.
+	Threshold int
+}
+
+// Verify returns an error if a was not approved by the committee.
+func (c *Committee) Verify(a *Approval) error {
+	if a == nil || a.Change == nil {
+		return errors.New("governance: nil approval")
+	}
+	digest := a.Change.Digest()
+
+	if a.Signature != nil {
+		if c.PublicKey == nil || !a.Signature.Verify(c.PublicKey, digest) {
+			return errors.New("governance: invalid threshold signature")
+		}
+		return nil
+	}
+
+	endorsed := make(map[party.ID]bool, len(a.Endorsements))
+	for _, e := range a.Endorsements {
+		key, ok := c.AuthenticationKeys[e.ID]
+		if !ok || len(key) != ed25519.PublicKeySize {
+			return fmt.Errorf("governance: endorsement from unknown member %v", e.ID)
+		}
+		if !ed25519.Verify(key, digest, e.Signature) {
+			return fmt.Errorf("governance: invalid endorsement from %v", e.ID)
+		}
+		endorsed[e.ID] = true
+	}
+	if len(endorsed) < c.Threshold+1 {
+		return fmt.Errorf("governance: need %d endorsements, got %d", c.Threshold+1, len(endorsed))
+	}
+	return nil
+}
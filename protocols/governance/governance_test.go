@@ -0,0 +1,124 @@
+package governance
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+)
+
+func run(t *testing.T, ids []party.ID, start func(id party.ID) protocol.StartFunc) map[party.ID]interface{} {
+	rounds := make([]round.Session, 0, len(ids))
+	for _, id := range ids {
+		r, err := start(id)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	results := make(map[party.ID]interface{}, len(ids))
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		results[r.SelfID()] = r.(*round.Output).Result
+	}
+	return results
+}
+
+func TestGovernance(t *testing.T) {
+	N := 3
+	threshold := 1
+	ids := test.PartyIDs(N)
+
+	configs := run(t, ids, func(id party.ID) protocol.StartFunc {
+		return frost.Keygen(curve.Secp256k1{}, id, ids, threshold)
+	})
+	config := configs[ids[0]].(*frost.Config)
+
+	committee := &Committee{
+		PublicKey:          config.PublicKey,
+		AuthenticationKeys: map[party.ID]ed25519.PublicKey{},
+		Threshold:          threshold,
+	}
+	authKeys := map[party.ID]ed25519.PrivateKey{}
+	for _, id := range ids {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		committee.AuthenticationKeys[id] = public
+		authKeys[id] = private
+	}
+	store := NewStore(committee)
+
+	// version 1 is approved with the threshold key
+	v1 := &Change{
+		Version:         1,
+		PolicyHash:      []byte("policy"),
+		AddressBook:     map[party.ID]string{"a": "10.0.0.1:4000", "b": "10.0.0.2:4000", "c": "10.0.0.3:4000"},
+		RefreshInterval: 24 * time.Hour,
+	}
+	signers := ids[:threshold+1]
+	sigs := run(t, signers, func(id party.ID) protocol.StartFunc {
+		return Sign(configs[id].(*frost.Config), signers, v1)
+	})
+	require.NoError(t, store.Append(NewApproval(v1, sigs[signers[0]].(frost.Signature))))
+
+	// version 2 is approved with the authentication keys
+	v2 := v1.Next()
+	v2.RefreshInterval = time.Hour
+	approval := &Approval{Change: v2}
+	approval.Endorsements = append(approval.Endorsements, Endorse(ids[0], authKeys[ids[0]], v2))
+	assert.Error(t, store.Append(approval), "not enough endorsements")
+	approval.Endorsements = append(approval.Endorsements, Endorse(ids[1], authKeys[ids[1]], v2))
+	require.NoError(t, store.Append(approval))
+
+	// a signature on a modified statement is rejected
+	v3 := v2.Next()
+	forged := &Approval{Change: v3, Endorsements: approval.Endorsements}
+	assert.Error(t, store.Append(forged))
+
+	// a valid approval which does not extend the latest version is rejected
+	fork := v1.Next()
+	fork.PolicyHash = []byte("other policy")
+	forkApproval := &Approval{Change: fork, Endorsements: []Endorsement{
+		Endorse(ids[1], authKeys[ids[1]], fork),
+		Endorse(ids[2], authKeys[ids[2]], fork),
+	}}
+	assert.Error(t, store.Append(forkApproval))
+
+	latest := store.Latest()
+	require.NotNil(t, latest)
+	assert.Equal(t, uint64(2), latest.Change.Version)
+	assert.Len(t, store.History(), 2)
+	got, ok := store.Version(1)
+	require.True(t, ok)
+	assert.Equal(t, v1.Digest(), got.Change.Digest())
+
+	// the store keeps its own copies, so modifying approvals afterwards doesn't change the history
+	v2.AddressBook["a"] = "10.0.0.9:4000"
+	approval.Endorsements[0].ID = ids[2]
+	latest.Change.PolicyHash[0] ^= 1
+	latest = store.Latest()
+	assert.Equal(t, "10.0.0.1:4000", latest.Change.AddressBook["a"])
+	assert.Equal(t, ids[0], latest.Endorsements[0].ID)
+	assert.Equal(t, []byte("policy"), latest.Change.PolicyHash)
+
+	// a store rebuilt from the history verifies it again
+	rebuilt := NewStore(committee)
+	for _, a := range store.History() {
+		require.NoError(t, rebuilt.Append(a))
+	}
+	assert.Equal(t, store.Latest().Change.Digest(), rebuilt.Latest().Change.Digest())
+}
@@ -0,0 +1,96 @@
+package governance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Store records the configuration versions approved by a committee.
+//
+// Versions can only be appended, and each must be approved by the committee
+// and chained to the previous version, which makes the history tamper-evident.
+// The Store keeps copies of the approvals, so that callers can't modify the history after it was verified.
+//
+// A Store lives in memory, and has no persistence interface: every approval carries its own proof,
+// so the History can be saved to any storage, and the Store rebuilt by appending it again in order,
+// which verifies it anew. Modified storage can then only drop the latest versions, which is detected
+// by comparing Latest with the other members of the committee.
+type Store struct {
+	committee *Committee
+	approvals []*Approval
+	mtx       sync.Mutex
+}
+
+// NewStore creates an empty Store for the given committee.
+func NewStore(committee *Committee) *Store {
+	return &Store{committee: committee}
+}
+
+// Append records a new approved version.
+//
+// An error is returned if the approval is invalid, or does not directly follow the latest version.
+func (s *Store) Append(a *Approval) error {
+	a = a.clone()
+	if err := s.committee.Verify(a); err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if err := s.follows(a.Change); err != nil {
+		return err
+	}
+	s.approvals = append(s.approvals, a)
+	return nil
+}
+
+// follows checks that change directly extends the latest version in the store.
+func (s *Store) follows(change *Change) error {
+	if len(s.approvals) == 0 {
+		if change.Version != 1 || len(change.Previous) != 0 {
+			return errors.New("governance: first version must be 1, with no previous digest")
+		}
+		return nil
+	}
+	latest := s.approvals[len(s.approvals)-1].Change
+	if change.Version != latest.Version+1 {
+		return fmt.Errorf("governance: expected version %d, got %d", latest.Version+1, change.Version)
+	}
+	if !bytes.Equal(change.Previous, latest.Digest()) {
+		return fmt.Errorf("governance: version %d is not chained to version %d", change.Version, latest.Version)
+	}
+	return nil
+}
+
+// Latest returns the most recently approved version, or nil if the store is empty.
+func (s *Store) Latest() *Approval {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if len(s.approvals) == 0 {
+		return nil
+	}
+	return s.approvals[len(s.approvals)-1].clone()
+}
+
+// Version returns the approval of a given version, if it exists.
+func (s *Store) Version(version uint64) (*Approval, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if version == 0 || version > uint64(len(s.approvals)) {
+		return nil, false
+	}
+	return s.approvals[version-1].clone(), true
+}
+
+// History returns all approved versions, from the oldest to the latest.
+func (s *Store) History() []*Approval {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	history := make([]*Approval, 0, len(s.approvals))
+	for _, a := range s.approvals {
+		history = append(history, a.clone())
+	}
+	return history
+}
@@ -0,0 +1,122 @@
+package keygen
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// ConfigP1 holds the results of key generation for the first party.
+type ConfigP1 struct {
+	// SecretShare is a multiplicative share x₁ of the secret key.
+	SecretShare curve.Scalar
+	// Public is the shared public key x₁x₂•G.
+	Public curve.Point
+	// Paillier is the Paillier key under which the second party holds an encryption of SecretShare.
+	Paillier *paillier.SecretKey
+	// aborted is set once the second party was caught cheating during signing.
+	aborted atomic.Bool
+}
+
+// Group returns the elliptic curve group associate with this config.
+func (c *ConfigP1) Group() curve.Curve {
+	return c.Public.Curve()
+}
+
+// Abort marks this config as unusable, after the second party sent an incorrect ciphertext during signing.
+//
+// Whether the signature check succeeds can depend on SecretShare, so each failed attempt may leak
+// a bit of it to the second party. Section 3.3 of https://eprint.iacr.org/2017/552 therefore requires
+// the first party to never sign with this key again, and signing or refreshing an aborted config fails.
+//
+// The flag is part of the marshalled config, which should be stored again once it is set.
+func (c *ConfigP1) Abort() {
+	c.aborted.Store(true)
+}
+
+// Aborted returns true if Abort was called on this config.
+func (c *ConfigP1) Aborted() bool {
+	return c.aborted.Load()
+}
+
+// ConfigP2 holds the results of key generation for the second party.
+type ConfigP2 struct {
+	// SecretShare is a multiplicative share x₂ of the secret key.
+	SecretShare curve.Scalar
+	// Public is the shared public key x₁x₂•G.
+	Public curve.Point
+	// Paillier is the Paillier public key of the first party.
+	Paillier *paillier.PublicKey
+	// EncryptedShare = Enc(x₁) is the encryption of the first party's secret share.
+	EncryptedShare *paillier.Ciphertext
+}
+
+// Group returns the elliptic curve group associate with this config.
+func (c *ConfigP2) Group() curve.Curve {
+	return c.Public.Curve()
+}
+
+// StartKeygen starts the key generation protocol.
+//
+// This is documented further in the base lindell package.
+//
+// This corresponds to protocol 3.2 of https://eprint.iacr.org/2017/552, where the
+// zero-knowledge proof that Enc(x₁) encrypts the discrete logarithm of Q₁ is replaced by the
+// Π^{log*} proof of CMP, using Pedersen parameters generated by the second party.
+//
+// Both parties also contribute to a scalar ρ, and the shares are re-randomized
+// into x₁ρ and x₂ρ⁻¹. This makes refreshing the key work the same way as generating it.
+//
+// If the secret share and public point are not nil, a refresh is done instead.
+func StartKeygen(group curve.Curve, p1 bool, selfID, otherID party.ID, secretShare curve.Scalar, public curve.Point, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		info := round.Info{
+			ProtocolID:       "lindell/keygen",
+			FinalRoundNumber: 2,
+			SelfID:           selfID,
+			PartyIDs:         party.NewIDSlice([]party.ID{selfID, otherID}),
+			Threshold:        1,
+			Group:            group,
+		}
+
+		helper, err := round.NewSession(info, sessionID, pl)
+		if err != nil {
+			return nil, fmt.Errorf("keygen.StartKeygen: %w", err)
+		}
+
+		refresh := true
+		if secretShare == nil && public == nil {
+			secretShare = sample.ScalarUnit(random.Reader(), group)
+			refresh = false
+		}
+		if secretShare.IsZero() {
+			return nil, errors.New("keygen.StartKeygen: secret share is zero")
+		}
+
+		if p1 {
+			return &round1P1{
+				Helper:      helper,
+				refresh:     refresh,
+				secretShare: secretShare,
+				publicShare: secretShare.ActOnBase(),
+				public:      public,
+			}, nil
+		}
+		return &round1P2{
+			Helper:      helper,
+			refresh:     refresh,
+			secretShare: secretShare,
+			publicShare: secretShare.ActOnBase(),
+			public:      public,
+		}, nil
+	}
+}
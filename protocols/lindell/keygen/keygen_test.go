@@ -0,0 +1,125 @@
+package keygen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+)
+
+var testGroup = curve.Secp256k1{}
+
+// runRounds executes the rounds of both parties until neither can make progress,
+// passing each message to modify before it is delivered.
+func runRounds(sessions []round.Session, modify func(*round.Message)) ([]round.Session, error) {
+	inbox := make([][]*round.Message, len(sessions))
+	for progress := true; progress; {
+		progress = false
+		for i, r := range sessions {
+			switch r.(type) {
+			case *round.Output, *round.Abort:
+				continue
+			}
+			if r.MessageContent() != nil {
+				if len(inbox[i]) == 0 || inbox[i][0].Content.RoundNumber() != r.Number() {
+					continue
+				}
+				msg := *inbox[i][0]
+				inbox[i] = inbox[i][1:]
+				if err := r.VerifyMessage(msg); err != nil {
+					return sessions, err
+				}
+				if err := r.StoreMessage(msg); err != nil {
+					return sessions, err
+				}
+			}
+			out := make(chan *round.Message, 1)
+			next, err := r.Finalize(out)
+			close(out)
+			if err != nil {
+				return sessions, err
+			}
+			for msg := range out {
+				if modify != nil {
+					modify(msg)
+				}
+				inbox[1-i] = append(inbox[1-i], msg)
+			}
+			sessions[i] = next
+			progress = true
+		}
+	}
+	return sessions, nil
+}
+
+func startKeygen(t *testing.T, partyIDs party.IDSlice, pl *pool.Pool) []round.Session {
+	r1, err := StartKeygen(testGroup, true, partyIDs[0], partyIDs[1], nil, nil, pl)([]byte("session"))
+	require.NoError(t, err)
+	r2, err := StartKeygen(testGroup, false, partyIDs[1], partyIDs[0], nil, nil, pl)([]byte("session"))
+	require.NoError(t, err)
+	return []round.Session{r1, r2}
+}
+
+func TestKeygen(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	partyIDs := test.PartyIDs(2)
+
+	rounds, err := runRounds(startKeygen(t, partyIDs, pl), nil)
+	require.NoError(t, err)
+	require.IsType(t, &round.Output{}, rounds[0])
+	require.IsType(t, &round.Output{}, rounds[1])
+	config1 := rounds[0].(*round.Output).Result.(*ConfigP1)
+	config2 := rounds[1].(*round.Output).Result.(*ConfigP2)
+
+	require.True(t, config1.Public.Equal(config2.Public))
+	secret := testGroup.NewScalar().Set(config1.SecretShare).Mul(config2.SecretShare)
+	require.True(t, secret.ActOnBase().Equal(config1.Public))
+	decrypted, err := config1.Paillier.Dec(config2.EncryptedShare)
+	require.NoError(t, err)
+	require.True(t, testGroup.NewScalar().SetNat(decrypted.Mod(testGroup.Order())).Equal(config1.SecretShare))
+	require.False(t, config1.Aborted())
+}
+
+func TestKeygenInvalidEncryptedShare(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	partyIDs := test.PartyIDs(2)
+
+	// the first party encrypts a different share than the one it proves knowledge of.
+	_, err := runRounds(startKeygen(t, partyIDs, pl), func(msg *round.Message) {
+		if body, ok := msg.Content.(*message2P1); ok {
+			body.EncryptedShare = body.EncryptedShare.Clone().Add(paillier.NewPublicKey(body.N), body.EncryptedShare)
+		}
+	})
+	require.ErrorContains(t, err, "log* proof")
+}
+
+func TestConfigP1Aborted(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	partyIDs := test.PartyIDs(2)
+
+	rounds, err := runRounds(startKeygen(t, partyIDs, pl), nil)
+	require.NoError(t, err)
+	config := rounds[0].(*round.Output).Result.(*ConfigP1)
+
+	data, err := config.MarshalBinary()
+	require.NoError(t, err)
+	decoded := &ConfigP1{SecretShare: testGroup.NewScalar(), Public: testGroup.NewPoint()}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.False(t, decoded.Aborted())
+
+	config.Abort()
+	require.True(t, config.Aborted())
+	data, err = config.MarshalBinary()
+	require.NoError(t, err)
+	decoded = &ConfigP1{SecretShare: testGroup.NewScalar(), Public: testGroup.NewPoint()}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.True(t, decoded.Aborted(), "the aborted flag should be marshalled")
+}
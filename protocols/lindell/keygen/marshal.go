@@ -0,0 +1,99 @@
+package keygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+)
+
+type configP1Marshal struct {
+	SecretShare curve.Scalar
+	Public      curve.Point
+	P, Q        *saferith.Nat
+	Aborted     bool
+}
+
+type configP2Marshal struct {
+	SecretShare    curve.Scalar
+	Public         curve.Point
+	N              *saferith.Modulus
+	EncryptedShare *paillier.Ciphertext
+}
+
+func (c *ConfigP1) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(&configP1Marshal{
+		SecretShare: c.SecretShare,
+		Public:      c.Public,
+		P:           c.Paillier.P(),
+		Q:           c.Paillier.Q(),
+		Aborted:     c.Aborted(),
+	})
+}
+
+func (c *ConfigP1) UnmarshalBinary(data []byte) error {
+	if c.SecretShare == nil || c.Public == nil {
+		return errors.New("config must be initialized using EmptyConfigP1")
+	}
+	cm := &configP1Marshal{SecretShare: c.SecretShare, Public: c.Public}
+	if err := cbor.Unmarshal(data, cm); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if cm.SecretShare.IsZero() || cm.Public.IsIdentity() {
+		return errors.New("config: secret share is zero or public key is identity")
+	}
+	if err := paillier.ValidatePrime(cm.P); err != nil {
+		return fmt.Errorf("config: prime P: %w", err)
+	}
+	if err := paillier.ValidatePrime(cm.Q); err != nil {
+		return fmt.Errorf("config: prime Q: %w", err)
+	}
+	*c = ConfigP1{
+		SecretShare: cm.SecretShare,
+		Public:      cm.Public,
+		Paillier:    paillier.NewSecretKeyFromPrimes(cm.P, cm.Q),
+	}
+	if cm.Aborted {
+		c.Abort()
+	}
+	return nil
+}
+
+func (c *ConfigP2) MarshalBinary() ([]byte, error) {
+	return cbor.Marshal(&configP2Marshal{
+		SecretShare:    c.SecretShare,
+		Public:         c.Public,
+		N:              c.Paillier.N(),
+		EncryptedShare: c.EncryptedShare,
+	})
+}
+
+func (c *ConfigP2) UnmarshalBinary(data []byte) error {
+	if c.SecretShare == nil || c.Public == nil {
+		return errors.New("config must be initialized using EmptyConfigP2")
+	}
+	cm := &configP2Marshal{SecretShare: c.SecretShare, Public: c.Public}
+	if err := cbor.Unmarshal(data, cm); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if cm.SecretShare.IsZero() || cm.Public.IsIdentity() {
+		return errors.New("config: secret share is zero or public key is identity")
+	}
+	if err := paillier.ValidateN(cm.N); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	paillierPublic := paillier.NewPublicKey(cm.N)
+	if cm.EncryptedShare == nil || !paillierPublic.ValidateCiphertexts(cm.EncryptedShare) {
+		return errors.New("config: invalid encrypted share")
+	}
+	*c = ConfigP2{
+		SecretShare:    cm.SecretShare,
+		Public:         cm.Public,
+		Paillier:       paillierPublic,
+		EncryptedShare: cm.EncryptedShare,
+	}
+	return nil
+}
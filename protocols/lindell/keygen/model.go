@@ -0,0 +1,25 @@
+package keygen
+
+import "github.com/taurusgroup/multi-party-sig/pkg/protocol"
+
+// ModelP1 returns the state machine of the keygen and refresh protocols, for P1.
+func ModelP1() *protocol.Model {
+	return &protocol.Model{
+		Name: "lindell/keygen/P1",
+		Rounds: []protocol.RoundModel{
+			{Name: "round1P1", Number: 1, Next: []string{"round2P1"}},
+			{Name: "round2P1", Number: 2, Message: true, Output: true},
+		},
+	}
+}
+
+// ModelP2 returns the state machine of the keygen and refresh protocols, for P2.
+func ModelP2() *protocol.Model {
+	return &protocol.Model{
+		Name: "lindell/keygen/P2",
+		Rounds: []protocol.RoundModel{
+			{Name: "round1P2", Number: 1, Message: true, Next: []string{"round2P2"}},
+			{Name: "round2P2", Number: 2, Message: true, Output: true},
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package keygen
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+)
+
+// message1P1 is the message sent by the first party at the start of the protocol.
+type message1P1 struct {
+	// Commit is the commitment to our public share and refresh scalar.
+	Commit hash.Commitment
+}
+
+func (message1P1) RoundNumber() round.Number { return 1 }
+
+// round1P1 corresponds to the first round from the first party's perspective.
+type round1P1 struct {
+	*round.Helper
+	// refresh indicates whether or not we should refresh
+	refresh bool
+	// public is an existing public key, if we're refreshing
+	public curve.Point
+	// Our secret share x₁
+	secretShare curve.Scalar
+	// Our secret share * G
+	publicShare curve.Point
+}
+
+// VerifyMessage implements round.Round.
+//
+// Since this is the start of the protocol, we aren't expecting to have received
+// any messages yet, so we do nothing.
+func (r *round1P1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1P1) StoreMessage(round.Message) error { return nil }
+
+func (r *round1P1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	proof := zksch.NewProof(r.Hash(), r.publicShare, r.secretShare, nil)
+	refreshScalar := sample.Scalar(random.Reader(), r.Group())
+	commit, decommit, err := r.Hash().Commit(r.publicShare, refreshScalar)
+	if err != nil {
+		return r, err
+	}
+	if err := r.SendMessage(out, &message1P1{commit}, ""); err != nil {
+		return r, err
+	}
+	return &round2P1{
+		round1P1:      r,
+		proof:         proof,
+		decommit:      decommit,
+		refreshScalar: refreshScalar,
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1P1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1P1) Number() round.Number { return 1 }
@@ -0,0 +1,104 @@
+package keygen
+
+import (
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	zkmod "github.com/taurusgroup/multi-party-sig/pkg/zk/mod"
+	zkprm "github.com/taurusgroup/multi-party-sig/pkg/zk/prm"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+)
+
+// message1P2 is the message sent by the second party in response to the first round.
+type message1P2 struct {
+	// PublicShare is our secret share times the group generator.
+	PublicShare curve.Point
+	// RefreshScalar is our contribution to re-randomizing the shares.
+	RefreshScalar curve.Scalar
+	// Proof is the proof of knowledge for the discrete logarithm of PublicShare.
+	Proof *zksch.Proof
+	// N, S, T are the Pedersen parameters the first party uses to prove the validity of its encrypted share.
+	N    *saferith.Modulus
+	S, T *saferith.Nat
+	// Mod proves that N is the product of two Blum primes.
+	Mod *zkmod.Proof
+	// Prm proves that S and T generate the same subgroup of Z/NZ.
+	Prm *zkprm.Proof
+}
+
+func (message1P2) RoundNumber() round.Number { return 2 }
+
+// round1P2 corresponds to the first round from the second party's perspective.
+type round1P2 struct {
+	*round.Helper
+	// refresh indicates whether or not we should refresh
+	refresh bool
+	// public is an existing public key, if we're refreshing
+	public curve.Point
+	// Our secret share x₂
+	secretShare curve.Scalar
+	// Our secret share * G
+	publicShare curve.Point
+	// commit is the commitment sent to us by the first party.
+	commit hash.Commitment
+}
+
+func (r *round1P2) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message1P1)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	return body.Commit.Validate()
+}
+
+func (r *round1P2) StoreMessage(msg round.Message) error {
+	r.commit = msg.Content.(*message1P1).Commit
+	return nil
+}
+
+func (r *round1P2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	proof := zksch.NewProof(r.Hash(), r.publicShare, r.secretShare, nil)
+	refreshScalar := sample.Scalar(random.Reader(), r.Group())
+
+	// These parameters are only used for the proofs of the first party, so they are discarded after keygen.
+	_, paillierSecret := paillier.KeyGen(r.Pool)
+	aux, lambda := paillierSecret.GeneratePedersen()
+	h := r.HashForID(r.SelfID())
+	mod := zkmod.NewProof(h.Clone(), zkmod.Private{
+		P:   paillierSecret.P(),
+		Q:   paillierSecret.Q(),
+		Phi: paillierSecret.Phi(),
+	}, zkmod.Public{N: aux.N()}, r.Pool)
+	prm := zkprm.NewProof(zkprm.Private{
+		Lambda: lambda,
+		Phi:    paillierSecret.Phi(),
+		P:      paillierSecret.P(),
+		Q:      paillierSecret.Q(),
+	}, h.Clone(), zkprm.Public{Aux: aux}, r.Pool)
+
+	if err := r.SendMessage(out, &message1P2{
+		PublicShare:   r.publicShare,
+		RefreshScalar: refreshScalar,
+		Proof:         proof,
+		N:             aux.N(),
+		S:             aux.S(),
+		T:             aux.T(),
+		Mod:           mod,
+		Prm:           prm,
+	}, ""); err != nil {
+		return r, err
+	}
+	return &round2P2{
+		round1P2:      r,
+		aux:           aux,
+		refreshScalar: refreshScalar,
+	}, nil
+}
+
+func (round1P2) MessageContent() round.Content { return &message1P1{} }
+
+func (round1P2) Number() round.Number { return 1 }
@@ -0,0 +1,170 @@
+package keygen
+
+import (
+	"errors"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/arith"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	zkfac "github.com/taurusgroup/multi-party-sig/pkg/zk/fac"
+	zklogstar "github.com/taurusgroup/multi-party-sig/pkg/zk/logstar"
+	zkmod "github.com/taurusgroup/multi-party-sig/pkg/zk/mod"
+	zkprm "github.com/taurusgroup/multi-party-sig/pkg/zk/prm"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+)
+
+// message2P1 is the final message of the first party.
+type message2P1 struct {
+	// Decommit reveals the values we committed to earlier.
+	Decommit hash.Decommitment
+	// PublicShare is our secret share times the group generator, before re-randomization.
+	PublicShare curve.Point
+	// RefreshScalar is our contribution to re-randomizing the shares.
+	RefreshScalar curve.Scalar
+	// Proof is a proof of knowledge of the discrete logarithm of PublicShare.
+	Proof *zksch.Proof
+	// N is our Paillier public key.
+	N *saferith.Modulus
+	// EncryptedShare = Enc(x₁ρ).
+	EncryptedShare *paillier.Ciphertext
+	// Mod proves that N is the product of two Blum primes.
+	Mod *zkmod.Proof
+	// Fac proves that the factors of N are large enough.
+	Fac *zkfac.Proof
+	// LogStar proves that EncryptedShare encrypts the discrete logarithm of ρ•PublicShare.
+	LogStar *zklogstar.Proof
+}
+
+func (message2P1) RoundNumber() round.Number { return 2 }
+
+// round2P1 is the second, and final, round from the first party's perspective.
+type round2P1 struct {
+	*round1P1
+	// proof is a proof of knowledge for the discrete logarithm of our public share.
+	proof *zksch.Proof
+	// decommit is the decommitment to our first commitment
+	decommit hash.Decommitment
+	// refreshScalar is our contribution to re-randomizing the shares
+	refreshScalar curve.Scalar
+	// otherPublicShare is x₂•G
+	otherPublicShare curve.Point
+	// otherRefreshScalar is the contribution of the second party to re-randomizing the shares
+	otherRefreshScalar curve.Scalar
+	// aux are the Pedersen parameters of the second party
+	aux *pedersen.Parameters
+}
+
+func (r *round2P1) VerifyMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*message1P2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.PublicShare == nil || body.RefreshScalar == nil || body.Proof == nil ||
+		body.N == nil || body.S == nil || body.T == nil || body.Mod == nil || body.Prm == nil {
+		return round.ErrNilFields
+	}
+	if body.PublicShare.IsIdentity() {
+		return errors.New("public share is identity")
+	}
+	if !body.Proof.Verify(r.Hash(), body.PublicShare, nil) {
+		return errors.New("invalid Schnorr proof")
+	}
+	if err := paillier.ValidateN(body.N); err != nil {
+		return err
+	}
+	if err := pedersen.ValidateParameters(body.N, body.S, body.T); err != nil {
+		return err
+	}
+	aux := pedersen.New(arith.ModulusFromN(body.N), body.S, body.T)
+	if !body.Mod.Verify(zkmod.Public{N: body.N}, r.HashForID(from), r.Pool) {
+		return errors.New("failed to validate mod proof")
+	}
+	if !body.Prm.Verify(zkprm.Public{Aux: aux}, r.HashForID(from), r.Pool) {
+		return errors.New("failed to validate prm proof")
+	}
+	return nil
+}
+
+func (r *round2P1) StoreMessage(msg round.Message) error {
+	body := msg.Content.(*message1P2)
+	r.otherPublicShare = body.PublicShare
+	r.otherRefreshScalar = body.RefreshScalar
+	r.aux = pedersen.New(arith.ModulusFromN(body.N), body.S, body.T)
+	return nil
+}
+
+func (r *round2P1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	group := r.Group()
+
+	// Q = x₁•Q₂
+	public := r.secretShare.Act(r.otherPublicShare)
+	if r.refresh && !public.Equal(r.public) {
+		return r, errors.New("refresh changed the public key")
+	}
+
+	// ρ = ρ₁ + ρ₂
+	rho := group.NewScalar().Set(r.refreshScalar).Add(r.otherRefreshScalar)
+	if rho.IsZero() {
+		return r, errors.New("refresh scalar is zero")
+	}
+	secretShare := group.NewScalar().Set(r.secretShare).Mul(rho)
+
+	paillierPublic, paillierSecret := paillier.KeyGen(r.Pool)
+	encryptedShare, nonce := paillierPublic.Enc(curve.MakeInt(secretShare))
+
+	h := r.HashForID(r.SelfID())
+	mod := zkmod.NewProof(h.Clone(), zkmod.Private{
+		P:   paillierSecret.P(),
+		Q:   paillierSecret.Q(),
+		Phi: paillierSecret.Phi(),
+	}, zkmod.Public{N: paillierPublic.N()}, r.Pool)
+	fac := zkfac.NewProof(zkfac.Private{P: paillierSecret.P(), Q: paillierSecret.Q()}, h.Clone(), zkfac.Public{
+		N:   paillierPublic.N(),
+		Aux: r.aux,
+	})
+	logStar := zklogstar.NewProof(group, h.Clone(), zklogstar.Public{
+		C:      encryptedShare,
+		X:      secretShare.ActOnBase(),
+		Prover: paillierPublic,
+		Aux:    r.aux,
+	}, zklogstar.Private{
+		X:   curve.MakeInt(secretShare),
+		Rho: nonce,
+	})
+
+	if err := r.SendMessage(out, &message2P1{
+		Decommit:       r.decommit,
+		PublicShare:    r.publicShare,
+		RefreshScalar:  r.refreshScalar,
+		Proof:          r.proof,
+		N:              paillierPublic.N(),
+		EncryptedShare: encryptedShare,
+		Mod:            mod,
+		Fac:            fac,
+		LogStar:        logStar,
+	}, ""); err != nil {
+		return r, err
+	}
+
+	return r.ResultRound(&ConfigP1{
+		SecretShare: secretShare,
+		Public:      public,
+		Paillier:    paillierSecret,
+	}), nil
+}
+
+func (r *round2P1) MessageContent() round.Content {
+	group := r.Group()
+	return &message1P2{
+		PublicShare:   group.NewPoint(),
+		RefreshScalar: group.NewScalar(),
+		Proof:         zksch.EmptyProof(group),
+	}
+}
+
+func (round2P1) Number() round.Number { return 2 }
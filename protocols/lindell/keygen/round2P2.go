@@ -0,0 +1,121 @@
+package keygen
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	"github.com/taurusgroup/multi-party-sig/pkg/pedersen"
+	zkfac "github.com/taurusgroup/multi-party-sig/pkg/zk/fac"
+	zklogstar "github.com/taurusgroup/multi-party-sig/pkg/zk/logstar"
+	zkmod "github.com/taurusgroup/multi-party-sig/pkg/zk/mod"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+)
+
+// round2P2 is the second, and final, round from the second party's perspective.
+type round2P2 struct {
+	*round1P2
+	// aux are our Pedersen parameters, used to verify the proofs of the first party
+	aux *pedersen.Parameters
+	// refreshScalar is our contribution to re-randomizing the shares
+	refreshScalar curve.Scalar
+	// otherPublicShare is x₁•G
+	otherPublicShare curve.Point
+	// rho = ρ₁ + ρ₂
+	rho            curve.Scalar
+	paillier       *paillier.PublicKey
+	encryptedShare *paillier.Ciphertext
+}
+
+func (r *round2P2) VerifyMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*message2P1)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.PublicShare == nil || body.RefreshScalar == nil || body.Proof == nil || body.N == nil ||
+		body.EncryptedShare == nil || body.Mod == nil || body.Fac == nil || body.LogStar == nil {
+		return round.ErrNilFields
+	}
+	if err := body.Decommit.Validate(); err != nil {
+		return err
+	}
+	if !r.Hash().Decommit(r.commit, body.Decommit, body.PublicShare, body.RefreshScalar) {
+		return errors.New("invalid commitment")
+	}
+	if body.PublicShare.IsIdentity() {
+		return errors.New("public share is identity")
+	}
+	if !body.Proof.Verify(r.Hash(), body.PublicShare, nil) {
+		return errors.New("invalid Schnorr proof")
+	}
+
+	if err := paillier.ValidateN(body.N); err != nil {
+		return err
+	}
+	paillierPublic := paillier.NewPublicKey(body.N)
+	if !paillierPublic.ValidateCiphertexts(body.EncryptedShare) {
+		return errors.New("invalid encrypted share")
+	}
+	if !body.Mod.Verify(zkmod.Public{N: body.N}, r.HashForID(from), r.Pool) {
+		return errors.New("failed to validate mod proof")
+	}
+	if !body.Fac.Verify(zkfac.Public{N: body.N, Aux: r.aux}, r.HashForID(from)) {
+		return errors.New("failed to validate fac proof")
+	}
+
+	rho := r.Group().NewScalar().Set(r.refreshScalar).Add(body.RefreshScalar)
+	if rho.IsZero() {
+		return errors.New("refresh scalar is zero")
+	}
+	if !body.LogStar.Verify(r.HashForID(from), zklogstar.Public{
+		C:      body.EncryptedShare,
+		X:      rho.Act(body.PublicShare),
+		Prover: paillierPublic,
+		Aux:    r.aux,
+	}) {
+		return errors.New("failed to validate log* proof")
+	}
+	return nil
+}
+
+func (r *round2P2) StoreMessage(msg round.Message) error {
+	body := msg.Content.(*message2P1)
+	r.otherPublicShare = body.PublicShare
+	r.rho = r.Group().NewScalar().Set(r.refreshScalar).Add(body.RefreshScalar)
+	r.paillier = paillier.NewPublicKey(body.N)
+	r.encryptedShare = body.EncryptedShare
+	return nil
+}
+
+func (r *round2P2) Finalize(chan<- *round.Message) (round.Session, error) {
+	// Q = x₂•Q₁
+	public := r.secretShare.Act(r.otherPublicShare)
+	if r.refresh && !public.Equal(r.public) {
+		return r, errors.New("refresh changed the public key")
+	}
+
+	// x₂ρ⁻¹, so that x₁ρ•x₂ρ⁻¹ = x₁x₂
+	rhoInv := r.Group().NewScalar().Set(r.rho).Invert()
+	secretShare := r.Group().NewScalar().Set(r.secretShare).Mul(rhoInv)
+
+	return r.ResultRound(&ConfigP2{
+		SecretShare:    secretShare,
+		Public:         public,
+		Paillier:       r.paillier,
+		EncryptedShare: r.encryptedShare,
+	}), nil
+}
+
+func (r *round2P2) MessageContent() round.Content {
+	group := r.Group()
+	return &message2P1{
+		PublicShare:   group.NewPoint(),
+		RefreshScalar: group.NewScalar(),
+		Proof:         zksch.EmptyProof(group),
+		LogStar:       zklogstar.Empty(group),
+	}
+}
+
+func (round2P2) Number() round.Number { return 2 }
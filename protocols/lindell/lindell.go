@@ -0,0 +1,102 @@
+// Package lindell implements the two-party ECDSA protocol of Lindell:
+// https://eprint.iacr.org/2017/552.
+//
+// The secret key is shared multiplicatively, and the second party holds a Paillier encryption
+// of the first party's share. Signing then only requires a single homomorphic evaluation,
+// which makes it compatible with existing deployments of this scheme.
+//
+// For a two-party protocol without Paillier encryption, see protocols/doerner.
+package lindell
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/lindell/keygen"
+	"github.com/taurusgroup/multi-party-sig/protocols/lindell/sign"
+)
+
+type (
+	ConfigP1 = keygen.ConfigP1
+	ConfigP2 = keygen.ConfigP2
+)
+
+// EmptyConfigP1 creates a ConfigP1 that's ready to be unmarshalled.
+//
+// Because ConfigP1 contains group dependent data, it needs to be initialized
+// with a concrete group to be unmarshalled correctly.
+func EmptyConfigP1(group curve.Curve) *ConfigP1 {
+	return &ConfigP1{SecretShare: group.NewScalar(), Public: group.NewPoint()}
+}
+
+// EmptyConfigP2 creates a ConfigP2 that's ready to be unmarshalled.
+//
+// Because ConfigP2 contains group dependent data, it needs to be initialized
+// with a concrete group to be unmarshalled correctly.
+func EmptyConfigP2(group curve.Curve) *ConfigP2 {
+	return &ConfigP2{SecretShare: group.NewScalar(), Public: group.NewPoint()}
+}
+
+// Keygen initiates the Lindell key generation protocol.
+//
+// The goal of this protocol is to create a new key-pair, with the private portion
+// shared between two participants.
+//
+// One of the participants is marked as the first party, P1, and generates a Paillier key.
+// The return type of this protocol depends on the role: P1 gets a ConfigP1, and the other
+// participant gets a ConfigP2. P1 should be the leader of the TwoPartyHandler.
+//
+// A pool can be passed to this function, to parallelize certain operations and improve performance.
+func Keygen(group curve.Curve, p1 bool, selfID, otherID party.ID, pl *pool.Pool) protocol.StartFunc {
+	return keygen.StartKeygen(group, p1, selfID, otherID, nil, nil, pl)
+}
+
+// RefreshP1 initiates a key-refresh protocol, from the first party's perspective.
+//
+// Both shares are re-randomized, and a new Paillier key is generated, while preserving
+// the shared public key.
+//
+// A config that was aborted during signing can't be refreshed, since the second party knows how the new share is
+// derived from the old one.
+func RefreshP1(config *ConfigP1, selfID, otherID party.ID, pl *pool.Pool) protocol.StartFunc {
+	start := keygen.StartKeygen(config.Group(), true, selfID, otherID, config.SecretShare, config.Public, pl)
+	return func(sessionID []byte) (round.Session, error) {
+		if config.Aborted() {
+			return nil, errors.New("lindell.RefreshP1: config was aborted, after the second party cheated")
+		}
+		return start(sessionID)
+	}
+}
+
+// RefreshP2 initiates a key-refresh protocol, from the second party's perspective.
+//
+// See RefreshP1.
+func RefreshP2(config *ConfigP2, selfID, otherID party.ID, pl *pool.Pool) protocol.StartFunc {
+	return keygen.StartKeygen(config.Group(), false, selfID, otherID, config.SecretShare, config.Public, pl)
+}
+
+// SignP1 initiates the signing process, given a message hash.
+//
+// This function has another version, SignP2, which uses the config for the second party
+// instead.
+//
+// The result, in both cases, will be an ecdsa.Signature type.
+//
+// If the second party sends an incorrect ciphertext, the protocol aborts blaming it, and config is marked
+// with ConfigP1.Abort. The config must then be stored again, and never used for signing afterwards.
+//
+// A pool can be passed to this function, to parallelize certain operations and improve performance.
+func SignP1(config *ConfigP1, selfID, otherID party.ID, hash []byte, pl *pool.Pool) protocol.StartFunc {
+	return sign.StartSignP1(config, selfID, otherID, hash, pl)
+}
+
+// SignP2 is like SignP1, but using the second party's results from key generation.
+//
+// See SignP1 for more information.
+func SignP2(config *ConfigP2, selfID, otherID party.ID, hash []byte, pl *pool.Pool) protocol.StartFunc {
+	return sign.StartSignP2(config, selfID, otherID, hash, pl)
+}
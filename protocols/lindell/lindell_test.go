@@ -0,0 +1,162 @@
+package lindell
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/lindell/keygen"
+	"github.com/taurusgroup/multi-party-sig/protocols/lindell/sign"
+)
+
+func runHandler(wg *sync.WaitGroup, id party.ID, handler protocol.Handler, network *test.Network) {
+	defer wg.Done()
+	test.HandlerLoop(id, handler, network)
+}
+
+var testGroup = curve.Secp256k1{}
+
+// run executes a two party protocol, where the first party is the leader.
+// The rounds of each party are checked against model1 and model2.
+func run(partyIDs party.IDSlice, start1, start2 protocol.StartFunc, model1, model2 *protocol.Model) (interface{}, interface{}, error) {
+	tracer1, tracer2 := test.NewTracer(), test.NewTracer()
+	h1, err := protocol.NewTwoPartyHandler(tracer1.Start(start1), []byte("session"), true)
+	if err != nil {
+		return nil, nil, err
+	}
+	h2, err := protocol.NewTwoPartyHandler(tracer2.Start(start2), []byte("session"), false)
+	if err != nil {
+		return nil, nil, err
+	}
+	var wg sync.WaitGroup
+	network := test.NewNetwork(partyIDs)
+	wg.Add(2)
+	go runHandler(&wg, partyIDs[0], h1, network)
+	go runHandler(&wg, partyIDs[1], h2, network)
+	wg.Wait()
+
+	result1, err := h1.Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	result2, err := h2.Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = tracer1.Check(model1); err != nil {
+		return nil, nil, err
+	}
+	if err = tracer2.Check(model2); err != nil {
+		return nil, nil, err
+	}
+	return result1, result2, nil
+}
+
+func runKeygen(partyIDs party.IDSlice, pl *pool.Pool, config1 *ConfigP1, config2 *ConfigP2) (*ConfigP1, *ConfigP2, error) {
+	start1 := Keygen(testGroup, true, partyIDs[0], partyIDs[1], pl)
+	start2 := Keygen(testGroup, false, partyIDs[1], partyIDs[0], pl)
+	if config1 != nil && config2 != nil {
+		start1 = RefreshP1(config1, partyIDs[0], partyIDs[1], pl)
+		start2 = RefreshP2(config2, partyIDs[1], partyIDs[0], pl)
+	}
+	result1, result2, err := run(partyIDs, start1, start2, keygen.ModelP1(), keygen.ModelP2())
+	if err != nil {
+		return nil, nil, err
+	}
+	newConfig1, ok := result1.(*ConfigP1)
+	if !ok {
+		return nil, nil, errors.New("failed to cast result to *ConfigP1")
+	}
+	newConfig2, ok := result2.(*ConfigP2)
+	if !ok {
+		return nil, nil, errors.New("failed to cast result to *ConfigP2")
+	}
+	return newConfig1, newConfig2, nil
+}
+
+var testHash = []byte("test hash")
+
+func runSign(partyIDs party.IDSlice, pl *pool.Pool, config1 *ConfigP1, config2 *ConfigP2) (*ecdsa.Signature, *ecdsa.Signature, error) {
+	result1, result2, err := run(partyIDs,
+		SignP1(config1, partyIDs[0], partyIDs[1], testHash, pl),
+		SignP2(config2, partyIDs[1], partyIDs[0], testHash, pl),
+		sign.ModelP1(), sign.ModelP2())
+	if err != nil {
+		return nil, nil, err
+	}
+	sig1, ok := result1.(*ecdsa.Signature)
+	if !ok {
+		return nil, nil, errors.New("failed to cast result to Signature")
+	}
+	sig2, ok := result2.(*ecdsa.Signature)
+	if !ok {
+		return nil, nil, errors.New("failed to cast result to Signature")
+	}
+	return sig1, sig2, nil
+}
+
+func checkKeygenOutput(t *testing.T, config1 *ConfigP1, config2 *ConfigP2) {
+	require.True(t, config1.Public.Equal(config2.Public))
+	require.False(t, config1.Public.IsIdentity())
+	secret := testGroup.NewScalar().Set(config1.SecretShare).Mul(config2.SecretShare)
+	require.True(t, secret.ActOnBase().Equal(config1.Public))
+	decrypted, err := config1.Paillier.Dec(config2.EncryptedShare)
+	require.NoError(t, err)
+	require.True(t, testGroup.NewScalar().SetNat(decrypted.Mod(testGroup.Order())).Equal(config1.SecretShare))
+}
+
+func checkSign(t *testing.T, partyIDs party.IDSlice, pl *pool.Pool, config1 *ConfigP1, config2 *ConfigP2) {
+	sig1, sig2, err := runSign(partyIDs, pl, config1, config2)
+	require.NoError(t, err)
+	require.True(t, sig1.Verify(config1.Public, testHash))
+	require.True(t, sig2.Verify(config2.Public, testHash))
+	require.True(t, sig1.R.Equal(sig2.R))
+	require.True(t, sig1.S.Equal(sig2.S))
+}
+
+func TestSign(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	partyIDs := test.PartyIDs(2)
+
+	config1, config2, err := runKeygen(partyIDs, pl, nil, nil)
+	require.NoError(t, err)
+	checkKeygenOutput(t, config1, config2)
+	checkSign(t, partyIDs, pl, config1, config2)
+
+	newConfig1, newConfig2, err := runKeygen(partyIDs, pl, config1, config2)
+	require.NoError(t, err)
+	checkKeygenOutput(t, newConfig1, newConfig2)
+	require.True(t, newConfig1.Public.Equal(config1.Public))
+	require.False(t, newConfig1.SecretShare.Equal(config1.SecretShare))
+	checkSign(t, partyIDs, pl, newConfig1, newConfig2)
+}
+
+func TestMarshal(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	partyIDs := test.PartyIDs(2)
+
+	config1, config2, err := runKeygen(partyIDs, pl, nil, nil)
+	require.NoError(t, err)
+
+	data1, err := config1.MarshalBinary()
+	require.NoError(t, err)
+	decoded1 := EmptyConfigP1(testGroup)
+	require.NoError(t, decoded1.UnmarshalBinary(data1))
+
+	data2, err := config2.MarshalBinary()
+	require.NoError(t, err)
+	decoded2 := EmptyConfigP2(testGroup)
+	require.NoError(t, decoded2.UnmarshalBinary(data2))
+
+	checkKeygenOutput(t, decoded1, decoded2)
+	checkSign(t, partyIDs, pl, decoded1, decoded2)
+}
@@ -0,0 +1,27 @@
+package sign
+
+import "github.com/taurusgroup/multi-party-sig/pkg/protocol"
+
+// ModelP1 returns the state machine of the signing protocol, for P1.
+func ModelP1() *protocol.Model {
+	return &protocol.Model{
+		Name: "lindell/sign/P1",
+		Rounds: []protocol.RoundModel{
+			{Name: "round1P1", Number: 1, Next: []string{"round2P1"}},
+			{Name: "round2P1", Number: 2, Message: true, Next: []string{"round3P1"}},
+			{Name: "round3P1", Number: 3, Message: true, Output: true, Abort: true},
+		},
+	}
+}
+
+// ModelP2 returns the state machine of the signing protocol, for P2.
+func ModelP2() *protocol.Model {
+	return &protocol.Model{
+		Name: "lindell/sign/P2",
+		Rounds: []protocol.RoundModel{
+			{Name: "round1P2", Number: 1, Message: true, Next: []string{"round2P2"}},
+			{Name: "round2P2", Number: 2, Message: true, Next: []string{"round3P2"}},
+			{Name: "round3P2", Number: 3, Message: true, Output: true},
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package sign
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+	"github.com/taurusgroup/multi-party-sig/protocols/lindell/keygen"
+)
+
+// message1P1 is the first message sent by the first party.
+type message1P1 struct {
+	// Commit is the commitment to our nonce share R₁ = k₁•G.
+	Commit hash.Commitment
+}
+
+func (message1P1) RoundNumber() round.Number { return 1 }
+
+type round1P1 struct {
+	*round.Helper
+	hash   []byte
+	config *keygen.ConfigP1
+}
+
+func (r *round1P1) VerifyMessage(round.Message) error { return nil }
+
+func (r *round1P1) StoreMessage(round.Message) error { return nil }
+
+func (r *round1P1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	k1, R1 := sample.ScalarPointPair(random.Reader(), r.Group())
+	proof := zksch.NewProof(r.Hash(), R1, k1, nil)
+	commit, decommit, err := r.Hash().Commit(R1)
+	if err != nil {
+		return r, err
+	}
+	if err := r.SendMessage(out, &message1P1{commit}, ""); err != nil {
+		return r, err
+	}
+	return &round2P1{round1P1: r, k1: k1, R1: R1, proof: proof, decommit: decommit}, nil
+}
+
+func (round1P1) MessageContent() round.Content { return nil }
+
+func (round1P1) Number() round.Number { return 1 }
@@ -0,0 +1,55 @@
+package sign
+
+import (
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+	"github.com/taurusgroup/multi-party-sig/protocols/lindell/keygen"
+)
+
+// message1P2 is the first message sent by the second party.
+type message1P2 struct {
+	// R2 = k₂•G is our nonce share.
+	R2 curve.Point
+	// Proof is a proof of knowledge of the discrete logarithm of R2.
+	Proof *zksch.Proof
+}
+
+func (message1P2) RoundNumber() round.Number { return 2 }
+
+type round1P2 struct {
+	*round.Helper
+	hash   []byte
+	config *keygen.ConfigP2
+	// commit is the commitment to R₁ sent by the first party
+	commit hash.Commitment
+}
+
+func (r *round1P2) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message1P1)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	return body.Commit.Validate()
+}
+
+func (r *round1P2) StoreMessage(msg round.Message) error {
+	r.commit = msg.Content.(*message1P1).Commit
+	return nil
+}
+
+func (r *round1P2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	k2, R2 := sample.ScalarPointPair(random.Reader(), r.Group())
+	proof := zksch.NewProof(r.Hash(), R2, k2, nil)
+	if err := r.SendMessage(out, &message1P2{R2, proof}, ""); err != nil {
+		return r, err
+	}
+	return &round2P2{round1P2: r, k2: k2}, nil
+}
+
+func (round1P2) MessageContent() round.Content { return &message1P1{} }
+
+func (round1P2) Number() round.Number { return 1 }
@@ -0,0 +1,68 @@
+package sign
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/hash"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+)
+
+// message2P1 is the second message sent by the first party.
+type message2P1 struct {
+	// Decommit opens the commitment to R1.
+	Decommit hash.Decommitment
+	// R1 = k₁•G is our nonce share.
+	R1 curve.Point
+	// Proof is a proof of knowledge of the discrete logarithm of R1.
+	Proof *zksch.Proof
+}
+
+func (message2P1) RoundNumber() round.Number { return 2 }
+
+type round2P1 struct {
+	*round1P1
+	k1       curve.Scalar
+	R1       curve.Point
+	proof    *zksch.Proof
+	decommit hash.Decommitment
+	// R2 is the nonce share of the second party
+	R2 curve.Point
+}
+
+func (r *round2P1) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message1P2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.R2 == nil || body.Proof == nil {
+		return round.ErrNilFields
+	}
+	if body.R2.IsIdentity() {
+		return errors.New("nonce share is identity")
+	}
+	if !body.Proof.Verify(r.Hash(), body.R2, nil) {
+		return errors.New("invalid Schnorr proof")
+	}
+	return nil
+}
+
+func (r *round2P1) StoreMessage(msg round.Message) error {
+	r.R2 = msg.Content.(*message1P2).R2
+	return nil
+}
+
+func (r *round2P1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	if err := r.SendMessage(out, &message2P1{r.decommit, r.R1, r.proof}, ""); err != nil {
+		return r, err
+	}
+	return &round3P1{round2P1: r}, nil
+}
+
+func (r *round2P1) MessageContent() round.Content {
+	group := r.Group()
+	return &message1P2{R2: group.NewPoint(), Proof: zksch.EmptyProof(group)}
+}
+
+func (round2P1) Number() round.Number { return 2 }
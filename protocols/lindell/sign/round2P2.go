@@ -0,0 +1,91 @@
+package sign
+
+import (
+	"errors"
+
+	"github.com/cronokirby/saferith"
+	"github.com/taurusgroup/multi-party-sig/internal/random"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/sample"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+	zksch "github.com/taurusgroup/multi-party-sig/pkg/zk/sch"
+)
+
+// message2P2 is the second message sent by the second party.
+type message2P2 struct {
+	// C = Enc(ρq + k₂⁻¹m + k₂⁻¹rx₂x₁), computed homomorphically from Enc(x₁).
+	C *paillier.Ciphertext
+}
+
+func (message2P2) RoundNumber() round.Number { return 3 }
+
+type round2P2 struct {
+	*round1P2
+	k2 curve.Scalar
+	// R = k₂•R₁
+	R curve.Point
+}
+
+func (r *round2P2) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message2P1)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.R1 == nil || body.Proof == nil {
+		return round.ErrNilFields
+	}
+	if err := body.Decommit.Validate(); err != nil {
+		return err
+	}
+	if !r.Hash().Decommit(r.commit, body.Decommit, body.R1) {
+		return errors.New("invalid commitment")
+	}
+	if body.R1.IsIdentity() {
+		return errors.New("nonce share is identity")
+	}
+	if !body.Proof.Verify(r.Hash(), body.R1, nil) {
+		return errors.New("invalid Schnorr proof")
+	}
+	return nil
+}
+
+func (r *round2P2) StoreMessage(msg round.Message) error {
+	r.R = r.k2.Act(msg.Content.(*message2P1).R1)
+	return nil
+}
+
+func (r *round2P2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	group := r.Group()
+	x := r.R.XScalar()
+	if x == nil || x.IsZero() {
+		return r, errors.New("invalid nonce")
+	}
+	k2Inv := group.NewScalar().Set(r.k2).Invert()
+	m := curve.FromHash(group, r.hash)
+
+	// ρq + k₂⁻¹m, where ρ ← [0, q²), statistically hides everything but the value mod q.
+	q := group.Order().Nat()
+	qSquared := new(saferith.Nat).Mul(q, q, -1)
+	rho := sample.ModN(random.Reader(), saferith.ModulusFromNat(qSquared))
+	plaintext := new(saferith.Nat).Mul(rho, q, -1)
+	mPart := group.NewScalar().Set(k2Inv).Mul(m)
+	plaintext.Add(plaintext, curve.MakeInt(mPart).Abs(), -1)
+	C, _ := r.config.Paillier.Enc(new(saferith.Int).SetNat(plaintext))
+
+	// k₂⁻¹rx₂ ⊙ Enc(x₁)
+	v := group.NewScalar().Set(k2Inv).Mul(x).Mul(r.config.SecretShare)
+	C.Add(r.config.Paillier, r.config.EncryptedShare.Clone().Mul(r.config.Paillier, curve.MakeInt(v)))
+
+	if err := r.SendMessage(out, &message2P2{C}, ""); err != nil {
+		return r, err
+	}
+	return &round3P2{round2P2: r}, nil
+}
+
+func (r *round2P2) MessageContent() round.Content {
+	group := r.Group()
+	return &message2P1{R1: group.NewPoint(), Proof: zksch.EmptyProof(group)}
+}
+
+func (round2P2) Number() round.Number { return 2 }
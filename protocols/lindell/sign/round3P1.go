@@ -0,0 +1,70 @@
+package sign
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/paillier"
+)
+
+// message3P1 is the final message, containing the signature.
+type message3P1 struct {
+	Sig ecdsa.Signature
+}
+
+func (message3P1) RoundNumber() round.Number { return 3 }
+
+// round3P1 is the final round of the first party.
+type round3P1 struct {
+	*round2P1
+	C *paillier.Ciphertext
+}
+
+func (r *round3P1) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message2P2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.C == nil {
+		return round.ErrNilFields
+	}
+	if !r.config.Paillier.ValidateCiphertexts(body.C) {
+		return errors.New("invalid ciphertext")
+	}
+	return nil
+}
+
+func (r *round3P1) StoreMessage(msg round.Message) error {
+	r.C = msg.Content.(*message2P2).C
+	return nil
+}
+
+func (r *round3P1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	group := r.Group()
+	decrypted, err := r.config.Paillier.Dec(r.C)
+	if err != nil {
+		return r, err
+	}
+	// s = k₁⁻¹•Dec(C) = (k₁k₂)⁻¹(m + rx₁x₂)
+	k1Inv := group.NewScalar().Set(r.k1).Invert()
+	s := group.NewScalar().SetNat(decrypted.Mod(group.Order())).Mul(k1Inv)
+
+	// The second party is not trusted to have computed C correctly, so the signature must be checked.
+	// A failure may leak information about x₁, so the key must not be used again.
+	sig := ecdsa.Signature{R: r.k1.Act(r.R2), S: s}
+	if !sig.Verify(r.config.Public, r.hash) {
+		r.config.Abort()
+		return r.AbortRound(errors.New("failed to verify signature"), r.OtherPartyIDs()...), nil
+	}
+	if err := r.SendMessage(out, &message3P1{sig}, ""); err != nil {
+		return r, err
+	}
+	return r.ResultRound(&sig), nil
+}
+
+func (round3P1) MessageContent() round.Content {
+	return &message2P2{}
+}
+
+func (round3P1) Number() round.Number { return 3 }
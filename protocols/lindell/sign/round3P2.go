@@ -0,0 +1,46 @@
+package sign
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+)
+
+// round3P2 is the final round of the second party.
+type round3P2 struct {
+	*round2P2
+	Sig ecdsa.Signature
+}
+
+func (r *round3P2) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message3P1)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.Sig.R == nil || body.Sig.S == nil {
+		return round.ErrNilFields
+	}
+	if !body.Sig.R.Equal(r.R) {
+		return errors.New("signature uses a different nonce")
+	}
+	if !body.Sig.Verify(r.config.Public, r.hash) {
+		return errors.New("failed to verify signature")
+	}
+	return nil
+}
+
+func (r *round3P2) StoreMessage(msg round.Message) error {
+	r.Sig = msg.Content.(*message3P1).Sig
+	return nil
+}
+
+func (r *round3P2) Finalize(chan<- *round.Message) (round.Session, error) {
+	return r.ResultRound(&r.Sig), nil
+}
+
+func (r *round3P2) MessageContent() round.Content {
+	return &message3P1{Sig: ecdsa.EmptySignature(r.Group())}
+}
+
+func (round3P2) Number() round.Number { return 3 }
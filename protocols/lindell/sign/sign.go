@@ -0,0 +1,69 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/protocols/lindell/keygen"
+)
+
+// StartSignP1 starts the signature protocol for the first party.
+//
+// This corresponds to protocol 3.3 of https://eprint.iacr.org/2017/552.
+//
+// The first party decrypts the result of the second party's homomorphic computation,
+// and checks the signature before sending it to the second party.
+// If the check fails, the protocol aborts blaming the second party, and config is marked
+// as aborted, after which it can no longer be used to sign.
+func StartSignP1(config *keygen.ConfigP1, selfID, otherID party.ID, hash []byte, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if config.Aborted() {
+			return nil, errors.New("sign.StartSignP1: config was aborted, after the second party cheated")
+		}
+		info := round.Info{
+			ProtocolID:       "lindell/sign",
+			FinalRoundNumber: 3,
+			SelfID:           selfID,
+			PartyIDs:         party.NewIDSlice([]party.ID{selfID, otherID}),
+			Threshold:        1,
+			Group:            config.Group(),
+		}
+
+		helper, err := round.NewSession(info, sessionID, pl)
+		if err != nil {
+			return nil, fmt.Errorf("sign.StartSignP1: %w", err)
+		}
+
+		return &round1P1{Helper: helper, config: config, hash: hash}, nil
+	}
+}
+
+// StartSignP2 starts the signature protocol for the second party.
+//
+// This corresponds to protocol 3.3 of https://eprint.iacr.org/2017/552.
+//
+// The second party never learns anything beyond the signature, since the only value
+// it receives from the first party, besides the nonce share, is the signature itself.
+func StartSignP2(config *keygen.ConfigP2, selfID, otherID party.ID, hash []byte, pl *pool.Pool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		info := round.Info{
+			ProtocolID:       "lindell/sign",
+			FinalRoundNumber: 3,
+			SelfID:           selfID,
+			PartyIDs:         party.NewIDSlice([]party.ID{selfID, otherID}),
+			Threshold:        1,
+			Group:            config.Group(),
+		}
+
+		helper, err := round.NewSession(info, sessionID, pl)
+		if err != nil {
+			return nil, fmt.Errorf("sign.StartSignP2: %w", err)
+		}
+
+		return &round1P2{Helper: helper, config: config, hash: hash}, nil
+	}
+}
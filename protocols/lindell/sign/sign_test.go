@@ -0,0 +1,109 @@
+package sign
+
+import (
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/internal/round"
+	"github.com/taurusgroup/multi-party-sig/internal/test"
+	"github.com/taurusgroup/multi-party-sig/pkg/ecdsa"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/pool"
+	"github.com/taurusgroup/multi-party-sig/protocols/lindell/keygen"
+)
+
+var (
+	testGroup = curve.Secp256k1{}
+	testHash  = []byte("test hash")
+)
+
+// runRounds executes the rounds of both parties until neither can make progress,
+// passing each message to modify before it is delivered.
+func runRounds(sessions []round.Session, modify func(*round.Message)) ([]round.Session, error) {
+	inbox := make([][]*round.Message, len(sessions))
+	for progress := true; progress; {
+		progress = false
+		for i, r := range sessions {
+			switch r.(type) {
+			case *round.Output, *round.Abort:
+				continue
+			}
+			if r.MessageContent() != nil {
+				if len(inbox[i]) == 0 || inbox[i][0].Content.RoundNumber() != r.Number() {
+					continue
+				}
+				msg := *inbox[i][0]
+				inbox[i] = inbox[i][1:]
+				if err := r.VerifyMessage(msg); err != nil {
+					return sessions, err
+				}
+				if err := r.StoreMessage(msg); err != nil {
+					return sessions, err
+				}
+			}
+			out := make(chan *round.Message, 1)
+			next, err := r.Finalize(out)
+			close(out)
+			if err != nil {
+				return sessions, err
+			}
+			for msg := range out {
+				if modify != nil {
+					modify(msg)
+				}
+				inbox[1-i] = append(inbox[1-i], msg)
+			}
+			sessions[i] = next
+			progress = true
+		}
+	}
+	return sessions, nil
+}
+
+func generateConfigs(t *testing.T, partyIDs party.IDSlice, pl *pool.Pool) (*keygen.ConfigP1, *keygen.ConfigP2) {
+	r1, err := keygen.StartKeygen(testGroup, true, partyIDs[0], partyIDs[1], nil, nil, pl)([]byte("session"))
+	require.NoError(t, err)
+	r2, err := keygen.StartKeygen(testGroup, false, partyIDs[1], partyIDs[0], nil, nil, pl)([]byte("session"))
+	require.NoError(t, err)
+	rounds, err := runRounds([]round.Session{r1, r2}, nil)
+	require.NoError(t, err)
+	return rounds[0].(*round.Output).Result.(*keygen.ConfigP1), rounds[1].(*round.Output).Result.(*keygen.ConfigP2)
+}
+
+func startSign(t *testing.T, partyIDs party.IDSlice, pl *pool.Pool, config1 *keygen.ConfigP1, config2 *keygen.ConfigP2) []round.Session {
+	r1, err := StartSignP1(config1, partyIDs[0], partyIDs[1], testHash, pl)([]byte("session"))
+	require.NoError(t, err)
+	r2, err := StartSignP2(config2, partyIDs[1], partyIDs[0], testHash, pl)([]byte("session"))
+	require.NoError(t, err)
+	return []round.Session{r1, r2}
+}
+
+func TestSignInvalidCiphertext(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	partyIDs := test.PartyIDs(2)
+	config1, config2 := generateConfigs(t, partyIDs, pl)
+
+	rounds, err := runRounds(startSign(t, partyIDs, pl, config1, config2), nil)
+	require.NoError(t, err)
+	require.IsType(t, &round.Output{}, rounds[0])
+	require.True(t, rounds[0].(*round.Output).Result.(*ecdsa.Signature).Verify(config1.Public, testHash))
+	require.False(t, config1.Aborted())
+
+	// the second party adds 1 to the plaintext of C, which the first party can only notice by checking the signature.
+	one := new(saferith.Int).SetNat(new(saferith.Nat).SetUint64(1))
+	rounds, err = runRounds(startSign(t, partyIDs, pl, config1, config2), func(msg *round.Message) {
+		if body, ok := msg.Content.(*message2P2); ok {
+			body.C = body.C.Clone().AddPlaintext(config2.Paillier, one)
+		}
+	})
+	require.NoError(t, err)
+	require.IsType(t, &round.Abort{}, rounds[0])
+	require.Equal(t, []party.ID{partyIDs[1]}, rounds[0].(*round.Abort).Culprits)
+	require.True(t, config1.Aborted())
+
+	_, err = StartSignP1(config1, partyIDs[0], partyIDs[1], testHash, pl)([]byte("session"))
+	require.Error(t, err, "an aborted config should not be used to sign")
+}
@@ -0,0 +1,285 @@
+// Package libp2p runs the messages of a protocol.Handler over libp2p, so that parties can reach each other
+// without a central relay.
+//
+// Point-to-point messages are sent over streams to the peer of their recipient, and broadcasts
+// are published on a gossipsub topic, or sent over a stream to every peer if no topic is configured.
+// libp2p authenticates the peer at the other end of every connection, and gossipsub signs the messages
+// published on a topic, so a message is only accepted if its sender is the party mapped to that peer.
+//
+// This is a separate module, so that applications which don't use libp2p don't depend on it.
+// The result implements transport.Transport, and is run with transport.Run.
+package libp2p
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	p2pprotocol "github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+)
+
+// ProtocolID identifies the streams carrying protocol messages.
+const ProtocolID p2pprotocol.ID = "/multi-party-sig/message/1.0.0"
+
+// maxFrameSize bounds the size of a single message on a stream, so that a peer can't make us allocate arbitrarily.
+const maxFrameSize = 1 << 26
+
+// Config describes the libp2p endpoints of a party.
+type Config struct {
+	// Self is the party running the transport.
+	Self party.ID
+	// Host is the libp2p host of this party. It must be able to connect to the peers,
+	// for instance by having their addresses in its peerstore, or through a routing system.
+	Host host.Host
+	// Peers maps every other party to the ID of its libp2p peer.
+	Peers map[party.ID]peer.ID
+	// PubSub, if not nil, is used to publish broadcasts on Topic.
+	PubSub *pubsub.PubSub
+	// Topic is the gossipsub topic which all parties join, and which should be unique to this group of parties.
+	Topic string
+	// Compression is applied to outgoing messages. Incoming messages are accepted whether compressed or not.
+	Compression protocol.Compression
+}
+
+// Transport is a transport.Transport over libp2p.
+type Transport struct {
+	config Config
+	// parties maps the peers back to their party.
+	parties map[peer.ID]party.ID
+	topic   *pubsub.Topic
+	sub     *pubsub.Subscription
+	inbox   *queue
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	mtx     sync.Mutex
+	closed  bool
+	streams map[network.Stream]struct{}
+	wg      sync.WaitGroup
+}
+
+var _ transport.Transport = (*Transport)(nil)
+
+// New registers a handler for ProtocolID on config.Host, and joins config.Topic if config.PubSub is set.
+func New(config Config) (*Transport, error) {
+	if config.Host == nil {
+		return nil, errors.New("transport: libp2p: no host")
+	}
+	if _, ok := config.Peers[config.Self]; ok {
+		return nil, errors.New("transport: libp2p: peers must not contain self")
+	}
+	t := &Transport{
+		config:  config,
+		parties: make(map[peer.ID]party.ID, len(config.Peers)),
+		inbox:   newQueue(),
+		streams: map[network.Stream]struct{}{},
+	}
+	for id, p := range config.Peers {
+		if p == config.Host.ID() {
+			return nil, fmt.Errorf("transport: libp2p: party %s uses our own peer ID", id)
+		}
+		if _, ok := t.parties[p]; ok {
+			return nil, fmt.Errorf("transport: libp2p: peer %s is used by two parties", p)
+		}
+		t.parties[p] = id
+	}
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+
+	if config.PubSub != nil {
+		if config.Topic == "" {
+			return nil, errors.New("transport: libp2p: no topic")
+		}
+		var err error
+		if t.topic, err = config.PubSub.Join(config.Topic); err != nil {
+			return nil, fmt.Errorf("transport: libp2p: %w", err)
+		}
+		if t.sub, err = t.topic.Subscribe(); err != nil {
+			_ = t.topic.Close()
+			return nil, fmt.Errorf("transport: libp2p: %w", err)
+		}
+		t.wg.Add(1)
+		go t.readTopic()
+	}
+	config.Host.SetStreamHandler(ProtocolID, t.handleStream)
+	return t, nil
+}
+
+// Send implements transport.Transport.
+func (t *Transport) Send(msg *protocol.Message) error {
+	data, err := msg.MarshalBinaryCompressed(t.config.Compression)
+	if err != nil {
+		return fmt.Errorf("transport: libp2p: %w", err)
+	}
+	return t.send(msg.To, data)
+}
+
+// Broadcast implements transport.Transport.
+//
+// With a topic, it waits until gossipsub is connected to all the other parties on it, since a message is only
+// delivered to the subscribers it knows of when it is published. It then only returns an error once the
+// transport is closed.
+func (t *Transport) Broadcast(msg *protocol.Message) error {
+	data, err := msg.MarshalBinaryCompressed(t.config.Compression)
+	if err != nil {
+		return fmt.Errorf("transport: libp2p: %w", err)
+	}
+	if t.topic != nil {
+		ready := pubsub.WithReadiness(pubsub.MinTopicSize(len(t.config.Peers)))
+		if err = t.topic.Publish(t.ctx, data, ready); err != nil {
+			if t.ctx.Err() != nil {
+				return transport.ErrClosed
+			}
+			return fmt.Errorf("transport: libp2p: %w", err)
+		}
+		return nil
+	}
+	for id := range t.config.Peers {
+		if err = t.send(id, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Receive implements transport.Transport.
+func (t *Transport) Receive() <-chan *protocol.Message {
+	return t.inbox.out
+}
+
+// Close implements transport.Transport. It doesn't close the host, or the PubSub.
+func (t *Transport) Close() error {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.config.Host.RemoveStreamHandler(ProtocolID)
+	for s := range t.streams {
+		_ = s.Reset()
+	}
+	t.mtx.Unlock()
+
+	t.cancel()
+	var err error
+	if t.topic != nil {
+		t.sub.Cancel()
+		err = t.topic.Close()
+	}
+	t.wg.Wait()
+	t.inbox.close()
+	return err
+}
+
+// send opens a stream to the peer of id, and writes data on it.
+func (t *Transport) send(id party.ID, data []byte) error {
+	p, ok := t.config.Peers[id]
+	if !ok {
+		return fmt.Errorf("transport: libp2p: unknown party %s", id)
+	}
+	s, err := t.config.Host.NewStream(t.ctx, p, ProtocolID)
+	if err != nil {
+		if t.ctx.Err() != nil {
+			return transport.ErrClosed
+		}
+		return fmt.Errorf("transport: libp2p: sending to %s: %w", id, err)
+	}
+	if err = writeFrame(s, data); err != nil {
+		_ = s.Reset()
+		return fmt.Errorf("transport: libp2p: sending to %s: %w", id, err)
+	}
+	return s.Close()
+}
+
+// handleStream delivers the messages received on s, until it is closed.
+// Streams from unknown peers are reset, and messages which don't come from the party of the peer are dropped.
+func (t *Transport) handleStream(s network.Stream) {
+	from, ok := t.parties[s.Conn().RemotePeer()]
+	t.mtx.Lock()
+	if !ok || t.closed {
+		t.mtx.Unlock()
+		_ = s.Reset()
+		return
+	}
+	t.streams[s] = struct{}{}
+	t.wg.Add(1)
+	t.mtx.Unlock()
+	defer func() {
+		t.mtx.Lock()
+		delete(t.streams, s)
+		t.mtx.Unlock()
+		_ = s.Close()
+		t.wg.Done()
+	}()
+
+	for {
+		data, err := readFrame(s)
+		if err != nil {
+			return
+		}
+		t.deliver(from, data)
+	}
+}
+
+// readTopic delivers the broadcasts published by the other parties on the topic.
+func (t *Transport) readTopic() {
+	defer t.wg.Done()
+	for {
+		m, err := t.sub.Next(t.ctx)
+		if err != nil {
+			return
+		}
+		// the author of the message is authenticated by its signature, whichever peer relayed it.
+		from, ok := t.parties[m.GetFrom()]
+		if !ok {
+			continue
+		}
+		t.deliver(from, m.Data)
+	}
+}
+
+// deliver decodes data, and hands it to the handler if it was sent by from to us.
+func (t *Transport) deliver(from party.ID, data []byte) {
+	msg := &protocol.Message{}
+	if err := msg.UnmarshalBinary(data); err != nil || msg.From != from || !msg.IsFor(t.config.Self) {
+		return
+	}
+	t.inbox.push(msg)
+}
+
+func writeFrame(w io.Writer, data []byte) error {
+	if len(data) > maxFrameSize {
+		return fmt.Errorf("frame of %d bytes is too large", len(data))
+	}
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+	_, err := w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("frame of %d bytes is too large", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,130 @@
+package libp2p_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+	p2p "github.com/taurusgroup/multi-party-sig/transport/libp2p"
+)
+
+// newTransports connects a libp2p host for each of ids, and returns their transports.
+func newTransports(t *testing.T, ids party.IDSlice, gossip bool) map[party.ID]*p2p.Transport {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	hosts := make(map[party.ID]host.Host, len(ids))
+	peers := make(map[party.ID]peer.ID, len(ids))
+	for _, id := range ids {
+		h, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = h.Close() })
+		hosts[id], peers[id] = h, h.ID()
+	}
+	for _, id := range ids {
+		for _, other := range ids {
+			if other != id {
+				require.NoError(t, hosts[id].Connect(ctx, peer.AddrInfo{ID: peers[other], Addrs: hosts[other].Addrs()}))
+			}
+		}
+	}
+
+	transports := make(map[party.ID]*p2p.Transport, len(ids))
+	for _, id := range ids {
+		config := p2p.Config{Self: id, Host: hosts[id], Peers: map[party.ID]peer.ID{}}
+		for _, other := range ids {
+			if other != id {
+				config.Peers[other] = peers[other]
+			}
+		}
+		if gossip {
+			ps, err := pubsub.NewGossipSub(context.Background(), hosts[id])
+			require.NoError(t, err)
+			config.PubSub, config.Topic = ps, "session"
+		}
+		tr, err := p2p.New(config)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = tr.Close() })
+		transports[id] = tr
+	}
+	return transports
+}
+
+// runKeygen runs frost keygen between ids over transports.
+func runKeygen(t *testing.T, ids party.IDSlice, transports map[party.ID]*p2p.Transport) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	group := curve.Secp256k1{}
+	results := make(map[party.ID]*frost.Config, len(ids))
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, len(ids)-1), []byte("session"))
+		require.NoError(t, err)
+		wg.Add(1)
+		go func(id party.ID) {
+			defer wg.Done()
+			r, err := transport.Run(ctx, h, transports[id])
+			assert.NoError(t, err, id)
+			mtx.Lock()
+			results[id], _ = r.(*frost.Config)
+			mtx.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		require.NotNil(t, results[id], id)
+		assert.True(t, results[ids[0]].PublicKey.Equal(results[id].PublicKey))
+	}
+}
+
+func TestStreams(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	runKeygen(t, ids, newTransports(t, ids, false))
+}
+
+func TestGossipSub(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	runKeygen(t, ids, newTransports(t, ids, true))
+}
+
+func TestSender(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	transports := newTransports(t, ids, true)
+
+	// b's peer can't send messages in the name of c, whether over a stream or on the topic.
+	require.NoError(t, transports["b"].Send(&protocol.Message{SSID: []byte("ssid"), From: "c", To: "a", Protocol: "test"}))
+	require.NoError(t, transports["b"].Broadcast(&protocol.Message{SSID: []byte("ssid"), From: "c", Protocol: "test", Broadcast: true}))
+	require.NoError(t, transports["b"].Send(&protocol.Message{SSID: []byte("ssid"), From: "b", To: "a", Protocol: "test"}))
+
+	select {
+	case msg := <-transports["a"].Receive():
+		assert.Equal(t, party.ID("b"), msg.From)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no message received")
+	}
+	select {
+	case msg := <-transports["a"].Receive():
+		t.Fatalf("received a message from %s", msg.From)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, transports["a"].Close())
+	_, ok := <-transports["a"].Receive()
+	assert.False(t, ok)
+	assert.ErrorIs(t, transports["a"].Send(&protocol.Message{From: "a", To: "b"}), transport.ErrClosed)
+}
@@ -0,0 +1,228 @@
+// Package nats runs the messages of a protocol.Handler over NATS subjects.
+//
+// A message from party i to party j in the session with SSID s is published on the subject
+//
+//	<prefix>.<hex(s)>.<hex(i)>.<hex(j)>
+//
+// and a broadcast from i on <prefix>.<hex(s)>.<hex(i)>.all.
+// Each party subscribes to the messages addressed to it, and to all broadcasts, of every session.
+//
+// NATS doesn't tell subscribers who published a message, so a message is dropped unless its sender
+// matches the subject it was published on. Giving every party a NATS user which may only publish on
+// <prefix>.*.<hex(self)>.> lets the server enforce that parties can't send messages in the name of others.
+// Otherwise, protocol.WithIdentity should be used to authenticate the messages.
+//
+// With core NATS, messages are delivered at most once, and are lost while a party is disconnected.
+// With JetStream, they are stored in a stream, and delivered at least once through durable consumers:
+// the handlers ignore the messages they have already accepted, so redeliveries are harmless.
+//
+// This is a separate module, so that applications which don't use NATS don't depend on it.
+// The result implements transport.Transport, and is run with transport.Run.
+package nats
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	natsio "github.com/nats-io/nats.go"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+)
+
+// DefaultPrefix is the first token of the subjects used when Config.Prefix is empty.
+const DefaultPrefix = "mps"
+
+// broadcastToken replaces the recipient in the subjects of broadcasts. It can't be the hex encoding of a party ID.
+const broadcastToken = "all"
+
+// Config describes how a party uses NATS.
+type Config struct {
+	// Self is the party running the transport.
+	Self party.ID
+	// Conn is the connection to the NATS server. It is not closed by the transport.
+	Conn *natsio.Conn
+	// Prefix is the first token of every subject, which defaults to DefaultPrefix.
+	Prefix string
+	// JetStream, if not nil, is used to publish messages to a stream, which must capture the subjects <Prefix>.>,
+	// and to receive them through durable consumers named after Durable, which are created if they don't exist.
+	JetStream natsio.JetStreamContext
+	// Durable is the prefix of the names of the durable consumers of this party, when using JetStream.
+	// It must be unique to each party, and kept across restarts so that pending messages are delivered.
+	Durable string
+	// Compression is applied to outgoing messages. Incoming messages are accepted whether compressed or not.
+	Compression protocol.Compression
+}
+
+// Transport is a transport.Transport over NATS.
+type Transport struct {
+	config Config
+	inbox  *queue
+
+	mtx    sync.Mutex
+	closed bool
+	subs   []*natsio.Subscription
+}
+
+var _ transport.Transport = (*Transport)(nil)
+
+// New subscribes to the messages for config.Self.
+func New(config Config) (*Transport, error) {
+	if config.Conn == nil {
+		return nil, errors.New("transport: nats: no connection")
+	}
+	if config.Self == "" {
+		return nil, errors.New("transport: nats: empty party ID")
+	}
+	if config.Prefix == "" {
+		config.Prefix = DefaultPrefix
+	}
+	if config.JetStream != nil && config.Durable == "" {
+		return nil, errors.New("transport: nats: no durable name")
+	}
+	t := &Transport{config: config, inbox: newQueue()}
+	filters := map[string]string{
+		"direct":    subject(config.Prefix, "*", "*", encode(config.Self)),
+		"broadcast": subject(config.Prefix, "*", "*", broadcastToken),
+	}
+	for name, filter := range filters {
+		var sub *natsio.Subscription
+		var err error
+		if config.JetStream != nil {
+			sub, err = t.subscribeDurable(filter, config.Durable+"-"+name)
+		} else {
+			sub, err = config.Conn.Subscribe(filter, t.receive)
+		}
+		if err != nil {
+			_ = t.Close()
+			return nil, fmt.Errorf("transport: nats: %w", err)
+		}
+		t.subs = append(t.subs, sub)
+	}
+	return t, nil
+}
+
+// subscribeDurable receives the messages on filter through the durable consumer name, creating it if needed.
+//
+// The consumer is created here, rather than by Subscribe, which would delete it once the subscription is drained.
+func (t *Transport) subscribeDurable(filter, name string) (*natsio.Subscription, error) {
+	js := t.config.JetStream
+	stream, err := js.StreamNameBySubject(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = js.ConsumerInfo(stream, name); errors.Is(err, natsio.ErrConsumerNotFound) {
+		_, err = js.AddConsumer(stream, &natsio.ConsumerConfig{
+			Durable:        name,
+			FilterSubject:  filter,
+			DeliverSubject: natsio.NewInbox(),
+			DeliverPolicy:  natsio.DeliverAllPolicy,
+			AckPolicy:      natsio.AckExplicitPolicy,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return js.Subscribe(filter, t.receive, natsio.Bind(stream, name), natsio.ManualAck())
+}
+
+// Send implements transport.Transport.
+func (t *Transport) Send(msg *protocol.Message) error {
+	return t.publish(msg, encode(msg.To))
+}
+
+// Broadcast implements transport.Transport.
+func (t *Transport) Broadcast(msg *protocol.Message) error {
+	return t.publish(msg, broadcastToken)
+}
+
+// Receive implements transport.Transport.
+func (t *Transport) Receive() <-chan *protocol.Message {
+	return t.inbox.out
+}
+
+// Close implements transport.Transport.
+//
+// The durable consumers are left on the server, so that a new Transport with the same Durable
+// resumes from the messages which weren't acknowledged yet.
+func (t *Transport) Close() error {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return nil
+	}
+	t.closed = true
+	subs := t.subs
+	t.mtx.Unlock()
+
+	var err error
+	for _, sub := range subs {
+		if errSub := sub.Drain(); errSub != nil && err == nil {
+			err = errSub
+		}
+	}
+	t.inbox.close()
+	return err
+}
+
+func (t *Transport) isClosed() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.closed
+}
+
+// publish sends msg on the subject of its session and sender, addressed to the recipient token to.
+func (t *Transport) publish(msg *protocol.Message, to string) error {
+	if t.isClosed() {
+		return transport.ErrClosed
+	}
+	data, err := msg.MarshalBinaryCompressed(t.config.Compression)
+	if err != nil {
+		return fmt.Errorf("transport: nats: %w", err)
+	}
+	s := subject(t.config.Prefix, hex.EncodeToString(msg.SSID), encode(msg.From), to)
+	if t.config.JetStream != nil {
+		_, err = t.config.JetStream.Publish(s, data)
+	} else {
+		err = t.config.Conn.Publish(s, data)
+	}
+	if err != nil {
+		return fmt.Errorf("transport: nats: %w", err)
+	}
+	return nil
+}
+
+// receive delivers m to the handler if it was sent by the party in its subject, for the session in its subject.
+// With JetStream, m is acknowledged once it is queued, or dropped.
+func (t *Transport) receive(m *natsio.Msg) {
+	if t.accept(m) && t.config.JetStream != nil {
+		_ = m.Ack()
+	}
+}
+
+func (t *Transport) accept(m *natsio.Msg) bool {
+	tokens := strings.Split(strings.TrimPrefix(m.Subject, t.config.Prefix+"."), ".")
+	if len(tokens) != 3 {
+		return true
+	}
+	msg := &protocol.Message{}
+	if err := msg.UnmarshalBinary(m.Data); err != nil {
+		return true
+	}
+	if msg.From == t.config.Self || tokens[0] != hex.EncodeToString(msg.SSID) || tokens[1] != encode(msg.From) || !msg.IsFor(t.config.Self) {
+		return true
+	}
+	return t.inbox.push(msg)
+}
+
+func subject(tokens ...string) string {
+	return strings.Join(tokens, ".")
+}
+
+// encode turns id into a subject token, since party IDs can contain characters with a meaning in subjects.
+func encode(id party.ID) string {
+	return hex.EncodeToString([]byte(id))
+}
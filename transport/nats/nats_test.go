@@ -0,0 +1,158 @@
+package nats_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsio "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/multi-party-sig/pkg/math/curve"
+	"github.com/taurusgroup/multi-party-sig/pkg/party"
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+	"github.com/taurusgroup/multi-party-sig/pkg/transport"
+	"github.com/taurusgroup/multi-party-sig/protocols/frost"
+	mpsnats "github.com/taurusgroup/multi-party-sig/transport/nats"
+)
+
+// startServer runs a NATS server with JetStream, and returns its URL.
+func startServer(t *testing.T) string {
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	require.NoError(t, err)
+	go s.Start()
+	t.Cleanup(s.Shutdown)
+	require.True(t, s.ReadyForConnections(10*time.Second))
+	return s.ClientURL()
+}
+
+// newConfig connects id to the server at url, using JetStream if stream is set.
+func newConfig(t *testing.T, url string, id party.ID, stream bool) mpsnats.Config {
+	conn, err := natsio.Connect(url)
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+	config := mpsnats.Config{Self: id, Conn: conn}
+	if stream {
+		config.JetStream, err = conn.JetStream()
+		require.NoError(t, err)
+		config.Durable = "party-" + string(id)
+	}
+	return config
+}
+
+// newTransports returns a transport for each of ids.
+func newTransports(t *testing.T, url string, ids party.IDSlice, stream bool) map[party.ID]*mpsnats.Transport {
+	if stream {
+		conn, err := natsio.Connect(url)
+		require.NoError(t, err)
+		defer conn.Close()
+		js, err := conn.JetStream()
+		require.NoError(t, err)
+		_, err = js.AddStream(&natsio.StreamConfig{Name: "MPS", Subjects: []string{mpsnats.DefaultPrefix + ".>"}})
+		require.NoError(t, err)
+	}
+	transports := make(map[party.ID]*mpsnats.Transport, len(ids))
+	for _, id := range ids {
+		tr, err := mpsnats.New(newConfig(t, url, id, stream))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = tr.Close() })
+		transports[id] = tr
+	}
+	return transports
+}
+
+// runKeygen runs frost keygen between ids over transports.
+func runKeygen(t *testing.T, ids party.IDSlice, transports map[party.ID]*mpsnats.Transport) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	group := curve.Secp256k1{}
+	results := make(map[party.ID]*frost.Config, len(ids))
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		h, err := protocol.NewMultiHandler(frost.Keygen(group, id, ids, len(ids)-1), []byte("session"))
+		require.NoError(t, err)
+		wg.Add(1)
+		go func(id party.ID) {
+			defer wg.Done()
+			r, err := transport.Run(ctx, h, transports[id])
+			assert.NoError(t, err, id)
+			mtx.Lock()
+			results[id], _ = r.(*frost.Config)
+			mtx.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		require.NotNil(t, results[id], id)
+		assert.True(t, results[ids[0]].PublicKey.Equal(results[id].PublicKey))
+	}
+}
+
+func TestCore(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	runKeygen(t, ids, newTransports(t, startServer(t), ids, false))
+}
+
+func TestJetStream(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	runKeygen(t, ids, newTransports(t, startServer(t), ids, true))
+}
+
+func TestSender(t *testing.T) {
+	ids := party.IDSlice{"a", "b", "c"}
+	url := startServer(t)
+	transports := newTransports(t, url, ids, false)
+
+	// a message claiming to come from c, published by b on the subjects of b, is dropped.
+	require.NoError(t, transports["b"].Send(&protocol.Message{SSID: []byte("ssid"), From: "b", To: "a", Protocol: "test"}))
+	msg := &protocol.Message{SSID: []byte("ssid"), From: "c", To: "a", Protocol: "test"}
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	conn, err := natsio.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.Publish("mps.73736964.62.61", data))
+	require.NoError(t, conn.Flush())
+
+	select {
+	case msg := <-transports["a"].Receive():
+		assert.Equal(t, party.ID("b"), msg.From)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no message received")
+	}
+	select {
+	case msg := <-transports["a"].Receive():
+		t.Fatalf("received a message from %s", msg.From)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, transports["a"].Close())
+	_, ok := <-transports["a"].Receive()
+	assert.False(t, ok)
+	assert.ErrorIs(t, transports["a"].Send(&protocol.Message{From: "a", To: "b"}), transport.ErrClosed)
+}
+
+func TestResume(t *testing.T) {
+	url := startServer(t)
+	ids := party.IDSlice{"a", "b"}
+	transports := newTransports(t, url, ids, true)
+
+	// a message sent while a is closed is delivered to the next transport with the same durable name.
+	require.NoError(t, transports["a"].Close())
+	require.NoError(t, transports["b"].Send(&protocol.Message{SSID: []byte("ssid"), From: "b", To: "a", Protocol: "test"}))
+	tr, err := mpsnats.New(newConfig(t, url, "a", true))
+	require.NoError(t, err)
+	defer tr.Close()
+
+	select {
+	case msg := <-tr.Receive():
+		assert.Equal(t, party.ID("b"), msg.From)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no message received")
+	}
+}
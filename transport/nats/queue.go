@@ -0,0 +1,87 @@
+package nats
+
+import (
+	"sync"
+
+	"github.com/taurusgroup/multi-party-sig/pkg/protocol"
+)
+
+// queue is an unbounded buffer in front of a channel, so that senders are never blocked by a slow receiver.
+type queue struct {
+	mtx     sync.Mutex
+	pending []*protocol.Message
+	closed  bool
+	signal  chan struct{}
+	out     chan *protocol.Message
+}
+
+func newQueue() *queue {
+	q := &queue{
+		signal: make(chan struct{}, 1),
+		out:    make(chan *protocol.Message),
+	}
+	go q.run()
+	return q
+}
+
+// push adds msg to the queue, and returns false if the queue is closed.
+func (q *queue) push(msg *protocol.Message) bool {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	if q.closed {
+		return false
+	}
+	q.pending = append(q.pending, msg)
+	q.notify()
+	return true
+}
+
+// close drops the pending messages, and closes the output channel.
+func (q *queue) close() {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.pending = nil
+	q.notify()
+}
+
+func (q *queue) notify() {
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (q *queue) run() {
+	defer close(q.out)
+	for range q.signal {
+		for {
+			q.mtx.Lock()
+			if q.closed {
+				q.mtx.Unlock()
+				return
+			}
+			if len(q.pending) == 0 {
+				q.mtx.Unlock()
+				break
+			}
+			msg := q.pending[0]
+			q.pending = q.pending[1:]
+			q.mtx.Unlock()
+
+			select {
+			case q.out <- msg:
+			case <-q.signal:
+				// either closed, or more messages are pending; put msg back and look again
+				q.mtx.Lock()
+				if !q.closed {
+					q.pending = append([]*protocol.Message{msg}, q.pending...)
+				}
+				q.mtx.Unlock()
+			}
+		}
+	}
+}